@@ -0,0 +1,348 @@
+// Package aa sends transactions from ERC‑4337 smart accounts. An
+// AccountClient wraps a call in a UserOperation of the account (nonce from
+// the EntryPoint, gas limits from the bundler, fees from the chain), lets
+// an optional Paymaster sponsor its gas, signs its hash with the chain's
+// wallet as the account's owner and submits it to a bundler, then waits
+// for it to be included.
+//
+// Accounts are expected to follow SimpleAccount: calls are encoded as
+// execute(address,uint256,bytes) and the owner signs the EIP‑191 digest of
+// the UserOperation hash. The EntryPoint is v0.6.
+//
+// File: internal/blockchain/evm/aa/aa.go
+
+package aa
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// DefaultEntryPoint is the address of the v0.6 EntryPoint, the same on
+// every chain.
+const DefaultEntryPoint = "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"
+
+// DefaultPollInterval is how often Wait asks the bundler for a receipt.
+const DefaultPollInterval = 2 * time.Second
+
+// ErrNoBundler indicates a chain without a bundler configured.
+var ErrNoBundler = errors.New("no bundler configured")
+
+// dummySignature is a well‑formed ECDSA signature used while estimating
+// gas, so that the account's signature check runs its full path.
+var dummySignature = hexutil.MustDecode("0xfffffffffffffffffffffffffffffff0000000000000000000000000000000007aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa1c")
+
+// accountABI holds the functions of the EntryPoint and the smart account
+// the client uses.
+var accountABI = mustParseABI(`[
+	{"type":"function","name":"getNonce","inputs":[{"name":"sender","type":"address"},{"name":"key","type":"uint192"}],
+		"outputs":[{"type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"execute","inputs":[{"name":"dest","type":"address"},{"name":"value","type":"uint256"},
+		{"name":"func","type":"bytes"}],"outputs":[],"stateMutability":"nonpayable"}
+]`)
+
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// EncodeExecute returns the call data of the account's
+// execute(to, value, data), which makes the account call to.
+func EncodeExecute(to common.Address, value *big.Int, data []byte) ([]byte, error) {
+	encoded, err := accountABI.Pack("execute", to, nonNil(value), data)
+	if err != nil {
+		return nil, fmt.Errorf("aa: encode execute: %w", err)
+	}
+	return encoded, nil
+}
+
+// DecodeExecute returns the call an account makes for call data built by
+// EncodeExecute.
+func DecodeExecute(callData []byte) (to common.Address, value *big.Int, data []byte, err error) {
+	method := accountABI.Methods["execute"]
+	if len(callData) < 4 || !bytes.Equal(callData[:4], method.ID) {
+		return common.Address{}, nil, nil, errors.New("aa: call data is not execute(address,uint256,bytes)")
+	}
+	args, err := method.Inputs.Unpack(callData[4:])
+	if err != nil {
+		return common.Address{}, nil, nil, fmt.Errorf("aa: decode execute: %w", err)
+	}
+	return args[0].(common.Address), args[1].(*big.Int), args[2].([]byte), nil
+}
+
+// UserOperation is an ERC‑4337 user operation for the v0.6 EntryPoint.
+type UserOperation struct {
+	Sender               common.Address
+	Nonce                *big.Int
+	InitCode             []byte
+	CallData             []byte
+	CallGasLimit         *big.Int
+	VerificationGasLimit *big.Int
+	PreVerificationGas   *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	PaymasterAndData     []byte
+	Signature            []byte
+}
+
+// Hash returns the hash of op that the account's owner signs, as returned
+// by the EntryPoint's getUserOpHash.
+func (op *UserOperation) Hash(entryPoint common.Address, chainID *big.Int) common.Hash {
+	packed := crypto.Keccak256(
+		common.LeftPadBytes(op.Sender[:], 32),
+		word(op.Nonce),
+		crypto.Keccak256(op.InitCode),
+		crypto.Keccak256(op.CallData),
+		word(op.CallGasLimit),
+		word(op.VerificationGasLimit),
+		word(op.PreVerificationGas),
+		word(op.MaxFeePerGas),
+		word(op.MaxPriorityFeePerGas),
+		crypto.Keccak256(op.PaymasterAndData),
+	)
+	return crypto.Keccak256Hash(packed, common.LeftPadBytes(entryPoint[:], 32), word(chainID))
+}
+
+// word encodes v as a 32‑byte ABI word; nil is zero.
+func word(v *big.Int) []byte {
+	if v == nil {
+		return make([]byte, 32)
+	}
+	return math.U256Bytes(new(big.Int).Set(v))
+}
+
+// Paymaster sponsors the gas of user operations. PaymasterAndData is
+// called with op complete but unsigned, and returns the paymasterAndData
+// field to set. It may raise the gas limits of op to cover the paymaster's
+// own validation.
+type Paymaster interface {
+	PaymasterAndData(ctx context.Context, op *UserOperation, entryPoint common.Address, chainID *big.Int) ([]byte, error)
+}
+
+// Chain is what an AccountClient needs of a chain; *evm.EVMGateway
+// implements it. User operations are signed by Wallet, the owner of the
+// smart account.
+type Chain interface {
+	CallContract(ctx context.Context, call *blockchain.ContractCall) ([]byte, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+	GasInfo(ctx context.Context) (*evm.GasInfo, error)
+	Wallet() blockchain.Wallet
+}
+
+// AccountClient sends user operations of one smart account through a
+// bundler. It is safe for concurrent use.
+type AccountClient struct {
+	chain        Chain
+	account      common.Address
+	entryPoint   common.Address
+	bundler      string
+	httpClient   *http.Client
+	paymaster    Paymaster
+	pollInterval time.Duration
+}
+
+// Option configures an AccountClient.
+type Option func(*AccountClient)
+
+// WithEntryPoint sets the EntryPoint address (default DefaultEntryPoint).
+func WithEntryPoint(address common.Address) Option {
+	return func(c *AccountClient) {
+		c.entryPoint = address
+	}
+}
+
+// WithPaymaster sponsors the gas of user operations with paymaster.
+func WithPaymaster(paymaster Paymaster) Option {
+	return func(c *AccountClient) {
+		c.paymaster = paymaster
+	}
+}
+
+// WithHTTPClient sets the client for bundler requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *AccountClient) {
+		c.httpClient = client
+	}
+}
+
+// WithPollInterval sets how often Wait asks the bundler for a receipt
+// (default DefaultPollInterval).
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *AccountClient) {
+		c.pollInterval = interval
+	}
+}
+
+// NewAccountClient returns a client for the smart account at account on
+// chain, submitting to the bundler at bundlerURL.
+func NewAccountClient(chain Chain, account, bundlerURL string, opts ...Option) (*AccountClient, error) {
+	normalized, err := evm.NormalizeAddress(account)
+	if err != nil {
+		return nil, fmt.Errorf("aa: account: %w", err)
+	}
+	if bundlerURL == "" {
+		return nil, fmt.Errorf("aa: %w", ErrNoBundler)
+	}
+	c := &AccountClient{
+		chain:        chain,
+		account:      common.HexToAddress(normalized),
+		entryPoint:   common.HexToAddress(DefaultEntryPoint),
+		bundler:      bundlerURL,
+		httpClient:   http.DefaultClient,
+		pollInterval: DefaultPollInterval,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Address returns the address of the smart account.
+func (c *AccountClient) Address() string {
+	return c.account.Hex()
+}
+
+// EntryPoint returns the address of the EntryPoint.
+func (c *AccountClient) EntryPoint() common.Address {
+	return c.entryPoint
+}
+
+// Nonce returns the account's next nonce (key 0) from the EntryPoint.
+func (c *AccountClient) Nonce(ctx context.Context) (*big.Int, error) {
+	data, err := accountABI.Pack("getNonce", c.account, new(big.Int))
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.chain.CallContract(ctx, &blockchain.ContractCall{To: c.entryPoint.Hex(), Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("aa: getNonce: %w", err)
+	}
+	values, err := accountABI.Unpack("getNonce", out)
+	if err != nil || len(values) != 1 {
+		return nil, fmt.Errorf("aa: getNonce: %s is not an EntryPoint: unexpected result", c.entryPoint.Hex())
+	}
+	return values[0].(*big.Int), nil
+}
+
+// BuildUserOp builds an unsigned user operation in which the account makes
+// call (its To, Value and Data, as from BoundContract.BuildTransaction),
+// with the account's nonce, the chain's current fees, gas limits estimated
+// by the bundler and, with a paymaster, its paymasterAndData.
+func (c *AccountClient) BuildUserOp(ctx context.Context, call *blockchain.Transaction) (*UserOperation, error) {
+	if call == nil || call.To == nil {
+		return nil, errors.New("aa: call has no target")
+	}
+	to, err := evm.NormalizeAddress(*call.To)
+	if err != nil {
+		return nil, fmt.Errorf("aa: to: %w", err)
+	}
+	callData, err := EncodeExecute(common.HexToAddress(to), call.Value, call.Data)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := c.chain.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("aa: chain ID: %w", err)
+	}
+	nonce, err := c.Nonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.chain.GasInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("aa: fees: %w", err)
+	}
+	op := &UserOperation{
+		Sender:    c.account,
+		Nonce:     nonce,
+		CallData:  callData,
+		Signature: dummySignature,
+	}
+	if info.BaseFee != nil {
+		// Twice the base fee leaves room for it to rise while the bundler
+		// waits for a slot; the surplus is not charged.
+		op.MaxPriorityFeePerGas = info.GasTipCap
+		op.MaxFeePerGas = new(big.Int).Add(new(big.Int).Mul(info.BaseFee, big.NewInt(2)), info.GasTipCap)
+	} else {
+		op.MaxPriorityFeePerGas = info.GasPrice
+		op.MaxFeePerGas = info.GasPrice
+	}
+	if err := c.EstimateGas(ctx, op); err != nil {
+		return nil, err
+	}
+	if c.paymaster != nil {
+		data, err := c.paymaster.PaymasterAndData(ctx, op, c.entryPoint, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("aa: paymaster: %w", err)
+		}
+		op.PaymasterAndData = data
+	}
+	op.Signature = nil
+	return op, nil
+}
+
+// Sign signs the hash of op with the chain's wallet and sets op.Signature
+// to the 65‑byte EIP‑191 signature with V of 27 or 28, as SimpleAccount
+// expects. It returns the hash.
+func (c *AccountClient) Sign(ctx context.Context, op *UserOperation) (common.Hash, error) {
+	wallet := c.chain.Wallet()
+	if wallet == nil {
+		return common.Hash{}, errors.New("aa: no wallet configured, read‑only mode")
+	}
+	chainID, err := c.chain.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("aa: chain ID: %w", err)
+	}
+	hash := op.Hash(c.entryPoint, chainID)
+	sig, err := evm.SignMessage(wallet, hash[:])
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("aa: sign: %w", err)
+	}
+	op.Signature = hexutil.MustDecode(sig)
+	return hash, nil
+}
+
+// Send builds a user operation making call, signs it and submits it to the
+// bundler, then waits for it to be included. A user operation whose call
+// reverted is returned with Success false and no error.
+func (c *AccountClient) Send(ctx context.Context, call *blockchain.Transaction) (*Receipt, error) {
+	op, err := c.BuildUserOp(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.Sign(ctx, op); err != nil {
+		return nil, err
+	}
+	hash, err := c.SendUserOperation(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	return c.Wait(ctx, hash)
+}
+
+func nonNil(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+// EOF: internal/blockchain/evm/aa/aa.go
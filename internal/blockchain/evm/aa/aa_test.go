@@ -0,0 +1,247 @@
+// Package aa_test tests the smart account client against a fake
+// EntryPoint and a fake bundler.
+//
+// File: internal/blockchain/evm/aa/aa_test.go
+
+package aa_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm/aa"
+)
+
+const (
+	accountAddress = "0x1306b01bC3e4AD202612D3843387e94737673F53"
+	target         = "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+)
+
+// fakeChain is an EIP‑1559 chain whose EntryPoint reports nonce 3 for
+// every account. The wallet owns the smart account.
+type fakeChain struct {
+	owner  *ecdsa.PrivateKey
+	wallet blockchain.Wallet
+}
+
+func newFakeChain(t *testing.T) *fakeChain {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet, err := evm.NewMemoryWalletFromHex(hexutil.Encode(crypto.FromECDSA(key)))
+	require.NoError(t, err)
+	return &fakeChain{owner: key, wallet: wallet}
+}
+
+func (f *fakeChain) CallContract(_ context.Context, call *blockchain.ContractCall) ([]byte, error) {
+	if call.To != aa.DefaultEntryPoint {
+		return nil, nil
+	}
+	return common.LeftPadBytes([]byte{3}, 32), nil
+}
+
+func (f *fakeChain) ChainID(context.Context) (*big.Int, error) { return big.NewInt(10), nil }
+
+func (f *fakeChain) GasInfo(context.Context) (*evm.GasInfo, error) {
+	return &evm.GasInfo{BaseFee: big.NewInt(100), GasTipCap: big.NewInt(2), GasPrice: big.NewInt(102)}, nil
+}
+
+func (f *fakeChain) Wallet() blockchain.Wallet { return f.wallet }
+
+// fakeBundler records the user operations it is sent and reports each as
+// included on the second receipt request.
+type fakeBundler struct {
+	mu        sync.Mutex
+	estimated map[string]interface{}
+	sent      map[string]interface{}
+	polls     int
+}
+
+func (b *fakeBundler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var result interface{}
+	switch req.Method {
+	case "eth_estimateUserOperationGas":
+		_ = json.Unmarshal(req.Params[0], &b.estimated)
+		result = map[string]interface{}{"preVerificationGas": "0xb000", "verificationGasLimit": 70000, "callGasLimit": "0x9000"}
+	case "eth_sendUserOperation":
+		_ = json.Unmarshal(req.Params[0], &b.sent)
+		result = common.HexToHash("0xaa").Hex()
+	case "eth_getUserOperationReceipt":
+		if b.polls++; b.polls > 1 {
+			result = map[string]interface{}{
+				"userOpHash":    common.HexToHash("0xaa").Hex(),
+				"success":       true,
+				"actualGasCost": "0x10",
+				"actualGasUsed": "0x08",
+				"receipt":       map[string]interface{}{"transactionHash": common.HexToHash("0xbb").Hex()},
+			}
+		}
+	default:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1,
+			"error": map[string]interface{}{"code": -32601, "message": "method not found"}})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+}
+
+// sponsor is a paymaster that sponsors every user operation.
+type sponsor struct {
+	gasLimit *big.Int
+}
+
+func (s *sponsor) PaymasterAndData(_ context.Context, op *aa.UserOperation, _ common.Address, _ *big.Int) ([]byte, error) {
+	s.gasLimit = op.CallGasLimit
+	op.VerificationGasLimit = new(big.Int).Add(op.VerificationGasLimit, big.NewInt(10000))
+	return hexutil.MustDecode("0x00000000000000000000000000000000000000aa01"), nil
+}
+
+func TestUserOperation_HashMatchesABIEncoding(t *testing.T) {
+	op := &aa.UserOperation{
+		Sender:               common.HexToAddress(accountAddress),
+		Nonce:                big.NewInt(3),
+		InitCode:             []byte{0x01},
+		CallData:             []byte{0xb6, 0x1d, 0x27, 0xf6},
+		CallGasLimit:         big.NewInt(50000),
+		VerificationGasLimit: big.NewInt(70000),
+		PreVerificationGas:   big.NewInt(45000),
+		MaxFeePerGas:         big.NewInt(2e9),
+		MaxPriorityFeePerGas: big.NewInt(1e9),
+		PaymasterAndData:     []byte{0x02},
+	}
+	newType := func(s string) abi.Type {
+		typ, err := abi.NewType(s, "", nil)
+		require.NoError(t, err)
+		return typ
+	}
+	address, uint256, bytes32 := newType("address"), newType("uint256"), newType("bytes32")
+	packed, err := abi.Arguments{{Type: address}, {Type: uint256}, {Type: bytes32}, {Type: bytes32},
+		{Type: uint256}, {Type: uint256}, {Type: uint256}, {Type: uint256}, {Type: uint256}, {Type: bytes32}}.Pack(
+		op.Sender, op.Nonce, crypto.Keccak256Hash(op.InitCode), crypto.Keccak256Hash(op.CallData),
+		op.CallGasLimit, op.VerificationGasLimit, op.PreVerificationGas, op.MaxFeePerGas, op.MaxPriorityFeePerGas,
+		crypto.Keccak256Hash(op.PaymasterAndData))
+	require.NoError(t, err)
+	entryPoint := common.HexToAddress(aa.DefaultEntryPoint)
+	outer, err := abi.Arguments{{Type: bytes32}, {Type: address}, {Type: uint256}}.Pack(
+		crypto.Keccak256Hash(packed), entryPoint, big.NewInt(10))
+	require.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256Hash(outer), op.Hash(entryPoint, big.NewInt(10)))
+}
+
+func TestExecute_RoundTrip(t *testing.T) {
+	data, err := aa.EncodeExecute(common.HexToAddress(target), big.NewInt(7), []byte{0xde, 0xad})
+	require.NoError(t, err)
+	to, value, inner, err := aa.DecodeExecute(data)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(target), to)
+	assert.Equal(t, int64(7), value.Int64())
+	assert.Equal(t, []byte{0xde, 0xad}, inner)
+
+	_, _, _, err = aa.DecodeExecute([]byte{0x01, 0x02, 0x03, 0x04})
+	assert.Error(t, err)
+}
+
+func TestAccountClient_Send(t *testing.T) {
+	chain := newFakeChain(t)
+	bundler := &fakeBundler{}
+	server := httptest.NewServer(bundler)
+	defer server.Close()
+	paymaster := &sponsor{}
+
+	ctx := context.Background()
+	client, err := aa.NewAccountClient(chain, accountAddress, server.URL,
+		aa.WithPaymaster(paymaster), aa.WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+
+	to := target
+	op, err := client.BuildUserOp(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(5), Data: []byte{0x01}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), op.Nonce.Int64(), "nonce from the EntryPoint")
+	assert.Equal(t, int64(0x9000), op.CallGasLimit.Int64())
+	assert.Equal(t, int64(80000), op.VerificationGasLimit.Int64(), "raised by the paymaster")
+	assert.Equal(t, int64(0xb000), op.PreVerificationGas.Int64())
+	assert.Equal(t, int64(202), op.MaxFeePerGas.Int64())
+	assert.Equal(t, int64(2), op.MaxPriorityFeePerGas.Int64())
+	assert.Equal(t, int64(0x9000), paymaster.gasLimit.Int64(), "the paymaster sees the estimated op")
+	assert.Len(t, op.PaymasterAndData, 21)
+	assert.Empty(t, op.Signature)
+	assert.Len(t, hexutil.MustDecode(bundler.estimated["signature"].(string)), 65, "estimated with a dummy signature")
+
+	inner, value, data, err := aa.DecodeExecute(op.CallData)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress(target), inner)
+	assert.Equal(t, int64(5), value.Int64())
+	assert.Equal(t, []byte{0x01}, data)
+
+	hash, err := client.Sign(ctx, op)
+	require.NoError(t, err)
+	assert.Equal(t, op.Hash(common.HexToAddress(aa.DefaultEntryPoint), big.NewInt(10)), hash)
+	ok, err := evm.VerifyMessage(chain.wallet.Address(), hash[:], hexutil.Encode(op.Signature))
+	require.NoError(t, err)
+	assert.True(t, ok, "the owner signs the EIP‑191 digest of the hash")
+
+	receipt, err := client.Send(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(5)})
+	require.NoError(t, err)
+	assert.True(t, receipt.Success)
+	assert.Equal(t, common.HexToHash("0xbb"), receipt.TxHash)
+	assert.Equal(t, int64(0x10), receipt.ActualGasCost.Int64())
+	assert.Equal(t, accountAddress, common.HexToAddress(bundler.sent["sender"].(string)).Hex())
+	assert.Equal(t, "0x3", bundler.sent["nonce"])
+	assert.Len(t, hexutil.MustDecode(bundler.sent["signature"].(string)), 65)
+}
+
+func TestAccountClient_Errors(t *testing.T) {
+	chain := newFakeChain(t)
+	_, err := aa.NewAccountClient(chain, accountAddress, "")
+	assert.ErrorIs(t, err, aa.ErrNoBundler)
+	_, err = aa.NewAccountClient(chain, "not-an-address", "http://bundler")
+	assert.Error(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32500,"message":"AA21 didn't pay prefund"}}`))
+	}))
+	defer server.Close()
+	client, err := aa.NewAccountClient(chain, accountAddress, server.URL)
+	require.NoError(t, err)
+	to := target
+	_, err = client.Send(context.Background(), &blockchain.Transaction{To: &to})
+	var bundlerErr *aa.BundlerError
+	require.ErrorAs(t, err, &bundlerErr)
+	assert.Equal(t, -32500, bundlerErr.Code)
+	assert.Contains(t, err.Error(), "AA21")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	pending := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":null}`))
+	}))
+	defer pending.Close()
+	client, err = aa.NewAccountClient(chain, accountAddress, pending.URL, aa.WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	_, err = client.Wait(ctx, common.HexToHash("0xaa"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// EOF: internal/blockchain/evm/aa/aa_test.go
@@ -0,0 +1,235 @@
+// Package aa provides the JSON‑RPC methods of ERC‑4337 bundlers.
+//
+// File: internal/blockchain/evm/aa/bundler.go
+
+package aa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// rpcUserOp is the JSON form of a UserOperation; all numbers are hex.
+type rpcUserOp struct {
+	Sender               common.Address `json:"sender"`
+	Nonce                *hexutil.Big   `json:"nonce"`
+	InitCode             hexutil.Bytes  `json:"initCode"`
+	CallData             hexutil.Bytes  `json:"callData"`
+	CallGasLimit         *hexutil.Big   `json:"callGasLimit"`
+	VerificationGasLimit *hexutil.Big   `json:"verificationGasLimit"`
+	PreVerificationGas   *hexutil.Big   `json:"preVerificationGas"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	PaymasterAndData     hexutil.Bytes  `json:"paymasterAndData"`
+	Signature            hexutil.Bytes  `json:"signature"`
+}
+
+// MarshalJSON encodes op as bundlers expect it.
+func (op *UserOperation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rpcUserOp{
+		Sender:               op.Sender,
+		Nonce:                (*hexutil.Big)(nonNil(op.Nonce)),
+		InitCode:             nonNilBytes(op.InitCode),
+		CallData:             nonNilBytes(op.CallData),
+		CallGasLimit:         (*hexutil.Big)(nonNil(op.CallGasLimit)),
+		VerificationGasLimit: (*hexutil.Big)(nonNil(op.VerificationGasLimit)),
+		PreVerificationGas:   (*hexutil.Big)(nonNil(op.PreVerificationGas)),
+		MaxFeePerGas:         (*hexutil.Big)(nonNil(op.MaxFeePerGas)),
+		MaxPriorityFeePerGas: (*hexutil.Big)(nonNil(op.MaxPriorityFeePerGas)),
+		PaymasterAndData:     nonNilBytes(op.PaymasterAndData),
+		Signature:            nonNilBytes(op.Signature),
+	})
+}
+
+func nonNilBytes(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
+
+// quantity decodes a number that bundlers send as a hex string or, in
+// some implementations, as a JSON number.
+type quantity big.Int
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (q *quantity) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return fmt.Errorf("invalid quantity %s", data)
+	}
+	*q = quantity(*v)
+	return nil
+}
+
+func (q *quantity) big() *big.Int {
+	if q == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set((*big.Int)(q))
+}
+
+// BundlerError is a JSON‑RPC error returned by the bundler, such as an
+// AA‑prefixed EntryPoint validation failure.
+type BundlerError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error returns the bundler's message and code.
+func (e *BundlerError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// call sends a JSON‑RPC request to the bundler and decodes its result into
+// out; a null result leaves out unchanged.
+func (c *AccountClient) call(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return fmt.Errorf("aa: %s: encode request: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.bundler, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("aa: %s: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("aa: %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("aa: %s: read response: %w", method, err)
+	}
+
+	var reply struct {
+		Result json.RawMessage `json:"result"`
+		Error  *BundlerError   `json:"error"`
+	}
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return fmt.Errorf("aa: %s: http %d: %s", method, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if reply.Error != nil {
+		return fmt.Errorf("aa: %s: %w", method, reply.Error)
+	}
+	if len(reply.Result) == 0 || string(reply.Result) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(reply.Result, out); err != nil {
+		return fmt.Errorf("aa: %s: decode result: %w", method, err)
+	}
+	return nil
+}
+
+// EstimateGas sets the gas limits of op from eth_estimateUserOperationGas.
+// op needs a signature of the right shape, not a valid one.
+func (c *AccountClient) EstimateGas(ctx context.Context, op *UserOperation) error {
+	var estimate struct {
+		PreVerificationGas   *quantity `json:"preVerificationGas"`
+		VerificationGasLimit *quantity `json:"verificationGasLimit"`
+		CallGasLimit         *quantity `json:"callGasLimit"`
+	}
+	if err := c.call(ctx, "eth_estimateUserOperationGas", []interface{}{op, c.entryPoint}, &estimate); err != nil {
+		return err
+	}
+	if estimate.CallGasLimit == nil || estimate.VerificationGasLimit == nil {
+		return fmt.Errorf("aa: eth_estimateUserOperationGas: incomplete estimate")
+	}
+	op.PreVerificationGas = estimate.PreVerificationGas.big()
+	op.VerificationGasLimit = estimate.VerificationGasLimit.big()
+	op.CallGasLimit = estimate.CallGasLimit.big()
+	return nil
+}
+
+// SendUserOperation submits the signed op to the bundler and returns its
+// hash.
+func (c *AccountClient) SendUserOperation(ctx context.Context, op *UserOperation) (common.Hash, error) {
+	var hash common.Hash
+	if err := c.call(ctx, "eth_sendUserOperation", []interface{}{op, c.entryPoint}, &hash); err != nil {
+		return common.Hash{}, err
+	}
+	return hash, nil
+}
+
+// Receipt is the outcome of an included user operation.
+type Receipt struct {
+	UserOpHash    common.Hash
+	Success       bool     // whether the account's call succeeded
+	Reason        string   // revert data of a failed call, if any
+	ActualGasCost *big.Int // wei paid by the account or paymaster
+	ActualGasUsed *big.Int
+	TxHash        common.Hash // bundle transaction including the operation
+}
+
+// UserOperationReceipt returns the receipt of the user operation hash, or
+// nil if it has not been included yet.
+func (c *AccountClient) UserOperationReceipt(ctx context.Context, hash common.Hash) (*Receipt, error) {
+	var raw *struct {
+		UserOpHash    common.Hash `json:"userOpHash"`
+		Success       bool        `json:"success"`
+		Reason        string      `json:"reason"`
+		ActualGasCost *quantity   `json:"actualGasCost"`
+		ActualGasUsed *quantity   `json:"actualGasUsed"`
+		Receipt       struct {
+			TransactionHash common.Hash `json:"transactionHash"`
+		} `json:"receipt"`
+	}
+	if err := c.call(ctx, "eth_getUserOperationReceipt", []interface{}{hash}, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	return &Receipt{
+		UserOpHash:    raw.UserOpHash,
+		Success:       raw.Success,
+		Reason:        raw.Reason,
+		ActualGasCost: raw.ActualGasCost.big(),
+		ActualGasUsed: raw.ActualGasUsed.big(),
+		TxHash:        raw.Receipt.TransactionHash,
+	}, nil
+}
+
+// Wait polls the bundler until the user operation hash is included or ctx
+// is done.
+func (c *AccountClient) Wait(ctx context.Context, hash common.Hash) (*Receipt, error) {
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := c.UserOperationReceipt(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("aa: wait for %s: %w", hash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// EOF: internal/blockchain/evm/aa/bundler.go
@@ -0,0 +1,339 @@
+// Package evm resolves contract ABIs from block explorers and Sourcify.
+//
+// File: internal/blockchain/evm/abiresolver.go
+
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+var (
+	// ErrABINotVerified is returned when no ABI source knows the contract:
+	// its source has not been verified. Verify it, or bind it with an
+	// explicit ABI or artifact instead.
+	ErrABINotVerified = errors.New("contract source not verified")
+	// ErrABIUnavailable is returned when an ABI source could not be
+	// reached or refused the request (network failure, rate limit, server
+	// error). Retrying later may succeed.
+	ErrABIUnavailable = errors.New("ABI source unavailable")
+)
+
+// Defaults for ABI resolution.
+const (
+	DefaultEtherscanURL    = "https://api.etherscan.io/v2/api"
+	DefaultSourcifyURL     = "https://sourcify.dev/server"
+	DefaultABICacheTTL     = 24 * time.Hour
+	defaultABIFetchTimeout = 15 * time.Second
+)
+
+// ABIResolver looks up the ABI of a deployed contract. address is a
+// checksummed hex address; the result is the ABI as a JSON array.
+// Implementations return errors wrapping ErrABINotVerified or
+// ErrABIUnavailable where they apply, and must be safe for concurrent use.
+// Plug in an internal ABI registry by implementing it.
+type ABIResolver interface {
+	ResolveABI(ctx context.Context, chainID uint64, address string) (string, error)
+}
+
+// ExplorerConfig points at an Etherscan‑compatible API for one chain.
+type ExplorerConfig struct {
+	// APIURL of the getabi endpoint (empty = DefaultEtherscanURL, the
+	// multichain Etherscan API, which selects the chain by ID).
+	APIURL string `mapstructure:"api_url"`
+	// APIKey sent as the apikey parameter.
+	APIKey string `mapstructure:"api_key"`
+}
+
+// ABIConfig configures the default ABI resolver.
+type ABIConfig struct {
+	// CacheDir holds fetched ABIs (empty = <user cache dir>/lola/abi).
+	CacheDir string `mapstructure:"cache_dir"`
+	// CacheTTL is how long a cached ABI is used (0 = DefaultABICacheTTL).
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// DisableCache fetches every ABI afresh.
+	DisableCache bool `mapstructure:"disable_cache"`
+	// SourcifyURL of the Sourcify server (empty = DefaultSourcifyURL).
+	SourcifyURL string `mapstructure:"sourcify_url"`
+	// DisableSourcify skips the Sourcify fallback.
+	DisableSourcify bool `mapstructure:"disable_sourcify"`
+}
+
+// NewABIResolver builds the default resolver: the chain's explorer, if
+// configured, then Sourcify, behind an on‑disk cache. explorer may be nil.
+func NewABIResolver(explorer *ExplorerConfig, cfg ABIConfig) (ABIResolver, error) {
+	var sources []ABIResolver
+	if explorer != nil {
+		sources = append(sources, &EtherscanResolver{URL: explorer.APIURL, APIKey: explorer.APIKey})
+	}
+	if !cfg.DisableSourcify {
+		sources = append(sources, &SourcifyResolver{URL: cfg.SourcifyURL})
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("ABI resolver: no sources configured")
+	}
+	var resolver ABIResolver = FallbackResolver(sources)
+	if len(sources) == 1 {
+		resolver = sources[0]
+	}
+	if cfg.DisableCache {
+		return resolver, nil
+	}
+	return NewABICache(resolver, cfg.CacheDir, cfg.CacheTTL)
+}
+
+// EtherscanResolver fetches verified ABIs from an Etherscan‑compatible
+// API (Etherscan, Blockscout, Routescan, …).
+type EtherscanResolver struct {
+	// URL of the API (empty = DefaultEtherscanURL).
+	URL    string
+	APIKey string
+	// HTTPClient is used for requests (nil = a client with a 15s timeout).
+	HTTPClient *http.Client
+}
+
+// String names the source in errors.
+func (r *EtherscanResolver) String() string {
+	return "etherscan"
+}
+
+// ResolveABI implements ABIResolver with the getabi action.
+func (r *EtherscanResolver) ResolveABI(ctx context.Context, chainID uint64, address string) (string, error) {
+	base := r.URL
+	if base == "" {
+		base = DefaultEtherscanURL
+	}
+	query := url.Values{
+		"chainid": {strconv.FormatUint(chainID, 10)},
+		"module":  {"contract"},
+		"action":  {"getabi"},
+		"address": {address},
+	}
+	if r.APIKey != "" {
+		query.Set("apikey", r.APIKey)
+	}
+	body, status, err := fetchABISource(ctx, r.HTTPClient, base+"?"+query.Encode())
+	if err != nil {
+		return "", fmt.Errorf("etherscan: %w", err)
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("etherscan: HTTP %d: %w", status, ErrABIUnavailable)
+	}
+	var resp struct {
+		Status string `json:"status"`
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("etherscan: decode response: %w", err)
+	}
+	if resp.Status == "1" {
+		return resp.Result, nil
+	}
+	result := strings.ToLower(resp.Result)
+	switch {
+	case strings.Contains(result, "not verified"):
+		return "", fmt.Errorf("etherscan: %s: %w", address, ErrABINotVerified)
+	case strings.Contains(result, "rate limit"):
+		return "", fmt.Errorf("etherscan: %s: %w", resp.Result, ErrABIUnavailable)
+	case strings.Contains(result, "api key"):
+		return "", fmt.Errorf("etherscan: %s; check the chain's explorer.api_key", resp.Result)
+	}
+	return "", fmt.Errorf("etherscan: %s", resp.Result)
+}
+
+// SourcifyResolver fetches ABIs of contracts verified on Sourcify.
+type SourcifyResolver struct {
+	// URL of the Sourcify server (empty = DefaultSourcifyURL).
+	URL string
+	// HTTPClient is used for requests (nil = a client with a 15s timeout).
+	HTTPClient *http.Client
+}
+
+// String names the source in errors.
+func (r *SourcifyResolver) String() string {
+	return "sourcify"
+}
+
+// ResolveABI implements ABIResolver with the v2 contract lookup.
+func (r *SourcifyResolver) ResolveABI(ctx context.Context, chainID uint64, address string) (string, error) {
+	base := r.URL
+	if base == "" {
+		base = DefaultSourcifyURL
+	}
+	endpoint := fmt.Sprintf("%s/v2/contract/%d/%s?fields=abi", strings.TrimSuffix(base, "/"), chainID, address)
+	body, status, err := fetchABISource(ctx, r.HTTPClient, endpoint)
+	if err != nil {
+		return "", fmt.Errorf("sourcify: %w", err)
+	}
+	switch {
+	case status == http.StatusNotFound:
+		return "", fmt.Errorf("sourcify: %s: %w", address, ErrABINotVerified)
+	case status != http.StatusOK:
+		return "", fmt.Errorf("sourcify: HTTP %d: %w", status, ErrABIUnavailable)
+	}
+	var resp struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("sourcify: decode response: %w", err)
+	}
+	if len(resp.ABI) == 0 || string(resp.ABI) == "null" {
+		return "", fmt.Errorf("sourcify: %s: %w", address, ErrABINotVerified)
+	}
+	return string(resp.ABI), nil
+}
+
+// fetchABISource GETs endpoint, returning the body and status code.
+// Transport failures wrap ErrABIUnavailable.
+func fetchABISource(ctx context.Context, client *http.Client, endpoint string) ([]byte, int, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultABIFetchTimeout}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, fmt.Errorf("%w: %v", ErrABIUnavailable, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: read response: %v", ErrABIUnavailable, err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// FallbackResolver tries each resolver in order and returns the first
+// ABI found. If every source reports the contract unverified the error
+// wraps ErrABINotVerified; otherwise it is the first other failure, for
+// an unverified answer may just mean the source that failed had it.
+type FallbackResolver []ABIResolver
+
+// ResolveABI implements ABIResolver.
+func (f FallbackResolver) ResolveABI(ctx context.Context, chainID uint64, address string) (string, error) {
+	var failure error
+	for _, r := range f {
+		abiJSON, err := r.ResolveABI(ctx, chainID, address)
+		if err == nil {
+			return abiJSON, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		if failure == nil && !errors.Is(err, ErrABINotVerified) {
+			failure = err
+		}
+	}
+	if failure != nil {
+		return "", failure
+	}
+	return "", fmt.Errorf("%s is not verified on %s: %w", address, f, ErrABINotVerified)
+}
+
+// String lists the sources, such as "etherscan or sourcify".
+func (f FallbackResolver) String() string {
+	names := make([]string, len(f))
+	for i, r := range f {
+		names[i] = sourceName(r)
+	}
+	return strings.Join(names, " or ")
+}
+
+// ABICache caches the ABIs found by another resolver in files under a
+// directory, one per chain and address. Failures are not cached.
+type ABICache struct {
+	resolver ABIResolver
+	dir      string
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewABICache wraps resolver with a cache in dir (empty = <user cache
+// dir>/lola/abi) whose entries expire after ttl (0 = DefaultABICacheTTL).
+func NewABICache(resolver ABIResolver, dir string, ttl time.Duration) (*ABICache, error) {
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("ABI cache: %w", err)
+		}
+		dir = filepath.Join(base, "lola", "abi")
+	}
+	if ttl <= 0 {
+		ttl = DefaultABICacheTTL
+	}
+	return &ABICache{resolver: resolver, dir: dir, ttl: ttl, now: time.Now}, nil
+}
+
+// ResolveABI implements ABIResolver, serving fresh cache entries from disk.
+// A cache that cannot be written is bypassed, not an error.
+func (c *ABICache) ResolveABI(ctx context.Context, chainID uint64, address string) (string, error) {
+	path := filepath.Join(c.dir, strconv.FormatUint(chainID, 10), strings.ToLower(address)+".json")
+	if info, err := os.Stat(path); err == nil && c.now().Sub(info.ModTime()) < c.ttl {
+		if data, err := os.ReadFile(path); err == nil {
+			return string(data), nil
+		}
+	}
+	abiJSON, err := c.resolver.ResolveABI(ctx, chainID, address)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+		tmp := path + ".tmp"
+		if os.WriteFile(tmp, []byte(abiJSON), 0o644) == nil {
+			_ = os.Rename(tmp, path)
+		}
+	}
+	return abiJSON, nil
+}
+
+// String names the cached resolver.
+func (c *ABICache) String() string {
+	return sourceName(c.resolver)
+}
+
+// sourceName names a resolver by its String method, else by its type.
+func sourceName(r ABIResolver) string {
+	if s, ok := r.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", r)
+}
+
+// SetABIResolver sets the resolver used by NewBoundContractByAddress.
+func (g *EVMGateway) SetABIResolver(resolver ABIResolver) {
+	g.abiResolver = resolver
+}
+
+// ABIResolver returns the gateway's ABI resolver, or nil.
+func (g *EVMGateway) ABIResolver() ABIResolver {
+	return g.abiResolver
+}
+
+// NewBoundContractByAddress binds the contract at address with an ABI
+// fetched by the gateway's resolver. For a proxy the implementation's ABI
+// is fetched; calls still go to the proxy (see
+// NewBoundContractResolvingProxy).
+func NewBoundContractByAddress(ctx context.Context, address string, gateway *EVMGateway) (blockchain.Contract, error) {
+	contract, _, err := NewBoundContractResolvingProxy(ctx, address, gateway)
+	return contract, err
+}
+
+// EOF: internal/blockchain/evm/abiresolver.go
@@ -0,0 +1,269 @@
+// Package evm_test tests fetching contract ABIs from explorers.
+//
+// File: internal/blockchain/evm/abiresolver_test.go
+
+package evm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+const (
+	verifiedAddr   = "0x00000000000000000000000000000000000000A1"
+	unverifiedAddr = "0x00000000000000000000000000000000000000b2"
+	counterABI     = `[{"type":"function","name":"value","inputs":[],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"}]`
+)
+
+// newEtherscan serves getabi for verifiedAddr on chain 1 and answers
+// every other address as unverified.
+func newEtherscan(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "getabi", q.Get("action"))
+		switch {
+		case q.Get("apikey") != "key":
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+		case q.Get("address") == "0x00000000000000000000000000000000000000c3":
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Max rate limit reached"}`)
+		case q.Get("address") == verifiedAddr && q.Get("chainid") == "1":
+			fmt.Fprintf(w, `{"status":"1","message":"OK","result":%q}`, counterABI)
+		default:
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newSourcify serves the v2 lookup for verifiedAddr on chain 1.
+func newSourcify(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/contract/1/" + verifiedAddr:
+			assert.Equal(t, "abi", r.URL.Query().Get("fields"))
+			fmt.Fprintf(w, `{"abi":%s,"match":"exact_match"}`, counterABI)
+		case "/v2/contract/1/0x00000000000000000000000000000000000000c3":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"customCode":"not_found"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestEtherscanResolver(t *testing.T) {
+	ctx := context.Background()
+	srv := newEtherscan(t)
+	r := &evm.EtherscanResolver{URL: srv.URL, APIKey: "key"}
+
+	abiJSON, err := r.ResolveABI(ctx, 1, verifiedAddr)
+	require.NoError(t, err)
+	assert.JSONEq(t, counterABI, abiJSON)
+
+	_, err = r.ResolveABI(ctx, 1, unverifiedAddr)
+	assert.ErrorIs(t, err, evm.ErrABINotVerified)
+	_, err = r.ResolveABI(ctx, 1, "0x00000000000000000000000000000000000000c3")
+	assert.ErrorIs(t, err, evm.ErrABIUnavailable)
+	assert.ErrorContains(t, err, "Max rate limit reached")
+
+	_, err = (&evm.EtherscanResolver{URL: srv.URL}).ResolveABI(ctx, 1, verifiedAddr)
+	assert.ErrorContains(t, err, "check the chain's explorer.api_key")
+	assert.False(t, errors.Is(err, evm.ErrABINotVerified) || errors.Is(err, evm.ErrABIUnavailable))
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	_, err = (&evm.EtherscanResolver{URL: down.URL, APIKey: "key"}).ResolveABI(ctx, 1, verifiedAddr)
+	assert.ErrorIs(t, err, evm.ErrABIUnavailable)
+}
+
+func TestSourcifyResolver(t *testing.T) {
+	ctx := context.Background()
+	r := &evm.SourcifyResolver{URL: newSourcify(t).URL + "/"}
+
+	abiJSON, err := r.ResolveABI(ctx, 1, verifiedAddr)
+	require.NoError(t, err)
+	assert.JSONEq(t, counterABI, abiJSON)
+
+	_, err = r.ResolveABI(ctx, 10, verifiedAddr)
+	assert.ErrorIs(t, err, evm.ErrABINotVerified)
+	_, err = r.ResolveABI(ctx, 1, "0x00000000000000000000000000000000000000c3")
+	assert.ErrorIs(t, err, evm.ErrABIUnavailable)
+	assert.ErrorContains(t, err, "HTTP 502")
+}
+
+func TestFallbackResolver(t *testing.T) {
+	ctx := context.Background()
+	etherscan := &evm.EtherscanResolver{URL: newEtherscan(t).URL, APIKey: "key"}
+	sourcify := &evm.SourcifyResolver{URL: newSourcify(t).URL}
+	chain := evm.FallbackResolver{etherscan, sourcify}
+
+	abiJSON, err := chain.ResolveABI(ctx, 1, verifiedAddr)
+	require.NoError(t, err)
+	assert.JSONEq(t, counterABI, abiJSON)
+
+	// Unverified on the explorer, found in the next source.
+	abiJSON, err = evm.FallbackResolver{etherscan, staticResolver{unverifiedAddr: counterABI}}.
+		ResolveABI(ctx, 1, unverifiedAddr)
+	require.NoError(t, err)
+	assert.Equal(t, counterABI, abiJSON)
+
+	_, err = chain.ResolveABI(ctx, 1, unverifiedAddr)
+	assert.ErrorIs(t, err, evm.ErrABINotVerified)
+	assert.ErrorContains(t, err, "is not verified on etherscan or sourcify")
+
+	// An unreachable source is reported rather than "not verified".
+	_, err = chain.ResolveABI(ctx, 1, "0x00000000000000000000000000000000000000c3")
+	assert.ErrorIs(t, err, evm.ErrABIUnavailable)
+	assert.False(t, errors.Is(err, evm.ErrABINotVerified))
+}
+
+// staticResolver serves ABIs from a map, like an internal registry.
+type staticResolver map[string]string
+
+func (s staticResolver) ResolveABI(ctx context.Context, chainID uint64, address string) (string, error) {
+	if abiJSON, ok := s[address]; ok {
+		return abiJSON, nil
+	}
+	return "", fmt.Errorf("registry: %s: %w", address, evm.ErrABINotVerified)
+}
+
+// countingResolver records the addresses it is asked for.
+type countingResolver struct {
+	mu    sync.Mutex
+	asked []string
+	next  abiResult
+}
+
+type abiResult struct {
+	abi string
+	err error
+}
+
+func (c *countingResolver) ResolveABI(ctx context.Context, chainID uint64, address string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.asked = append(c.asked, address)
+	return c.next.abi, c.next.err
+}
+
+func TestABICache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	source := &countingResolver{next: abiResult{abi: counterABI}}
+	cache, err := evm.NewABICache(source, dir, time.Hour)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		abiJSON, err := cache.ResolveABI(ctx, 1, verifiedAddr)
+		require.NoError(t, err)
+		assert.Equal(t, counterABI, abiJSON)
+	}
+	assert.Len(t, source.asked, 1, "second lookup is served from disk")
+	path := filepath.Join(dir, "1", "0x00000000000000000000000000000000000000a1.json")
+	assert.FileExists(t, path)
+
+	// Another chain is a different entry.
+	_, err = cache.ResolveABI(ctx, 137, verifiedAddr)
+	require.NoError(t, err)
+	assert.Len(t, source.asked, 2)
+
+	// Expired entries are fetched again.
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	_, err = cache.ResolveABI(ctx, 1, verifiedAddr)
+	require.NoError(t, err)
+	assert.Len(t, source.asked, 3)
+
+	// Failures are not cached.
+	source.next = abiResult{err: evm.ErrABIUnavailable}
+	for i := 0; i < 2; i++ {
+		_, err = cache.ResolveABI(ctx, 1, unverifiedAddr)
+		assert.ErrorIs(t, err, evm.ErrABIUnavailable)
+	}
+	assert.Len(t, source.asked, 5)
+}
+
+func TestNewABIResolver(t *testing.T) {
+	_, err := evm.NewABIResolver(nil, evm.ABIConfig{DisableSourcify: true})
+	assert.ErrorContains(t, err, "no sources configured")
+
+	r, err := evm.NewABIResolver(nil, evm.ABIConfig{DisableCache: true})
+	require.NoError(t, err)
+	assert.IsType(t, &evm.SourcifyResolver{}, r)
+
+	r, err = evm.NewABIResolver(&evm.ExplorerConfig{APIKey: "key"}, evm.ABIConfig{CacheDir: t.TempDir()})
+	require.NoError(t, err)
+	assert.Equal(t, "etherscan or sourcify", fmt.Sprint(r))
+}
+
+func TestNewBoundContractByAddress(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	_, err = evm.NewBoundContractByAddress(ctx, verifiedAddr, gateway)
+	assert.ErrorContains(t, err, "no ABI resolver configured")
+
+	// A plain contract and an EIP‑1967 proxy pointing at it; both run
+	// Counter's code and return 0.
+	_, plain, err := gateway.DeployContract(ctx, common.FromHex(counterInitCode), nil)
+	require.NoError(t, err)
+	impl := common.HexToAddress("0x00000000000000000000000000000000000000d4")
+	_, proxy, err := gateway.DeployContract(ctx, proxyInitCode(impl), nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	source := &countingResolver{next: abiResult{abi: counterABI}}
+	gateway.SetABIResolver(source)
+
+	for _, addr := range []common.Address{plain, proxy} {
+		contract, err := evm.NewBoundContractByAddress(ctx, addr.Hex(), gateway)
+		require.NoError(t, err)
+		var value *big.Int
+		require.NoError(t, contract.CallInto(ctx, &value, "value"))
+		assert.Zero(t, value.Sign())
+	}
+	assert.Equal(t, []string{plain.Hex(), impl.Hex()}, source.asked,
+		"the proxy is bound with its implementation's ABI")
+
+	source.next = abiResult{err: fmt.Errorf("sourcify: %w", evm.ErrABINotVerified)}
+	_, err = evm.NewBoundContractByAddress(ctx, plain.Hex(), gateway)
+	assert.ErrorIs(t, err, evm.ErrABINotVerified)
+}
+
+// counterInitCode deploys code returning storage slot 0.
+const counterInitCode = "600b600c600039600b6000f3" + "60005460005260206000f3"
+
+// proxyInitCode deploys counter code with impl in the EIP‑1967
+// implementation slot.
+func proxyInitCode(impl common.Address) []byte {
+	return common.FromHex(fmt.Sprintf("73%x7f%s55"+"600b6043600039600b6000f3"+"60005460005260206000f3",
+		impl, "360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"))
+}
+
+// EOF: internal/blockchain/evm/abiresolver_test.go
@@ -0,0 +1,123 @@
+// Package evm provides EIP‑2930 access list generation and conversion.
+//
+// File: internal/blockchain/evm/accesslist.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// accessListResult is the eth_createAccessList response.
+type accessListResult struct {
+	AccessList *types.AccessList `json:"accessList"`
+	GasUsed    hexutil.Uint64    `json:"gasUsed"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// CreateAccessList asks the node which accounts and storage slots call would
+// touch (eth_createAccessList) against the pending block. It returns the list
+// and the gas the call uses with that list applied. A call that reverts
+// returns an error matching ErrReverted.
+func (c *Client) CreateAccessList(ctx context.Context, call ethereum.CallMsg) (types.AccessList, uint64, error) {
+	result, err := c.withRetry(ctx, "CreateAccessList", func(ctx context.Context) (interface{}, error) {
+		var res accessListResult
+		if err := c.eth().Client().CallContext(ctx, &res, "eth_createAccessList", callArg(call), "pending"); err != nil {
+			return nil, err
+		}
+		return &res, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	res := result.(*accessListResult)
+	if res.Error != "" {
+		return nil, 0, fmt.Errorf("CreateAccessList: %w", ClassifyError(errors.New(res.Error)))
+	}
+	var list types.AccessList
+	if res.AccessList != nil {
+		list = *res.AccessList
+	}
+	return list, uint64(res.GasUsed), nil
+}
+
+// CreateAccessList returns the access list and gas estimate for a transaction
+// sent from tx.From or the gateway's wallet (or the zero address in
+// read‑only mode).
+func (g *EVMGateway) CreateAccessList(ctx context.Context, tx *blockchain.Transaction) ([]blockchain.AccessTuple, uint64, error) {
+	msg := ethereum.CallMsg{
+		Value:     tx.Value,
+		Data:      tx.Data,
+		Gas:       tx.Gas,
+		GasPrice:  tx.GasPrice,
+		GasFeeCap: tx.GasFeeCap,
+		GasTipCap: tx.GasTipCap,
+	}
+	if tx.To != nil {
+		to, err := parseAddress(*tx.To)
+		if err != nil {
+			return nil, 0, fmt.Errorf("CreateAccessList: to address: %w", err)
+		}
+		msg.To = &to
+	}
+	msg.From = g.senderAddress(tx.From)
+
+	list, gas, err := g.client.CreateAccessList(ctx, msg)
+	if err != nil {
+		return nil, 0, fmt.Errorf("CreateAccessList: %w", err)
+	}
+	return fromAccessList(list), gas, nil
+}
+
+// toAccessList converts a chain‑agnostic access list, validating its entries.
+func toAccessList(tuples []blockchain.AccessTuple) (types.AccessList, error) {
+	if len(tuples) == 0 {
+		return nil, nil
+	}
+	list := make(types.AccessList, 0, len(tuples))
+	for _, t := range tuples {
+		if _, err := parseAddress(t.Address); err != nil {
+			return nil, fmt.Errorf("invalid access list address: %w", err)
+		}
+		keys := make([]common.Hash, 0, len(t.StorageKeys))
+		for _, k := range t.StorageKeys {
+			b, err := hexutil.Decode(k)
+			if err != nil || len(b) > common.HashLength {
+				return nil, fmt.Errorf("invalid access list storage key: %s", k)
+			}
+			keys = append(keys, common.BytesToHash(b))
+		}
+		list = append(list, types.AccessTuple{
+			Address:     common.HexToAddress(t.Address),
+			StorageKeys: keys,
+		})
+	}
+	return list, nil
+}
+
+// fromAccessList converts an access list into its chain‑agnostic form.
+func fromAccessList(list types.AccessList) []blockchain.AccessTuple {
+	tuples := make([]blockchain.AccessTuple, 0, len(list))
+	for _, t := range list {
+		keys := make([]string, 0, len(t.StorageKeys))
+		for _, k := range t.StorageKeys {
+			keys = append(keys, k.Hex())
+		}
+		tuples = append(tuples, blockchain.AccessTuple{
+			Address:     t.Address.Hex(),
+			StorageKeys: keys,
+		})
+	}
+	return tuples
+}
+
+// EOF: internal/blockchain/evm/accesslist.go
@@ -0,0 +1,85 @@
+// Package evm_test tests EIP‑2930 access list transactions.
+//
+// File: internal/blockchain/evm/accesslist_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+func TestEVMGateway_AccessListTransactions(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	self := common.HexToAddress(wallet.Address())
+	sim := simulated.NewBackend(types.GenesisAlloc{self: {Balance: big.NewInt(1e18)}})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	_, addr, err := gateway.DeployContract(ctx, storageInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	to := addr.Hex()
+	setData := append(common.FromHex("60fe47b1"), common.LeftPadBytes([]byte{5}, 32)...)
+	list, gas, err := gateway.CreateAccessList(ctx, &blockchain.Transaction{To: &to, Data: setData})
+	require.NoError(t, err)
+	assert.NotZero(t, gas)
+	require.Len(t, list, 1)
+	assert.Equal(t, addr.Hex(), list[0].Address)
+	assert.Equal(t, []string{common.Hash{}.Hex()}, list[0].StorageKeys)
+
+	tests := []struct {
+		name   string
+		tx     blockchain.Transaction
+		txType uint8
+	}{
+		{"legacy pricing", blockchain.Transaction{GasPrice: big.NewInt(2e9)}, types.AccessListTxType},
+		{"dynamic fee", blockchain.Transaction{GasFeeCap: big.NewInt(1e10), GasTipCap: big.NewInt(1e9)}, types.DynamicFeeTxType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := tt.tx
+			tx.To = &to
+			tx.Data = setData
+			tx.AccessList = list
+			hash, err := gateway.SendTransaction(ctx, &tx)
+			require.NoError(t, err)
+			sim.Commit()
+
+			sent, _, err := sim.Client().TransactionByHash(ctx, common.HexToHash(hash))
+			require.NoError(t, err)
+			assert.Equal(t, tt.txType, sent.Type())
+			assert.Equal(t, types.AccessList{{Address: addr, StorageKeys: []common.Hash{{}}}}, sent.AccessList())
+
+			sender, err := types.Sender(types.LatestSignerForChainID(sent.ChainId()), sent)
+			require.NoError(t, err)
+			assert.Equal(t, self, sender)
+
+			receipt, err := sim.Client().TransactionReceipt(ctx, common.HexToHash(hash))
+			require.NoError(t, err)
+			assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+		})
+	}
+
+	_, err = gateway.SendTransaction(ctx, &blockchain.Transaction{
+		To:         &to,
+		AccessList: []blockchain.AccessTuple{{Address: "not-an-address"}},
+	})
+	assert.ErrorContains(t, err, "invalid access list address")
+}
+
+// EOF: internal/blockchain/evm/accesslist_test.go
@@ -0,0 +1,51 @@
+// Package evm validates and normalises hex addresses.
+//
+// File: internal/blockchain/evm/address.go
+
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Address validation errors. Use errors.Is to test for them.
+var (
+	// ErrInvalidAddress indicates a string that is not a 20‑byte hex address.
+	ErrInvalidAddress = errors.New("invalid address format")
+	// ErrInvalidChecksum indicates a mixed‑case address whose EIP‑55
+	// checksum does not match, which usually means it was mistyped.
+	ErrInvalidChecksum = errors.New("invalid address checksum")
+)
+
+// NormalizeAddress validates a hex address and returns its EIP‑55
+// checksummed form. All‑lowercase and all‑uppercase addresses carry no
+// checksum and are accepted; a mixed‑case address must match its checksum.
+func NormalizeAddress(address string) (string, error) {
+	addr, err := parseAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return addr.Hex(), nil
+}
+
+// parseAddress is NormalizeAddress returning the parsed address.
+func parseAddress(address string) (common.Address, error) {
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrInvalidAddress, address)
+	}
+	addr := common.HexToAddress(address)
+	digits := address
+	if len(digits) == 2*common.AddressLength+2 {
+		digits = digits[2:]
+	}
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && digits != addr.Hex()[2:] {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrInvalidChecksum, address)
+	}
+	return addr, nil
+}
+
+// EOF: internal/blockchain/evm/address.go
@@ -0,0 +1,58 @@
+// Package evm_test tests address normalisation.
+//
+// File: internal/blockchain/evm/address_test.go
+
+package evm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	const checksummed = "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		err   error
+	}{
+		{"checksummed", checksummed, checksummed, nil},
+		{"lowercase", "0xd8da6bf26964af9d7eed9e03e53415d37aa96045", checksummed, nil},
+		{"uppercase", "0xD8DA6BF26964AF9D7EED9E03E53415D37AA96045", checksummed, nil},
+		{"no prefix", "d8da6bf26964af9d7eed9e03e53415d37aa96045", checksummed, nil},
+		{"bad checksum", "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96046", "", evm.ErrInvalidChecksum},
+		{"one case flipped", "0xD8dA6BF26964aF9D7eEd9e03E53415D37aA96045", "", evm.ErrInvalidChecksum},
+		{"too short", "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA960", "", evm.ErrInvalidAddress},
+		{"not hex", "0xz8dA6BF26964aF9D7eEd9e03E53415D37aA96045", "", evm.ErrInvalidAddress},
+		{"name", "vitalik.eth", "", evm.ErrInvalidAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evm.NormalizeAddress(tt.input)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEVMGateway_RejectsBadChecksum(t *testing.T) {
+	gateway, _ := newENSGateway(t, "0x1", &ensNode{}, nil)
+	_, err := gateway.GetBalance(context.Background(), "0xD8dA6BF26964aF9D7eEd9e03E53415D37aA96045", blockchain.BlockNumberLatest)
+	assert.ErrorIs(t, err, evm.ErrInvalidChecksum)
+
+	_, err = gateway.CallContract(context.Background(), &blockchain.ContractCall{To: "0xD8dA6BF26964aF9D7eEd9e03E53415D37aA96045"})
+	assert.ErrorIs(t, err, evm.ErrInvalidChecksum)
+}
+
+// EOF: internal/blockchain/evm/address_test.go
@@ -0,0 +1,167 @@
+// Package evm loads contract bindings from Foundry and Hardhat artifacts.
+//
+// File: internal/blockchain/evm/artifact.go
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// Artifact is a compiled contract read from a build artifact file.
+//
+// Two shapes are understood: Foundry's out/<File>.sol/<Name>.json, where
+// bytecode and deployedBytecode are objects holding the hex in "object",
+// and Hardhat's artifacts/…/<Name>.json, where they are hex strings.
+type Artifact struct {
+	// ContractName is Hardhat's contractName; empty for Foundry.
+	ContractName string
+	// ABIJSON is the raw "abi" array and ABI its parsed form.
+	ABIJSON string
+	ABI     abi.ABI
+	// Bytecode is the creation code, empty for interfaces and abstract
+	// contracts.
+	Bytecode []byte
+	// DeployedBytecode is the runtime code, if present.
+	DeployedBytecode []byte
+}
+
+// artifactFile covers the fields of both artifact shapes.
+type artifactFile struct {
+	ContractName     string          `json:"contractName"`
+	ABI              json.RawMessage `json:"abi"`
+	Bytecode         json.RawMessage `json:"bytecode"`
+	DeployedBytecode json.RawMessage `json:"deployedBytecode"`
+}
+
+// LoadArtifact reads and parses the artifact file at path.
+func LoadArtifact(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load artifact: %w", err)
+	}
+	artifact, err := ParseArtifact(data)
+	if err != nil {
+		return nil, fmt.Errorf("artifact %s: %w", path, err)
+	}
+	return artifact, nil
+}
+
+// ParseArtifact parses a Foundry or Hardhat artifact. The "abi" and
+// "bytecode" fields are required; a missing or malformed field is named in
+// the error.
+func ParseArtifact(data []byte) (*Artifact, error) {
+	var file artifactFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse artifact: %w", err)
+	}
+	if len(file.ABI) == 0 || string(file.ABI) == "null" {
+		return nil, fmt.Errorf(`missing field "abi"`)
+	}
+	parsedABI, err := abi.JSON(bytes.NewReader(file.ABI))
+	if err != nil {
+		return nil, fmt.Errorf(`field "abi": %w`, err)
+	}
+	code, err := artifactBytecode("bytecode", file.Bytecode, true)
+	if err != nil {
+		return nil, err
+	}
+	deployed, err := artifactBytecode("deployedBytecode", file.DeployedBytecode, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Artifact{
+		ContractName:     file.ContractName,
+		ABIJSON:          string(file.ABI),
+		ABI:              parsedABI,
+		Bytecode:         code,
+		DeployedBytecode: deployed,
+	}, nil
+}
+
+// artifactBytecode decodes a bytecode field: a hex string (Hardhat) or an
+// object whose "object" holds the hex (Foundry).
+func artifactBytecode(field string, raw json.RawMessage, required bool) ([]byte, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		if required {
+			return nil, fmt.Errorf("missing field %q", field)
+		}
+		return nil, nil
+	}
+	var code string
+	if raw[0] == '{' {
+		var obj struct {
+			Object *string `json:"object"`
+		}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, fmt.Errorf("field %q: %w", field, err)
+		}
+		if obj.Object == nil {
+			return nil, fmt.Errorf("missing field %q", field+".object")
+		}
+		field, code = field+".object", *obj.Object
+	} else if err := json.Unmarshal(raw, &code); err != nil {
+		return nil, fmt.Errorf("field %q: want a hex string or an object: %w", field, err)
+	}
+	if strings.Contains(code, "__") {
+		return nil, fmt.Errorf("field %q: unlinked library references; link the libraries before loading", field)
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(code, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("field %q: %w", field, err)
+	}
+	return decoded, nil
+}
+
+// NewBoundContractFromArtifact binds the contract at address using the ABI
+// of the artifact file at path.
+func NewBoundContractFromArtifact(path, address string, gateway *EVMGateway) (blockchain.Contract, error) {
+	artifact, err := LoadArtifact(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewBoundContract(address, artifact.ABIJSON, gateway)
+}
+
+// CreationData returns the data of a transaction deploying the artifact's
+// contract: its creation bytecode followed by the ABI‑encoded constructor
+// args.
+func (a *Artifact) CreationData(args ...interface{}) ([]byte, error) {
+	if len(a.Bytecode) == 0 {
+		return nil, errors.New("artifact has no creation bytecode; is it an interface or abstract contract?")
+	}
+	encoded, err := a.ABI.Pack("", args...)
+	if err != nil {
+		return nil, fmt.Errorf("pack constructor arguments: %w", err)
+	}
+	return append(append([]byte(nil), a.Bytecode...), encoded...), nil
+}
+
+// DeployFromArtifact deploys the contract of the artifact file at path,
+// appending the ABI‑encoded constructor args to its creation bytecode.
+// Returns the transaction hash and the new contract's address.
+func (g *EVMGateway) DeployFromArtifact(ctx context.Context, path string, opts *TxOpts, args ...interface{}) (string, common.Address, error) {
+	artifact, err := LoadArtifact(path)
+	if err != nil {
+		return "", common.Address{}, fmt.Errorf("DeployFromArtifact: %w", err)
+	}
+	data, err := artifact.CreationData(args...)
+	if err != nil {
+		return "", common.Address{}, fmt.Errorf("DeployFromArtifact: %s: %w", path, err)
+	}
+	return g.DeployContract(ctx, data, opts)
+}
+
+// EOF: internal/blockchain/evm/artifact.go
@@ -0,0 +1,101 @@
+// Package evm_test tests loading Foundry and Hardhat artifacts.
+//
+// File: internal/blockchain/evm/artifact_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+var (
+	foundryCounter = filepath.Join("testdata", "artifacts", "foundry", "Counter.sol", "Counter.json")
+	hardhatCounter = filepath.Join("testdata", "artifacts", "hardhat", "Counter.json")
+)
+
+func TestLoadArtifact(t *testing.T) {
+	for _, path := range []string{foundryCounter, hardhatCounter} {
+		artifact, err := evm.LoadArtifact(path)
+		require.NoError(t, err, path)
+		assert.Contains(t, artifact.ABI.Methods, "value", path)
+		assert.Len(t, artifact.ABI.Constructor.Inputs, 1, path)
+		assert.Equal(t, common.FromHex("0x60005460005260206000f3"), artifact.DeployedBytecode, path)
+		assert.Len(t, artifact.Bytecode, 38, path)
+	}
+	hardhat, err := evm.LoadArtifact(hardhatCounter)
+	require.NoError(t, err)
+	assert.Equal(t, "Counter", hardhat.ContractName)
+
+	_, err = evm.LoadArtifact(filepath.Join("testdata", "artifacts", "missing.json"))
+	assert.ErrorContains(t, err, "load artifact")
+}
+
+func TestParseArtifact_Malformed(t *testing.T) {
+	tests := []struct {
+		name, json, err string
+	}{
+		{"no abi", `{"bytecode":"0x00"}`, `missing field "abi"`},
+		{"bad abi", `{"abi":{"type":"function"},"bytecode":"0x00"}`, `field "abi"`},
+		{"no bytecode", `{"abi":[]}`, `missing field "bytecode"`},
+		{"foundry without object", `{"abi":[],"bytecode":{"sourceMap":""}}`, `missing field "bytecode.object"`},
+		{"bad hex", `{"abi":[],"bytecode":"0xzz"}`, `field "bytecode": encoding/hex`},
+		{"bad deployed hex", `{"abi":[],"bytecode":{"object":"0x"},"deployedBytecode":{"object":"0x1"}}`, `field "deployedBytecode.object"`},
+		{"unlinked", `{"abi":[],"bytecode":"0x73__$1234$__"}`, "unlinked library references"},
+		{"not json", `abi`, "parse artifact"},
+	}
+	for _, tt := range tests {
+		_, err := evm.ParseArtifact([]byte(tt.json))
+		assert.ErrorContains(t, err, tt.err, tt.name)
+	}
+
+	// Interfaces have empty bytecode, which still binds.
+	artifact, err := evm.ParseArtifact([]byte(`{"abi":[],"bytecode":{"object":"0x"}}`))
+	require.NoError(t, err)
+	assert.Empty(t, artifact.Bytecode)
+}
+
+func TestEVMGateway_DeployFromArtifact(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	for i, path := range []string{foundryCounter, hardhatCounter} {
+		initial := big.NewInt(int64(41 + i))
+		_, addr, err := gateway.DeployFromArtifact(ctx, path, nil, initial)
+		require.NoError(t, err, path)
+		sim.Commit()
+
+		contract, err := evm.NewBoundContractFromArtifact(path, addr.Hex(), gateway)
+		require.NoError(t, err, path)
+		var value *big.Int
+		require.NoError(t, contract.CallInto(ctx, &value, "value"), path)
+		assert.Equal(t, initial, value, path)
+	}
+
+	_, _, err = gateway.DeployFromArtifact(ctx, foundryCounter, nil)
+	assert.ErrorContains(t, err, "pack constructor arguments")
+
+	iface := filepath.Join(t.TempDir(), "ICounter.json")
+	require.NoError(t, os.WriteFile(iface, []byte(`{"abi":[],"bytecode":{"object":"0x"}}`), 0o600))
+	_, _, err = gateway.DeployFromArtifact(ctx, iface, nil)
+	assert.ErrorContains(t, err, "has no creation bytecode")
+}
+
+// EOF: internal/blockchain/evm/artifact_test.go
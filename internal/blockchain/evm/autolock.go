@@ -0,0 +1,182 @@
+// Package evm provides locking of keystores: explicit Lock and Unlock, and
+// an optional auto‑lock after an idle period.
+//
+// File: internal/blockchain/evm/autolock.go
+
+package evm
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// keyGuard guards the decrypted keys of a keystore and, for a directory,
+// of all its accounts. Signing holds mu for reading; wiping and restoring
+// the keys hold it for writing. Where both are taken, the owner's
+// Keystore.mu is taken before mu, never after.
+type keyGuard struct {
+	owner *Keystore
+
+	mu       sync.RWMutex
+	locked   bool
+	idle     time.Duration // 0 = never auto‑lock
+	timer    *time.Timer
+	lastUsed atomic.Int64 // unix nanoseconds
+}
+
+// newKeyGuard returns the guard of owner, auto‑locking after idle.
+func newKeyGuard(owner *Keystore, idle time.Duration) *keyGuard {
+	g := &keyGuard{owner: owner}
+	g.startAutoLock(idle)
+	return g
+}
+
+// startAutoLock (re)starts the idle timer; idle 0 disables it.
+func (g *keyGuard) startAutoLock(idle time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.idle = idle
+	g.lastUsed.Store(time.Now().UnixNano())
+	if g.timer != nil {
+		g.timer.Stop()
+		g.timer = nil
+	}
+	if idle > 0 && !g.locked {
+		g.timer = time.AfterFunc(idle, g.expire)
+	}
+}
+
+// expire locks the keys if they have been idle long enough; otherwise it
+// waits for the rest of the idle period.
+func (g *keyGuard) expire() {
+	g.owner.mu.Lock()
+	defer g.owner.mu.Unlock()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.locked || g.idle <= 0 || g.timer == nil {
+		return
+	}
+	if rest := g.idle - time.Since(time.Unix(0, g.lastUsed.Load())); rest > 0 {
+		g.timer.Reset(rest)
+		return
+	}
+	g.wipe()
+}
+
+// wipe zeroes and drops every key and the directory passphrase. The
+// caller holds owner.mu and mu for writing.
+func (g *keyGuard) wipe() {
+	for _, k := range append([]*Keystore{g.owner}, g.owner.accounts...) {
+		if k.privateKey != nil {
+			clear(k.privateKey.D.Bits())
+			k.privateKey.D.SetInt64(0)
+			k.privateKey = nil
+		}
+	}
+	g.owner.passphrase = ""
+	g.locked = true
+	if g.timer != nil {
+		g.timer.Stop()
+		g.timer = nil
+	}
+}
+
+// useKey returns the key of k for one use, marking the keystore as used.
+// Until release is called the key cannot be wiped. A locked keystore fails
+// with blockchain.ErrWalletLocked.
+func (k *Keystore) useKey() (*ecdsa.PrivateKey, func(), error) {
+	g := k.guard
+	if g == nil {
+		return k.privateKey, func() {}, nil
+	}
+	g.mu.RLock()
+	if g.locked {
+		g.mu.RUnlock()
+		return nil, nil, fmt.Errorf("keystore: %s: %w", k.address.Hex(), blockchain.ErrWalletLocked)
+	}
+	g.lastUsed.Store(time.Now().UnixNano())
+	return k.privateKey, g.mu.RUnlock, nil
+}
+
+// Lock implements blockchain.LockableWallet. It wipes the keys of the
+// keystore, and of every account of a directory, from memory.
+func (k *Keystore) Lock() {
+	g := k.guard
+	if g == nil {
+		return
+	}
+	g.owner.mu.Lock()
+	defer g.owner.mu.Unlock()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.locked {
+		g.wipe()
+	}
+}
+
+// Unlock implements blockchain.LockableWallet. It decrypts the key files
+// again with passphrase, which must open all of them, and restarts the
+// auto‑lock timer. Unlocking an unlocked keystore only checks passphrase.
+func (k *Keystore) Unlock(passphrase string) error {
+	g := k.guard
+	if g == nil {
+		return nil
+	}
+	owner := g.owner
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+
+	accounts := owner.accounts
+	if owner.dir == "" {
+		accounts = []*Keystore{owner}
+	}
+	keys := make([]*ecdsa.PrivateKey, len(accounts))
+	for i, account := range accounts {
+		loaded, err := loadKeystore(account.keyFile, passphrase)
+		if err != nil {
+			return fmt.Errorf("keystore: unlock: %w", err)
+		}
+		if loaded.address != account.address {
+			return fmt.Errorf("keystore: unlock: %s now holds account %s, not %s",
+				account.keyFile, loaded.address.Hex(), account.address.Hex())
+		}
+		keys[i] = loaded.privateKey
+	}
+
+	g.mu.Lock()
+	if g.locked {
+		for i, account := range accounts {
+			account.privateKey = keys[i]
+		}
+		if owner.dir != "" {
+			owner.privateKey = owner.accounts[0].privateKey
+			owner.passphrase = passphrase
+		}
+		g.locked = false
+	} else {
+		for _, key := range keys {
+			clear(key.D.Bits())
+		}
+	}
+	idle := g.idle
+	g.mu.Unlock()
+	g.startAutoLock(idle)
+	return nil
+}
+
+// Locked implements blockchain.LockableWallet.
+func (k *Keystore) Locked() bool {
+	if k.guard == nil {
+		return false
+	}
+	k.guard.mu.RLock()
+	defer k.guard.mu.RUnlock()
+	return k.guard.locked
+}
+
+// EOF: internal/blockchain/evm/autolock.go
@@ -0,0 +1,149 @@
+// Package evm_test tests locking and unlocking keystores.
+//
+// File: internal/blockchain/evm/autolock_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+func TestKeystore_AutoLock(t *testing.T) {
+	ks, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test", evm.WithAutoLock(100*time.Millisecond))
+	require.NoError(t, err)
+	var _ blockchain.LockableWallet = ks
+
+	digest := crypto.Keccak256([]byte("hello"))
+	want, err := ks.Sign(digest)
+	require.NoError(t, err)
+
+	assert.Eventually(t, ks.Locked, 2*time.Second, 10*time.Millisecond)
+	_, err = ks.Sign(digest)
+	assert.ErrorIs(t, err, blockchain.ErrWalletLocked)
+	to := common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
+	_, err = ks.SignTx(context.Background(), types.NewTx(&types.LegacyTx{To: &to, Gas: 21000, GasPrice: big.NewInt(1)}), big.NewInt(1))
+	assert.ErrorIs(t, err, blockchain.ErrWalletLocked)
+	assert.ErrorIs(t, ks.ExportTo(filepath.Join(t.TempDir(), "backup.key"), "backup"), blockchain.ErrWalletLocked)
+
+	assert.Error(t, ks.Unlock("wrong"))
+	assert.True(t, ks.Locked())
+
+	require.NoError(t, ks.Unlock("test"))
+	assert.False(t, ks.Locked())
+	sig, err := ks.Sign(digest)
+	require.NoError(t, err)
+	assert.Equal(t, want, sig)
+
+	// The timer restarts after Unlock.
+	assert.Eventually(t, ks.Locked, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestKeystore_AutoLockIdleOnly(t *testing.T) {
+	ks, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test", evm.WithAutoLock(200*time.Millisecond))
+	require.NoError(t, err)
+
+	digest := crypto.Keccak256([]byte("hello"))
+	for deadline := time.Now().Add(500 * time.Millisecond); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		_, err := ks.Sign(digest)
+		require.NoError(t, err, "a keystore in use does not lock")
+	}
+	assert.Eventually(t, ks.Locked, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestKeystore_WithoutAutoLock(t *testing.T) {
+	ks, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+
+	ks.Lock()
+	ks.Lock()
+	assert.True(t, ks.Locked())
+	require.NoError(t, ks.Unlock("test"))
+	require.NoError(t, ks.Unlock("test"), "unlocking an unlocked keystore checks the passphrase")
+	assert.Error(t, ks.Unlock("wrong"))
+	assert.False(t, ks.Locked())
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, ks.Locked())
+}
+
+func TestKeystoreDir_LockUnlock(t *testing.T) {
+	ks, err := evm.NewKeystoreDir(t.TempDir(), "test")
+	require.NoError(t, err)
+	second, err := ks.NewAccount()
+	require.NoError(t, err)
+	digest := crypto.Keccak256([]byte("hello"))
+
+	ks.Lock()
+	assert.True(t, second.Locked(), "accounts lock with their directory")
+	_, err = second.Sign(digest)
+	assert.ErrorIs(t, err, blockchain.ErrWalletLocked)
+	_, err = ks.NewAccount()
+	assert.ErrorIs(t, err, blockchain.ErrWalletLocked)
+
+	// Unlocking any account unlocks the directory.
+	require.NoError(t, second.Unlock("test"))
+	assert.False(t, ks.Locked())
+	for _, address := range ks.Accounts() {
+		wallet, err := ks.WalletFor(address)
+		require.NoError(t, err)
+		sig, err := wallet.Sign(digest)
+		require.NoError(t, err)
+		pub, err := crypto.SigToPub(digest, sig)
+		require.NoError(t, err)
+		assert.Equal(t, address, crypto.PubkeyToAddress(*pub).Hex())
+	}
+	_, err = ks.NewAccount()
+	assert.NoError(t, err, "the passphrase is restored for new accounts")
+}
+
+func TestKeystore_ConcurrentLockUnlock(t *testing.T) {
+	ks, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test", evm.WithAutoLock(5*time.Millisecond))
+	require.NoError(t, err)
+	digest := crypto.Keccak256([]byte("hello"))
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				sig, err := ks.Sign(digest)
+				if err != nil {
+					assert.ErrorIs(t, err, blockchain.ErrWalletLocked)
+					continue
+				}
+				pub, err := crypto.SigToPub(digest, sig)
+				if assert.NoError(t, err) {
+					assert.Equal(t, ks.Address(), crypto.PubkeyToAddress(*pub).Hex())
+				}
+			}
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		ks.Lock()
+		assert.NoError(t, ks.Unlock("test"))
+	}
+	close(done)
+	wg.Wait()
+}
+
+// EOF: internal/blockchain/evm/autolock_test.go
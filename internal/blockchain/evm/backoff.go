@@ -0,0 +1,104 @@
+// Package evm provides backoff helpers for the RPC retry loop: jitter and
+// provider Retry‑After hints.
+//
+// File: internal/blockchain/evm/backoff.go
+
+package evm
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// JitterMode selects how a retry backoff is randomised.
+type JitterMode string
+
+const (
+	// JitterNone waits exactly the computed backoff (default).
+	JitterNone JitterMode = ""
+	// JitterFull waits a random duration in [0, backoff).
+	JitterFull JitterMode = "full"
+	// JitterEqual waits backoff/2 plus a random duration in [0, backoff/2).
+	JitterEqual JitterMode = "equal"
+)
+
+// valid reports whether m is a known jitter mode.
+func (m JitterMode) valid() bool {
+	switch m {
+	case JitterNone, JitterFull, JitterEqual:
+		return true
+	}
+	return false
+}
+
+// Clock abstracts time so retry delays and circuit cool‑downs can be
+// controlled in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the wall‑clock implementation of Clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// SetClock replaces the time source used for retries and the circuit
+// breaker (for testing only).
+func (c *Client) SetClock(clock Clock) {
+	c.clock = clock
+}
+
+// SetRandSource replaces the jitter source; fn must return values in [0, 1)
+// (for testing only).
+func (c *Client) SetRandSource(fn func() float64) {
+	c.rand = fn
+}
+
+// retryDelay returns how long to wait before the next attempt.
+// A Retry‑After hint on a rate‑limited error wins over the computed backoff.
+func (c *Client) retryDelay(backoff time.Duration, err error) time.Duration {
+	if hint, ok := retryAfterHint(err); ok {
+		return hint
+	}
+	switch c.retry.Jitter {
+	case JitterFull:
+		return time.Duration(c.rand() * float64(backoff))
+	case JitterEqual:
+		half := backoff / 2
+		return half + time.Duration(c.rand()*float64(backoff-half))
+	}
+	return backoff
+}
+
+// retryAfterPattern matches hints such as "Retry-After: 5", "retry after 2s"
+// or "retry after 500ms" that some providers put in 429 response bodies.
+var retryAfterPattern = regexp.MustCompile(`(?i)retry[- _]?after["']?\s*[:=]?\s*(\d+(?:\.\d+)?)\s*(ms|s|sec|seconds?)?`)
+
+// retryAfterHint extracts a Retry‑After delay from a rate‑limited error.
+// Bare numbers are seconds, as in the HTTP header.
+func retryAfterHint(err error) (time.Duration, bool) {
+	if err == nil || !errors.Is(err, ErrRateLimited) {
+		return 0, false
+	}
+	m := retryAfterPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, false
+	}
+	value, perr := strconv.ParseFloat(m[1], 64)
+	if perr != nil || value < 0 {
+		return 0, false
+	}
+	unit := time.Second
+	if m[2] == "ms" {
+		unit = time.Millisecond
+	}
+	return time.Duration(value * float64(unit)), true
+}
+
+// EOF: internal/blockchain/evm/backoff.go
@@ -0,0 +1,117 @@
+// Package evm_test tests retry jitter and Retry‑After handling.
+//
+// File: internal/blockchain/evm/backoff_test.go
+
+package evm_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// fakeClock records requested waits and fires immediately.
+// Its current time only moves when advanced explicitly.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	f.waits = append(f.waits, d)
+	f.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	ch <- time.Now()
+	return ch
+}
+
+// newStatusServer returns an RPC endpoint that always answers with the given
+// HTTP status and body.
+func newStatusServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newBackoffClient(t *testing.T, url string, jitter evm.JitterMode) (*evm.Client, *fakeClock) {
+	t.Helper()
+	retry := &evm.RetryConfig{
+		MaxAttempts:    4,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		BackoffFactor:  2,
+		Jitter:         jitter,
+	}
+	client, err := evm.NewClient(context.Background(), url, &observe.NoopLogger{}, retry, time.Second)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	clock := &fakeClock{}
+	client.SetClock(clock)
+	client.SetRandSource(func() float64 { return 0.5 })
+	return client, clock
+}
+
+func TestClient_Backoff_Jitter(t *testing.T) {
+	srv := newStatusServer(t, http.StatusServiceUnavailable, "")
+	tests := []struct {
+		jitter evm.JitterMode
+		want   []time.Duration
+	}{
+		{evm.JitterNone, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}},
+		{evm.JitterFull, []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 150 * time.Millisecond}},
+		{evm.JitterEqual, []time.Duration{75 * time.Millisecond, 150 * time.Millisecond, 225 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.jitter), func(t *testing.T) {
+			client, clock := newBackoffClient(t, srv.URL, tt.jitter)
+			_, err := client.ChainID(context.Background())
+			require.Error(t, err)
+			assert.Equal(t, tt.want, clock.waits)
+		})
+	}
+}
+
+func TestClient_Backoff_RetryAfter(t *testing.T) {
+	srv := newStatusServer(t, http.StatusTooManyRequests, `{"error":"rate limited, retry after 2s"}`)
+	client, clock := newBackoffClient(t, srv.URL, evm.JitterFull)
+
+	_, err := client.ChainID(context.Background())
+	assert.ErrorIs(t, err, evm.ErrRateLimited)
+	// The hint replaces jittered backoff and is not capped by MaxBackoff.
+	assert.Equal(t, []time.Duration{2 * time.Second, 2 * time.Second, 2 * time.Second}, clock.waits)
+}
+
+func TestNewClient_UnknownJitter(t *testing.T) {
+	srv := newStatusServer(t, http.StatusOK, "")
+	_, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{}, &evm.RetryConfig{Jitter: "random"}, 0)
+	assert.ErrorContains(t, err, "unknown jitter mode")
+}
+
+// EOF: internal/blockchain/evm/backoff_test.go
@@ -64,7 +64,7 @@ func BenchmarkSendTransaction(b *testing.B) {
 	// Create wallet.
 	tmpDir := b.TempDir()
 	keyFile := tmpDir + "/wallet.key"
-	wallet, _ := evm.NewKeystore(keyFile, "test")
+	wallet, _ := evm.NewKeystoreWithOptions(keyFile, "test", evm.LightKDF)
 
 	gw := &evm.EVMGateway{
 		Client: client,
@@ -72,7 +72,7 @@ func BenchmarkSendTransaction(b *testing.B) {
 		Wallet: wallet,
 	}
 
-	to := "0x742d35Cc6634C0532925a3b844Bc9e90F1A6B1E7"
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
 	value := big.NewInt(1000)
 
 	b.ResetTimer()
@@ -0,0 +1,220 @@
+// Package evm provides EIP‑4844 blob transaction construction.
+//
+// File: internal/blockchain/evm/blob.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// blobBytesPerFieldElement is the payload carried by each 32‑byte field
+// element; the leading byte stays zero to keep the element below the
+// BLS12‑381 modulus.
+const blobBytesPerFieldElement = 31
+
+// MaxBlobPayload is the largest payload EncodeBlob fits into one blob.
+const MaxBlobPayload = params.BlobTxFieldElementsPerBlob * blobBytesPerFieldElement
+
+// EncodeBlob packs payload into a blob, 31 bytes per field element.
+// Payloads shorter than MaxBlobPayload are zero‑padded.
+func EncodeBlob(payload []byte) (*kzg4844.Blob, error) {
+	if len(payload) > MaxBlobPayload {
+		return nil, fmt.Errorf("blob payload of %d bytes exceeds %d", len(payload), MaxBlobPayload)
+	}
+	var blob kzg4844.Blob
+	for i := 0; len(payload) > 0; i++ {
+		n := copy(blob[i*32+1:(i+1)*32], payload)
+		payload = payload[n:]
+	}
+	return &blob, nil
+}
+
+// newBlobSidecar encodes payloads and computes their KZG commitments and proofs.
+func newBlobSidecar(payloads [][]byte) (*types.BlobTxSidecar, error) {
+	blobs := make([]kzg4844.Blob, len(payloads))
+	commitments := make([]kzg4844.Commitment, len(payloads))
+	proofs := make([]kzg4844.Proof, len(payloads))
+	for i, payload := range payloads {
+		blob, err := EncodeBlob(payload)
+		if err != nil {
+			return nil, fmt.Errorf("blob %d: %w", i, err)
+		}
+		blobs[i] = *blob
+		commitments[i], err = kzg4844.BlobToCommitment(&blobs[i])
+		if err != nil {
+			return nil, fmt.Errorf("blob %d: commitment: %w", i, err)
+		}
+		proofs[i], err = kzg4844.ComputeBlobProof(&blobs[i], commitments[i])
+		if err != nil {
+			return nil, fmt.Errorf("blob %d: proof: %w", i, err)
+		}
+	}
+	return types.NewBlobTxSidecar(types.BlobSidecarVersion0, blobs, commitments, proofs), nil
+}
+
+// BlobBaseFee returns the current blob base fee (eth_blobBaseFee). Nodes
+// without that method are asked for the next block's blob fee via
+// eth_feeHistory instead.
+func (c *Client) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	result, err := c.withRetry(ctx, "BlobBaseFee", func(ctx context.Context) (interface{}, error) {
+		return c.eth().BlobBaseFee(ctx)
+	})
+	if err == nil {
+		return result.(*big.Int), nil
+	}
+	if !errors.Is(err, ErrNotSupported) {
+		return nil, err
+	}
+
+	result, err = c.withRetry(ctx, "FeeHistory", func(ctx context.Context) (interface{}, error) {
+		var res struct {
+			BlobBaseFee []*hexutil.Big `json:"baseFeePerBlobGas"`
+		}
+		if err := c.eth().Client().CallContext(ctx, &res, "eth_feeHistory", hexutil.Uint(1), "latest", []float64{}); err != nil {
+			return nil, err
+		}
+		return res.BlobBaseFee, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	fees := result.([]*hexutil.Big)
+	if len(fees) == 0 {
+		return nil, fmt.Errorf("BlobBaseFee: %w", ErrNotSupported)
+	}
+	return fees[len(fees)-1].ToInt(), nil
+}
+
+// BuildBlobTx constructs and signs an EIP‑4844 transaction carrying one blob
+// per payload (each at most MaxBlobPayload bytes). Fees left nil in opts are
+// estimated; the blob fee cap defaults to twice the current blob base fee.
+// Chains without blob support fail with ErrNotSupported before signing.
+func (b *TxBuilder) BuildBlobTx(ctx context.Context, to string, blobs [][]byte, opts *TxOpts) (*types.Transaction, error) {
+	toAddr, err := parseAddress(to)
+	if err != nil {
+		return nil, fmt.Errorf("txbuilder: to address: %w", err)
+	}
+	if len(blobs) == 0 || len(blobs) > params.BlobTxMaxBlobs {
+		return nil, fmt.Errorf("txbuilder: blob count %d out of range 1..%d", len(blobs), params.BlobTxMaxBlobs)
+	}
+	if opts == nil {
+		opts = &TxOpts{}
+	}
+
+	header, err := b.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("txbuilder: get header: %w", err)
+	}
+	if header.BaseFee == nil || header.ExcessBlobGas == nil {
+		return nil, fmt.Errorf("txbuilder: blob transactions: %w", ErrNotSupported)
+	}
+
+	sidecar, err := newBlobSidecar(blobs)
+	if err != nil {
+		return nil, fmt.Errorf("txbuilder: %w", err)
+	}
+	blobHashes := sidecar.BlobHashes()
+
+	nonce, err := b.resolveNonce(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	blobFeeCap := opts.BlobFeeCap
+	if blobFeeCap == nil {
+		fee, err := b.client.BlobBaseFee(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("txbuilder: blob base fee: %w", err)
+		}
+		blobFeeCap = new(big.Int).Mul(fee, big.NewInt(2))
+	}
+
+	gasTipCap := opts.GasTipCap
+	if gasTipCap == nil {
+		tip, err := b.client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("txbuilder: suggest gas tip cap: %w", err)
+		}
+		gasTipCap = tip
+	}
+	gasFeeCap := opts.GasFeeCap
+	if gasFeeCap == nil {
+		gasFeeCap = new(big.Int).Mul(header.BaseFee, big.NewInt(2))
+		gasFeeCap.Add(gasFeeCap, gasTipCap)
+	}
+
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		est, err := b.client.EstimateGas(ctx, ethereum.CallMsg{
+			From:          b.address,
+			To:            &toAddr,
+			GasFeeCap:     gasFeeCap,
+			GasTipCap:     gasTipCap,
+			AccessList:    opts.AccessList,
+			BlobGasFeeCap: blobFeeCap,
+			BlobHashes:    blobHashes,
+		})
+		if err != nil {
+			return nil, b.estimateError(err, opts)
+		}
+		gasLimit, err = b.padGasLimit(est, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, fee := range []*big.Int{gasFeeCap, gasTipCap, blobFeeCap} {
+		if fee.Sign() < 0 || fee.BitLen() > 256 {
+			return nil, fmt.Errorf("txbuilder: fee out of range: %s", fee)
+		}
+	}
+
+	unsignedTx := types.NewTx(&types.BlobTx{
+		ChainID:    uint256.MustFromBig(b.chainID),
+		Nonce:      nonce,
+		GasTipCap:  uint256.MustFromBig(gasTipCap),
+		GasFeeCap:  uint256.MustFromBig(gasFeeCap),
+		Gas:        gasLimit,
+		To:         toAddr,
+		Value:      new(uint256.Int),
+		AccessList: opts.AccessList,
+		BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+		BlobHashes: blobHashes,
+		Sidecar:    sidecar,
+	})
+	return b.simulateAndSign(ctx, unsignedTx, opts)
+}
+
+// SendBlobTransaction builds, signs and broadcasts a blob transaction
+// (see TxBuilder.BuildBlobTx). Returns the transaction hash.
+func (g *EVMGateway) SendBlobTransaction(ctx context.Context, to string, blobs [][]byte, opts *TxOpts) (string, error) {
+	if g.wallet == nil {
+		return "", errors.New("SendBlobTransaction: no wallet configured, read‑only mode")
+	}
+	builder, err := g.txBuilder(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("SendBlobTransaction: create tx builder: %w", err)
+	}
+	signedTx, err := builder.BuildBlobTx(ctx, to, blobs, opts)
+	if err != nil {
+		return "", fmt.Errorf("SendBlobTransaction: build tx: %w", err)
+	}
+	if err := g.client.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("SendBlobTransaction: send: %w", err)
+	}
+	g.track(signedTx)
+	return signedTx.Hash().Hex(), nil
+}
+
+// EOF: internal/blockchain/evm/blob.go
@@ -0,0 +1,170 @@
+// Package evm_test tests EIP‑4844 blob transaction construction.
+//
+// File: internal/blockchain/evm/blob_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// newFakeNode serves JSON‑RPC results by method name. A result of type
+// func([]json.RawMessage) interface{} is called with the request params.
+// Unknown methods get a -32601 "method not found" error.
+func newFakeNode(t testing.TB, results map[string]interface{}) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if result, ok := results[req.Method]; ok {
+			if fn, ok := result.(func([]json.RawMessage) interface{}); ok {
+				result = fn(req.Params)
+			}
+			resp["result"] = result
+		} else {
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "the method " + req.Method + " does not exist/is not available"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// countingWallet counts signatures made by the wrapped keystore.
+type countingWallet struct {
+	*evm.Keystore
+	signs int
+}
+
+func (w *countingWallet) Sign(digest []byte) ([]byte, error) {
+	w.signs++
+	return w.Keystore.Sign(digest)
+}
+
+func (w *countingWallet) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	w.signs++
+	return w.Keystore.SignTx(ctx, tx, chainID)
+}
+
+func newBlobBuilder(t *testing.T, header *types.Header, extra map[string]interface{}) (*evm.TxBuilder, *countingWallet) {
+	t.Helper()
+	results := map[string]interface{}{
+		"eth_chainId":              "0x1",
+		"eth_getBlockByNumber":     header,
+		"eth_getTransactionCount":  "0x7",
+		"eth_estimateGas":          "0x5208",
+		"eth_maxPriorityFeePerGas": "0x3b9aca00", // 1 gwei
+	}
+	for k, v := range extra {
+		results[k] = v
+	}
+	srv := newFakeNode(t, results)
+	client, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{}, &evm.RetryConfig{MaxAttempts: 1}, time.Second)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	ks, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	wallet := &countingWallet{Keystore: ks}
+	builder, err := evm.NewTxBuilder(context.Background(), client, wallet)
+	require.NoError(t, err)
+	return builder, wallet
+}
+
+func cancunHeader() *types.Header {
+	excess := uint64(0)
+	return &types.Header{
+		Number:        big.NewInt(100),
+		Difficulty:    new(big.Int),
+		BaseFee:       big.NewInt(10e9),
+		ExcessBlobGas: &excess,
+		BlobGasUsed:   new(uint64),
+	}
+}
+
+func TestTxBuilder_BuildBlobTx(t *testing.T) {
+	builder, wallet := newBlobBuilder(t, cancunHeader(), map[string]interface{}{
+		"eth_blobBaseFee": "0x3",
+	})
+	payloads := [][]byte{[]byte("rollup batch 1"), make([]byte, evm.MaxBlobPayload)}
+
+	tx, err := builder.BuildBlobTx(context.Background(), "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", payloads, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, wallet.signs)
+
+	assert.Equal(t, uint8(types.BlobTxType), tx.Type())
+	assert.Equal(t, uint64(7), tx.Nonce())
+	assert.Equal(t, uint64(25200), tx.Gas(), "21000 estimate padded by 1.2")
+	assert.Equal(t, big.NewInt(1e9), tx.GasTipCap())
+	assert.Equal(t, big.NewInt(21e9), tx.GasFeeCap(), "2 × base fee + tip")
+	assert.Equal(t, big.NewInt(6), tx.BlobGasFeeCap(), "2 × blob base fee")
+
+	sidecar := tx.BlobTxSidecar()
+	require.NotNil(t, sidecar)
+	require.Len(t, sidecar.Blobs, 2)
+	assert.Equal(t, sidecar.BlobHashes(), tx.BlobHashes())
+	for i := range sidecar.Blobs {
+		require.NoError(t, kzg4844.VerifyBlobProof(&sidecar.Blobs[i], sidecar.Commitments[i], sidecar.Proofs[i]))
+	}
+	// Payload bytes start after the zero byte of the first field element.
+	assert.Equal(t, []byte("rollup batch 1"), sidecar.Blobs[0][1:15])
+
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1)), tx)
+	require.NoError(t, err)
+	assert.Equal(t, wallet.Address(), sender.Hex())
+}
+
+func TestTxBuilder_BuildBlobTx_FeeHistoryFallback(t *testing.T) {
+	builder, _ := newBlobBuilder(t, cancunHeader(), map[string]interface{}{
+		"eth_feeHistory": map[string]interface{}{
+			"oldestBlock":       "0x64",
+			"baseFeePerBlobGas": []*hexutil.Big{(*hexutil.Big)(big.NewInt(4)), (*hexutil.Big)(big.NewInt(5))},
+			"gasUsedRatio":      []float64{0.5},
+		},
+	})
+
+	tx, err := builder.BuildBlobTx(context.Background(), "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", [][]byte{{1}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10), tx.BlobGasFeeCap())
+}
+
+func TestTxBuilder_BuildBlobTx_NotSupported(t *testing.T) {
+	header := cancunHeader()
+	header.ExcessBlobGas, header.BlobGasUsed = nil, nil
+	builder, wallet := newBlobBuilder(t, header, nil)
+
+	_, err := builder.BuildBlobTx(context.Background(), "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", [][]byte{{1}}, nil)
+	assert.ErrorIs(t, err, evm.ErrNotSupported)
+	assert.Zero(t, wallet.signs, "must fail before signing")
+}
+
+func TestTxBuilder_BuildBlobTx_PayloadTooLarge(t *testing.T) {
+	builder, _ := newBlobBuilder(t, cancunHeader(), nil)
+	_, err := builder.BuildBlobTx(context.Background(), "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7",
+		[][]byte{make([]byte, evm.MaxBlobPayload+1)}, nil)
+	assert.ErrorContains(t, err, "exceeds")
+}
+
+// EOF: internal/blockchain/evm/blob_test.go
@@ -0,0 +1,77 @@
+// Package evm_test tests contract reads at historical blocks.
+//
+// File: internal/blockchain/evm/callat_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// storageInitCode deploys a minimal storage contract matching storageABI:
+// calldata of 36 bytes (store(uint256)) stores the argument in slot 0;
+// anything else (retrieve()) returns it.
+var storageInitCode = common.FromHex("601a600c600039601a6000f3" +
+	"36602414601257600054600052602060" + "00f35b60043560005500")
+
+func TestBoundContract_CallAtHistoricalBlock(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	_, addr, err := gateway.DeployContract(ctx, storageInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	bound, err := evm.NewBoundContract(addr.Hex(), storageABI, gateway)
+	require.NoError(t, err)
+	contract := bound.(*evm.BoundContract)
+
+	set := func(v int64) uint64 {
+		data := append(common.FromHex("6057361d"), common.LeftPadBytes(big.NewInt(v).Bytes(), 32)...)
+		to := addr.Hex()
+		_, err := gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Data: data})
+		require.NoError(t, err)
+		sim.Commit()
+		head, err := gateway.BlockNumber(ctx)
+		require.NoError(t, err)
+		return head
+	}
+	first := set(7)
+	set(42)
+
+	latest, err := contract.Call(ctx, "retrieve")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), latest[0])
+
+	old, err := contract.CallAt(ctx, &evm.CallOpts{Block: blockchain.BlockNumber(strconv.FormatUint(first, 10))}, "retrieve")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(7), old[0])
+
+	hex, err := contract.CallAt(ctx, &evm.CallOpts{Block: blockchain.BlockNumber("0x" + strconv.FormatUint(first, 16))}, "retrieve")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(7), hex[0])
+
+	_, err = contract.CallAt(ctx, &evm.CallOpts{Block: "yesterday"}, "retrieve")
+	assert.ErrorContains(t, err, "invalid block number format")
+}
+
+// EOF: internal/blockchain/evm/callat_test.go
@@ -0,0 +1,195 @@
+// Package evm encodes and decodes contract calldata without a binding.
+//
+// File: internal/blockchain/evm/calldata.go
+
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrUnknownSelector is returned by DecodeCall when the calldata's 4‑byte
+// selector matches no method of the ABI.
+var ErrUnknownSelector = errors.New("unknown method selector")
+
+// EncodeCall returns the calldata invoking method with args: the 4‑byte
+// selector followed by the ABI‑encoded arguments. method is a bare name,
+// such as "transfer", or for overloaded methods a full signature, such as
+// "safeTransferFrom(address,address,uint256)".
+func EncodeCall(abiJSON, method string, args ...interface{}) ([]byte, error) {
+	m, err := parseMethod(abiJSON, method)
+	if err != nil {
+		return nil, fmt.Errorf("EncodeCall: %w", err)
+	}
+	encoded, err := m.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("EncodeCall %s: %w", m.Sig, err)
+	}
+	return append(append([]byte(nil), m.ID...), encoded...), nil
+}
+
+// DecodeCall identifies the method that calldata invokes and decodes its
+// arguments by input name (unnamed inputs are "arg0", "arg1", …). The
+// method is returned by name, or by full signature if it is overloaded.
+func DecodeCall(abiJSON string, data []byte) (string, map[string]interface{}, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return "", nil, fmt.Errorf("DecodeCall: parse ABI: %w", err)
+	}
+	if len(data) < 4 {
+		return "", nil, fmt.Errorf("DecodeCall: calldata is %d bytes, shorter than a selector", len(data))
+	}
+	m, err := parsed.MethodById(data[:4])
+	if err != nil {
+		return "", nil, fmt.Errorf("DecodeCall: %#x: %w", data[:4], ErrUnknownSelector)
+	}
+	values, err := m.Inputs.Unpack(data[4:])
+	if err != nil {
+		return "", nil, fmt.Errorf("DecodeCall %s: %w", m.Sig, err)
+	}
+	args := make(map[string]interface{}, len(values))
+	for i, v := range values {
+		args[inputName(m.Inputs[i], i)] = v
+	}
+	name := m.RawName
+	if len(overloads(parsed, m.RawName)) > 1 {
+		name = m.Sig
+	}
+	return name, args, nil
+}
+
+// MethodSignature returns the signature of the method of the ABI whose
+// selector is the first four bytes of data, such as
+// "approve(address,uint256)".
+func MethodSignature(abiJSON string, data []byte) (string, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return "", fmt.Errorf("MethodSignature: parse ABI: %w", err)
+	}
+	if len(data) < 4 {
+		return "", fmt.Errorf("MethodSignature: calldata is %d bytes, shorter than a selector", len(data))
+	}
+	m, err := parsed.MethodById(data[:4])
+	if err != nil {
+		return "", fmt.Errorf("MethodSignature: %#x: %w", data[:4], ErrUnknownSelector)
+	}
+	return m.Sig, nil
+}
+
+// ParseSelector parses a method selector given as 0x‑prefixed hex, such as
+// "0x095ea7b3", or as a method signature, such as
+// "approve(address, uint256)". It returns the selector and the signature
+// without spaces, or "" if s was hex.
+func ParseSelector(s string) ([4]byte, string, error) {
+	var selector [4]byte
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		b, err := hexutil.Decode("0x" + s[2:])
+		if err != nil || len(b) != 4 {
+			return selector, "", fmt.Errorf("selector %q: want 4 bytes of 0x‑prefixed hex", s)
+		}
+		copy(selector[:], b)
+		return selector, "", nil
+	}
+	sig := strings.ReplaceAll(s, " ", "")
+	open := strings.IndexByte(sig, '(')
+	if open <= 0 || !strings.HasSuffix(sig, ")") || !isIdentifier(sig[:open]) {
+		return selector, "", fmt.Errorf("selector %q: want 0x‑prefixed hex or a signature like \"transfer(address,uint256)\"", s)
+	}
+	copy(selector[:], crypto.Keccak256([]byte(sig))[:4])
+	return selector, sig, nil
+}
+
+// isIdentifier reports whether s is a Solidity identifier.
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+// DecodeResult decodes the return data of a call to method, named as for
+// EncodeCall.
+func DecodeResult(abiJSON, method string, data []byte) ([]interface{}, error) {
+	m, err := parseMethod(abiJSON, method)
+	if err != nil {
+		return nil, fmt.Errorf("DecodeResult: %w", err)
+	}
+	values, err := m.Outputs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("DecodeResult %s: %w", m.Sig, err)
+	}
+	return values, nil
+}
+
+// parseMethod parses abiJSON and looks up method in it.
+func parseMethod(abiJSON, method string) (abi.Method, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("parse ABI: %w", err)
+	}
+	return lookupMethod(parsed, method)
+}
+
+// lookupMethod finds a method by full signature, by name if only one
+// method has it, or by go‑ethereum's key for overloads ("transfer0").
+func lookupMethod(parsed abi.ABI, method string) (abi.Method, error) {
+	if strings.Contains(method, "(") {
+		sig := strings.ReplaceAll(method, " ", "")
+		for _, m := range parsed.Methods {
+			if m.Sig == sig {
+				return m, nil
+			}
+		}
+		return abi.Method{}, fmt.Errorf("method %q not found in ABI", method)
+	}
+	switch candidates := overloads(parsed, method); len(candidates) {
+	case 0:
+	case 1:
+		return candidates[0], nil
+	default:
+		sigs := make([]string, len(candidates))
+		for i, m := range candidates {
+			sigs[i] = m.Sig
+		}
+		return abi.Method{}, fmt.Errorf("method %q is overloaded; use its signature: %s", method, strings.Join(sigs, ", "))
+	}
+	if m, ok := parsed.Methods[method]; ok {
+		return m, nil
+	}
+	return abi.Method{}, fmt.Errorf("method %q not found in ABI", method)
+}
+
+// overloads returns the methods named name, ordered by signature.
+func overloads(parsed abi.ABI, name string) []abi.Method {
+	var found []abi.Method
+	for _, m := range parsed.Methods {
+		if m.RawName == name {
+			found = append(found, m)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Sig < found[j].Sig })
+	return found
+}
+
+// inputName is an argument's name, or "arg<i>" if it has none.
+func inputName(arg abi.Argument, i int) string {
+	if arg.Name == "" {
+		return fmt.Sprintf("arg%d", i)
+	}
+	return arg.Name
+}
+
+// EOF: internal/blockchain/evm/calldata.go
@@ -0,0 +1,128 @@
+// Package evm_test tests calldata encoding and decoding.
+//
+// File: internal/blockchain/evm/calldata_test.go
+
+package evm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+const tokenABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"}
+]`
+
+func TestEncodeCall(t *testing.T) {
+	to := common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
+	data, err := evm.EncodeCall(tokenABI, "transfer", to, big.NewInt(1000))
+	require.NoError(t, err)
+	assert.Equal(t, "0xa9059cbb"+
+		"000000000000000000000000742d35cc6634c0532925a3b844bc9e90f1a6b1e7"+
+		"00000000000000000000000000000000000000000000000000000000000003e8",
+		hexutil.Encode(data))
+
+	same, err := evm.EncodeCall(tokenABI, "transfer(address, uint256)", to, big.NewInt(1000))
+	require.NoError(t, err)
+	assert.Equal(t, data, same)
+
+	from := common.HexToAddress("0x01")
+	data, err = evm.EncodeCall(tokenABI, "safeTransferFrom(address,address,uint256)", from, to, big.NewInt(7))
+	require.NoError(t, err)
+	assert.Equal(t, "0x42842e0e", hexutil.Encode(data[:4]))
+	data, err = evm.EncodeCall(tokenABI, "safeTransferFrom(address,address,uint256,bytes)", from, to, big.NewInt(7), []byte{1})
+	require.NoError(t, err)
+	assert.Equal(t, "0xb88d4fde", hexutil.Encode(data[:4]))
+
+	_, err = evm.EncodeCall(tokenABI, "safeTransferFrom", from, to, big.NewInt(7))
+	assert.EqualError(t, err, `EncodeCall: method "safeTransferFrom" is overloaded; use its signature: `+
+		`safeTransferFrom(address,address,uint256), safeTransferFrom(address,address,uint256,bytes)`)
+	_, err = evm.EncodeCall(tokenABI, "approve", to)
+	assert.ErrorContains(t, err, `method "approve" not found`)
+	_, err = evm.EncodeCall(tokenABI, "transfer(address)", to)
+	assert.ErrorContains(t, err, `method "transfer(address)" not found`)
+	_, err = evm.EncodeCall(tokenABI, "transfer", to)
+	assert.ErrorContains(t, err, "EncodeCall transfer(address,uint256)")
+	_, err = evm.EncodeCall(`{`, "transfer")
+	assert.ErrorContains(t, err, "parse ABI")
+}
+
+func TestDecodeCall(t *testing.T) {
+	to := common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
+	data, err := evm.EncodeCall(tokenABI, "transfer", to, big.NewInt(1000))
+	require.NoError(t, err)
+	method, args, err := evm.DecodeCall(tokenABI, data)
+	require.NoError(t, err)
+	assert.Equal(t, "transfer", method)
+	assert.Equal(t, map[string]interface{}{"to": to, "amount": big.NewInt(1000)}, args)
+
+	data, err = evm.EncodeCall(tokenABI, "safeTransferFrom(address,address,uint256,bytes)", to, to, big.NewInt(7), []byte{1})
+	require.NoError(t, err)
+	method, args, err = evm.DecodeCall(tokenABI, data)
+	require.NoError(t, err)
+	assert.Equal(t, "safeTransferFrom(address,address,uint256,bytes)", method)
+	assert.Equal(t, []byte{1}, args["data"])
+
+	data, err = evm.EncodeCall(tokenABI, "balanceOf", to)
+	require.NoError(t, err)
+	_, args, err = evm.DecodeCall(tokenABI, data)
+	require.NoError(t, err)
+	assert.Equal(t, to, args["arg0"])
+
+	_, _, err = evm.DecodeCall(tokenABI, common.FromHex("0x095ea7b3"))
+	assert.ErrorIs(t, err, evm.ErrUnknownSelector)
+	_, _, err = evm.DecodeCall(tokenABI, []byte{0xa9})
+	assert.ErrorContains(t, err, "shorter than a selector")
+	_, _, err = evm.DecodeCall(tokenABI, common.FromHex("0xa9059cbb01"))
+	assert.ErrorContains(t, err, "DecodeCall transfer(address,uint256)")
+}
+
+func TestParseSelector(t *testing.T) {
+	for _, s := range []string{"approve(address,uint256)", " approve(address, uint256) ", "0x095ea7b3", "0X095EA7B3"} {
+		selector, _, err := evm.ParseSelector(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, "0x095ea7b3", hexutil.Encode(selector[:]), s)
+	}
+	_, sig, err := evm.ParseSelector("approve(address, uint256)")
+	require.NoError(t, err)
+	assert.Equal(t, "approve(address,uint256)", sig)
+
+	for _, s := range []string{"", "approve", "0x095ea7", "0xzz5ea7b3", "(address)", "1approve()", "approve(address"} {
+		_, _, err := evm.ParseSelector(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestMethodSignature(t *testing.T) {
+	sig, err := evm.MethodSignature(tokenABI, common.FromHex("0xa9059cbb0000"))
+	require.NoError(t, err)
+	assert.Equal(t, "transfer(address,uint256)", sig)
+	_, err = evm.MethodSignature(tokenABI, common.FromHex("0x095ea7b3"))
+	assert.ErrorIs(t, err, evm.ErrUnknownSelector)
+}
+
+func TestDecodeResult(t *testing.T) {
+	values, err := evm.DecodeResult(tokenABI, "balanceOf",
+		common.FromHex("0x00000000000000000000000000000000000000000000000000000000000003e8"))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(1000)}, values)
+
+	values, err = evm.DecodeResult(tokenABI, "transfer(address,uint256)", common.LeftPadBytes([]byte{1}, 32))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{true}, values)
+
+	_, err = evm.DecodeResult(tokenABI, "balanceOf", []byte{1})
+	assert.ErrorContains(t, err, "DecodeResult balanceOf(address)")
+}
+
+// EOF: internal/blockchain/evm/calldata_test.go
@@ -0,0 +1,104 @@
+// Package evm_test tests unpacking contract call results into Go values.
+//
+// File: internal/blockchain/evm/callinto_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// pairInitCode deploys a contract that returns the words 100 and 5 to
+// every call.
+var pairInitCode = common.FromHex("600f600c600039600f6000f3" +
+	"6064600052" + "6005602052" + "60406000f3")
+
+const pairABI = `[
+	{"type":"function","name":"balances","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"required","type":"uint256"},{"name":"available_amount","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"total","inputs":[],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"ping","inputs":[],"outputs":[],"stateMutability":"view"}
+]`
+
+func TestBoundContract_CallInto(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	_, addr, err := gateway.DeployContract(ctx, pairInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+	contract, err := evm.NewBoundContract(addr.Hex(), pairABI, gateway)
+	require.NoError(t, err)
+	owner := wallet.Address()
+
+	t.Run("struct by name", func(t *testing.T) {
+		var out struct {
+			Required        *big.Int
+			AvailableAmount *big.Int
+		}
+		require.NoError(t, contract.CallInto(ctx, &out, "balances", common.HexToAddress(owner)))
+		assert.Equal(t, big.NewInt(100), out.Required)
+		assert.Equal(t, big.NewInt(5), out.AvailableAmount)
+	})
+
+	t.Run("struct by tag", func(t *testing.T) {
+		var out struct {
+			Need *big.Int `abi:"required"`
+			Have *big.Int `abi:"available_amount"`
+		}
+		require.NoError(t, contract.CallInto(ctx, &out, "balances", common.HexToAddress(owner)))
+		assert.Equal(t, big.NewInt(100), out.Need)
+		assert.Equal(t, big.NewInt(5), out.Have)
+	})
+
+	t.Run("single value", func(t *testing.T) {
+		var total *big.Int
+		require.NoError(t, contract.CallInto(ctx, &total, "total"))
+		assert.Equal(t, big.NewInt(100), total)
+		require.NoError(t, contract.CallInto(ctx, new(struct{}), "ping"))
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		var out struct{ Required *big.Int }
+		err := contract.CallInto(ctx, &out, "balances", common.HexToAddress(owner))
+		assert.ErrorContains(t, err, `has no field for output "available_amount" (uint256) of 2 outputs`)
+	})
+
+	t.Run("wrong field type", func(t *testing.T) {
+		var out struct {
+			Required        uint64
+			AvailableAmount *big.Int
+		}
+		err := contract.CallInto(ctx, &out, "balances", common.HexToAddress(owner))
+		assert.EqualError(t, err, `CallInto balances: field Required (uint64) cannot hold output "required" (uint256); use *big.Int`)
+	})
+
+	t.Run("wrong single type", func(t *testing.T) {
+		var total string
+		err := contract.CallInto(ctx, &total, "total")
+		assert.EqualError(t, err, "CallInto total: cannot store output #0 (uint256) in string; use *big.Int")
+	})
+
+	t.Run("not a pointer", func(t *testing.T) {
+		assert.ErrorContains(t, contract.CallInto(ctx, struct{}{}, "total"), "out must be a non‑nil pointer")
+		assert.ErrorContains(t, contract.CallInto(ctx, new(big.Int), "withdraw"), `method "withdraw" not found`)
+	})
+}
+
+// EOF: internal/blockchain/evm/callinto_test.go
@@ -0,0 +1,86 @@
+// Package evm_test tests pending transaction cancellation.
+//
+// File: internal/blockchain/evm/cancel_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// newSimulatedGateway wires a gateway to an in‑process simulated backend.
+func newSimulatedGateway(t *testing.T, sim *simulated.Backend, wallet blockchain.Wallet, opts ...evm.ClientOption) *evm.EVMGateway {
+	t.Helper()
+	// The simulated client wraps an *ethclient.Client in its first field.
+	ec := reflect.ValueOf(sim.Client()).Field(0).Interface().(*ethclient.Client)
+	client := evm.NewClientFromEthClient(ec, &observe.NoopLogger{}, nil, opts...)
+	return evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, wallet)
+}
+
+func TestEVMGateway_CancelTransaction(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	self := common.HexToAddress(wallet.Address())
+
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		self: {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	// Underpriced transfer: a 1 wei gas price is below the base fee,
+	// so the transaction stays in the pool.
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	stuckHash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{
+		To:       &to,
+		Value:    big.NewInt(1000),
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+	})
+	require.NoError(t, err)
+
+	cancelHash, err := gateway.CancelTransaction(ctx, stuckHash)
+	require.NoError(t, err)
+	assert.NotEqual(t, stuckHash, cancelHash)
+	sim.Commit()
+
+	ec := sim.Client()
+	receipt, err := ec.TransactionReceipt(ctx, common.HexToHash(cancelHash))
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	cancelTx, _, err := ec.TransactionByHash(ctx, common.HexToHash(cancelHash))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), cancelTx.Nonce())
+	assert.Equal(t, self, *cancelTx.To())
+	assert.Equal(t, 0, cancelTx.Value().Sign())
+
+	_, err = ec.TransactionReceipt(ctx, common.HexToHash(stuckHash))
+	assert.Error(t, err, "cancelled transaction must never be mined")
+
+	bal, err := gateway.GetBalance(ctx, to, blockchain.BlockNumberLatest)
+	require.NoError(t, err)
+	assert.Equal(t, 0, bal.Sign())
+
+	// A mined transaction can no longer be cancelled.
+	_, err = gateway.CancelTransaction(ctx, cancelHash)
+	assert.ErrorContains(t, err, "no longer pending")
+}
+
+// EOF: internal/blockchain/evm/cancel_test.go
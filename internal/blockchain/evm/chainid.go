@@ -0,0 +1,98 @@
+// Package evm verifies that an RPC endpoint serves the configured network.
+//
+// File: internal/blockchain/evm/chainid.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ChainIDMismatchError reports an endpoint serving a different network than
+// the one configured for the chain.
+type ChainIDMismatchError struct {
+	Chain    string   // configured chain name
+	Expected uint64   // ChainConfig.ChainID
+	Actual   *big.Int // chain ID reported by the node
+}
+
+func (e *ChainIDMismatchError) Error() string {
+	return fmt.Sprintf("chain %s: expected chain id %d, node reports %s", e.Chain, e.Expected, e.Actual)
+}
+
+// WithExpectedChainID makes NewEVMGateway fail unless the node reports id.
+func WithExpectedChainID(id uint64) ClientOption {
+	return func(c *Client) {
+		c.expectedChainID = &id
+	}
+}
+
+// WithSkipChainIDCheck disables the WithExpectedChainID check, e.g. for
+// local devnets whose chain ID intentionally differs from the profile.
+func WithSkipChainIDCheck() ClientOption {
+	return func(c *Client) {
+		c.skipChainIDCheck = true
+	}
+}
+
+// verifyChainID fetches and caches the node's chain ID at connect time.
+// A mismatch is fatal. An unreachable node is not: the check is deferred to
+// the first successful lookup so offline signing keeps working.
+func (g *EVMGateway) verifyChainID(ctx context.Context) error {
+	if g.configuredChainID() == nil {
+		return nil
+	}
+	_, err := g.ChainID(ctx)
+	var mismatch *ChainIDMismatchError
+	if errors.As(err, &mismatch) {
+		return err
+	}
+	if err != nil {
+		g.logger.Warn("chain id check deferred, node unreachable", map[string]interface{}{
+			"chain": g.client.chain,
+			"error": err.Error(),
+		})
+	}
+	return nil
+}
+
+// configuredChainID returns the expected chain ID, or nil if none is
+// configured or the check is skipped.
+func (g *EVMGateway) configuredChainID() *uint64 {
+	if g.client.skipChainIDCheck {
+		return nil
+	}
+	return g.client.expectedChainID
+}
+
+// checkChainID compares a chain ID reported by the node with the configured one.
+func (g *EVMGateway) checkChainID(actual *big.Int) error {
+	expected := g.configuredChainID()
+	if expected == nil {
+		return nil
+	}
+	if !actual.IsUint64() || actual.Uint64() != *expected {
+		return &ChainIDMismatchError{Chain: g.client.chain, Expected: *expected, Actual: actual}
+	}
+	return nil
+}
+
+// signingChainID returns the chain ID to sign with: the cached node value,
+// else the configured one (no RPC needed), else a fresh node lookup.
+func (g *EVMGateway) signingChainID(ctx context.Context) (*big.Int, error) {
+	g.chainIDMu.Lock()
+	cached := g.chainID
+	g.chainIDMu.Unlock()
+	if cached != nil {
+		return new(big.Int).Set(cached), nil
+	}
+	if expected := g.configuredChainID(); expected != nil {
+		return new(big.Int).SetUint64(*expected), nil
+	}
+	return g.ChainID(ctx)
+}
+
+// EOF: internal/blockchain/evm/chainid.go
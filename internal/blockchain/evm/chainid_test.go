@@ -0,0 +1,62 @@
+// Package evm_test tests chain ID verification at connect time.
+//
+// File: internal/blockchain/evm/chainid_test.go
+
+package evm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+func newCheckedGateway(t *testing.T, url string, opts ...evm.ClientOption) (*evm.EVMGateway, error) {
+	t.Helper()
+	opts = append([]evm.ClientOption{evm.WithChainName("polygon")}, opts...)
+	gw, err := evm.NewEVMGateway(context.Background(), url, &observe.NoopLogger{}, nil, 0, nil, opts...)
+	if gw != nil {
+		t.Cleanup(func() { gw.Close() })
+	}
+	return gw, err
+}
+
+func TestNewEVMGateway_ChainIDMismatch(t *testing.T) {
+	srv := newToggleServer(t) // reports chain id 1
+
+	_, err := newCheckedGateway(t, srv.URL, evm.WithExpectedChainID(137))
+	require.Error(t, err)
+	assert.EqualError(t, err, "chain polygon: expected chain id 137, node reports 1")
+
+	var mismatch *evm.ChainIDMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, uint64(137), mismatch.Expected)
+}
+
+func TestNewEVMGateway_ChainIDMatchIsCached(t *testing.T) {
+	srv := newToggleServer(t)
+
+	gw, err := newCheckedGateway(t, srv.URL, evm.WithExpectedChainID(1))
+	require.NoError(t, err)
+	hits := srv.hits.Load()
+	assert.Equal(t, int32(1), hits)
+
+	id, err := gw.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id.Int64())
+	assert.Equal(t, hits, srv.hits.Load(), "chain id should come from the cache")
+}
+
+func TestNewEVMGateway_SkipChainIDCheck(t *testing.T) {
+	srv := newToggleServer(t)
+
+	_, err := newCheckedGateway(t, srv.URL, evm.WithExpectedChainID(137), evm.WithSkipChainIDCheck())
+	require.NoError(t, err)
+	assert.Zero(t, srv.hits.Load())
+}
+
+// EOF: internal/blockchain/evm/chainid_test.go
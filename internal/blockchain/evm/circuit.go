@@ -0,0 +1,187 @@
+// Package evm provides a circuit breaker that makes the RPC client fail fast
+// while an endpoint is down.
+//
+// File: internal/blockchain/evm/circuit.go
+
+package evm
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the node while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("rpc circuit open")
+
+// CircuitConfig defines when the circuit breaker trips and recovers.
+type CircuitConfig struct {
+	// FailureThreshold is the number of consecutive transport failures that
+	// opens the circuit (0 = breaker disabled).
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// Window limits how far apart counted failures may be; a failure after a
+	// longer gap starts a new count (0 = no window).
+	Window time.Duration `mapstructure:"window"`
+	// CoolDown is how long the circuit stays open before a single half‑open
+	// probe is allowed through (0 = 30s).
+	CoolDown time.Duration `mapstructure:"cool_down"`
+}
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all calls through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all calls with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets one probe through to test recovery.
+	CircuitHalfOpen
+)
+
+// String returns the state name.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// circuitBreaker tracks consecutive transport failures.
+// A nil *circuitBreaker is valid and never trips.
+type circuitBreaker struct {
+	mu           sync.Mutex
+	cfg          CircuitConfig
+	state        CircuitState
+	failures     int
+	lastFailure  time.Time
+	openedAt     time.Time
+	probing      bool
+	now          func() time.Time
+	onTransition func(from, to CircuitState)
+}
+
+// newCircuitBreaker creates a closed breaker.
+func newCircuitBreaker(cfg CircuitConfig, now func() time.Time, onTransition func(from, to CircuitState)) *circuitBreaker {
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = 30 * time.Second
+	}
+	return &circuitBreaker{
+		cfg:          cfg,
+		now:          now,
+		onTransition: onTransition,
+	}
+}
+
+// allow reports whether a call may proceed.
+// Once the cool‑down has elapsed, exactly one caller is admitted as a probe.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cfg.CoolDown {
+			return ErrCircuitOpen
+		}
+		b.transition(CircuitHalfOpen)
+		b.probing = true
+		return nil
+	case CircuitHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of an admitted call.
+// Only retryable (transport) failures count; a revert proves the node is up.
+func (b *circuitBreaker) record(err error) {
+	if b == nil {
+		return
+	}
+	failed := err != nil && IsRetryable(err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case CircuitClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		if b.cfg.Window > 0 && b.failures > 0 && now.Sub(b.lastFailure) > b.cfg.Window {
+			b.failures = 0
+		}
+		b.failures++
+		b.lastFailure = now
+		if b.failures >= b.cfg.FailureThreshold {
+			b.openedAt = now
+			b.transition(CircuitOpen)
+		}
+	case CircuitHalfOpen:
+		b.probing = false
+		if failed {
+			b.openedAt = now
+			b.transition(CircuitOpen)
+			return
+		}
+		b.failures = 0
+		b.transition(CircuitClosed)
+	}
+}
+
+// State returns the current breaker state.
+func (b *circuitBreaker) State() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// transition changes state and notifies the listener. Caller holds b.mu.
+func (b *circuitBreaker) transition(to CircuitState) {
+	from := b.state
+	b.state = to
+	if b.onTransition != nil && from != to {
+		b.onTransition(from, to)
+	}
+}
+
+// CircuitState returns the state of the client's circuit breaker
+// (always CircuitClosed when the breaker is disabled).
+func (c *Client) CircuitState() CircuitState {
+	return c.breaker.State()
+}
+
+// onCircuitChange logs breaker transitions and exports the state as a gauge.
+func (c *Client) onCircuitChange(from, to CircuitState) {
+	fields := map[string]interface{}{
+		"chain": c.chain,
+		"from":  from.String(),
+		"to":    to.String(),
+	}
+	if to == CircuitOpen {
+		c.logger.Warn("RPC circuit opened", fields)
+	} else {
+		c.logger.Info("RPC circuit state changed", fields)
+	}
+	c.metrics.Gauge("rpc_circuit_state", float64(to), c.labels())
+}
+
+// EOF: internal/blockchain/evm/circuit.go
@@ -0,0 +1,180 @@
+// Package evm_test tests the RPC circuit breaker.
+//
+// File: internal/blockchain/evm/circuit_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// toggleServer answers eth_chainId with 0x1 while up and HTTP 503 while down.
+type toggleServer struct {
+	*httptest.Server
+	down atomic.Bool
+	hits atomic.Int32
+}
+
+func newToggleServer(t *testing.T) *toggleServer {
+	t.Helper()
+	ts := &toggleServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts.hits.Add(1)
+		if ts.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  "0x1",
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// fakeMetrics records metric values per metric name.
+type fakeMetrics struct {
+	mu         sync.Mutex
+	gauges     map[string][]float64
+	counters   map[string]float64
+	histograms map[string][]float64
+	labels     []map[string]string
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{
+		gauges:     make(map[string][]float64),
+		counters:   make(map[string]float64),
+		histograms: make(map[string][]float64),
+	}
+}
+
+func (f *fakeMetrics) Counter(name string, value float64, labels ...map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.counters[name] += value
+	f.labels = append(f.labels, labels...)
+}
+
+func (f *fakeMetrics) Histogram(name string, value float64, labels ...map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.histograms[name] = append(f.histograms[name], value)
+	f.labels = append(f.labels, labels...)
+}
+
+func (f *fakeMetrics) Gauge(name string, value float64, labels ...map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.gauges[name] = append(f.gauges[name], value)
+	f.labels = append(f.labels, labels...)
+}
+
+func newBreakerClient(t *testing.T, url string, metrics observe.Metrics) (*evm.Client, *fakeClock) {
+	t.Helper()
+	client, err := evm.NewClient(context.Background(), url, &observe.NoopLogger{},
+		&evm.RetryConfig{MaxAttempts: 1}, time.Second,
+		evm.WithChainName("testnet"),
+		evm.WithMetrics(metrics),
+		evm.WithCircuitBreaker(evm.CircuitConfig{FailureThreshold: 2, CoolDown: time.Minute}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	client.SetClock(clock)
+	return client, clock
+}
+
+func TestCircuitBreaker_Lifecycle(t *testing.T) {
+	srv := newToggleServer(t)
+	metrics := newFakeMetrics()
+	client, clock := newBreakerClient(t, srv.URL, metrics)
+	ctx := context.Background()
+
+	// closed → open after two consecutive transport failures.
+	srv.down.Store(true)
+	for i := 0; i < 2; i++ {
+		_, err := client.ChainID(ctx)
+		require.Error(t, err)
+	}
+	assert.Equal(t, evm.CircuitOpen, client.CircuitState())
+
+	// open: fail fast without touching the node.
+	hits := srv.hits.Load()
+	_, err := client.ChainID(ctx)
+	assert.ErrorIs(t, err, evm.ErrCircuitOpen)
+	assert.Equal(t, hits, srv.hits.Load())
+
+	// open → half‑open after the cool‑down; the probe succeeds → closed.
+	srv.down.Store(false)
+	clock.Advance(time.Minute)
+	id, err := client.ChainID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id.Int64())
+	assert.Equal(t, evm.CircuitClosed, client.CircuitState())
+
+	assert.Equal(t, []float64{
+		float64(evm.CircuitClosed),
+		float64(evm.CircuitOpen),
+		float64(evm.CircuitHalfOpen),
+		float64(evm.CircuitClosed),
+	}, metrics.gauges["rpc_circuit_state"])
+	assert.Contains(t, metrics.labels, map[string]string{"chain": "testnet"})
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	srv := newToggleServer(t)
+	client, clock := newBreakerClient(t, srv.URL, newFakeMetrics())
+	ctx := context.Background()
+
+	srv.down.Store(true)
+	for i := 0; i < 2; i++ {
+		_, _ = client.ChainID(ctx)
+	}
+	require.Equal(t, evm.CircuitOpen, client.CircuitState())
+
+	clock.Advance(time.Minute)
+	_, err := client.ChainID(ctx)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, evm.ErrCircuitOpen, "the probe must reach the node")
+	assert.Equal(t, evm.CircuitOpen, client.CircuitState())
+
+	_, err = client.ChainID(ctx)
+	assert.ErrorIs(t, err, evm.ErrCircuitOpen)
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	srv := newToggleServer(t)
+	srv.down.Store(true)
+	client, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{}, &evm.RetryConfig{MaxAttempts: 1}, time.Second)
+	require.NoError(t, err)
+	defer client.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := client.ChainID(context.Background())
+		assert.NotErrorIs(t, err, evm.ErrCircuitOpen)
+	}
+	assert.Equal(t, evm.CircuitClosed, client.CircuitState())
+}
+
+// EOF: internal/blockchain/evm/circuit_test.go
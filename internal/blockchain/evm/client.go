@@ -15,12 +15,15 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"math/rand/v2"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"crypto/ecdsa"
 
 	"github.com/0xSemantic/lola-os/internal/observe"
@@ -32,6 +35,11 @@ type RetryConfig struct {
 	InitialBackoff  time.Duration
 	MaxBackoff      time.Duration
 	BackoffFactor   float64
+	// Jitter randomises each backoff so concurrent clients do not retry in
+	// lockstep (see JitterMode). Jitter is applied after MaxBackoff capping,
+	// so a delay never exceeds MaxBackoff; a provider's Retry‑After hint
+	// replaces the computed delay entirely and is not capped.
+	Jitter JitterMode
 }
 
 // DefaultRetryConfig is the recommended retry policy.
@@ -42,23 +50,67 @@ var DefaultRetryConfig = RetryConfig{
 	BackoffFactor:  2.0,
 }
 
+// DefaultRPCTimeout bounds a single RPC attempt when the chain configuration
+// does not specify a timeout.
+const DefaultRPCTimeout = 30 * time.Second
+
 // Client is a thread‑safe wrapper around ethclient.Client with retry and logging.
 type Client struct {
-	rpcURL string
-	ec     *ethclient.Client
-	logger observe.Logger
-	retry  RetryConfig
+	rpcURL  string
+	ecMu    sync.RWMutex
+	ec      *ethclient.Client // swapped by Reconnect; read through eth()
+	dialMu  sync.Mutex        // serialises lazy dials
+	lazy    bool              // set by WithLazyConnect
+
+	subscriptions bool // transport supports eth_subscribe (see SupportsSubscriptions)
+
+	l2Stack L2Stack        // set by WithL2Stack
+	l2fees  L2FeeEstimator // nil without an L1 data fee
+	logger  observe.Logger
+	retry   RetryConfig
+	timeout time.Duration  // per‑attempt deadline
+	clock   Clock          // timer source for backoff waits
+	rand    func() float64 // jitter source in [0, 1)
+
+	chain   string          // configured chain name, used as a label
+	metrics observe.Metrics // never nil; NoopMetrics when disabled
+	circuit *CircuitConfig  // set by WithCircuitBreaker
+	breaker *circuitBreaker // nil when the breaker is disabled
+	limiter *RateLimiter    // nil when rate limiting is disabled
+
+	metricsOn bool // false for NoopMetrics; the hot path then builds no labels
+
+	expectedChainID  *uint64 // set by WithExpectedChainID
+	skipChainIDCheck bool    // set by WithSkipChainIDCheck
+
+	blockTime time.Duration // expected block interval; 0 = unknown
+	fees      *FeeEstimator
+	cache     *rpcCache // ChainID forever, fee suggestions for blockTime
+
+	gasMultiplier float64 // pads estimated gas limits; set by WithGasLimitMultiplier
+	maxGasLimit   uint64  // caps padded gas limits; 0 = no cap
+	simulate      bool    // simulate every transaction; set by WithSimulation
+	revertReasons bool    // WaitForReceipt explains failures; set by WithRevertReasons
+	maxReorgDepth uint64  // deeper reorgs fail WaitForReceipt; 0 = never
+
+	ensRegistry common.Address // zero = DefaultENSRegistry on known chains
+	ensTTL      time.Duration  // ENS cache lifetime; 0 = DefaultENSCacheTTL
+	ens         *ENSResolver
+
+	watchBuffer  int           // WatchEvents channel capacity; set by WithWatchBuffer
+	tracing      traceSupport  // whether debug_traceTransaction works, once known
+	callTracing  traceSupport  // whether debug_traceCall works, once known
+	customErrors errorRegistry // custom errors of bound contracts, for revert reasons
 }
 
 // NewClient creates a new EVM RPC client.
 // It establishes the connection immediately; if the connection fails,
-// the error is returned and the client is unusable.
-func NewClient(ctx context.Context, rpcURL string, logger observe.Logger, retry *RetryConfig) (*Client, error) {
-	ec, err := ethclient.DialContext(ctx, rpcURL)
-	if err != nil {
-		return nil, fmt.Errorf("evm client: dial %s: %w", rpcURL, err)
-	}
-
+// the error is returned and the client is unusable, unless WithLazyConnect
+// is given, in which case dialling is retried by later calls.
+// timeout bounds each individual RPC attempt (0 = DefaultRPCTimeout); the
+// worst case for a retried call is MaxAttempts × timeout plus backoff, and
+// a shorter deadline on the caller's context always takes precedence.
+func NewClient(ctx context.Context, rpcURL string, logger observe.Logger, retry *RetryConfig, timeout time.Duration, opts ...ClientOption) (*Client, error) {
 	if retry == nil {
 		retry = &DefaultRetryConfig
 	}
@@ -74,42 +126,134 @@ func NewClient(ctx context.Context, rpcURL string, logger observe.Logger, retry
 	if retry.BackoffFactor <= 0 {
 		retry.BackoffFactor = 2.0
 	}
+	if !retry.Jitter.valid() {
+		return nil, fmt.Errorf("evm client: unknown jitter mode %q", retry.Jitter)
+	}
+	if timeout <= 0 {
+		timeout = DefaultRPCTimeout
+	}
 
-	return &Client{
-		rpcURL: rpcURL,
-		ec:     ec,
-		logger: logger,
-		retry:  *retry,
-	}, nil
+	c := &Client{
+		rpcURL:        rpcURL,
+		logger:        logger,
+		retry:         *retry,
+		timeout:       timeout,
+		clock:         realClock{},
+		rand:          rand.Float64,
+		subscriptions: subscriptionTransport(rpcURL),
+	}
+	c.applyOptions(opts)
+	if !c.l2Stack.valid() {
+		return nil, fmt.Errorf("evm client: unknown l2 stack %q", c.l2Stack)
+	}
+
+	ec, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		if !c.lazy {
+			return nil, fmt.Errorf("evm client: dial %s: %w", rpcURL, err)
+		}
+		logger.Warn("rpc endpoint unreachable, connecting lazily", map[string]interface{}{
+			"chain": c.chain,
+			"error": err.Error(),
+		})
+		return c, nil
+	}
+	c.ec = ec
+	return c, nil
 }
 
 // NewClientFromEthClient creates a client from an existing ethclient.Client (for testing).
-func NewClientFromEthClient(ec *ethclient.Client, logger observe.Logger, retry *RetryConfig) *Client {
+func NewClientFromEthClient(ec *ethclient.Client, logger observe.Logger, retry *RetryConfig, opts ...ClientOption) *Client {
 	if retry == nil {
 		retry = &DefaultRetryConfig
 	}
-	return &Client{
-		ec:     ec,
-		logger: logger,
-		rpcURL: "simulated", // not used
-		retry:  *retry,
+	c := &Client{
+		ec:      ec,
+		logger:  logger,
+		rpcURL:  "simulated", // not used
+		retry:   *retry,
+		timeout: DefaultRPCTimeout,
+		clock:   realClock{},
+		rand:    rand.Float64,
+
+		subscriptions: ec.Client().SupportsSubscriptions(),
 	}
+	c.applyOptions(opts)
+	return c
 }
 
 // Close terminates the underlying RPC connection.
 func (c *Client) Close() {
-	c.ec.Close()
+	if ec := c.eth(); ec != nil {
+		ec.Close()
+	}
+}
+
+// eth returns the current connection, or nil if a lazy client has not
+// connected yet (see connect). Callers must not keep it across calls:
+// Reconnect may replace it at any time.
+func (c *Client) eth() *ethclient.Client {
+	c.ecMu.RLock()
+	defer c.ecMu.RUnlock()
+	return c.ec
+}
+
+// Timeout returns the per‑attempt RPC deadline.
+func (c *Client) Timeout() time.Duration {
+	return c.timeout
+}
+
+// attemptContext derives the context for a single RPC attempt.
+// The caller's deadline wins if it is earlier than the per‑attempt timeout.
+func (c *Client) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.timeout)
 }
 
 // withRetry executes an RPC call with exponential backoff.
+// Errors are classified with ClassifyError; non‑retryable ones are returned
+// after the first attempt. Each attempt runs under its own deadline (see
+// attemptContext); fn must use the context it is given rather than the outer one.
+// The wait between attempts is computed by retryDelay (jitter, Retry‑After).
 // It logs each attempt and final error.
-func (c *Client) withRetry(ctx context.Context, operation string, fn func() (interface{}, error)) (interface{}, error) {
+func (c *Client) withRetry(ctx context.Context, operation string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	return c.withRetryN(ctx, operation, 1, fn)
+}
+
+// withRetryN is withRetry for calls that carry several requests (batches);
+// each attempt consumes cost rate limiter tokens.
+// The whole call, retries included, is recorded in the latency histogram.
+func (c *Client) withRetryN(ctx context.Context, operation string, cost int, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	start := c.clock.Now()
+	result, err := c.retryLoop(ctx, operation, cost, fn)
+	c.observeCall(ctx, operation, start, err)
+	return result, err
+}
+
+// retryLoop runs the attempts for withRetryN.
+func (c *Client) retryLoop(ctx context.Context, operation string, cost int, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
 	var lastErr error
 	backoff := c.retry.InitialBackoff
 
 	for attempt := 1; attempt <= c.retry.MaxAttempts; attempt++ {
+		// Fail fast while the endpoint is known to be down.
+		if err := c.breaker.allow(); err != nil {
+			return nil, fmt.Errorf("%s: %w", operation, err)
+		}
+		if err := c.connect(ctx); err != nil {
+			return nil, fmt.Errorf("%s: %w", operation, err)
+		}
+		// Every attempt, including retries, counts against the rate limit.
+		if err := c.limiter.wait(ctx, c.clock, cost); err != nil {
+			return nil, fmt.Errorf("%s: %w", operation, err)
+		}
+
 		// Attempt the call.
-		result, err := fn()
+		attemptCtx, cancel := c.attemptContext(ctx)
+		result, err := fn(attemptCtx)
+		cancel()
+		if ctx.Err() == nil {
+			c.breaker.record(err)
+		}
 		if err == nil {
 			c.logger.Debug("RPC call succeeded",
 				map[string]interface{}{
@@ -119,27 +263,38 @@ func (c *Client) withRetry(ctx context.Context, operation string, fn func() (int
 			return result, nil
 		}
 
-		lastErr = err
+		// The caller gave up; further attempts cannot succeed.
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		classified := ClassifyError(err)
+		lastErr = classified
 		c.logger.Warn("RPC call failed",
 			map[string]interface{}{
 				"operation": operation,
 				"attempt":   attempt,
 				"error":     err.Error(),
+				"retryable": classified.Retryable,
 			})
 
+		// Deterministic failures (reverts, bad nonces, ...) would fail again.
+		if !classified.Retryable {
+			return nil, fmt.Errorf("%s: %w", operation, classified)
+		}
+
 		// If last attempt, break out.
 		if attempt == c.retry.MaxAttempts {
 			break
 		}
 
 		// Wait for backoff, respecting context cancellation.
-		timer := time.NewTimer(backoff)
 		select {
 		case <-ctx.Done():
-			timer.Stop()
 			return nil, ctx.Err()
-		case <-timer.C:
+		case <-c.clock.After(c.retryDelay(backoff, classified)):
 		}
+		c.countRetry(operation)
 
 		// Increase backoff.
 		backoff = time.Duration(float64(backoff) * c.retry.BackoffFactor)
@@ -153,8 +308,8 @@ func (c *Client) withRetry(ctx context.Context, operation string, fn func() (int
 
 // BalanceAt returns the wei balance of the given address at the specified block.
 func (c *Client) BalanceAt(ctx context.Context, address common.Address, block *big.Int) (*big.Int, error) {
-	result, err := c.withRetry(ctx, "BalanceAt", func() (interface{}, error) {
-		return c.ec.BalanceAt(ctx, address, block)
+	result, err := c.withRetry(ctx, "BalanceAt", func(ctx context.Context) (interface{}, error) {
+		return c.eth().BalanceAt(ctx, address, block)
 	})
 	if err != nil {
 		return nil, err
@@ -164,8 +319,8 @@ func (c *Client) BalanceAt(ctx context.Context, address common.Address, block *b
 
 // CallContract executes a message call and returns the raw result data.
 func (c *Client) CallContract(ctx context.Context, call ethereum.CallMsg, block *big.Int) ([]byte, error) {
-	result, err := c.withRetry(ctx, "CallContract", func() (interface{}, error) {
-		return c.ec.CallContract(ctx, call, block)
+	result, err := c.withRetry(ctx, "CallContract", func(ctx context.Context) (interface{}, error) {
+		return c.eth().CallContract(ctx, call, block)
 	})
 	if err != nil {
 		return nil, err
@@ -173,21 +328,24 @@ func (c *Client) CallContract(ctx context.Context, call ethereum.CallMsg, block
 	return result.([]byte), nil
 }
 
-// ChainID retrieves the chain ID of the connected network.
+// ChainID retrieves the chain ID of the connected network. It is cached
+// after the first success (see ContextWithoutCache).
 func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
-	result, err := c.withRetry(ctx, "ChainID", func() (interface{}, error) {
-		return c.ec.ChainID(ctx)
+	return c.cachedBigInt(ctx, cacheChainID, -1, func(ctx context.Context) (*big.Int, error) {
+		result, err := c.withRetry(ctx, "ChainID", func(ctx context.Context) (interface{}, error) {
+			return c.eth().ChainID(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.(*big.Int), nil
 	})
-	if err != nil {
-		return nil, err
-	}
-	return result.(*big.Int), nil
 }
 
 // BlockNumber returns the number of the most recent block.
 func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
-	result, err := c.withRetry(ctx, "BlockNumber", func() (interface{}, error) {
-		return c.ec.BlockNumber(ctx)
+	result, err := c.withRetry(ctx, "BlockNumber", func(ctx context.Context) (interface{}, error) {
+		return c.eth().BlockNumber(ctx)
 	})
 	if err != nil {
 		return 0, err
@@ -197,8 +355,8 @@ func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
 
 // EstimateGas tries to estimate the gas needed for a transaction or call.
 func (c *Client) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
-	result, err := c.withRetry(ctx, "EstimateGas", func() (interface{}, error) {
-		return c.ec.EstimateGas(ctx, call)
+	result, err := c.withRetry(ctx, "EstimateGas", func(ctx context.Context) (interface{}, error) {
+		return c.eth().EstimateGas(ctx, call)
 	})
 	if err != nil {
 		return 0, err
@@ -209,8 +367,8 @@ func (c *Client) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64
 // PendingNonceAt returns the account nonce of the given address in the pending state.
 // This is needed for write operations (Phase 3).
 func (c *Client) PendingNonceAt(ctx context.Context, address common.Address) (uint64, error) {
-	result, err := c.withRetry(ctx, "PendingNonceAt", func() (interface{}, error) {
-		return c.ec.PendingNonceAt(ctx, address)
+	result, err := c.withRetry(ctx, "PendingNonceAt", func(ctx context.Context) (interface{}, error) {
+		return c.eth().PendingNonceAt(ctx, address)
 	})
 	if err != nil {
 		return 0, err
@@ -218,26 +376,125 @@ func (c *Client) PendingNonceAt(ctx context.Context, address common.Address) (ui
 	return result.(uint64), nil
 }
 
-// SuggestGasPrice retrieves the currently suggested gas price.
-func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
-	result, err := c.withRetry(ctx, "SuggestGasPrice", func() (interface{}, error) {
-		return c.ec.SuggestGasPrice(ctx)
+// NonceAt returns the account nonce of the given address at the specified
+// block (nil = latest), i.e. the number of its confirmed transactions.
+func (c *Client) NonceAt(ctx context.Context, address common.Address, block *big.Int) (uint64, error) {
+	result, err := c.withRetry(ctx, "NonceAt", func(ctx context.Context) (interface{}, error) {
+		return c.eth().NonceAt(ctx, address, block)
 	})
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return result.(*big.Int), nil
+	return result.(uint64), nil
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price. It is cached
+// for one block time (see WithBlockTime, InvalidateFees).
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return c.cachedBigInt(ctx, cacheGasPrice, c.blockTime, func(ctx context.Context) (*big.Int, error) {
+		result, err := c.withRetry(ctx, "SuggestGasPrice", func(ctx context.Context) (interface{}, error) {
+			return c.eth().SuggestGasPrice(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.(*big.Int), nil
+	})
 }
 
 // SuggestGasTipCap retrieves the currently suggested EIP‑1559 priority fee.
+// It is cached for one block time (see WithBlockTime, InvalidateFees).
 func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
-	result, err := c.withRetry(ctx, "SuggestGasTipCap", func() (interface{}, error) {
-		return c.ec.SuggestGasTipCap(ctx)
+	return c.cachedBigInt(ctx, cacheGasTipCap, c.blockTime, func(ctx context.Context) (*big.Int, error) {
+		result, err := c.withRetry(ctx, "SuggestGasTipCap", func(ctx context.Context) (interface{}, error) {
+			return c.eth().SuggestGasTipCap(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.(*big.Int), nil
+	})
+}
+
+// HeaderByNumber returns the block header with the given number (nil = latest).
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	result, err := c.withRetry(ctx, "HeaderByNumber", func(ctx context.Context) (interface{}, error) {
+		return c.eth().HeaderByNumber(ctx, number)
 	})
 	if err != nil {
 		return nil, err
 	}
-	return result.(*big.Int), nil
+	return result.(*types.Header), nil
+}
+
+// SendTransaction broadcasts a signed transaction.
+// It is bounded by the per‑attempt timeout but never retried: a send that
+// timed out may still have reached the node, and a blind resend would only
+// report a misleading "already known" error.
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	start := c.clock.Now()
+	err := c.sendTransaction(ctx, tx)
+	c.observeCall(ctx, "SendTransaction", start, err)
+	return err
+}
+
+// sendTransaction performs the single SendTransaction attempt.
+func (c *Client) sendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := c.breaker.allow(); err != nil {
+		return fmt.Errorf("SendTransaction: %w", err)
+	}
+	if err := c.connect(ctx); err != nil {
+		return fmt.Errorf("SendTransaction: %w", err)
+	}
+	if err := c.limiter.wait(ctx, c.clock, 1); err != nil {
+		return fmt.Errorf("SendTransaction: %w", err)
+	}
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	err := c.eth().SendTransaction(attemptCtx, tx)
+	if ctx.Err() == nil {
+		c.breaker.record(err)
+	}
+	if err != nil {
+		return fmt.Errorf("SendTransaction: %w", ClassifyError(err))
+	}
+	return nil
+}
+
+// BatchCall sends several JSON‑RPC requests in one round trip.
+// Each element counts as one rate limiter token. Per‑element failures are
+// reported in the elements' Error fields, not in the returned error.
+func (c *Client) BatchCall(ctx context.Context, batch []rpc.BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	_, err := c.withRetryN(ctx, "BatchCall", len(batch), func(ctx context.Context) (interface{}, error) {
+		return nil, c.eth().Client().BatchCallContext(ctx, batch)
+	})
+	return err
+}
+
+// pendingTx pairs a transaction with its pending status for withRetry.
+type pendingTx struct {
+	tx      *types.Transaction
+	pending bool
+}
+
+// TransactionByHash returns the transaction with the given hash and whether
+// it is still waiting in the mempool.
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	result, err := c.withRetry(ctx, "TransactionByHash", func(ctx context.Context) (interface{}, error) {
+		tx, pending, err := c.eth().TransactionByHash(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		return pendingTx{tx: tx, pending: pending}, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	p := result.(pendingTx)
+	return p.tx, p.pending, nil
 }
 
 // EOF: internal/blockchain/evm/client.go
\ No newline at end of file
@@ -0,0 +1,58 @@
+// Package evm reads deployed bytecode to tell contracts from plain accounts.
+//
+// File: internal/blockchain/evm/code.go
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// CodeAt returns the bytecode deployed at address at the specified block
+// (nil = latest). Accounts without code return an empty slice.
+func (c *Client) CodeAt(ctx context.Context, address common.Address, block *big.Int) ([]byte, error) {
+	result, err := c.withRetry(ctx, "CodeAt", func(ctx context.Context) (interface{}, error) {
+		return c.eth().CodeAt(ctx, address, block)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// GetCode returns the bytecode deployed at address, which may be a hex
+// address or an ENS name, at the specified block.
+func (g *EVMGateway) GetCode(ctx context.Context, address string, block blockchain.BlockNumber) ([]byte, error) {
+	addr, err := g.resolveAddress(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("GetCode: %w", err)
+	}
+	blockNum, err := parseBlockNumber(block)
+	if err != nil {
+		return nil, fmt.Errorf("GetCode: %w", err)
+	}
+	code, err := g.client.CodeAt(ctx, addr, blockNum)
+	if err != nil {
+		return nil, fmt.Errorf("GetCode: %w", err)
+	}
+	return code, nil
+}
+
+// IsContract reports whether address has code at the latest block. Accounts
+// with an EIP‑7702 delegation count as contracts, since value sent to them
+// runs the delegate's code. It implements blockchain.ContractDetector.
+func (g *EVMGateway) IsContract(ctx context.Context, address string) (bool, error) {
+	code, err := g.GetCode(ctx, address, blockchain.BlockNumberLatest)
+	if err != nil {
+		return false, fmt.Errorf("IsContract: %w", err)
+	}
+	return len(code) > 0, nil
+}
+
+// EOF: internal/blockchain/evm/code.go
@@ -0,0 +1,54 @@
+// Package evm_test tests contract detection.
+//
+// File: internal/blockchain/evm/code_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+func TestEVMGateway_IsContract(t *testing.T) {
+	token := common.HexToAddress("0x1001")
+	holder := common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		token:  {Code: revertNopeRuntime, Balance: new(big.Int)},
+		holder: {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, nil)
+	ctx := context.Background()
+
+	var detector blockchain.ContractDetector = gateway
+	isContract, err := detector.IsContract(ctx, token.Hex())
+	require.NoError(t, err)
+	assert.True(t, isContract)
+
+	for _, addr := range []string{holder.Hex(), "0x000000000000000000000000000000000000dEaD"} {
+		isContract, err = gateway.IsContract(ctx, addr)
+		require.NoError(t, err)
+		assert.False(t, isContract, addr)
+	}
+
+	code, err := gateway.GetCode(ctx, token.Hex(), blockchain.BlockNumberLatest)
+	require.NoError(t, err)
+	assert.Equal(t, revertNopeRuntime, code)
+	code, err = gateway.GetCode(ctx, token.Hex(), "0")
+	require.NoError(t, err)
+	assert.Equal(t, revertNopeRuntime, code, "genesis block")
+
+	_, err = gateway.IsContract(ctx, "0x1234")
+	assert.ErrorContains(t, err, "IsContract")
+}
+
+// EOF: internal/blockchain/evm/code_test.go
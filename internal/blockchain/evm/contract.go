@@ -6,8 +6,9 @@ package evm
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"math/big"
+	"reflect"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -24,18 +25,23 @@ type BoundContract struct {
 }
 
 // NewBoundContract creates a new contract binding.
-// The ABI is parsed at construction; invalid ABI returns an error.
+// The ABI is parsed at construction; invalid ABI returns an error. Its
+// custom errors are registered with the gateway's client so that reverts
+// are decoded by name (see Client.RegisterErrors).
 func NewBoundContract(address string, abiJSON string, gateway *EVMGateway) (blockchain.Contract, error) {
-	if !common.IsHexAddress(address) {
-		return nil, fmt.Errorf("invalid contract address: %s", address)
+	addr, err := parseAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("contract address: %w", err)
 	}
-	addr := common.HexToAddress(address)
 
 	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
 	if err != nil {
 		return nil, fmt.Errorf("parse ABI: %w", err)
 	}
 
+	if gateway != nil && gateway.client != nil {
+		gateway.client.RegisterErrors(parsedABI)
+	}
 	return &BoundContract{
 		address: addr,
 		abi:     parsedABI,
@@ -43,48 +49,288 @@ func NewBoundContract(address string, abiJSON string, gateway *EVMGateway) (bloc
 	}, nil
 }
 
+// Address returns the address of the bound contract.
+func (c *BoundContract) Address() common.Address {
+	return c.address
+}
+
+// CallOpts customises a read‑only contract call.
+type CallOpts struct {
+	// Block to read state at (empty = latest). Reading past blocks needs
+	// an archive node once the state has been pruned.
+	Block blockchain.BlockNumber
+}
+
 // Call executes a read‑only contract method.
+// method is the bare name or, if the ABI overloads it, the full signature,
+// e.g. "balanceOf(address,uint256)".
 // args are the method parameters, which are ABI‑encoded.
 // Returns the decoded return values as a slice of interface{}.
 func (c *BoundContract) Call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
-	// 1. Look up method in ABI.
-	m, ok := c.abi.Methods[method]
-	if !ok {
-		return nil, fmt.Errorf("method %q not found in ABI", method)
+	return c.CallAt(ctx, nil, method, args...)
+}
+
+// CallAt is Call with options; opts may be nil.
+func (c *BoundContract) CallAt(ctx context.Context, opts *CallOpts, method string, args ...interface{}) ([]interface{}, error) {
+	m, resultData, err := c.call(ctx, opts, method, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// The ABI binding returns a single `*interface{}` or multiple values.
+	unpacked, err := m.Outputs.Unpack(resultData)
+	if err != nil {
+		return nil, fmt.Errorf("unpack result: %w", err)
+	}
+
+	// If the method returns a single value, it's often wrapped; we return as slice.
+	return unpacked, nil
+}
+
+// CallInto executes a read‑only contract method and unpacks its results
+// into out, which must be a pointer. Multiple return values fill the
+// fields of a struct, matched by `abi:"name"` tag or by the camel‑cased
+// output name; a single return value may be unpacked into a pointer of
+// its Go type, e.g. **big.Int for uint256.
+func (c *BoundContract) CallInto(ctx context.Context, out interface{}, method string, args ...interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("CallInto: out must be a non‑nil pointer, got %T", out)
+	}
+	m, resultData, err := c.call(ctx, nil, method, args)
+	if err != nil {
+		return err
+	}
+	if len(m.Outputs) == 0 {
+		return nil
+	}
+	values, err := m.Outputs.Unpack(resultData)
+	if err != nil {
+		return fmt.Errorf("unpack result: %w", err)
+	}
+	if err := m.Outputs.Copy(out, values); err != nil {
+		if detail := outputMismatch(m.Outputs, values, rv.Elem()); detail != "" {
+			return fmt.Errorf("CallInto %s: %s", method, detail)
+		}
+		return fmt.Errorf("CallInto %s: %w", method, err)
+	}
+	return nil
+}
+
+// call packs and executes a read‑only call of method, returning the ABI
+// method and the raw result. A revert is returned as *ContractRevertError.
+func (c *BoundContract) call(ctx context.Context, opts *CallOpts, method string, args []interface{}) (abi.Method, []byte, error) {
+	if opts == nil {
+		opts = &CallOpts{}
 	}
 
-	// 2. Pack the arguments.
-	data, err := c.abi.Pack(method, args...)
+	// 1. Look up the method in the ABI and pack the arguments.
+	m, data, err := c.pack(method, args)
 	if err != nil {
-		return nil, fmt.Errorf("pack arguments: %w", err)
+		return abi.Method{}, nil, err
 	}
 
-	// 3. Construct the call.
+	// 2. Construct the call.
 	call := &blockchain.ContractCall{
-		To:   c.address.Hex(),
-		Data: data,
+		To:    c.address.Hex(),
+		Data:  data,
+		Block: opts.Block,
 	}
 
-	// 4. Execute call via gateway.
+	// 3. Execute call via gateway.
 	resultData, err := c.gateway.CallContract(ctx, call)
 	if err != nil {
-		return nil, fmt.Errorf("contract call: %w", err)
+		if data, ok := revertData(err); ok {
+			return abi.Method{}, nil, &ContractRevertError{Reason: c.decodeRevert(data), Data: data}
+		}
+		return abi.Method{}, nil, fmt.Errorf("contract call: %w", err)
 	}
+	return m, resultData, nil
+}
 
-	// 5. Unpack the result.
-	// The ABI binding returns a single `*interface{}` or multiple values.
-	unpacked, err := m.Outputs.Unpack(resultData)
-	if err != nil {
-		return nil, fmt.Errorf("unpack result: %w", err)
+// outputMismatch describes why values cannot be stored in dst, naming the
+// output and the struct field, or returns "" if it cannot tell.
+func outputMismatch(outputs abi.Arguments, values []interface{}, dst reflect.Value) string {
+	if len(outputs) == 1 {
+		src := reflect.TypeOf(values[0])
+		if dst.Kind() != reflect.Struct && !src.AssignableTo(dst.Type()) && !src.ConvertibleTo(dst.Type()) {
+			return fmt.Sprintf("cannot store output %s (%s) in %s; use %s", outputName(outputs[0], 0), outputs[0].Type, dst.Type(), src)
+		}
+		return ""
+	}
+	if dst.Kind() != reflect.Struct {
+		return fmt.Sprintf("%d outputs need a struct, got %s", len(outputs), dst.Type())
+	}
+	for i, output := range outputs {
+		field, ok := outputField(dst.Type(), output.Name)
+		if !ok {
+			return fmt.Sprintf("%s has no field for output %s (%s) of %d outputs",
+				dst.Type(), outputName(output, i), output.Type, len(outputs))
+		}
+		src := reflect.TypeOf(values[i])
+		if field.Type.Kind() == reflect.Struct {
+			continue // tuples are matched field by field
+		}
+		if !src.AssignableTo(field.Type) && !src.ConvertibleTo(field.Type) {
+			return fmt.Sprintf("field %s (%s) cannot hold output %s (%s); use %s",
+				field.Name, field.Type, outputName(output, i), output.Type, src)
+		}
 	}
+	return ""
+}
 
-	// If the method returns a single value, it's often wrapped; we return as slice.
-	return unpacked, nil
+// outputField finds the struct field for an ABI output: the field tagged
+// `abi:"name"`, else the field named after the camel‑cased output name.
+func outputField(t reflect.Type, name string) (reflect.StructField, bool) {
+	if name == "" {
+		return reflect.StructField{}, false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Tag.Get("abi") == name {
+			return f, true
+		}
+	}
+	return t.FieldByName(abi.ToCamelCase(name))
 }
 
-// Transact is not implemented in read‑only mode.
+// outputName quotes an output's name, or gives its position if unnamed.
+func outputName(output abi.Argument, i int) string {
+	if output.Name == "" {
+		return fmt.Sprintf("#%d", i)
+	}
+	return fmt.Sprintf("%q", output.Name)
+}
+
+// TransactOpts customises a contract transaction or its estimate. Zero
+// fields are filled in as for a plain Transact: no value, estimated gas
+// and fees, and the next pending nonce.
+type TransactOpts struct {
+	// Value is the native currency in wei sent to a payable method.
+	Value *big.Int
+	// GasLimit (0 = estimate).
+	GasLimit uint64
+	// GasPrice for legacy transactions (nil = estimate).
+	GasPrice *big.Int
+	// GasFeeCap and GasTipCap for EIP‑1559 transactions (nil = estimate).
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+	// Nonce (nil = next pending nonce).
+	Nonce *uint64
+	// From is the signing account ("" = the wallet's primary account).
+	From string
+}
+
+// Transact sends a transaction invoking method with the ABI‑encoded args
+// and returns its hash. Overloaded methods are named by full signature,
+// as for Call. The transaction is simulated first, so a revert
+// fails with a *RevertError whose reason names the contract's custom
+// error, and nothing is signed or sent.
 func (c *BoundContract) Transact(ctx context.Context, method string, args ...interface{}) (string, error) {
-	return "", errors.New("Transact not implemented in read‑only EVM contract binding")
+	return c.TransactWithOpts(ctx, nil, method, args...)
+}
+
+// TransactWithOpts is Transact with options; opts may be nil.
+func (c *BoundContract) TransactWithOpts(ctx context.Context, opts *TransactOpts, method string, args ...interface{}) (string, error) {
+	tx, err := c.BuildTransaction(opts, method, args...)
+	if err != nil {
+		return "", err
+	}
+	tx.Simulate = true
+	txHash, err := c.gateway.SendTransaction(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("contract transact: %w", err)
+	}
+	return txHash, nil
+}
+
+// EstimateGas estimates the gas a transaction invoking method would use,
+// sent from the gateway's wallet.
+func (c *BoundContract) EstimateGas(ctx context.Context, method string, args ...interface{}) (uint64, error) {
+	return c.EstimateGasWithOpts(ctx, nil, method, args...)
+}
+
+// EstimateGasWithOpts is EstimateGas with options, such as the value
+// sent to a payable method; opts may be nil.
+func (c *BoundContract) EstimateGasWithOpts(ctx context.Context, opts *TransactOpts, method string, args ...interface{}) (uint64, error) {
+	tx, err := c.BuildTransaction(opts, method, args...)
+	if err != nil {
+		return 0, err
+	}
+	gas, err := c.gateway.EstimateGas(ctx, &blockchain.ContractCall{To: *tx.To, Data: tx.Data, Value: tx.Value})
+	if err != nil {
+		return 0, fmt.Errorf("contract estimate %s: %w", method, err)
+	}
+	return gas, nil
+}
+
+// EstimateCost estimates the fee in wei of a transaction invoking method:
+// its estimated gas at the current gas price, plus the L1 data fee on
+// rollups (see EVMGateway.EstimateTotalCost). The value sent is not
+// included.
+func (c *BoundContract) EstimateCost(ctx context.Context, method string, args ...interface{}) (*big.Int, error) {
+	return c.EstimateCostWithOpts(ctx, nil, method, args...)
+}
+
+// EstimateCostWithOpts is EstimateCost with options; opts may be nil. A
+// gas limit or price set in opts is used instead of the estimate.
+func (c *BoundContract) EstimateCostWithOpts(ctx context.Context, opts *TransactOpts, method string, args ...interface{}) (*big.Int, error) {
+	tx, err := c.BuildTransaction(opts, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	cost, err := c.gateway.EstimateTotalCost(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("contract estimate %s: %w", method, err)
+	}
+	return cost.Total, nil
+}
+
+// BuildTransaction returns the unsigned transaction invoking method with
+// args, with the value and gas settings of opts (may be nil).
+func (c *BoundContract) BuildTransaction(opts *TransactOpts, method string, args ...interface{}) (*blockchain.Transaction, error) {
+	_, data, err := c.pack(method, args)
+	if err != nil {
+		return nil, err
+	}
+	to := c.address.Hex()
+	tx := &blockchain.Transaction{To: &to, Data: data}
+	if opts != nil {
+		tx.Value = opts.Value
+		tx.Gas = opts.GasLimit
+		tx.GasPrice = opts.GasPrice
+		tx.GasFeeCap = opts.GasFeeCap
+		tx.GasTipCap = opts.GasTipCap
+		tx.Nonce = opts.Nonce
+		tx.From = opts.From
+	}
+	return tx, nil
+}
+
+// pack resolves method and returns it with the calldata invoking it with
+// args. method is a bare name or, for an overloaded method, its full
+// signature such as "safeTransferFrom(address,address,uint256)"; a bare
+// overloaded name is an error listing the candidate signatures.
+func (c *BoundContract) pack(method string, args []interface{}) (abi.Method, []byte, error) {
+	m, err := lookupMethod(c.abi, method)
+	if err != nil {
+		return abi.Method{}, nil, err
+	}
+	encoded, err := m.Inputs.Pack(args...)
+	if err != nil {
+		return abi.Method{}, nil, fmt.Errorf("pack arguments: %w", err)
+	}
+	return m, append(append([]byte(nil), m.ID...), encoded...), nil
+}
+
+// decodeRevert decodes revert data with the contract's ABI, falling back
+// to the errors of other contracts bound to the same client, since a
+// revert may bubble up from a nested call.
+func (c *BoundContract) decodeRevert(data []byte) string {
+	reason := DecodeRevertWithABI(data, &c.abi)
+	if strings.HasPrefix(reason, "custom error 0x") && c.gateway != nil && c.gateway.client != nil {
+		return c.gateway.client.decodeRevert(data)
+	}
+	return reason
 }
 
 // EOF: internal/blockchain/evm/contract.go
\ No newline at end of file
@@ -0,0 +1,98 @@
+// Package evm deploys contracts to deterministic addresses with CREATE2.
+//
+// File: internal/blockchain/evm/create2.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DeterministicDeployerAddress is the canonical CREATE2 factory, Arachnid's
+// deterministic‑deployment‑proxy, deployed at this address on most chains.
+// Its calldata is a 32‑byte salt followed by the init code.
+var DeterministicDeployerAddress = common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+
+// ComputeCreateAddress returns the address of the contract that deployer
+// creates with a plain deployment transaction of the given nonce.
+func ComputeCreateAddress(deployer common.Address, nonce uint64) common.Address {
+	return crypto.CreateAddress(deployer, nonce)
+}
+
+// ComputeCreate2Address returns the address of the contract that deployer,
+// usually a CREATE2 factory, creates from init code with keccak256 hash
+// initCodeHash and salt. The address does not depend on the chain.
+func ComputeCreate2Address(deployer common.Address, salt [32]byte, initCodeHash []byte) common.Address {
+	return crypto.CreateAddress2(deployer, salt, initCodeHash)
+}
+
+// Create2Factory returns the address of the CREATE2 factory that
+// DeployContractCreate2 uses by default, DeterministicDeployerAddress, so
+// that security policies see calls to it as deployments.
+func (g *EVMGateway) Create2Factory() string {
+	return DeterministicDeployerAddress.Hex()
+}
+
+// DeployContractCreate2 deploys initCode through the CREATE2 factory at
+// factory (zero = DeterministicDeployerAddress), so the contract lands at
+// ComputeCreate2Address(factory, salt, keccak256(initCode)) on every chain
+// with that factory. It waits for the deployment to be mined and checked
+// by WaitForDeployment. Deploying the same init code with the same salt
+// twice is an error. Returns the transaction hash and the contract's
+// address.
+func (g *EVMGateway) DeployContractCreate2(ctx context.Context, factory common.Address, salt [32]byte, initCode []byte, opts *TxOpts) (string, common.Address, error) {
+	if g.wallet == nil {
+		return "", common.Address{}, errors.New("DeployContractCreate2: no wallet configured, read‑only mode")
+	}
+	if factory == (common.Address{}) {
+		factory = DeterministicDeployerAddress
+	}
+	isFactory, err := g.IsContract(ctx, factory.Hex())
+	if err != nil {
+		return "", common.Address{}, fmt.Errorf("DeployContractCreate2: %w", err)
+	}
+	if !isFactory {
+		return "", common.Address{}, fmt.Errorf("DeployContractCreate2: no CREATE2 factory at %s", factory.Hex())
+	}
+	address := ComputeCreate2Address(factory, salt, crypto.Keccak256(initCode))
+	deployed, err := g.IsContract(ctx, address.Hex())
+	if err != nil {
+		return "", common.Address{}, fmt.Errorf("DeployContractCreate2: %w", err)
+	}
+	if deployed {
+		return "", common.Address{}, fmt.Errorf("DeployContractCreate2: %s is already deployed with this salt", address.Hex())
+	}
+
+	builder, err := g.txBuilder(ctx, "")
+	if err != nil {
+		return "", common.Address{}, fmt.Errorf("DeployContractCreate2: create tx builder: %w", err)
+	}
+	data := append(append([]byte(nil), salt[:]...), initCode...)
+	signedTx, err := builder.BuildContractCall(ctx, factory.Hex(), data, big.NewInt(0), opts)
+	if err != nil {
+		return "", common.Address{}, fmt.Errorf("DeployContractCreate2: build tx: %w", err)
+	}
+	if err := g.client.SendTransaction(ctx, signedTx); err != nil {
+		return "", common.Address{}, fmt.Errorf("DeployContractCreate2: send: %w", err)
+	}
+	g.track(signedTx)
+	txHash := signedTx.Hash().Hex()
+
+	if _, err := g.WaitForDeployment(ctx, txHash, address, 0); err != nil {
+		return txHash, address, fmt.Errorf("DeployContractCreate2: %w", err)
+	}
+	g.logger.Info("contract deployed with CREATE2", map[string]interface{}{
+		"factory": factory.Hex(),
+		"address": address.Hex(),
+		"tx_hash": txHash,
+	})
+	return txHash, address, nil
+}
+
+// EOF: internal/blockchain/evm/create2.go
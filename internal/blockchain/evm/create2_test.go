@@ -0,0 +1,95 @@
+// Package evm_test tests CREATE2 deployment.
+//
+// File: internal/blockchain/evm/create2_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// deterministicDeployerCode is the runtime code of the canonical CREATE2
+// factory.
+const deterministicDeployerCode = "7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf3"
+
+func TestComputeCreate2Address(t *testing.T) {
+	// Example 1 of EIP‑1014.
+	got := evm.ComputeCreate2Address(common.Address{}, [32]byte{}, crypto.Keccak256([]byte{0x00}))
+	assert.Equal(t, common.HexToAddress("0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"), got)
+
+	deployer := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	assert.Equal(t, crypto.CreateAddress(deployer, 7), evm.ComputeCreateAddress(deployer, 7))
+}
+
+func TestEVMGateway_DeployContractCreate2(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+		evm.DeterministicDeployerAddress:      {Code: common.FromHex(deterministicDeployerCode)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	initCode := common.FromHex(counterInitCode)
+
+	// deploy mines blocks until DeployContractCreate2, which waits for its
+	// transaction, returns.
+	deploy := func(factory common.Address, salt [32]byte) (common.Address, error) {
+		type result struct {
+			address common.Address
+			err     error
+		}
+		done := make(chan result, 1)
+		go func() {
+			_, address, err := gateway.DeployContractCreate2(ctx, factory, salt, initCode, nil)
+			done <- result{address, err}
+		}()
+		for {
+			select {
+			case r := <-done:
+				return r.address, r.err
+			case <-time.After(20 * time.Millisecond):
+				sim.Commit()
+			}
+		}
+	}
+
+	var saltA, saltB [32]byte
+	saltA[31], saltB[31] = 1, 2
+	a, err := deploy(common.Address{}, saltA)
+	require.NoError(t, err)
+	b, err := deploy(evm.DeterministicDeployerAddress, saltB)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, evm.ComputeCreate2Address(evm.DeterministicDeployerAddress, saltA, crypto.Keccak256(initCode)), a)
+	assert.Equal(t, evm.ComputeCreate2Address(evm.DeterministicDeployerAddress, saltB, crypto.Keccak256(initCode)), b)
+	for _, addr := range []common.Address{a, b} {
+		code, err := gateway.GetCode(ctx, addr.Hex(), blockchain.BlockNumberLatest)
+		require.NoError(t, err)
+		assert.Equal(t, common.FromHex(counterInitCode[24:]), code)
+	}
+
+	_, err = deploy(common.Address{}, saltA)
+	assert.ErrorContains(t, err, "already deployed")
+	_, err = deploy(common.HexToAddress("0x1000000000000000000000000000000000000001"), saltA)
+	assert.ErrorContains(t, err, "no CREATE2 factory")
+}
+
+// EOF: internal/blockchain/evm/create2_test.go
@@ -0,0 +1,155 @@
+// Package evm decodes Solidity custom errors with contract ABIs.
+//
+// File: internal/blockchain/evm/customerror.go
+
+package evm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ContractRevertError is returned by BoundContract.Call when the call
+// reverts. errors.Is(err, ErrReverted) matches it.
+type ContractRevertError struct {
+	// Reason is the decoded revert reason: an Error(string) message, a
+	// panic description, a custom error such as
+	// "InsufficientBalance(required=100, available=5)", or "custom error
+	// 0x…" with the selector of an error missing from the ABI. Empty if
+	// the contract reverted without data.
+	Reason string
+	// Data is the raw revert data.
+	Data []byte
+}
+
+// Error returns "contract reverted: " and the reason, if any.
+func (e *ContractRevertError) Error() string {
+	if e.Reason == "" {
+		return "contract reverted"
+	}
+	return "contract reverted: " + e.Reason
+}
+
+// Unwrap returns ErrReverted.
+func (e *ContractRevertError) Unwrap() error {
+	return ErrReverted
+}
+
+// DecodeRevertWithABI is DecodeRevert that also decodes the custom errors
+// declared in contractABI, such as "InsufficientBalance(required=100,
+// available=5)". contractABI may be nil.
+func DecodeRevertWithABI(data []byte, contractABI *abi.ABI) string {
+	if len(data) < 4 {
+		return ""
+	}
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	if contractABI != nil {
+		for _, e := range contractABI.Errors {
+			if bytes.Equal(e.ID[:4], data[:4]) {
+				return formatCustomError(e, data[4:])
+			}
+		}
+	}
+	return fmt.Sprintf("custom error %#x", data[:4])
+}
+
+// formatCustomError renders a custom error as Name(arg=value, ...).
+// Unnamed arguments are shown by position.
+func formatCustomError(e abi.Error, args []byte) string {
+	values, err := e.Inputs.Unpack(args)
+	if err != nil {
+		return fmt.Sprintf("%s(undecodable arguments %s)", e.Name, hexutil.Encode(args))
+	}
+	parts := make([]string, len(values))
+	for i, v := range values {
+		name := e.Inputs[i].Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		parts[i] = name + "=" + formatABIValue(v)
+	}
+	return e.Name + "(" + strings.Join(parts, ", ") + ")"
+}
+
+// formatABIValue renders an unpacked ABI value: addresses checksummed,
+// byte strings and fixed byte arrays as 0x‑hex, strings quoted.
+func formatABIValue(v interface{}) string {
+	switch x := v.(type) {
+	case common.Address:
+		return x.Hex()
+	case *big.Int:
+		return x.String()
+	case []byte:
+		return hexutil.Encode(x)
+	case string:
+		return strconv.Quote(x)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return hexutil.Encode(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// errorRegistry holds the custom errors of every contract bound to a
+// client, so reverts bubbling up from any of them can be decoded.
+type errorRegistry struct {
+	mu     sync.RWMutex
+	errors abi.ABI // only Errors is used
+}
+
+// RegisterErrors adds the custom errors of contractABI to those the client
+// decodes in revert reasons: simulations, state‑override calls and
+// GetRevertReason. NewBoundContract registers its ABI automatically.
+func (c *Client) RegisterErrors(contractABI abi.ABI) {
+	if len(contractABI.Errors) == 0 {
+		return
+	}
+	c.customErrors.mu.Lock()
+	defer c.customErrors.mu.Unlock()
+	if c.customErrors.errors.Errors == nil {
+		c.customErrors.errors.Errors = make(map[string]abi.Error)
+	}
+	for _, e := range contractABI.Errors {
+		c.customErrors.errors.Errors[e.Sig] = e
+	}
+}
+
+// decodeRevert decodes revert data with the registered custom errors.
+func (c *Client) decodeRevert(data []byte) string {
+	c.customErrors.mu.RLock()
+	defer c.customErrors.mu.RUnlock()
+	return DecodeRevertWithABI(data, &c.customErrors.errors)
+}
+
+// revertData returns the revert data carried by an "execution reverted"
+// error, which may be empty.
+func revertData(err error) ([]byte, bool) {
+	if !errors.Is(ClassifyError(err), ErrReverted) {
+		return nil, false
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if s, ok := dataErr.ErrorData().(string); ok {
+			return common.FromHex(s), true
+		}
+	}
+	return nil, true
+}
+
+// EOF: internal/blockchain/evm/customerror.go
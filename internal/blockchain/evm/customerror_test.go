@@ -0,0 +1,130 @@
+// Package evm_test tests decoding Solidity custom errors.
+//
+// File: internal/blockchain/evm/customerror_test.go
+
+package evm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+const vaultABI = `[
+	{"type":"function","name":"withdraw","inputs":[{"name":"amount","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"required","type":"uint256"},{"name":"available","type":"uint256"}]},
+	{"type":"error","name":"Unauthorized","inputs":[{"name":"caller","type":"address"},{"name":"","type":"bytes32"}]}
+]`
+
+var insufficientBalance = crypto.Keccak256([]byte("InsufficientBalance(uint256,uint256)"))[:4]
+
+// vaultInitCode deploys a contract that reverts every call with
+// InsufficientBalance(100, 5).
+var vaultInitCode = common.FromHex(fmt.Sprintf("601a600c600039601a6000f3"+
+	"63%x60e01b600052"+"6064600452"+"6005602452"+"60446000fd", insufficientBalance))
+
+func TestDecodeRevertWithABI(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(vaultABI))
+	require.NoError(t, err)
+	args, err := parsed.Errors["InsufficientBalance"].Inputs.Pack(big.NewInt(100), big.NewInt(5))
+	require.NoError(t, err)
+	data := append(append([]byte(nil), insufficientBalance...), args...)
+
+	assert.Equal(t, "InsufficientBalance(required=100, available=5)", evm.DecodeRevertWithABI(data, &parsed))
+	assert.Equal(t, fmt.Sprintf("custom error %#x", insufficientBalance), evm.DecodeRevertWithABI(data, nil),
+		"without the ABI the selector is still shown")
+	assert.Equal(t, fmt.Sprintf("custom error %#x", insufficientBalance), evm.DecodeRevert(data))
+
+	unauthorized := parsed.Errors["Unauthorized"]
+	args, err = unauthorized.Inputs.Pack(common.HexToAddress("0xdead"), [32]byte{0xab})
+	require.NoError(t, err)
+	assert.Equal(t,
+		"Unauthorized(caller=0x000000000000000000000000000000000000dEaD, arg1=0xab00000000000000000000000000000000000000000000000000000000000000)",
+		evm.DecodeRevertWithABI(append(unauthorized.ID[:4:4], args...), &parsed))
+
+	assert.Equal(t, "InsufficientBalance(undecodable arguments 0x01)",
+		evm.DecodeRevertWithABI(append(append([]byte(nil), insufficientBalance...), 0x01), &parsed))
+
+	// Error(string) is unaffected.
+	reason, err := abi.Arguments{{Type: abiStringType(t)}}.Pack("nope")
+	require.NoError(t, err)
+	assert.Equal(t, "nope", evm.DecodeRevertWithABI(append(common.FromHex("08c379a0"), reason...), &parsed))
+}
+
+func abiStringType(t *testing.T) abi.Type {
+	typ, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	return typ
+}
+
+func TestBoundContract_CustomErrors(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	ec := reflect.ValueOf(sim.Client()).Field(0).Interface().(*ethclient.Client)
+	client := evm.NewClientFromEthClient(ec, &observe.NoopLogger{}, nil)
+	gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, wallet)
+	ctx := context.Background()
+
+	_, addr, err := gateway.DeployContract(ctx, vaultInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	// Before binding, only the selector is known.
+	to := addr.Hex()
+	gas := uint64(100000)
+	failed, err := gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Data: common.FromHex("0x2e1a7d4d"), Gas: gas})
+	require.NoError(t, err)
+	sim.Commit()
+	reason, err := client.GetRevertReason(ctx, common.HexToHash(failed))
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("custom error %#x", insufficientBalance), reason)
+
+	bound, err := evm.NewBoundContract(addr.Hex(), vaultABI, gateway)
+	require.NoError(t, err)
+	contract := bound.(*evm.BoundContract)
+
+	// Call.
+	_, err = contract.Call(ctx, "withdraw", big.NewInt(100))
+	require.Error(t, err)
+	assert.EqualError(t, err, "contract reverted: InsufficientBalance(required=100, available=5)")
+	assert.ErrorIs(t, err, evm.ErrReverted)
+	var revert *evm.ContractRevertError
+	require.True(t, errors.As(err, &revert))
+	assert.Equal(t, insufficientBalance, revert.Data[:4])
+
+	// Transact simulates first and sends nothing.
+	_, err = contract.Transact(ctx, "withdraw", big.NewInt(100))
+	assert.ErrorIs(t, err, evm.ErrWouldRevert)
+	assert.ErrorContains(t, err, "InsufficientBalance(required=100, available=5)")
+	_, err = contract.Transact(ctx, "deposit")
+	assert.ErrorContains(t, err, `method "deposit" not found`)
+
+	// Binding registered the contract's errors with the client.
+	reason, err = client.GetRevertReason(ctx, common.HexToHash(failed))
+	require.NoError(t, err)
+	assert.Equal(t, "InsufficientBalance(required=100, available=5)", reason)
+}
+
+// EOF: internal/blockchain/evm/customerror_test.go
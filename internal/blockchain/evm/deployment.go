@@ -0,0 +1,115 @@
+// Package evm confirms that contract deployments succeeded.
+//
+// File: internal/blockchain/evm/deployment.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// ErrDeploymentFailed indicates that a deployment transaction was mined
+// without leaving code at the contract's address.
+var ErrDeploymentFailed = errors.New("contract deployment failed")
+
+// DeploymentFailedError describes a failed deployment.
+// errors.Is(err, ErrDeploymentFailed) matches it.
+type DeploymentFailedError struct {
+	TxHash  common.Hash
+	Address common.Address
+	// Receipt is the deployment's receipt.
+	Receipt *types.Receipt
+	// Reason explains the failure, such as the constructor's revert
+	// reason; empty if it could not be determined.
+	Reason string
+}
+
+// Error returns the address, transaction hash and reason.
+func (e *DeploymentFailedError) Error() string {
+	msg := fmt.Sprintf("deployment of %s in transaction %s failed", e.Address.Hex(), e.TxHash.Hex())
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// Unwrap returns ErrDeploymentFailed.
+func (e *DeploymentFailedError) Unwrap() error {
+	return ErrDeploymentFailed
+}
+
+// deploymentCodeTimeout bounds how long WaitForDeployment polls for code
+// after the receipt, for nodes whose state lags their receipts.
+const deploymentCodeTimeout = 10 * time.Second
+
+// deploymentCodePollInterval is how often WaitForDeployment polls for code.
+const deploymentCodePollInterval = 500 * time.Millisecond
+
+// WaitForDeployment waits until the deployment transaction txHash is mined
+// with the given number of confirmations (see Client.WaitForReceipt) and
+// checks that it deployed code at address. A reverted deployment, one
+// whose receipt names another contract address, or one that leaves no
+// code at address returns a *DeploymentFailedError, with the revert reason
+// when it can be determined. Returns the receipt.
+func (g *EVMGateway) WaitForDeployment(ctx context.Context, txHash string, address common.Address, confirmations uint64) (*types.Receipt, error) {
+	if len(common.FromHex(txHash)) != common.HashLength {
+		return nil, fmt.Errorf("WaitForDeployment: invalid transaction hash: %s", txHash)
+	}
+	hash := common.HexToHash(txHash)
+	failed := func(receipt *types.Receipt, reason string) error {
+		return &DeploymentFailedError{TxHash: hash, Address: address, Receipt: receipt, Reason: reason}
+	}
+
+	result, err := g.client.WaitForReceipt(ctx, hash, confirmations)
+	var reverted *TransactionRevertedError
+	if errors.As(err, &reverted) {
+		return reverted.Receipt, failed(reverted.Receipt, reverted.Reason)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("WaitForDeployment: %w", err)
+	}
+	receipt := result.Receipt
+	if receipt.Status == types.ReceiptStatusFailed {
+		reason, err := g.client.revertReason(ctx, receipt)
+		if err != nil {
+			g.logger.Warn("could not determine revert reason", map[string]interface{}{
+				"tx_hash": txHash,
+				"error":   err.Error(),
+			})
+		}
+		return receipt, failed(receipt, reason)
+	}
+	// Deployments through a factory are calls, without a ContractAddress.
+	if receipt.ContractAddress != (common.Address{}) && receipt.ContractAddress != address {
+		return receipt, failed(receipt, fmt.Sprintf("receipt names contract address %s", receipt.ContractAddress.Hex()))
+	}
+
+	deadline := time.NewTimer(deploymentCodeTimeout)
+	defer deadline.Stop()
+	for {
+		code, err := g.GetCode(ctx, address.Hex(), blockchain.BlockNumberLatest)
+		if err != nil {
+			return receipt, fmt.Errorf("WaitForDeployment: %w", err)
+		}
+		if len(code) > 0 {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return receipt, fmt.Errorf("WaitForDeployment: %w", ctx.Err())
+		case <-deadline.C:
+			return receipt, failed(receipt, "no code at address")
+		case <-time.After(deploymentCodePollInterval):
+		}
+	}
+}
+
+// EOF: internal/blockchain/evm/deployment.go
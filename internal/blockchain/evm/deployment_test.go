@@ -0,0 +1,71 @@
+// Package evm_test tests deployment confirmation.
+//
+// File: internal/blockchain/evm/deployment_test.go
+
+package evm_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// revertingInitCode is a constructor that reverts with Error("boom").
+var revertingInitCode = common.FromHex("7f08c379a000000000000000000000000000000000000000000000000000000000600052602060045260046024527f626f6f6d0000000000000000000000000000000000000000000000000000000060445260646000fd")
+
+func TestEVMGateway_WaitForDeployment(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	t.Run("deployed", func(t *testing.T) {
+		txHash, addr, err := gateway.DeployContract(ctx, common.FromHex(counterInitCode), nil)
+		require.NoError(t, err)
+		sim.Commit()
+		receipt, err := gateway.WaitForDeployment(ctx, txHash, addr, 0)
+		require.NoError(t, err)
+		assert.Equal(t, addr, receipt.ContractAddress)
+	})
+
+	t.Run("constructor reverts", func(t *testing.T) {
+		// An explicit gas limit skips estimation, which would catch the revert.
+		txHash, addr, err := gateway.DeployContract(ctx, revertingInitCode, &evm.TxOpts{GasLimit: 100000})
+		require.NoError(t, err)
+		sim.Commit()
+		receipt, err := gateway.WaitForDeployment(ctx, txHash, addr, 0)
+		require.ErrorIs(t, err, evm.ErrDeploymentFailed)
+		var failed *evm.DeploymentFailedError
+		require.True(t, errors.As(err, &failed))
+		assert.Equal(t, "boom", failed.Reason)
+		assert.Equal(t, addr, failed.Address)
+		assert.Equal(t, types.ReceiptStatusFailed, receipt.Status)
+	})
+
+	t.Run("out of gas", func(t *testing.T) {
+		txHash, addr, err := gateway.DeployContract(ctx, common.FromHex(counterInitCode), &evm.TxOpts{GasLimit: 53500})
+		require.NoError(t, err)
+		sim.Commit()
+		_, err = gateway.WaitForDeployment(ctx, txHash, addr, 0)
+		assert.ErrorIs(t, err, evm.ErrDeploymentFailed)
+	})
+
+	_, err = gateway.WaitForDeployment(ctx, "0x1234", common.Address{}, 0)
+	assert.ErrorContains(t, err, "invalid transaction hash")
+}
+
+// EOF: internal/blockchain/evm/deployment_test.go
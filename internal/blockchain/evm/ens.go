@@ -0,0 +1,294 @@
+// Package evm resolves ENS names to addresses and back.
+//
+// File: internal/blockchain/evm/ens.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrNameNotResolved indicates that an ENS name could not be resolved to
+// an address (or an address has no reverse record).
+var ErrNameNotResolved = errors.New("could not resolve name")
+
+// DefaultENSRegistry is the ENS registry deployed on Ethereum mainnet and
+// its Sepolia and Holesky testnets.
+var DefaultENSRegistry = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+// ensRegistryChains lists the chain IDs where DefaultENSRegistry is used
+// when no registry is configured.
+var ensRegistryChains = map[uint64]bool{1: true, 11155111: true, 17000: true}
+
+// DefaultENSCacheTTL is how long resolutions are cached when
+// WithENSCacheTTL is not given.
+const DefaultENSCacheTTL = 5 * time.Minute
+
+// ENS contract selectors.
+var (
+	ensResolverSelector = crypto.Keccak256([]byte("resolver(bytes32)"))[:4]
+	ensAddrSelector     = crypto.Keccak256([]byte("addr(bytes32)"))[:4]
+	ensNameSelector     = crypto.Keccak256([]byte("name(bytes32)"))[:4]
+)
+
+// IsENSName reports whether s looks like an ENS name ("vitalik.eth"):
+// dot‑separated non‑empty labels without whitespace, and not a hex address.
+func IsENSName(s string) bool {
+	if common.IsHexAddress(s) || !strings.Contains(s, ".") {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || strings.ContainsAny(label, " \t\r\n") {
+			return false
+		}
+	}
+	return true
+}
+
+// Namehash returns the ENS namehash of name (EIP‑137). The name is only
+// lower‑cased, not fully ENSIP‑15 normalised, so names should be given in
+// their normalised form.
+func Namehash(name string) common.Hash {
+	var node common.Hash
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = crypto.Keccak256Hash(node[:], crypto.Keccak256([]byte(labels[i])))
+	}
+	return node
+}
+
+// ensCacheEntry is a cached forward or reverse resolution.
+type ensCacheEntry struct {
+	value   string
+	expires time.Time
+}
+
+// ENSResolver resolves ENS names through the registry and resolver
+// contracts. Results are cached for the configured TTL. It is safe for
+// concurrent use.
+type ENSResolver struct {
+	client   *Client
+	registry common.Address // zero = chosen by chain ID on first use
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]ensCacheEntry
+}
+
+// newENSResolver creates the resolver for client.
+func newENSResolver(client *Client, registry common.Address, ttl time.Duration) *ENSResolver {
+	if ttl == 0 {
+		ttl = DefaultENSCacheTTL
+	}
+	return &ENSResolver{
+		client:   client,
+		registry: registry,
+		ttl:      ttl,
+		cache:    make(map[string]ensCacheEntry),
+	}
+}
+
+// Resolve returns the address name points to. Failures wrap
+// ErrNameNotResolved.
+func (r *ENSResolver) Resolve(ctx context.Context, name string) (common.Address, error) {
+	key := "name:" + strings.ToLower(name)
+	if v, ok := r.cached(key); ok {
+		return common.HexToAddress(v), nil
+	}
+
+	node := Namehash(name)
+	resolver, err := r.resolver(ctx, node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w %q: %v", ErrNameNotResolved, name, err)
+	}
+	res, err := r.call(ctx, resolver, ensAddrSelector, node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w %q: addr: %v", ErrNameNotResolved, name, err)
+	}
+	addr, err := decodeENSAddress(res)
+	if err != nil || addr == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("%w %q: no address record", ErrNameNotResolved, name)
+	}
+
+	r.store(key, addr.Hex())
+	return addr, nil
+}
+
+// LookupAddress returns the primary ENS name of addr from its reverse
+// record. The name is only returned if it resolves back to addr.
+func (r *ENSResolver) LookupAddress(ctx context.Context, addr common.Address) (string, error) {
+	key := "addr:" + addr.Hex()
+	if v, ok := r.cached(key); ok {
+		return v, nil
+	}
+
+	reverse := strings.ToLower(addr.Hex()[2:]) + ".addr.reverse"
+	node := Namehash(reverse)
+	resolver, err := r.resolver(ctx, node)
+	if err != nil {
+		return "", fmt.Errorf("%w for %s: %v", ErrNameNotResolved, addr.Hex(), err)
+	}
+	res, err := r.call(ctx, resolver, ensNameSelector, node)
+	if err != nil {
+		return "", fmt.Errorf("%w for %s: name: %v", ErrNameNotResolved, addr.Hex(), err)
+	}
+	name, err := decodeENSString(res)
+	if err != nil || name == "" {
+		return "", fmt.Errorf("%w for %s: no reverse record", ErrNameNotResolved, addr.Hex())
+	}
+
+	// A reverse record can claim any name; only trust it if it matches.
+	forward, err := r.Resolve(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if forward != addr {
+		return "", fmt.Errorf("%w for %s: %q resolves to %s", ErrNameNotResolved, addr.Hex(), name, forward.Hex())
+	}
+
+	r.store(key, name)
+	return name, nil
+}
+
+// resolver returns the resolver contract registered for node.
+func (r *ENSResolver) resolver(ctx context.Context, node common.Hash) (common.Address, error) {
+	registry, err := r.registryAddress(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	res, err := r.call(ctx, registry, ensResolverSelector, node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("registry: %v", err)
+	}
+	resolver, err := decodeENSAddress(res)
+	if err != nil || resolver == (common.Address{}) {
+		return common.Address{}, errors.New("no resolver set")
+	}
+	return resolver, nil
+}
+
+// registryAddress returns the configured registry, or DefaultENSRegistry on
+// chains where it is deployed.
+func (r *ENSResolver) registryAddress(ctx context.Context) (common.Address, error) {
+	if r.registry != (common.Address{}) {
+		return r.registry, nil
+	}
+	id, err := r.client.ChainID(ctx)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("chain id: %v", err)
+	}
+	if !id.IsUint64() || !ensRegistryChains[id.Uint64()] {
+		return common.Address{}, fmt.Errorf("no ENS registry configured for chain %s", id)
+	}
+	return DefaultENSRegistry, nil
+}
+
+// call invokes selector(node) on contract.
+func (r *ENSResolver) call(ctx context.Context, contract common.Address, selector []byte, node common.Hash) ([]byte, error) {
+	data := append(append([]byte(nil), selector...), node[:]...)
+	return r.client.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+}
+
+// cached returns an unexpired cache entry.
+func (r *ENSResolver) cached(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.cache[key]
+	if !ok || !r.client.clock.Now().Before(e.expires) {
+		return "", false
+	}
+	return e.value, true
+}
+
+// store caches value under key for the TTL.
+func (r *ENSResolver) store(key, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache[key] = ensCacheEntry{value: value, expires: r.client.clock.Now().Add(r.ttl)}
+}
+
+// decodeENSAddress decodes an ABI‑encoded address return value.
+func decodeENSAddress(res []byte) (common.Address, error) {
+	if len(res) < 32 {
+		return common.Address{}, fmt.Errorf("short result of %d bytes", len(res))
+	}
+	return common.BytesToAddress(res[12:32]), nil
+}
+
+// decodeENSString decodes an ABI‑encoded string return value.
+func decodeENSString(res []byte) (string, error) {
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		return "", err
+	}
+	values, err := abi.Arguments{{Type: stringType}}.Unpack(res)
+	if err != nil {
+		return "", err
+	}
+	return values[0].(string), nil
+}
+
+// ENS returns the client's ENS resolver.
+func (c *Client) ENS() *ENSResolver {
+	return c.ens
+}
+
+// ResolveName resolves an ENS name to a checksummed address. It implements
+// blockchain.NameResolver.
+func (g *EVMGateway) ResolveName(ctx context.Context, name string) (string, error) {
+	addr, err := g.client.ens.Resolve(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("ResolveName: %w", err)
+	}
+	return addr.Hex(), nil
+}
+
+// LookupAddress returns the verified primary ENS name of address.
+func (g *EVMGateway) LookupAddress(ctx context.Context, address string) (string, error) {
+	addr, err := parseAddress(address)
+	if err != nil {
+		return "", fmt.Errorf("LookupAddress: %w", err)
+	}
+	name, err := g.client.ens.LookupAddress(ctx, addr)
+	if err != nil {
+		return "", fmt.Errorf("LookupAddress: %w", err)
+	}
+	return name, nil
+}
+
+// resolveAddress parses a hex address (see NormalizeAddress) or resolves an
+// ENS name. Resolved names are logged with their address so the log
+// records both.
+func (g *EVMGateway) resolveAddress(ctx context.Context, address string) (common.Address, error) {
+	if common.IsHexAddress(address) {
+		return parseAddress(address)
+	}
+	if !IsENSName(address) {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrInvalidAddress, address)
+	}
+	addr, err := g.client.ens.Resolve(ctx, address)
+	if err != nil {
+		return common.Address{}, err
+	}
+	g.logger.Info("ens name resolved", map[string]interface{}{
+		"name":    address,
+		"address": addr.Hex(),
+	})
+	return addr, nil
+}
+
+// EOF: internal/blockchain/evm/ens.go
@@ -0,0 +1,217 @@
+// Package evm_test tests ENS name resolution.
+//
+// File: internal/blockchain/evm/ens_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+var (
+	ensResolverAddr = common.HexToAddress("0x4976fb03C32e5B8cfe2b6cCB31c09Ba78EBaBa41")
+	vitalik         = common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+)
+
+// ensNode fakes the ENS registry and one resolver. names maps names to
+// addresses; reverse maps addresses to their reverse record.
+type ensNode struct {
+	names   map[string]common.Address
+	reverse map[common.Address]string
+	calls   atomic.Int32
+}
+
+func (n *ensNode) call(params []json.RawMessage) interface{} {
+	n.calls.Add(1)
+	var msg struct {
+		To    common.Address `json:"to"`
+		Input hexutil.Bytes  `json:"input"`
+		Data  hexutil.Bytes  `json:"data"`
+	}
+	if err := json.Unmarshal(params[0], &msg); err != nil {
+		return "0x"
+	}
+	data := msg.Input
+	if len(data) == 0 {
+		data = msg.Data
+	}
+	node := common.BytesToHash(data[4:36])
+	word := func(a common.Address) string { return hexutil.Encode(common.LeftPadBytes(a[:], 32)) }
+
+	switch {
+	case msg.To == evm.DefaultENSRegistry:
+		for name := range n.names {
+			if evm.Namehash(name) == node {
+				return word(ensResolverAddr)
+			}
+		}
+		for addr := range n.reverse {
+			if evm.Namehash(strings.ToLower(addr.Hex()[2:])+".addr.reverse") == node {
+				return word(ensResolverAddr)
+			}
+		}
+		return word(common.Address{})
+	case msg.To == ensResolverAddr && hexutil.Encode(data[:4]) == "0x3b3b57de": // addr(bytes32)
+		for name, addr := range n.names {
+			if evm.Namehash(name) == node {
+				return word(addr)
+			}
+		}
+		return word(common.Address{})
+	case msg.To == ensResolverAddr: // name(bytes32)
+		stringType, _ := abi.NewType("string", "", nil)
+		for addr, name := range n.reverse {
+			if evm.Namehash(strings.ToLower(addr.Hex()[2:])+".addr.reverse") == node {
+				out, _ := abi.Arguments{{Type: stringType}}.Pack(name)
+				return hexutil.Encode(out)
+			}
+		}
+		out, _ := abi.Arguments{{Type: stringType}}.Pack("")
+		return hexutil.Encode(out)
+	}
+	return "0x"
+}
+
+func newENSGateway(t *testing.T, chainID string, n *ensNode, extra map[string]interface{}) (*evm.EVMGateway, *fakeClock) {
+	t.Helper()
+	results := map[string]interface{}{
+		"eth_chainId": chainID,
+		"eth_call":    n.call,
+	}
+	for k, v := range extra {
+		results[k] = v
+	}
+	client := newFeeClient(t, results)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	client.SetClock(clock)
+
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	return evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, wallet), clock
+}
+
+func TestNamehash(t *testing.T) {
+	assert.Equal(t, common.Hash{}, evm.Namehash(""))
+	assert.Equal(t, common.HexToHash("0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"), evm.Namehash("eth"))
+	assert.Equal(t, common.HexToHash("0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"), evm.Namehash("foo.eth"))
+	assert.Equal(t, evm.Namehash("foo.eth"), evm.Namehash("FOO.eth"))
+}
+
+func TestIsENSName(t *testing.T) {
+	assert.True(t, evm.IsENSName("vitalik.eth"))
+	assert.True(t, evm.IsENSName("pay.vitalik.eth"))
+	assert.False(t, evm.IsENSName(vitalik.Hex()))
+	assert.False(t, evm.IsENSName("vitalik"))
+	assert.False(t, evm.IsENSName("vitalik..eth"))
+	assert.False(t, evm.IsENSName("bad name.eth"))
+}
+
+func TestEVMGateway_ResolveNameCaches(t *testing.T) {
+	n := &ensNode{names: map[string]common.Address{"vitalik.eth": vitalik}}
+	gateway, clock := newENSGateway(t, "0x1", n, nil)
+	ctx := context.Background()
+
+	addr, err := gateway.ResolveName(ctx, "vitalik.eth")
+	require.NoError(t, err)
+	assert.Equal(t, vitalik.Hex(), addr)
+	assert.Equal(t, int32(2), n.calls.Load(), "registry and resolver")
+
+	_, err = gateway.ResolveName(ctx, "Vitalik.eth")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), n.calls.Load(), "served from cache")
+
+	clock.Advance(evm.DefaultENSCacheTTL)
+	_, err = gateway.ResolveName(ctx, "vitalik.eth")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), n.calls.Load(), "expired entry is refreshed")
+}
+
+func TestEVMGateway_ResolveNameFailures(t *testing.T) {
+	n := &ensNode{names: map[string]common.Address{"vitalik.eth": vitalik}}
+	ctx := context.Background()
+
+	gateway, _ := newENSGateway(t, "0x1", n, nil)
+	_, err := gateway.ResolveName(ctx, "nobody.eth")
+	require.ErrorIs(t, err, evm.ErrNameNotResolved)
+	assert.ErrorContains(t, err, `could not resolve name "nobody.eth": no resolver set`)
+
+	other, _ := newENSGateway(t, "0x89", n, nil)
+	_, err = other.ResolveName(ctx, "vitalik.eth")
+	require.ErrorIs(t, err, evm.ErrNameNotResolved)
+	assert.ErrorContains(t, err, "no ENS registry configured for chain 137")
+}
+
+func TestEVMGateway_ENSNameAsAddress(t *testing.T) {
+	n := &ensNode{names: map[string]common.Address{"vitalik.eth": vitalik}}
+	gateway, _ := newENSGateway(t, "0x1", n, map[string]interface{}{
+		"eth_getBalance": func(params []json.RawMessage) interface{} {
+			var addr common.Address
+			_ = json.Unmarshal(params[0], &addr)
+			if addr == vitalik {
+				return "0x3e8"
+			}
+			return "0x0"
+		},
+	})
+	ctx := context.Background()
+
+	bal, err := gateway.GetBalance(ctx, "vitalik.eth", blockchain.BlockNumberLatest)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), bal)
+
+	_, err = gateway.GetBalance(ctx, "nobody.eth", blockchain.BlockNumberLatest)
+	assert.ErrorIs(t, err, evm.ErrNameNotResolved)
+	_, err = gateway.GetBalance(ctx, "not-an-address", blockchain.BlockNumberLatest)
+	assert.ErrorContains(t, err, "invalid address format")
+
+	// A fully specified transaction signs without other RPC calls.
+	to, nonce := "vitalik.eth", uint64(3)
+	raw, _, err := gateway.SignTransaction(ctx, &blockchain.Transaction{
+		To:       &to,
+		Value:    big.NewInt(1),
+		Gas:      21000,
+		GasPrice: big.NewInt(1e9),
+		Nonce:    &nonce,
+	})
+	require.NoError(t, err)
+	tx := new(types.Transaction)
+	require.NoError(t, tx.UnmarshalBinary(hexutil.MustDecode(raw)))
+	assert.Equal(t, vitalik, *tx.To())
+}
+
+func TestEVMGateway_LookupAddress(t *testing.T) {
+	impostor := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	n := &ensNode{
+		names:   map[string]common.Address{"vitalik.eth": vitalik},
+		reverse: map[common.Address]string{vitalik: "vitalik.eth", impostor: "vitalik.eth"},
+	}
+	gateway, _ := newENSGateway(t, "0x1", n, nil)
+	ctx := context.Background()
+
+	name, err := gateway.LookupAddress(ctx, vitalik.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "vitalik.eth", name)
+
+	_, err = gateway.LookupAddress(ctx, impostor.Hex())
+	assert.ErrorIs(t, err, evm.ErrNameNotResolved, "reverse record must resolve back")
+}
+
+// EOF: internal/blockchain/evm/ens_test.go
@@ -0,0 +1,141 @@
+// Package evm classifies RPC failures so the retry loop and callers can tell
+// transient transport problems from deterministic execution errors.
+//
+// File: internal/blockchain/evm/errors.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Sentinel errors for well‑known RPC failure classes.
+// Use errors.Is to test an error returned by Client or EVMGateway.
+var (
+	// ErrReverted indicates the EVM reverted the call or transaction.
+	ErrReverted = errors.New("execution reverted")
+	// ErrRateLimited indicates the RPC provider throttled the request (HTTP 429).
+	ErrRateLimited = errors.New("rpc rate limited")
+	// ErrNonceTooLow indicates the transaction nonce has already been used.
+	ErrNonceTooLow = errors.New("nonce too low")
+	// ErrInsufficientFunds indicates the sender cannot cover value plus gas.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrInvalidArgument indicates the node rejected the request parameters.
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrNotSupported indicates the node does not implement the method or feature.
+	ErrNotSupported = errors.New("not supported by rpc provider")
+)
+
+// JSON‑RPC error codes used for classification.
+const (
+	rpcCodeMethodNotFound = -32601
+	rpcCodeInvalidParams  = -32602
+	rpcCodeReverted       = 3 // geth's code for "execution reverted" with data
+)
+
+// RPCError annotates an RPC failure with its classification.
+// errors.Is matches both the class sentinel (Kind) and the original error.
+type RPCError struct {
+	// Kind is one of the sentinel errors above, or nil if unclassified.
+	Kind error
+	// Retryable reports whether repeating the request may succeed.
+	Retryable bool
+	// Err is the original error returned by the transport.
+	Err error
+}
+
+// Error returns the original error message.
+func (e *RPCError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap exposes both the class sentinel and the original error.
+func (e *RPCError) Unwrap() []error {
+	if e.Kind != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Err}
+}
+
+// ClassifyError wraps err in an *RPCError describing its failure class.
+// Unrecognised errors are treated as transient and retryable.
+// Returns nil for a nil error; an already classified error is returned as is.
+func ClassifyError(err error) *RPCError {
+	if err == nil {
+		return nil
+	}
+	var classified *RPCError
+	if errors.As(err, &classified) {
+		return classified
+	}
+
+	// Context errors: cancellation is final, a deadline only ends one attempt.
+	if errors.Is(err, context.Canceled) {
+		return &RPCError{Err: err}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &RPCError{Err: err, Retryable: true}
+	}
+
+	// HTTP transport status.
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == http.StatusTooManyRequests:
+			return &RPCError{Kind: ErrRateLimited, Err: err, Retryable: true}
+		case httpErr.StatusCode >= 500:
+			return &RPCError{Err: err, Retryable: true}
+		case httpErr.StatusCode >= 400:
+			return &RPCError{Err: err}
+		}
+	}
+
+	// JSON‑RPC error codes.
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.ErrorCode() {
+		case rpcCodeReverted:
+			return &RPCError{Kind: ErrReverted, Err: err}
+		case rpcCodeMethodNotFound:
+			return &RPCError{Kind: ErrNotSupported, Err: err}
+		case rpcCodeInvalidParams:
+			return &RPCError{Kind: ErrInvalidArgument, Err: err}
+		}
+	}
+
+	// Network timeouts.
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &RPCError{Err: err, Retryable: true}
+	}
+
+	// Fall back to message matching; providers are inconsistent with codes.
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "execution reverted"), strings.Contains(msg, "vm exception"):
+		return &RPCError{Kind: ErrReverted, Err: err}
+	case strings.Contains(msg, "nonce too low"):
+		return &RPCError{Kind: ErrNonceTooLow, Err: err}
+	case strings.Contains(msg, "insufficient funds"):
+		return &RPCError{Kind: ErrInsufficientFunds, Err: err}
+	case strings.Contains(msg, "invalid argument"), strings.Contains(msg, "invalid params"):
+		return &RPCError{Kind: ErrInvalidArgument, Err: err}
+	case strings.Contains(msg, "429"), strings.Contains(msg, "too many requests"), strings.Contains(msg, "rate limit"):
+		return &RPCError{Kind: ErrRateLimited, Err: err, Retryable: true}
+	}
+	return &RPCError{Err: err, Retryable: true}
+}
+
+// IsRetryable reports whether err is worth retrying.
+func IsRetryable(err error) bool {
+	c := ClassifyError(err)
+	return c != nil && c.Retryable
+}
+
+// EOF: internal/blockchain/evm/errors.go
@@ -0,0 +1,78 @@
+// Package evm_test tests RPC error classification.
+//
+// File: internal/blockchain/evm/errors_test.go
+
+package evm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// codedError mimics a JSON‑RPC error carrying a code.
+type codedError struct {
+	msg  string
+	code int
+}
+
+func (e codedError) Error() string  { return e.msg }
+func (e codedError) ErrorCode() int { return e.code }
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		kind      error
+		retryable bool
+	}{
+		{"revert message", errors.New("execution reverted: insufficient allowance"), evm.ErrReverted, false},
+		{"revert code", codedError{"execution reverted", 3}, evm.ErrReverted, false},
+		{"invalid params code", codedError{"missing value for required argument 0", -32602}, evm.ErrInvalidArgument, false},
+		{"method not found code", codedError{"the method eth_foo does not exist/is not available", -32601}, evm.ErrNotSupported, false},
+		{"invalid argument", errors.New("invalid argument 0: hex string has odd length"), evm.ErrInvalidArgument, false},
+		{"nonce too low", errors.New("nonce too low: next nonce 5, tx nonce 4"), evm.ErrNonceTooLow, false},
+		{"insufficient funds", errors.New("insufficient funds for gas * price + value"), evm.ErrInsufficientFunds, false},
+		{"http 429", rpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}, evm.ErrRateLimited, true},
+		{"too many requests", errors.New("Too Many Requests"), evm.ErrRateLimited, true},
+		{"http 503", rpc.HTTPError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}, nil, true},
+		{"http 401", rpc.HTTPError{StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized"}, nil, false},
+		{"connection refused", errors.New("dial tcp 127.0.0.1:8545: connect: connection refused"), nil, true},
+		{"deadline", fmt.Errorf("post: %w", context.DeadlineExceeded), nil, true},
+		{"canceled", context.Canceled, nil, false},
+		{"unknown", errors.New("something odd"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := evm.ClassifyError(tt.err)
+			assert.Equal(t, tt.retryable, c.Retryable)
+			assert.Equal(t, tt.retryable, evm.IsRetryable(tt.err))
+			assert.Equal(t, tt.err, c.Err, "original error must stay reachable")
+			if tt.kind != nil {
+				assert.ErrorIs(t, c, tt.kind)
+			} else {
+				assert.Nil(t, c.Kind)
+			}
+		})
+	}
+
+	assert.Nil(t, evm.ClassifyError(nil))
+	assert.False(t, evm.IsRetryable(nil))
+}
+
+func TestClassifyError_Wrapped(t *testing.T) {
+	inner := evm.ClassifyError(errors.New("execution reverted"))
+	wrapped := fmt.Errorf("CallContract: %w", inner)
+	assert.ErrorIs(t, wrapped, evm.ErrReverted)
+	assert.Same(t, inner, evm.ClassifyError(wrapped))
+}
+
+// EOF: internal/blockchain/evm/errors_test.go
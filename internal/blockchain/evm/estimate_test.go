@@ -0,0 +1,137 @@
+// Package evm_test tests per‑method gas and cost estimates.
+//
+// File: internal/blockchain/evm/estimate_test.go
+
+package evm_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+const ownedABI = `[{"type":"function","name":"poke","inputs":[{"name":"n","type":"uint256"}],"outputs":[],"stateMutability":"payable"}]`
+
+// ownedInitCode deploys code that reverts unless called by owner.
+func ownedInitCode(owner common.Address) []byte {
+	return common.FromHex(fmt.Sprintf("6021600c60003960216000f3"+"3373%x14601f5760006000fd5b00", owner))
+}
+
+func TestBoundContract_EstimateGasAndCost(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	owner := common.HexToAddress(wallet.Address())
+	sim := simulated.NewBackend(types.GenesisAlloc{owner: {Balance: big.NewInt(1e18)}})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	_, addr, err := gateway.DeployContract(ctx, ownedInitCode(owner), nil)
+	require.NoError(t, err)
+	sim.Commit()
+	contract, err := evm.NewBoundContract(addr.Hex(), ownedABI, gateway)
+	require.NoError(t, err)
+	bound := contract.(*evm.BoundContract)
+
+	// Only the owner gets past the check, so the estimate must be made
+	// from the wallet's address.
+	gas, err := contract.EstimateGas(ctx, "poke", big.NewInt(1))
+	require.NoError(t, err)
+	assert.Greater(t, gas, uint64(21000))
+
+	price, err := sim.Client().SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	cost, err := contract.EstimateCost(ctx, "poke", big.NewInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, new(big.Int).Mul(new(big.Int).SetUint64(gas), price), cost)
+
+	// The value is part of the estimate: more than the wallet holds fails.
+	opts := &evm.TransactOpts{Value: big.NewInt(1000)}
+	withValue, err := bound.EstimateGasWithOpts(ctx, opts, "poke", big.NewInt(1))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, withValue, gas)
+	_, err = bound.EstimateGasWithOpts(ctx, &evm.TransactOpts{Value: big.NewInt(2e18)}, "poke", big.NewInt(1))
+	assert.ErrorContains(t, err, "contract estimate poke")
+	_, err = bound.EstimateCostWithOpts(ctx, &evm.TransactOpts{Value: big.NewInt(2e18)}, "poke", big.NewInt(1))
+	assert.ErrorContains(t, err, "contract estimate poke")
+
+	_, err = contract.EstimateGas(ctx, "missing")
+	assert.ErrorContains(t, err, `method "missing" not found`)
+	_, err = contract.EstimateCost(ctx, "poke")
+	assert.ErrorContains(t, err, "pack arguments")
+
+	// Another wallet's estimate reverts.
+	stranger := newSimulatedGateway(t, sim, nil)
+	other, err := evm.NewBoundContract(addr.Hex(), ownedABI, stranger)
+	require.NoError(t, err)
+	_, err = other.EstimateGas(ctx, "poke", big.NewInt(1))
+	assert.Error(t, err)
+
+	// Value is sent by TransactWithOpts.
+	txHash, err := bound.TransactWithOpts(ctx, opts, "poke", big.NewInt(1))
+	require.NoError(t, err)
+	sim.Commit()
+	balance, err := sim.Client().BalanceAt(ctx, addr, nil)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), balance, txHash)
+}
+
+func TestBoundContract_TransactWithOpts(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	owner := common.HexToAddress(wallet.Address())
+	sim := simulated.NewBackend(types.GenesisAlloc{owner: {Balance: big.NewInt(1e18)}})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	_, addr, err := gateway.DeployContract(ctx, ownedInitCode(owner), nil)
+	require.NoError(t, err)
+	sim.Commit()
+	contract, err := evm.NewBoundContract(addr.Hex(), ownedABI, gateway)
+	require.NoError(t, err)
+	bound := contract.(*evm.BoundContract)
+
+	nonce := uint64(1)
+	opts := &evm.TransactOpts{
+		Value:     big.NewInt(5),
+		GasLimit:  60000,
+		GasFeeCap: big.NewInt(5e9),
+		GasTipCap: big.NewInt(1e9),
+		Nonce:     &nonce,
+	}
+	tx, err := bound.BuildTransaction(opts, "poke", big.NewInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, addr.Hex(), *tx.To)
+	assert.Equal(t, big.NewInt(5), tx.Value)
+	assert.Equal(t, uint64(60000), tx.Gas)
+	assert.Equal(t, &nonce, tx.Nonce)
+
+	txHash, err := bound.TransactWithOpts(ctx, opts, "poke", big.NewInt(1))
+	require.NoError(t, err)
+	sim.Commit()
+	mined, _, err := sim.Client().TransactionByHash(ctx, common.HexToHash(txHash))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(60000), mined.Gas())
+	assert.Equal(t, uint64(1), mined.Nonce())
+	assert.Equal(t, big.NewInt(5e9), mined.GasFeeCap())
+	assert.Equal(t, big.NewInt(5), mined.Value())
+
+	// Plain Transact sends no value.
+	plain, err := bound.BuildTransaction(nil, "poke", big.NewInt(1))
+	require.NoError(t, err)
+	assert.Nil(t, plain.Value)
+	assert.Zero(t, plain.Gas)
+}
+
+// EOF: internal/blockchain/evm/estimate_test.go
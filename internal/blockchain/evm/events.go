@@ -0,0 +1,274 @@
+// Package evm decodes transaction receipt logs into ABI events.
+//
+// File: internal/blockchain/evm/events.go
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// DecodedEvent is a log decoded against an ABI event.
+type DecodedEvent struct {
+	// Name is the event name from the ABI, such as "Transfer", or its
+	// full signature if the ABI overloads the name.
+	Name string
+	// Args holds indexed and non‑indexed fields by name. Indexed fields of
+	// dynamic type (string, bytes, arrays, tuples) hold their keccak256
+	// hash as a common.Hash, since only the hash is logged.
+	Args map[string]interface{}
+	// LogIndex is the log's index within its block.
+	LogIndex uint
+	// Address is the contract that emitted the log.
+	Address string
+	// BlockNumber and TxHash locate the log on chain.
+	BlockNumber uint64
+	TxHash      string
+}
+
+// ParseLogs decodes the logs emitted by the bound contract. Logs from other
+// addresses and logs matching no event in the ABI, including anonymous
+// events, are skipped. A log from the contract that matches an event but
+// does not fit its fields is an error, as the ABI does not describe the
+// contract.
+func (c *BoundContract) ParseLogs(logs []*types.Log) ([]DecodedEvent, error) {
+	return decodeLogs(c.abi, logs, &c.address)
+}
+
+// FilterEvents returns the eventName events the contract emitted between
+// from and to, inclusive (empty = latest), decoded and sorted by block and
+// log index. An overloaded event is named by full signature, such as
+// "Transfer(address,address,uint256)". indexedFilters are matched against the event's inputs in ABI
+// order: nil is a wildcard, a []interface{} matches any of its values, and
+// any other value must equal the field. Only indexed fields can be
+// filtered. Addresses may be given as hex strings.
+//
+//	// Transfers to me: Transfer(address indexed from, address indexed to, uint256 value)
+//	events, err := token.FilterEvents(ctx, "Transfer", "19000000", "latest", nil, me)
+func (c *BoundContract) FilterEvents(ctx context.Context, eventName string, from, to blockchain.BlockNumber, indexedFilters ...interface{}) ([]DecodedEvent, error) {
+	event, err := lookupEvent(c.abi, eventName)
+	if err != nil {
+		return nil, err
+	}
+	if event.Anonymous {
+		return nil, fmt.Errorf("event %q is anonymous and cannot be filtered by name", eventName)
+	}
+	topics, err := eventTopics(event, indexedFilters)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := c.gateway.GetLogs(ctx, &LogFilter{
+		Addresses: []string{c.address.Hex()},
+		FromBlock: from,
+		ToBlock:   to,
+		Topics:    topics,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("filter %s events: %w", eventName, err)
+	}
+	ptrs := make([]*types.Log, len(logs))
+	for i := range logs {
+		ptrs[i] = &logs[i]
+	}
+	events, err := decodeLogs(c.abi, ptrs, &c.address)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+		return events[i].LogIndex < events[j].LogIndex
+	})
+	return events, nil
+}
+
+// eventTopics builds the topic filter for event: its ID, then one position
+// per indexed input.
+func eventTopics(event abi.Event, filters []interface{}) ([][]common.Hash, error) {
+	if len(filters) > len(event.Inputs) {
+		return nil, fmt.Errorf("event %s has %d fields, got %d filters", event.Name, len(event.Inputs), len(filters))
+	}
+	topics := [][]common.Hash{{event.ID}}
+	for i, input := range event.Inputs {
+		var filter interface{}
+		if i < len(filters) {
+			filter = filters[i]
+		}
+		if !input.Indexed {
+			if filter != nil {
+				return nil, fmt.Errorf("event %s field %q (position %d) is not indexed and cannot be filtered; pass nil", event.Name, input.Name, i)
+			}
+			continue
+		}
+		if filter == nil {
+			topics = append(topics, nil)
+			continue
+		}
+		values := []interface{}{filter}
+		if anyOf, ok := filter.([]interface{}); ok {
+			values = append([]interface{}(nil), anyOf...)
+		}
+		for j, v := range values {
+			if s, ok := v.(string); ok && input.Type.T == abi.AddressTy {
+				addr, err := parseAddress(s)
+				if err != nil {
+					return nil, fmt.Errorf("event %s field %q: %w", event.Name, input.Name, err)
+				}
+				values[j] = addr
+			}
+		}
+		row, err := abi.MakeTopics(values)
+		if err != nil {
+			return nil, fmt.Errorf("event %s field %q: %w", event.Name, input.Name, err)
+		}
+		topics = append(topics, row[0])
+	}
+	// Trailing wildcards are implied.
+	for len(topics) > 1 && topics[len(topics)-1] == nil {
+		topics = topics[:len(topics)-1]
+	}
+	return topics, nil
+}
+
+// DecodeReceiptLogs decodes receipt's logs against the events in abiJSON.
+// Each log's topic0 is matched against the ABI's event IDs; logs matching
+// none, anonymous events, and logs that match an ID but not the event's
+// indexed layout (an ERC‑721 Transfer against an ERC‑20 ABI, say) are
+// skipped, so receipts touching several contracts decode cleanly.
+func (g *EVMGateway) DecodeReceiptLogs(receipt *types.Receipt, abiJSON string) ([]DecodedEvent, error) {
+	if receipt == nil {
+		return nil, fmt.Errorf("DecodeReceiptLogs: nil receipt")
+	}
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("DecodeReceiptLogs: parse ABI: %w", err)
+	}
+	return decodeLogs(parsed, receipt.Logs, nil)
+}
+
+// WaitForTransaction waits until txHash is mined with the given number of
+// confirmations and returns its receipt. See Client.WaitForReceipt.
+func (g *EVMGateway) WaitForTransaction(ctx context.Context, txHash string, confirmations uint64) (*types.Receipt, error) {
+	if len(common.FromHex(txHash)) != common.HashLength {
+		return nil, fmt.Errorf("WaitForTransaction: invalid transaction hash: %s", txHash)
+	}
+	result, err := g.client.WaitForReceipt(ctx, common.HexToHash(txHash), confirmations)
+	if err != nil {
+		return nil, fmt.Errorf("WaitForTransaction: %w", err)
+	}
+	return result.Receipt, nil
+}
+
+// decodeLogs decodes logs against the events of parsed. With a non‑nil
+// address, logs from other addresses are skipped and a matching log that
+// fails to decode is an error; otherwise such logs are skipped too.
+func decodeLogs(parsed abi.ABI, logs []*types.Log, address *common.Address) ([]DecodedEvent, error) {
+	var events []DecodedEvent
+	for _, log := range logs {
+		if log == nil || len(log.Topics) == 0 {
+			continue
+		}
+		if address != nil && log.Address != *address {
+			continue
+		}
+		event, err := parsed.EventByID(log.Topics[0])
+		if err != nil || event.Anonymous {
+			continue
+		}
+		args, err := decodeLog(event, log)
+		if err != nil {
+			if address != nil {
+				return nil, fmt.Errorf("decode %s log %d: %w", event.Name, log.Index, err)
+			}
+			continue
+		}
+		name := event.RawName
+		if len(eventOverloads(parsed, name)) > 1 {
+			name = event.Sig
+		}
+		events = append(events, DecodedEvent{
+			Name:        name,
+			Args:        args,
+			LogIndex:    log.Index,
+			Address:     log.Address.Hex(),
+			BlockNumber: log.BlockNumber,
+			TxHash:      log.TxHash.Hex(),
+		})
+	}
+	return events, nil
+}
+
+// lookupEvent finds an event by full signature, such as
+// "Transfer(address,address,uint256)", by name if only one event has it,
+// or by go‑ethereum's key for overloads ("Transfer0").
+func lookupEvent(parsed abi.ABI, name string) (abi.Event, error) {
+	if strings.Contains(name, "(") {
+		sig := strings.ReplaceAll(name, " ", "")
+		for _, e := range parsed.Events {
+			if e.Sig == sig {
+				return e, nil
+			}
+		}
+		return abi.Event{}, fmt.Errorf("event %q not found in ABI", name)
+	}
+	switch candidates := eventOverloads(parsed, name); len(candidates) {
+	case 0:
+	case 1:
+		return candidates[0], nil
+	default:
+		sigs := make([]string, len(candidates))
+		for i, e := range candidates {
+			sigs[i] = e.Sig
+		}
+		return abi.Event{}, fmt.Errorf("event %q is overloaded; use its signature: %s", name, strings.Join(sigs, ", "))
+	}
+	if e, ok := parsed.Events[name]; ok {
+		return e, nil
+	}
+	return abi.Event{}, fmt.Errorf("event %q not found in ABI", name)
+}
+
+// eventOverloads returns the events named name, ordered by signature.
+func eventOverloads(parsed abi.ABI, name string) []abi.Event {
+	var found []abi.Event
+	for _, e := range parsed.Events {
+		if e.RawName == name {
+			found = append(found, e)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Sig < found[j].Sig })
+	return found
+}
+
+// decodeLog unpacks the data and indexed topics of log into a map.
+func decodeLog(event *abi.Event, log *types.Log) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(event.Inputs))
+	if err := event.Inputs.UnpackIntoMap(args, log.Data); err != nil {
+		return nil, fmt.Errorf("unpack data: %w", err)
+	}
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if len(log.Topics)-1 != len(indexed) {
+		return nil, fmt.Errorf("got %d indexed topics, want %d", len(log.Topics)-1, len(indexed))
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, log.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("parse topics: %w", err)
+	}
+	return args, nil
+}
+
+// EOF: internal/blockchain/evm/events.go
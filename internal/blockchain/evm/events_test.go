@@ -0,0 +1,205 @@
+// Package evm_test tests decoding receipt logs into ABI events.
+//
+// File: internal/blockchain/evm/events_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+const storedABI = `[
+	{"type":"event","name":"Stored","anonymous":false,"inputs":[
+		{"name":"from","type":"address","indexed":true},
+		{"name":"id","type":"uint256","indexed":true},
+		{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Note","anonymous":true,"inputs":[
+		{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+var storedTopic = crypto.Keccak256Hash([]byte("Stored(address,uint256,uint256)"))
+
+// storedInitCode deploys a contract that, on any call, emits
+// Stored(msg.sender, calldata[0:32], calldata[32:64]).
+var storedInitCode = common.FromHex("6031600c60003960316000f3" +
+	"602035600052600035337f" + storedTopic.Hex()[2:] + "60206000a300")
+
+func TestDecodeReceiptLogs_EmittedEvent(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	_, addr, err := gateway.DeployContract(ctx, storedInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	to := addr.Hex()
+	data := append(common.LeftPadBytes(big.NewInt(7).Bytes(), 32), common.LeftPadBytes(big.NewInt(1234).Bytes(), 32)...)
+	txHash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Data: data})
+	require.NoError(t, err)
+	sim.Commit()
+
+	receipt, err := gateway.WaitForTransaction(ctx, txHash, 0)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	events, err := gateway.DecodeReceiptLogs(receipt, storedABI)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	ev := events[0]
+	assert.Equal(t, "Stored", ev.Name)
+	assert.Equal(t, addr.Hex(), ev.Address)
+	assert.Equal(t, receipt.Logs[0].Index, ev.LogIndex)
+	assert.Equal(t, common.HexToAddress(wallet.Address()), ev.Args["from"])
+	assert.Equal(t, big.NewInt(7), ev.Args["id"])
+	assert.Equal(t, big.NewInt(1234), ev.Args["value"])
+
+	bound, err := evm.NewBoundContract(addr.Hex(), storedABI, gateway)
+	require.NoError(t, err)
+	parsed, err := bound.(*evm.BoundContract).ParseLogs(receipt.Logs)
+	require.NoError(t, err)
+	assert.Equal(t, events, parsed)
+
+	_, err = gateway.WaitForTransaction(ctx, "0x1234", 0)
+	assert.Error(t, err)
+}
+
+func TestDecodeReceiptLogs_SkipsForeignLogs(t *testing.T) {
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	from := common.HexToHash("0x01")
+	id := common.BigToHash(big.NewInt(9))
+	value := common.LeftPadBytes(big.NewInt(5).Bytes(), 32)
+	receipt := &types.Receipt{Logs: []*types.Log{
+		// Anonymous event: no topics to match.
+		{Address: contract, Data: value, Index: 0},
+		// Unknown event from another contract.
+		{Address: other, Topics: []common.Hash{crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))}, Data: value, Index: 1},
+		// Same topic0, different indexed layout (two topics, not three).
+		{Address: other, Topics: []common.Hash{storedTopic, from}, Data: value, Index: 2},
+		{Address: contract, Topics: []common.Hash{storedTopic, from, id}, Data: value, Index: 3},
+	}}
+
+	gateway := evm.NewEVMGatewayFromClient(nil, nil, nil)
+	events, err := gateway.DecodeReceiptLogs(receipt, storedABI)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, uint(3), events[0].LogIndex)
+	assert.Equal(t, big.NewInt(9), events[0].Args["id"])
+
+	_, err = gateway.DecodeReceiptLogs(receipt, "not json")
+	assert.Error(t, err)
+
+	// A bound contract rejects its own logs that do not fit the ABI.
+	bound, err := evm.NewBoundContract(other.Hex(), storedABI, gateway)
+	require.NoError(t, err)
+	_, err = bound.(*evm.BoundContract).ParseLogs(receipt.Logs)
+	assert.ErrorContains(t, err, "decode Stored log 2")
+
+	bound, err = evm.NewBoundContract(contract.Hex(), storedABI, gateway)
+	require.NoError(t, err)
+	parsed, err := bound.(*evm.BoundContract).ParseLogs(receipt.Logs)
+	require.NoError(t, err)
+	assert.Equal(t, events, parsed)
+}
+
+func TestBoundContract_FilterEvents(t *testing.T) {
+	alice, err := evm.NewKeystore(filepath.Join(t.TempDir(), "alice.key"), "test")
+	require.NoError(t, err)
+	bob, err := evm.NewKeystore(filepath.Join(t.TempDir(), "bob.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(alice.Address()): {Balance: big.NewInt(1e18)},
+		common.HexToAddress(bob.Address()):   {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	asAlice := newSimulatedGateway(t, sim, alice)
+	asBob := newSimulatedGateway(t, sim, bob)
+	ctx := context.Background()
+
+	_, addr, err := asAlice.DeployContract(ctx, storedInitCode, nil)
+	require.NoError(t, err)
+	_, otherAddr, err := asAlice.DeployContract(ctx, storedInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	store := func(gw *evm.EVMGateway, contract common.Address, id, value int64) {
+		to := contract.Hex()
+		data := append(common.LeftPadBytes(big.NewInt(id).Bytes(), 32), common.LeftPadBytes(big.NewInt(value).Bytes(), 32)...)
+		_, err := gw.SendTransaction(ctx, &blockchain.Transaction{To: &to, Data: data})
+		require.NoError(t, err)
+	}
+	store(asAlice, addr, 1, 10)
+	store(asBob, addr, 2, 20)
+	sim.Commit()
+	store(asAlice, addr, 3, 30)
+	store(asAlice, otherAddr, 4, 40) // another contract: never returned
+	sim.Commit()
+
+	bound, err := evm.NewBoundContract(addr.Hex(), storedABI, asAlice)
+	require.NoError(t, err)
+	contract := bound.(*evm.BoundContract)
+	ids := func(events []evm.DecodedEvent) []int64 {
+		var out []int64
+		for _, e := range events {
+			assert.Equal(t, "Stored", e.Name)
+			assert.Equal(t, addr.Hex(), e.Address)
+			out = append(out, e.Args["id"].(*big.Int).Int64())
+		}
+		return out
+	}
+
+	all, err := contract.FilterEvents(ctx, "Stored", blockchain.BlockNumberEarliest, blockchain.BlockNumberLatest)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ids(all))
+	assert.Equal(t, big.NewInt(20), all[1].Args["value"])
+	assert.Less(t, all[1].BlockNumber, all[2].BlockNumber)
+
+	byAlice, err := contract.FilterEvents(ctx, "Stored", "0", "latest", alice.Address())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, ids(byAlice))
+
+	byID, err := contract.FilterEvents(ctx, "Stored", "0", "latest", nil, big.NewInt(2))
+	require.NoError(t, err)
+	require.Equal(t, []int64{2}, ids(byID))
+	assert.Equal(t, common.HexToAddress(bob.Address()), byID[0].Args["from"])
+
+	anyOf, err := contract.FilterEvents(ctx, "Stored", "0", "latest", nil, []interface{}{big.NewInt(1), big.NewInt(2)})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids(anyOf))
+
+	latest, err := contract.FilterEvents(ctx, "Stored", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3}, ids(latest))
+
+	_, err = contract.FilterEvents(ctx, "Stored", "0", "latest", nil, nil, big.NewInt(10))
+	assert.ErrorContains(t, err, `field "value" (position 2) is not indexed`)
+	_, err = contract.FilterEvents(ctx, "Stored", "0", "latest", nil, nil, nil, nil)
+	assert.ErrorContains(t, err, "3 fields, got 4 filters")
+	_, err = contract.FilterEvents(ctx, "Stored", "0", "latest", "0xnot-an-address")
+	assert.Error(t, err)
+	_, err = contract.FilterEvents(ctx, "Missing", "0", "latest")
+	assert.ErrorContains(t, err, "not found in ABI")
+	_, err = contract.FilterEvents(ctx, "Note", "0", "latest")
+	assert.ErrorContains(t, err, "anonymous")
+}
+
+// EOF: internal/blockchain/evm/events_test.go
@@ -0,0 +1,212 @@
+// Package evm estimates EIP‑1559 fees from recent fee history.
+//
+// File: internal/blockchain/evm/fees.go
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// FeeSpeed selects how aggressively EstimateFees prices a transaction.
+type FeeSpeed string
+
+const (
+	// FeeSpeedSlow pays the 25th percentile tip and covers one block of
+	// base fee growth.
+	FeeSpeedSlow FeeSpeed = "slow"
+	// FeeSpeedStandard pays the median tip and covers three blocks of
+	// base fee growth. It is the default.
+	FeeSpeedStandard FeeSpeed = "standard"
+	// FeeSpeedFast pays the 75th percentile tip and covers six blocks of
+	// base fee growth.
+	FeeSpeedFast FeeSpeed = "fast"
+)
+
+// feeHistoryBlocks is the number of recent blocks sampled by FeeEstimator.
+const feeHistoryBlocks = 20
+
+// feePercentiles are the tip percentiles requested from eth_feeHistory,
+// indexed by speed (see feeSpeedIndex).
+var feePercentiles = []float64{25, 50, 75}
+
+// feeHeadroom is the fee cap multiplier over the next base fee, in per
+// mille, indexed by speed. The base fee can rise 12.5% per block, so n
+// blocks of headroom is 1.125^n.
+var feeHeadroom = []int64{1125, 1424, 2027}
+
+// maxFeeTrend caps the base fee trend multiplier, in per mille.
+const maxFeeTrend = 2000
+
+// feeSpeedIndex maps speed to its percentile and headroom index.
+func feeSpeedIndex(speed FeeSpeed) (int, error) {
+	switch speed {
+	case FeeSpeedSlow:
+		return 0, nil
+	case FeeSpeedStandard, "":
+		return 1, nil
+	case FeeSpeedFast:
+		return 2, nil
+	}
+	return 0, fmt.Errorf("EstimateFees: unknown fee speed %q", speed)
+}
+
+// feeSnapshot is the fee history summary shared by all speeds.
+type feeSnapshot struct {
+	baseFee *big.Int   // base fee of the next block
+	trend   int64      // next base fee over the window average, per mille
+	tips    []*big.Int // median tip per percentile; nil if no block had txs
+}
+
+// FeeEstimator prices EIP‑1559 transactions from eth_feeHistory: tips are
+// the median of each block's reward percentile over the recent window, and
+// the fee cap leaves headroom for base fee growth, scaled up while the base
+// fee is rising. The summary is cached for one block time (see
+// WithBlockTime). It is safe for concurrent use.
+type FeeEstimator struct {
+	client *Client
+
+	mu      sync.Mutex
+	snap    *feeSnapshot
+	fetched time.Time
+}
+
+// newFeeEstimator creates an estimator that queries client.
+func newFeeEstimator(client *Client) *FeeEstimator {
+	return &FeeEstimator{client: client}
+}
+
+// EstimateFees returns the tip cap and fee cap for speed ("" = standard).
+// Nodes without eth_feeHistory, or chains without a base fee, fail with
+// ErrNotSupported.
+func (e *FeeEstimator) EstimateFees(ctx context.Context, speed FeeSpeed) (*big.Int, *big.Int, error) {
+	idx, err := feeSpeedIndex(speed)
+	if err != nil {
+		return nil, nil, err
+	}
+	snap, err := e.snapshot(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tip *big.Int
+	if snap.tips != nil {
+		tip = new(big.Int).Set(snap.tips[idx])
+	} else {
+		// No recent transactions to sample.
+		tip, err = e.client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("EstimateFees: %w", err)
+		}
+	}
+
+	feeCap := new(big.Int).Mul(snap.baseFee, big.NewInt(feeHeadroom[idx]*snap.trend))
+	feeCap.Div(feeCap, big.NewInt(1000*1000))
+	feeCap.Add(feeCap, tip)
+	return tip, feeCap, nil
+}
+
+// snapshot returns the cached summary, refreshing it when a block time has
+// passed. Concurrent callers share one eth_feeHistory request.
+func (e *FeeEstimator) snapshot(ctx context.Context) (*feeSnapshot, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.client.clock.Now()
+	if e.snap != nil && now.Sub(e.fetched) < e.client.blockTime && !cacheBypassed(ctx) {
+		return e.snap, nil
+	}
+	history, err := e.client.FeeHistory(ctx, feeHistoryBlocks, nil, feePercentiles)
+	if err != nil {
+		return nil, fmt.Errorf("EstimateFees: %w", err)
+	}
+	snap, err := summarizeFeeHistory(history)
+	if err != nil {
+		return nil, err
+	}
+	e.snap, e.fetched = snap, now
+	return snap, nil
+}
+
+// invalidate drops the cached summary.
+func (e *FeeEstimator) invalidate() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.snap = nil
+}
+
+// summarizeFeeHistory reduces a fee history to a feeSnapshot.
+func summarizeFeeHistory(h *ethereum.FeeHistory) (*feeSnapshot, error) {
+	if len(h.BaseFee) < 2 || h.BaseFee[len(h.BaseFee)-1].Sign() == 0 {
+		return nil, fmt.Errorf("EstimateFees: no base fee: %w", ErrNotSupported)
+	}
+	next := h.BaseFee[len(h.BaseFee)-1]
+
+	// Trend: next base fee relative to the window average, clamped to
+	// [1, maxFeeTrend/1000] so falling fees never shrink the headroom.
+	sum := new(big.Int)
+	for _, fee := range h.BaseFee[:len(h.BaseFee)-1] {
+		sum.Add(sum, fee)
+	}
+	trend := int64(1000)
+	if sum.Sign() > 0 {
+		ratio := new(big.Int).Mul(next, big.NewInt(int64(1000*(len(h.BaseFee)-1))))
+		ratio.Div(ratio, sum)
+		if ratio.Cmp(big.NewInt(maxFeeTrend)) > 0 {
+			trend = maxFeeTrend
+		} else if r := ratio.Int64(); r > trend {
+			trend = r
+		}
+	}
+
+	// Tips: median per percentile over blocks that included transactions.
+	samples := make([][]*big.Int, len(feePercentiles))
+	for i, rewards := range h.Reward {
+		if i >= len(h.GasUsedRatio) || h.GasUsedRatio[i] == 0 || len(rewards) != len(feePercentiles) {
+			continue
+		}
+		for p, r := range rewards {
+			samples[p] = append(samples[p], r)
+		}
+	}
+	snap := &feeSnapshot{baseFee: next, trend: trend}
+	if len(samples[0]) > 0 {
+		snap.tips = make([]*big.Int, len(samples))
+		for p, s := range samples {
+			sort.Slice(s, func(i, j int) bool { return s[i].Cmp(s[j]) < 0 })
+			snap.tips[p] = s[len(s)/2]
+		}
+	}
+	return snap, nil
+}
+
+// FeeHistory returns base fees and reward percentiles for blockCount blocks
+// ending at lastBlock (nil = latest).
+func (c *Client) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	result, err := c.withRetry(ctx, "FeeHistory", func(ctx context.Context) (interface{}, error) {
+		return c.eth().FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*ethereum.FeeHistory), nil
+}
+
+// FeeEstimator returns the client's fee‑history based fee estimator.
+func (c *Client) FeeEstimator() *FeeEstimator {
+	return c.fees
+}
+
+// EstimateFees returns the tip cap and fee cap for speed (see FeeEstimator).
+func (c *Client) EstimateFees(ctx context.Context, speed FeeSpeed) (*big.Int, *big.Int, error) {
+	return c.fees.EstimateFees(ctx, speed)
+}
+
+// EOF: internal/blockchain/evm/fees.go
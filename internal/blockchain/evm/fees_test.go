@@ -0,0 +1,155 @@
+// Package evm_test tests fee‑history based fee estimation.
+//
+// File: internal/blockchain/evm/fees_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// gwei returns n gwei as a JSON‑RPC quantity.
+func gwei(n float64) *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(int64(math.Round(n * 1e9))))
+}
+
+// feeHistory builds an eth_feeHistory result with flat rewards of 1/2/3 gwei
+// (one outlier block at 5/6/7) and the given base fees; the last base fee is
+// the next block's.
+func feeHistory(baseFees ...float64) map[string]interface{} {
+	fees := make([]*hexutil.Big, len(baseFees))
+	for i, f := range baseFees {
+		fees[i] = gwei(f)
+	}
+	low := []*hexutil.Big{gwei(1), gwei(2), gwei(3)}
+	return map[string]interface{}{
+		"oldestBlock":   "0x64",
+		"baseFeePerGas": fees,
+		"gasUsedRatio":  []float64{0.5, 0.5, 0.5, 0}, // the empty block is ignored
+		"reward": [][]*hexutil.Big{
+			low, low, {gwei(5), gwei(6), gwei(7)}, {gwei(90), gwei(90), gwei(90)},
+		},
+	}
+}
+
+func newFeeClient(t testing.TB, results map[string]interface{}, opts ...evm.ClientOption) *evm.Client {
+	t.Helper()
+	srv := newFakeNode(t, results)
+	client, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{},
+		&evm.RetryConfig{MaxAttempts: 1}, time.Second, opts...)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClient_EstimateFees(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  map[string]interface{}
+		speed    evm.FeeSpeed
+		tip, cap *hexutil.Big
+	}{
+		{"slow", feeHistory(10, 10, 10, 10, 10), evm.FeeSpeedSlow, gwei(1), gwei(10*1.125 + 1)},
+		{"standard", feeHistory(10, 10, 10, 10, 10), evm.FeeSpeedStandard, gwei(2), gwei(10*1.424 + 2)},
+		{"default", feeHistory(10, 10, 10, 10, 10), "", gwei(2), gwei(10*1.424 + 2)},
+		{"fast", feeHistory(10, 10, 10, 10, 10), evm.FeeSpeedFast, gwei(3), gwei(10*2.027 + 3)},
+		{"rising base fee", feeHistory(10, 10, 10, 10, 15), evm.FeeSpeedStandard, gwei(2), gwei(15*1.424*1.5 + 2)},
+		{"falling base fee", feeHistory(20, 20, 20, 20, 10), evm.FeeSpeedStandard, gwei(2), gwei(10*1.424 + 2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFeeClient(t, map[string]interface{}{"eth_feeHistory": tt.history})
+			tip, feeCap, err := client.EstimateFees(context.Background(), tt.speed)
+			require.NoError(t, err)
+			assert.Equal(t, tt.tip.ToInt(), tip)
+			assert.Equal(t, tt.cap.ToInt(), feeCap)
+		})
+	}
+
+	t.Run("unknown speed", func(t *testing.T) {
+		client := newFeeClient(t, map[string]interface{}{"eth_feeHistory": feeHistory(10, 10)})
+		_, _, err := client.EstimateFees(context.Background(), "ludicrous")
+		assert.ErrorContains(t, err, "unknown fee speed")
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		client := newFeeClient(t, map[string]interface{}{})
+		_, _, err := client.EstimateFees(context.Background(), evm.FeeSpeedStandard)
+		assert.ErrorIs(t, err, evm.ErrNotSupported)
+	})
+}
+
+func TestClient_EstimateFees_CachedForBlockTime(t *testing.T) {
+	results := map[string]interface{}{"eth_feeHistory": feeHistory(10, 10, 10, 10, 10)}
+	client := newFeeClient(t, results, evm.WithBlockTime(12*time.Second))
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	client.SetClock(clock)
+	ctx := context.Background()
+
+	_, first, err := client.EstimateFees(ctx, evm.FeeSpeedStandard)
+	require.NoError(t, err)
+
+	results["eth_feeHistory"] = feeHistory(20, 20, 20, 20, 20)
+	clock.Advance(11 * time.Second)
+	_, cached, err := client.EstimateFees(ctx, evm.FeeSpeedStandard)
+	require.NoError(t, err)
+	assert.Equal(t, first, cached, "must reuse the estimate within one block time")
+
+	clock.Advance(time.Second)
+	_, fresh, err := client.EstimateFees(ctx, evm.FeeSpeedStandard)
+	require.NoError(t, err)
+	assert.Equal(t, gwei(20*1.424+2).ToInt(), fresh)
+}
+
+func TestTxBuilder_DynamicFeeEstimation(t *testing.T) {
+	header := cancunHeader() // base fee 10 gwei
+	base := map[string]interface{}{
+		"eth_chainId":              "0x1",
+		"eth_getBlockByNumber":     header,
+		"eth_getTransactionCount":  "0x7",
+		"eth_estimateGas":          "0x5208",
+		"eth_maxPriorityFeePerGas": "0x3b9aca00", // 1 gwei
+	}
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+
+	t.Run("fee history", func(t *testing.T) {
+		results := map[string]interface{}{"eth_feeHistory": feeHistory(10, 10, 10, 10, 10)}
+		for k, v := range base {
+			results[k] = v
+		}
+		builder, err := evm.NewTxBuilder(context.Background(), newFeeClient(t, results), wallet)
+		require.NoError(t, err)
+
+		tx, err := builder.BuildTransfer(context.Background(), to, big.NewInt(1),
+			&evm.TxOpts{DynamicFee: true, FeeSpeed: evm.FeeSpeedFast})
+		require.NoError(t, err)
+		assert.Equal(t, gwei(3).ToInt(), tx.GasTipCap())
+		assert.Equal(t, gwei(10*2.027+3).ToInt(), tx.GasFeeCap())
+	})
+
+	t.Run("fallback without eth_feeHistory", func(t *testing.T) {
+		builder, err := evm.NewTxBuilder(context.Background(), newFeeClient(t, base), wallet)
+		require.NoError(t, err)
+
+		tx, err := builder.BuildTransfer(context.Background(), to, big.NewInt(1), &evm.TxOpts{DynamicFee: true})
+		require.NoError(t, err)
+		assert.Equal(t, gwei(1).ToInt(), tx.GasTipCap())
+		assert.Equal(t, gwei(2*10+1).ToInt(), tx.GasFeeCap())
+	})
+}
+
+// EOF: internal/blockchain/evm/fees_test.go
@@ -0,0 +1,58 @@
+// Package evm reports the chain's current base fee and gas price suggestions.
+//
+// File: internal/blockchain/evm/gasinfo.go
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// GasInfo is a snapshot of current gas pricing. BaseFee and GasTipCap are
+// nil on chains without EIP‑1559, so callers can branch on BaseFee == nil.
+type GasInfo struct {
+	BlockNumber uint64   // latest block, which BaseFee is read from
+	BaseFee     *big.Int // base fee of the latest block
+	GasTipCap   *big.Int // suggested priority fee (eth_maxPriorityFeePerGas)
+	GasPrice    *big.Int // suggested legacy gas price (eth_gasPrice)
+}
+
+// BaseFee returns the base fee of the latest block, or nil on chains
+// without EIP‑1559.
+func (g *EVMGateway) BaseFee(ctx context.Context) (*big.Int, error) {
+	header, err := g.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("BaseFee: %w", err)
+	}
+	if header.BaseFee == nil {
+		return nil, nil
+	}
+	return new(big.Int).Set(header.BaseFee), nil
+}
+
+// GasInfo returns the latest base fee together with the node's suggested
+// tip and legacy gas price. The tip is only requested on EIP‑1559 chains.
+func (g *EVMGateway) GasInfo(ctx context.Context) (*GasInfo, error) {
+	header, err := g.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("GasInfo: header: %w", err)
+	}
+	info := &GasInfo{BlockNumber: header.Number.Uint64()}
+
+	info.GasPrice, err = g.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GasInfo: gas price: %w", err)
+	}
+	if header.BaseFee != nil {
+		info.BaseFee = new(big.Int).Set(header.BaseFee)
+		info.GasTipCap, err = g.client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("GasInfo: gas tip cap: %w", err)
+		}
+	}
+	return info, nil
+}
+
+// EOF: internal/blockchain/evm/gasinfo.go
@@ -0,0 +1,62 @@
+// Package evm_test tests base fee and gas price reporting.
+//
+// File: internal/blockchain/evm/gasinfo_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+func TestEVMGateway_GasInfo(t *testing.T) {
+	gateway := evm.NewEVMGatewayFromClient(newFeeClient(t, map[string]interface{}{
+		"eth_getBlockByNumber":     cancunHeader(), // block 100, base fee 10 gwei
+		"eth_gasPrice":             "0x2cb417800",  // 12 gwei
+		"eth_maxPriorityFeePerGas": "0x3b9aca00",   // 1 gwei
+	}), &observe.NoopLogger{}, nil)
+	ctx := context.Background()
+
+	baseFee, err := gateway.BaseFee(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10e9), baseFee)
+
+	info, err := gateway.GasInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &evm.GasInfo{
+		BlockNumber: 100,
+		BaseFee:     big.NewInt(10e9),
+		GasTipCap:   big.NewInt(1e9),
+		GasPrice:    big.NewInt(12e9),
+	}, info)
+}
+
+func TestEVMGateway_GasInfoLegacyChain(t *testing.T) {
+	// No eth_maxPriorityFeePerGas: it must not be requested.
+	gateway := evm.NewEVMGatewayFromClient(newFeeClient(t, map[string]interface{}{
+		"eth_getBlockByNumber": &types.Header{Number: big.NewInt(7), Difficulty: new(big.Int)},
+		"eth_gasPrice":         "0x3b9aca00",
+	}), &observe.NoopLogger{}, nil)
+	ctx := context.Background()
+
+	baseFee, err := gateway.BaseFee(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, baseFee)
+
+	info, err := gateway.GasInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), info.BlockNumber)
+	assert.Nil(t, info.BaseFee)
+	assert.Nil(t, info.GasTipCap)
+	assert.Equal(t, big.NewInt(1e9), info.GasPrice)
+}
+
+// EOF: internal/blockchain/evm/gasinfo_test.go
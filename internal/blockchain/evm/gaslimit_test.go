@@ -0,0 +1,80 @@
+// Package evm_test tests gas limit padding in TxBuilder.
+//
+// File: internal/blockchain/evm/gaslimit_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+func TestTxBuilder_GasLimitPadding(t *testing.T) {
+	results := map[string]interface{}{
+		"eth_chainId":             "0x1",
+		"eth_getBlockByNumber":    cancunHeader(),
+		"eth_getTransactionCount": "0x7",
+		"eth_estimateGas":         "0x186a0", // 100000
+		"eth_gasPrice":            "0x3b9aca00",
+	}
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+
+	paths := []struct {
+		name string
+		opts evm.TxOpts
+	}{
+		{"legacy", evm.TxOpts{}},
+		{"dynamic fee", evm.TxOpts{DynamicFee: true, GasFeeCap: big.NewInt(30e9), GasTipCap: big.NewInt(1e9)}},
+	}
+	tests := []struct {
+		name       string
+		clientOpts []evm.ClientOption
+		gasLimit   uint64
+		multiplier float64
+		maxGas     uint64
+		want       uint64
+	}{
+		{name: "default multiplier", want: 120000},
+		{name: "chain multiplier", clientOpts: []evm.ClientOption{evm.WithGasLimitMultiplier(1.5)}, want: 150000},
+		{name: "per-tx multiplier", clientOpts: []evm.ClientOption{evm.WithGasLimitMultiplier(1.5)}, multiplier: 1.1, want: 110000},
+		{name: "padding disabled", multiplier: 1, want: 100000},
+		{name: "chain cap", clientOpts: []evm.ClientOption{evm.WithMaxGasLimit(105000)}, want: 105000},
+		{name: "per-tx cap", maxGas: 101000, want: 101000},
+		{name: "cap below estimate", maxGas: 90000, want: 100000},
+		{name: "explicit limit untouched", clientOpts: []evm.ClientOption{evm.WithMaxGasLimit(30000)}, gasLimit: 50000, want: 50000},
+	}
+	for _, path := range paths {
+		for _, tt := range tests {
+			t.Run(path.name+"/"+tt.name, func(t *testing.T) {
+				builder, err := evm.NewTxBuilder(context.Background(), newFeeClient(t, results, tt.clientOpts...), wallet)
+				require.NoError(t, err)
+
+				opts := path.opts
+				opts.GasLimit = tt.gasLimit
+				opts.GasLimitMultiplier = tt.multiplier
+				opts.MaxGasLimit = tt.maxGas
+				tx, err := builder.BuildTransfer(context.Background(), to, big.NewInt(1), &opts)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, tx.Gas())
+			})
+		}
+	}
+
+	t.Run("multiplier below one", func(t *testing.T) {
+		builder, err := evm.NewTxBuilder(context.Background(), newFeeClient(t, results), wallet)
+		require.NoError(t, err)
+		_, err = builder.BuildTransfer(context.Background(), to, big.NewInt(1), &evm.TxOpts{GasLimitMultiplier: 0.5})
+		assert.ErrorContains(t, err, "invalid gas limit multiplier")
+	})
+}
+
+// EOF: internal/blockchain/evm/gaslimit_test.go
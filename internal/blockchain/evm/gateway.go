@@ -9,9 +9,14 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/0xSemantic/lola-os/internal/blockchain"
 	"github.com/0xSemantic/lola-os/internal/observe"
@@ -23,30 +28,98 @@ type EVMGateway struct {
 	client *Client
 	logger observe.Logger
 	wallet blockchain.Wallet // added for write operations
+
+	chainIDMu sync.Mutex
+	chainID   *big.Int // cached after the first successful lookup
+
+	tracker     *TxTracker // optional; watches broadcast transactions
+	health      healthChecker
+	abiResolver ABIResolver    // optional; fetches ABIs for binding by address
+	safeService string         // optional; Safe Transaction Service base URL
+	bundler     *BundlerConfig // optional; ERC‑4337 smart account
+
+	multicallMu sync.Mutex
+	multicalls  map[common.Address]bool // Multicall3 deployments seen
+
+	closeOnce sync.Once
 }
 
 // NewEVMGateway creates a new gateway for a specific RPC endpoint.
-// It establishes the connection immediately.
-func NewEVMGateway(ctx context.Context, rpcURL string, logger observe.Logger, retry *RetryConfig, wallet blockchain.Wallet) (*EVMGateway, error) {
-	client, err := NewClient(ctx, rpcURL, logger, retry)
+// It establishes the connection immediately. timeout is the per‑attempt
+// RPC deadline, normally ChainConfig.Timeout (0 = DefaultRPCTimeout).
+// opts are passed to the underlying Client. With WithExpectedChainID the
+// node's chain ID is checked once here and cached for later use.
+func NewEVMGateway(ctx context.Context, rpcURL string, logger observe.Logger, retry *RetryConfig, timeout time.Duration, wallet blockchain.Wallet, opts ...ClientOption) (*EVMGateway, error) {
+	client, err := NewClient(ctx, rpcURL, logger, retry, timeout, opts...)
 	if err != nil {
 		return nil, err
 	}
+	g := &EVMGateway{
+		client: client,
+		logger: logger,
+		wallet: wallet,
+	}
+	if err := g.verifyChainID(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return g, nil
+}
+
+// NewEVMGatewayFromClient creates a gateway around an existing client (for testing).
+func NewEVMGatewayFromClient(client *Client, logger observe.Logger, wallet blockchain.Wallet) *EVMGateway {
 	return &EVMGateway{
 		client: client,
 		logger: logger,
 		wallet: wallet,
-	}, nil
+	}
+}
+
+// Close stops the health checker and transaction tracker, if any, and
+// terminates the underlying RPC connection. It implements blockchain.Chain;
+// calls after the first do nothing.
+func (g *EVMGateway) Close() error {
+	g.closeOnce.Do(func() {
+		g.StopHealthCheck()
+		if g.tracker != nil {
+			g.tracker.Stop()
+		}
+		g.client.Close()
+	})
+	return nil
 }
 
-// Close terminates the underlying RPC connection.
-func (g *EVMGateway) Close() {
-	g.client.Close()
+// SetTracker attaches a TxTracker; every transaction the gateway
+// broadcasts from then on is tracked. Pass nil to detach it.
+func (g *EVMGateway) SetTracker(tracker *TxTracker) {
+	g.tracker = tracker
+}
+
+// Tracker returns the attached TxTracker, or nil.
+func (g *EVMGateway) Tracker() *TxTracker {
+	return g.tracker
+}
+
+// track hands a broadcast transaction to the tracker, if any. Tracking
+// failures are logged; the transaction has already been sent.
+func (g *EVMGateway) track(tx *types.Transaction) {
+	if g.tracker == nil {
+		return
+	}
+	if err := g.tracker.Track(tx); err != nil {
+		g.logger.Warn("could not track transaction", map[string]interface{}{
+			"tx_hash": tx.Hash().Hex(),
+			"error":   err.Error(),
+		})
+	}
 }
 
 // SetClient replaces the underlying client (for testing only).
 func (g *EVMGateway) SetClient(client *Client) {
 	g.client = client
+	g.chainIDMu.Lock()
+	g.chainID = nil
+	g.chainIDMu.Unlock()
 }
 
 // GetBalance returns the balance of the given address at the specified block.
@@ -57,24 +130,14 @@ func (g *EVMGateway) GetBalance(ctx context.Context, address string, block block
 		"block":   block,
 	})
 
-	if !common.IsHexAddress(address) {
-		return nil, fmt.Errorf("invalid address format: %s", address)
-	}
-	addr := common.HexToAddress(address)
-
-	var blockNum *big.Int
-	if block != "" {
-		switch block {
-		case blockchain.BlockNumberLatest, blockchain.BlockNumberPending, blockchain.BlockNumberEarliest:
-			blockNum = nil // ethclient interprets nil as latest/pending
-		default:
-			// Try to parse as decimal or hex.
-			blockNum = new(big.Int)
-			_, ok := blockNum.SetString(string(block), 0)
-			if !ok {
-				return nil, fmt.Errorf("invalid block number format: %s", block)
-			}
-		}
+	addr, err := g.resolveAddress(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("GetBalance: %w", err)
+	}
+
+	blockNum, err := parseBlockNumber(block)
+	if err != nil {
+		return nil, err
 	}
 
 	bal, err := g.client.BalanceAt(ctx, addr, blockNum)
@@ -84,6 +147,22 @@ func (g *EVMGateway) GetBalance(ctx context.Context, address string, block block
 	return bal, nil
 }
 
+// parseBlockNumber converts a block identifier into the form ethclient expects.
+// Named blocks and the empty string map to nil (latest); numbers may be
+// decimal or 0x‑prefixed hex.
+func parseBlockNumber(block blockchain.BlockNumber) (*big.Int, error) {
+	switch block {
+	case "", blockchain.BlockNumberLatest, blockchain.BlockNumberPending, blockchain.BlockNumberEarliest:
+		return nil, nil // ethclient interprets nil as latest/pending
+	}
+	// Try to parse as decimal or hex.
+	blockNum, ok := new(big.Int).SetString(string(block), 0)
+	if !ok || blockNum.Sign() < 0 {
+		return nil, fmt.Errorf("invalid block number format: %s", block)
+	}
+	return blockNum, nil
+}
+
 // SendTransaction is not implemented in read‑only mode.
 func (g *EVMGateway) SendTransaction(ctx context.Context, tx *blockchain.Transaction) (string, error) {
 	return "", errors.New("SendTransaction not implemented in read‑only EVM gateway")
@@ -96,12 +175,13 @@ func (g *EVMGateway) CallContract(ctx context.Context, call *blockchain.Contract
 		"value": call.Value,
 		"gas":   call.Gas,
 		"data":  common.Bytes2Hex(call.Data),
+		"block": call.Block,
 	})
 
-	if !common.IsHexAddress(call.To) {
-		return nil, fmt.Errorf("invalid contract address: %s", call.To)
+	to, err := g.resolveAddress(ctx, call.To)
+	if err != nil {
+		return nil, fmt.Errorf("CallContract: %w", err)
 	}
-	to := common.HexToAddress(call.To)
 
 	msg := ethereum.CallMsg{
 		To:    &to,
@@ -110,7 +190,12 @@ func (g *EVMGateway) CallContract(ctx context.Context, call *blockchain.Contract
 		Gas:   call.Gas,
 	}
 
-	data, err := g.client.CallContract(ctx, msg, nil) // always latest block for calls
+	blockNum, err := parseBlockNumber(call.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := g.client.CallContract(ctx, msg, blockNum)
 	if err != nil {
 		return nil, fmt.Errorf("CallContract: %w", err)
 	}
@@ -118,12 +203,72 @@ func (g *EVMGateway) CallContract(ctx context.Context, call *blockchain.Contract
 }
 
 // ChainID returns the chain ID of the connected network.
+// The node is queried once and checked against the configured chain ID;
+// later calls return the cached value.
 func (g *EVMGateway) ChainID(ctx context.Context) (*big.Int, error) {
-	id, err := g.client.ChainID(ctx)
+	g.chainIDMu.Lock()
+	defer g.chainIDMu.Unlock()
+	if g.chainID == nil {
+		id, err := g.client.ChainID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ChainID: %w", err)
+		}
+		if err := g.checkChainID(id); err != nil {
+			return nil, err
+		}
+		g.chainID = id
+	}
+	return new(big.Int).Set(g.chainID), nil
+}
+
+// txBuilder returns a TxBuilder signing as from ("" = the wallet's primary
+// account) that reuses the cached chain ID.
+func (g *EVMGateway) txBuilder(ctx context.Context, from string) (*TxBuilder, error) {
+	wallet, err := g.walletFor(from)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := g.signingChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newTxBuilder(g.client, wallet, chainID), nil
+}
+
+// walletFor returns the wallet signing as from: the gateway's wallet if
+// from is empty or its address, else the matching account of a
+// blockchain.MultiWallet.
+func (g *EVMGateway) walletFor(from string) (blockchain.Wallet, error) {
+	if g.wallet == nil {
+		return nil, errors.New("no wallet configured, read‑only mode")
+	}
+	if from == "" {
+		return g.wallet, nil
+	}
+	addr, err := parseAddress(from)
 	if err != nil {
-		return nil, fmt.Errorf("ChainID: %w", err)
+		return nil, fmt.Errorf("from address: %w", err)
+	}
+	if addr == common.HexToAddress(g.wallet.Address()) {
+		return g.wallet, nil
+	}
+	if multi, ok := g.wallet.(blockchain.MultiWallet); ok {
+		return multi.WalletFor(addr.Hex())
+	}
+	return nil, fmt.Errorf("%w %s", blockchain.ErrUnknownAccount, addr.Hex())
+}
+
+// senderAddress returns the account a transaction naming from is
+// estimated as sent from: from itself, else the wallet's primary account,
+// else the zero address.
+func (g *EVMGateway) senderAddress(from string) common.Address {
+	if from != "" {
+		return common.HexToAddress(from)
+	}
+	if g.wallet != nil {
+		return common.HexToAddress(g.wallet.Address())
 	}
-	return id, nil
+	return common.Address{}
 }
 
 // BlockNumber returns the number of the most recent block.
@@ -135,24 +280,55 @@ func (g *EVMGateway) BlockNumber(ctx context.Context) (uint64, error) {
 	return num, nil
 }
 
+// GetTransactionCount returns the nonce of address at the specified block.
+// "pending" includes transactions still in the mempool, while "latest" (or
+// "") counts only mined ones; a pending count that stays above the latest
+// one indicates stuck transactions.
+func (g *EVMGateway) GetTransactionCount(ctx context.Context, address string, block blockchain.BlockNumber) (uint64, error) {
+	addr, err := g.resolveAddress(ctx, address)
+	if err != nil {
+		return 0, fmt.Errorf("GetTransactionCount: %w", err)
+	}
+	var nonce uint64
+	if block == blockchain.BlockNumberPending {
+		nonce, err = g.client.PendingNonceAt(ctx, addr)
+	} else {
+		var blockNum *big.Int
+		if blockNum, err = parseBlockNumber(block); err != nil {
+			return 0, fmt.Errorf("GetTransactionCount: %w", err)
+		}
+		nonce, err = g.client.NonceAt(ctx, addr, blockNum)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("GetTransactionCount: %w", err)
+	}
+	return nonce, nil
+}
+
 // EstimateGas tries to estimate the gas needed for a transaction or call.
+// The call is estimated as sent from the wallet, if any, since the sender
+// can change the execution path (access checks, balances).
 func (g *EVMGateway) EstimateGas(ctx context.Context, call *blockchain.ContractCall) (uint64, error) {
 	g.logger.Debug("EstimateGas called", map[string]interface{}{
 		"to":    call.To,
 		"value": call.Value,
 		"data":  common.Bytes2Hex(call.Data),
+		"block": call.Block,
 	})
 
-	if !common.IsHexAddress(call.To) {
-		return 0, fmt.Errorf("invalid contract address: %s", call.To)
+	to, err := g.resolveAddress(ctx, call.To)
+	if err != nil {
+		return 0, fmt.Errorf("EstimateGas: %w", err)
 	}
-	to := common.HexToAddress(call.To)
 
 	msg := ethereum.CallMsg{
 		To:    &to,
 		Data:  call.Data,
 		Value: call.Value,
 	}
+	if g.wallet != nil {
+		msg.From = common.HexToAddress(g.wallet.Address())
+	}
 
 	gas, err := g.client.EstimateGas(ctx, msg)
 	if err != nil {
@@ -162,16 +338,66 @@ func (g *EVMGateway) EstimateGas(ctx context.Context, call *blockchain.ContractC
 }
 
 // SendTransaction implements blockchain.Chain.
-// It builds, signs, and broadcasts a transaction using the provided wallet.
+// It builds, signs, and broadcasts a transaction using the provided wallet,
+// signing as tx.From if set; a MultiWallet can sign as any of its accounts.
 // If the gateway does not have a wallet, an error is returned.
 func (g *EVMGateway) SendTransaction(ctx context.Context, tx *blockchain.Transaction) (string, error) {
 	if g.wallet == nil {
 		return "", errors.New("SendTransaction: no wallet configured, read‑only mode")
 	}
 
-	builder, err := NewTxBuilder(ctx, g.client, g.wallet)
+	signedTx, err := g.buildTransaction(ctx, tx)
+	if err != nil {
+		return "", fmt.Errorf("SendTransaction: %w", err)
+	}
+
+	// Broadcast.
+	err = g.client.SendTransaction(ctx, signedTx)
+	if err != nil {
+		return "", fmt.Errorf("SendTransaction: send: %w", err)
+	}
+	g.track(signedTx)
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// SignTransaction builds and signs a transaction without broadcasting it.
+// It returns the RLP‑encoded signed transaction as 0x‑hex and its hash.
+// When tx carries a nonce, gas limit and fees (GasPrice, or GasFeeCap and
+// GasTipCap) and the chain ID is configured, no RPC call is made, so it
+// works without a reachable node.
+func (g *EVMGateway) SignTransaction(ctx context.Context, tx *blockchain.Transaction) (string, string, error) {
+	if g.wallet == nil {
+		return "", "", errors.New("SignTransaction: no wallet configured, read‑only mode")
+	}
+
+	signedTx, err := g.buildTransaction(ctx, tx)
+	if err != nil {
+		return "", "", fmt.Errorf("SignTransaction: %w", err)
+	}
+	raw, err := signedTx.MarshalBinary()
 	if err != nil {
-		return "", fmt.Errorf("SendTransaction: create tx builder: %w", err)
+		return "", "", fmt.Errorf("SignTransaction: encode: %w", err)
+	}
+
+	g.logger.Info("transaction signed offline", map[string]interface{}{
+		"tx_hash": signedTx.Hash().Hex(),
+		"nonce":   signedTx.Nonce(),
+	})
+	return hexutil.Encode(raw), signedTx.Hash().Hex(), nil
+}
+
+// buildTransaction builds and signs tx with the gateway's wallet, as the
+// account tx.From names.
+func (g *EVMGateway) buildTransaction(ctx context.Context, tx *blockchain.Transaction) (*types.Transaction, error) {
+	builder, err := g.txBuilder(ctx, tx.From)
+	if err != nil {
+		return nil, fmt.Errorf("create tx builder: %w", err)
+	}
+
+	accessList, err := toAccessList(tx.AccessList)
+	if err != nil {
+		return nil, err
 	}
 
 	// Convert blockchain.Transaction to builder options.
@@ -182,6 +408,8 @@ func (g *EVMGateway) SendTransaction(ctx context.Context, tx *blockchain.Transac
 		GasTipCap:   tx.GasTipCap,
 		Nonce:       tx.Nonce,
 		DynamicFee:  tx.GasFeeCap != nil || tx.GasTipCap != nil,
+		AccessList:  accessList,
+		Simulate:    tx.Simulate,
 	}
 
 	var signedTx *types.Transaction
@@ -189,20 +417,18 @@ func (g *EVMGateway) SendTransaction(ctx context.Context, tx *blockchain.Transac
 		// Contract deployment.
 		signedTx, err = builder.BuildDeploy(ctx, tx.Data, opts)
 	} else {
-		// Transfer or contract call.
-		signedTx, err = builder.BuildContractCall(ctx, *tx.To, tx.Data, tx.Value, opts)
-	}
-	if err != nil {
-		return "", fmt.Errorf("SendTransaction: build tx: %w", err)
+		// Transfer or contract call; the recipient may be an ENS name.
+		var to common.Address
+		to, err = g.resolveAddress(ctx, *tx.To)
+		if err != nil {
+			return nil, err
+		}
+		signedTx, err = builder.BuildContractCall(ctx, to.Hex(), tx.Data, tx.Value, opts)
 	}
-
-	// Broadcast.
-	err = g.client.ec.SendTransaction(ctx, signedTx)
 	if err != nil {
-		return "", fmt.Errorf("SendTransaction: send: %w", err)
+		return nil, fmt.Errorf("build tx: %w", err)
 	}
-
-	return signedTx.Hash().Hex(), nil
+	return signedTx, nil
 }
 
 // DeployContract is a convenience method for contract deployment.
@@ -212,7 +438,7 @@ func (g *EVMGateway) DeployContract(ctx context.Context, data []byte, opts *TxOp
 		return "", common.Address{}, errors.New("DeployContract: no wallet configured, read‑only mode")
 	}
 
-	builder, err := NewTxBuilder(ctx, g.client, g.wallet)
+	builder, err := g.txBuilder(ctx, "")
 	if err != nil {
 		return "", common.Address{}, fmt.Errorf("DeployContract: create tx builder: %w", err)
 	}
@@ -222,14 +448,194 @@ func (g *EVMGateway) DeployContract(ctx context.Context, data []byte, opts *TxOp
 		return "", common.Address{}, fmt.Errorf("DeployContract: build tx: %w", err)
 	}
 
-	err = g.client.ec.SendTransaction(ctx, signedTx)
+	err = g.client.SendTransaction(ctx, signedTx)
 	if err != nil {
 		return "", common.Address{}, fmt.Errorf("DeployContract: send: %w", err)
 	}
+	g.track(signedTx)
+
+	return signedTx.Hash().Hex(), ComputeCreateAddress(builder.address, signedTx.Nonce()), nil
+}
+
+// CancelTransaction replaces a pending transaction with a zero‑value transfer
+// from its sender to itself. The replacement reuses the original nonce and
+// bumps its fees so the node's mempool accepts it in place of the original.
+// Returns the hash of the cancellation transaction.
+func (g *EVMGateway) CancelTransaction(ctx context.Context, txHash string) (string, error) {
+	builder, opts, err := g.cancellation(ctx, txHash)
+	if err != nil {
+		return "", fmt.Errorf("CancelTransaction: %w", err)
+	}
+
+	signedTx, err := builder.BuildTransfer(ctx, builder.address.Hex(), big.NewInt(0), opts)
+	if err != nil {
+		return "", fmt.Errorf("CancelTransaction: build tx: %w", err)
+	}
+
+	if err := g.client.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("CancelTransaction: send: %w", err)
+	}
+	g.track(signedTx)
+
+	g.logger.Info("cancellation transaction sent", map[string]interface{}{
+		"original":    txHash,
+		"replacement": signedTx.Hash().Hex(),
+		"nonce":       *opts.Nonce,
+	})
+	return signedTx.Hash().Hex(), nil
+}
+
+// CancellationTransaction implements blockchain.Canceller: it returns the
+// transfer CancelTransaction would send for txHash, unsigned, so that
+// policies can check it before it is.
+func (g *EVMGateway) CancellationTransaction(ctx context.Context, txHash string) (*blockchain.Transaction, error) {
+	builder, opts, err := g.cancellation(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("CancellationTransaction: %w", err)
+	}
+	sender := builder.address.Hex()
+	return &blockchain.Transaction{
+		From:      sender,
+		To:        &sender,
+		Value:     new(big.Int),
+		Gas:       opts.GasLimit,
+		GasPrice:  opts.GasPrice,
+		GasFeeCap: opts.GasFeeCap,
+		GasTipCap: opts.GasTipCap,
+		Nonce:     opts.Nonce,
+	}, nil
+}
+
+// cancellation returns the builder of the pending transaction txHash's
+// sender and the options of a zero‑value self‑transfer replacing it.
+func (g *EVMGateway) cancellation(ctx context.Context, txHash string) (*TxBuilder, *TxOpts, error) {
+	original, builder, err := g.pendingOwnTransaction(ctx, txHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts, err := g.replacementOpts(ctx, original)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts.GasLimit = params.TxGas
+	opts.AccessList = nil
+	return builder, opts, nil
+}
+
+// SpeedUpTransaction resends a pending transaction unchanged except for
+// bumped fees, so it replaces the original in the mempool and is mined
+// sooner. Blob transactions are not supported. Returns the new hash.
+func (g *EVMGateway) SpeedUpTransaction(ctx context.Context, txHash string) (string, error) {
+	original, builder, err := g.pendingOwnTransaction(ctx, txHash)
+	if err != nil {
+		return "", fmt.Errorf("SpeedUpTransaction: %w", err)
+	}
+	signedTx, err := g.speedUp(ctx, builder, original)
+	if err != nil {
+		return "", fmt.Errorf("SpeedUpTransaction: %w", err)
+	}
+
+	if err := g.client.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("SpeedUpTransaction: send: %w", err)
+	}
+	g.track(signedTx)
 
-	// Compute contract address from sender and nonce.
-	contractAddress := crypto.CreateAddress(builder.address, signedTx.Nonce())
-	return signedTx.Hash().Hex(), contractAddress, nil
+	g.logger.Info("speed‑up transaction sent", map[string]interface{}{
+		"original":    txHash,
+		"replacement": signedTx.Hash().Hex(),
+		"nonce":       signedTx.Nonce(),
+	})
+	return signedTx.Hash().Hex(), nil
+}
+
+// speedUp builds and signs a fee‑bumped copy of original.
+func (g *EVMGateway) speedUp(ctx context.Context, builder *TxBuilder, original *types.Transaction) (*types.Transaction, error) {
+	if original.Type() == types.BlobTxType {
+		return nil, fmt.Errorf("speed up blob transaction: %w", ErrNotSupported)
+	}
+	opts, err := g.replacementOpts(ctx, original)
+	if err != nil {
+		return nil, err
+	}
+	opts.GasLimit = original.Gas()
+
+	var signedTx *types.Transaction
+	if opts.DynamicFee {
+		signedTx, err = builder.buildAndSignDynamicFee(ctx, original.To(), original.Value(), original.Data(), opts, *opts.Nonce)
+	} else {
+		signedTx, err = builder.buildAndSignLegacy(ctx, original.To(), original.Value(), original.Data(), opts, *opts.Nonce)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("build tx: %w", err)
+	}
+	return signedTx, nil
+}
+
+// pendingOwnTransaction fetches txHash and checks that it is still pending
+// and was sent by one of the gateway wallet's accounts. It returns the transaction and a
+// builder for its replacement.
+func (g *EVMGateway) pendingOwnTransaction(ctx context.Context, txHash string) (*types.Transaction, *TxBuilder, error) {
+	if g.wallet == nil {
+		return nil, nil, errors.New("no wallet configured, read‑only mode")
+	}
+	if len(common.FromHex(txHash)) != common.HashLength {
+		return nil, nil, fmt.Errorf("invalid transaction hash: %s", txHash)
+	}
+
+	original, pending, err := g.client.TransactionByHash(ctx, common.HexToHash(txHash))
+	if err != nil {
+		return nil, nil, fmt.Errorf("get transaction: %w", err)
+	}
+	if !pending {
+		return nil, nil, fmt.Errorf("transaction %s is no longer pending", txHash)
+	}
+
+	chainID, err := g.signingChainID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), original)
+	if err != nil {
+		return nil, nil, fmt.Errorf("recover sender: %w", err)
+	}
+	builder, err := g.txBuilder(ctx, sender.Hex())
+	if errors.Is(err, blockchain.ErrUnknownAccount) {
+		return nil, nil, fmt.Errorf("transaction %s was not sent by wallet %s", txHash, g.wallet.Address())
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("create tx builder: %w", err)
+	}
+	return original, builder, nil
+}
+
+// replacementOpts returns options that reuse original's nonce, type and
+// access list with fees bumped enough for the mempool to accept a
+// replacement (see bumpFee).
+func (g *EVMGateway) replacementOpts(ctx context.Context, original *types.Transaction) (*TxOpts, error) {
+	nonce := original.Nonce()
+	opts := &TxOpts{
+		Nonce:      &nonce,
+		AccessList: original.AccessList(),
+	}
+	if original.Type() == types.LegacyTxType || original.Type() == types.AccessListTxType {
+		suggested, err := g.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("suggest gas price: %w", err)
+		}
+		opts.GasPrice = bumpFee(original.GasPrice(), suggested)
+	} else {
+		suggestedTip, err := g.client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("suggest gas tip cap: %w", err)
+		}
+		opts.DynamicFee = true
+		opts.GasTipCap = bumpFee(original.GasTipCap(), suggestedTip)
+		opts.GasFeeCap = bumpFee(original.GasFeeCap(), nil)
+		if opts.GasFeeCap.Cmp(opts.GasTipCap) < 0 {
+			opts.GasFeeCap = new(big.Int).Set(opts.GasTipCap)
+		}
+	}
+	return opts, nil
 }
 
 // SetWallet assigns a wallet to the gateway, enabling write operations.
@@ -242,4 +648,36 @@ func (g *EVMGateway) Wallet() blockchain.Wallet {
 	return g.wallet
 }
 
+// SetSafeTxService sets the base URL of the chain's Safe Transaction
+// Service, used to propose Safe transactions.
+func (g *EVMGateway) SetSafeTxService(url string) {
+	g.safeService = url
+}
+
+// SafeTxService returns the chain's Safe Transaction Service URL, or "".
+func (g *EVMGateway) SafeTxService() string {
+	return g.safeService
+}
+
+// BundlerConfig configures the ERC‑4337 smart account of a chain.
+type BundlerConfig struct {
+	// URL of the bundler's JSON‑RPC endpoint.
+	URL string `mapstructure:"url"`
+	// Account is the address of the smart account, owned by the wallet.
+	Account string `mapstructure:"account"`
+	// EntryPoint address (empty = the v0.6 EntryPoint).
+	EntryPoint string `mapstructure:"entry_point"`
+}
+
+// SetBundler sets the chain's bundler and smart account, used to send
+// user operations.
+func (g *EVMGateway) SetBundler(cfg *BundlerConfig) {
+	g.bundler = cfg
+}
+
+// Bundler returns the chain's bundler configuration, or nil.
+func (g *EVMGateway) Bundler() *BundlerConfig {
+	return g.bundler
+}
+
 // EOF: internal/blockchain/evm/gateway.go
\ No newline at end of file
@@ -0,0 +1,235 @@
+// Package evm provides a wallet implementing blockchain.Wallet whose key
+// never leaves Google Cloud KMS.
+//
+// File: internal/blockchain/evm/gcpkms.go
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/oauth2/google"
+)
+
+// DefaultGCPKMSEndpoint is the Cloud KMS REST endpoint.
+const DefaultGCPKMSEndpoint = "https://cloudkms.googleapis.com"
+
+// gcpKMSAlgorithm is the only Cloud KMS algorithm usable for Ethereum.
+const gcpKMSAlgorithm = "EC_SIGN_SECP256K1_SHA256"
+
+// gcpKMSScope is the OAuth scope of the Cloud KMS API.
+const gcpKMSScope = "https://www.googleapis.com/auth/cloudkms"
+
+// crc32c is the checksum Cloud KMS uses to detect corruption in transit.
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// GCPKMSWallet implements blockchain.Wallet with a Google Cloud KMS key
+// version of algorithm EC_SIGN_SECP256K1_SHA256, so the private key is never
+// held in memory or on disk. Every Sign is a KMS request. A GCPKMSWallet is
+// not modified after construction and is safe for concurrent use.
+type GCPKMSWallet struct {
+	httpClient *http.Client
+	endpoint   string
+	keyName    string
+	timeout    time.Duration
+	publicKey  []byte // uncompressed secp256k1 point
+	address    common.Address
+}
+
+// GCPKMSOption configures a GCPKMSWallet.
+type GCPKMSOption func(*GCPKMSWallet)
+
+// WithGCPKMSEndpoint overrides DefaultGCPKMSEndpoint, e.g. for a regional
+// endpoint or an emulator.
+func WithGCPKMSEndpoint(endpoint string) GCPKMSOption {
+	return func(w *GCPKMSWallet) {
+		w.endpoint = strings.TrimRight(endpoint, "/")
+	}
+}
+
+// WithGCPKMSHTTPClient sets the client for KMS requests, which must add
+// credentials itself. Without it, Application Default Credentials are used.
+func WithGCPKMSHTTPClient(client *http.Client) GCPKMSOption {
+	return func(w *GCPKMSWallet) {
+		w.httpClient = client
+	}
+}
+
+// WithGCPKMSTimeout bounds each KMS request (0 = DefaultKMSTimeout).
+func WithGCPKMSTimeout(timeout time.Duration) GCPKMSOption {
+	return func(w *GCPKMSWallet) {
+		if timeout > 0 {
+			w.timeout = timeout
+		}
+	}
+}
+
+// NewGCPKMSWallet creates a wallet for the key version keyName
+// (projects/…/locations/…/keyRings/…/cryptoKeys/…/cryptoKeyVersions/…).
+// It fetches the public key once to derive the address and fails if the key
+// is not a secp256k1 signing key.
+func NewGCPKMSWallet(ctx context.Context, keyName string, opts ...GCPKMSOption) (*GCPKMSWallet, error) {
+	if !strings.HasPrefix(keyName, "projects/") || !strings.Contains(keyName, "/cryptoKeyVersions/") {
+		return nil, fmt.Errorf("gcp kms wallet: %q is not a key version resource name", keyName)
+	}
+	w := &GCPKMSWallet{endpoint: DefaultGCPKMSEndpoint, keyName: keyName, timeout: DefaultKMSTimeout}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.httpClient == nil {
+		client, err := google.DefaultClient(ctx, gcpKMSScope)
+		if err != nil {
+			return nil, fmt.Errorf("gcp kms wallet: application default credentials: %w", err)
+		}
+		w.httpClient = client
+	}
+
+	var out struct {
+		PEM       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+		PEMCrc32c string `json:"pemCrc32c"`
+	}
+	if err := w.call(ctx, http.MethodGet, "/v1/"+keyName+"/publicKey", nil, &out); err != nil {
+		return nil, fmt.Errorf("gcp kms wallet: get public key: %w", err)
+	}
+	if out.Algorithm != gcpKMSAlgorithm {
+		return nil, fmt.Errorf("gcp kms wallet: key %s has algorithm %s, want %s", keyName, out.Algorithm, gcpKMSAlgorithm)
+	}
+	if out.PEMCrc32c != "" && out.PEMCrc32c != fmt.Sprint(crc32.Checksum([]byte(out.PEM), crc32c)) {
+		return nil, errors.New("gcp kms wallet: public key corrupted in transit")
+	}
+	block, _ := pem.Decode([]byte(out.PEM))
+	if block == nil {
+		return nil, errors.New("gcp kms wallet: invalid PEM public key")
+	}
+	publicKey, err := parseKMSPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("gcp kms wallet: %w", err)
+	}
+	w.publicKey = publicKey
+	w.address = common.BytesToAddress(crypto.Keccak256(publicKey[1:])[12:])
+	return w, nil
+}
+
+// Sign implements blockchain.Wallet.
+// It has KMS sign the 32‑byte digest and returns the signature as
+// [R || S || V] with V in {0,1} and S in the lower half of the curve order,
+// as crypto.Sign does. Errors wrap ErrKMSQuotaExceeded,
+// ErrKMSPermissionDenied or ErrKMSInvalidSignature where they apply.
+func (w *GCPKMSWallet) Sign(digest []byte) ([]byte, error) {
+	if len(digest) != 32 {
+		return nil, fmt.Errorf("gcp kms wallet: digest is %d bytes, want 32", len(digest))
+	}
+	digestCRC := fmt.Sprint(crc32.Checksum(digest, crc32c))
+	in := map[string]interface{}{
+		"digest":       map[string]string{"sha256": base64.StdEncoding.EncodeToString(digest)},
+		"digestCrc32c": digestCRC,
+	}
+	var out struct {
+		Signature            []byte `json:"signature"`
+		SignatureCrc32c      string `json:"signatureCrc32c"`
+		VerifiedDigestCrc32c bool   `json:"verifiedDigestCrc32c"`
+	}
+	if err := w.call(context.Background(), http.MethodPost, "/v1/"+w.keyName+":asymmetricSign", in, &out); err != nil {
+		return nil, fmt.Errorf("gcp kms wallet: sign: %w", err)
+	}
+	if !out.VerifiedDigestCrc32c {
+		return nil, errors.New("gcp kms wallet: sign: digest corrupted in transit")
+	}
+	if out.SignatureCrc32c != "" && out.SignatureCrc32c != fmt.Sprint(crc32.Checksum(out.Signature, crc32c)) {
+		return nil, fmt.Errorf("gcp kms wallet: %w: signature corrupted in transit", ErrKMSInvalidSignature)
+	}
+	sig, err := recoverKMSSignature(digest, out.Signature, w.publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("gcp kms wallet: %w", err)
+	}
+	return sig, nil
+}
+
+// Address implements blockchain.Wallet.
+func (w *GCPKMSWallet) Address() string {
+	return w.address.Hex()
+}
+
+// KeyName returns the key version the wallet signs with.
+func (w *GCPKMSWallet) KeyName() string {
+	return w.keyName
+}
+
+// call sends a JSON request to the KMS REST API and decodes the response
+// into out. API errors are classified by gcpKMSError.
+func (w *GCPKMSWallet) call(ctx context.Context, method, path string, in, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, w.endpoint+path, body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return gcpKMSError(resp.StatusCode, data)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// gcpKMSError builds an error from a Google API error response, wrapping
+// ErrKMSQuotaExceeded or ErrKMSPermissionDenied where they apply.
+func gcpKMSError(statusCode int, body []byte) error {
+	var apiErr struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+		} `json:"error"`
+	}
+	msg := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+		msg = apiErr.Error.Message
+	}
+	err := fmt.Errorf("http %d %s: %s", statusCode, apiErr.Error.Status, msg)
+	switch {
+	case statusCode == http.StatusTooManyRequests || apiErr.Error.Status == "RESOURCE_EXHAUSTED":
+		return fmt.Errorf("%w: %w", ErrKMSQuotaExceeded, err)
+	case statusCode == http.StatusForbidden || statusCode == http.StatusUnauthorized ||
+		apiErr.Error.Status == "PERMISSION_DENIED" || apiErr.Error.Status == "UNAUTHENTICATED":
+		return fmt.Errorf("%w: %w", ErrKMSPermissionDenied, err)
+	}
+	return err
+}
+
+// EOF: internal/blockchain/evm/gcpkms.go
@@ -0,0 +1,242 @@
+// Package evm_test tests the Google Cloud KMS wallet against a fake KMS
+// server.
+//
+// File: internal/blockchain/evm/gcpkms_test.go
+
+package evm_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+const testKeyName = "projects/p/locations/global/keyRings/r/cryptoKeys/agent/cryptoKeyVersions/1"
+
+// fakeCloudKMS serves the publicKey and asymmetricSign methods of the Cloud
+// KMS REST API with a local key.
+type fakeCloudKMS struct {
+	key *ecdsa.PrivateKey
+
+	mu         sync.Mutex
+	algorithm  string
+	signStatus int    // answers asymmetricSign with this error if set
+	sigOver    []byte // replaces the signature if set
+	signs      int
+}
+
+func newFakeCloudKMS(t *testing.T) (*fakeCloudKMS, *httptest.Server) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	f := &fakeCloudKMS{key: key, algorithm: "EC_SIGN_SECP256K1_SHA256"}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeCloudKMS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	table := crc32.MakeTable(crc32.Castagnoli)
+	f.mu.Lock()
+	algorithm := f.algorithm
+	f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/"+testKeyName+"/publicKey":
+		curve, _ := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 132, 0, 10})
+		point := crypto.FromECDSAPub(&f.key.PublicKey)
+		der, _ := asn1.Marshal(struct {
+			Algorithm pkix.AlgorithmIdentifier
+			PublicKey asn1.BitString
+		}{
+			Algorithm: pkix.AlgorithmIdentifier{
+				Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+				Parameters: asn1.RawValue{FullBytes: curve},
+			},
+			PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+		})
+		p := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"pem":       p,
+			"algorithm": algorithm,
+			"pemCrc32c": fmt.Sprint(crc32.Checksum([]byte(p), table)),
+		})
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/"+testKeyName+":asymmetricSign":
+		var in struct {
+			Digest struct {
+				SHA256 []byte `json:"sha256"`
+			} `json:"digest"`
+			DigestCrc32c string `json:"digestCrc32c"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil || len(in.Digest.SHA256) != 32 {
+			http.Error(w, `{"error":{"code":400,"message":"bad digest","status":"INVALID_ARGUMENT"}}`, http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		f.signs++
+		status, sigOver := f.signStatus, f.sigOver
+		f.mu.Unlock()
+		switch status {
+		case http.StatusTooManyRequests:
+			http.Error(w, `{"error":{"code":429,"message":"Quota exceeded","status":"RESOURCE_EXHAUSTED"}}`, status)
+			return
+		case http.StatusForbidden:
+			http.Error(w, `{"error":{"code":403,"message":"Permission 'cloudkms.cryptoKeyVersions.useToSign' denied","status":"PERMISSION_DENIED"}}`, status)
+			return
+		}
+
+		sig, _ := crypto.Sign(in.Digest.SHA256, f.key)
+		sigR := new(big.Int).SetBytes(sig[:32])
+		sigS := new(big.Int).SetBytes(sig[32:64])
+		if sig[0]&1 == 0 { // KMS returns either half of the curve order
+			sigS.Sub(crypto.S256().Params().N, sigS)
+		}
+		der, _ := asn1.Marshal(struct{ R, S *big.Int }{sigR, sigS})
+		if sigOver != nil {
+			der = sigOver
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":                 testKeyName,
+			"signature":            der,
+			"signatureCrc32c":      fmt.Sprint(crc32.Checksum(der, table)),
+			"verifiedDigestCrc32c": in.DigestCrc32c == fmt.Sprint(crc32.Checksum(in.Digest.SHA256, table)),
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// set changes the fake's behaviour while the server may be serving.
+func (f *fakeCloudKMS) set(change func(f *fakeCloudKMS)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	change(f)
+}
+
+func newTestGCPKMSWallet(t *testing.T, srv *httptest.Server) *evm.GCPKMSWallet {
+	t.Helper()
+	wallet, err := evm.NewGCPKMSWallet(context.Background(), testKeyName,
+		evm.WithGCPKMSEndpoint(srv.URL), evm.WithGCPKMSHTTPClient(srv.Client()))
+	require.NoError(t, err)
+	return wallet
+}
+
+func TestGCPKMSWallet_Sign(t *testing.T) {
+	fake, srv := newFakeCloudKMS(t)
+	wallet := newTestGCPKMSWallet(t, srv)
+	assert.Equal(t, crypto.PubkeyToAddress(fake.key.PublicKey).Hex(), wallet.Address())
+	assert.Equal(t, testKeyName, wallet.KeyName())
+
+	halfN := new(big.Int).Rsh(crypto.S256().Params().N, 1)
+	for i := 0; i < 16; i++ {
+		digest := crypto.Keccak256([]byte{byte(i)})
+		want, err := crypto.Sign(digest, fake.key)
+		require.NoError(t, err)
+
+		sig, err := wallet.Sign(digest)
+		require.NoError(t, err)
+		assert.Equal(t, want, sig)
+		assert.LessOrEqual(t, new(big.Int).SetBytes(sig[32:64]).Cmp(halfN), 0)
+	}
+}
+
+func TestGCPKMSWallet_ConcurrentSign(t *testing.T) {
+	fake, srv := newFakeCloudKMS(t)
+	wallet := newTestGCPKMSWallet(t, srv)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			digest := crypto.Keccak256([]byte{byte(i), 1})
+			sig, err := wallet.Sign(digest)
+			if err != nil {
+				errs <- err
+				return
+			}
+			pub, err := crypto.SigToPub(digest, sig)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if crypto.PubkeyToAddress(*pub).Hex() != wallet.Address() {
+				errs <- fmt.Errorf("signature %d recovers to another address", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	fake.set(func(f *fakeCloudKMS) { assert.Equal(t, 32, f.signs) })
+}
+
+func TestGCPKMSWallet_Errors(t *testing.T) {
+	ctx := context.Background()
+	fake, srv := newFakeCloudKMS(t)
+
+	_, err := evm.NewGCPKMSWallet(ctx, "projects/p/locations/global/keyRings/r/cryptoKeys/agent",
+		evm.WithGCPKMSHTTPClient(srv.Client()))
+	assert.ErrorContains(t, err, "not a key version")
+
+	fake.set(func(f *fakeCloudKMS) { f.algorithm = "EC_SIGN_P256_SHA256" })
+	_, err = evm.NewGCPKMSWallet(ctx, testKeyName,
+		evm.WithGCPKMSEndpoint(srv.URL), evm.WithGCPKMSHTTPClient(srv.Client()))
+	assert.ErrorContains(t, err, "EC_SIGN_SECP256K1_SHA256")
+	fake.set(func(f *fakeCloudKMS) { f.algorithm = "EC_SIGN_SECP256K1_SHA256" })
+
+	wallet := newTestGCPKMSWallet(t, srv)
+	digest := crypto.Keccak256([]byte("hello"))
+
+	_, err = wallet.Sign(digest[:31])
+	assert.ErrorContains(t, err, "want 32")
+
+	fake.set(func(f *fakeCloudKMS) { f.signStatus = http.StatusTooManyRequests })
+	_, err = wallet.Sign(digest)
+	assert.ErrorIs(t, err, evm.ErrKMSQuotaExceeded)
+	assert.NotErrorIs(t, err, evm.ErrKMSInvalidSignature)
+
+	fake.set(func(f *fakeCloudKMS) { f.signStatus = http.StatusForbidden })
+	_, err = wallet.Sign(digest)
+	assert.ErrorIs(t, err, evm.ErrKMSPermissionDenied)
+	assert.ErrorContains(t, err, "useToSign")
+	fake.set(func(f *fakeCloudKMS) { f.signStatus = 0 })
+
+	fake.set(func(f *fakeCloudKMS) { f.sigOver = []byte{0x30, 0x01} })
+	_, err = wallet.Sign(digest)
+	assert.ErrorIs(t, err, evm.ErrKMSInvalidSignature)
+	assert.NotErrorIs(t, err, evm.ErrKMSPermissionDenied)
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sig, err := crypto.Sign(digest, other)
+	require.NoError(t, err)
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{
+		new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]),
+	})
+	require.NoError(t, err)
+	fake.set(func(f *fakeCloudKMS) { f.sigOver = der })
+	_, err = wallet.Sign(digest)
+	assert.ErrorIs(t, err, evm.ErrKMSInvalidSignature)
+	assert.ErrorContains(t, err, "does not recover")
+}
+
+// EOF: internal/blockchain/evm/gcpkms_test.go
@@ -0,0 +1,200 @@
+// Package evm provides an HD wallet implementing blockchain.Wallet, which
+// derives its keys from a BIP‑39 mnemonic along a BIP‑32 derivation path.
+//
+// File: internal/blockchain/evm/hdwallet.go
+
+package evm
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultDerivationPath is the BIP‑44 Ethereum path used by MetaMask,
+// ethers and Foundry. {index} is replaced by the account index.
+const DefaultDerivationPath = "m/44'/60'/0'/0/{index}"
+
+// ErrInvalidMnemonic indicates a mnemonic with unknown words, a wrong word
+// count or a failed checksum.
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// HDWallet implements blockchain.Wallet with a key derived from a BIP‑39
+// mnemonic. It keeps the BIP‑32 master key, not the mnemonic or seed, so
+// that DeriveAccount can derive further accounts.
+type HDWallet struct {
+	master     *extendedKey
+	pathFormat string
+	path       string
+	index      uint32
+	address    common.Address
+	privateKey *ecdsa.PrivateKey
+}
+
+// NewHDWallet derives the account at index from mnemonic and the optional
+// BIP‑39 passphrase. path is a derivation path containing {index}, such
+// as DefaultDerivationPath ("" = DefaultDerivationPath). The mnemonic's
+// checksum is verified, and the seed is zeroed once the master key is
+// derived.
+func NewHDWallet(mnemonic, passphrase, path string, index uint32) (*HDWallet, error) {
+	if path == "" {
+		path = DefaultDerivationPath
+	}
+	if strings.Count(path, "{index}") != 1 {
+		return nil, fmt.Errorf("hdwallet: derivation path %q must contain {index} once", path)
+	}
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("hdwallet: %w: %v", ErrInvalidMnemonic, err)
+	}
+	master, err := newMasterKey(seed)
+	clear(seed)
+	if err != nil {
+		return nil, fmt.Errorf("hdwallet: %w", err)
+	}
+	wallet := &HDWallet{master: master, pathFormat: path}
+	return wallet.DeriveAccount(index)
+}
+
+// DeriveAccount returns the wallet's account at index, derived from the
+// same mnemonic and derivation path.
+func (w *HDWallet) DeriveAccount(index uint32) (*HDWallet, error) {
+	path := strings.Replace(w.pathFormat, "{index}", strconv.FormatUint(uint64(index), 10), 1)
+	parsed, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("hdwallet: %w", err)
+	}
+	key := w.master
+	for _, i := range parsed {
+		child, err := key.child(i)
+		if key != w.master {
+			key.zero()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("hdwallet: derive %s: %w", path, err)
+		}
+		key = child
+	}
+	privateKey, err := crypto.ToECDSA(key.key[:])
+	if key != w.master {
+		key.zero()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("hdwallet: derive %s: %w", path, err)
+	}
+	return &HDWallet{
+		master:     w.master,
+		pathFormat: w.pathFormat,
+		path:       path,
+		index:      index,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		privateKey: privateKey,
+	}, nil
+}
+
+// Sign implements blockchain.Wallet.
+// It signs the provided digest (32‑byte hash) using ECDSA.
+func (w *HDWallet) Sign(digest []byte) ([]byte, error) {
+	sig, err := crypto.Sign(digest, w.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("hdwallet: sign: %w", err)
+	}
+	return sig, nil
+}
+
+// Address implements blockchain.Wallet.
+func (w *HDWallet) Address() string {
+	return w.address.Hex()
+}
+
+// Path returns the derivation path of the account, such as
+// m/44'/60'/0'/0/3.
+func (w *HDWallet) Path() string {
+	return w.path
+}
+
+// Index returns the account index.
+func (w *HDWallet) Index() uint32 {
+	return w.index
+}
+
+// extendedKey is a BIP‑32 extended private key.
+type extendedKey struct {
+	key       [32]byte
+	chainCode [32]byte
+}
+
+// newMasterKey derives the BIP‑32 master key from a seed.
+func newMasterKey(seed []byte) (*extendedKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	defer clear(sum)
+	k := new(big.Int).SetBytes(sum[:32])
+	if k.Sign() == 0 || k.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, errors.New("seed yields an invalid master key")
+	}
+	master := &extendedKey{}
+	copy(master.key[:], sum[:32])
+	copy(master.chainCode[:], sum[32:])
+	return master, nil
+}
+
+// hardenedOffset is the first hardened child index (written i' in paths).
+const hardenedOffset = 0x80000000
+
+// child derives the child key at index i.
+func (k *extendedKey) child(i uint32) (*extendedKey, error) {
+	var data []byte
+	if i >= hardenedOffset {
+		data = append([]byte{0}, k.key[:]...)
+	} else {
+		priv, err := crypto.ToECDSA(k.key[:])
+		if err != nil {
+			return nil, err
+		}
+		data = crypto.CompressPubkey(&priv.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, i)
+	defer clear(data)
+
+	mac := hmac.New(sha512.New, k.chainCode[:])
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	defer clear(sum)
+
+	n := crypto.S256().Params().N
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("index %d yields an invalid key", i)
+	}
+	childKey := il.Add(il, new(big.Int).SetBytes(k.key[:]))
+	childKey.Mod(childKey, n)
+	if childKey.Sign() == 0 {
+		return nil, fmt.Errorf("index %d yields an invalid key", i)
+	}
+	child := &extendedKey{}
+	childKey.FillBytes(child.key[:])
+	copy(child.chainCode[:], sum[32:])
+	return child, nil
+}
+
+// zero overwrites the key material.
+func (k *extendedKey) zero() {
+	clear(k.key[:])
+	clear(k.chainCode[:])
+}
+
+// EOF: internal/blockchain/evm/hdwallet.go
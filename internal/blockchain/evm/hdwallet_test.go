@@ -0,0 +1,88 @@
+// Package evm_test tests HD wallet derivation.
+//
+// File: internal/blockchain/evm/hdwallet_test.go
+
+package evm_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// testMnemonic is the default mnemonic of Hardhat, Anvil and Ganache, whose
+// accounts MetaMask and ethers derive identically.
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestHDWallet_KnownVectors(t *testing.T) {
+	for _, tt := range []struct {
+		mnemonic string
+		index    uint32
+		want     string
+	}{
+		{testMnemonic, 0, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
+		{testMnemonic, 1, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+		{testMnemonic, 2, "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", 0,
+			"0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+	} {
+		w, err := evm.NewHDWallet(tt.mnemonic, "", "", tt.index)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, w.Address(), "index %d", tt.index)
+	}
+}
+
+func TestHDWallet_DeriveAccount(t *testing.T) {
+	w, err := evm.NewHDWallet("  "+testMnemonic+"\n", "", evm.DefaultDerivationPath, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "m/44'/60'/0'/0/0", w.Path())
+
+	second, err := w.DeriveAccount(1)
+	require.NoError(t, err)
+	assert.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", second.Address())
+	assert.Equal(t, uint32(1), second.Index())
+	assert.Equal(t, "m/44'/60'/0'/0/1", second.Path())
+	again, err := second.DeriveAccount(0)
+	require.NoError(t, err)
+	assert.Equal(t, w.Address(), again.Address(), "derivation does not consume the master key")
+
+	// Ledger Live puts the index in the account level.
+	ledger, err := evm.NewHDWallet(testMnemonic, "", "m/44'/60'/{index}'/0/0", 0)
+	require.NoError(t, err)
+	assert.Equal(t, w.Address(), ledger.Address())
+	ledger1, err := ledger.DeriveAccount(1)
+	require.NoError(t, err)
+	assert.NotEqual(t, second.Address(), ledger1.Address())
+
+	// A BIP‑39 passphrase selects a different wallet.
+	hidden, err := evm.NewHDWallet(testMnemonic, "secret", "", 0)
+	require.NoError(t, err)
+	assert.NotEqual(t, w.Address(), hidden.Address())
+
+	digest := crypto.Keccak256([]byte("hello"))
+	sig, err := second.Sign(digest)
+	require.NoError(t, err)
+	pub, err := crypto.SigToPub(digest, sig)
+	require.NoError(t, err)
+	assert.Equal(t, second.Address(), crypto.PubkeyToAddress(*pub).Hex())
+}
+
+func TestHDWallet_Invalid(t *testing.T) {
+	_, err := evm.NewHDWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", "", "", 0)
+	assert.ErrorIs(t, err, evm.ErrInvalidMnemonic, "bad checksum")
+	_, err = evm.NewHDWallet("test test test test test test test test test test test lola", "", "", 0)
+	assert.ErrorIs(t, err, evm.ErrInvalidMnemonic, "unknown word")
+	_, err = evm.NewHDWallet("", "", "", 0)
+	assert.ErrorIs(t, err, evm.ErrInvalidMnemonic)
+
+	_, err = evm.NewHDWallet(testMnemonic, "", "m/44'/60'/0'/0/0", 0)
+	assert.ErrorContains(t, err, "{index}")
+	_, err = evm.NewHDWallet(testMnemonic, "", "x/{index}", 0)
+	assert.Error(t, err)
+}
+
+// EOF: internal/blockchain/evm/hdwallet_test.go
@@ -0,0 +1,253 @@
+// Package evm probes RPC endpoint health and reconnects stuck clients.
+//
+// File: internal/blockchain/evm/health.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// MetricRPCReconnects counts reconnects made by the health checker. It
+// carries a "chain" label.
+const MetricRPCReconnects = "rpc_reconnects_total"
+
+// Health check defaults, used when HealthConfig fields are 0.
+const (
+	DefaultHealthInterval         = 30 * time.Second
+	DefaultHealthFailureThreshold = 3
+)
+
+// HealthState summarises the health of an RPC endpoint.
+type HealthState string
+
+const (
+	// HealthHealthy means the last probe succeeded.
+	HealthHealthy HealthState = "healthy"
+	// HealthDegraded means recent probes failed, but fewer than the
+	// failure threshold.
+	HealthDegraded HealthState = "degraded"
+	// HealthReconnecting means the threshold was reached and the endpoint
+	// is being (or could not yet be) re‑dialled.
+	HealthReconnecting HealthState = "reconnecting"
+)
+
+// HealthConfig configures the gateway's background health checker.
+type HealthConfig struct {
+	// Interval between probes (0 = DefaultHealthInterval).
+	Interval time.Duration `mapstructure:"interval"`
+	// FailureThreshold is the number of consecutive failed probes after
+	// which the endpoint is re‑dialled (0 = DefaultHealthFailureThreshold).
+	FailureThreshold int `mapstructure:"failure_threshold"`
+}
+
+// withDefaults fills in zero fields.
+func (cfg HealthConfig) withDefaults() HealthConfig {
+	if cfg.Interval == 0 {
+		cfg.Interval = DefaultHealthInterval
+	}
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = DefaultHealthFailureThreshold
+	}
+	return cfg
+}
+
+// HealthStatus is a snapshot of an endpoint's health.
+type HealthStatus struct {
+	State               HealthState
+	LastError           error     // error of the last failed probe or reconnect
+	LastSuccess         time.Time // time of the last successful probe
+	ConsecutiveFailures int
+	Reconnects          int // successful re‑dials since the gateway was created
+}
+
+// healthChecker holds the gateway's health state and background loop.
+type healthChecker struct {
+	mu     sync.Mutex
+	cfg    HealthConfig
+	status HealthStatus
+
+	runMu sync.Mutex
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// Ping checks that the endpoint answers with a single, uncached
+// eth_chainId request. It is not retried.
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.connect(ctx); err != nil {
+		return fmt.Errorf("Ping: %w", err)
+	}
+	if err := c.limiter.wait(ctx, c.clock, 1); err != nil {
+		return fmt.Errorf("Ping: %w", err)
+	}
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	if _, err := c.eth().ChainID(attemptCtx); err != nil {
+		return fmt.Errorf("Ping: %w", ClassifyError(err))
+	}
+	return nil
+}
+
+// Reconnect dials the endpoint again and swaps the new connection in. Calls
+// in flight on the old connection may fail when it is closed; retried calls
+// use the new one. On error the old connection stays in use.
+func (c *Client) Reconnect(ctx context.Context) error {
+	ec, err := ethclient.DialContext(ctx, c.rpcURL)
+	if err != nil {
+		return fmt.Errorf("Reconnect: dial %s: %w", c.rpcURL, err)
+	}
+	c.ecMu.Lock()
+	old := c.ec
+	c.ec = ec
+	c.ecMu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+// CheckHealth probes the endpoint once and updates Status. After
+// FailureThreshold consecutive failures the client is reconnected. The
+// returned error is the probe's (or the reconnect's) failure.
+func (g *EVMGateway) CheckHealth(ctx context.Context) error {
+	return g.checkHealth(ctx, true)
+}
+
+// checkHealth is CheckHealth; reconnect is false for the probe that follows
+// a reconnect, so a dead endpoint is re‑dialled at most once per check.
+func (g *EVMGateway) checkHealth(ctx context.Context, reconnect bool) error {
+	h := &g.health
+	err := g.client.Ping(ctx)
+	if err != nil && ctx.Err() != nil {
+		return err // the caller gave up; says nothing about the endpoint
+	}
+
+	h.mu.Lock()
+	cfg := h.cfg.withDefaults()
+	now := g.client.clock.Now()
+	if err == nil {
+		recovered := h.status.State != HealthHealthy && h.status.State != ""
+		h.status.State = HealthHealthy
+		h.status.LastError = nil
+		h.status.LastSuccess = now
+		h.status.ConsecutiveFailures = 0
+		h.mu.Unlock()
+		if recovered {
+			g.logger.Info("rpc endpoint healthy again", map[string]interface{}{"chain": g.client.chain})
+		}
+		return nil
+	}
+	h.status.LastError = err
+	h.status.ConsecutiveFailures++
+	if h.status.ConsecutiveFailures < cfg.FailureThreshold {
+		h.status.State = HealthDegraded
+		h.mu.Unlock()
+		return err
+	}
+	h.status.State = HealthReconnecting
+	failures := h.status.ConsecutiveFailures
+	h.mu.Unlock()
+	if !reconnect {
+		return err
+	}
+
+	g.logger.Warn("rpc endpoint unhealthy, reconnecting", map[string]interface{}{
+		"chain":    g.client.chain,
+		"failures": failures,
+		"error":    err.Error(),
+	})
+	if rerr := g.client.Reconnect(ctx); rerr != nil {
+		h.mu.Lock()
+		h.status.LastError = rerr
+		h.mu.Unlock()
+		return rerr
+	}
+	h.mu.Lock()
+	h.status.Reconnects++
+	h.mu.Unlock()
+	if g.client.metricsOn {
+		g.client.metrics.Counter(MetricRPCReconnects, 1, g.client.labels())
+	}
+
+	// Probe the new connection straight away.
+	return g.checkHealth(ctx, false)
+}
+
+// Status returns the endpoint's health as of the last probe. A gateway
+// that was never probed reports HealthHealthy with a zero LastSuccess.
+func (g *EVMGateway) Status() HealthStatus {
+	g.health.mu.Lock()
+	defer g.health.mu.Unlock()
+	status := g.health.status
+	if status.State == "" {
+		status.State = HealthHealthy
+	}
+	return status
+}
+
+// StartHealthCheck probes the endpoint every cfg.Interval in a background
+// goroutine (see CheckHealth). Close stops it; calling it again restarts
+// the loop with the new configuration.
+func (g *EVMGateway) StartHealthCheck(cfg HealthConfig) error {
+	if cfg.Interval < 0 || cfg.FailureThreshold < 0 {
+		return errors.New("StartHealthCheck: interval and failure_threshold must not be negative")
+	}
+	g.StopHealthCheck()
+
+	h := &g.health
+	h.mu.Lock()
+	h.cfg = cfg
+	h.mu.Unlock()
+
+	h.runMu.Lock()
+	defer h.runMu.Unlock()
+	h.stop, h.done = make(chan struct{}), make(chan struct{})
+	go g.runHealthCheck(cfg.withDefaults().Interval, h.stop, h.done)
+	return nil
+}
+
+// runHealthCheck is the StartHealthCheck loop.
+func (g *EVMGateway) runHealthCheck(interval time.Duration, stop, done chan struct{}) {
+	defer close(done)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-g.client.clock.After(interval):
+		}
+		_ = g.CheckHealth(ctx) // recorded in Status
+	}
+}
+
+// StopHealthCheck ends the StartHealthCheck loop and waits for a running
+// probe to finish. It is safe to call when no check is running.
+func (g *EVMGateway) StopHealthCheck() {
+	h := &g.health
+	h.runMu.Lock()
+	defer h.runMu.Unlock()
+	if h.stop == nil {
+		return
+	}
+	close(h.stop)
+	<-h.done
+	h.stop, h.done = nil, nil
+}
+
+// EOF: internal/blockchain/evm/health.go
@@ -0,0 +1,141 @@
+// Package evm_test tests RPC health checks and reconnects.
+//
+// File: internal/blockchain/evm/health_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// newFlakyNode serves eth_chainId and eth_blockNumber, failing every
+// request with HTTP 502 while down is set.
+func newFlakyNode(t *testing.T, down *atomic.Bool) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		result := "0x1"
+		if req.Method == "eth_blockNumber" {
+			result = "0x64"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newHealthGateway(t *testing.T, url string) (*evm.EVMGateway, *fakeClock) {
+	t.Helper()
+	client, err := evm.NewClient(context.Background(), url, &observe.NoopLogger{},
+		&evm.RetryConfig{MaxAttempts: 1}, time.Second)
+	require.NoError(t, err)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	client.SetClock(clock)
+	gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, nil)
+	t.Cleanup(func() { gateway.Close() })
+	return gateway, clock
+}
+
+func TestEVMGateway_CheckHealth(t *testing.T) {
+	var down atomic.Bool
+	srv := newFlakyNode(t, &down)
+	gateway, clock := newHealthGateway(t, srv.URL)
+	require.NoError(t, gateway.StartHealthCheck(evm.HealthConfig{Interval: time.Hour, FailureThreshold: 2}))
+	gateway.StopHealthCheck() // keep the config, probe by hand
+	ctx := context.Background()
+
+	assert.Equal(t, evm.HealthHealthy, gateway.Status().State, "never probed")
+	require.NoError(t, gateway.CheckHealth(ctx))
+	assert.Equal(t, clock.Now(), gateway.Status().LastSuccess)
+
+	down.Store(true)
+	assert.Error(t, gateway.CheckHealth(ctx))
+	status := gateway.Status()
+	assert.Equal(t, evm.HealthDegraded, status.State)
+	assert.Equal(t, 1, status.ConsecutiveFailures)
+	assert.ErrorContains(t, status.LastError, "502")
+
+	// The threshold triggers a reconnect; the endpoint is still down.
+	assert.Error(t, gateway.CheckHealth(ctx))
+	status = gateway.Status()
+	assert.Equal(t, evm.HealthReconnecting, status.State)
+	assert.Equal(t, 1, status.Reconnects)
+	assert.Equal(t, 3, status.ConsecutiveFailures, "the probe after the reconnect failed too")
+
+	down.Store(false)
+	clock.Advance(time.Minute)
+	require.NoError(t, gateway.CheckHealth(ctx))
+	status = gateway.Status()
+	assert.Equal(t, evm.HealthHealthy, status.State)
+	assert.Zero(t, status.ConsecutiveFailures)
+	assert.NoError(t, status.LastError)
+	assert.Equal(t, clock.Now(), status.LastSuccess)
+	assert.Equal(t, 1, status.Reconnects)
+}
+
+func TestEVMGateway_StartHealthCheckRejectsNegative(t *testing.T) {
+	var down atomic.Bool
+	gateway, _ := newHealthGateway(t, newFlakyNode(t, &down).URL)
+	assert.Error(t, gateway.StartHealthCheck(evm.HealthConfig{Interval: -time.Second}))
+}
+
+// TestClient_ReconnectDuringCalls swaps the connection while other
+// goroutines use it; run with -race.
+func TestClient_ReconnectDuringCalls(t *testing.T) {
+	var down atomic.Bool
+	srv := newFlakyNode(t, &down)
+	client, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{}, nil, time.Second)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	ctx := evm.ContextWithoutCache(context.Background())
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				_, err := client.BlockNumber(ctx)
+				assert.NoError(t, err)
+				_, err = client.ChainID(ctx)
+				assert.NoError(t, err)
+				assert.NoError(t, client.Ping(ctx))
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		require.NoError(t, client.Reconnect(context.Background()))
+	}
+	close(stop)
+	wg.Wait()
+}
+
+// EOF: internal/blockchain/evm/health_test.go
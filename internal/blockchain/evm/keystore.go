@@ -1,37 +1,199 @@
 // Package evm provides an encrypted keystore implementing blockchain.Wallet.
-// It uses AES-256-GCM for encryption and scrypt for key derivation.
+// Keys are stored in the Web3 Secret Storage (keystore V3) format of geth;
+// files in this package's earlier AES-256-GCM format are still read. A
+// keystore directory holds several keys, one file each, and implements
+// blockchain.MultiWallet.
 //
 // File: internal/blockchain/evm/keystore.go
 
 package evm
 
 import (
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/ecdsa"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
+	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/scrypt"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/observe"
 )
 
 // Keystore implements blockchain.Wallet using an encrypted file on disk.
-// The private key is encrypted with AES-256-GCM; the encryption key is derived
-// from a passphrase using scrypt (N=32768, r=8, p=1).
+// The encryption key is derived from a passphrase using scrypt (N=32768,
+// r=8, p=1).
+//
+// A Keystore opened with NewKeystoreDir also implements
+// blockchain.MultiWallet; it signs as its primary account, the first key
+// file by name, and WalletFor returns a Keystore for each other account.
+//
+// A Keystore implements blockchain.LockableWallet: Lock wipes the decrypted
+// keys (and the passphrase of a directory) from memory, and with
+// WithAutoLock that happens after an idle period. A locked Keystore fails
+// with blockchain.ErrWalletLocked until Unlock decrypts the files again.
 type Keystore struct {
 	address    common.Address
-	privateKey *ecdsa.PrivateKey
+	privateKey *ecdsa.PrivateKey // nil while locked
 	keyFile    string
+	format     KeystoreFormat
+	guard      *keyGuard       // shared by the accounts of a directory
+	kdf        KeystoreOptions // for key files written later
+
+	// Set for a keystore directory only.
+	dir        string
+	passphrase string
+	mu         sync.RWMutex
+	accounts   []*Keystore // primary first
+}
+
+// KeystoreFormat is the file format of a keystore.
+type KeystoreFormat string
+
+const (
+	// KeystoreFormatV3 is Web3 Secret Storage version 3, the format of
+	// geth's UTC--… files, also read by Foundry and most wallets.
+	KeystoreFormatV3 KeystoreFormat = "v3"
+	// KeystoreFormatLegacy is the AES-256-GCM format earlier versions of
+	// this package wrote. Only other LOLA OS installations can read it.
+	KeystoreFormatLegacy KeystoreFormat = "legacy"
+)
+
+// keystoreScryptN and keystoreScryptP are the default scrypt parameters
+// of new keys in either format. Below weakScryptN a warning is logged.
+const (
+	keystoreScryptN = 32768
+	keystoreScryptP = 1
+	weakScryptN     = 1024
+)
+
+// KeystoreOptions sets the key derivation of key files a keystore writes.
+// Files are always loaded with the parameters stored in them, so keys
+// written with different parameters can be mixed. The zero value uses the
+// production defaults (N=32768, p=1).
+type KeystoreOptions struct {
+	// ScryptN is the scrypt CPU/memory cost, a power of two (0 = 32768).
+	ScryptN int
+	// ScryptP is the scrypt parallelization (0 = 1).
+	ScryptP int
+	// Logger receives a warning when a key file is written with N below
+	// 1024, which offers little protection (nil = no log).
+	Logger observe.Logger
+}
+
+// LightKDF derives keys about eight times faster than the default, for
+// tests and development. Do not use it for keys that hold real funds.
+var LightKDF = KeystoreOptions{ScryptN: 4096, ScryptP: 1}
+
+// scryptParams returns the scrypt N and p of o, with defaults applied.
+func (o KeystoreOptions) scryptParams() (n, p int, err error) {
+	n, p = o.ScryptN, o.ScryptP
+	if n == 0 {
+		n = keystoreScryptN
+	}
+	if p == 0 {
+		p = keystoreScryptP
+	}
+	if n < 2 || n&(n-1) != 0 {
+		return 0, 0, fmt.Errorf("keystore: scrypt N %d is not a power of two", n)
+	}
+	if p < 0 || uint64(p)*8*128 > 1<<30 {
+		return 0, 0, fmt.Errorf("keystore: scrypt p %d out of range", p)
+	}
+	return n, p, nil
+}
+
+// ErrEmptyPassphrase is returned when a key would be encrypted with an
+// empty passphrase without WithEmptyPassphrase.
+var ErrEmptyPassphrase = errors.New("empty passphrase")
+
+var (
+	// ErrWrongPassphrase is returned when a key file does not open with
+	// the passphrase given.
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+	// ErrKeystoreCorrupted is returned when a key file is unreadable or its
+	// ciphertext fails the MAC check, e.g. after truncation or a bit flip.
+	ErrKeystoreCorrupted = errors.New("keystore file corrupted")
+	// ErrAddressMismatch is returned when the address stored in a key file
+	// is not the address of the key it holds.
+	ErrAddressMismatch = errors.New("keystore address does not match key")
+)
+
+// KeystoreOption configures NewKeystore and the other keystore functions
+// that write key files.
+type KeystoreOption func(*keystoreOptions)
+
+type keystoreOptions struct {
+	format     KeystoreFormat
+	allowEmpty bool
+	audit      *observe.AuditLogger
+	autoLock   time.Duration
+	kdf        *KeystoreOptions
+}
+
+// kdfOr returns the key derivation set by WithKeystoreOptions, or def.
+func (o keystoreOptions) kdfOr(def KeystoreOptions) KeystoreOptions {
+	if o.kdf != nil {
+		return *o.kdf
+	}
+	return def
 }
 
-// keystoreJSON represents the on‑disk encrypted format.
+// WithKeystoreFormat sets the format NewKeystore writes a new key in
+// (default KeystoreFormatV3). Existing files are loaded in whichever
+// format they are in.
+func WithKeystoreFormat(format KeystoreFormat) KeystoreOption {
+	return func(o *keystoreOptions) { o.format = format }
+}
+
+// WithEmptyPassphrase lets ChangePassphrase and ExportTo encrypt a key
+// with an empty passphrase, which they otherwise refuse.
+func WithEmptyPassphrase() KeystoreOption {
+	return func(o *keystoreOptions) { o.allowEmpty = true }
+}
+
+// WithAuditLog records ChangePassphrase and ExportTo in audit: the
+// account, the file written and the time, never key material.
+func WithAuditLog(audit *observe.AuditLogger) KeystoreOption {
+	return func(o *keystoreOptions) { o.audit = audit }
+}
+
+// WithAutoLock makes NewKeystore and NewKeystoreDir lock the keystore once
+// no key has been used for idle (0 = never).
+func WithAutoLock(idle time.Duration) KeystoreOption {
+	return func(o *keystoreOptions) { o.autoLock = idle }
+}
+
+// WithKeystoreOptions sets the key derivation of the key files written,
+// including by NewAccount and ChangePassphrase later on (see
+// KeystoreOptions).
+func WithKeystoreOptions(kdf KeystoreOptions) KeystoreOption {
+	return func(o *keystoreOptions) { o.kdf = &kdf }
+}
+
+// keystoreJSON represents the legacy on‑disk encrypted format. MAC is
+// keccak256(dk[16:32] || ciphertext) as in keystore V3, and KeyCheck is
+// keccak256(dk[16:32]), which tells a wrong passphrase from a damaged
+// ciphertext. Files written before both were added have neither; they are
+// rewritten with them on the next successful load.
 type keystoreJSON struct {
 	Address string `json:"address"`
 	Crypto  struct {
@@ -39,6 +201,8 @@ type keystoreJSON struct {
 		CipherParams struct {
 			IV string `json:"iv"`
 		} `json:"cipherparams"`
+		MAC       string `json:"mac,omitempty"`
+		KeyCheck  string `json:"keycheck,omitempty"`
 		KDF       string `json:"kdf"`
 		KDFParams struct {
 			N     int    `json:"n"`
@@ -51,66 +215,243 @@ type keystoreJSON struct {
 }
 
 // NewKeystore creates or loads an encrypted keystore.
-// If the key file exists, it is decrypted and the wallet is initialized.
-// If it does not exist, a new private key is generated, encrypted, and saved.
-func NewKeystore(keyFile, passphrase string) (*Keystore, error) {
+// If the key file exists, it is decrypted and the wallet is initialized;
+// its format is detected from the file. If it does not exist, a new
+// private key is generated, encrypted, and saved in the format chosen by
+// WithKeystoreFormat.
+func NewKeystore(keyFile, passphrase string, opts ...KeystoreOption) (*Keystore, error) {
+	o := keystoreOptions{format: KeystoreFormatV3}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.format != KeystoreFormatV3 && o.format != KeystoreFormatLegacy {
+		return nil, fmt.Errorf("keystore: unknown format %q", o.format)
+	}
+
 	// Check if file exists.
-	if _, err := os.Stat(keyFile); err == nil {
+	var k *Keystore
+	var err error
+	if _, err = os.Stat(keyFile); err == nil {
 		// Load existing.
-		return loadKeystore(keyFile, passphrase)
-	} else if !os.IsNotExist(err) {
+		k, err = loadKeystore(keyFile, passphrase)
+	} else if os.IsNotExist(err) {
+		k, err = generateKeystore(keyFile, passphrase, o.format, o.kdfOr(KeystoreOptions{}))
+	} else {
 		return nil, fmt.Errorf("keystore: stat file: %w", err)
 	}
+	if err != nil {
+		return nil, err
+	}
+	k.guard = newKeyGuard(k, o.autoLock)
+	k.kdf = o.kdfOr(KeystoreOptions{})
+	return k, nil
+}
 
-	// Generate new private key.
+// NewKeystoreWithOptions is NewKeystore writing a new key with the key
+// derivation in kdf, such as LightKDF in tests.
+func NewKeystoreWithOptions(keyFile, passphrase string, kdf KeystoreOptions, opts ...KeystoreOption) (*Keystore, error) {
+	return NewKeystore(keyFile, passphrase, append(opts, WithKeystoreOptions(kdf))...)
+}
+
+// generateKeystore generates a private key and saves it to keyFile,
+// encrypted with passphrase.
+func generateKeystore(keyFile, passphrase string, format KeystoreFormat, kdf KeystoreOptions) (*Keystore, error) {
 	privateKey, err := crypto.GenerateKey()
 	if err != nil {
 		return nil, fmt.Errorf("keystore: generate key: %w", err)
 	}
-	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	return saveKey(keyFile, passphrase, privateKey, format, kdf)
+}
 
-	// Encrypt and save.
-	if err := saveKeystore(keyFile, passphrase, privateKey, address); err != nil {
+// saveKey encrypts privateKey with the key derivation kdf and saves it to
+// keyFile in format.
+func saveKey(keyFile, passphrase string, privateKey *ecdsa.PrivateKey, format KeystoreFormat, kdf KeystoreOptions) (*Keystore, error) {
+	n, p, err := kdf.scryptParams()
+	if err != nil {
+		return nil, err
+	}
+	if n < weakScryptN && kdf.Logger != nil {
+		kdf.Logger.Warn("keystore written with weak scrypt parameters",
+			map[string]interface{}{"path": keyFile, "n": n, "p": p})
+	}
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	if format == KeystoreFormatV3 {
+		err = saveKeystoreV3(keyFile, passphrase, privateKey, n, p)
+	} else {
+		err = saveKeystore(keyFile, passphrase, privateKey, address, n, p)
+	}
+	if err != nil {
 		return nil, err
 	}
-
 	return &Keystore{
 		address:    address,
 		privateKey: privateKey,
 		keyFile:    keyFile,
+		format:     format,
+		kdf:        kdf,
 	}, nil
 }
 
+// ImportPrivateKey encrypts an existing private key, given as hex with or
+// without 0x, into keyFile in the format chosen by WithKeystoreFormat, so
+// a funded account can be used as a keystore. An existing keyFile is only
+// overwritten if force is set; otherwise the error wraps fs.ErrExist.
+func ImportPrivateKey(keyFile, passphrase, hexKey string, force bool, opts ...KeystoreOption) (*Keystore, error) {
+	o := keystoreOptions{format: KeystoreFormatV3}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.format != KeystoreFormatV3 && o.format != KeystoreFormatLegacy {
+		return nil, fmt.Errorf("keystore: unknown format %q", o.format)
+	}
+	if !force {
+		if _, err := os.Stat(keyFile); err == nil {
+			return nil, fmt.Errorf("keystore: %s: %w", keyFile, fs.ErrExist)
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("keystore: stat file: %w", err)
+		}
+	}
+
+	privateKey, err := parsePrivateKeyHex(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: %w", err)
+	}
+	k, err := saveKey(keyFile, passphrase, privateKey, o.format, o.kdfOr(KeystoreOptions{}))
+	if err != nil {
+		return nil, err
+	}
+	k.guard = newKeyGuard(k, o.autoLock)
+	return k, nil
+}
+
+// parsePrivateKeyHex parses a secp256k1 private key given as hex with or
+// without 0x. Its errors never quote the key.
+func parsePrivateKeyHex(hexKey string) (*ecdsa.PrivateKey, error) {
+	keyBytes, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"), "0X"))
+	if err != nil {
+		// The decoding error would quote part of the key.
+		return nil, errors.New("private key is not valid hex")
+	}
+	defer clear(keyBytes)
+	if len(keyBytes) != 32 {
+		return nil, fmt.Errorf("private key is %d bytes, want 32", len(keyBytes))
+	}
+	privateKey, err := crypto.ToECDSA(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	return privateKey, nil
+}
+
+// NewKeystoreDir opens a keystore directory, creating it if needed. Every
+// file in it not starting with a dot must be a key encrypted with
+// passphrase, in either format. If the directory holds no keys, one is
+// generated. New keys are written in the format chosen by
+// WithKeystoreFormat, named like geth's (UTC--<time>--<address>), so the
+// first file by name is the oldest key.
+func NewKeystoreDir(dir, passphrase string, opts ...KeystoreOption) (*Keystore, error) {
+	o := keystoreOptions{format: KeystoreFormatV3}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.format != KeystoreFormatV3 && o.format != KeystoreFormatLegacy {
+		return nil, fmt.Errorf("keystore: unknown format %q", o.format)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("keystore: create directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: read directory: %w", err)
+	}
+
+	k := &Keystore{dir: dir, passphrase: passphrase, format: o.format, kdf: o.kdfOr(KeystoreOptions{})}
+	k.guard = newKeyGuard(k, 0)
+	seen := make(map[common.Address]string)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		account, err := loadKeystore(filepath.Join(dir, entry.Name()), passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("%w (file %s)", err, entry.Name())
+		}
+		if other, ok := seen[account.address]; ok {
+			return nil, fmt.Errorf("keystore: files %s and %s hold the same account %s", other, entry.Name(), account.address.Hex())
+		}
+		seen[account.address] = entry.Name()
+		account.guard = k.guard
+		account.kdf = k.kdf
+		k.accounts = append(k.accounts, account)
+	}
+	if len(k.accounts) == 0 {
+		if _, err := k.NewAccount(); err != nil {
+			return nil, err
+		}
+	}
+
+	primary := k.accounts[0]
+	k.address = primary.address
+	k.privateKey = primary.privateKey
+	k.keyFile = primary.keyFile
+	k.guard.startAutoLock(o.autoLock)
+	return k, nil
+}
+
 // loadKeystore reads, decrypts, and parses an existing keystore file.
+// Files with a version field are Web3 Secret Storage files; others are in
+// the legacy format.
 func loadKeystore(keyFile, passphrase string) (*Keystore, error) {
 	data, err := os.ReadFile(keyFile)
 	if err != nil {
 		return nil, fmt.Errorf("keystore: read file: %w", err)
 	}
 
+	var probe struct {
+		Version json.RawMessage `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("keystore: parse JSON: %w: %w", ErrKeystoreCorrupted, err)
+	}
+	if len(probe.Version) > 0 {
+		return loadKeystoreV3(keyFile, data, passphrase)
+	}
+
 	var ks keystoreJSON
 	if err := json.Unmarshal(data, &ks); err != nil {
-		return nil, fmt.Errorf("keystore: parse JSON: %w", err)
+		return nil, fmt.Errorf("keystore: parse JSON: %w: %w", ErrKeystoreCorrupted, err)
 	}
-
-	// Derive key from passphrase using scrypt.
 	salt, err := hex.DecodeString(ks.Crypto.KDFParams.Salt)
 	if err != nil {
-		return nil, fmt.Errorf("keystore: decode salt: %w", err)
-	}
-	dk, err := scrypt.Key([]byte(passphrase), salt, ks.Crypto.KDFParams.N, ks.Crypto.KDFParams.R, ks.Crypto.KDFParams.P, ks.Crypto.KDFParams.DKLen)
-	if err != nil {
-		return nil, fmt.Errorf("keystore: scrypt: %w", err)
+		return nil, fmt.Errorf("keystore: decode salt: %w: %w", ErrKeystoreCorrupted, err)
 	}
-
-	// Decrypt ciphertext.
 	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
 	if err != nil {
-		return nil, fmt.Errorf("keystore: decode iv: %w", err)
+		return nil, fmt.Errorf("keystore: decode iv: %w: %w", ErrKeystoreCorrupted, err)
 	}
 	ciphertext, err := hex.DecodeString(ks.Crypto.CipherText)
 	if err != nil {
-		return nil, fmt.Errorf("keystore: decode ciphertext: %w", err)
+		return nil, fmt.Errorf("keystore: decode ciphertext: %w: %w", ErrKeystoreCorrupted, err)
+	}
+	if ks.Crypto.KDFParams.DKLen != 32 {
+		return nil, fmt.Errorf("keystore: dklen %d: %w", ks.Crypto.KDFParams.DKLen, ErrKeystoreCorrupted)
+	}
+
+	// Derive key from passphrase using scrypt.
+	dk, err := scrypt.Key([]byte(passphrase), salt, ks.Crypto.KDFParams.N, ks.Crypto.KDFParams.R, ks.Crypto.KDFParams.P, ks.Crypto.KDFParams.DKLen)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: scrypt: %w: %w", ErrKeystoreCorrupted, err)
+	}
+
+	// Check passphrase and ciphertext before decrypting.
+	migrate := ks.Crypto.MAC == ""
+	if !migrate {
+		if !hexEqual(ks.Crypto.KeyCheck, crypto.Keccak256(dk[16:32])) {
+			return nil, fmt.Errorf("keystore: decrypt: %w", ErrWrongPassphrase)
+		}
+		if !hexEqual(ks.Crypto.MAC, crypto.Keccak256(dk[16:32], ciphertext)) {
+			return nil, fmt.Errorf("keystore: decrypt: MAC mismatch: %w", ErrKeystoreCorrupted)
+		}
 	}
 
 	block, err := aes.NewCipher(dk[:32])
@@ -121,28 +462,98 @@ func loadKeystore(keyFile, passphrase string) (*Keystore, error) {
 	if err != nil {
 		return nil, fmt.Errorf("keystore: new GCM: %w", err)
 	}
-
+	if len(iv) != aesgcm.NonceSize() {
+		return nil, fmt.Errorf("keystore: iv is %d bytes: %w", len(iv), ErrKeystoreCorrupted)
+	}
 	plaintext, err := aesgcm.Open(nil, iv, ciphertext, nil)
 	if err != nil {
-		return nil, fmt.Errorf("keystore: decrypt: %w", err)
+		if migrate {
+			// Without a MAC the two cannot be told apart.
+			return nil, fmt.Errorf("keystore: decrypt: %w or %w", ErrWrongPassphrase, ErrKeystoreCorrupted)
+		}
+		return nil, fmt.Errorf("keystore: decrypt: %w: %w", ErrKeystoreCorrupted, err)
 	}
 
 	// Parse private key.
 	privateKey, err := crypto.ToECDSA(plaintext)
+	clear(plaintext)
 	if err != nil {
-		return nil, fmt.Errorf("keystore: parse private key: %w", err)
+		return nil, fmt.Errorf("keystore: parse private key: %w: %w", ErrKeystoreCorrupted, err)
 	}
-	address := common.HexToAddress(ks.Address)
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	if err := checkStoredAddress(ks.Address, address); err != nil {
+		return nil, err
+	}
+
+	if migrate {
+		// Best effort: a file that cannot be rewritten still loads, and is
+		// migrated on a later load.
+		ks.Crypto.MAC = hex.EncodeToString(crypto.Keccak256(dk[16:32], ciphertext))
+		ks.Crypto.KeyCheck = hex.EncodeToString(crypto.Keccak256(dk[16:32]))
+		if migrated, err := json.MarshalIndent(ks, "", "  "); err == nil {
+			_ = writeKeystoreFile(keyFile, migrated)
+		}
+	}
+	clear(dk)
 
 	return &Keystore{
 		address:    address,
 		privateKey: privateKey,
 		keyFile:    keyFile,
+		format:     KeystoreFormatLegacy,
+	}, nil
+}
+
+// hexEqual reports whether the hex string stored equals want, in constant
+// time.
+func hexEqual(stored string, want []byte) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(stored, "0x"))
+	return err == nil && subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// checkStoredAddress fails with ErrAddressMismatch if stored, the address
+// field of a key file, is set and is not address, the key's own.
+func checkStoredAddress(stored string, address common.Address) error {
+	if stored == "" {
+		return nil
+	}
+	if !common.IsHexAddress(stored) || common.HexToAddress(stored) != address {
+		return fmt.Errorf("keystore: file names %s, key is %s: %w", stored, address.Hex(), ErrAddressMismatch)
+	}
+	return nil
+}
+
+// loadKeystoreV3 decrypts a Web3 Secret Storage file with go-ethereum's
+// keystore package, which supports the scrypt and pbkdf2 KDFs and checks
+// the MAC, so a wrong passphrase is detected before decryption.
+func loadKeystoreV3(keyFile string, data []byte, passphrase string) (*Keystore, error) {
+	key, err := keystore.DecryptKey(data, passphrase)
+	if errors.Is(err, keystore.ErrDecrypt) {
+		// A failed MAC check: the V3 format cannot tell a wrong passphrase
+		// from a damaged ciphertext.
+		return nil, fmt.Errorf("keystore: decrypt: %w or %w: %w", ErrWrongPassphrase, ErrKeystoreCorrupted, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keystore: decrypt: %w: %w", ErrKeystoreCorrupted, err)
+	}
+	var stored struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(data, &stored); err == nil && stored.Address != "" {
+		if err := checkStoredAddress(stored.Address, key.Address); err != nil {
+			return nil, err
+		}
+	}
+	return &Keystore{
+		address:    key.Address,
+		privateKey: key.PrivateKey,
+		keyFile:    keyFile,
+		format:     KeystoreFormatV3,
 	}, nil
 }
 
 // saveKeystore encrypts a private key and writes it to disk.
-func saveKeystore(keyFile, passphrase string, privateKey *ecdsa.PrivateKey, address common.Address) error {
+func saveKeystore(keyFile, passphrase string, privateKey *ecdsa.PrivateKey, address common.Address, scryptN, scryptP int) error {
 	// Generate random salt and IV.
 	salt := make([]byte, 32)
 	if _, err := rand.Read(salt); err != nil {
@@ -154,7 +565,7 @@ func saveKeystore(keyFile, passphrase string, privateKey *ecdsa.PrivateKey, addr
 	}
 
 	// Derive key.
-	dk, err := scrypt.Key([]byte(passphrase), salt, 32768, 8, 1, 32)
+	dk, err := scrypt.Key([]byte(passphrase), salt, scryptN, 8, scryptP, 32)
 	if err != nil {
 		return fmt.Errorf("keystore: scrypt: %w", err)
 	}
@@ -170,16 +581,19 @@ func saveKeystore(keyFile, passphrase string, privateKey *ecdsa.PrivateKey, addr
 	}
 	privateKeyBytes := crypto.FromECDSA(privateKey)
 	ciphertext := aesgcm.Seal(nil, iv, privateKeyBytes, nil)
+	clear(privateKeyBytes)
 
 	// Build JSON.
 	var ks keystoreJSON
 	ks.Address = address.Hex()
 	ks.Crypto.CipherText = hex.EncodeToString(ciphertext)
 	ks.Crypto.CipherParams.IV = hex.EncodeToString(iv)
+	ks.Crypto.MAC = hex.EncodeToString(crypto.Keccak256(dk[16:32], ciphertext))
+	ks.Crypto.KeyCheck = hex.EncodeToString(crypto.Keccak256(dk[16:32]))
 	ks.Crypto.KDF = "scrypt"
-	ks.Crypto.KDFParams.N = 32768
+	ks.Crypto.KDFParams.N = scryptN
 	ks.Crypto.KDFParams.R = 8
-	ks.Crypto.KDFParams.P = 1
+	ks.Crypto.KDFParams.P = scryptP
 	ks.Crypto.KDFParams.Salt = hex.EncodeToString(salt)
 	ks.Crypto.KDFParams.DKLen = 32
 
@@ -187,24 +601,71 @@ func saveKeystore(keyFile, passphrase string, privateKey *ecdsa.PrivateKey, addr
 	if err != nil {
 		return fmt.Errorf("keystore: marshal JSON: %w", err)
 	}
+	return writeKeystoreFile(keyFile, data)
+}
 
+// writeKeystoreFile writes an encrypted key file, readable only by its
+// owner. The data goes to a temporary file that is renamed into place, so
+// a crash never leaves a truncated key file; the temporary file's name
+// starts with a dot, so a keystore directory skips a leftover one.
+func writeKeystoreFile(keyFile string, data []byte) error {
 	// Ensure directory exists.
 	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
 		return fmt.Errorf("keystore: create directory: %w", err)
 	}
 
-	// Write file with restrictive permissions.
-	if err := os.WriteFile(keyFile, data, 0600); err != nil {
+	// CreateTemp creates the file with restrictive permissions (0600).
+	tmp, err := os.CreateTemp(filepath.Dir(keyFile), "."+filepath.Base(keyFile)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("keystore: write file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("keystore: write file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("keystore: write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("keystore: write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), keyFile); err != nil {
 		return fmt.Errorf("keystore: write file: %w", err)
 	}
 
 	return nil
 }
 
+// saveKeystoreV3 encrypts a private key as a Web3 Secret Storage file and
+// writes it to disk.
+func saveKeystoreV3(keyFile, passphrase string, privateKey *ecdsa.PrivateKey, scryptN, scryptP int) error {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return fmt.Errorf("keystore: generate id: %w", err)
+	}
+	key := &keystore.Key{
+		Id:         id,
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+	data, err := keystore.EncryptKey(key, passphrase, scryptN, scryptP)
+	if err != nil {
+		return fmt.Errorf("keystore: encrypt: %w", err)
+	}
+	return writeKeystoreFile(keyFile, data)
+}
+
 // Sign implements blockchain.Wallet.
 // It signs the provided digest (32‑byte hash) using ECDSA.
 func (k *Keystore) Sign(digest []byte) ([]byte, error) {
-	sig, err := crypto.Sign(digest, k.privateKey)
+	privateKey, release, err := k.useKey()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	sig, err := crypto.Sign(digest, privateKey)
 	if err != nil {
 		return nil, fmt.Errorf("keystore: sign: %w", err)
 	}
@@ -212,6 +673,21 @@ func (k *Keystore) Sign(digest []byte) ([]byte, error) {
 	return sig, nil
 }
 
+// SignTx implements TxSigner.
+// It signs tx for chainID with the signer of the latest fork.
+func (k *Keystore) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	privateKey, release, err := k.useKey()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: sign transaction: %w", err)
+	}
+	return signed, nil
+}
+
 // Address implements blockchain.Wallet.
 func (k *Keystore) Address() string {
 	return k.address.Hex()
@@ -222,4 +698,199 @@ func (k *Keystore) Path() string {
 	return k.keyFile
 }
 
+// Format returns the format of the keystore file. For a keystore
+// directory it is the format new keys are written in.
+func (k *Keystore) Format() KeystoreFormat {
+	return k.format
+}
+
+// ChangePassphrase re‑encrypts the keystore with newPassphrase, with a
+// fresh salt and nonce, after checking oldPassphrase against the file. Each
+// file is replaced atomically. A keystore directory changes every key, all
+// of which must open with oldPassphrase; an I/O error partway leaves the
+// keys written so far on newPassphrase. Keys keep their format unless
+// WithKeystoreFormat is given. An empty newPassphrase fails with
+// ErrEmptyPassphrase unless WithEmptyPassphrase is given.
+func (k *Keystore) ChangePassphrase(oldPassphrase, newPassphrase string, opts ...KeystoreOption) error {
+	o, err := rewriteOptions(newPassphrase, opts)
+	if err != nil {
+		return err
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	_, release, err := k.useKey()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	accounts := []*Keystore{k}
+	if k.dir != "" {
+		accounts = k.accounts
+	}
+	for _, account := range accounts {
+		if _, err := loadKeystore(account.keyFile, oldPassphrase); err != nil {
+			return fmt.Errorf("keystore: check old passphrase: %w", err)
+		}
+	}
+	for _, account := range accounts {
+		format := o.format
+		if format == "" {
+			format = account.format
+		}
+		kdf := o.kdfOr(account.kdf)
+		if _, err := saveKey(account.keyFile, newPassphrase, account.privateKey, format, kdf); err != nil {
+			return err
+		}
+		account.format = format
+		account.kdf = kdf
+		logKeystoreEvent(o.audit, "keystore_change_passphrase", account.address, account.keyFile)
+	}
+	if k.dir != "" {
+		// New accounts are encrypted like the existing ones.
+		k.passphrase = newPassphrase
+	}
+	return nil
+}
+
+// ExportTo writes the keystore's key, encrypted with newPassphrase, to a
+// new file at path, for example as a backup; an existing file is not
+// overwritten (the error wraps fs.ErrExist). A keystore directory exports
+// its primary account; use WalletFor to export another. The key keeps its
+// format unless WithKeystoreFormat is given, and an empty newPassphrase
+// fails with ErrEmptyPassphrase unless WithEmptyPassphrase is given.
+func (k *Keystore) ExportTo(path, newPassphrase string, opts ...KeystoreOption) error {
+	o, err := rewriteOptions(newPassphrase, opts)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("keystore: %s: %w", path, fs.ErrExist)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("keystore: stat file: %w", err)
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	privateKey, release, err := k.useKey()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	format := o.format
+	if format == "" {
+		format = k.format
+		if k.dir != "" {
+			format = k.accounts[0].format
+		}
+	}
+	if _, err := saveKey(path, newPassphrase, privateKey, format, o.kdfOr(k.kdf)); err != nil {
+		return err
+	}
+	logKeystoreEvent(o.audit, "keystore_export", k.address, path)
+	return nil
+}
+
+// rewriteOptions applies the options of ChangePassphrase and ExportTo and
+// checks newPassphrase against them. The format is "" unless set.
+func rewriteOptions(newPassphrase string, opts []KeystoreOption) (keystoreOptions, error) {
+	var o keystoreOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.format != "" && o.format != KeystoreFormatV3 && o.format != KeystoreFormatLegacy {
+		return o, fmt.Errorf("keystore: unknown format %q", o.format)
+	}
+	if newPassphrase == "" && !o.allowEmpty {
+		return o, fmt.Errorf("keystore: %w (use WithEmptyPassphrase to allow it)", ErrEmptyPassphrase)
+	}
+	return o, nil
+}
+
+// logKeystoreEvent records a key file written for address in audit, if
+// any. Audit failures do not fail the operation, which already succeeded.
+func logKeystoreEvent(audit *observe.AuditLogger, action string, address common.Address, path string) {
+	if audit == nil {
+		return
+	}
+	_ = audit.Log(&observe.AuditEntry{
+		From:  address.Hex(),
+		Extra: map[string]interface{}{"action": action, "path": path},
+	})
+}
+
+// Dir returns the directory of a keystore opened with NewKeystoreDir, or
+// "" for a single key file.
+func (k *Keystore) Dir() string {
+	return k.dir
+}
+
+// NewAccount generates a key in a keystore directory, encrypted with the
+// directory's passphrase, and returns a Keystore signing as it.
+func (k *Keystore) NewAccount() (*Keystore, error) {
+	if k.dir == "" {
+		return nil, fmt.Errorf("keystore: %s is a single key file, not a directory", k.keyFile)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	_, release, err := k.useKey()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("keystore: generate key: %w", err)
+	}
+	// Key files are named like geth's, so sorting by name sorts by age.
+	name := fmt.Sprintf("UTC--%s--%s",
+		time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z"),
+		hex.EncodeToString(crypto.PubkeyToAddress(privateKey.PublicKey).Bytes()))
+	account, err := saveKey(filepath.Join(k.dir, name), k.passphrase, privateKey, k.format, k.kdf)
+	if err != nil {
+		return nil, err
+	}
+	account.guard = k.guard
+	k.accounts = append(k.accounts, account)
+	return account, nil
+}
+
+// Accounts implements blockchain.MultiWallet. A single key file holds one
+// account.
+func (k *Keystore) Accounts() []string {
+	if k.dir == "" {
+		return []string{k.Address()}
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	addresses := make([]string, len(k.accounts))
+	for i, account := range k.accounts {
+		addresses[i] = account.Address()
+	}
+	return addresses
+}
+
+// WalletFor implements blockchain.MultiWallet.
+func (k *Keystore) WalletFor(address string) (blockchain.Wallet, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("keystore: invalid address %q", address)
+	}
+	addr := common.HexToAddress(address)
+	if k.dir == "" {
+		if addr == k.address {
+			return k, nil
+		}
+	} else {
+		k.mu.RLock()
+		defer k.mu.RUnlock()
+		for _, account := range k.accounts {
+			if account.address == addr {
+				return account, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("keystore: %w %s", blockchain.ErrUnknownAccount, addr.Hex())
+}
+
 // EOF: internal/blockchain/evm/keystore.go
\ No newline at end of file
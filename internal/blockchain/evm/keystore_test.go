@@ -5,15 +5,24 @@
 package evm_test
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/0xSemantic/lola-os/internal/blockchain"
 	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
 )
 
 func TestKeystore_CreateAndLoad(t *testing.T) {
@@ -60,4 +69,552 @@ func TestKeystore_WrongPassphrase(t *testing.T) {
 	assert.Contains(t, err.Error(), "decrypt")
 }
 
+func TestKeystore_Formats(t *testing.T) {
+	for _, format := range []evm.KeystoreFormat{evm.KeystoreFormatV3, evm.KeystoreFormatLegacy} {
+		t.Run(string(format), func(t *testing.T) {
+			keyFile := filepath.Join(t.TempDir(), "test.key")
+			ks, err := evm.NewKeystore(keyFile, "pass", evm.WithKeystoreFormat(format))
+			require.NoError(t, err)
+			assert.Equal(t, format, ks.Format())
+
+			// The format is detected on load, whatever the option says.
+			loaded, err := evm.NewKeystore(keyFile, "pass", evm.WithKeystoreFormat(evm.KeystoreFormatV3))
+			require.NoError(t, err)
+			assert.Equal(t, format, loaded.Format())
+			assert.Equal(t, ks.Address(), loaded.Address())
+
+			_, err = evm.NewKeystore(keyFile, "wrong")
+			assert.ErrorContains(t, err, "decrypt")
+
+			data, err := os.ReadFile(keyFile)
+			require.NoError(t, err)
+			key, err := keystore.DecryptKey(data, "pass")
+			if format == evm.KeystoreFormatV3 {
+				require.NoError(t, err, "geth reads the file")
+				assert.Equal(t, ks.Address(), key.Address.Hex())
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	_, err := evm.NewKeystore(filepath.Join(t.TempDir(), "test.key"), "pass", evm.WithKeystoreFormat("v4"))
+	assert.ErrorContains(t, err, `unknown format "v4"`)
+}
+
+func TestKeystore_LoadsGethFiles(t *testing.T) {
+	t.Run("scrypt", func(t *testing.T) {
+		privateKey, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		data, err := keystore.EncryptKey(&keystore.Key{
+			Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+			PrivateKey: privateKey,
+		}, "pass", keystore.LightScryptN, keystore.LightScryptP)
+		require.NoError(t, err)
+		keyFile := filepath.Join(t.TempDir(), "UTC--2024-01-01T00-00-00.000000000Z--key")
+		require.NoError(t, os.WriteFile(keyFile, data, 0600))
+
+		ks, err := evm.NewKeystore(keyFile, "pass")
+		require.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(privateKey.PublicKey).Hex(), ks.Address())
+		assert.Equal(t, evm.KeystoreFormatV3, ks.Format())
+	})
+
+	t.Run("pbkdf2", func(t *testing.T) {
+		// The PBKDF2 test vector of the Web3 Secret Storage definition.
+		const vector = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},` +
+			`"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2",` +
+			`"kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},` +
+			`"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},` +
+			`"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+		keyFile := filepath.Join(t.TempDir(), "vector.json")
+		require.NoError(t, os.WriteFile(keyFile, []byte(vector), 0600))
+
+		ks, err := evm.NewKeystore(keyFile, "testpassword")
+		require.NoError(t, err)
+		privateKey, err := hex.DecodeString("7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d")
+		require.NoError(t, err)
+		key, err := crypto.ToECDSA(privateKey)
+		require.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey).Hex(), ks.Address())
+
+		// The MAC rejects a wrong passphrase.
+		_, err = evm.NewKeystore(keyFile, "wrong")
+		assert.ErrorIs(t, err, keystore.ErrDecrypt)
+	})
+}
+
+func TestKeystore_Dir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keys")
+	ks, err := evm.NewKeystoreDir(dir, "pass")
+	require.NoError(t, err)
+	assert.Equal(t, dir, ks.Dir())
+	require.Len(t, ks.Accounts(), 1, "an empty directory gets a key")
+	assert.Equal(t, ks.Address(), ks.Accounts()[0])
+
+	second, err := ks.NewAccount()
+	require.NoError(t, err)
+	assert.Equal(t, []string{ks.Address(), second.Address()}, ks.Accounts())
+	assert.Equal(t, dir, filepath.Dir(second.Path()))
+
+	// Each account signs as itself.
+	digest := crypto.Keccak256Hash([]byte("hello")).Bytes()
+	for _, address := range ks.Accounts() {
+		wallet, err := ks.WalletFor(strings.ToLower(address))
+		require.NoError(t, err)
+		assert.Equal(t, address, wallet.Address())
+		sig, err := wallet.Sign(digest)
+		require.NoError(t, err)
+		pubKey, err := crypto.SigToPub(digest, sig)
+		require.NoError(t, err)
+		assert.Equal(t, address, crypto.PubkeyToAddress(*pubKey).Hex())
+	}
+	_, err = ks.WalletFor("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
+	assert.ErrorIs(t, err, blockchain.ErrUnknownAccount)
+
+	// Reopening loads both accounts; the oldest stays primary.
+	reopened, err := evm.NewKeystoreDir(dir, "pass")
+	require.NoError(t, err)
+	assert.Equal(t, ks.Accounts(), reopened.Accounts())
+	assert.Equal(t, ks.Address(), reopened.Address())
+
+	_, err = evm.NewKeystoreDir(dir, "wrong")
+	assert.ErrorContains(t, err, "decrypt")
+}
+
+func TestKeystore_SingleFileAccounts(t *testing.T) {
+	ks, err := evm.NewKeystore(filepath.Join(t.TempDir(), "test.key"), "pass")
+	require.NoError(t, err)
+	assert.Empty(t, ks.Dir())
+	assert.Equal(t, []string{ks.Address()}, ks.Accounts())
+
+	wallet, err := ks.WalletFor(ks.Address())
+	require.NoError(t, err)
+	assert.Equal(t, ks.Address(), wallet.Address())
+
+	_, err = ks.NewAccount()
+	assert.ErrorContains(t, err, "not a directory")
+}
+
+func TestImportPrivateKey(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	hexKey := hex.EncodeToString(crypto.FromECDSA(privateKey))
+	address := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+
+	for _, format := range []evm.KeystoreFormat{evm.KeystoreFormatV3, evm.KeystoreFormatLegacy} {
+		t.Run(string(format), func(t *testing.T) {
+			keyFile := filepath.Join(t.TempDir(), "imported.key")
+			ks, err := evm.ImportPrivateKey(keyFile, "pass", "0x"+hexKey, false, evm.WithKeystoreFormat(format))
+			require.NoError(t, err)
+			assert.Equal(t, address, ks.Address())
+			assert.Equal(t, format, ks.Format())
+
+			loaded, err := evm.NewKeystore(keyFile, "pass")
+			require.NoError(t, err)
+			assert.Equal(t, address, loaded.Address())
+		})
+	}
+
+	keyFile := filepath.Join(t.TempDir(), "imported.key")
+	_, err = evm.ImportPrivateKey(keyFile, "pass", hexKey, false)
+	require.NoError(t, err, "the 0x prefix is optional")
+
+	// An existing file is kept unless force is set.
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherHex := hex.EncodeToString(crypto.FromECDSA(other))
+	_, err = evm.ImportPrivateKey(keyFile, "pass", otherHex, false)
+	assert.ErrorIs(t, err, fs.ErrExist)
+	loaded, err := evm.NewKeystore(keyFile, "pass")
+	require.NoError(t, err)
+	assert.Equal(t, address, loaded.Address())
+
+	ks, err := evm.ImportPrivateKey(keyFile, "pass", otherHex, true)
+	require.NoError(t, err)
+	loaded, err = evm.NewKeystore(keyFile, "pass")
+	require.NoError(t, err)
+	assert.Equal(t, ks.Address(), loaded.Address())
+	assert.Equal(t, crypto.PubkeyToAddress(other.PublicKey).Hex(), loaded.Address())
+
+	for _, bad := range []string{"", "0x1234", "zz" + hexKey[2:], hexKey + "00", strings.Repeat("0", 64)} {
+		_, err := evm.ImportPrivateKey(filepath.Join(t.TempDir(), "bad.key"), "pass", bad, false)
+		assert.Error(t, err, bad)
+		if err != nil {
+			assert.NotContains(t, err.Error(), hexKey[2:], "errors must not echo the key")
+		}
+	}
+}
+
+// readAudit returns the entries of an audit log file.
+func readAudit(t *testing.T, path string) []observe.AuditEntry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entries []observe.AuditEntry
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var entry observe.AuditEntry
+		require.NoError(t, dec.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestKeystore_ChangePassphrase(t *testing.T) {
+	for _, format := range []evm.KeystoreFormat{evm.KeystoreFormatV3, evm.KeystoreFormatLegacy} {
+		t.Run(string(format), func(t *testing.T) {
+			dir := t.TempDir()
+			keyFile := filepath.Join(dir, "test.key")
+			ks, err := evm.NewKeystore(keyFile, "old", evm.WithKeystoreFormat(format))
+			require.NoError(t, err)
+			before, err := os.ReadFile(keyFile)
+			require.NoError(t, err)
+
+			err = ks.ChangePassphrase("wrong", "new")
+			assert.ErrorContains(t, err, "decrypt")
+			err = ks.ChangePassphrase("old", "")
+			assert.ErrorIs(t, err, evm.ErrEmptyPassphrase)
+			after, err := os.ReadFile(keyFile)
+			require.NoError(t, err)
+			assert.Equal(t, before, after, "a failed change leaves the file alone")
+
+			auditPath := filepath.Join(dir, "audit.log")
+			audit, err := observe.NewAuditLogger(auditPath, true)
+			require.NoError(t, err)
+			require.NoError(t, ks.ChangePassphrase("old", "new", evm.WithAuditLog(audit)))
+			require.NoError(t, audit.Close())
+
+			_, err = evm.NewKeystore(keyFile, "old")
+			assert.ErrorContains(t, err, "decrypt")
+			loaded, err := evm.NewKeystore(keyFile, "new")
+			require.NoError(t, err)
+			assert.Equal(t, ks.Address(), loaded.Address())
+			assert.Equal(t, format, loaded.Format())
+			info, err := os.Stat(keyFile)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+			entries := readAudit(t, auditPath)
+			require.Len(t, entries, 1)
+			assert.Equal(t, ks.Address(), entries[0].From)
+			assert.Equal(t, "keystore_change_passphrase", entries[0].Extra["action"])
+			assert.Equal(t, keyFile, entries[0].Extra["path"])
+			assert.False(t, entries[0].Timestamp.IsZero())
+			raw, err := os.ReadFile(auditPath)
+			require.NoError(t, err)
+			assert.NotContains(t, string(raw), "ciphertext")
+
+			// The empty passphrase is allowed only explicitly.
+			require.NoError(t, ks.ChangePassphrase("new", "", evm.WithEmptyPassphrase()))
+			_, err = evm.NewKeystore(keyFile, "")
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestKeystore_ChangePassphraseDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keys")
+	ks, err := evm.NewKeystoreDir(dir, "old")
+	require.NoError(t, err)
+	_, err = ks.NewAccount()
+	require.NoError(t, err)
+
+	require.NoError(t, ks.ChangePassphrase("old", "new"))
+	_, err = ks.NewAccount()
+	require.NoError(t, err)
+
+	// Every key, including the one created afterwards, opens with the
+	// new passphrase.
+	reopened, err := evm.NewKeystoreDir(dir, "new")
+	require.NoError(t, err)
+	assert.Len(t, reopened.Accounts(), 3)
+	_, err = evm.NewKeystoreDir(dir, "old")
+	assert.ErrorContains(t, err, "decrypt")
+}
+
+func TestKeystore_ChangePassphraseLeftoverTempFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keys")
+	ks, err := evm.NewKeystoreDir(dir, "old")
+	require.NoError(t, err)
+	primary, err := ks.WalletFor(ks.Address())
+	require.NoError(t, err)
+	path := primary.(*evm.Keystore).Path()
+
+	// A crash between writing and renaming leaves a partial temporary
+	// file next to the intact key file.
+	leftover := filepath.Join(dir, "."+filepath.Base(path)+".123.tmp")
+	require.NoError(t, os.WriteFile(leftover, []byte(`{"address":"`), 0600))
+
+	reopened, err := evm.NewKeystoreDir(dir, "old")
+	require.NoError(t, err, "the directory skips the temporary file")
+	assert.Equal(t, []string{ks.Address()}, reopened.Accounts())
+
+	require.NoError(t, reopened.ChangePassphrase("old", "new"))
+	loaded, err := evm.NewKeystore(path, "new")
+	require.NoError(t, err)
+	assert.Equal(t, ks.Address(), loaded.Address())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{filepath.Base(path), filepath.Base(leftover)}, names,
+		"a successful write leaves no temporary file of its own")
+}
+
+func TestKeystore_ExportTo(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := evm.NewKeystore(filepath.Join(dir, "test.key"), "pass", evm.WithKeystoreFormat(evm.KeystoreFormatLegacy))
+	require.NoError(t, err)
+
+	auditPath := filepath.Join(dir, "audit.log")
+	audit, err := observe.NewAuditLogger(auditPath, true)
+	require.NoError(t, err)
+	backup := filepath.Join(dir, "backup", "test.key")
+	require.NoError(t, ks.ExportTo(backup, "backup-pass", evm.WithKeystoreFormat(evm.KeystoreFormatV3), evm.WithAuditLog(audit)))
+	require.NoError(t, audit.Close())
+
+	loaded, err := evm.NewKeystore(backup, "backup-pass")
+	require.NoError(t, err)
+	assert.Equal(t, ks.Address(), loaded.Address())
+	assert.Equal(t, evm.KeystoreFormatV3, loaded.Format())
+	_, err = evm.NewKeystore(filepath.Join(dir, "test.key"), "pass")
+	assert.NoError(t, err, "the original is unchanged")
+
+	entries := readAudit(t, auditPath)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ks.Address(), entries[0].From)
+	assert.Equal(t, "keystore_export", entries[0].Extra["action"])
+	assert.Equal(t, backup, entries[0].Extra["path"])
+
+	err = ks.ExportTo(backup, "other")
+	assert.ErrorIs(t, err, fs.ErrExist)
+	err = ks.ExportTo(filepath.Join(dir, "empty.key"), "")
+	assert.ErrorIs(t, err, evm.ErrEmptyPassphrase)
+	assert.NoFileExists(t, filepath.Join(dir, "empty.key"))
+}
+
+// warnLogger records the messages of Warn.
+type warnLogger struct {
+	observe.NoopLogger
+	warnings []string
+}
+
+func (l *warnLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.warnings = append(l.warnings, msg)
+}
+
+// scryptN returns the scrypt N stored in a key file of either format.
+func scryptN(t *testing.T, keyFile string) int {
+	data, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+	var file struct {
+		Crypto struct {
+			KDFParams struct {
+				N int `json:"n"`
+			} `json:"kdfparams"`
+		} `json:"crypto"`
+	}
+	require.NoError(t, json.Unmarshal(data, &file))
+	return file.Crypto.KDFParams.N
+}
+
+func TestKeystore_KDFOptions(t *testing.T) {
+	for _, format := range []evm.KeystoreFormat{evm.KeystoreFormatV3, evm.KeystoreFormatLegacy} {
+		t.Run(string(format), func(t *testing.T) {
+			dir := t.TempDir()
+			light := filepath.Join(dir, "light.key")
+			ks, err := evm.NewKeystoreWithOptions(light, "pass", evm.LightKDF, evm.WithKeystoreFormat(format))
+			require.NoError(t, err)
+			assert.Equal(t, 4096, scryptN(t, light))
+
+			// Files load with their own parameters, whatever the options say.
+			loaded, err := evm.NewKeystore(light, "pass")
+			require.NoError(t, err)
+			assert.Equal(t, ks.Address(), loaded.Address())
+
+			def := filepath.Join(dir, "default.key")
+			_, err = evm.NewKeystore(def, "pass", evm.WithKeystoreFormat(format))
+			require.NoError(t, err)
+			assert.Equal(t, 32768, scryptN(t, def))
+
+			// ChangePassphrase keeps the parameters of the keystore.
+			require.NoError(t, ks.ChangePassphrase("pass", "new"))
+			assert.Equal(t, 4096, scryptN(t, light))
+			_, err = evm.NewKeystore(light, "new")
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestKeystore_KDFOptionsDir(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := evm.NewKeystoreDir(dir, "pass", evm.WithKeystoreOptions(evm.LightKDF))
+	require.NoError(t, err)
+	_, err = ks.NewAccount()
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, 4096, scryptN(t, filepath.Join(dir, entry.Name())), entry.Name())
+	}
+}
+
+func TestKeystore_KDFOptionsValidation(t *testing.T) {
+	for _, kdf := range []evm.KeystoreOptions{{ScryptN: 1000}, {ScryptN: 1}, {ScryptN: -2}, {ScryptP: -1}} {
+		keyFile := filepath.Join(t.TempDir(), "test.key")
+		_, err := evm.NewKeystoreWithOptions(keyFile, "pass", kdf)
+		assert.Error(t, err, "%+v", kdf)
+		assert.NoFileExists(t, keyFile)
+	}
+
+	logger := &warnLogger{}
+	_, err := evm.NewKeystoreWithOptions(filepath.Join(t.TempDir(), "weak.key"), "pass",
+		evm.KeystoreOptions{ScryptN: 16, Logger: logger})
+	require.NoError(t, err)
+	assert.Len(t, logger.warnings, 1, "weak parameters are logged")
+
+	logger = &warnLogger{}
+	_, err = evm.NewKeystoreWithOptions(filepath.Join(t.TempDir(), "light.key"), "pass",
+		evm.KeystoreOptions{ScryptN: 4096, Logger: logger})
+	require.NoError(t, err)
+	assert.Empty(t, logger.warnings)
+}
+
+// editKeyFile rewrites the JSON of keyFile with edit.
+func editKeyFile(t *testing.T, keyFile string, edit func(file map[string]interface{})) {
+	t.Helper()
+	data, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+	var file map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &file))
+	edit(file)
+	data, err = json.Marshal(file)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, data, 0600))
+}
+
+func TestKeystore_LegacyIntegrity(t *testing.T) {
+	newLegacy := func(t *testing.T) string {
+		keyFile := filepath.Join(t.TempDir(), "legacy.key")
+		_, err := evm.NewKeystoreWithOptions(keyFile, "pass", evm.LightKDF, evm.WithKeystoreFormat(evm.KeystoreFormatLegacy))
+		require.NoError(t, err)
+		return keyFile
+	}
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		_, err := evm.NewKeystore(newLegacy(t), "wrong")
+		assert.ErrorIs(t, err, evm.ErrWrongPassphrase)
+		assert.NotErrorIs(t, err, evm.ErrKeystoreCorrupted)
+	})
+
+	t.Run("tampered ciphertext", func(t *testing.T) {
+		keyFile := newLegacy(t)
+		editKeyFile(t, keyFile, func(file map[string]interface{}) {
+			c := file["crypto"].(map[string]interface{})
+			ct := []byte(c["ciphertext"].(string))
+			if ct[0] == '0' {
+				ct[0] = '1'
+			} else {
+				ct[0] = '0'
+			}
+			c["ciphertext"] = string(ct)
+		})
+		_, err := evm.NewKeystore(keyFile, "pass")
+		assert.ErrorIs(t, err, evm.ErrKeystoreCorrupted)
+		assert.NotErrorIs(t, err, evm.ErrWrongPassphrase)
+
+		editKeyFile(t, keyFile, func(file map[string]interface{}) {
+			c := file["crypto"].(map[string]interface{})
+			ct := c["ciphertext"].(string)
+			c["ciphertext"] = ct[:len(ct)/2]
+		})
+		_, err = evm.NewKeystore(keyFile, "pass")
+		assert.ErrorIs(t, err, evm.ErrKeystoreCorrupted, "truncated")
+	})
+
+	t.Run("tampered address", func(t *testing.T) {
+		keyFile := newLegacy(t)
+		editKeyFile(t, keyFile, func(file map[string]interface{}) {
+			file["address"] = "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+		})
+		_, err := evm.NewKeystore(keyFile, "pass")
+		assert.ErrorIs(t, err, evm.ErrAddressMismatch)
+		assert.NotErrorIs(t, err, evm.ErrKeystoreCorrupted)
+	})
+
+	t.Run("truncated file", func(t *testing.T) {
+		keyFile := newLegacy(t)
+		data, err := os.ReadFile(keyFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(keyFile, data[:len(data)/2], 0600))
+		_, err = evm.NewKeystore(keyFile, "pass")
+		assert.ErrorIs(t, err, evm.ErrKeystoreCorrupted)
+	})
+}
+
+func TestKeystore_LegacyMigratesMAC(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "legacy.key")
+	ks, err := evm.NewKeystoreWithOptions(keyFile, "pass", evm.LightKDF, evm.WithKeystoreFormat(evm.KeystoreFormatLegacy))
+	require.NoError(t, err)
+	editKeyFile(t, keyFile, func(file map[string]interface{}) {
+		c := file["crypto"].(map[string]interface{})
+		delete(c, "mac")
+		delete(c, "keycheck")
+	})
+
+	// Without a MAC a failure is ambiguous.
+	_, err = evm.NewKeystore(keyFile, "wrong")
+	assert.ErrorIs(t, err, evm.ErrWrongPassphrase)
+	assert.ErrorIs(t, err, evm.ErrKeystoreCorrupted)
+
+	loaded, err := evm.NewKeystore(keyFile, "pass")
+	require.NoError(t, err)
+	assert.Equal(t, ks.Address(), loaded.Address())
+	editKeyFile(t, keyFile, func(file map[string]interface{}) {
+		c := file["crypto"].(map[string]interface{})
+		assert.NotEmpty(t, c["mac"], "the MAC is added on load")
+		assert.NotEmpty(t, c["keycheck"])
+	})
+
+	_, err = evm.NewKeystore(keyFile, "wrong")
+	assert.ErrorIs(t, err, evm.ErrWrongPassphrase)
+	assert.NotErrorIs(t, err, evm.ErrKeystoreCorrupted)
+	_, err = evm.NewKeystore(keyFile, "pass")
+	require.NoError(t, err)
+}
+
+func TestKeystore_V3AddressMismatch(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "v3.key")
+	_, err := evm.NewKeystoreWithOptions(keyFile, "pass", evm.LightKDF)
+	require.NoError(t, err)
+	editKeyFile(t, keyFile, func(file map[string]interface{}) {
+		file["address"] = "742d35cc6634c0532925a3b844bc9e90f1a6b1e7"
+	})
+	_, err = evm.NewKeystore(keyFile, "pass")
+	assert.ErrorIs(t, err, evm.ErrAddressMismatch)
+}
+
+func BenchmarkKeystore_Create(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		kdf  evm.KeystoreOptions
+	}{{"default", evm.KeystoreOptions{}}, {"light", evm.LightKDF}} {
+		b.Run(bc.name, func(b *testing.B) {
+			dir := b.TempDir()
+			for i := 0; i < b.N; i++ {
+				keyFile := filepath.Join(dir, fmt.Sprintf("%d.key", i))
+				if _, err := evm.NewKeystoreWithOptions(keyFile, "test", bc.kdf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 // EOF: internal/blockchain/evm/keystore_test.go
\ No newline at end of file
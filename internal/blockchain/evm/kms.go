@@ -0,0 +1,227 @@
+// Package evm provides a wallet implementing blockchain.Wallet whose key
+// never leaves AWS KMS.
+//
+// File: internal/blockchain/evm/kms.go
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/smithy-go"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultKMSTimeout bounds each KMS request of a KMSWallet or
+// GCPKMSWallet.
+const DefaultKMSTimeout = 10 * time.Second
+
+// Errors of the KMS wallets, so that callers can tell a throttled or
+// misconfigured KMS from a bad signature.
+var (
+	// ErrKMSQuotaExceeded indicates that KMS throttled the request.
+	ErrKMSQuotaExceeded = errors.New("kms quota exceeded")
+	// ErrKMSPermissionDenied indicates missing credentials or rights on
+	// the key.
+	ErrKMSPermissionDenied = errors.New("kms permission denied")
+	// ErrKMSInvalidSignature indicates a signature from KMS that is
+	// malformed or not made by the wallet's key.
+	ErrKMSInvalidSignature = errors.New("invalid kms signature")
+)
+
+// KMSClient is the part of the AWS KMS API a KMSWallet uses; *kms.Client
+// implements it.
+type KMSClient interface {
+	GetPublicKey(ctx context.Context, params *kms.GetPublicKeyInput, optFns ...func(*kms.Options)) (*kms.GetPublicKeyOutput, error)
+	Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error)
+}
+
+// KMSWallet implements blockchain.Wallet with an asymmetric AWS KMS key of
+// spec ECC_SECG_P256K1 (secp256k1), so the private key is never held in
+// memory or on disk. Every Sign is a KMS request.
+type KMSWallet struct {
+	client    KMSClient
+	keyID     string
+	timeout   time.Duration
+	publicKey []byte // uncompressed secp256k1 point
+	address   common.Address
+}
+
+// KMSOption configures a KMSWallet.
+type KMSOption func(*KMSWallet)
+
+// WithKMSTimeout bounds each KMS request (0 = DefaultKMSTimeout).
+func WithKMSTimeout(timeout time.Duration) KMSOption {
+	return func(w *KMSWallet) {
+		if timeout > 0 {
+			w.timeout = timeout
+		}
+	}
+}
+
+// NewKMSWallet creates a wallet for the KMS key keyID (a key id, ARN or
+// alias). It fetches the public key once to derive the address and fails
+// if the key is not a secp256k1 signing key.
+func NewKMSWallet(ctx context.Context, client KMSClient, keyID string, opts ...KMSOption) (*KMSWallet, error) {
+	w := &KMSWallet{client: client, keyID: keyID, timeout: DefaultKMSTimeout}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	out, err := client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return nil, fmt.Errorf("kms wallet: get public key: %w", classifyAWSKMSError(err))
+	}
+	if out.KeySpec != kmstypes.KeySpecEccSecgP256k1 {
+		return nil, fmt.Errorf("kms wallet: key %s has spec %s, want %s", keyID, out.KeySpec, kmstypes.KeySpecEccSecgP256k1)
+	}
+	if out.KeyUsage != "" && out.KeyUsage != kmstypes.KeyUsageTypeSignVerify {
+		return nil, fmt.Errorf("kms wallet: key %s has usage %s, want %s", keyID, out.KeyUsage, kmstypes.KeyUsageTypeSignVerify)
+	}
+	publicKey, err := parseKMSPublicKey(out.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("kms wallet: %w", err)
+	}
+	w.publicKey = publicKey
+	w.address = common.BytesToAddress(crypto.Keccak256(publicKey[1:])[12:])
+	return w, nil
+}
+
+// NewKMSWalletFromConfig creates a KMSWallet using the default AWS
+// credential chain (environment, shared config, instance role) in region
+// ("" = the region of the AWS configuration).
+func NewKMSWalletFromConfig(ctx context.Context, keyID, region string, opts ...KMSOption) (*KMSWallet, error) {
+	var loadOpts []func(*config.LoadOptions) error
+	if region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("kms wallet: load AWS config: %w", err)
+	}
+	return NewKMSWallet(ctx, kms.NewFromConfig(cfg), keyID, opts...)
+}
+
+// Sign implements blockchain.Wallet.
+// It has KMS sign the 32‑byte digest and returns the signature as
+// [R || S || V] with V in {0,1} and S in the lower half of the curve order,
+// as crypto.Sign does.
+func (w *KMSWallet) Sign(digest []byte) ([]byte, error) {
+	if len(digest) != 32 {
+		return nil, fmt.Errorf("kms wallet: digest is %d bytes, want 32", len(digest))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+	out, err := w.client.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(w.keyID),
+		Message:          digest,
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: kmstypes.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("kms wallet: sign: %w", classifyAWSKMSError(err))
+	}
+	sig, err := recoverKMSSignature(digest, out.Signature, w.publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("kms wallet: %w", err)
+	}
+	return sig, nil
+}
+
+// Address implements blockchain.Wallet.
+func (w *KMSWallet) Address() string {
+	return w.address.Hex()
+}
+
+// KeyID returns the KMS key the wallet signs with.
+func (w *KMSWallet) KeyID() string {
+	return w.keyID
+}
+
+// secp256k1HalfN is half the secp256k1 curve order. Ethereum rejects
+// signatures with S above it (EIP‑2), which KMS does not avoid.
+var secp256k1HalfN = new(big.Int).Rsh(crypto.S256().Params().N, 1)
+
+// classifyAWSKMSError wraps throttling and access errors of AWS KMS in
+// ErrKMSQuotaExceeded and ErrKMSPermissionDenied.
+func classifyAWSKMSError(err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	switch apiErr.ErrorCode() {
+	case "ThrottlingException", "LimitExceededException":
+		return fmt.Errorf("%w: %w", ErrKMSQuotaExceeded, err)
+	case "AccessDeniedException", "UnrecognizedClientException", "ExpiredTokenException":
+		return fmt.Errorf("%w: %w", ErrKMSPermissionDenied, err)
+	}
+	return err
+}
+
+// recoverKMSSignature converts a DER ECDSA signature from a KMS to the
+// 65‑byte format: S is normalized to the lower half of the curve order, and
+// the recovery id V is found by trying both values against publicKey, the
+// uncompressed point of the signing key. Errors wrap
+// ErrKMSInvalidSignature.
+func recoverKMSSignature(digest, der, publicKey []byte) ([]byte, error) {
+	var parsed struct {
+		R, S *big.Int
+	}
+	rest, err := asn1.Unmarshal(der, &parsed)
+	if err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("%w: invalid DER signature", ErrKMSInvalidSignature)
+	}
+	n := crypto.S256().Params().N
+	if parsed.R.Sign() <= 0 || parsed.S.Sign() <= 0 || parsed.R.Cmp(n) >= 0 || parsed.S.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("%w: signature values out of range", ErrKMSInvalidSignature)
+	}
+	if parsed.S.Cmp(secp256k1HalfN) > 0 {
+		parsed.S.Sub(n, parsed.S)
+	}
+
+	sig := make([]byte, 65)
+	parsed.R.FillBytes(sig[:32])
+	parsed.S.FillBytes(sig[32:64])
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		recovered, err := crypto.Ecrecover(digest, sig)
+		if err == nil && bytes.Equal(recovered, publicKey) {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: signature does not recover to the key's address", ErrKMSInvalidSignature)
+}
+
+// parseKMSPublicKey returns the uncompressed point of a DER
+// SubjectPublicKeyInfo, which x509 cannot parse for secp256k1.
+func parseKMSPublicKey(der []byte) ([]byte, error) {
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil || len(rest) > 0 {
+		return nil, errors.New("invalid DER public key")
+	}
+	publicKey, err := crypto.UnmarshalPubkey(info.PublicKey.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	return crypto.FromECDSAPub(publicKey), nil
+}
+
+// EOF: internal/blockchain/evm/kms.go
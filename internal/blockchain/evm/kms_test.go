@@ -0,0 +1,189 @@
+// Package evm_test tests the AWS KMS wallet against a fake KMS.
+//
+// File: internal/blockchain/evm/kms_test.go
+
+package evm_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// fakeKMS signs with a local key the way KMS does: DER signatures whose S
+// may be in the upper half of the curve order.
+type fakeKMS struct {
+	key      *ecdsa.PrivateKey
+	keySpec  kmstypes.KeySpec
+	highS    bool
+	signErr  error
+	sigOver  []byte // replaces the signature if set
+	lastSign *kms.SignInput
+}
+
+func newFakeKMS(t *testing.T) *fakeKMS {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return &fakeKMS{key: key, keySpec: kmstypes.KeySpecEccSecgP256k1}
+}
+
+func (f *fakeKMS) GetPublicKey(_ context.Context, in *kms.GetPublicKeyInput, _ ...func(*kms.Options)) (*kms.GetPublicKeyOutput, error) {
+	curve, err := asn1.Marshal(asn1.ObjectIdentifier{1, 3, 132, 0, 10}) // secp256k1
+	if err != nil {
+		return nil, err
+	}
+	point := crypto.FromECDSAPub(&f.key.PublicKey)
+	der, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}, // id-ecPublicKey
+			Parameters: asn1.RawValue{FullBytes: curve},
+		},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &kms.GetPublicKeyOutput{
+		KeyId:     in.KeyId,
+		KeySpec:   f.keySpec,
+		KeyUsage:  kmstypes.KeyUsageTypeSignVerify,
+		PublicKey: der,
+	}, nil
+}
+
+func (f *fakeKMS) Sign(_ context.Context, in *kms.SignInput, _ ...func(*kms.Options)) (*kms.SignOutput, error) {
+	f.lastSign = in
+	if f.signErr != nil {
+		return nil, f.signErr
+	}
+	if f.sigOver != nil {
+		return &kms.SignOutput{Signature: f.sigOver}, nil
+	}
+	sig, err := crypto.Sign(in.Message, f.key)
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if f.highS {
+		s.Sub(crypto.S256().Params().N, s)
+	}
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		return nil, err
+	}
+	return &kms.SignOutput{KeyId: in.KeyId, Signature: der}, nil
+}
+
+func TestKMSWallet_Sign(t *testing.T) {
+	fake := newFakeKMS(t)
+	wallet, err := evm.NewKMSWallet(context.Background(), fake, "alias/agent")
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(fake.key.PublicKey).Hex(), wallet.Address())
+	assert.Equal(t, "alias/agent", wallet.KeyID())
+
+	halfN := new(big.Int).Rsh(crypto.S256().Params().N, 1)
+	for _, highS := range []bool{false, true} {
+		fake.highS = highS
+		for i := 0; i < 8; i++ {
+			digest := crypto.Keccak256([]byte{byte(i)})
+			want, err := crypto.Sign(digest, fake.key)
+			require.NoError(t, err)
+
+			sig, err := wallet.Sign(digest)
+			require.NoError(t, err)
+			assert.Equal(t, want, sig, "highS=%v", highS)
+			assert.LessOrEqual(t, new(big.Int).SetBytes(sig[32:64]).Cmp(halfN), 0)
+		}
+	}
+
+	require.NotNil(t, fake.lastSign)
+	assert.Equal(t, "alias/agent", aws.ToString(fake.lastSign.KeyId))
+	assert.Equal(t, kmstypes.MessageTypeDigest, fake.lastSign.MessageType)
+	assert.Equal(t, kmstypes.SigningAlgorithmSpecEcdsaSha256, fake.lastSign.SigningAlgorithm)
+}
+
+func TestKMSWallet_SignsTransactions(t *testing.T) {
+	fake := newFakeKMS(t)
+	fake.highS = true
+	wallet, err := evm.NewKMSWallet(context.Background(), fake, "key")
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     7,
+		To:        &to,
+		Value:     big.NewInt(1000),
+		Gas:       21000,
+		GasFeeCap: big.NewInt(2e9),
+		GasTipCap: big.NewInt(1e9),
+	})
+	sig, err := wallet.Sign(signer.Hash(tx).Bytes())
+	require.NoError(t, err)
+	signed, err := tx.WithSignature(signer, sig)
+	require.NoError(t, err)
+	sender, err := types.Sender(signer, signed)
+	require.NoError(t, err)
+	assert.Equal(t, wallet.Address(), sender.Hex())
+}
+
+func TestKMSWallet_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	fake := newFakeKMS(t)
+	fake.keySpec = kmstypes.KeySpecEccNistP256
+	_, err := evm.NewKMSWallet(ctx, fake, "key")
+	assert.ErrorContains(t, err, "ECC_SECG_P256K1")
+
+	fake = newFakeKMS(t)
+	wallet, err := evm.NewKMSWallet(ctx, fake, "key")
+	require.NoError(t, err)
+	digest := crypto.Keccak256([]byte("hello"))
+
+	_, err = wallet.Sign(digest[:31])
+	assert.ErrorContains(t, err, "want 32")
+
+	fake.signErr = errors.New("AccessDeniedException")
+	_, err = wallet.Sign(digest)
+	assert.ErrorContains(t, err, "AccessDeniedException")
+	fake.signErr = nil
+
+	fake.sigOver = []byte{0x30, 0x01}
+	_, err = wallet.Sign(digest)
+	assert.ErrorContains(t, err, "invalid DER")
+
+	// A signature by another key matches neither recovery id.
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sig, err := crypto.Sign(digest, other)
+	require.NoError(t, err)
+	fake.sigOver, err = asn1.Marshal(struct{ R, S *big.Int }{
+		new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]),
+	})
+	require.NoError(t, err)
+	_, err = wallet.Sign(digest)
+	assert.ErrorContains(t, err, "does not recover")
+}
+
+// EOF: internal/blockchain/evm/kms_test.go
@@ -0,0 +1,250 @@
+// Package evm prices the L1 data fee that rollups charge on top of gas.
+//
+// File: internal/blockchain/evm/l2fees.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// L2Stack names the rollup stack of a chain, which decides how its L1 data
+// fee is priced.
+type L2Stack string
+
+const (
+	// L2None is an L1 or a chain without a separate data fee (default).
+	L2None L2Stack = ""
+	// L2OPStack is an OP Stack chain such as Optimism or Base; the data fee
+	// is quoted by the GasPriceOracle predeploy.
+	L2OPStack L2Stack = "op-stack"
+	// L2Arbitrum is an Arbitrum chain; the data fee is quoted by the
+	// NodeInterface precompile.
+	L2Arbitrum L2Stack = "arbitrum"
+)
+
+// valid reports whether s is a known stack.
+func (s L2Stack) valid() bool {
+	switch s {
+	case L2None, L2OPStack, L2Arbitrum:
+		return true
+	default:
+		return false
+	}
+}
+
+// Rollup fee contracts.
+var (
+	// OPGasPriceOracle is the OP Stack GasPriceOracle predeploy.
+	OPGasPriceOracle = common.HexToAddress("0x420000000000000000000000000000000000000F")
+	// ArbitrumNodeInterface is Arbitrum's NodeInterface precompile. It only
+	// exists for eth_call and eth_estimateGas.
+	ArbitrumNodeInterface = common.HexToAddress("0x00000000000000000000000000000000000000C8")
+
+	opGetL1FeeSelector               = crypto.Keccak256([]byte("getL1Fee(bytes)"))[:4]
+	arbGasEstimateComponentsSelector = crypto.Keccak256([]byte("gasEstimateComponents(address,bool,bytes)"))[:4]
+)
+
+// L2FeeEstimator prices the L1 data fee of a rollup transaction.
+type L2FeeEstimator interface {
+	// L1DataFee returns the data fee in wei that tx, sent by from, pays on
+	// top of its execution gas, and how much of the node's gas estimate
+	// for tx already covers that fee (0 if estimates exclude it).
+	L1DataFee(ctx context.Context, from common.Address, tx *types.Transaction) (fee *big.Int, l1Gas uint64, err error)
+}
+
+// WithL2Stack sets the chain's rollup stack, so that EstimateTotalCost
+// includes the L1 data fee. NewClient rejects unknown stacks.
+func WithL2Stack(stack L2Stack) ClientOption {
+	return func(c *Client) {
+		c.l2Stack = stack
+	}
+}
+
+// newL2FeeEstimator returns the estimator for stack, or nil if the chain
+// charges no separate data fee.
+func newL2FeeEstimator(c *Client, stack L2Stack) L2FeeEstimator {
+	switch stack {
+	case L2OPStack:
+		return opStackFees{client: c}
+	case L2Arbitrum:
+		return arbitrumFees{client: c}
+	default:
+		return nil
+	}
+}
+
+// L2FeeEstimator returns the client's data fee estimator, or nil on chains
+// without an L1 data fee.
+func (c *Client) L2FeeEstimator() L2FeeEstimator {
+	return c.l2fees
+}
+
+// opStackFees asks the GasPriceOracle for getL1Fee(unsigned tx).
+type opStackFees struct {
+	client *Client
+}
+
+func (f opStackFees) L1DataFee(ctx context.Context, from common.Address, tx *types.Transaction) (*big.Int, uint64, error) {
+	unsigned, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, 0, fmt.Errorf("encode tx: %w", err)
+	}
+	args, err := abi.Arguments{{Type: abiType("bytes")}}.Pack(unsigned)
+	if err != nil {
+		return nil, 0, err
+	}
+	res, err := f.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &OPGasPriceOracle,
+		Data: append(append([]byte(nil), opGetL1FeeSelector...), args...),
+	}, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("getL1Fee: %w", err)
+	}
+	if len(res) < 32 {
+		return nil, 0, fmt.Errorf("getL1Fee: short result of %d bytes", len(res))
+	}
+	return new(big.Int).SetBytes(res[:32]), 0, nil
+}
+
+// arbitrumFees asks NodeInterface.gasEstimateComponents for the gas that
+// pays for L1 data. Arbitrum's eth_estimateGas already includes it.
+type arbitrumFees struct {
+	client *Client
+}
+
+func (f arbitrumFees) L1DataFee(ctx context.Context, from common.Address, tx *types.Transaction) (*big.Int, uint64, error) {
+	var to common.Address
+	if tx.To() != nil {
+		to = *tx.To()
+	}
+	args, err := abi.Arguments{
+		{Type: abiType("address")}, {Type: abiType("bool")}, {Type: abiType("bytes")},
+	}.Pack(to, tx.To() == nil, tx.Data())
+	if err != nil {
+		return nil, 0, err
+	}
+	res, err := f.client.CallContract(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    &ArbitrumNodeInterface,
+		Value: tx.Value(),
+		Data:  append(append([]byte(nil), arbGasEstimateComponentsSelector...), args...),
+	}, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("gasEstimateComponents: %w", err)
+	}
+	// (uint64 gasEstimate, uint64 gasEstimateForL1, uint256 baseFee, uint256 l1BaseFeeEstimate)
+	if len(res) < 4*32 {
+		return nil, 0, fmt.Errorf("gasEstimateComponents: short result of %d bytes", len(res))
+	}
+	l1Gas := new(big.Int).SetBytes(res[32:64])
+	baseFee := new(big.Int).SetBytes(res[64:96])
+	if !l1Gas.IsUint64() {
+		return nil, 0, errors.New("gasEstimateComponents: L1 gas out of range")
+	}
+	return new(big.Int).Mul(l1Gas, baseFee), l1Gas.Uint64(), nil
+}
+
+// abiType returns the ABI type t, which must be a valid elementary type.
+func abiType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// EstimateTotalCost estimates what tx will cost in fees: its execution gas
+// at the current (or given) gas price, plus on rollups configured with
+// WithL2Stack the L1 data fee, which often dominates there. The value sent
+// is not included. It implements blockchain.CostEstimator.
+func (g *EVMGateway) EstimateTotalCost(ctx context.Context, tx *blockchain.Transaction) (*blockchain.TxCost, error) {
+	from := g.senderAddress(tx.From)
+	var to *common.Address
+	if tx.To != nil {
+		addr, err := g.resolveAddress(ctx, *tx.To)
+		if err != nil {
+			return nil, fmt.Errorf("EstimateTotalCost: %w", err)
+		}
+		to = &addr
+	}
+	value := tx.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	gas := tx.Gas
+	if gas == 0 {
+		estimate, err := g.client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: to, Value: value, Data: tx.Data})
+		if err != nil {
+			return nil, fmt.Errorf("EstimateTotalCost: estimate gas: %w", err)
+		}
+		gas = estimate
+	}
+
+	// The fee cap bounds what an EIP‑1559 transaction can pay per gas.
+	price := tx.GasPrice
+	if price == nil {
+		price = tx.GasFeeCap
+	}
+	if price == nil {
+		suggested, err := g.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("EstimateTotalCost: gas price: %w", err)
+		}
+		price = suggested
+	}
+
+	dataFee := new(big.Int)
+	execGas := gas
+	if est := g.client.l2fees; est != nil {
+		chainID, err := g.client.ChainID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("EstimateTotalCost: chain id: %w", err)
+		}
+		var nonce uint64
+		if tx.Nonce != nil {
+			nonce = *tx.Nonce
+		}
+		unsigned := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: price,
+			GasFeeCap: price,
+			Gas:       gas,
+			To:        to,
+			Value:     value,
+			Data:      tx.Data,
+		})
+		fee, l1Gas, err := est.L1DataFee(ctx, from, unsigned)
+		if err != nil {
+			return nil, fmt.Errorf("EstimateTotalCost: L1 data fee: %w", err)
+		}
+		dataFee = fee
+		if l1Gas < execGas {
+			execGas -= l1Gas
+		}
+	}
+
+	execFee := new(big.Int).Mul(new(big.Int).SetUint64(execGas), price)
+	return &blockchain.TxCost{
+		Gas:          gas,
+		GasPrice:     new(big.Int).Set(price),
+		ExecutionFee: execFee,
+		DataFee:      dataFee,
+		Total:        new(big.Int).Add(execFee, dataFee),
+	}, nil
+}
+
+// EOF: internal/blockchain/evm/l2fees.go
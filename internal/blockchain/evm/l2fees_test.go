@@ -0,0 +1,149 @@
+// Package evm_test tests total cost estimates including rollup data fees.
+//
+// File: internal/blockchain/evm/l2fees_test.go
+
+package evm_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// callArgs decodes the call object of an eth_call request.
+func callArgs(t *testing.T, params []json.RawMessage) (to common.Address, data []byte) {
+	t.Helper()
+	var call struct {
+		To    common.Address `json:"to"`
+		Input hexutil.Bytes  `json:"input"`
+		Data  hexutil.Bytes  `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(params[0], &call))
+	if call.Input != nil {
+		return call.To, call.Input
+	}
+	return call.To, call.Data
+}
+
+// word ABI‑encodes v as a uint256.
+func word(v int64) []byte {
+	return common.LeftPadBytes(big.NewInt(v).Bytes(), 32)
+}
+
+func TestEVMGateway_EstimateTotalCost(t *testing.T) {
+	recipient := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	results := func(call func([]json.RawMessage) interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"eth_chainId":     "0xa",
+			"eth_estimateGas": "0x5208",     // 21000
+			"eth_gasPrice":    "0x3b9aca00", // 1 gwei
+			"eth_call":        call,
+		}
+	}
+	noCall := func([]json.RawMessage) interface{} {
+		t.Error("unexpected eth_call")
+		return "0x"
+	}
+
+	t.Run("L1", func(t *testing.T) {
+		client := newFeeClient(t, results(noCall))
+		gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, nil)
+		cost, err := gateway.EstimateTotalCost(context.Background(), &blockchain.Transaction{To: &recipient, Value: big.NewInt(1)})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(21000), cost.Gas)
+		assert.Equal(t, big.NewInt(1e9), cost.GasPrice)
+		assert.Equal(t, big.NewInt(21000e9), cost.ExecutionFee)
+		assert.Zero(t, cost.DataFee.Sign())
+		assert.Equal(t, cost.ExecutionFee, cost.Total)
+	})
+
+	t.Run("given gas and price", func(t *testing.T) {
+		client := newFeeClient(t, map[string]interface{}{}) // no RPC needed
+		gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, nil)
+		cost, err := gateway.EstimateTotalCost(context.Background(), &blockchain.Transaction{
+			To: &recipient, Gas: 50000, GasPrice: big.NewInt(2e9),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(2e9), cost.GasPrice)
+		assert.Equal(t, big.NewInt(100000e9), cost.Total)
+	})
+
+	t.Run("op-stack", func(t *testing.T) {
+		var quoted *types.Transaction
+		client := newFeeClient(t, results(func(params []json.RawMessage) interface{} {
+			to, data := callArgs(t, params)
+			assert.Equal(t, evm.OPGasPriceOracle, to)
+			assert.True(t, bytes.HasPrefix(data, crypto.Keccak256([]byte("getL1Fee(bytes)"))[:4]))
+			// bytes argument: offset, length, payload
+			n := new(big.Int).SetBytes(data[4+32 : 4+64]).Int64()
+			quoted = new(types.Transaction)
+			assert.NoError(t, quoted.UnmarshalBinary(data[4+64:4+64+n]))
+			return hexutil.Encode(word(5e13))
+		}), evm.WithL2Stack(evm.L2OPStack))
+		gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, nil)
+
+		cost, err := gateway.EstimateTotalCost(context.Background(), &blockchain.Transaction{
+			To: &recipient, Value: big.NewInt(1), Data: []byte{1, 2, 3},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(21000e9), cost.ExecutionFee)
+		assert.Equal(t, big.NewInt(5e13), cost.DataFee)
+		assert.Equal(t, big.NewInt(21000e9+5e13), cost.Total)
+
+		require.NotNil(t, quoted)
+		assert.Equal(t, common.HexToAddress(recipient), *quoted.To())
+		assert.Equal(t, []byte{1, 2, 3}, quoted.Data())
+		assert.Equal(t, big.NewInt(10), quoted.ChainId())
+	})
+
+	t.Run("arbitrum", func(t *testing.T) {
+		client := newFeeClient(t, results(func(params []json.RawMessage) interface{} {
+			to, data := callArgs(t, params)
+			assert.Equal(t, evm.ArbitrumNodeInterface, to)
+			assert.True(t, bytes.HasPrefix(data, crypto.Keccak256([]byte("gasEstimateComponents(address,bool,bytes)"))[:4]))
+			assert.Equal(t, common.HexToAddress(recipient), common.BytesToAddress(data[4:36]))
+			// gasEstimate, gasEstimateForL1, baseFee, l1BaseFeeEstimate
+			return hexutil.Encode(bytes.Join([][]byte{word(21000), word(8000), word(1e8), word(3e9)}, nil))
+		}), evm.WithL2Stack(evm.L2Arbitrum))
+		gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, nil)
+
+		cost, err := gateway.EstimateTotalCost(context.Background(), &blockchain.Transaction{To: &recipient})
+		require.NoError(t, err)
+		// eth_estimateGas already includes the 8000 L1 gas.
+		assert.Equal(t, big.NewInt(13000e9), cost.ExecutionFee)
+		assert.Equal(t, big.NewInt(8000*1e8), cost.DataFee)
+		assert.Equal(t, big.NewInt(13000e9+8000*1e8), cost.Total)
+	})
+
+	t.Run("oracle failure", func(t *testing.T) {
+		client := newFeeClient(t, map[string]interface{}{
+			"eth_chainId":     "0xa",
+			"eth_estimateGas": "0x5208",
+			"eth_gasPrice":    "0x3b9aca00",
+		}, evm.WithL2Stack(evm.L2OPStack))
+		gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, nil)
+		_, err := gateway.EstimateTotalCost(context.Background(), &blockchain.Transaction{To: &recipient})
+		assert.ErrorContains(t, err, "L1 data fee")
+	})
+}
+
+func TestNewClient_RejectsUnknownL2Stack(t *testing.T) {
+	srv := newFakeNode(t, map[string]interface{}{})
+	_, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{}, nil, 0, evm.WithL2Stack("zk"))
+	assert.ErrorContains(t, err, "unknown l2 stack")
+}
+
+// EOF: internal/blockchain/evm/l2fees_test.go
@@ -0,0 +1,65 @@
+// Package evm defers dialling the RPC endpoint until it is first needed.
+//
+// File: internal/blockchain/evm/lazy.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ErrNotConnected indicates a lazily connected client that could not reach
+// its endpoint yet. The next call dials again.
+var ErrNotConnected = errors.New("rpc endpoint not connected")
+
+// WithLazyConnect makes NewClient succeed even if the endpoint cannot be
+// dialled. The connection is then established by the first call (or health
+// probe) that reaches the endpoint; until then calls fail with
+// ErrNotConnected.
+func WithLazyConnect() ClientOption {
+	return func(c *Client) {
+		c.lazy = true
+	}
+}
+
+// Connected reports whether the client has a connection to its endpoint.
+func (c *Client) Connected() bool {
+	return c.eth() != nil
+}
+
+// Connected reports whether the gateway's client is connected (see
+// WithLazyConnect).
+func (g *EVMGateway) Connected() bool {
+	return g.client.Connected()
+}
+
+// connect dials the endpoint if the client has no connection yet.
+// Concurrent callers share one dial.
+func (c *Client) connect(ctx context.Context) error {
+	if c.eth() != nil {
+		return nil
+	}
+	c.dialMu.Lock()
+	defer c.dialMu.Unlock()
+	if c.eth() != nil {
+		return nil
+	}
+
+	dialCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	ec, err := ethclient.DialContext(dialCtx, c.rpcURL)
+	if err != nil {
+		return fmt.Errorf("%w: dial %s: %v", ErrNotConnected, c.rpcURL, err)
+	}
+	c.ecMu.Lock()
+	c.ec = ec
+	c.ecMu.Unlock()
+	c.logger.Info("rpc endpoint connected", map[string]interface{}{"chain": c.chain})
+	return nil
+}
+
+// EOF: internal/blockchain/evm/lazy.go
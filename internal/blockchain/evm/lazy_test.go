@@ -0,0 +1,98 @@
+// Package evm_test tests lazily connected clients.
+//
+// File: internal/blockchain/evm/lazy_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// ethService answers eth_chainId and eth_blockNumber.
+type ethService struct{}
+
+func (ethService) ChainId() *hexutil.Big       { return (*hexutil.Big)(big.NewInt(1)) }
+func (ethService) BlockNumber() hexutil.Uint64 { return 100 }
+
+// freeAddr returns a local address nothing listens on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
+
+// serveWS starts a websocket RPC node on addr.
+func serveWS(t *testing.T, addr string) {
+	t.Helper()
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", ethService{}))
+	l, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+	httpSrv := &http.Server{Handler: server.WebsocketHandler([]string{"*"})}
+	go httpSrv.Serve(l)
+	t.Cleanup(func() {
+		httpSrv.Close()
+		server.Stop()
+	})
+}
+
+func TestClient_LazyConnect(t *testing.T) {
+	addr := freeAddr(t)
+	url := "ws://" + addr
+	ctx := context.Background()
+
+	_, err := evm.NewClient(ctx, url, &observe.NoopLogger{}, nil, time.Second)
+	require.Error(t, err, "eager clients fail on an unreachable endpoint")
+
+	client, err := evm.NewClient(ctx, url, &observe.NoopLogger{},
+		&evm.RetryConfig{MaxAttempts: 3}, time.Second, evm.WithLazyConnect())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	assert.False(t, client.Connected())
+
+	_, err = client.BlockNumber(ctx)
+	assert.ErrorIs(t, err, evm.ErrNotConnected)
+	assert.ErrorIs(t, client.Ping(ctx), evm.ErrNotConnected)
+
+	serveWS(t, addr)
+	n, err := client.BlockNumber(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), n)
+	assert.True(t, client.Connected())
+	assert.NoError(t, client.Ping(ctx))
+}
+
+func TestEVMGateway_LazyConnectHealthCheck(t *testing.T) {
+	addr := freeAddr(t)
+	ctx := context.Background()
+	gateway, err := evm.NewEVMGateway(ctx, "ws://"+addr, &observe.NoopLogger{}, nil, time.Second, nil,
+		evm.WithLazyConnect(), evm.WithExpectedChainID(1))
+	require.NoError(t, err, "the chain id check is deferred")
+	t.Cleanup(func() { gateway.Close() })
+
+	assert.ErrorIs(t, gateway.CheckHealth(ctx), evm.ErrNotConnected)
+	assert.False(t, gateway.Connected())
+
+	serveWS(t, addr)
+	require.NoError(t, gateway.CheckHealth(ctx))
+	assert.True(t, gateway.Connected())
+	assert.Equal(t, evm.HealthHealthy, gateway.Status().State)
+}
+
+// EOF: internal/blockchain/evm/lazy_test.go
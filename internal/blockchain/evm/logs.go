@@ -0,0 +1,87 @@
+// Package evm queries contract logs with eth_getLogs.
+//
+// File: internal/blockchain/evm/logs.go
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// LogFilter selects logs for GetLogs.
+type LogFilter struct {
+	// Addresses limits the query to logs from these contracts, given as hex
+	// addresses or ENS names; empty matches every contract.
+	Addresses []string
+	// FromBlock and ToBlock bound the range, inclusive. Empty means latest,
+	// as in eth_getLogs; "earliest" is block 0.
+	FromBlock blockchain.BlockNumber
+	ToBlock   blockchain.BlockNumber
+	// Topics filters by position: Topics[0] matches topic0 (the event ID),
+	// and so on. An empty position matches anything; several hashes in one
+	// position match any of them.
+	Topics [][]common.Hash
+}
+
+// FilterLogs runs eth_getLogs.
+func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	result, err := c.withRetry(ctx, "FilterLogs", func(ctx context.Context) (interface{}, error) {
+		return c.eth().FilterLogs(ctx, query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]types.Log), nil
+}
+
+// GetLogs returns the logs matching filter in the order the node returns
+// them, by block and then log index. Providers cap the block range or the
+// result size of a single query; split large ranges.
+func (g *EVMGateway) GetLogs(ctx context.Context, filter *LogFilter) ([]types.Log, error) {
+	query := ethereum.FilterQuery{Topics: filter.Topics}
+	for _, a := range filter.Addresses {
+		addr, err := g.resolveAddress(ctx, a)
+		if err != nil {
+			return nil, fmt.Errorf("GetLogs: %w", err)
+		}
+		query.Addresses = append(query.Addresses, addr)
+	}
+	var err error
+	if query.FromBlock, err = logBlock(filter.FromBlock); err != nil {
+		return nil, fmt.Errorf("GetLogs: from: %w", err)
+	}
+	if query.ToBlock, err = logBlock(filter.ToBlock); err != nil {
+		return nil, fmt.Errorf("GetLogs: to: %w", err)
+	}
+	logs, err := g.client.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("GetLogs: %w", err)
+	}
+	return logs, nil
+}
+
+// logBlock converts a block number for a filter query. Unlike
+// parseBlockNumber it keeps tags distinct: a nil FromBlock would mean
+// block 0 to ethclient.
+func logBlock(block blockchain.BlockNumber) (*big.Int, error) {
+	switch block {
+	case "", blockchain.BlockNumberLatest:
+		return big.NewInt(int64(rpc.LatestBlockNumber)), nil
+	case blockchain.BlockNumberPending:
+		return big.NewInt(int64(rpc.PendingBlockNumber)), nil
+	case blockchain.BlockNumberEarliest:
+		return big.NewInt(0), nil
+	}
+	return parseBlockNumber(block)
+}
+
+// EOF: internal/blockchain/evm/logs.go
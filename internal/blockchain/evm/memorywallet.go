@@ -0,0 +1,73 @@
+// Package evm provides an in‑memory wallet for tests and local devnets.
+//
+// File: internal/blockchain/evm/memorywallet.go
+
+package evm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MemoryWallet implements TxSigner with a key held only in memory; nothing
+// is read from or written to disk. It is meant for tests and local devnets
+// such as Anvil or Hardhat, not for keys that hold real funds.
+type MemoryWallet struct {
+	address    common.Address
+	privateKey *ecdsa.PrivateKey
+}
+
+// NewMemoryWallet creates a wallet with a random key. Its Address can be
+// funded in a test genesis allocation.
+func NewMemoryWallet() (*MemoryWallet, error) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("memory wallet: generate key: %w", err)
+	}
+	return newMemoryWallet(privateKey), nil
+}
+
+// NewMemoryWalletFromHex creates a wallet with the private key hexKey,
+// with or without 0x, such as one of the prefunded accounts of Anvil.
+func NewMemoryWalletFromHex(hexKey string) (*MemoryWallet, error) {
+	privateKey, err := parsePrivateKeyHex(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("memory wallet: %w", err)
+	}
+	return newMemoryWallet(privateKey), nil
+}
+
+func newMemoryWallet(privateKey *ecdsa.PrivateKey) *MemoryWallet {
+	return &MemoryWallet{address: crypto.PubkeyToAddress(privateKey.PublicKey), privateKey: privateKey}
+}
+
+// Sign implements blockchain.Wallet.
+func (w *MemoryWallet) Sign(digest []byte) ([]byte, error) {
+	sig, err := crypto.Sign(digest, w.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("memory wallet: sign: %w", err)
+	}
+	return sig, nil
+}
+
+// SignTx implements TxSigner.
+func (w *MemoryWallet) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), w.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("memory wallet: sign transaction: %w", err)
+	}
+	return signed, nil
+}
+
+// Address implements blockchain.Wallet.
+func (w *MemoryWallet) Address() string {
+	return w.address.Hex()
+}
+
+// EOF: internal/blockchain/evm/memorywallet.go
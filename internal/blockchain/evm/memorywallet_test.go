@@ -0,0 +1,60 @@
+// Package evm_test tests the in‑memory wallet.
+//
+// File: internal/blockchain/evm/memorywallet_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// anvilKey is the first prefunded account of Anvil and Hardhat.
+const anvilKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+func TestMemoryWallet_SendsOnSimulatedChain(t *testing.T) {
+	wallet, err := evm.NewMemoryWallet()
+	require.NoError(t, err)
+	var _ evm.TxSigner = wallet
+
+	sim := simulated.NewBackend(types.GenesisAlloc{common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)}})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+
+	ctx := context.Background()
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	txHash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1000)})
+	require.NoError(t, err)
+	sim.Commit()
+	receipt, err := sim.Client().TransactionReceipt(ctx, common.HexToHash(txHash))
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	other, err := evm.NewMemoryWallet()
+	require.NoError(t, err)
+	assert.NotEqual(t, wallet.Address(), other.Address(), "keys are random")
+}
+
+func TestMemoryWallet_FromHex(t *testing.T) {
+	wallet, err := evm.NewMemoryWalletFromHex(anvilKey)
+	require.NoError(t, err)
+	assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", wallet.Address())
+
+	_, err = evm.NewMemoryWalletFromHex("0xzz0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "bec39a17", "errors do not quote the key")
+	_, err = evm.NewMemoryWalletFromHex("0x1234")
+	assert.ErrorContains(t, err, "2 bytes, want 32")
+}
+
+// EOF: internal/blockchain/evm/memorywallet_test.go
@@ -0,0 +1,80 @@
+// Package evm provides EIP‑191 personal message signing and verification.
+//
+// File: internal/blockchain/evm/message.go
+
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// HashMessage returns the EIP‑191 digest of msg, the keccak256 hash of
+// "\x19Ethereum Signed Message:\n" + len(msg) + msg, as used by
+// personal_sign.
+func HashMessage(msg []byte) []byte {
+	return accounts.TextHash(msg)
+}
+
+// SignMessage signs msg with wallet using the EIP‑191 personal_sign
+// prefix and returns the 65‑byte signature as 0x‑hex with V set to 27 or
+// 28, the form wallets and contracts expect.
+func SignMessage(wallet blockchain.Wallet, msg []byte) (string, error) {
+	if wallet == nil {
+		return "", errors.New("SignMessage: no wallet configured")
+	}
+	sig, err := wallet.Sign(HashMessage(msg))
+	if err != nil {
+		return "", fmt.Errorf("SignMessage: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return "", fmt.Errorf("SignMessage: wallet returned %d‑byte signature", len(sig))
+	}
+	out := make([]byte, crypto.SignatureLength)
+	copy(out, sig)
+	if out[64] < 27 {
+		out[64] += 27
+	}
+	return hexutil.Encode(out), nil
+}
+
+// VerifyMessage reports whether sig, a 0x‑hex EIP‑191 signature over msg,
+// was produced by address. V may be 27/28 or 0/1. A malformed address or
+// signature is an error; a valid signature by another signer returns false.
+func VerifyMessage(address string, msg []byte, sig string) (bool, error) {
+	raw, err := hexutil.Decode(strings.TrimSpace(sig))
+	if err != nil {
+		return false, fmt.Errorf("VerifyMessage: decode signature: %w", err)
+	}
+	ok, err := VerifySignature(address, HashMessage(msg), raw)
+	if err != nil {
+		return false, fmt.Errorf("VerifyMessage: %w", err)
+	}
+	return ok, nil
+}
+
+// SignMessage signs msg with the gateway's wallet using the EIP‑191
+// personal_sign prefix. See SignMessage.
+func (g *EVMGateway) SignMessage(msg []byte) (string, error) {
+	if g.wallet == nil {
+		return "", errors.New("SignMessage: no wallet configured, read‑only mode")
+	}
+	sig, err := SignMessage(g.wallet, msg)
+	if err != nil {
+		return "", err
+	}
+	g.logger.Info("message signed", map[string]interface{}{
+		"address": g.wallet.Address(),
+		"length":  len(msg),
+	})
+	return sig, nil
+}
+
+// EOF: internal/blockchain/evm/message.go
@@ -0,0 +1,94 @@
+// Package evm_test tests EIP‑191 message signing and verification.
+//
+// File: internal/blockchain/evm/message_test.go
+
+package evm_test
+
+import (
+	"crypto/ecdsa"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// keyWallet signs with a fixed private key.
+type keyWallet struct{ key *ecdsa.PrivateKey }
+
+func (w keyWallet) Sign(digest []byte) ([]byte, error) { return crypto.Sign(digest, w.key) }
+func (w keyWallet) Address() string                    { return crypto.PubkeyToAddress(w.key.PublicKey).Hex() }
+
+func TestSignMessage_KnownVector(t *testing.T) {
+	// web3.eth.accounts.sign("Some data", key) from the web3.js documentation.
+	key, err := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	require.NoError(t, err)
+	w := keyWallet{key}
+
+	sig, err := evm.SignMessage(w, []byte("Some data"))
+	require.NoError(t, err)
+	assert.Equal(t, "0xb91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c", sig)
+
+	ok, err := evm.VerifyMessage(w.Address(), []byte("Some data"), sig)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestSignMessage_RoundTripKeystore(t *testing.T) {
+	ks, err := evm.NewKeystore(filepath.Join(t.TempDir(), "msg.key"), "pass")
+	require.NoError(t, err)
+	msg := []byte("login nonce 42")
+
+	sig, err := evm.SignMessage(ks, msg)
+	require.NoError(t, err)
+	raw, err := hexutil.Decode(sig)
+	require.NoError(t, err)
+	require.Len(t, raw, 65)
+	assert.Contains(t, []byte{27, 28}, raw[64])
+
+	ok, err := evm.VerifyMessage(ks.Address(), msg, sig)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Lower‑case addresses and V of 0/1 are accepted too.
+	raw[64] -= 27
+	ok, err = evm.VerifyMessage(strings.ToLower(ks.Address()), msg, hexutil.Encode(raw))
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = evm.VerifyMessage(ks.Address(), []byte("login nonce 43"), sig)
+	require.NoError(t, err)
+	assert.False(t, ok, "tampered message must not verify")
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ok, err = evm.VerifyMessage(crypto.PubkeyToAddress(other.PublicKey).Hex(), msg, sig)
+	require.NoError(t, err)
+	assert.False(t, ok, "signature must not verify for another address")
+}
+
+func TestVerifyMessage_Malformed(t *testing.T) {
+	sig := "0x" + strings.Repeat("11", 64) + "1b"
+	addr := "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"
+
+	_, err := evm.VerifyMessage("not-an-address", nil, sig)
+	assert.Error(t, err)
+	_, err = evm.VerifyMessage(addr, nil, "0x1234")
+	assert.Error(t, err)
+	_, err = evm.VerifyMessage(addr, nil, "zz")
+	assert.Error(t, err)
+	_, err = evm.VerifyMessage(addr, nil, "0x"+strings.Repeat("11", 64)+"05")
+	assert.Error(t, err)
+}
+
+func TestSignMessage_NoWallet(t *testing.T) {
+	_, err := evm.SignMessage(nil, []byte("x"))
+	assert.Error(t, err)
+}
+
+// EOF: internal/blockchain/evm/message_test.go
@@ -0,0 +1,98 @@
+// Package evm records per‑operation RPC metrics for the client.
+//
+// File: internal/blockchain/evm/metrics.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// Metric names recorded by Client. Every metric carries "operation" and
+// "chain" labels; the latency histogram adds "outcome".
+const (
+	MetricRPCDuration = "rpc_duration_seconds"
+	MetricRPCRetries  = "rpc_retries_total"
+	MetricRPCFailures = "rpc_failures_total"
+)
+
+// MetricEventsDropped counts events WatchEvents dropped because the
+// consumer fell behind. It carries "event" and "chain" labels.
+const MetricEventsDropped = "events_dropped_total"
+
+// Call outcomes used as the "outcome" label.
+const (
+	OutcomeSuccess  = "success"
+	OutcomeFailure  = "failure"  // the node or transport failed the call
+	OutcomeRejected = "rejected" // refused locally (circuit open, rate limit)
+	OutcomeCanceled = "canceled" // the caller's context ended first
+)
+
+// metricsEnabled reports whether metrics go anywhere. Callers check it
+// before building label maps so NoopMetrics costs no allocations.
+func metricsEnabled(m observe.Metrics) bool {
+	_, noop := m.(*observe.NoopMetrics)
+	return m != nil && !noop
+}
+
+// observeCall records the latency and, on failure, the final failure of a
+// call that started at start.
+func (c *Client) observeCall(ctx context.Context, operation string, start time.Time, err error) {
+	if !c.metricsOn {
+		return
+	}
+	outcome := callOutcome(ctx, err)
+	c.metrics.Histogram(MetricRPCDuration, c.clock.Now().Sub(start).Seconds(), map[string]string{
+		"operation": operation,
+		"chain":     c.chain,
+		"outcome":   outcome,
+	})
+	if outcome == OutcomeFailure || outcome == OutcomeRejected {
+		c.metrics.Counter(MetricRPCFailures, 1, map[string]string{
+			"operation": operation,
+			"chain":     c.chain,
+		})
+	}
+}
+
+// countRetry records that operation is about to be attempted again.
+func (c *Client) countRetry(operation string) {
+	if !c.metricsOn {
+		return
+	}
+	c.metrics.Counter(MetricRPCRetries, 1, map[string]string{
+		"operation": operation,
+		"chain":     c.chain,
+	})
+}
+
+// countDroppedEvent records that WatchEvents dropped an event.
+func (c *Client) countDroppedEvent(event string) {
+	if !c.metricsOn {
+		return
+	}
+	c.metrics.Counter(MetricEventsDropped, 1, map[string]string{
+		"event": event,
+		"chain": c.chain,
+	})
+}
+
+// callOutcome maps a call result to its outcome label.
+func callOutcome(ctx context.Context, err error) string {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case ctx.Err() != nil:
+		return OutcomeCanceled
+	case errors.Is(err, ErrCircuitOpen), errors.Is(err, ErrRateLimitWait):
+		return OutcomeRejected
+	default:
+		return OutcomeFailure
+	}
+}
+
+// EOF: internal/blockchain/evm/metrics.go
@@ -0,0 +1,96 @@
+// Package evm_test tests RPC client metrics.
+//
+// File: internal/blockchain/evm/metrics_test.go
+
+package evm_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+func newMetricsClient(t *testing.T, url string, metrics observe.Metrics) (*evm.Client, *fakeClock) {
+	t.Helper()
+	retry := &evm.RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		BackoffFactor:  2,
+	}
+	client, err := evm.NewClient(context.Background(), url, &observe.NoopLogger{}, retry, time.Second,
+		evm.WithChainName("testnet"),
+		evm.WithMetrics(metrics),
+	)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	clock := &fakeClock{}
+	client.SetClock(clock)
+	return client, clock
+}
+
+func TestClientMetrics_Success(t *testing.T) {
+	srv := newToggleServer(t)
+	metrics := newFakeMetrics()
+	client, _ := newMetricsClient(t, srv.URL, metrics)
+
+	_, err := client.ChainID(context.Background())
+	require.NoError(t, err)
+
+	assert.Len(t, metrics.histograms[evm.MetricRPCDuration], 1)
+	assert.Contains(t, metrics.labels, map[string]string{
+		"operation": "ChainID",
+		"chain":     "testnet",
+		"outcome":   evm.OutcomeSuccess,
+	})
+	assert.Zero(t, metrics.counters[evm.MetricRPCRetries])
+	assert.Zero(t, metrics.counters[evm.MetricRPCFailures])
+}
+
+func TestClientMetrics_RetriesAndFailure(t *testing.T) {
+	srv := newStatusServer(t, http.StatusServiceUnavailable, "")
+	metrics := newFakeMetrics()
+	client, _ := newMetricsClient(t, srv.URL, metrics)
+
+	_, err := client.ChainID(context.Background())
+	require.Error(t, err)
+
+	assert.Equal(t, 2.0, metrics.counters[evm.MetricRPCRetries])
+	assert.Equal(t, 1.0, metrics.counters[evm.MetricRPCFailures])
+	assert.Len(t, metrics.histograms[evm.MetricRPCDuration], 1)
+	assert.Contains(t, metrics.labels, map[string]string{
+		"operation": "ChainID",
+		"chain":     "testnet",
+		"outcome":   evm.OutcomeFailure,
+	})
+	assert.Contains(t, metrics.labels, map[string]string{
+		"operation": "ChainID",
+		"chain":     "testnet",
+	})
+}
+
+func TestClientMetrics_CircuitRejection(t *testing.T) {
+	srv := newToggleServer(t)
+	srv.down.Store(true)
+	metrics := newFakeMetrics()
+	client, _ := newBreakerClient(t, srv.URL, metrics)
+
+	for i := 0; i < 3; i++ {
+		_, _ = client.ChainID(context.Background())
+	}
+	assert.Equal(t, 3.0, metrics.counters[evm.MetricRPCFailures])
+	assert.Contains(t, metrics.labels, map[string]string{
+		"operation": "ChainID",
+		"chain":     "testnet",
+		"outcome":   evm.OutcomeRejected,
+	})
+}
+
+// EOF: internal/blockchain/evm/metrics_test.go
@@ -0,0 +1,110 @@
+// Package evm_test tests sending from several accounts of one wallet.
+//
+// File: internal/blockchain/evm/multiaccount_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+func TestEVMGateway_SendFromAccounts(t *testing.T) {
+	wallet, err := evm.NewKeystoreDir(filepath.Join(t.TempDir(), "keys"), "test")
+	require.NoError(t, err)
+	_, err = wallet.NewAccount()
+	require.NoError(t, err)
+	accounts := wallet.Accounts()
+	require.Len(t, accounts, 2)
+
+	alloc := types.GenesisAlloc{}
+	for _, account := range accounts {
+		alloc[common.HexToAddress(account)] = types.Account{Balance: big.NewInt(1e18)}
+	}
+	sim := simulated.NewBackend(alloc)
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	send := func(from string) string {
+		t.Helper()
+		txHash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{
+			To:    &to,
+			Value: big.NewInt(1000),
+			From:  from,
+		})
+		require.NoError(t, err)
+		sim.Commit()
+		return txHash
+	}
+	sender := func(txHash string) string {
+		t.Helper()
+		tx, _, err := sim.Client().TransactionByHash(ctx, common.HexToHash(txHash))
+		require.NoError(t, err)
+		chainID, err := sim.Client().ChainID(ctx)
+		require.NoError(t, err)
+		from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+		require.NoError(t, err)
+		return from.Hex()
+	}
+
+	assert.Equal(t, accounts[0], sender(send("")), "the primary account signs by default")
+	assert.Equal(t, accounts[0], sender(send(accounts[0])))
+	assert.Equal(t, accounts[1], sender(send(accounts[1])))
+
+	// Each account keeps its own nonce.
+	nonce, err := gateway.GetTransactionCount(ctx, accounts[1], blockchain.BlockNumberLatest)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), nonce)
+
+	_, err = gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1), From: to})
+	assert.ErrorIs(t, err, blockchain.ErrUnknownAccount)
+
+	// A stuck transaction of a secondary account can be cancelled.
+	stuckHash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{
+		To:       &to,
+		Value:    big.NewInt(1000),
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+		From:     accounts[1],
+	})
+	require.NoError(t, err)
+	cancelHash, err := gateway.CancelTransaction(ctx, stuckHash)
+	require.NoError(t, err)
+	sim.Commit()
+	assert.Equal(t, accounts[1], sender(cancelHash))
+}
+
+func TestEVMGateway_SendFromSingleAccount(t *testing.T) {
+	wallet, err := evm.NewHDWallet(testMnemonic, "", "", 0)
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	// A wallet holding one account signs only as itself.
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	_, err = gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1), From: wallet.Address()})
+	assert.NoError(t, err)
+	_, err = gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1), From: to})
+	assert.ErrorIs(t, err, blockchain.ErrUnknownAccount)
+	_, err = gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1), From: "nope"})
+	assert.ErrorContains(t, err, "from address")
+}
+
+// EOF: internal/blockchain/evm/multiaccount_test.go
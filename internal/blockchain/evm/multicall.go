@@ -0,0 +1,291 @@
+// Package evm batches read‑only contract calls through Multicall3.
+//
+// File: internal/blockchain/evm/multicall.go
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// Multicall3Address is where Multicall3 is deployed, at the same address,
+// on most EVM chains.
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// DefaultBatchParallelism bounds the individual eth_calls a CallBatch
+// runs at once when Multicall3 is unavailable.
+const DefaultBatchParallelism = 8
+
+var (
+	aggregate3Selector = crypto.Keccak256([]byte("aggregate3((address,bool,bytes)[])"))[:4]
+	aggregate3Args     = abi.Arguments{{Type: abiTupleType([]abi.ArgumentMarshaling{
+		{Name: "target", Type: "address"},
+		{Name: "allowFailure", Type: "bool"},
+		{Name: "callData", Type: "bytes"},
+	})}}
+	aggregate3Results = abi.Arguments{{Type: abiTupleType([]abi.ArgumentMarshaling{
+		{Name: "success", Type: "bool"},
+		{Name: "returnData", Type: "bytes"},
+	})}}
+)
+
+// multicall3Call and multicall3Result mirror Multicall3's Call3 and Result.
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// BatchResult is the outcome of one call in a CallBatch: its decoded
+// return values, or the error it failed with. A revert is a
+// *ContractRevertError.
+type BatchResult struct {
+	Values []interface{}
+	Err    error
+}
+
+// CallBatch collects read‑only contract calls and executes them together.
+// Where Multicall3 is deployed the calls go to the node as one eth_call;
+// otherwise they are sent individually, Parallelism at a time. Either way
+// one failing call does not fail the others.
+type CallBatch struct {
+	// Parallelism bounds concurrent eth_calls without Multicall3
+	// (0 = DefaultBatchParallelism).
+	Parallelism int
+	// Multicall is the Multicall3 contract (nil = Multicall3Address).
+	Multicall *common.Address
+	// DisableMulticall sends every call individually.
+	DisableMulticall bool
+	// Block to read state at (empty = latest).
+	Block blockchain.BlockNumber
+
+	gateway *EVMGateway
+	calls   []batchCall
+}
+
+type batchCall struct {
+	contract *BoundContract
+	method   abi.Method
+	args     []interface{}
+	data     []byte
+	err      error // set if the call could not be built
+}
+
+// bindable is implemented by BoundContract and by types embedding it.
+type bindable interface {
+	bound() *BoundContract
+}
+
+func (c *BoundContract) bound() *BoundContract { return c }
+
+// NewCallBatch returns an empty batch executing through gateway.
+func NewCallBatch(gateway *EVMGateway) *CallBatch {
+	return &CallBatch{gateway: gateway}
+}
+
+// Add queues a call of method with args on contract, which must be an EVM
+// binding such as one returned by NewBoundContract. An unknown method or
+// bad arguments fail only this call, when the batch executes.
+func (b *CallBatch) Add(contract blockchain.Contract, method string, args ...interface{}) *CallBatch {
+	call := batchCall{args: args}
+	if bc, ok := contract.(bindable); ok {
+		call.contract = bc.bound()
+		call.method, call.data, call.err = call.contract.pack(method, args)
+	} else {
+		call.err = fmt.Errorf("call batch: %T is not an EVM contract binding", contract)
+	}
+	b.calls = append(b.calls, call)
+	return b
+}
+
+// Len returns the number of queued calls.
+func (b *CallBatch) Len() int {
+	return len(b.calls)
+}
+
+// Execute runs the queued calls and returns one result per call, in the
+// order they were added. The error is non‑nil only if the batch as a whole
+// could not run; failures of single calls are reported in their results.
+func (b *CallBatch) Execute(ctx context.Context) ([]BatchResult, error) {
+	results := make([]BatchResult, len(b.calls))
+	pending := make([]int, 0, len(b.calls))
+	for i, call := range b.calls {
+		if call.err != nil {
+			results[i].Err = call.err
+			continue
+		}
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	if !b.DisableMulticall && len(pending) > 1 {
+		available, err := b.gateway.multicallAvailable(ctx, b.multicall())
+		if err != nil {
+			return nil, fmt.Errorf("call batch: %w", err)
+		}
+		if available {
+			done, err := b.aggregate(ctx, pending, results)
+			if err != nil {
+				return nil, fmt.Errorf("call batch: %w", err)
+			}
+			if done {
+				return results, nil
+			}
+		}
+	}
+	b.individually(ctx, pending, results)
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("call batch: %w", err)
+	}
+	return results, nil
+}
+
+// aggregate executes the pending calls with one Multicall3 aggregate3
+// call. It returns false if Multicall3 itself reverted, e.g. for running
+// out of gas, so that the calls are retried individually.
+func (b *CallBatch) aggregate(ctx context.Context, pending []int, results []BatchResult) (bool, error) {
+	calls := make([]multicall3Call, len(pending))
+	for j, i := range pending {
+		calls[j] = multicall3Call{
+			Target:       b.calls[i].contract.address,
+			AllowFailure: true,
+			CallData:     b.calls[i].data,
+		}
+	}
+	packed, err := aggregate3Args.Pack(calls)
+	if err != nil {
+		return false, fmt.Errorf("pack aggregate3: %w", err)
+	}
+	multicall := b.multicall()
+	res, err := b.gateway.CallContract(ctx, &blockchain.ContractCall{
+		To:    multicall.Hex(),
+		Data:  append(append([]byte(nil), aggregate3Selector...), packed...),
+		Block: b.Block,
+	})
+	if err != nil {
+		if _, ok := revertData(err); ok {
+			b.gateway.logger.Warn("Multicall3 reverted; sending calls individually", map[string]interface{}{
+				"calls": len(pending),
+				"error": err.Error(),
+			})
+			return false, nil
+		}
+		return false, err
+	}
+	values, err := aggregate3Results.Unpack(res)
+	if err != nil {
+		return false, fmt.Errorf("unpack aggregate3: %w", err)
+	}
+	var returned []multicall3Result
+	if err := aggregate3Results.Copy(&returned, values); err != nil {
+		return false, fmt.Errorf("unpack aggregate3: %w", err)
+	}
+	if len(returned) != len(pending) {
+		return false, fmt.Errorf("aggregate3 returned %d results for %d calls", len(returned), len(pending))
+	}
+	for j, i := range pending {
+		call := b.calls[i]
+		if !returned[j].Success {
+			data := returned[j].ReturnData
+			results[i].Err = &ContractRevertError{Reason: call.contract.decodeRevert(data), Data: data}
+			continue
+		}
+		results[i].Values, results[i].Err = call.method.Outputs.Unpack(returned[j].ReturnData)
+		if results[i].Err != nil {
+			results[i].Err = fmt.Errorf("unpack result: %w", results[i].Err)
+		}
+	}
+	return true, nil
+}
+
+// individually executes the pending calls as separate eth_calls, at most
+// Parallelism at a time.
+func (b *CallBatch) individually(ctx context.Context, pending []int, results []BatchResult) {
+	parallelism := b.Parallelism
+	if parallelism <= 0 {
+		parallelism = DefaultBatchParallelism
+	}
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for _, i := range pending {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			call := b.calls[i]
+			results[i].Values, results[i].Err = call.contract.CallAt(ctx, &CallOpts{Block: b.Block}, call.method.Sig, call.args...)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (b *CallBatch) multicall() common.Address {
+	if b.Multicall != nil {
+		return *b.Multicall
+	}
+	return Multicall3Address
+}
+
+// multicallAvailable reports whether Multicall3 has code at address. A
+// positive answer is cached, since deployed code cannot go away.
+func (g *EVMGateway) multicallAvailable(ctx context.Context, address common.Address) (bool, error) {
+	g.multicallMu.Lock()
+	known := g.multicalls[address]
+	g.multicallMu.Unlock()
+	if known {
+		return true, nil
+	}
+	code, err := g.client.CodeAt(ctx, address, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, err
+		}
+		g.logger.Warn("Multicall3 lookup failed; sending calls individually", map[string]interface{}{
+			"multicall": address.Hex(),
+			"error":     err.Error(),
+		})
+		return false, nil
+	}
+	if len(code) == 0 {
+		return false, nil
+	}
+	g.multicallMu.Lock()
+	if g.multicalls == nil {
+		g.multicalls = make(map[common.Address]bool)
+	}
+	g.multicalls[address] = true
+	g.multicallMu.Unlock()
+	return true, nil
+}
+
+// abiTupleType returns the ABI type of an array of tuples with the given
+// components.
+func abiTupleType(components []abi.ArgumentMarshaling) abi.Type {
+	typ, err := abi.NewType("tuple[]", "", components)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// EOF: internal/blockchain/evm/multicall.go
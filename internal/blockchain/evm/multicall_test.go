@@ -0,0 +1,189 @@
+// Package evm_test tests batched contract calls.
+//
+// File: internal/blockchain/evm/multicall_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+const multicall3ABI = `[
+	{"type":"function","name":"aggregate3","stateMutability":"payable",
+	 "inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}],
+	 "outputs":[{"name":"returnData","type":"tuple[]","components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]}
+]`
+
+func TestCallBatch_IndividualCalls(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	ec := reflect.ValueOf(sim.Client()).Field(0).Interface().(*ethclient.Client)
+	client := evm.NewClientFromEthClient(ec, &observe.NoopLogger{}, nil)
+	gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, wallet)
+	ctx := context.Background()
+
+	_, pairAddr, err := gateway.DeployContract(ctx, pairInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+	_, vaultAddr, err := gateway.DeployContract(ctx, vaultInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+	pair, err := evm.NewBoundContract(pairAddr.Hex(), pairABI, gateway)
+	require.NoError(t, err)
+	vault, err := evm.NewBoundContract(vaultAddr.Hex(), vaultABI, gateway)
+	require.NoError(t, err)
+
+	// No Multicall3 on the simulated chain, so the calls go one by one.
+	batch := evm.NewCallBatch(gateway)
+	batch.Parallelism = 2
+	batch.Add(pair, "total").
+		Add(vault, "withdraw", big.NewInt(100)).
+		Add(pair, "balances", common.HexToAddress(wallet.Address())).
+		Add(pair, "missing").
+		Add(pair, "total")
+	require.Equal(t, 5, batch.Len())
+
+	results, err := batch.Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, []interface{}{big.NewInt(100)}, results[0].Values)
+
+	var revert *evm.ContractRevertError
+	require.True(t, errors.As(results[1].Err, &revert), "got %v", results[1].Err)
+	assert.Equal(t, "InsufficientBalance(required=100, available=5)", revert.Reason)
+	assert.ErrorIs(t, results[1].Err, evm.ErrReverted)
+
+	require.NoError(t, results[2].Err)
+	assert.Equal(t, []interface{}{big.NewInt(100), big.NewInt(5)}, results[2].Values)
+
+	assert.ErrorContains(t, results[3].Err, `method "missing" not found`)
+
+	require.NoError(t, results[4].Err)
+	assert.Equal(t, []interface{}{big.NewInt(100)}, results[4].Values)
+}
+
+func TestCallBatch_Multicall3(t *testing.T) {
+	aggregate3 := parseABI(t, multicall3ABI).Methods["aggregate3"]
+	pairAddr := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	vaultAddr := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	pairParsed := parseABI(t, pairABI)
+	vaultParsed := parseABI(t, vaultABI)
+	pairResult, err := pairParsed.Methods["balances"].Outputs.Pack(big.NewInt(100), big.NewInt(5))
+	require.NoError(t, err)
+	revertArgs, err := vaultParsed.Errors["InsufficientBalance"].Inputs.Pack(big.NewInt(100), big.NewInt(5))
+	require.NoError(t, err)
+	revertData := append(append([]byte(nil), insufficientBalance...), revertArgs...)
+
+	var calls, aggregated atomic.Int32
+	srv := newFakeNode(t, map[string]interface{}{
+		"eth_getCode": func(params []json.RawMessage) interface{} {
+			var addr common.Address
+			require.NoError(t, json.Unmarshal(params[0], &addr))
+			if addr == evm.Multicall3Address {
+				return "0x6001"
+			}
+			return "0x"
+		},
+		"eth_call": func(params []json.RawMessage) interface{} {
+			calls.Add(1)
+			var msg struct {
+				To    common.Address `json:"to"`
+				Input hexutil.Bytes  `json:"input"`
+			}
+			require.NoError(t, json.Unmarshal(params[0], &msg))
+			require.Equal(t, evm.Multicall3Address, msg.To)
+			require.Equal(t, aggregate3.ID, []byte(msg.Input[:4]))
+			args, err := aggregate3.Inputs.Unpack(msg.Input[4:])
+			require.NoError(t, err)
+			var in []struct {
+				Target       common.Address
+				AllowFailure bool
+				CallData     []byte
+			}
+			require.NoError(t, aggregate3.Inputs.Copy(&in, args))
+			aggregated.Add(int32(len(in)))
+
+			type result struct {
+				Success    bool
+				ReturnData []byte
+			}
+			out := make([]result, len(in))
+			for i, call := range in {
+				assert.True(t, call.AllowFailure)
+				if call.Target == vaultAddr {
+					out[i] = result{ReturnData: revertData}
+				} else {
+					out[i] = result{Success: true, ReturnData: pairResult}
+				}
+			}
+			packed, err := aggregate3.Outputs.Pack(out)
+			require.NoError(t, err)
+			return hexutil.Bytes(packed)
+		},
+	})
+	client, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{}, &evm.RetryConfig{MaxAttempts: 1}, time.Second)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, nil)
+	pair, err := evm.NewBoundContract(pairAddr.Hex(), pairABI, gateway)
+	require.NoError(t, err)
+	vault, err := evm.NewBoundContract(vaultAddr.Hex(), vaultABI, gateway)
+	require.NoError(t, err)
+
+	owner := common.HexToAddress("0xdead")
+	batch := evm.NewCallBatch(gateway).
+		Add(pair, "balances", owner).
+		Add(vault, "withdraw", big.NewInt(100)).
+		Add(pair, "balances", "not an address").
+		Add(pair, "balances", owner)
+	results, err := batch.Execute(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, int32(1), calls.Load(), "one eth_call for the whole batch")
+	assert.Equal(t, int32(3), aggregated.Load(), "calls that cannot be packed are not sent")
+
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, []interface{}{big.NewInt(100), big.NewInt(5)}, results[0].Values)
+	var revert *evm.ContractRevertError
+	require.True(t, errors.As(results[1].Err, &revert), "got %v", results[1].Err)
+	assert.Equal(t, "InsufficientBalance(required=100, available=5)", revert.Reason)
+	assert.ErrorContains(t, results[2].Err, "pack arguments")
+	require.NoError(t, results[3].Err)
+	assert.Equal(t, []interface{}{big.NewInt(100), big.NewInt(5)}, results[3].Values)
+}
+
+func parseABI(t *testing.T, abiJSON string) abi.ABI {
+	t.Helper()
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	require.NoError(t, err)
+	return parsed
+}
+
+// EOF: internal/blockchain/evm/multicall_test.go
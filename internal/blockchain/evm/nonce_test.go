@@ -0,0 +1,63 @@
+// Package evm_test tests confirmed and pending transaction counts.
+//
+// File: internal/blockchain/evm/nonce_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+func TestEVMGateway_GetTransactionCount(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	counts := func() (pending, latest uint64) {
+		t.Helper()
+		pending, err := gateway.GetTransactionCount(ctx, wallet.Address(), blockchain.BlockNumberPending)
+		require.NoError(t, err)
+		latest, err = gateway.GetTransactionCount(ctx, wallet.Address(), blockchain.BlockNumberLatest)
+		require.NoError(t, err)
+		return pending, latest
+	}
+
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	_, err = gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1)})
+	require.NoError(t, err)
+
+	pending, latest := counts()
+	assert.Equal(t, uint64(1), pending, "the transaction is in the mempool")
+	assert.Equal(t, uint64(0), latest, "but not mined")
+
+	sim.Commit()
+	pending, latest = counts()
+	assert.Equal(t, uint64(1), pending)
+	assert.Equal(t, uint64(1), latest)
+
+	genesis, err := gateway.GetTransactionCount(ctx, wallet.Address(), "0")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), genesis)
+
+	_, err = gateway.GetTransactionCount(ctx, wallet.Address(), "yesterday")
+	assert.ErrorContains(t, err, "GetTransactionCount")
+}
+
+// EOF: internal/blockchain/evm/nonce_test.go
@@ -0,0 +1,162 @@
+// Package evm provides functional options for configuring the RPC client.
+//
+// File: internal/blockchain/evm/options.go
+
+package evm
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// ClientOption configures optional Client behaviour.
+type ClientOption func(*Client)
+
+// WithChainName sets the configured chain name (e.g. "polygon") used to
+// label logs and metrics.
+func WithChainName(name string) ClientOption {
+	return func(c *Client) {
+		c.chain = name
+	}
+}
+
+// WithMetrics records client metrics through the given implementation.
+func WithMetrics(metrics observe.Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// WithCircuitBreaker enables the circuit breaker (see CircuitConfig).
+func WithCircuitBreaker(cfg CircuitConfig) ClientOption {
+	return func(c *Client) {
+		c.circuit = &cfg
+	}
+}
+
+// WithRateLimit throttles requests to rps per second with the given burst.
+// The bucket is shared with every client using the same endpoint URL (see
+// SharedRateLimiter). rps <= 0 disables limiting.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		if rps > 0 {
+			c.limiter = SharedRateLimiter(c.rpcURL, rps, burst)
+		}
+	}
+}
+
+// WithRateLimiter throttles requests through an explicitly shared limiter.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithBlockTime sets the chain's expected block interval. Fee estimates and
+// gas price suggestions are cached for this long; 0 (the default) disables
+// caching.
+func WithBlockTime(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.blockTime = d
+	}
+}
+
+// WithWatchBuffer sets the event channel capacity of WatchEvents
+// (0 = DefaultWatchBuffer). Events arriving while it is full are dropped.
+func WithWatchBuffer(n int) ClientOption {
+	return func(c *Client) {
+		c.watchBuffer = n
+	}
+}
+
+// WithGasLimitMultiplier sets the default factor by which TxBuilder pads
+// estimated gas limits (default DefaultGasLimitMultiplier; 1 disables
+// padding). TxOpts.GasLimitMultiplier overrides it per transaction.
+func WithGasLimitMultiplier(m float64) ClientOption {
+	return func(c *Client) {
+		c.gasMultiplier = m
+	}
+}
+
+// WithMaxGasLimit caps padded gas limits (0 = no cap).
+// TxOpts.MaxGasLimit overrides it per transaction.
+func WithMaxGasLimit(limit uint64) ClientOption {
+	return func(c *Client) {
+		c.maxGasLimit = limit
+	}
+}
+
+// WithSimulation simulates every transaction built by TxBuilder before
+// signing, as if TxOpts.Simulate were set.
+func WithSimulation() ClientOption {
+	return func(c *Client) {
+		c.simulate = true
+	}
+}
+
+// WithRevertReasons makes WaitForReceipt fail with a
+// *TransactionRevertedError carrying the decoded revert reason (see
+// GetRevertReason) when the transaction failed. The receipt is still returned.
+func WithRevertReasons() ClientOption {
+	return func(c *Client) {
+		c.revertReasons = true
+	}
+}
+
+// WithMaxReorgDepth makes WaitForReceipt fail with ErrReorged when a reorg
+// replaces more than depth blocks of a receipt it was confirming (the
+// including block and those seen on top of it). 0, the default, tolerates
+// any reorg and keeps waiting.
+func WithMaxReorgDepth(depth uint64) ClientOption {
+	return func(c *Client) {
+		c.maxReorgDepth = depth
+	}
+}
+
+// WithENSRegistry sets the ENS registry used to resolve names. Without it
+// names resolve only on chains where DefaultENSRegistry is deployed.
+func WithENSRegistry(registry common.Address) ClientOption {
+	return func(c *Client) {
+		c.ensRegistry = registry
+	}
+}
+
+// WithENSCacheTTL sets how long ENS resolutions are cached (0 =
+// DefaultENSCacheTTL).
+func WithENSCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.ensTTL = ttl
+	}
+}
+
+// applyOptions applies opts and initialises the components that depend on them.
+func (c *Client) applyOptions(opts []ClientOption) {
+	for _, o := range opts {
+		o(c)
+	}
+	if c.metrics == nil {
+		c.metrics = &observe.NoopMetrics{}
+	}
+	if c.gasMultiplier == 0 {
+		c.gasMultiplier = DefaultGasLimitMultiplier
+	}
+	c.metricsOn = metricsEnabled(c.metrics)
+	c.fees = newFeeEstimator(c)
+	c.cache = newRPCCache()
+	c.ens = newENSResolver(c, c.ensRegistry, c.ensTTL)
+	c.l2fees = newL2FeeEstimator(c, c.l2Stack)
+	if c.circuit != nil && c.circuit.FailureThreshold > 0 {
+		c.breaker = newCircuitBreaker(*c.circuit, func() time.Time { return c.clock.Now() }, c.onCircuitChange)
+		c.metrics.Gauge("rpc_circuit_state", float64(CircuitClosed), c.labels())
+	}
+}
+
+// labels returns the metric labels identifying this client.
+func (c *Client) labels() map[string]string {
+	return map[string]string{"chain": c.chain}
+}
+
+// EOF: internal/blockchain/evm/options.go
@@ -0,0 +1,135 @@
+// Package evm_test tests binding contracts with overloaded methods and
+// events.
+//
+// File: internal/blockchain/evm/overload_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+const overloadedABI = `[
+	{"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"},{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"event","name":"Ping","inputs":[{"name":"value","type":"uint256","indexed":false}],"anonymous":false},
+	{"type":"event","name":"Ping","inputs":[{"name":"value","type":"uint256","indexed":false},{"name":"extra","type":"uint256","indexed":false}],"anonymous":false}
+]`
+
+// loggerInitCode deploys a contract that emits a LOG1 whose topic is the
+// first calldata word and whose data is the rest of the calldata.
+var loggerInitCode = common.FromHex("6011600c60003960116000f3" +
+	"600035" + "60203603" + "8060206000" + "37" + "6000a1" + "00")
+
+func TestBoundContract_Overloads(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	ec := reflect.ValueOf(sim.Client()).Field(0).Interface().(*ethclient.Client)
+	client := evm.NewClientFromEthClient(ec, &observe.NoopLogger{}, nil)
+	gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, wallet)
+	ctx := context.Background()
+	owner := common.HexToAddress(wallet.Address())
+
+	_, addr, err := gateway.DeployContract(ctx, pairInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+	bound, err := evm.NewBoundContract(addr.Hex(), overloadedABI, gateway)
+	require.NoError(t, err)
+	contract := bound.(*evm.BoundContract)
+
+	t.Run("call", func(t *testing.T) {
+		_, err := contract.Call(ctx, "balanceOf", owner)
+		assert.EqualError(t, err, `method "balanceOf" is overloaded; use its signature: balanceOf(address), balanceOf(address,uint256)`)
+
+		values, err := contract.Call(ctx, "balanceOf(address)", owner)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{big.NewInt(100)}, values)
+
+		values, err = contract.Call(ctx, "balanceOf(address, uint256)", owner, big.NewInt(1))
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{big.NewInt(100)}, values)
+
+		_, err = contract.Call(ctx, "balanceOf(address)", owner, big.NewInt(1))
+		assert.ErrorContains(t, err, "pack arguments", "arguments are checked against the chosen overload")
+		_, err = contract.Call(ctx, "balanceOf(uint256)", big.NewInt(1))
+		assert.EqualError(t, err, `method "balanceOf(uint256)" not found in ABI`)
+	})
+
+	t.Run("transact", func(t *testing.T) {
+		_, err := contract.Transact(ctx, "safeTransferFrom", owner, owner, big.NewInt(1))
+		assert.EqualError(t, err, `method "safeTransferFrom" is overloaded; use its signature: `+
+			`safeTransferFrom(address,address,uint256), safeTransferFrom(address,address,uint256,bytes)`)
+
+		for sig, args := range map[string][]interface{}{
+			"safeTransferFrom(address,address,uint256)":       {owner, owner, big.NewInt(1)},
+			"safeTransferFrom(address,address,uint256,bytes)": {owner, owner, big.NewInt(1), []byte{0x01}},
+		} {
+			txHash, err := contract.Transact(ctx, sig, args...)
+			require.NoError(t, err, sig)
+			sim.Commit()
+			tx, _, err := sim.Client().TransactionByHash(ctx, common.HexToHash(txHash))
+			require.NoError(t, err)
+			assert.Equal(t, crypto.Keccak256([]byte(sig))[:4], tx.Data()[:4], sig)
+		}
+	})
+
+	t.Run("events", func(t *testing.T) {
+		_, loggerAddr, err := gateway.DeployContract(ctx, loggerInitCode, nil)
+		require.NoError(t, err)
+		sim.Commit()
+		logger, err := evm.NewBoundContract(loggerAddr.Hex(), overloadedABI, gateway)
+		require.NoError(t, err)
+		to := loggerAddr.Hex()
+		emit := func(sig string, words ...int64) {
+			data := crypto.Keccak256([]byte(sig))
+			for _, w := range words {
+				data = append(data, common.BigToHash(big.NewInt(w)).Bytes()...)
+			}
+			_, err := gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Data: data, Gas: 100000})
+			require.NoError(t, err)
+			sim.Commit()
+		}
+		emit("Ping(uint256)", 7)
+		emit("Ping(uint256,uint256)", 8, 9)
+
+		contract := logger.(*evm.BoundContract)
+		_, err = contract.FilterEvents(ctx, "Ping", "0", "latest")
+		assert.EqualError(t, err, `event "Ping" is overloaded; use its signature: Ping(uint256), Ping(uint256,uint256)`)
+
+		events, err := contract.FilterEvents(ctx, "Ping(uint256)", "0", "latest")
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "Ping(uint256)", events[0].Name)
+		assert.Equal(t, map[string]interface{}{"value": big.NewInt(7)}, events[0].Args)
+
+		events, err = contract.FilterEvents(ctx, "Ping(uint256,uint256)", "0", "latest")
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "Ping(uint256,uint256)", events[0].Name)
+		assert.Equal(t, map[string]interface{}{"value": big.NewInt(8), "extra": big.NewInt(9)}, events[0].Args)
+	})
+}
+
+// EOF: internal/blockchain/evm/overload_test.go
@@ -0,0 +1,162 @@
+// Package evm detects proxy contracts and finds their implementations.
+//
+// File: internal/blockchain/evm/proxy.go
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// ProxyKind names the proxy pattern DetectProxy recognised.
+type ProxyKind string
+
+const (
+	// ProxyEIP1967 stores its implementation in the EIP‑1967
+	// implementation slot (transparent and UUPS proxies).
+	ProxyEIP1967 ProxyKind = "eip1967"
+	// ProxyEIP1967Beacon stores a beacon in the EIP‑1967 beacon slot; the
+	// beacon's implementation() is the implementation.
+	ProxyEIP1967Beacon ProxyKind = "eip1967-beacon"
+	// ProxyEIP1167 is a minimal proxy ("clone") with the implementation
+	// baked into its bytecode.
+	ProxyEIP1167 ProxyKind = "eip1167"
+)
+
+// ProxyInfo describes a proxy found by DetectProxy.
+type ProxyInfo struct {
+	Kind ProxyKind
+	// Implementation is the contract whose code the proxy runs.
+	Implementation common.Address
+	// Beacon is the beacon contract of a ProxyEIP1967Beacon proxy.
+	Beacon common.Address
+}
+
+var (
+	// eip1967ImplementationSlot is keccak256("eip1967.proxy.implementation") - 1.
+	eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// eip1967BeaconSlot is keccak256("eip1967.proxy.beacon") - 1.
+	eip1967BeaconSlot = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+
+	// beaconImplementationSelector is implementation().
+	beaconImplementationSelector = common.FromHex("0x5c60da1b")
+
+	// The runtime code of an EIP‑1167 minimal proxy surrounds the
+	// implementation address with these bytes.
+	eip1167Prefix = common.FromHex("0x363d3d373d3d3d363d73")
+	eip1167Suffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+)
+
+// DetectProxy reports whether the contract at address, a hex address or
+// ENS name, is a proxy and if so which implementation it delegates to. It
+// reads the EIP‑1967 implementation slot, then the beacon slot, then
+// matches the EIP‑1167 minimal proxy bytecode. It returns nil, nil for a
+// contract that is not a recognised proxy, or an account without code.
+func DetectProxy(ctx context.Context, gateway *EVMGateway, address string) (*ProxyInfo, error) {
+	addr, err := gateway.resolveAddress(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("DetectProxy: %w", err)
+	}
+	slot, err := gateway.GetStorageAt(ctx, addr.Hex(), eip1967ImplementationSlot.Hex(), blockchain.BlockNumberLatest)
+	if err != nil {
+		return nil, fmt.Errorf("DetectProxy: read implementation slot: %w", err)
+	}
+	if impl := common.BytesToAddress(slot); impl != (common.Address{}) {
+		return proxyFound(gateway, addr, &ProxyInfo{Kind: ProxyEIP1967, Implementation: impl}), nil
+	}
+
+	slot, err = gateway.GetStorageAt(ctx, addr.Hex(), eip1967BeaconSlot.Hex(), blockchain.BlockNumberLatest)
+	if err != nil {
+		return nil, fmt.Errorf("DetectProxy: read beacon slot: %w", err)
+	}
+	if beacon := common.BytesToAddress(slot); beacon != (common.Address{}) {
+		res, err := gateway.CallContract(ctx, &blockchain.ContractCall{To: beacon.Hex(), Data: beaconImplementationSelector})
+		if err != nil {
+			return nil, fmt.Errorf("DetectProxy: beacon %s implementation(): %w", beacon.Hex(), err)
+		}
+		if len(res) < 32 {
+			return nil, fmt.Errorf("DetectProxy: beacon %s implementation(): short result of %d bytes", beacon.Hex(), len(res))
+		}
+		info := &ProxyInfo{Kind: ProxyEIP1967Beacon, Implementation: common.BytesToAddress(res[:32]), Beacon: beacon}
+		return proxyFound(gateway, addr, info), nil
+	}
+
+	code, err := gateway.GetCode(ctx, addr.Hex(), blockchain.BlockNumberLatest)
+	if err != nil {
+		return nil, fmt.Errorf("DetectProxy: %w", err)
+	}
+	if impl, ok := eip1167Implementation(code); ok {
+		return proxyFound(gateway, addr, &ProxyInfo{Kind: ProxyEIP1167, Implementation: impl}), nil
+	}
+	return nil, nil
+}
+
+// eip1167Implementation extracts the implementation from EIP‑1167
+// minimal proxy runtime code.
+func eip1167Implementation(code []byte) (common.Address, bool) {
+	if len(code) != len(eip1167Prefix)+common.AddressLength+len(eip1167Suffix) ||
+		!bytes.HasPrefix(code, eip1167Prefix) || !bytes.HasSuffix(code, eip1167Suffix) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(code[len(eip1167Prefix) : len(eip1167Prefix)+common.AddressLength]), true
+}
+
+// proxyFound logs the implementation behind proxy, so that audit logs
+// record which code a binding was resolved against.
+func proxyFound(gateway *EVMGateway, proxy common.Address, info *ProxyInfo) *ProxyInfo {
+	fields := map[string]interface{}{
+		"proxy":          proxy.Hex(),
+		"kind":           string(info.Kind),
+		"implementation": info.Implementation.Hex(),
+	}
+	if info.Beacon != (common.Address{}) {
+		fields["beacon"] = info.Beacon.Hex()
+	}
+	gateway.logger.Info("resolved proxy implementation", fields)
+	return info
+}
+
+// NewBoundContractResolvingProxy binds the contract at address like
+// NewBoundContractByAddress: if it is a proxy (see DetectProxy) the
+// implementation's ABI is fetched by the gateway's resolver, while calls,
+// transactions and event filters still target the proxy. The proxy found,
+// or nil, is returned alongside the binding.
+func NewBoundContractResolvingProxy(ctx context.Context, address string, gateway *EVMGateway) (blockchain.Contract, *ProxyInfo, error) {
+	if gateway.abiResolver == nil {
+		return nil, nil, errors.New("bind by address: no ABI resolver configured")
+	}
+	addr, err := gateway.resolveAddress(ctx, address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bind by address: %w", err)
+	}
+	chainID, err := gateway.ChainID(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bind by address: %w", err)
+	}
+	proxy, err := DetectProxy(ctx, gateway, addr.Hex())
+	if err != nil {
+		return nil, nil, fmt.Errorf("bind by address: %w", err)
+	}
+	source := addr
+	if proxy != nil {
+		source = proxy.Implementation
+	}
+	abiJSON, err := gateway.abiResolver.ResolveABI(ctx, chainID.Uint64(), source.Hex())
+	if err != nil {
+		return nil, nil, fmt.Errorf("bind by address: %w", err)
+	}
+	contract, err := NewBoundContract(addr.Hex(), abiJSON, gateway)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contract, proxy, nil
+}
+
+// EOF: internal/blockchain/evm/proxy.go
@@ -0,0 +1,97 @@
+// Package evm_test tests proxy detection.
+//
+// File: internal/blockchain/evm/proxy_test.go
+
+package evm_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// eip1967Slot returns keccak256(label) - 1.
+func eip1967Slot(label string) common.Hash {
+	slot := new(big.Int).SetBytes(crypto.Keccak256([]byte(label)))
+	return common.BigToHash(slot.Sub(slot, big.NewInt(1)))
+}
+
+func TestDetectProxy(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+
+	impl := common.HexToAddress("0x00000000000000000000000000000000000000d4")
+	var (
+		plain    = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		uups     = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		beacon   = common.HexToAddress("0x1000000000000000000000000000000000000003")
+		beaconed = common.HexToAddress("0x1000000000000000000000000000000000000004")
+		clone    = common.HexToAddress("0x1000000000000000000000000000000000000005")
+		account  = common.HexToAddress("0x1000000000000000000000000000000000000006")
+	)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+		impl:                                  {Code: common.FromHex(counterInitCode[24:])},
+		plain:                                 {Code: common.FromHex(counterInitCode[24:])},
+		uups: {
+			Code:    common.FromHex(counterInitCode[24:]),
+			Storage: map[common.Hash]common.Hash{eip1967Slot("eip1967.proxy.implementation"): common.BytesToHash(impl.Bytes())},
+		},
+		// The beacon returns impl from every call, implementation() included.
+		beacon: {Code: common.FromHex(fmt.Sprintf("73%x60005260206000f3", impl))},
+		beaconed: {
+			Code:    common.FromHex(counterInitCode[24:]),
+			Storage: map[common.Hash]common.Hash{eip1967Slot("eip1967.proxy.beacon"): common.BytesToHash(beacon.Bytes())},
+		},
+		clone:   {Code: common.FromHex(fmt.Sprintf("363d3d373d3d3d363d73%x5af43d82803e903d91602b57fd5bf3", impl))},
+		account: {Balance: big.NewInt(1)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name    string
+		address common.Address
+		want    *evm.ProxyInfo
+	}{
+		{"plain contract", plain, nil},
+		{"account without code", account, nil},
+		{"eip1967", uups, &evm.ProxyInfo{Kind: evm.ProxyEIP1967, Implementation: impl}},
+		{"beacon", beaconed, &evm.ProxyInfo{Kind: evm.ProxyEIP1967Beacon, Implementation: impl, Beacon: beacon}},
+		{"minimal proxy", clone, &evm.ProxyInfo{Kind: evm.ProxyEIP1167, Implementation: impl}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := evm.DetectProxy(ctx, gateway, tt.address.Hex())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, info)
+		})
+	}
+
+	t.Run("binding", func(t *testing.T) {
+		source := &countingResolver{next: abiResult{abi: counterABI}}
+		gateway.SetABIResolver(source)
+		for _, addr := range []common.Address{plain, uups, beaconed, clone} {
+			contract, proxy, err := evm.NewBoundContractResolvingProxy(ctx, addr.Hex(), gateway)
+			require.NoError(t, err)
+			assert.Equal(t, addr == plain, proxy == nil)
+			var value *big.Int
+			require.NoError(t, contract.CallInto(ctx, &value, "value"), "calls go to %s", addr.Hex())
+		}
+		assert.Equal(t, []string{plain.Hex(), impl.Hex(), impl.Hex(), impl.Hex()}, source.asked,
+			"proxies are bound with their implementation's ABI")
+	})
+}
+
+// EOF: internal/blockchain/evm/proxy_test.go
@@ -0,0 +1,118 @@
+// Package evm provides a client‑side token‑bucket rate limiter for RPC endpoints.
+//
+// File: internal/blockchain/evm/ratelimit.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitWait is returned when waiting for a rate limiter token would
+// outlast the caller's context deadline.
+var ErrRateLimitWait = errors.New("rate limiter: wait exceeds context deadline")
+
+// RateLimiter is a token bucket shared by every client that talks to the
+// same endpoint. It is safe for concurrent use.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64 // bucket capacity
+	tokens float64 // may go negative while callers wait for reservations
+	last   time.Time
+}
+
+// NewRateLimiter creates a bucket that refills at rps tokens per second and
+// holds at most burst tokens (burst < 1 = 1). It starts full.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = make(map[string]*RateLimiter)
+)
+
+// SharedRateLimiter returns the process‑wide limiter for an endpoint URL,
+// creating it on first use. Gateways for different chains on the same
+// provider key therefore draw from one bucket; later calls with different
+// parameters receive the existing limiter unchanged.
+func SharedRateLimiter(rpcURL string, rps float64, burst int) *RateLimiter {
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+	if l, ok := sharedLimiters[rpcURL]; ok {
+		return l
+	}
+	l := NewRateLimiter(rps, burst)
+	sharedLimiters[rpcURL] = l
+	return l
+}
+
+// reserve takes n tokens at time now and returns how long the caller must
+// wait before using them.
+func (l *RateLimiter) reserve(now time.Time, n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	if now.After(l.last) {
+		l.last = now
+	}
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// release returns n reserved tokens that will not be used.
+func (l *RateLimiter) release(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens += float64(n)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// wait blocks until n tokens are available. It fails fast with
+// ErrRateLimitWait if the wait would end after the context deadline.
+func (l *RateLimiter) wait(ctx context.Context, clock Clock, n int) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+	now := clock.Now()
+	delay := l.reserve(now, n)
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		l.release(n)
+		return fmt.Errorf("%w: need %v", ErrRateLimitWait, delay)
+	}
+	select {
+	case <-ctx.Done():
+		l.release(n)
+		return ctx.Err()
+	case <-clock.After(delay):
+		return nil
+	}
+}
+
+// EOF: internal/blockchain/evm/ratelimit.go
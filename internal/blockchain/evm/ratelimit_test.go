@@ -0,0 +1,126 @@
+// Package evm_test tests the client‑side RPC rate limiter.
+//
+// File: internal/blockchain/evm/ratelimit_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// newChainIDServer answers every JSON‑RPC request, single or batched, with chain ID 1.
+func newChainIDServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	type request struct {
+		ID json.RawMessage `json:"id"`
+	}
+	reply := func(req request) map[string]interface{} {
+		return map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": "0x1"}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		w.Header().Set("Content-Type", "application/json")
+		if len(raw) > 0 && raw[0] == '[' {
+			var reqs []request
+			require.NoError(t, json.Unmarshal(raw, &reqs))
+			out := make([]map[string]interface{}, len(reqs))
+			for i, req := range reqs {
+				out[i] = reply(req)
+			}
+			_ = json.NewEncoder(w).Encode(out)
+			return
+		}
+		var req request
+		require.NoError(t, json.Unmarshal(raw, &req))
+		_ = json.NewEncoder(w).Encode(reply(req))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newLimitedClient(t *testing.T, url string, limiter *evm.RateLimiter) (*evm.Client, *fakeClock) {
+	t.Helper()
+	client, err := evm.NewClient(context.Background(), url, &observe.NoopLogger{}, nil, time.Second,
+		evm.WithRateLimiter(limiter))
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	clock := &fakeClock{now: time.Now()}
+	client.SetClock(clock)
+	return client, clock
+}
+
+func TestRateLimiter_WaitsAfterBurst(t *testing.T) {
+	srv := newChainIDServer(t)
+	client, clock := newLimitedClient(t, srv.URL, evm.NewRateLimiter(10, 2))
+	fresh := evm.ContextWithoutCache(context.Background()) // every call hits the node
+
+	for i := 0; i < 3; i++ {
+		_, err := client.ChainID(fresh)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []time.Duration{100 * time.Millisecond}, clock.waits)
+
+	// The bucket refills with time.
+	clock.Advance(time.Second)
+	_, err := client.ChainID(fresh)
+	require.NoError(t, err)
+	assert.Len(t, clock.waits, 1)
+}
+
+func TestRateLimiter_FailsFastPastDeadline(t *testing.T) {
+	srv := newChainIDServer(t)
+	client, clock := newLimitedClient(t, srv.URL, evm.NewRateLimiter(1, 1))
+	fresh := evm.ContextWithoutCache(context.Background()) // every call hits the node
+
+	_, err := client.ChainID(fresh)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(fresh, 100*time.Millisecond)
+	defer cancel()
+	_, err = client.ChainID(ctx)
+	assert.ErrorIs(t, err, evm.ErrRateLimitWait)
+	assert.Empty(t, clock.waits)
+
+	// The refused reservation was returned to the bucket.
+	_, err = client.ChainID(fresh)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second}, clock.waits)
+}
+
+func TestRateLimiter_BatchCostsPerElement(t *testing.T) {
+	srv := newChainIDServer(t)
+	client, clock := newLimitedClient(t, srv.URL, evm.NewRateLimiter(10, 1))
+
+	batch := make([]rpc.BatchElem, 3)
+	results := make([]string, len(batch))
+	for i := range batch {
+		batch[i] = rpc.BatchElem{Method: "eth_chainId", Result: &results[i]}
+	}
+	require.NoError(t, client.BatchCall(context.Background(), batch))
+	for i, elem := range batch {
+		require.NoError(t, elem.Error)
+		assert.Equal(t, "0x1", results[i])
+	}
+	assert.Equal(t, []time.Duration{200 * time.Millisecond}, clock.waits)
+}
+
+func TestSharedRateLimiter_PerEndpoint(t *testing.T) {
+	a := evm.SharedRateLimiter("https://rpc.example/key-a", 5, 1)
+	assert.Same(t, a, evm.SharedRateLimiter("https://rpc.example/key-a", 50, 10))
+	assert.NotSame(t, a, evm.SharedRateLimiter("https://rpc.example/key-b", 5, 1))
+}
+
+// EOF: internal/blockchain/evm/ratelimit_test.go
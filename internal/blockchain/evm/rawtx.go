@@ -0,0 +1,120 @@
+// Package evm decodes and broadcasts transactions signed outside LOLA.
+//
+// File: internal/blockchain/evm/rawtx.go
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RawTransaction is a decoded signed transaction with its recovered sender.
+type RawTransaction struct {
+	Tx    *types.Transaction
+	From  common.Address
+	To    *common.Address // nil for contract creation
+	Value *big.Int
+}
+
+// DecodeRawTransaction decodes a signed transaction given either as its
+// binary encoding or as 0x‑prefixed hex text, and recovers the sender.
+func DecodeRawTransaction(raw []byte) (*RawTransaction, error) {
+	// Only hex text is trimmed: binary encodings may end in bytes that
+	// look like whitespace.
+	if text := bytes.TrimSpace(raw); bytes.HasPrefix(text, []byte("0x")) || bytes.HasPrefix(text, []byte("0X")) {
+		decoded, err := hexutil.Decode("0x" + string(text[2:]))
+		if err != nil {
+			return nil, fmt.Errorf("decode raw tx: %w", err)
+		}
+		raw = decoded
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("decode raw tx: empty transaction")
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("decode raw tx: %w", err)
+	}
+	from, err := txSender(tx)
+	if err != nil {
+		return nil, fmt.Errorf("decode raw tx: recover sender: %w", err)
+	}
+	return &RawTransaction{
+		Tx:    tx,
+		From:  from,
+		To:    tx.To(),
+		Value: tx.Value(),
+	}, nil
+}
+
+// RawTransactionSender implements blockchain.RawSender: it returns the
+// checksummed address that signed raw.
+func (g *EVMGateway) RawTransactionSender(raw []byte) (string, error) {
+	decoded, err := DecodeRawTransaction(raw)
+	if err != nil {
+		return "", err
+	}
+	return decoded.From.Hex(), nil
+}
+
+// txSender recovers the sender of a signed transaction, including
+// unprotected (pre‑EIP‑155) legacy transactions.
+func txSender(tx *types.Transaction) (common.Address, error) {
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	}
+	return types.Sender(signer, tx)
+}
+
+// SendRawTransaction broadcasts a transaction signed elsewhere, such as by a
+// hardware wallet, and returns its hash. raw is the signed transaction as
+// bytes or 0x‑hex text. The transaction must be replay‑protected and signed
+// for the connected chain. No wallet is required.
+func (g *EVMGateway) SendRawTransaction(ctx context.Context, raw []byte) (string, error) {
+	decoded, err := DecodeRawTransaction(raw)
+	if err != nil {
+		return "", fmt.Errorf("SendRawTransaction: %w", err)
+	}
+	tx := decoded.Tx
+	if !tx.Protected() {
+		return "", errors.New("SendRawTransaction: transaction is not replay‑protected (no chain id)")
+	}
+	chainID, err := g.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("SendRawTransaction: %w", err)
+	}
+	if tx.ChainId().Cmp(chainID) != 0 {
+		return "", fmt.Errorf("SendRawTransaction: transaction chain id %s does not match connected chain %s",
+			tx.ChainId(), chainID)
+	}
+
+	if err := g.client.SendTransaction(ctx, tx); err != nil {
+		return "", fmt.Errorf("SendRawTransaction: send: %w", err)
+	}
+	g.track(tx)
+
+	to := ""
+	if decoded.To != nil {
+		to = decoded.To.Hex()
+	}
+	g.logger.Info("raw transaction sent", map[string]interface{}{
+		"tx_hash": tx.Hash().Hex(),
+		"from":    decoded.From.Hex(),
+		"to":      to,
+		"value":   decoded.Value.String(),
+		"nonce":   tx.Nonce(),
+	})
+	return tx.Hash().Hex(), nil
+}
+
+// EOF: internal/blockchain/evm/rawtx.go
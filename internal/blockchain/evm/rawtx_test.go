@@ -0,0 +1,97 @@
+// Package evm_test tests broadcasting pre‑signed transactions.
+//
+// File: internal/blockchain/evm/rawtx_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+func TestEVMGateway_SendRawTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		from: {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, nil) // no wallet: signing happened elsewhere
+	ctx := context.Background()
+
+	to := common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
+	sign := func(nonce uint64, signer types.Signer) *types.Transaction {
+		tx, err := types.SignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &to,
+			Value:    big.NewInt(1000),
+			Gas:      21000,
+			GasPrice: big.NewInt(10e9),
+		})
+		require.NoError(t, err)
+		return tx
+	}
+	encode := func(tx *types.Transaction) []byte {
+		raw, err := tx.MarshalBinary()
+		require.NoError(t, err)
+		return raw
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		tx := sign(0, types.LatestSignerForChainID(big.NewInt(1337)))
+		decoded, err := evm.DecodeRawTransaction([]byte(hexutil.Encode(encode(tx))))
+		require.NoError(t, err)
+		assert.Equal(t, from, decoded.From)
+		assert.Equal(t, to, *decoded.To)
+		assert.Equal(t, big.NewInt(1000), decoded.Value)
+
+		_, err = evm.DecodeRawTransaction([]byte("0xdeadbeef"))
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong chain", func(t *testing.T) {
+		tx := sign(0, types.LatestSignerForChainID(big.NewInt(1)))
+		_, err := gateway.SendRawTransaction(ctx, encode(tx))
+		assert.ErrorContains(t, err, "does not match connected chain 1337")
+	})
+
+	t.Run("unprotected", func(t *testing.T) {
+		tx := sign(0, types.HomesteadSigner{})
+		_, err := gateway.SendRawTransaction(ctx, encode(tx))
+		assert.ErrorContains(t, err, "not replay‑protected")
+	})
+
+	t.Run("binary and hex", func(t *testing.T) {
+		first := sign(0, types.LatestSignerForChainID(big.NewInt(1337)))
+		hash, err := gateway.SendRawTransaction(ctx, encode(first))
+		require.NoError(t, err)
+		assert.Equal(t, first.Hash().Hex(), hash)
+
+		second := sign(1, types.LatestSignerForChainID(big.NewInt(1337)))
+		hash, err = gateway.SendRawTransaction(ctx, []byte(hexutil.Encode(encode(second))))
+		require.NoError(t, err)
+		assert.Equal(t, second.Hash().Hex(), hash)
+		sim.Commit()
+
+		for _, tx := range []*types.Transaction{first, second} {
+			receipt, err := sim.Client().TransactionReceipt(ctx, tx.Hash())
+			require.NoError(t, err)
+			assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+		}
+	})
+}
+
+// EOF: internal/blockchain/evm/rawtx_test.go
@@ -6,79 +6,214 @@ package evm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// WaitForReceipt polls for a transaction receipt until it is mined or the context is cancelled.
-// It waits for the specified number of confirmations (blocks after the receipt block).
-// Returns the receipt and the number of blocks it has been confirmed.
-func (c *Client) WaitForReceipt(ctx context.Context, txHash common.Hash, confirmations uint64) (*types.Receipt, uint64, error) {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+// ErrReorged indicates that a chain reorganisation removed a transaction's
+// block after it had gained more confirmations than the configured maximum
+// reorg depth (see WithMaxReorgDepth).
+var ErrReorged = errors.New("transaction block reorganised")
 
-	var receipt *types.Receipt
-	var err error
+// ReceiptResult is the outcome of waiting for a transaction receipt.
+type ReceiptResult struct {
+	Receipt *types.Receipt
+	// Confirmations is the number of blocks mined on top of the receipt's block.
+	Confirmations uint64
+	// BlockHash is the hash of the including block, verified to be canonical
+	// at the last poll.
+	BlockHash common.Hash
+	// Reorgs counts how often the including block was replaced while waiting.
+	Reorgs int
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, 0, ctx.Err()
-		case <-ticker.C:
-			receipt, err = c.ec.TransactionReceipt(ctx, txHash)
-			if err != nil {
-				// If not found, continue polling.
-				continue
-			}
-			if receipt != nil {
-				// Receipt found; check confirmations.
-				currentBlock, err := c.ec.BlockNumber(ctx)
-				if err != nil {
-					continue
-				}
-				blocks := currentBlock - receipt.BlockNumber.Uint64()
-				if blocks >= confirmations {
-					return receipt, blocks, nil
-				}
-			}
-		}
-	}
+// receiptPollInterval is the fixed polling interval of WaitForReceipt.
+const receiptPollInterval = 1 * time.Second
+
+// WaitForReceipt waits for a transaction receipt until it is mined or the context is cancelled.
+// It waits for the specified number of confirmations (blocks after the receipt block).
+// On transports that support subscriptions (see SupportsSubscriptions) it
+// checks once per new head; otherwise, or if the subscription fails, it
+// polls every second.
+// Every poll verifies that the receipt's block is still canonical; if a
+// reorg replaced it, the confirmation count restarts from the transaction's
+// new block, and reorgs deeper than WithMaxReorgDepth fail with ErrReorged.
+// With WithRevertReasons, a failed transaction also returns a *TransactionRevertedError.
+func (c *Client) WaitForReceipt(ctx context.Context, txHash common.Hash, confirmations uint64) (*ReceiptResult, error) {
+	return c.waitForReceipt(ctx, txHash, confirmations, func() time.Duration {
+		return receiptPollInterval
+	})
 }
 
-// WaitForReceiptWithBackoff polls with exponential backoff.
-func (c *Client) WaitForReceiptWithBackoff(ctx context.Context, txHash common.Hash, confirmations uint64) (*types.Receipt, uint64, error) {
+// WaitForReceiptWithBackoff is WaitForReceipt with exponential polling
+// backoff. The backoff only applies when polling.
+func (c *Client) WaitForReceiptWithBackoff(ctx context.Context, txHash common.Hash, confirmations uint64) (*ReceiptResult, error) {
 	backoff := 500 * time.Millisecond
 	maxBackoff := 30 * time.Second
 	const factor = 1.5
 
+	return c.waitForReceipt(ctx, txHash, confirmations, func() time.Duration {
+		d := backoff
+		backoff = time.Duration(float64(backoff) * factor)
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		return d
+	})
+}
+
+// waitForReceipt polls until the receipt has the requested confirmations,
+// after every new head if the transport supports subscriptions and
+// otherwise sleeping next() between polls. Transient RPC errors are retried
+// by polling again.
+func (c *Client) waitForReceipt(ctx context.Context, txHash common.Hash, confirmations uint64, next func() time.Duration) (*ReceiptResult, error) {
+	result := &ReceiptResult{}
+	var seen uint64 // confirmations of the tracked receipt at the last poll
+
+	var heads chan *types.Header
+	var sub ethereum.Subscription
+	if c.SupportsSubscriptions() {
+		heads = make(chan *types.Header, 1)
+		s, err := c.subscribeNewHeads(ctx, heads)
+		if err != nil {
+			c.logger.Debug("newHeads subscription failed, polling for receipt", map[string]interface{}{
+				"tx_hash": txHash.Hex(),
+				"error":   err.Error(),
+			})
+		} else {
+			sub = s
+			defer s.Unsubscribe()
+		}
+	}
+
 	for {
+		if done, err := c.pollReceipt(ctx, txHash, confirmations, result, &seen); done || err != nil {
+			return result, err
+		}
+		if sub != nil {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-heads:
+			case err := <-sub.Err():
+				c.logger.Warn("newHeads subscription dropped, polling for receipt", map[string]interface{}{
+					"tx_hash": txHash.Hex(),
+					"error":   fmt.Sprint(err),
+				})
+				sub = nil
+			}
+			continue
+		}
 		select {
 		case <-ctx.Done():
-			return nil, 0, ctx.Err()
-		default:
+			return nil, ctx.Err()
+		case <-c.clock.After(next()):
 		}
+	}
+}
 
-		receipt, err := c.ec.TransactionReceipt(ctx, txHash)
-		if err == nil && receipt != nil {
-			currentBlock, err := c.ec.BlockNumber(ctx)
-			if err == nil {
-				blocks := currentBlock - receipt.BlockNumber.Uint64()
-				if blocks >= confirmations {
-					return receipt, blocks, nil
-				}
-			}
+// pollReceipt performs one poll for waitForReceipt, updating result.
+// It reports whether the receipt reached the requested confirmations.
+func (c *Client) pollReceipt(ctx context.Context, txHash common.Hash, confirmations uint64, result *ReceiptResult, seen *uint64) (bool, error) {
+	if result.Receipt == nil {
+		receipt, err := c.transactionReceipt(ctx, txHash)
+		if err != nil || receipt == nil {
+			// Not mined yet (or a transient error); keep polling.
+			return false, nil
 		}
+		result.Receipt, result.BlockHash, *seen = receipt, receipt.BlockHash, 0
+	}
 
-		// Wait before next attempt.
-		time.Sleep(backoff)
-		backoff = time.Duration(float64(backoff) * factor)
-		if backoff > maxBackoff {
-			backoff = maxBackoff
+	// Verify the including block is still canonical.
+	number := result.Receipt.BlockNumber
+	header, err := c.headerByNumberOnce(ctx, number)
+	if err != nil {
+		return false, nil
+	}
+	if header.Hash() != result.BlockHash {
+		result.Reorgs++
+		depth := *seen + 1 // the including block and those seen on top of it
+		c.logger.Warn("transaction block reorganised", map[string]interface{}{
+			"tx_hash":    txHash.Hex(),
+			"block":      number.Uint64(),
+			"block_hash": result.BlockHash.Hex(),
+			"depth":      depth,
+		})
+		result.Receipt, result.BlockHash, result.Confirmations = nil, common.Hash{}, 0
+		if c.maxReorgDepth > 0 && depth > c.maxReorgDepth {
+			return false, fmt.Errorf("WaitForReceipt: %s: reorg of %d blocks: %w", txHash.Hex(), depth, ErrReorged)
 		}
+		return false, nil
+	}
+
+	head, err := c.blockNumberOnce(ctx)
+	if err != nil {
+		return false, nil
+	}
+	result.Confirmations = 0
+	if head > number.Uint64() { // a lagging node may report an older head
+		result.Confirmations = head - number.Uint64()
+	}
+	*seen = result.Confirmations
+	if result.Confirmations < confirmations {
+		return false, nil
+	}
+	return true, c.receiptError(ctx, result.Receipt)
+}
+
+// receiptError returns the error reported for a confirmed receipt: nil, or
+// a *TransactionRevertedError for a failure when WithRevertReasons is set.
+func (c *Client) receiptError(ctx context.Context, receipt *types.Receipt) error {
+	if !c.revertReasons || receipt.Status != types.ReceiptStatusFailed {
+		return nil
+	}
+	return c.revertedError(ctx, receipt)
+}
+
+// transactionReceipt fetches a receipt once, bounded by the per‑attempt timeout.
+// Polling loops call it repeatedly instead of going through withRetry.
+func (c *Client) transactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if err := c.connect(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.limiter.wait(ctx, c.clock, 1); err != nil {
+		return nil, err
+	}
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	return c.eth().TransactionReceipt(attemptCtx, txHash)
+}
+
+// headerByNumberOnce fetches a block header once, bounded by the per‑attempt timeout.
+func (c *Client) headerByNumberOnce(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := c.connect(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.limiter.wait(ctx, c.clock, 1); err != nil {
+		return nil, err
+	}
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	return c.eth().HeaderByNumber(attemptCtx, number)
+}
+
+// blockNumberOnce fetches the head block number once, bounded by the per‑attempt timeout.
+func (c *Client) blockNumberOnce(ctx context.Context) (uint64, error) {
+	if err := c.connect(ctx); err != nil {
+		return 0, err
+	}
+	if err := c.limiter.wait(ctx, c.clock, 1); err != nil {
+		return 0, err
 	}
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	return c.eth().BlockNumber(attemptCtx)
 }
 
 // EOF: internal/blockchain/evm/receipt.go
\ No newline at end of file
@@ -0,0 +1,280 @@
+// Package evm provides a wallet implementing blockchain.Wallet that
+// delegates signing to a remote signer such as Web3Signer.
+//
+// File: internal/blockchain/evm/remotesigner.go
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// DefaultRemoteSignerTimeout bounds each request of a RemoteSignerWallet.
+const DefaultRemoteSignerTimeout = 10 * time.Second
+
+// RemoteSignerWallet implements TxSigner by sending eth_signTransaction to
+// a JSON‑RPC signer such as Consensys Web3Signer (eth1 mode), which holds
+// the key. The signed transaction is broadcast by the gateway as usual.
+// The signer cannot sign bare digests, so Sign (and with it message
+// signing) is unsupported. A RemoteSignerWallet is safe for concurrent use.
+type RemoteSignerWallet struct {
+	httpClient *http.Client
+	url        string
+	address    common.Address
+	timeout    time.Duration
+
+	// TLS client certificate and CA, loaded by NewRemoteSignerWallet.
+	certFile, keyFile, caFile string
+}
+
+// RemoteSignerOption configures a RemoteSignerWallet.
+type RemoteSignerOption func(*RemoteSignerWallet)
+
+// WithRemoteSignerTLS authenticates to the signer with the PEM client
+// certificate and key, and verifies the signer against caFile ("" = the
+// system roots). It is ignored if WithRemoteSignerHTTPClient is given.
+func WithRemoteSignerTLS(certFile, keyFile, caFile string) RemoteSignerOption {
+	return func(w *RemoteSignerWallet) {
+		w.certFile, w.keyFile, w.caFile = certFile, keyFile, caFile
+	}
+}
+
+// WithRemoteSignerHTTPClient sets the client for signer requests.
+func WithRemoteSignerHTTPClient(client *http.Client) RemoteSignerOption {
+	return func(w *RemoteSignerWallet) {
+		w.httpClient = client
+	}
+}
+
+// WithRemoteSignerTimeout bounds each signer request
+// (0 = DefaultRemoteSignerTimeout).
+func WithRemoteSignerTimeout(timeout time.Duration) RemoteSignerOption {
+	return func(w *RemoteSignerWallet) {
+		if timeout > 0 {
+			w.timeout = timeout
+		}
+	}
+}
+
+// NewRemoteSignerWallet creates a wallet signing as account through the
+// JSON‑RPC signer at url. As a health check it fails unless the signer is
+// reachable and lists account in eth_accounts.
+func NewRemoteSignerWallet(ctx context.Context, url, account string, opts ...RemoteSignerOption) (*RemoteSignerWallet, error) {
+	if url == "" {
+		return nil, errors.New("remote signer: empty URL")
+	}
+	address, err := parseAddress(account)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: account: %w", err)
+	}
+	w := &RemoteSignerWallet{url: url, address: address, timeout: DefaultRemoteSignerTimeout}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.httpClient == nil {
+		client, err := w.tlsClient()
+		if err != nil {
+			return nil, fmt.Errorf("remote signer: %w", err)
+		}
+		w.httpClient = client
+	}
+
+	var accounts []string
+	if err := w.call(ctx, "eth_accounts", nil, &accounts); err != nil {
+		return nil, fmt.Errorf("remote signer: health check: %w", err)
+	}
+	for _, a := range accounts {
+		if common.IsHexAddress(a) && common.HexToAddress(a) == address {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("remote signer: %s does not hold account %s", url, address.Hex())
+}
+
+// tlsClient returns an HTTP client with the configured client certificate
+// and CA, or http.DefaultClient if none is configured.
+func (w *RemoteSignerWallet) tlsClient() (*http.Client, error) {
+	if w.certFile == "" && w.keyFile == "" && w.caFile == "" {
+		return http.DefaultClient, nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if w.certFile != "" || w.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(w.certFile, w.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if w.caFile != "" {
+		pemData, err := os.ReadFile(w.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("CA %s: no certificates found", w.caFile)
+		}
+		cfg.RootCAs = pool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = cfg
+	return &http.Client{Transport: transport}, nil
+}
+
+// SignTx implements TxSigner.
+// A refusal by the signer, such as a policy rejection, is returned as a
+// *blockchain.SignerRejectedError.
+func (w *RemoteSignerWallet) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := map[string]interface{}{
+		"from":    w.address,
+		"gas":     hexutil.Uint64(tx.Gas()),
+		"nonce":   hexutil.Uint64(tx.Nonce()),
+		"value":   (*hexutil.Big)(tx.Value()),
+		"data":    hexutil.Bytes(tx.Data()),
+		"chainId": (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		args["to"] = tx.To()
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args["gasPrice"] = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args["maxFeePerGas"] = (*hexutil.Big)(tx.GasFeeCap())
+		args["maxPriorityFeePerGas"] = (*hexutil.Big)(tx.GasTipCap())
+		if len(tx.AccessList()) > 0 {
+			args["accessList"] = tx.AccessList()
+		}
+	default:
+		return nil, fmt.Errorf("remote signer: transaction type %d: %w", tx.Type(), errors.ErrUnsupported)
+	}
+
+	var result json.RawMessage
+	if err := w.call(ctx, "eth_signTransaction", []interface{}{args}, &result); err != nil {
+		var rpcErr *remoteSignerError
+		if errors.As(err, &rpcErr) {
+			return nil, &blockchain.SignerRejectedError{
+				Signer:  w.url,
+				Account: w.address.Hex(),
+				Code:    rpcErr.Code,
+				Reason:  rpcErr.Message,
+			}
+		}
+		return nil, fmt.Errorf("remote signer: sign: %w", err)
+	}
+	// Web3Signer returns the raw transaction; geth‑style signers return
+	// {"raw": ..., "tx": ...}.
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err != nil {
+		var wrapped struct {
+			Raw hexutil.Bytes `json:"raw"`
+		}
+		if err := json.Unmarshal(result, &wrapped); err != nil || len(wrapped.Raw) == 0 {
+			return nil, errors.New("remote signer: sign: unexpected result")
+		}
+		raw = wrapped.Raw
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("remote signer: decode signed transaction: %w", err)
+	}
+	return signed, nil
+}
+
+// Sign implements blockchain.Wallet. Remote signers only sign whole
+// transactions, so it always fails with errors.ErrUnsupported.
+func (w *RemoteSignerWallet) Sign([]byte) ([]byte, error) {
+	return nil, fmt.Errorf("remote signer: signing digests: %w", errors.ErrUnsupported)
+}
+
+// Address implements blockchain.Wallet.
+func (w *RemoteSignerWallet) Address() string {
+	return w.address.Hex()
+}
+
+// remoteSignerError is an error reported by the signer itself: a JSON‑RPC
+// error object, or an HTTP 401/403 (Code is then the status).
+type remoteSignerError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error returns the signer's message and code.
+func (e *remoteSignerError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// call sends a JSON‑RPC request to the signer and decodes its result into
+// out.
+func (w *RemoteSignerWallet) call(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return &remoteSignerError{Code: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+
+	var reply struct {
+		Result json.RawMessage    `json:"result"`
+		Error  *remoteSignerError `json:"error"`
+	}
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return fmt.Errorf("http %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if reply.Error != nil {
+		return reply.Error
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(reply.Result, out); err != nil {
+		return fmt.Errorf("decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+// EOF: internal/blockchain/evm/remotesigner.go
@@ -0,0 +1,274 @@
+// Package evm_test tests the remote signer wallet against a stub
+// Web3Signer.
+//
+// File: internal/blockchain/evm/remotesigner_test.go
+
+package evm_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// stubSigner answers eth_accounts and eth_signTransaction like Web3Signer,
+// signing with a local key. It rejects transactions above maxValue.
+type stubSigner struct {
+	key      *ecdsa.PrivateKey
+	maxValue *big.Int
+
+	mu     sync.Mutex
+	lastTx map[string]interface{}
+	signs  int
+	tamper bool // signs a different nonce than requested
+}
+
+func newStubSigner(t *testing.T) *stubSigner {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return &stubSigner{key: key, maxValue: big.NewInt(1e15)}
+}
+
+func (s *stubSigner) address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *stubSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reply := func(result interface{}, rpcErr map[string]interface{}) {
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if rpcErr != nil {
+			resp["error"] = rpcErr
+		} else {
+			resp["result"] = result
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+
+	switch req.Method {
+	case "eth_accounts":
+		reply([]string{s.address().Hex()}, nil)
+	case "eth_signTransaction":
+		var args struct {
+			From                 common.Address  `json:"from"`
+			To                   *common.Address `json:"to"`
+			Gas                  hexutil.Uint64  `json:"gas"`
+			Nonce                hexutil.Uint64  `json:"nonce"`
+			Value                *hexutil.Big    `json:"value"`
+			Data                 hexutil.Bytes   `json:"data"`
+			ChainID              *hexutil.Big    `json:"chainId"`
+			GasPrice             *hexutil.Big    `json:"gasPrice"`
+			MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+			MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+		}
+		if len(req.Params) != 1 || json.Unmarshal(req.Params[0], &args) != nil {
+			reply(nil, map[string]interface{}{"code": -32602, "message": "invalid params"})
+			return
+		}
+		s.mu.Lock()
+		s.signs++
+		_ = json.Unmarshal(req.Params[0], &s.lastTx)
+		tamper := s.tamper
+		s.mu.Unlock()
+		if args.From != s.address() {
+			reply(nil, map[string]interface{}{"code": -32000, "message": "signer not found"})
+			return
+		}
+		if args.Value.ToInt().Cmp(s.maxValue) > 0 {
+			reply(nil, map[string]interface{}{"code": -32000, "message": "value exceeds signing policy"})
+			return
+		}
+		nonce := uint64(args.Nonce)
+		if tamper {
+			nonce++
+		}
+		var tx *types.Transaction
+		if args.MaxFeePerGas != nil {
+			tx = types.NewTx(&types.DynamicFeeTx{
+				ChainID: args.ChainID.ToInt(), Nonce: nonce, To: args.To, Value: args.Value.ToInt(), Gas: uint64(args.Gas),
+				GasFeeCap: args.MaxFeePerGas.ToInt(), GasTipCap: args.MaxPriorityFeePerGas.ToInt(), Data: args.Data,
+			})
+		} else {
+			tx = types.NewTx(&types.LegacyTx{
+				Nonce: nonce, To: args.To, Value: args.Value.ToInt(), Gas: uint64(args.Gas),
+				GasPrice: args.GasPrice.ToInt(), Data: args.Data,
+			})
+		}
+		signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), s.key)
+		if err != nil {
+			reply(nil, map[string]interface{}{"code": -32603, "message": err.Error()})
+			return
+		}
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			reply(nil, map[string]interface{}{"code": -32603, "message": err.Error()})
+			return
+		}
+		reply(hexutil.Encode(raw), nil)
+	default:
+		reply(nil, map[string]interface{}{"code": -32601, "message": "method not found"})
+	}
+}
+
+func TestRemoteSignerWallet_SendsThroughGateway(t *testing.T) {
+	stub := newStubSigner(t)
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+	ctx := context.Background()
+
+	wallet, err := evm.NewRemoteSignerWallet(ctx, srv.URL, stub.address().Hex())
+	require.NoError(t, err)
+	assert.Equal(t, stub.address().Hex(), wallet.Address())
+
+	sim := simulated.NewBackend(types.GenesisAlloc{stub.address(): {Balance: big.NewInt(1e18)}})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	txHash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1000)})
+	require.NoError(t, err)
+	sim.Commit()
+
+	receipt, err := sim.Client().TransactionReceipt(ctx, common.HexToHash(txHash))
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	balance, err := sim.Client().BalanceAt(ctx, common.HexToAddress(to), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), balance.Int64())
+
+	stub.mu.Lock()
+	assert.Equal(t, 1, stub.signs)
+	assert.Equal(t, "0x3e8", stub.lastTx["value"])
+	assert.Contains(t, stub.lastTx, "chainId")
+	stub.mu.Unlock()
+
+	// A policy rejection by the signer is typed; nothing is broadcast.
+	_, err = gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1e16)})
+	var rejected *blockchain.SignerRejectedError
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, -32000, rejected.Code)
+	assert.Equal(t, "value exceeds signing policy", rejected.Reason)
+	assert.Equal(t, stub.address().Hex(), rejected.Account)
+
+	// A signer returning another transaction than requested is caught.
+	stub.mu.Lock()
+	stub.tamper = true
+	stub.mu.Unlock()
+	_, err = gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1)})
+	assert.ErrorContains(t, err, "different transaction")
+
+	_, err = wallet.Sign(crypto.Keccak256([]byte("hello")))
+	assert.ErrorIs(t, err, errors.ErrUnsupported)
+}
+
+func TestRemoteSignerWallet_HealthCheck(t *testing.T) {
+	ctx := context.Background()
+	stub := newStubSigner(t)
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = evm.NewRemoteSignerWallet(ctx, srv.URL, crypto.PubkeyToAddress(other.PublicKey).Hex())
+	assert.ErrorContains(t, err, "does not hold account")
+
+	_, err = evm.NewRemoteSignerWallet(ctx, srv.URL, "not-an-address")
+	assert.ErrorIs(t, err, evm.ErrInvalidAddress)
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	_, err = evm.NewRemoteSignerWallet(ctx, down.URL, stub.address().Hex())
+	assert.ErrorContains(t, err, "health check")
+}
+
+func TestRemoteSignerWallet_ClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	clientCert := writeClientCertificate(t, certFile, keyFile)
+
+	stub := newStubSigner(t)
+	srv := httptest.NewUnstartedServer(stub)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+
+	ctx := context.Background()
+	_, err := evm.NewRemoteSignerWallet(ctx, srv.URL, stub.address().Hex(),
+		evm.WithRemoteSignerTLS("", "", caFile))
+	assert.Error(t, err, "the signer requires a client certificate")
+
+	wallet, err := evm.NewRemoteSignerWallet(ctx, srv.URL, stub.address().Hex(),
+		evm.WithRemoteSignerTLS(certFile, keyFile, caFile))
+	require.NoError(t, err)
+	assert.Equal(t, stub.address().Hex(), wallet.Address())
+}
+
+// writeClientCertificate writes a self‑signed client certificate and its
+// key as PEM and returns the certificate.
+func writeClientCertificate(t *testing.T, certFile, keyFile string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "lola-agent"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+// EOF: internal/blockchain/evm/remotesigner_test.go
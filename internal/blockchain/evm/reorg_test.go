@@ -0,0 +1,150 @@
+// Package evm_test tests reorg‑aware receipt confirmation.
+//
+// File: internal/blockchain/evm/reorg_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// reorgNode simulates a chain on which the block including a transaction
+// is replaced once: before the reorg the transaction sits in block 10 on
+// fork A, afterwards in block 11 on fork B.
+type reorgNode struct {
+	mu      sync.Mutex
+	txHash  common.Hash
+	head    uint64 // head while fork A is canonical
+	headB   uint64 // head once fork B is canonical
+	checksA int    // header lookups of block 10 before the reorg happens
+	reorged bool
+}
+
+// header returns block n of fork A or B; forks differ in their extra data.
+func header(n uint64, fork string) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(n), Difficulty: new(big.Int), Extra: []byte(fork)}
+}
+
+func (n *reorgNode) receipt(block uint64, fork string) *types.Receipt {
+	return &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      n.txHash,
+		BlockNumber: new(big.Int).SetUint64(block),
+		BlockHash:   header(block, fork).Hash(),
+		Logs:        []*types.Log{},
+	}
+}
+
+func (n *reorgNode) results() map[string]interface{} {
+	return map[string]interface{}{
+		"eth_getTransactionReceipt": func([]json.RawMessage) interface{} {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			if n.reorged {
+				return n.receipt(11, "B")
+			}
+			return n.receipt(10, "A")
+		},
+		"eth_getBlockByNumber": func(params []json.RawMessage) interface{} {
+			var s string
+			_ = json.Unmarshal(params[0], &s)
+			num := hexutil.MustDecodeUint64(s)
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			if num == 10 && !n.reorged {
+				if n.checksA == 0 {
+					n.reorged = true
+					return header(num, "B")
+				}
+				n.checksA--
+			}
+			if n.reorged {
+				return header(num, "B")
+			}
+			return header(num, "A")
+		},
+		"eth_blockNumber": func([]json.RawMessage) interface{} {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			if n.reorged {
+				return hexutil.Uint64(n.headB)
+			}
+			return hexutil.Uint64(n.head)
+		},
+	}
+}
+
+func newReorgClient(t *testing.T, node *reorgNode, opts ...evm.ClientOption) *evm.Client {
+	t.Helper()
+	srv := newFakeNode(t, node.results())
+	client, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{},
+		&evm.RetryConfig{MaxAttempts: 1}, time.Second, opts...)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	client.SetClock(&fakeClock{})
+	return client
+}
+
+func TestClient_WaitForReceipt_Reorg(t *testing.T) {
+	txHash := common.HexToHash("0xabc")
+
+	t.Run("shallow reorg restarts confirmation", func(t *testing.T) {
+		// Block 10 is seen canonical once with no confirmations, then replaced.
+		node := &reorgNode{txHash: txHash, head: 10, headB: 13, checksA: 1}
+		client := newReorgClient(t, node)
+
+		result, err := client.WaitForReceipt(context.Background(), txHash, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Reorgs)
+		assert.Equal(t, header(11, "B").Hash(), result.BlockHash)
+		assert.Equal(t, uint64(11), result.Receipt.BlockNumber.Uint64())
+		assert.Equal(t, uint64(2), result.Confirmations)
+	})
+
+	t.Run("no reorg", func(t *testing.T) {
+		node := &reorgNode{txHash: txHash, head: 12, checksA: 100}
+		client := newReorgClient(t, node)
+
+		result, err := client.WaitForReceipt(context.Background(), txHash, 2)
+		require.NoError(t, err)
+		assert.Zero(t, result.Reorgs)
+		assert.Equal(t, header(10, "A").Hash(), result.BlockHash)
+	})
+
+	t.Run("reorg deeper than limit", func(t *testing.T) {
+		// Block 10 gains one confirmation on fork A before being replaced:
+		// a reorg of two blocks.
+		node := &reorgNode{txHash: txHash, head: 11, headB: 13, checksA: 1}
+		client := newReorgClient(t, node, evm.WithMaxReorgDepth(1))
+
+		result, err := client.WaitForReceipt(context.Background(), txHash, 5)
+		require.ErrorIs(t, err, evm.ErrReorged)
+		assert.Equal(t, 1, result.Reorgs)
+		assert.ErrorContains(t, err, "reorg of 2 blocks")
+	})
+
+	t.Run("reorg within limit", func(t *testing.T) {
+		node := &reorgNode{txHash: txHash, head: 11, headB: 13, checksA: 1}
+		client := newReorgClient(t, node, evm.WithMaxReorgDepth(2))
+
+		result, err := client.WaitForReceipt(context.Background(), txHash, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Reorgs)
+	})
+}
+
+// EOF: internal/blockchain/evm/reorg_test.go
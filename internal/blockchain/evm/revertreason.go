@@ -0,0 +1,105 @@
+// Package evm explains why mined transactions failed.
+//
+// File: internal/blockchain/evm/revertreason.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrTransactionReverted indicates a mined transaction failed (receipt
+// status 0). WaitForReceipt reports it as a *TransactionRevertedError when
+// the client was created with WithRevertReasons.
+var ErrTransactionReverted = errors.New("transaction reverted")
+
+// TransactionRevertedError describes a failed transaction.
+// errors.Is(err, ErrTransactionReverted) matches it.
+type TransactionRevertedError struct {
+	TxHash common.Hash
+	// Receipt is the failed receipt.
+	Receipt *types.Receipt
+	// Reason is the decoded revert reason; empty if it could not be
+	// determined.
+	Reason string
+}
+
+// Error returns the transaction hash and reason.
+func (e *TransactionRevertedError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("transaction %s reverted", e.TxHash.Hex())
+	}
+	return fmt.Sprintf("transaction %s reverted: %s", e.TxHash.Hex(), e.Reason)
+}
+
+// Unwrap returns ErrTransactionReverted.
+func (e *TransactionRevertedError) Unwrap() error {
+	return ErrTransactionReverted
+}
+
+// GetRevertReason explains why a mined transaction failed by replaying it
+// as an eth_call at the block it was mined in. The result is the decoded
+// revert reason (see DecodeRevert), "execution reverted" for a revert
+// without data, or the node's execution error such as "out of gas".
+func (c *Client) GetRevertReason(ctx context.Context, txHash common.Hash) (string, error) {
+	result, err := c.withRetry(ctx, "TransactionReceipt", func(ctx context.Context) (interface{}, error) {
+		return c.eth().TransactionReceipt(ctx, txHash)
+	})
+	if err != nil {
+		return "", fmt.Errorf("GetRevertReason: receipt: %w", err)
+	}
+	return c.revertReason(ctx, result.(*types.Receipt))
+}
+
+// revertReason replays the transaction of a failed receipt.
+func (c *Client) revertReason(ctx context.Context, receipt *types.Receipt) (string, error) {
+	if receipt.Status != types.ReceiptStatusFailed {
+		return "", fmt.Errorf("GetRevertReason: transaction %s did not fail", receipt.TxHash.Hex())
+	}
+	tx, _, err := c.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return "", fmt.Errorf("GetRevertReason: transaction: %w", err)
+	}
+	from, err := txSender(tx)
+	if err != nil {
+		return "", fmt.Errorf("GetRevertReason: sender: %w", err)
+	}
+
+	_, err = c.CallContract(ctx, callMsgFromTx(from, tx), receipt.BlockNumber)
+	if err == nil {
+		return "", fmt.Errorf("GetRevertReason: transaction %s does not fail when replayed", receipt.TxHash.Hex())
+	}
+	if res, ok := c.revertResult(err); ok {
+		if res.RevertReason == "" {
+			return ErrReverted.Error(), nil
+		}
+		return res.RevertReason, nil
+	}
+	// The node executed the call and reported why it failed.
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Error(), nil
+	}
+	return "", fmt.Errorf("GetRevertReason: replay: %w", err)
+}
+
+// revertedError builds the error WaitForReceipt returns for a failed
+// receipt. A failed reason lookup is logged and leaves Reason empty.
+func (c *Client) revertedError(ctx context.Context, receipt *types.Receipt) error {
+	reason, err := c.revertReason(ctx, receipt)
+	if err != nil {
+		c.logger.Warn("could not determine revert reason", map[string]interface{}{
+			"tx_hash": receipt.TxHash.Hex(),
+			"error":   err.Error(),
+		})
+	}
+	return &TransactionRevertedError{TxHash: receipt.TxHash, Receipt: receipt, Reason: reason}
+}
+
+// EOF: internal/blockchain/evm/revertreason.go
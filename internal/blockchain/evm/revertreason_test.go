@@ -0,0 +1,95 @@
+// Package evm_test tests revert reason lookup for mined transactions.
+//
+// File: internal/blockchain/evm/revertreason_test.go
+
+package evm_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+func TestClient_GetRevertReason(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+
+	nope := common.HexToAddress("0x1001")
+	custom := common.HexToAddress("0x1002")
+	bare := common.HexToAddress("0x1003")
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+		nope:                                  {Code: revertNopeRuntime, Balance: new(big.Int)},
+		custom:                                {Code: revertingRuntime(common.FromHex("deadbeef")), Balance: new(big.Int)},
+		bare:                                  {Code: revertingRuntime(nil), Balance: new(big.Int)},
+	})
+	defer sim.Close()
+	ec := reflect.ValueOf(sim.Client()).Field(0).Interface().(*ethclient.Client)
+	client := evm.NewClientFromEthClient(ec, &observe.NoopLogger{}, nil, evm.WithRevertReasons())
+	gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, wallet)
+	ctx := context.Background()
+
+	// An explicit gas limit skips estimation, so the reverting call is mined.
+	send := func(to common.Address, gas uint64) common.Hash {
+		addr := to.Hex()
+		hash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{
+			To: &addr, Value: new(big.Int), Gas: gas, GasPrice: big.NewInt(10e9),
+		})
+		require.NoError(t, err)
+		sim.Commit()
+		return common.HexToHash(hash)
+	}
+
+	tests := []struct {
+		name   string
+		to     common.Address
+		gas    uint64
+		reason string
+	}{
+		{"error string", nope, 100000, "nope"},
+		{"custom error", custom, 100000, "custom error 0xdeadbeef"},
+		{"no data", bare, 100000, "execution reverted"},
+		{"out of gas", nope, 21010, "out of gas"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := client.GetRevertReason(ctx, send(tt.to, tt.gas))
+			require.NoError(t, err)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+
+	t.Run("successful transaction", func(t *testing.T) {
+		_, err := client.GetRevertReason(ctx, send(common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"), 21000))
+		assert.ErrorContains(t, err, "did not fail")
+	})
+
+	t.Run("WaitForReceipt", func(t *testing.T) {
+		hash := send(nope, 100000)
+		result, err := client.WaitForReceipt(ctx, hash, 0)
+		require.ErrorIs(t, err, evm.ErrTransactionReverted)
+		require.NotNil(t, result.Receipt)
+		assert.Equal(t, types.ReceiptStatusFailed, result.Receipt.Status)
+
+		var reverted *evm.TransactionRevertedError
+		require.True(t, errors.As(err, &reverted))
+		assert.Equal(t, "nope", reverted.Reason)
+		assert.Equal(t, "transaction "+hash.Hex()+" reverted: nope", err.Error())
+	})
+}
+
+// EOF: internal/blockchain/evm/revertreason_test.go
@@ -0,0 +1,116 @@
+// Package evm caches RPC results that do not change within a block.
+//
+// File: internal/blockchain/evm/rpccache.go
+
+package evm
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Cached RPC methods.
+const (
+	cacheChainID   = "ChainID"
+	cacheGasPrice  = "SuggestGasPrice"
+	cacheGasTipCap = "SuggestGasTipCap"
+)
+
+// feeCacheKeys are the entries dropped by InvalidateFees.
+var feeCacheKeys = []string{cacheGasPrice, cacheGasTipCap}
+
+// noCacheKey marks a context whose calls bypass the RPC cache.
+type noCacheKey struct{}
+
+// ContextWithoutCache returns a context whose Client calls always query the
+// node. The fresh results still refresh the cache for other callers.
+func ContextWithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// cacheBypassed reports whether ctx was made by ContextWithoutCache.
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(noCacheKey{}).(bool)
+	return bypass
+}
+
+// rpcCacheEntry is a cached result; a zero expires never expires.
+type rpcCacheEntry struct {
+	value   *big.Int
+	expires time.Time
+}
+
+// rpcCache holds recent results keyed by method. It is safe for concurrent
+// use; concurrent misses each query the node.
+type rpcCache struct {
+	mu      sync.Mutex
+	entries map[string]rpcCacheEntry
+}
+
+func newRPCCache() *rpcCache {
+	return &rpcCache{entries: make(map[string]rpcCacheEntry)}
+}
+
+// get returns a copy of the unexpired entry for key.
+func (r *rpcCache) get(key string, now time.Time) (*big.Int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[key]
+	if !ok || (!e.expires.IsZero() && !now.Before(e.expires)) {
+		return nil, false
+	}
+	return new(big.Int).Set(e.value), true
+}
+
+// put stores a copy of value; a zero expires keeps it forever.
+func (r *rpcCache) put(key string, value *big.Int, expires time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[key] = rpcCacheEntry{value: new(big.Int).Set(value), expires: expires}
+}
+
+// delete drops the entries for keys.
+func (r *rpcCache) delete(keys ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		delete(r.entries, key)
+	}
+}
+
+// cachedBigInt serves key from the cache, or calls fetch and caches its
+// result. ttl < 0 caches forever; ttl == 0 disables caching, which is the
+// case for fee suggestions when the block time is unknown.
+func (c *Client) cachedBigInt(ctx context.Context, key string, ttl time.Duration, fetch func(context.Context) (*big.Int, error)) (*big.Int, error) {
+	if ttl == 0 {
+		return fetch(ctx)
+	}
+	now := c.clock.Now()
+	if !cacheBypassed(ctx) {
+		if v, ok := c.cache.get(key, now); ok {
+			return v, nil
+		}
+	}
+	v, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var expires time.Time
+	if ttl > 0 {
+		expires = now.Add(ttl)
+	}
+	c.cache.put(key, v, expires)
+	return v, nil
+}
+
+// InvalidateFees drops cached fee suggestions and the fee history estimate,
+// so the next transaction is priced from fresh data. Call it after a
+// transaction was rejected as underpriced.
+func (c *Client) InvalidateFees() {
+	c.cache.delete(feeCacheKeys...)
+	c.fees.invalidate()
+}
+
+// EOF: internal/blockchain/evm/rpccache.go
@@ -0,0 +1,157 @@
+// Package evm_test tests caching of fee suggestions and chain metadata.
+//
+// File: internal/blockchain/evm/rpccache_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// countCalls wraps every result so that calls are counted per method.
+func countCalls(results map[string]interface{}) map[string]*atomic.Int32 {
+	counts := make(map[string]*atomic.Int32, len(results))
+	for method, result := range results {
+		n := new(atomic.Int32)
+		counts[method] = n
+		results[method] = func(params []json.RawMessage) interface{} {
+			n.Add(1)
+			if fn, ok := result.(func([]json.RawMessage) interface{}); ok {
+				return fn(params)
+			}
+			return result
+		}
+	}
+	return counts
+}
+
+func TestClient_CachesFeeSuggestions(t *testing.T) {
+	results := map[string]interface{}{
+		"eth_chainId":              "0x1",
+		"eth_gasPrice":             "0x3b9aca00",
+		"eth_maxPriorityFeePerGas": "0x5f5e100",
+	}
+	calls := countCalls(results)
+	client := newFeeClient(t, results, evm.WithBlockTime(12*time.Second))
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	client.SetClock(clock)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		price, err := client.SuggestGasPrice(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1e9), price)
+		tip, err := client.SuggestGasTipCap(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1e8), tip)
+		id, err := client.ChainID(ctx)
+		require.NoError(t, err)
+		id.SetInt64(99) // callers get copies
+	}
+	assert.Equal(t, int32(1), calls["eth_gasPrice"].Load())
+	assert.Equal(t, int32(1), calls["eth_maxPriorityFeePerGas"].Load())
+	assert.Equal(t, int32(1), calls["eth_chainId"].Load())
+
+	clock.Advance(12 * time.Second)
+	_, err := client.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	id, err := client.ChainID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), id)
+	assert.Equal(t, int32(2), calls["eth_gasPrice"].Load(), "expires after a block time")
+	assert.Equal(t, int32(1), calls["eth_chainId"].Load(), "chain id never expires")
+
+	client.InvalidateFees()
+	_, err = client.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls["eth_gasPrice"].Load(), "invalidated")
+
+	fresh := evm.ContextWithoutCache(ctx)
+	_, err = client.SuggestGasPrice(fresh)
+	require.NoError(t, err)
+	_, err = client.ChainID(fresh)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), calls["eth_gasPrice"].Load(), "bypassed")
+	assert.Equal(t, int32(2), calls["eth_chainId"].Load(), "bypassed")
+}
+
+func TestClient_FeeCacheDisabledWithoutBlockTime(t *testing.T) {
+	results := map[string]interface{}{"eth_gasPrice": "0x3b9aca00"}
+	calls := countCalls(results)
+	client := newFeeClient(t, results)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.SuggestGasPrice(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), calls["eth_gasPrice"].Load())
+}
+
+// newBurstGateway returns a gateway on a fake legacy chain and the total
+// number of RPC calls it has made.
+func newBurstGateway(tb testing.TB, blockTime time.Duration) (*evm.EVMGateway, func() int32) {
+	tb.Helper()
+	results := map[string]interface{}{
+		"eth_chainId":             "0x1",
+		"eth_getBlockByNumber":    &types.Header{Number: big.NewInt(100), Difficulty: new(big.Int)},
+		"eth_getTransactionCount": "0x7",
+		"eth_estimateGas":         "0x5208",
+		"eth_gasPrice":            "0x3b9aca00",
+		"eth_sendRawTransaction":  "0x0000000000000000000000000000000000000000000000000000000000000000",
+	}
+	calls := countCalls(results)
+	client := newFeeClient(tb, results, evm.WithBlockTime(blockTime))
+	wallet, err := evm.NewKeystore(filepath.Join(tb.TempDir(), "wallet.key"), "test")
+	require.NoError(tb, err)
+	total := func() int32 {
+		var n int32
+		for _, c := range calls {
+			n += c.Load()
+		}
+		return n
+	}
+	return evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, wallet), total
+}
+
+// BenchmarkTransferBurst sends bursts of 100 transfers within one block and
+// reports the RPC round trips per burst with and without the cache.
+func BenchmarkTransferBurst(b *testing.B) {
+	for _, bc := range []struct {
+		name      string
+		blockTime time.Duration
+	}{
+		{"uncached", 0},
+		{"cached", time.Hour},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			gateway, rpcs := newBurstGateway(b, bc.blockTime)
+			to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < 100; j++ {
+					if _, err := gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1)}); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			b.ReportMetric(float64(rpcs())/float64(b.N), "rpcs/burst")
+		})
+	}
+}
+
+// EOF: internal/blockchain/evm/rpccache_test.go
@@ -0,0 +1,459 @@
+// Package safe proposes and executes transactions of a Safe (formerly
+// Gnosis Safe) multisig. A SafeClient builds the Safe transaction, signs
+// its EIP‑712 hash with the chain's wallet as one of the owners, and
+// either submits it to the Safe Transaction Service for the other owners
+// to confirm or, with enough owner signatures, executes it on chain.
+//
+// File: internal/blockchain/evm/safe/safe.go
+
+package safe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+var (
+	// ErrNotOwner indicates a signer that is not an owner of the Safe.
+	ErrNotOwner = errors.New("not a Safe owner")
+	// ErrNotEnoughSignatures indicates fewer owner signatures than the
+	// Safe's threshold.
+	ErrNotEnoughSignatures = errors.New("not enough owner signatures")
+	// ErrNoTxService indicates a proposal on a chain without a Safe
+	// Transaction Service configured.
+	ErrNoTxService = errors.New("no Safe Transaction Service configured")
+)
+
+// Operation is how a Safe performs a transaction.
+type Operation uint8
+
+const (
+	// Call is a regular call from the Safe.
+	Call Operation = 0
+	// DelegateCall runs the target's code in the Safe's context.
+	DelegateCall Operation = 1
+)
+
+// safeABI holds the functions of the Safe contract (v1.3.0 and later)
+// the client uses.
+var safeABI = mustParseABI(`[
+	{"type":"function","name":"nonce","inputs":[],"outputs":[{"type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"getThreshold","inputs":[],"outputs":[{"type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"getOwners","inputs":[],"outputs":[{"type":"address[]"}],"stateMutability":"view"},
+	{"type":"function","name":"execTransaction","inputs":[
+		{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},
+		{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},
+		{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},
+		{"name":"refundReceiver","type":"address"},{"name":"signatures","type":"bytes"}],
+		"outputs":[{"type":"bool"}],"stateMutability":"payable"}
+]`)
+
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// SafeABI returns the ABI of the Safe functions the client uses.
+func SafeABI() abi.ABI {
+	return safeABI
+}
+
+var (
+	domainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)"))
+	safeTxTypeHash = crypto.Keccak256Hash([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation," +
+		"uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)"))
+)
+
+// SafeTx is a transaction of a Safe. The gas refund fields are zero for
+// transactions whose gas is paid by the executor, as built by BuildTx.
+type SafeTx struct {
+	Safe    common.Address
+	ChainID *big.Int
+
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      Operation
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          *big.Int
+}
+
+// Hash returns the EIP‑712 hash of tx that owners sign, as returned by
+// the Safe's getTransactionHash.
+func (tx *SafeTx) Hash() common.Hash {
+	domain := crypto.Keccak256(domainTypeHash[:], word(tx.ChainID), common.LeftPadBytes(tx.Safe[:], 32))
+	data := crypto.Keccak256(
+		safeTxTypeHash[:],
+		common.LeftPadBytes(tx.To[:], 32),
+		word(tx.Value),
+		crypto.Keccak256(tx.Data),
+		word(big.NewInt(int64(tx.Operation))),
+		word(tx.SafeTxGas),
+		word(tx.BaseGas),
+		word(tx.GasPrice),
+		common.LeftPadBytes(tx.GasToken[:], 32),
+		common.LeftPadBytes(tx.RefundReceiver[:], 32),
+		word(tx.Nonce),
+	)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domain, data)
+}
+
+// word encodes v as a 32‑byte ABI word; nil is zero.
+func word(v *big.Int) []byte {
+	if v == nil {
+		return make([]byte, 32)
+	}
+	return math.U256Bytes(new(big.Int).Set(v))
+}
+
+// Chain is what a SafeClient needs of a chain; *evm.EVMGateway
+// implements it. Proposals and executions are signed by Wallet.
+type Chain interface {
+	CallContract(ctx context.Context, call *blockchain.ContractCall) ([]byte, error)
+	SendTransaction(ctx context.Context, tx *blockchain.Transaction) (string, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+	Wallet() blockchain.Wallet
+}
+
+// SafeClient proposes and executes transactions of one Safe. It is safe
+// for concurrent use.
+type SafeClient struct {
+	chain      Chain
+	address    common.Address
+	service    string
+	httpClient *http.Client
+}
+
+// Option configures a SafeClient.
+type Option func(*SafeClient)
+
+// WithTxService sets the base URL of the chain's Safe Transaction Service
+// (e.g. https://safe-transaction-mainnet.safe.global), required by Propose.
+func WithTxService(url string) Option {
+	return func(c *SafeClient) {
+		c.service = strings.TrimRight(url, "/")
+	}
+}
+
+// WithHTTPClient sets the client for Transaction Service requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *SafeClient) {
+		c.httpClient = client
+	}
+}
+
+// NewSafeClient returns a client for the Safe at address on chain.
+func NewSafeClient(chain Chain, address string, opts ...Option) (*SafeClient, error) {
+	normalized, err := evm.NormalizeAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("safe: %w", err)
+	}
+	c := &SafeClient{chain: chain, address: common.HexToAddress(normalized), httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Address returns the address of the Safe.
+func (c *SafeClient) Address() string {
+	return c.address.Hex()
+}
+
+// call runs a view function of the Safe and returns its single result.
+func (c *SafeClient) call(ctx context.Context, method string) (interface{}, error) {
+	data, err := safeABI.Pack(method)
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.chain.CallContract(ctx, &blockchain.ContractCall{To: c.address.Hex(), Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("safe: %s: %w", method, err)
+	}
+	values, err := safeABI.Unpack(method, out)
+	if err != nil || len(values) != 1 {
+		return nil, fmt.Errorf("safe: %s: %s is not a Safe: unexpected result", method, c.address.Hex())
+	}
+	return values[0], nil
+}
+
+// Nonce returns the nonce of the Safe's next transaction.
+func (c *SafeClient) Nonce(ctx context.Context) (*big.Int, error) {
+	v, err := c.call(ctx, "nonce")
+	if err != nil {
+		return nil, err
+	}
+	return v.(*big.Int), nil
+}
+
+// Threshold returns the number of owner signatures a transaction needs.
+func (c *SafeClient) Threshold(ctx context.Context) (uint64, error) {
+	v, err := c.call(ctx, "getThreshold")
+	if err != nil {
+		return 0, err
+	}
+	return v.(*big.Int).Uint64(), nil
+}
+
+// Owners returns the owners of the Safe.
+func (c *SafeClient) Owners(ctx context.Context) ([]common.Address, error) {
+	v, err := c.call(ctx, "getOwners")
+	if err != nil {
+		return nil, err
+	}
+	return v.([]common.Address), nil
+}
+
+// BuildTx builds a transaction of the Safe calling to with value and data,
+// with the Safe's current nonce and no gas refund.
+func (c *SafeClient) BuildTx(ctx context.Context, to string, value *big.Int, data []byte, op Operation) (*SafeTx, error) {
+	target, err := evm.NormalizeAddress(to)
+	if err != nil {
+		return nil, fmt.Errorf("safe: to: %w", err)
+	}
+	if op != Call && op != DelegateCall {
+		return nil, fmt.Errorf("safe: unknown operation %d", op)
+	}
+	chainID, err := c.chain.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("safe: chain ID: %w", err)
+	}
+	nonce, err := c.Nonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+	return &SafeTx{
+		Safe:      c.address,
+		ChainID:   chainID,
+		To:        common.HexToAddress(target),
+		Value:     value,
+		Data:      data,
+		Operation: op,
+		SafeTxGas: new(big.Int),
+		BaseGas:   new(big.Int),
+		GasPrice:  new(big.Int),
+		Nonce:     nonce,
+	}, nil
+}
+
+// Sign signs the hash of tx with the chain's wallet and returns the
+// 65‑byte signature with V of 27 or 28, as the Safe expects.
+func (c *SafeClient) Sign(tx *SafeTx) ([]byte, error) {
+	wallet := c.chain.Wallet()
+	if wallet == nil {
+		return nil, errors.New("safe: no wallet configured, read‑only mode")
+	}
+	hash := tx.Hash()
+	sig, err := wallet.Sign(hash[:])
+	if err != nil {
+		return nil, fmt.Errorf("safe: sign: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("safe: sign: wallet returned %d‑byte signature", len(sig))
+	}
+	out := bytes.Clone(sig)
+	if out[64] < 27 {
+		out[64] += 27
+	}
+	return out, nil
+}
+
+// Signer recovers the account that produced sig over the hash of tx. Both
+// EIP‑712 signatures (V 27/28, or 0/1) and eth_sign signatures of the
+// hash (V 31/32) are accepted; contract signatures are not.
+func Signer(tx *SafeTx, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("safe: signature is %d bytes, want 65", len(sig))
+	}
+	hash := tx.Hash()
+	digest := hash[:]
+	rsv := bytes.Clone(sig)
+	switch v := rsv[64]; {
+	case v <= 1:
+	case v == 27 || v == 28:
+		rsv[64] -= 27
+	case v == 31 || v == 32:
+		digest = evm.HashMessage(hash[:])
+		rsv[64] -= 31
+	default:
+		return common.Address{}, fmt.Errorf("safe: unsupported signature type (v=%d)", v)
+	}
+	pub, err := crypto.SigToPub(digest, rsv)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("safe: recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Result is the outcome of Submit.
+type Result struct {
+	// SafeTxHash is the hash of the Safe transaction.
+	SafeTxHash common.Hash
+	// TxHash is the hash of the execution transaction, or "" if the
+	// transaction was proposed.
+	TxHash string
+}
+
+// Executed reports whether the Safe transaction was executed rather than
+// proposed.
+func (r *Result) Executed() bool {
+	return r.TxHash != ""
+}
+
+// Submit signs tx with the chain's wallet, which must be an owner, and
+// executes it if together with signatures, by other owners, it reaches the
+// Safe's threshold; otherwise it proposes tx to the Transaction Service.
+func (c *SafeClient) Submit(ctx context.Context, tx *SafeTx, signatures [][]byte) (*Result, error) {
+	threshold, err := c.Threshold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ordered, owner, err := c.ownerSignatures(ctx, tx, signatures)
+	if err != nil {
+		return nil, err
+	}
+	if !owner {
+		return nil, fmt.Errorf("safe: wallet %s: %w %s", c.chain.Wallet().Address(), ErrNotOwner, c.address.Hex())
+	}
+	if uint64(len(ordered)) >= threshold {
+		txHash, err := c.execute(ctx, tx, ordered)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{SafeTxHash: tx.Hash(), TxHash: txHash}, nil
+	}
+	hash, err := c.Propose(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{SafeTxHash: hash}, nil
+}
+
+// Execute executes tx with signatures by owners, adding the wallet's own
+// if it is an owner; the wallet need not be one. It fails with ErrNotEnoughSignatures below the
+// Safe's threshold. It returns the hash of the execution transaction,
+// which is sent by the wallet, which pays its gas.
+func (c *SafeClient) Execute(ctx context.Context, tx *SafeTx, signatures [][]byte) (string, error) {
+	threshold, err := c.Threshold(ctx)
+	if err != nil {
+		return "", err
+	}
+	ordered, _, err := c.ownerSignatures(ctx, tx, signatures)
+	if err != nil {
+		return "", err
+	}
+	if uint64(len(ordered)) < threshold {
+		return "", fmt.Errorf("safe: %w: %d of %d", ErrNotEnoughSignatures, len(ordered), threshold)
+	}
+	return c.execute(ctx, tx, ordered)
+}
+
+// ownedSignature is a signature by an owner.
+type ownedSignature struct {
+	owner common.Address
+	sig   []byte
+}
+
+// ownerSignatures checks that each signature is by an owner, adds the
+// wallet's own signature if the wallet is an owner, and returns them
+// ordered by owner address as the Safe requires, without duplicates.
+// owner reports whether the wallet is an owner.
+func (c *SafeClient) ownerSignatures(ctx context.Context, tx *SafeTx, signatures [][]byte) (ordered []ownedSignature, owner bool, err error) {
+	wallet := c.chain.Wallet()
+	if wallet == nil {
+		return nil, false, errors.New("safe: no wallet configured, read‑only mode")
+	}
+	owners, err := c.Owners(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	isOwner := make(map[common.Address]bool, len(owners))
+	for _, owner := range owners {
+		isOwner[owner] = true
+	}
+	seen := make(map[common.Address]bool)
+	for i, sig := range signatures {
+		signer, err := Signer(tx, sig)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w (signature %d)", err, i)
+		}
+		if !isOwner[signer] {
+			return nil, false, fmt.Errorf("safe: signature %d: %s: %w", i, signer.Hex(), ErrNotOwner)
+		}
+		if seen[signer] {
+			continue
+		}
+		seen[signer] = true
+		normalized := bytes.Clone(sig)
+		if normalized[64] <= 1 {
+			normalized[64] += 27
+		}
+		ordered = append(ordered, ownedSignature{owner: signer, sig: normalized})
+	}
+
+	self := common.HexToAddress(wallet.Address())
+	owner = isOwner[self]
+	if owner && !seen[self] {
+		sig, err := c.Sign(tx)
+		if err != nil {
+			return nil, false, err
+		}
+		ordered = append(ordered, ownedSignature{owner: self, sig: sig})
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return bytes.Compare(ordered[i].owner[:], ordered[j].owner[:]) < 0
+	})
+	return ordered, owner, nil
+}
+
+// execute sends execTransaction with the ordered signatures.
+func (c *SafeClient) execute(ctx context.Context, tx *SafeTx, ordered []ownedSignature) (string, error) {
+	var packed []byte
+	for _, s := range ordered {
+		packed = append(packed, s.sig...)
+	}
+	data, err := safeABI.Pack("execTransaction", tx.To, nonNil(tx.Value), tx.Data, uint8(tx.Operation),
+		nonNil(tx.SafeTxGas), nonNil(tx.BaseGas), nonNil(tx.GasPrice), tx.GasToken, tx.RefundReceiver, packed)
+	if err != nil {
+		return "", fmt.Errorf("safe: encode execTransaction: %w", err)
+	}
+	safeAddress := c.address.Hex()
+	txHash, err := c.chain.SendTransaction(ctx, &blockchain.Transaction{To: &safeAddress, Value: new(big.Int), Data: data})
+	if err != nil {
+		return "", fmt.Errorf("safe: execute: %w", err)
+	}
+	return txHash, nil
+}
+
+func nonNil(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+// EOF: internal/blockchain/evm/safe/safe.go
@@ -0,0 +1,251 @@
+// Package safe_test tests the Safe client against a fake Safe and a fake
+// Transaction Service.
+//
+// File: internal/blockchain/evm/safe/safe_test.go
+
+package safe_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm/safe"
+)
+
+const safeAddress = "0x5aFE3855358E112B5647B952709E6165e1c1eEEe"
+
+// fakeSafe is a chain holding a 2‑of‑3 Safe. The wallet is owner 0.
+type fakeSafe struct {
+	owners    []*ecdsa.PrivateKey
+	threshold int64
+	nonce     int64
+	wallet    blockchain.Wallet
+
+	mu   sync.Mutex
+	sent []*blockchain.Transaction
+}
+
+func newFakeSafe(t *testing.T) *fakeSafe {
+	t.Helper()
+	f := &fakeSafe{threshold: 2, nonce: 7}
+	for i := 0; i < 3; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		f.owners = append(f.owners, key)
+	}
+	wallet, err := evm.NewMemoryWalletFromHex(hexutil.Encode(crypto.FromECDSA(f.owners[0])))
+	require.NoError(t, err)
+	f.wallet = wallet
+	return f
+}
+
+func (f *fakeSafe) CallContract(_ context.Context, call *blockchain.ContractCall) ([]byte, error) {
+	safeABI := safe.SafeABI()
+	method, err := safeABI.MethodById(call.Data)
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "nonce":
+		return method.Outputs.Pack(big.NewInt(f.nonce))
+	case "getThreshold":
+		return method.Outputs.Pack(big.NewInt(f.threshold))
+	case "getOwners":
+		owners := make([]common.Address, len(f.owners))
+		for i, key := range f.owners {
+			owners[i] = crypto.PubkeyToAddress(key.PublicKey)
+		}
+		return method.Outputs.Pack(owners)
+	}
+	return nil, nil
+}
+
+func (f *fakeSafe) SendTransaction(_ context.Context, tx *blockchain.Transaction) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, tx)
+	return "0x01", nil
+}
+
+func (f *fakeSafe) ChainID(context.Context) (*big.Int, error) { return big.NewInt(1), nil }
+
+func (f *fakeSafe) Wallet() blockchain.Wallet { return f.wallet }
+
+// ownerSignature signs the hash of tx as owner i, with V of 27/28.
+func (f *fakeSafe) ownerSignature(t *testing.T, tx *safe.SafeTx, i int) []byte {
+	t.Helper()
+	hash := tx.Hash()
+	sig, err := crypto.Sign(hash[:], f.owners[i])
+	require.NoError(t, err)
+	sig[64] += 27
+	return sig
+}
+
+func TestSafeTx_HashMatchesEIP712(t *testing.T) {
+	tx := &safe.SafeTx{
+		Safe:      common.HexToAddress(safeAddress),
+		ChainID:   big.NewInt(137),
+		To:        common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"),
+		Value:     big.NewInt(1e18),
+		Data:      []byte{0xa9, 0x05, 0x9c, 0xbb},
+		Operation: safe.Call,
+		SafeTxGas: big.NewInt(0), BaseGas: big.NewInt(0), GasPrice: big.NewInt(0),
+		Nonce: big.NewInt(42),
+	}
+	typed := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "chainId", Type: "uint256"}, {Name: "verifyingContract", Type: "address"}},
+			"SafeTx": {
+				{Name: "to", Type: "address"}, {Name: "value", Type: "uint256"}, {Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"}, {Name: "safeTxGas", Type: "uint256"}, {Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"}, {Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"}, {Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain:      apitypes.TypedDataDomain{ChainId: math.NewHexOrDecimal256(137), VerifyingContract: safeAddress},
+		Message: apitypes.TypedDataMessage{
+			"to": tx.To.Hex(), "value": "1000000000000000000", "data": hexutil.Encode(tx.Data),
+			"operation": "0", "safeTxGas": "0", "baseGas": "0", "gasPrice": "0",
+			"gasToken": common.Address{}.Hex(), "refundReceiver": common.Address{}.Hex(), "nonce": "42",
+		},
+	}
+	want, _, err := apitypes.TypedDataAndHash(typed)
+	require.NoError(t, err)
+	assert.Equal(t, common.BytesToHash(want), tx.Hash())
+}
+
+func TestSafeClient_Propose(t *testing.T) {
+	fake := newFakeSafe(t)
+	var got map[string]interface{}
+	var path string
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer service.Close()
+
+	ctx := context.Background()
+	client, err := safe.NewSafeClient(fake, safeAddress, safe.WithTxService(service.URL+"/"))
+	require.NoError(t, err)
+	tx, err := client.BuildTx(ctx, "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", big.NewInt(5e17), nil, safe.Call)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), tx.Nonce.Int64(), "nonce from the Safe contract")
+
+	result, err := client.Submit(ctx, tx, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Executed(), "one signature of two proposes")
+	assert.Empty(t, fake.sent)
+
+	assert.Equal(t, "/api/v1/safes/"+safeAddress+"/multisig-transactions/", path)
+	assert.Equal(t, "500000000000000000", got["value"])
+	assert.Equal(t, "7", got["nonce"])
+	assert.Nil(t, got["data"])
+	assert.Equal(t, tx.Hash().Hex(), got["contractTransactionHash"])
+	assert.Equal(t, fake.wallet.Address(), got["sender"])
+	signer, err := safe.Signer(tx, hexutil.MustDecode(got["signature"].(string)))
+	require.NoError(t, err)
+	assert.Equal(t, fake.wallet.Address(), signer.Hex())
+
+	// Without a service there is nowhere to propose to.
+	bare, err := safe.NewSafeClient(fake, safeAddress)
+	require.NoError(t, err)
+	_, err = bare.Submit(ctx, tx, nil)
+	assert.ErrorIs(t, err, safe.ErrNoTxService)
+}
+
+func TestSafeClient_ExecutesWithEnoughSignatures(t *testing.T) {
+	fake := newFakeSafe(t)
+	ctx := context.Background()
+	client, err := safe.NewSafeClient(fake, safeAddress)
+	require.NoError(t, err)
+	tx, err := client.BuildTx(ctx, "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", big.NewInt(1), []byte{1, 2}, safe.Call)
+	require.NoError(t, err)
+
+	result, err := client.Submit(ctx, tx, [][]byte{fake.ownerSignature(t, tx, 2)})
+	require.NoError(t, err)
+	assert.True(t, result.Executed())
+	assert.Equal(t, tx.Hash(), result.SafeTxHash)
+
+	require.Len(t, fake.sent, 1)
+	sent := fake.sent[0]
+	assert.Equal(t, safeAddress, *sent.To)
+	assert.Zero(t, sent.Value.Sign(), "the Safe pays the value, not the executor")
+	args, err := safe.SafeABI().Methods["execTransaction"].Inputs.Unpack(sent.Data[4:])
+	require.NoError(t, err)
+	assert.Equal(t, tx.To, args[0])
+	assert.Equal(t, []byte{1, 2}, args[2])
+
+	// Signatures are ordered by owner address, as the Safe requires.
+	sigs := args[9].([]byte)
+	require.Len(t, sigs, 2*65)
+	var signers []common.Address
+	for i := 0; i < 2; i++ {
+		signer, err := safe.Signer(tx, sigs[i*65:(i+1)*65])
+		require.NoError(t, err)
+		signers = append(signers, signer)
+	}
+	assert.True(t, sort.SliceIsSorted(signers, func(i, j int) bool {
+		return bytes.Compare(signers[i][:], signers[j][:]) < 0
+	}))
+	assert.ElementsMatch(t, []common.Address{
+		crypto.PubkeyToAddress(fake.owners[0].PublicKey), crypto.PubkeyToAddress(fake.owners[2].PublicKey),
+	}, signers)
+}
+
+func TestSafeClient_Errors(t *testing.T) {
+	fake := newFakeSafe(t)
+	ctx := context.Background()
+	client, err := safe.NewSafeClient(fake, safeAddress)
+	require.NoError(t, err)
+	tx, err := client.BuildTx(ctx, "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", big.NewInt(1), nil, safe.Call)
+	require.NoError(t, err)
+
+	// A signature by someone else is rejected.
+	stranger, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	hash := tx.Hash()
+	sig, err := crypto.Sign(hash[:], stranger)
+	require.NoError(t, err)
+	_, err = client.Execute(ctx, tx, [][]byte{sig})
+	assert.ErrorIs(t, err, safe.ErrNotOwner)
+
+	// A duplicate of the wallet's own signature does not count twice.
+	_, err = client.Execute(ctx, tx, [][]byte{fake.ownerSignature(t, tx, 0)})
+	assert.ErrorIs(t, err, safe.ErrNotEnoughSignatures)
+
+	// A wallet that is not an owner cannot propose.
+	fake.wallet, err = evm.NewMemoryWallet()
+	require.NoError(t, err)
+	_, err = client.Submit(ctx, tx, nil)
+	assert.ErrorIs(t, err, safe.ErrNotOwner)
+	// It can still execute with enough owner signatures.
+	_, err = client.Execute(ctx, tx, [][]byte{fake.ownerSignature(t, tx, 1), fake.ownerSignature(t, tx, 2)})
+	assert.NoError(t, err)
+
+	_, err = safe.NewSafeClient(fake, "not-an-address")
+	assert.ErrorIs(t, err, evm.ErrInvalidAddress)
+	_, err = client.BuildTx(ctx, "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", nil, nil, safe.Operation(2))
+	assert.ErrorContains(t, err, "unknown operation")
+}
+
+// EOF: internal/blockchain/evm/safe/safe_test.go
@@ -0,0 +1,101 @@
+// Package safe provides proposals through the Safe Transaction Service.
+//
+// File: internal/blockchain/evm/safe/service.go
+
+package safe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// proposal is the body of a multisig transaction proposal to the Safe
+// Transaction Service. Amounts are decimal strings.
+type proposal struct {
+	Safe                    string  `json:"safe"`
+	To                      string  `json:"to"`
+	Value                   string  `json:"value"`
+	Data                    *string `json:"data"`
+	Operation               uint8   `json:"operation"`
+	SafeTxGas               string  `json:"safeTxGas"`
+	BaseGas                 string  `json:"baseGas"`
+	GasPrice                string  `json:"gasPrice"`
+	GasToken                string  `json:"gasToken"`
+	RefundReceiver          string  `json:"refundReceiver"`
+	Nonce                   string  `json:"nonce"`
+	ContractTransactionHash string  `json:"contractTransactionHash"`
+	Sender                  string  `json:"sender"`
+	Signature               string  `json:"signature"`
+	Origin                  string  `json:"origin"`
+}
+
+// Propose signs tx with the chain's wallet, which must be an owner, and
+// submits it to the Transaction Service, where the other owners can
+// confirm it. It returns the hash of the Safe transaction.
+func (c *SafeClient) Propose(ctx context.Context, tx *SafeTx) (common.Hash, error) {
+	if c.service == "" {
+		return common.Hash{}, fmt.Errorf("safe: propose: %w", ErrNoTxService)
+	}
+	wallet := c.chain.Wallet()
+	if wallet == nil {
+		return common.Hash{}, errors.New("safe: no wallet configured, read‑only mode")
+	}
+	sig, err := c.Sign(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hash := tx.Hash()
+	body := proposal{
+		Safe:                    tx.Safe.Hex(),
+		To:                      tx.To.Hex(),
+		Value:                   nonNil(tx.Value).String(),
+		Operation:               uint8(tx.Operation),
+		SafeTxGas:               nonNil(tx.SafeTxGas).String(),
+		BaseGas:                 nonNil(tx.BaseGas).String(),
+		GasPrice:                nonNil(tx.GasPrice).String(),
+		GasToken:                tx.GasToken.Hex(),
+		RefundReceiver:          tx.RefundReceiver.Hex(),
+		Nonce:                   nonNil(tx.Nonce).String(),
+		ContractTransactionHash: hash.Hex(),
+		Sender:                  common.HexToAddress(wallet.Address()).Hex(),
+		Signature:               hexutil.Encode(sig),
+		Origin:                  "lola-os",
+	}
+	if len(tx.Data) > 0 {
+		data := hexutil.Encode(tx.Data)
+		body.Data = &data
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("safe: encode proposal: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/safes/%s/multisig-transactions/", c.service, tx.Safe.Hex())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(encoded))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("safe: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("safe: propose: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return common.Hash{}, fmt.Errorf("safe: propose: transaction service: http %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return hash, nil
+}
+
+// EOF: internal/blockchain/evm/safe/service.go
@@ -0,0 +1,77 @@
+// Package evm_test tests offline transaction signing.
+//
+// File: internal/blockchain/evm/sign_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+func TestEVMGateway_SignTransaction_Offline(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+
+	// Nothing listens on port 1: every RPC call would fail.
+	gw, err := evm.NewEVMGateway(context.Background(), "http://127.0.0.1:1", &observe.NoopLogger{},
+		&evm.RetryConfig{MaxAttempts: 1}, time.Second, wallet, evm.WithExpectedChainID(137))
+	require.NoError(t, err, "an unreachable node must not block offline signing")
+	t.Cleanup(func() { gw.Close() })
+
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	nonce := uint64(9)
+	tests := []struct {
+		name   string
+		tx     blockchain.Transaction
+		txType uint8
+	}{
+		{"legacy", blockchain.Transaction{GasPrice: big.NewInt(30e9)}, types.LegacyTxType},
+		{"dynamic fee", blockchain.Transaction{GasFeeCap: big.NewInt(40e9), GasTipCap: big.NewInt(2e9)}, types.DynamicFeeTxType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := tt.tx
+			tx.To = &to
+			tx.Value = big.NewInt(1000)
+			tx.Gas = 21000
+			tx.Nonce = &nonce
+
+			raw, hash, err := gw.SignTransaction(context.Background(), &tx)
+			require.NoError(t, err)
+
+			var decoded types.Transaction
+			require.NoError(t, decoded.UnmarshalBinary(hexutil.MustDecode(raw)))
+			assert.Equal(t, hash, decoded.Hash().Hex())
+			assert.Equal(t, tt.txType, decoded.Type())
+			assert.Equal(t, big.NewInt(137), decoded.ChainId())
+			assert.Equal(t, nonce, decoded.Nonce())
+			assert.Equal(t, common.HexToAddress(to), *decoded.To())
+
+			sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(137)), &decoded)
+			require.NoError(t, err)
+			assert.Equal(t, wallet.Address(), sender.Hex())
+		})
+	}
+
+	// Without a nonce the builder needs the node.
+	_, _, err = gw.SignTransaction(context.Background(), &blockchain.Transaction{
+		To: &to, Gas: 21000, GasPrice: big.NewInt(1),
+	})
+	assert.ErrorContains(t, err, "get nonce")
+}
+
+// EOF: internal/blockchain/evm/sign_test.go
@@ -0,0 +1,110 @@
+// Package evm recovers and verifies ECDSA and EIP‑1271 signatures.
+//
+// File: internal/blockchain/evm/signature.go
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// eip1271MagicValue is returned by isValidSignature(bytes32,bytes) for a
+// valid signature; it equals the function's selector.
+var eip1271MagicValue = crypto.Keccak256([]byte("isValidSignature(bytes32,bytes)"))[:4]
+
+// RecoverSigner returns the address that produced sig, a 65‑byte [R || S || V]
+// signature over the 32‑byte digest. V may be 0/1 or 27/28. High‑S
+// signatures are rejected as malleable.
+func RecoverSigner(digest, sig []byte) (string, error) {
+	if len(digest) != common.HashLength {
+		return "", fmt.Errorf("RecoverSigner: digest must be %d bytes, got %d", common.HashLength, len(digest))
+	}
+	if len(sig) != crypto.SignatureLength {
+		return "", fmt.Errorf("RecoverSigner: signature must be %d bytes, got %d", crypto.SignatureLength, len(sig))
+	}
+	norm := make([]byte, crypto.SignatureLength)
+	copy(norm, sig)
+	if norm[64] >= 27 {
+		norm[64] -= 27
+	}
+	r, s := new(big.Int).SetBytes(norm[:32]), new(big.Int).SetBytes(norm[32:64])
+	if !crypto.ValidateSignatureValues(norm[64], r, s, true) {
+		return "", errors.New("RecoverSigner: invalid signature values")
+	}
+	pub, err := crypto.SigToPub(digest, norm)
+	if err != nil {
+		return "", fmt.Errorf("RecoverSigner: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub).Hex(), nil
+}
+
+// VerifySignature reports whether sig over digest was produced by the
+// private key of address. A malformed address or signature is an error; a
+// valid signature by another key returns false. It only covers externally
+// owned accounts; use EVMGateway.VerifySignature for contract wallets.
+func VerifySignature(address string, digest, sig []byte) (bool, error) {
+	if !common.IsHexAddress(address) {
+		return false, fmt.Errorf("VerifySignature: invalid address %q", address)
+	}
+	signer, err := RecoverSigner(digest, sig)
+	if err != nil {
+		return false, fmt.Errorf("VerifySignature: %w", err)
+	}
+	return common.HexToAddress(signer) == common.HexToAddress(address), nil
+}
+
+// VerifySignature reports whether sig over digest is valid for address,
+// which may be a hex address or an ENS name. A 65‑byte signature recovering
+// to address is accepted directly; otherwise, if address has code, the
+// contract is asked via EIP‑1271 isValidSignature(bytes32,bytes) and the
+// magic value 0x1626ba7e means valid. A reverting contract returns false.
+func (g *EVMGateway) VerifySignature(ctx context.Context, address string, digest, sig []byte) (bool, error) {
+	if len(digest) != common.HashLength {
+		return false, fmt.Errorf("VerifySignature: digest must be %d bytes, got %d", common.HashLength, len(digest))
+	}
+	addr, err := g.resolveAddress(ctx, address)
+	if err != nil {
+		return false, fmt.Errorf("VerifySignature: %w", err)
+	}
+	if len(sig) == crypto.SignatureLength {
+		if signer, err := RecoverSigner(digest, sig); err == nil && common.HexToAddress(signer) == addr {
+			return true, nil
+		}
+	}
+
+	code, err := g.client.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return false, fmt.Errorf("VerifySignature: %w", err)
+	}
+	if len(code) == 0 {
+		return false, nil
+	}
+
+	args, err := abi.Arguments{{Type: abiType("bytes32")}, {Type: abiType("bytes")}}.Pack(common.BytesToHash(digest), sig)
+	if err != nil {
+		return false, fmt.Errorf("VerifySignature: encode isValidSignature: %w", err)
+	}
+	out, err := g.CallContract(ctx, &blockchain.ContractCall{
+		To:   addr.Hex(),
+		Data: append(append([]byte(nil), eip1271MagicValue...), args...),
+	})
+	if err != nil {
+		if errors.Is(ClassifyError(err), ErrReverted) {
+			return false, nil
+		}
+		return false, fmt.Errorf("VerifySignature: isValidSignature: %w", err)
+	}
+	return len(out) >= 4 && bytes.Equal(out[:4], eip1271MagicValue), nil
+}
+
+// EOF: internal/blockchain/evm/signature.go
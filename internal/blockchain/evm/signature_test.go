@@ -0,0 +1,115 @@
+// Package evm_test tests signature recovery and EIP‑1271 verification.
+//
+// File: internal/blockchain/evm/signature_test.go
+
+package evm_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// erc1271InitCode deploys a mock EIP‑1271 wallet that returns the magic
+// value 0x1626ba7e from isValidSignature only for the given hash, whatever
+// the signature bytes.
+func erc1271InitCode(hash common.Hash) []byte {
+	runtime := fmt.Sprintf("6004357f%x14602d5760206000f35b631626ba7e60e01b60005260206000f3", hash)
+	return common.FromHex("603e600c600039603e6000f3" + runtime)
+}
+
+func TestRecoverSigner_EOA(t *testing.T) {
+	// web3.eth.accounts.sign("Some data", 0x4c08…2318) from the web3.js docs.
+	digest := evm.HashMessage([]byte("Some data"))
+	sig := common.FromHex("0xb91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c")
+	const signer = "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"
+
+	got, err := evm.RecoverSigner(digest, sig)
+	require.NoError(t, err)
+	assert.Equal(t, signer, got)
+
+	// The same signature with V of 0/1.
+	low := append([]byte(nil), sig...)
+	low[64] -= 27
+	got, err = evm.RecoverSigner(digest, low)
+	require.NoError(t, err)
+	assert.Equal(t, signer, got)
+
+	ok, err := evm.VerifySignature(signer, digest, sig)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = evm.VerifySignature("0x0000000000000000000000000000000000000001", digest, sig)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = evm.RecoverSigner(digest[:31], sig)
+	assert.Error(t, err)
+	_, err = evm.RecoverSigner(digest, sig[:64])
+	assert.Error(t, err)
+
+	// The malleable high‑S twin of a valid signature is rejected.
+	n := crypto.S256().Params().N
+	highS := append([]byte(nil), low...)
+	new(big.Int).Sub(n, new(big.Int).SetBytes(low[32:64])).FillBytes(highS[32:64])
+	highS[64] ^= 1
+	_, err = evm.RecoverSigner(digest, highS)
+	assert.Error(t, err)
+}
+
+func TestGateway_VerifySignature(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	approved := crypto.Keccak256Hash([]byte("approved order"))
+	_, contractWallet, err := gateway.DeployContract(ctx, erc1271InitCode(approved), nil)
+	require.NoError(t, err)
+	_, plain, err := gateway.DeployContract(ctx, storageInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	// EOA: recovered from the signature.
+	digest := crypto.Keccak256([]byte("eoa payload"))
+	sig, err := wallet.Sign(digest)
+	require.NoError(t, err)
+	ok, err := gateway.VerifySignature(ctx, wallet.Address(), digest, sig)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = gateway.VerifySignature(ctx, wallet.Address(), crypto.Keccak256([]byte("other")), sig)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Contract wallet: EIP‑1271 magic value, with arbitrary signature bytes.
+	ok, err = gateway.VerifySignature(ctx, contractWallet.Hex(), approved.Bytes(), []byte{0xde, 0xad})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = gateway.VerifySignature(ctx, contractWallet.Hex(), digest, []byte{0xde, 0xad})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// A contract without isValidSignature returns no magic value.
+	ok, err = gateway.VerifySignature(ctx, plain.Hex(), approved.Bytes(), sig)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = gateway.VerifySignature(ctx, wallet.Address(), digest[:10], sig)
+	assert.Error(t, err)
+}
+
+// EOF: internal/blockchain/evm/signature_test.go
@@ -0,0 +1,238 @@
+// Package evm simulates transactions before they are signed, traces the
+// assets they would move, and decodes revert reasons.
+//
+// File: internal/blockchain/evm/simulate.go
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// ErrWouldRevert indicates that simulating a transaction before broadcast
+// reverted, so it was never signed or sent. The error is a *RevertError.
+var ErrWouldRevert = errors.New("transaction would revert")
+
+// RevertError carries the decoded reason of a simulated revert.
+// errors.Is(err, ErrWouldRevert) matches it.
+type RevertError struct {
+	// Reason is the decoded revert reason (see DecodeRevert); empty if the
+	// contract reverted without data.
+	Reason string
+	// Data is the raw revert data.
+	Data []byte
+}
+
+// Error returns the revert reason, if any.
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return ErrWouldRevert.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrWouldRevert, e.Reason)
+}
+
+// Unwrap returns ErrWouldRevert.
+func (e *RevertError) Unwrap() error {
+	return ErrWouldRevert
+}
+
+// DecodeRevert decodes revert data: the Error(string) message, a
+// description of a Panic(uint256) code, or "custom error 0x…" with the
+// 4‑byte selector of any other error. Returns "" for empty data.
+// See DecodeRevertWithABI to decode custom errors too.
+func DecodeRevert(data []byte) string {
+	return DecodeRevertWithABI(data, nil)
+}
+
+// simulating reports whether transactions built with opts are simulated.
+func (b *TxBuilder) simulating(opts *TxOpts) bool {
+	return b.client.simulate || (opts != nil && opts.Simulate)
+}
+
+// simulateAndSign simulates unsigned if requested, then signs it.
+func (b *TxBuilder) simulateAndSign(ctx context.Context, unsigned *types.Transaction, opts *TxOpts) (*types.Transaction, error) {
+	if b.simulating(opts) {
+		if err := b.simulate(ctx, unsigned); err != nil {
+			return nil, err
+		}
+	}
+	return b.signTransaction(ctx, unsigned)
+}
+
+// estimateError wraps a failed gas estimation. When simulating, a revert
+// during estimation is reported as a *RevertError as well.
+func (b *TxBuilder) estimateError(err error, opts *TxOpts) error {
+	if b.simulating(opts) {
+		return b.revertError("estimate gas", err)
+	}
+	return fmt.Errorf("txbuilder: estimate gas: %w", err)
+}
+
+// simulate runs unsigned as an eth_call from the builder's address against
+// the latest state and fails with a *RevertError if it reverts.
+func (b *TxBuilder) simulate(ctx context.Context, unsigned *types.Transaction) error {
+	_, err := b.client.CallContract(ctx, callMsgFromTx(b.address, unsigned), nil)
+	if err == nil {
+		return nil
+	}
+	return b.revertError("simulate", err)
+}
+
+// callMsgFromTx returns the eth_call message that executes tx as from.
+func callMsgFromTx(from common.Address, tx *types.Transaction) ethereum.CallMsg {
+	msg := ethereum.CallMsg{
+		From:          from,
+		To:            tx.To(),
+		Gas:           tx.Gas(),
+		Value:         tx.Value(),
+		Data:          tx.Data(),
+		AccessList:    tx.AccessList(),
+		BlobHashes:    tx.BlobHashes(),
+		BlobGasFeeCap: tx.BlobGasFeeCap(),
+	}
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		msg.GasPrice = tx.GasPrice()
+	default:
+		msg.GasFeeCap = tx.GasFeeCap()
+		msg.GasTipCap = tx.GasTipCap()
+	}
+	return msg
+}
+
+// revertError converts a reverted call into a *RevertError and wraps any
+// other failure as "txbuilder: op: err".
+func (b *TxBuilder) revertError(op string, err error) error {
+	if res, ok := b.client.revertResult(err); ok {
+		b.client.logger.Debug("transaction simulation reverted", map[string]interface{}{
+			"reason": res.RevertReason,
+		})
+		return &RevertError{Reason: res.RevertReason, Data: res.Data}
+	}
+	return fmt.Errorf("txbuilder: %s: %w", op, err)
+}
+
+// transferTopic is the topic of the ERC‑20 Transfer event.
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// SimulateTransaction runs tx against the latest state without sending it,
+// as the account tx.From names or else the wallet's primary account, and
+// reports whether it would revert. A node serving debug_traceCall traces
+// the run, so the result also lists the native currency and ERC‑20 tokens
+// it moves and the contracts it calls; other nodes run it with eth_call,
+// and the result is not Traced. Fees are not charged in the simulation.
+func (g *EVMGateway) SimulateTransaction(ctx context.Context, tx *blockchain.Transaction) (*blockchain.Simulation, error) {
+	msg := ethereum.CallMsg{Value: tx.Value, Data: tx.Data, Gas: tx.Gas}
+	switch {
+	case tx.From != "":
+		from, err := parseAddress(tx.From)
+		if err != nil {
+			return nil, fmt.Errorf("SimulateTransaction: %w", err)
+		}
+		msg.From = from
+	case g.wallet != nil:
+		msg.From = common.HexToAddress(g.wallet.Address())
+	}
+	if tx.To != nil {
+		to, err := g.resolveAddress(ctx, *tx.To)
+		if err != nil {
+			return nil, fmt.Errorf("SimulateTransaction: %w", err)
+		}
+		msg.To = &to
+	}
+
+	frame, err := g.client.TraceCall(ctx, msg, &TraceOptions{WithLogs: true})
+	if err == nil {
+		return g.client.simulation(frame), nil
+	}
+	if !errors.Is(err, ErrNotSupported) {
+		return nil, fmt.Errorf("SimulateTransaction: %w", err)
+	}
+	if _, err := g.client.CallContract(ctx, msg, nil); err != nil {
+		if res, ok := g.client.revertResult(err); ok {
+			return &blockchain.Simulation{Reverted: true, RevertReason: res.RevertReason}, nil
+		}
+		return nil, fmt.Errorf("SimulateTransaction: %w", err)
+	}
+	return &blockchain.Simulation{}, nil
+}
+
+// simulation summarizes the call tree of a traced simulation. Calls that
+// reverted are skipped with everything beneath them, since their effects
+// are undone.
+func (c *Client) simulation(root *CallFrame) *blockchain.Simulation {
+	sim := &blockchain.Simulation{Traced: true}
+	if root.Error != "" {
+		sim.Reverted = true
+		sim.RevertReason = root.RevertReason
+		if sim.RevertReason == "" {
+			sim.RevertReason = c.decodeRevert(root.Output)
+		}
+		return sim
+	}
+	called := make(map[common.Address]bool)
+	var walk func(f *CallFrame)
+	walk = func(f *CallFrame) {
+		if f.Error != "" {
+			return
+		}
+		switch f.Type {
+		case "CREATE", "CREATE2":
+		default:
+			if len(f.Input) > 0 && !isPrecompile(f.To) && !called[f.To] {
+				called[f.To] = true
+				sim.Contracts = append(sim.Contracts, f.To.Hex())
+			}
+		}
+		switch f.Type {
+		case "DELEGATECALL", "STATICCALL", "CALLCODE":
+			// No value leaves the caller.
+		default:
+			if f.Value != nil && f.Value.Sign() > 0 {
+				sim.Transfers = append(sim.Transfers, blockchain.AssetTransfer{
+					From: f.From.Hex(), To: f.To.Hex(), Amount: new(big.Int).Set(f.Value),
+				})
+			}
+		}
+		for _, l := range f.Logs {
+			// ERC‑721 Transfer events index the token id as a fourth topic.
+			if len(l.Topics) != 3 || l.Topics[0] != transferTopic || len(l.Data) != 32 {
+				continue
+			}
+			sim.Transfers = append(sim.Transfers, blockchain.AssetTransfer{
+				Token:  l.Address.Hex(),
+				From:   common.BytesToAddress(l.Topics[1].Bytes()).Hex(),
+				To:     common.BytesToAddress(l.Topics[2].Bytes()).Hex(),
+				Amount: new(big.Int).SetBytes(l.Data),
+			})
+		}
+		for i := range f.Calls {
+			walk(&f.Calls[i])
+		}
+	}
+	walk(root)
+	return sim
+}
+
+// isPrecompile reports whether address is in the low range reserved for
+// precompiled contracts.
+func isPrecompile(address common.Address) bool {
+	for _, b := range address[:common.AddressLength-2] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// EOF: internal/blockchain/evm/simulate.go
@@ -0,0 +1,191 @@
+// Package evm_test tests pre‑broadcast transaction simulation.
+//
+// File: internal/blockchain/evm/simulate_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// revertingRuntime returns runtime code that reverts with data (< 256 bytes).
+func revertingRuntime(data []byte) []byte {
+	n := len(data)
+	code := common.FromHex(fmt.Sprintf("60%02x600c600039"+"60%02x6000fd", n, n))
+	return append(code, data...)
+}
+
+func TestEVMGateway_SendTransaction_Simulate(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	self := common.HexToAddress(wallet.Address())
+
+	contracts := map[string]struct {
+		addr   common.Address
+		code   []byte
+		reason string
+	}{
+		"error string": {common.HexToAddress("0x1001"), revertNopeRuntime, "nope"},
+		"panic": {common.HexToAddress("0x1002"), revertingRuntime(append(common.FromHex("4e487b71"),
+			common.LeftPadBytes([]byte{0x11}, 32)...)), "arithmetic underflow or overflow"},
+		"custom error": {common.HexToAddress("0x1003"), revertingRuntime(append(common.FromHex("deadbeef"),
+			make([]byte, 32)...)), "custom error 0xdeadbeef"},
+		"no data": {common.HexToAddress("0x1004"), revertingRuntime(nil), ""},
+	}
+	alloc := types.GenesisAlloc{self: {Balance: big.NewInt(1e18)}}
+	for _, c := range contracts {
+		alloc[c.addr] = types.Account{Code: c.code, Balance: new(big.Int)}
+	}
+	sim := simulated.NewBackend(alloc)
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	for name, c := range contracts {
+		for _, gas := range []uint64{100000, 0} { // explicit gas, then estimation
+			t.Run(fmt.Sprintf("%s/gas=%d", name, gas), func(t *testing.T) {
+				to := c.addr.Hex()
+				_, err := gateway.SendTransaction(ctx, &blockchain.Transaction{
+					To:       &to,
+					Gas:      gas,
+					GasPrice: big.NewInt(10e9),
+					Simulate: true,
+				})
+				require.ErrorIs(t, err, evm.ErrWouldRevert)
+				var revertErr *evm.RevertError
+				require.True(t, errors.As(err, &revertErr))
+				assert.Equal(t, c.reason, revertErr.Reason)
+			})
+		}
+	}
+
+	nonce, err := sim.Client().PendingNonceAt(ctx, self)
+	require.NoError(t, err)
+	assert.Zero(t, nonce, "reverting transactions must not be broadcast")
+
+	// Without simulation an explicit gas limit skips every check and the
+	// transaction is mined as a failure.
+	to := contracts["error string"].addr.Hex()
+	hash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Gas: 100000, GasPrice: big.NewInt(10e9)})
+	require.NoError(t, err)
+	sim.Commit()
+	receipt, err := sim.Client().TransactionReceipt(ctx, common.HexToHash(hash))
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusFailed, receipt.Status)
+
+	// A transaction that succeeds in simulation is sent.
+	recipient := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	_, err = gateway.SendTransaction(ctx, &blockchain.Transaction{
+		To: &recipient, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(10e9), Simulate: true,
+	})
+	require.NoError(t, err)
+}
+
+func TestEVMGateway_SimulateTransaction(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	self := common.HexToAddress(wallet.Address())
+	router := common.HexToAddress("0x5aFE3855358E112B5647B952709E6165e1c1eEEe")
+	token := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	payee := common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
+	word := func(b []byte) string { return hexutil.Encode(common.LeftPadBytes(b, 32)) }
+
+	// The router pulls 500 tokens from the wallet and forwards 1 eth it
+	// was sent; a reverted call and a precompile call are ignored.
+	trace := fmt.Sprintf(`{
+		"type": "CALL", "from": %[1]q, "to": %[2]q, "value": "0xde0b6b3a7640000", "gas": "0x30d40", "gasUsed": "0x1d4c0", "input": "0x38ed1739",
+		"calls": [
+			{"type": "CALL", "from": %[2]q, "to": %[3]q, "gas": "0x8000", "gasUsed": "0x75a8", "input": "0x23b872dd",
+			 "logs": [{"address": %[3]q, "topics": [%[4]q, %[5]q, %[6]q], "data": %[7]q}]},
+			{"type": "CALL", "from": %[2]q, "to": "0x00000000000000000000000000000000000000d4", "value": "0x1", "gas": "0x100", "gasUsed": "0x100", "input": "0x01", "error": "out of gas"},
+			{"type": "STATICCALL", "from": %[2]q, "to": "0x0000000000000000000000000000000000000001", "gas": "0x100", "gasUsed": "0xbb8", "input": "0x01"},
+			{"type": "CALL", "from": %[2]q, "to": %[8]q, "value": "0xde0b6b3a7640000", "gas": "0x8fc", "gasUsed": "0x0", "input": "0x"}
+		]
+	}`, self.Hex(), router.Hex(), token.Hex(), crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")).Hex(), word(self.Bytes()), word(router.Bytes()), word(big.NewInt(500).Bytes()), payee.Hex())
+
+	var params []json.RawMessage
+	client := newFeeClient(t, map[string]interface{}{
+		"debug_traceCall": func(p []json.RawMessage) interface{} {
+			params = p
+			return json.RawMessage(trace)
+		},
+	})
+	gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, wallet)
+	ctx := context.Background()
+
+	to := router.Hex()
+	sim, err := gateway.SimulateTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1e18), Data: common.FromHex("38ed1739")})
+	require.NoError(t, err)
+	require.Len(t, params, 3)
+	var call map[string]interface{}
+	require.NoError(t, json.Unmarshal(params[0], &call))
+	assert.Equal(t, strings.ToLower(self.Hex()), strings.ToLower(call["from"].(string)), "simulated as the wallet")
+	assert.JSONEq(t, `"latest"`, string(params[1]))
+	assert.JSONEq(t, `{"tracer":"callTracer","tracerConfig":{"withLog":true}}`, string(params[2]))
+
+	assert.Equal(t, &blockchain.Simulation{
+		Traced:    true,
+		Contracts: []string{router.Hex(), token.Hex()},
+		Transfers: []blockchain.AssetTransfer{
+			{From: self.Hex(), To: router.Hex(), Amount: big.NewInt(1e18)},
+			{Token: token.Hex(), From: self.Hex(), To: router.Hex(), Amount: big.NewInt(500)},
+			{From: router.Hex(), To: payee.Hex(), Amount: big.NewInt(1e18)},
+		},
+	}, sim)
+
+	trace = `{"type": "CALL", "from": "0x00000000000000000000000000000000000000a1", "to": "0x00000000000000000000000000000000000000b2", "gas": "0x1", "gasUsed": "0x1", "input": "0x", "error": "execution reverted",
+		"output": "0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000046e6f706500000000000000000000000000000000000000000000000000000000"}`
+	sim, err = gateway.SimulateTransaction(ctx, &blockchain.Transaction{To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, &blockchain.Simulation{Reverted: true, RevertReason: "nope", Traced: true}, sim)
+}
+
+func TestEVMGateway_SimulateTransaction_Untraced(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	reverting := common.HexToAddress("0x1001")
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+		reverting:                             {Code: revertNopeRuntime, Balance: new(big.Int)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	to := reverting.Hex()
+	res, err := gateway.SimulateTransaction(ctx, &blockchain.Transaction{To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, &blockchain.Simulation{Reverted: true, RevertReason: "nope"}, res)
+
+	recipient := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	res, err = gateway.SimulateTransaction(ctx, &blockchain.Transaction{To: &recipient, Value: big.NewInt(1)})
+	require.NoError(t, err)
+	assert.Equal(t, &blockchain.Simulation{}, res, "sent with eth_call, not traced")
+}
+
+func TestDecodeRevert(t *testing.T) {
+	assert.Equal(t, "nope", evm.DecodeRevert(revertNopeRuntime[12:]))
+	assert.Equal(t, "custom error 0x12345678", evm.DecodeRevert(common.FromHex("12345678")))
+	assert.Equal(t, "", evm.DecodeRevert(nil))
+}
+
+// EOF: internal/blockchain/evm/simulate_test.go
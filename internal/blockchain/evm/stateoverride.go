@@ -0,0 +1,183 @@
+// Package evm provides eth_call simulation with per‑account state overrides.
+//
+// File: internal/blockchain/evm/stateoverride.go
+
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// OverrideAccount replaces parts of one account's state for a single call.
+// Nil or empty fields leave the corresponding state untouched.
+type OverrideAccount struct {
+	Balance   *big.Int
+	Nonce     *uint64
+	Code      []byte
+	State     map[common.Hash]common.Hash // replaces the whole storage
+	StateDiff map[common.Hash]common.Hash // patches individual slots
+}
+
+// MarshalJSON encodes the override in the eth_call wire format.
+func (a OverrideAccount) MarshalJSON() ([]byte, error) {
+	type override struct {
+		Balance   *hexutil.Big                `json:"balance,omitempty"`
+		Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+		Code      hexutil.Bytes               `json:"code,omitempty"`
+		State     map[common.Hash]common.Hash `json:"state,omitempty"`
+		StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+	}
+	return json.Marshal(override{
+		Balance:   (*hexutil.Big)(a.Balance),
+		Nonce:     (*hexutil.Uint64)(a.Nonce),
+		Code:      a.Code,
+		State:     a.State,
+		StateDiff: a.StateDiff,
+	})
+}
+
+// Overrides maps account addresses to the state to assume during a call.
+type Overrides map[common.Address]OverrideAccount
+
+// CallResult is the outcome of a simulated call.
+// A revert is reported here rather than as an error.
+type CallResult struct {
+	Data         []byte // return data, or the raw revert data if Reverted
+	Reverted     bool
+	RevertReason string // decoded revert reason, if any (see DecodeRevert)
+}
+
+// CallWithStateOverride executes eth_call against the latest block as if the
+// accounts in overrides had the given balance, nonce, code or storage.
+// Nothing is broadcast. Providers without override support yield ErrNotSupported.
+func (c *Client) CallWithStateOverride(ctx context.Context, call ethereum.CallMsg, overrides Overrides) (*CallResult, error) {
+	return c.callWithStateOverride(ctx, call, nil, overrides)
+}
+
+// callWithStateOverride is CallWithStateOverride at a given block (nil = latest).
+func (c *Client) callWithStateOverride(ctx context.Context, call ethereum.CallMsg, block *big.Int, overrides Overrides) (*CallResult, error) {
+	result, err := c.withRetry(ctx, "CallWithStateOverride", func(ctx context.Context) (interface{}, error) {
+		var data hexutil.Bytes
+		err := c.eth().Client().CallContext(ctx, &data, "eth_call", callArg(call), blockArg(block), overrides)
+		if err == nil {
+			return &CallResult{Data: data}, nil
+		}
+		if res, ok := c.revertResult(err); ok {
+			return res, nil
+		}
+		if overridesUnsupported(err) {
+			return nil, &RPCError{Kind: ErrNotSupported, Err: err}
+		}
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*CallResult), nil
+}
+
+// callArg encodes a call message as an eth_call transaction object.
+func callArg(msg ethereum.CallMsg) map[string]interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["input"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	if msg.GasFeeCap != nil {
+		arg["maxFeePerGas"] = (*hexutil.Big)(msg.GasFeeCap)
+	}
+	if msg.GasTipCap != nil {
+		arg["maxPriorityFeePerGas"] = (*hexutil.Big)(msg.GasTipCap)
+	}
+	return arg
+}
+
+// blockArg encodes a block number for a raw RPC call (nil = latest).
+func blockArg(block *big.Int) string {
+	if block == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(block)
+}
+
+// revertResult turns an "execution reverted" error into a CallResult.
+func (c *Client) revertResult(err error) (*CallResult, bool) {
+	data, ok := revertData(err)
+	if !ok {
+		return nil, false
+	}
+	return &CallResult{Reverted: true, Data: data, RevertReason: c.decodeRevert(data)}, true
+}
+
+// overridesUnsupported reports whether the node rejected the third eth_call
+// parameter or does not know eth_call at all.
+func overridesUnsupported(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	switch rpcErr.ErrorCode() {
+	case rpcCodeMethodNotFound:
+		return true
+	case rpcCodeInvalidParams:
+		msg := strings.ToLower(rpcErr.Error())
+		return strings.Contains(msg, "too many arguments") ||
+			strings.Contains(msg, "override") ||
+			strings.Contains(msg, "argument 2")
+	}
+	return false
+}
+
+// CallWithStateOverride simulates a contract call as if the accounts in
+// overrides had the given state. call.Block selects the state to start from.
+func (g *EVMGateway) CallWithStateOverride(ctx context.Context, call *blockchain.ContractCall, overrides Overrides) (*CallResult, error) {
+	to, err := parseAddress(call.To)
+	if err != nil {
+		return nil, fmt.Errorf("CallWithStateOverride: %w", err)
+	}
+	blockNum, err := parseBlockNumber(call.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := ethereum.CallMsg{
+		To:    &to,
+		Data:  call.Data,
+		Value: call.Value,
+		Gas:   call.Gas,
+	}
+	if g.wallet != nil {
+		msg.From = common.HexToAddress(g.wallet.Address())
+	}
+
+	res, err := g.client.callWithStateOverride(ctx, msg, blockNum, overrides)
+	if err != nil {
+		return nil, fmt.Errorf("CallWithStateOverride: %w", err)
+	}
+	return res, nil
+}
+
+// EOF: internal/blockchain/evm/stateoverride.go
@@ -0,0 +1,109 @@
+// Package evm_test tests eth_call simulation with state overrides.
+//
+// File: internal/blockchain/evm/stateoverride_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+var (
+	// storageRuntime is the runtime part of storageInitCode.
+	storageRuntime = storageInitCode[12:]
+	// callerBalanceRuntime returns BALANCE(CALLER) as a uint256.
+	callerBalanceRuntime = common.FromHex("3331600052602060" + "00f3")
+	// revertNopeRuntime reverts with Error("nope").
+	revertNopeRuntime = common.FromHex("6064600c600039" + "60646000fd" +
+		"08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6e6f706500000000000000000000000000000000000000000000000000000000")
+)
+
+func TestEVMGateway_CallWithStateOverride(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	self := common.HexToAddress(wallet.Address())
+	sim := simulated.NewBackend(types.GenesisAlloc{self: {Balance: big.NewInt(1e18)}})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+	target := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	call := func(overrides evm.Overrides) *evm.CallResult {
+		t.Helper()
+		res, err := gateway.CallWithStateOverride(ctx, &blockchain.ContractCall{
+			To:   target.Hex(),
+			Data: common.FromHex("6d4ce63c"), // get()
+		}, overrides)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("code and storage", func(t *testing.T) {
+		res := call(evm.Overrides{target: {
+			Code:      storageRuntime,
+			StateDiff: map[common.Hash]common.Hash{{}: common.BigToHash(big.NewInt(5))},
+		}})
+		assert.False(t, res.Reverted)
+		assert.Equal(t, big.NewInt(5), new(big.Int).SetBytes(res.Data))
+	})
+
+	t.Run("balance", func(t *testing.T) {
+		res := call(evm.Overrides{
+			target: {Code: callerBalanceRuntime},
+			self:   {Balance: big.NewInt(12345)},
+		})
+		assert.Equal(t, big.NewInt(12345), new(big.Int).SetBytes(res.Data))
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		res := call(evm.Overrides{target: {Code: revertNopeRuntime}})
+		assert.True(t, res.Reverted)
+		assert.Equal(t, "nope", res.RevertReason)
+		assert.Len(t, res.Data, 100)
+	})
+
+	// Nothing was written on chain.
+	code, err := sim.Client().CodeAt(ctx, target, nil)
+	require.NoError(t, err)
+	assert.Empty(t, code)
+}
+
+func TestClient_CallWithStateOverride_NotSupported(t *testing.T) {
+	bodies := []string{
+		`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"the method eth_call does not exist/is not available"}}`,
+		`{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"too many arguments, want at most 2"}}`,
+	}
+	for _, body := range bodies {
+		srv := newStatusServer(t, http.StatusOK, body)
+		client, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{}, nil, time.Second)
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+
+		to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+		_, err = client.CallWithStateOverride(context.Background(), ethereum.CallMsg{To: &to}, evm.Overrides{
+			to: {Code: storageRuntime},
+		})
+		assert.ErrorIs(t, err, evm.ErrNotSupported)
+	}
+}
+
+// EOF: internal/blockchain/evm/stateoverride_test.go
@@ -0,0 +1,70 @@
+// Package evm reads raw contract storage slots.
+//
+// File: internal/blockchain/evm/storage.go
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// StorageAt returns the 32‑byte value of a storage slot of address at the
+// specified block (nil = latest).
+func (c *Client) StorageAt(ctx context.Context, address common.Address, slot common.Hash, block *big.Int) ([]byte, error) {
+	result, err := c.withRetry(ctx, "StorageAt", func(ctx context.Context) (interface{}, error) {
+		return c.eth().StorageAt(ctx, address, slot, block)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// GetStorageAt returns the 32‑byte value of a storage slot, for example an
+// EIP‑1967 proxy implementation slot. address may be a hex address or an
+// ENS name; slot is a 0x‑prefixed hex or a decimal slot number.
+func (g *EVMGateway) GetStorageAt(ctx context.Context, address, slot string, block blockchain.BlockNumber) ([]byte, error) {
+	addr, err := g.resolveAddress(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("GetStorageAt: %w", err)
+	}
+	key, err := parseSlot(slot)
+	if err != nil {
+		return nil, fmt.Errorf("GetStorageAt: %w", err)
+	}
+	blockNum, err := parseBlockNumber(block)
+	if err != nil {
+		return nil, fmt.Errorf("GetStorageAt: %w", err)
+	}
+	value, err := g.client.StorageAt(ctx, addr, key, blockNum)
+	if err != nil {
+		return nil, fmt.Errorf("GetStorageAt: %w", err)
+	}
+	return value, nil
+}
+
+// parseSlot parses a storage slot given as 0x‑prefixed hex (up to 32 bytes)
+// or as a decimal number below 2^256.
+func parseSlot(s string) (common.Hash, error) {
+	digits, base := s, 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		digits, base = s[2:], 16
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if digits == "" || !ok || n.Sign() < 0 || strings.ContainsAny(digits, "+-_") {
+		return common.Hash{}, fmt.Errorf("invalid storage slot %q", s)
+	}
+	if n.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("storage slot %q exceeds 32 bytes", s)
+	}
+	return common.BigToHash(n), nil
+}
+
+// EOF: internal/blockchain/evm/storage.go
@@ -0,0 +1,70 @@
+// Package evm_test tests raw storage reads.
+//
+// File: internal/blockchain/evm/storage_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+func TestEVMGateway_GetStorageAt(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	gateway := newSimulatedGateway(t, sim, wallet)
+	ctx := context.Background()
+
+	_, addr, err := gateway.DeployContract(ctx, storageInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+	deployed, err := gateway.BlockNumber(ctx)
+	require.NoError(t, err)
+
+	// set(42)
+	data := append(common.FromHex("60fe47b1"), common.LeftPadBytes(big.NewInt(42).Bytes(), 32)...)
+	to := addr.Hex()
+	_, err = gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Data: data})
+	require.NoError(t, err)
+	sim.Commit()
+
+	for _, slot := range []string{"0", "0x0", "0x00", "0x" + common.Hash{}.Hex()[2:]} {
+		value, err := gateway.GetStorageAt(ctx, addr.Hex(), slot, blockchain.BlockNumberLatest)
+		require.NoError(t, err, slot)
+		assert.Len(t, value, 32)
+		assert.Equal(t, big.NewInt(42), new(big.Int).SetBytes(value), slot)
+	}
+
+	value, err := gateway.GetStorageAt(ctx, addr.Hex(), "1", "")
+	require.NoError(t, err)
+	assert.Zero(t, new(big.Int).SetBytes(value).Sign(), "unused slot")
+
+	value, err = gateway.GetStorageAt(ctx, addr.Hex(), "0", blockchain.BlockNumber(strconv.FormatUint(deployed, 10)))
+	require.NoError(t, err)
+	assert.Zero(t, new(big.Int).SetBytes(value).Sign(), "before set")
+
+	for _, slot := range []string{"", "0x", "-1", "+1", "1_000", "0xzz", "1.5", "0x1" + common.Hash{}.Hex()[2:]} {
+		_, err := gateway.GetStorageAt(ctx, addr.Hex(), slot, "")
+		assert.ErrorContains(t, err, "storage slot", slot)
+	}
+	_, err = gateway.GetStorageAt(ctx, "0x1234", "0", "")
+	assert.Error(t, err)
+}
+
+// EOF: internal/blockchain/evm/storage_test.go
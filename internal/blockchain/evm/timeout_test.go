@@ -0,0 +1,71 @@
+// Package evm_test tests per‑attempt RPC deadlines.
+//
+// File: internal/blockchain/evm/timeout_test.go
+
+package evm_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// newHungServer returns an RPC endpoint that never answers until the test ends.
+func newHungServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	t.Cleanup(func() {
+		close(done)
+		srv.Close()
+	})
+	return srv
+}
+
+func TestClient_PerAttemptTimeout(t *testing.T) {
+	srv := newHungServer(t)
+	retry := &evm.RetryConfig{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		BackoffFactor:  1,
+	}
+	client, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{}, retry, 50*time.Millisecond)
+	require.NoError(t, err)
+	defer client.Close()
+
+	start := time.Now()
+	_, err = client.ChainID(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second, "hung RPC must not stall the caller")
+}
+
+func TestClient_CallerDeadlineWins(t *testing.T) {
+	srv := newHungServer(t)
+	client, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{}, nil, time.Minute)
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, time.Minute, client.Timeout())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = client.BlockNumber(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+// EOF: internal/blockchain/evm/timeout_test.go
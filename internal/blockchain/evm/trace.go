@@ -0,0 +1,305 @@
+// Package evm traces mined transactions and calls with the debug namespace.
+//
+// File: internal/blockchain/evm/trace.go
+
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// TraceOptions configures the callTracer. A nil *TraceOptions uses the
+// node's defaults.
+type TraceOptions struct {
+	// OnlyTopCall skips nested calls.
+	OnlyTopCall bool
+	// Timeout bounds tracing on the node (0 = the node's default, usually 5s).
+	Timeout time.Duration
+	// WithLogs records the logs each call emits in CallFrame.Logs.
+	WithLogs bool
+}
+
+// CallFrame is one call in a callTracer trace: the transaction itself at
+// the root and every internal call nested beneath it.
+type CallFrame struct {
+	Type         string // CALL, STATICCALL, DELEGATECALL, CREATE, CREATE2, SELFDESTRUCT
+	From         common.Address
+	To           common.Address // the created contract for CREATE/CREATE2
+	Value        *big.Int       // nil for STATICCALL and DELEGATECALL
+	Gas          uint64
+	GasUsed      uint64
+	Input        []byte
+	Output       []byte
+	Error        string // such as "execution reverted" or "out of gas"; empty on success
+	RevertReason string // decoded by the node, if any
+	Calls        []CallFrame
+	Logs         []CallLog // with TraceOptions.WithLogs
+}
+
+// CallLog is a log emitted by a call in a trace.
+type CallLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// callFrameJSON is the callTracer wire format.
+type callFrameJSON struct {
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to"`
+	Value        *hexutil.Big    `json:"value"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output"`
+	Error        string          `json:"error"`
+	RevertReason string          `json:"revertReason"`
+	Calls        []CallFrame     `json:"calls"`
+	Logs         []CallLog       `json:"logs"`
+}
+
+// UnmarshalJSON decodes a callTracer frame.
+func (f *CallFrame) UnmarshalJSON(data []byte) error {
+	var raw callFrameJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*f = CallFrame{
+		Type:         raw.Type,
+		From:         raw.From,
+		Value:        (*big.Int)(raw.Value),
+		Gas:          uint64(raw.Gas),
+		GasUsed:      uint64(raw.GasUsed),
+		Input:        raw.Input,
+		Output:       raw.Output,
+		Error:        raw.Error,
+		RevertReason: raw.RevertReason,
+		Calls:        raw.Calls,
+		Logs:         raw.Logs,
+	}
+	if raw.To != nil {
+		f.To = *raw.To
+	}
+	return nil
+}
+
+// traceSupport caches whether the node serves debug_traceTransaction.
+type traceSupport struct {
+	mu    sync.Mutex
+	known bool
+	ok    bool
+}
+
+func (s *traceSupport) get() (known, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.known, s.ok
+}
+
+func (s *traceSupport) set(ok bool) {
+	s.mu.Lock()
+	s.known, s.ok = true, ok
+	s.mu.Unlock()
+}
+
+// SupportsTracing probes whether the node serves debug_traceTransaction by
+// tracing the zero hash: a node with the debug namespace answers "not
+// found", one without rejects the method. The answer is cached, as is any
+// answer learnt from TraceTransaction.
+func (c *Client) SupportsTracing(ctx context.Context) (bool, error) {
+	if known, ok := c.tracing.get(); known {
+		return ok, nil
+	}
+	_, err := c.TraceTransaction(ctx, common.Hash{}, &TraceOptions{OnlyTopCall: true})
+	if errors.Is(err, ErrNotSupported) {
+		return false, nil
+	}
+	if err != nil && !errors.Is(ClassifyError(err), ErrInvalidArgument) && !traceNotFound(err) {
+		return false, fmt.Errorf("SupportsTracing: %w", err)
+	}
+	c.tracing.set(true)
+	return true, nil
+}
+
+// TraceTransaction replays a mined transaction with debug_traceTransaction
+// and the callTracer and returns its call tree. It needs a node with the
+// debug namespace enabled, usually an archive node for old transactions;
+// providers without it fail with ErrNotSupported, which is remembered so
+// later calls fail without a request.
+func (c *Client) TraceTransaction(ctx context.Context, txHash common.Hash, opts *TraceOptions) (*CallFrame, error) {
+	if known, ok := c.tracing.get(); known && !ok {
+		return nil, fmt.Errorf("TraceTransaction: debug_traceTransaction: %w", ErrNotSupported)
+	}
+	config := traceConfig(opts)
+	result, err := c.withRetry(ctx, "TraceTransaction", func(ctx context.Context) (interface{}, error) {
+		var frame CallFrame
+		err := c.eth().Client().CallContext(ctx, &frame, "debug_traceTransaction", txHash, config)
+		if err != nil {
+			switch {
+			case tracingUnsupported(err):
+				return nil, &RPCError{Kind: ErrNotSupported, Err: err}
+			case traceNotFound(err):
+				return nil, &RPCError{Err: err} // final; not worth retrying
+			}
+			return nil, err
+		}
+		return &frame, nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotSupported) {
+			c.tracing.set(false)
+		}
+		return nil, err
+	}
+	c.tracing.set(true)
+	return result.(*CallFrame), nil
+}
+
+// TraceCall runs call against the latest state with debug_traceCall and
+// the callTracer and returns its call tree, as TraceTransaction does for a
+// mined transaction. Nothing is broadcast. Providers without the debug
+// namespace fail with ErrNotSupported, which is remembered.
+func (c *Client) TraceCall(ctx context.Context, call ethereum.CallMsg, opts *TraceOptions) (*CallFrame, error) {
+	if known, ok := c.callTracing.get(); known && !ok {
+		return nil, fmt.Errorf("TraceCall: debug_traceCall: %w", ErrNotSupported)
+	}
+	config := traceConfig(opts)
+	result, err := c.withRetry(ctx, "TraceCall", func(ctx context.Context) (interface{}, error) {
+		var frame CallFrame
+		err := c.eth().Client().CallContext(ctx, &frame, "debug_traceCall", callArg(call), "latest", config)
+		if err != nil {
+			if tracingUnsupported(err) {
+				return nil, &RPCError{Kind: ErrNotSupported, Err: err}
+			}
+			return nil, err
+		}
+		return &frame, nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotSupported) {
+			c.callTracing.set(false)
+		}
+		return nil, err
+	}
+	c.callTracing.set(true)
+	return result.(*CallFrame), nil
+}
+
+// traceConfig returns the callTracer configuration for opts.
+func traceConfig(opts *TraceOptions) map[string]interface{} {
+	config := map[string]interface{}{"tracer": "callTracer"}
+	if opts == nil {
+		return config
+	}
+	tracerConfig := map[string]interface{}{}
+	if opts.OnlyTopCall {
+		tracerConfig["onlyTopCall"] = true
+	}
+	if opts.WithLogs {
+		tracerConfig["withLog"] = true
+	}
+	if len(tracerConfig) > 0 {
+		config["tracerConfig"] = tracerConfig
+	}
+	if opts.Timeout > 0 {
+		config["timeout"] = opts.Timeout.String()
+	}
+	return config
+}
+
+// tracingUnsupported reports whether err means the node does not serve the
+// debug namespace. Providers report it inconsistently.
+func tracingUnsupported(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcCodeMethodNotFound {
+		return true
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == 403 || httpErr.StatusCode == 404 || httpErr.StatusCode == 405) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"does not exist", "not available", "method not found", "not supported", "unsupported method", "not whitelisted", "not allowed", "disabled"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// traceNotFound reports whether err is a debug node's answer for an
+// unknown transaction.
+func traceNotFound(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "not found")
+}
+
+// TraceTransaction returns the call tree of a mined transaction given as
+// 0x‑hex hash. See Client.TraceTransaction.
+func (g *EVMGateway) TraceTransaction(ctx context.Context, txHash string, opts *TraceOptions) (*CallFrame, error) {
+	if len(common.FromHex(txHash)) != common.HashLength {
+		return nil, fmt.Errorf("TraceTransaction: invalid transaction hash: %s", txHash)
+	}
+	return g.client.TraceTransaction(ctx, common.HexToHash(txHash), opts)
+}
+
+// String renders the call tree one call per line, indented by depth, for
+// logs:
+//
+//	CALL 0xSender → 0xRouter value=0 gasUsed=91234 input=0x38ed1739…(260 bytes)
+//	  STATICCALL 0xRouter → 0xPair gasUsed=2504 input=0x0902f1ac
+//	  CALL 0xRouter → 0xToken gasUsed=30120 input=0xa9059cbb…(68 bytes) error="execution reverted: STF"
+func (f *CallFrame) String() string {
+	var b strings.Builder
+	f.format(&b, 0)
+	return b.String()
+}
+
+func (f *CallFrame) format(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(b, "%s %s → %s", f.Type, f.From.Hex(), f.To.Hex())
+	if f.Value != nil {
+		fmt.Fprintf(b, " value=%s", f.Value)
+	}
+	fmt.Fprintf(b, " gasUsed=%d", f.GasUsed)
+	if len(f.Input) > 0 {
+		fmt.Fprintf(b, " input=%s", shortHex(f.Input))
+	}
+	if f.Error != "" {
+		reason := f.RevertReason
+		if reason == "" {
+			reason = DecodeRevert(f.Output)
+		}
+		if reason != "" {
+			fmt.Fprintf(b, " error=%q", f.Error+": "+reason)
+		} else {
+			fmt.Fprintf(b, " error=%q", f.Error)
+		}
+	}
+	b.WriteString("\n")
+	for i := range f.Calls {
+		f.Calls[i].format(b, depth+1)
+	}
+}
+
+// shortHex renders data as its 4‑byte selector followed by the total length.
+func shortHex(data []byte) string {
+	if len(data) <= 4 {
+		return hexutil.Encode(data)
+	}
+	return fmt.Sprintf("%s…(%d bytes)", hexutil.Encode(data[:4]), len(data))
+}
+
+// EOF: internal/blockchain/evm/trace.go
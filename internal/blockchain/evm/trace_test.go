@@ -0,0 +1,115 @@
+// Package evm_test tests debug_traceTransaction call traces.
+//
+// File: internal/blockchain/evm/trace_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+const callTrace = `{
+	"type": "CALL",
+	"from": "0x00000000000000000000000000000000000000a1",
+	"to": "0x00000000000000000000000000000000000000b2",
+	"value": "0xde0b6b3a7640000",
+	"gas": "0x30d40",
+	"gasUsed": "0x1d4c0",
+	"input": "0x38ed173900000000000000000000000000000000000000000000000000000000000000ff",
+	"error": "execution reverted",
+	"calls": [
+		{"type": "STATICCALL", "from": "0x00000000000000000000000000000000000000b2", "to": "0x00000000000000000000000000000000000000c3", "gas": "0x1000", "gasUsed": "0x9c8", "input": "0x0902f1ac", "output": "0x01"},
+		{"type": "CALL", "from": "0x00000000000000000000000000000000000000b2", "to": "0x00000000000000000000000000000000000000d4", "value": "0x0", "gas": "0x8000", "gasUsed": "0x75a8",
+		 "input": "0xa9059cbb", "error": "execution reverted",
+		 "output": "0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000035354460000000000000000000000000000000000000000000000000000000000"}
+	]
+}`
+
+func TestClient_TraceTransaction(t *testing.T) {
+	var config map[string]interface{}
+	client := newFeeClient(t, map[string]interface{}{
+		"debug_traceTransaction": func(params []json.RawMessage) interface{} {
+			require.Len(t, params, 2)
+			require.NoError(t, json.Unmarshal(params[1], &config))
+			return json.RawMessage(callTrace)
+		},
+	})
+	ctx := context.Background()
+
+	frame, err := client.TraceTransaction(ctx, common.HexToHash("0xabc"), &evm.TraceOptions{Timeout: 10 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, "callTracer", config["tracer"])
+	assert.Equal(t, "10s", config["timeout"])
+	assert.NotContains(t, config, "tracerConfig")
+
+	assert.Equal(t, "CALL", frame.Type)
+	assert.Equal(t, common.HexToAddress("0xa1"), frame.From)
+	assert.Equal(t, common.HexToAddress("0xb2"), frame.To)
+	assert.Equal(t, big.NewInt(1e18), frame.Value)
+	assert.Equal(t, uint64(200000), frame.Gas)
+	assert.Equal(t, uint64(120000), frame.GasUsed)
+	assert.Equal(t, "execution reverted", frame.Error)
+	require.Len(t, frame.Calls, 2)
+	assert.Equal(t, "STATICCALL", frame.Calls[0].Type)
+	assert.Nil(t, frame.Calls[0].Value)
+	assert.Equal(t, []byte{0x01}, frame.Calls[0].Output)
+
+	out := frame.String()
+	assert.Equal(t, ""+
+		"CALL 0x00000000000000000000000000000000000000A1 → 0x00000000000000000000000000000000000000b2 value=1000000000000000000 gasUsed=120000 input=0x38ed1739…(36 bytes) error=\"execution reverted\"\n"+
+		"  STATICCALL 0x00000000000000000000000000000000000000b2 → 0x00000000000000000000000000000000000000C3 gasUsed=2504 input=0x0902f1ac\n"+
+		"  CALL 0x00000000000000000000000000000000000000b2 → 0x00000000000000000000000000000000000000D4 value=0 gasUsed=30120 input=0xa9059cbb error=\"execution reverted: STF\"\n",
+		out)
+
+	ok, err := client.SupportsTracing(ctx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestClient_TraceTransaction_NotSupported(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0", "id": req.ID,
+			"error": map[string]interface{}{"code": -32601, "message": "the method " + req.Method + " does not exist/is not available"},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	client, err := evm.NewClient(context.Background(), srv.URL, &observe.NoopLogger{},
+		&evm.RetryConfig{MaxAttempts: 3}, time.Second, evm.WithSkipChainIDCheck())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	ctx := context.Background()
+
+	ok, err := client.SupportsTracing(ctx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, int32(1), requests.Load(), "unsupported methods are not retried")
+
+	_, err = client.TraceTransaction(ctx, common.HexToHash("0xabc"), nil)
+	assert.ErrorIs(t, err, evm.ErrNotSupported)
+	assert.Equal(t, int32(1), requests.Load(), "the probe result is cached")
+}
+
+// EOF: internal/blockchain/evm/trace_test.go
@@ -0,0 +1,463 @@
+// Package evm tracks broadcast transactions until they are mined,
+// rebroadcasting those dropped from the mempool.
+//
+// File: internal/blockchain/evm/tracker.go
+
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Metric names recorded by TxTracker. Every metric carries a "chain" label.
+const (
+	MetricTxDropped     = "tx_dropped_total"
+	MetricTxRebroadcast = "tx_rebroadcasts_total"
+	MetricTxSpeedUp     = "tx_speedups_total"
+)
+
+// DefaultTrackerInterval is how often a TxTracker checks its transactions
+// when TrackerConfig.Interval is 0.
+const DefaultTrackerInterval = 15 * time.Second
+
+// TrackerConfig configures a TxTracker.
+type TrackerConfig struct {
+	// Interval between checks (0 = DefaultTrackerInterval).
+	Interval time.Duration `mapstructure:"interval"`
+	// SpeedUpAfter is how long a transaction may stay pending before it is
+	// replaced with a fee‑bumped copy (0 = never).
+	SpeedUpAfter time.Duration `mapstructure:"speed_up_after"`
+	// StatePath is the JSON file the tracked transactions are saved to, so
+	// they survive a restart ("" = memory only).
+	StatePath string `mapstructure:"state_path"`
+}
+
+// TrackedTx is a broadcast transaction awaiting its receipt.
+type TrackedTx struct {
+	// Hash is the latest broadcast version of the transaction.
+	Hash common.Hash `json:"hash"`
+	// Raw is the signed encoding of Hash, rebroadcast if it is dropped.
+	Raw   hexutil.Bytes  `json:"raw"`
+	From  common.Address `json:"from"`
+	Nonce uint64         `json:"nonce"`
+	// SentAt is when the transaction was first broadcast.
+	SentAt time.Time `json:"sent_at"`
+	// Deadline is when the current version is sped up; zero if never.
+	Deadline     time.Time `json:"deadline,omitempty"`
+	Rebroadcasts int       `json:"rebroadcasts"`
+	SpeedUps     int       `json:"speed_ups"`
+	// Replaced lists earlier versions with the same nonce (speed‑ups and
+	// cancellations); a receipt for any of them completes the transaction.
+	Replaced []common.Hash `json:"replaced,omitempty"`
+}
+
+// trackerState is the persisted form of a TxTracker.
+type trackerState struct {
+	Transactions []*TrackedTx `json:"transactions"`
+}
+
+// TxTracker watches the transactions broadcast through an EVMGateway until
+// they are mined. Each check looks for a receipt, rebroadcasts the same
+// signed bytes when the node no longer knows the transaction, and replaces
+// transactions pending longer than SpeedUpAfter with a fee‑bumped copy
+// (see EVMGateway.SpeedUpTransaction). Attach it with
+// EVMGateway.SetTracker. It is safe for concurrent use.
+type TxTracker struct {
+	gateway *EVMGateway
+	cfg     TrackerConfig
+
+	mu  sync.Mutex
+	txs map[string]*TrackedTx // keyed by sender and nonce
+
+	runMu sync.Mutex
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewTxTracker creates a tracker for gateway and loads any transactions
+// saved at cfg.StatePath.
+func NewTxTracker(gateway *EVMGateway, cfg TrackerConfig) (*TxTracker, error) {
+	if cfg.Interval == 0 {
+		cfg.Interval = DefaultTrackerInterval
+	}
+	if cfg.Interval < 0 || cfg.SpeedUpAfter < 0 {
+		return nil, errors.New("NewTxTracker: interval and speed_up_after must not be negative")
+	}
+	t := &TxTracker{
+		gateway: gateway,
+		cfg:     cfg,
+		txs:     make(map[string]*TrackedTx),
+	}
+	if err := t.load(); err != nil {
+		return nil, fmt.Errorf("NewTxTracker: %w", err)
+	}
+	return t, nil
+}
+
+// trackerKey identifies the transactions that can replace each other.
+func trackerKey(from common.Address, nonce uint64) string {
+	return fmt.Sprintf("%s/%d", from.Hex(), nonce)
+}
+
+// Track starts watching a signed, broadcast transaction. A transaction with
+// the sender and nonce of a tracked one replaces it.
+func (t *TxTracker) Track(tx *types.Transaction) error {
+	from, err := txSender(tx)
+	if err != nil {
+		return fmt.Errorf("Track: recover sender: %w", err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("Track: encode tx: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.gateway.client.clock.Now()
+	key := trackerKey(from, tx.Nonce())
+	entry, ok := t.txs[key]
+	if !ok {
+		entry = &TrackedTx{From: from, Nonce: tx.Nonce(), SentAt: now}
+		t.txs[key] = entry
+	} else if entry.Hash != tx.Hash() {
+		entry.Replaced = append(entry.Replaced, entry.Hash)
+	}
+	entry.Hash, entry.Raw = tx.Hash(), raw
+	entry.Deadline = time.Time{}
+	if t.cfg.SpeedUpAfter > 0 {
+		entry.Deadline = now.Add(t.cfg.SpeedUpAfter)
+	}
+	return t.save()
+}
+
+// Pending returns copies of the tracked transactions ordered by sender
+// and nonce.
+func (t *TxTracker) Pending() []TrackedTx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.pendingLocked()
+}
+
+// pendingLocked returns Pending; t.mu must be held.
+func (t *TxTracker) pendingLocked() []TrackedTx {
+	out := make([]TrackedTx, 0, len(t.txs))
+	for _, entry := range t.txs {
+		cp := *entry
+		cp.Raw = append(hexutil.Bytes(nil), entry.Raw...)
+		cp.Replaced = append([]common.Hash(nil), entry.Replaced...)
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].From != out[j].From {
+			return out[i].From.Cmp(out[j].From) < 0
+		}
+		return out[i].Nonce < out[j].Nonce
+	})
+	return out
+}
+
+// Check inspects every tracked transaction once. Mined transactions are
+// forgotten, dropped ones rebroadcast and overdue ones sped up. Failures for
+// individual transactions are logged and retried on the next check; the
+// returned error only reports a failure to save the state.
+func (t *TxTracker) Check(ctx context.Context) error {
+	for _, entry := range t.Pending() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		t.check(ctx, entry)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.save()
+}
+
+// check handles one tracked transaction.
+func (t *TxTracker) check(ctx context.Context, entry TrackedTx) {
+	client := t.gateway.client
+
+	// Mined, in any of its versions?
+	for _, hash := range append([]common.Hash{entry.Hash}, entry.Replaced...) {
+		if receipt, err := client.transactionReceipt(ctx, hash); err == nil && receipt != nil {
+			t.forget(entry, "mined", map[string]interface{}{"mined_hash": hash.Hex()})
+			return
+		}
+	}
+	// Nonce used by a transaction sent elsewhere?
+	if nonce, err := client.nonceAtOnce(ctx, entry.From); err == nil && nonce > entry.Nonce {
+		t.forget(entry, "nonce used", nil)
+		return
+	}
+
+	_, pending, err := client.transactionByHashOnce(ctx, entry.Hash)
+	switch {
+	case errors.Is(err, ethereum.NotFound):
+		t.rebroadcast(ctx, entry)
+	case err != nil:
+		t.gateway.logger.Debug("tracked transaction lookup failed", map[string]interface{}{
+			"tx_hash": entry.Hash.Hex(),
+			"error":   err.Error(),
+		})
+	case pending && !entry.Deadline.IsZero() && !client.clock.Now().Before(entry.Deadline):
+		t.speedUp(ctx, entry)
+	}
+}
+
+// rebroadcast resends a transaction the node no longer knows.
+func (t *TxTracker) rebroadcast(ctx context.Context, entry TrackedTx) {
+	t.count(MetricTxDropped)
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(entry.Raw); err != nil {
+		t.forget(entry, "undecodable", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	err := t.gateway.client.SendTransaction(ctx, tx)
+	switch {
+	case errors.Is(err, ErrNonceTooLow):
+		t.forget(entry, "nonce used", nil)
+		return
+	case err != nil && !strings.Contains(strings.ToLower(err.Error()), "already known"):
+		t.gateway.logger.Warn("rebroadcast failed", map[string]interface{}{
+			"tx_hash": entry.Hash.Hex(),
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	t.count(MetricTxRebroadcast)
+	t.update(entry, func(e *TrackedTx) { e.Rebroadcasts++ })
+	t.gateway.logger.Info("dropped transaction rebroadcast", map[string]interface{}{
+		"tx_hash": entry.Hash.Hex(),
+		"nonce":   entry.Nonce,
+	})
+}
+
+// speedUp replaces an overdue transaction with a fee‑bumped copy. Only
+// transactions from the gateway wallet's accounts can be sped up.
+func (t *TxTracker) speedUp(ctx context.Context, entry TrackedTx) {
+	g := t.gateway
+	if g.wallet == nil {
+		return
+	}
+	builder, err := g.txBuilder(ctx, entry.From.Hex())
+	if err != nil {
+		return
+	}
+	original := new(types.Transaction)
+	if err := original.UnmarshalBinary(entry.Raw); err != nil {
+		return
+	}
+
+	signedTx, err := g.speedUp(ctx, builder, original)
+	if err == nil {
+		err = g.client.SendTransaction(ctx, signedTx)
+	}
+	if err != nil {
+		g.logger.Warn("speed‑up failed", map[string]interface{}{
+			"tx_hash": entry.Hash.Hex(),
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err := t.Track(signedTx); err != nil {
+		g.logger.Warn("could not track transaction", map[string]interface{}{
+			"tx_hash": signedTx.Hash().Hex(),
+			"error":   err.Error(),
+		})
+	}
+	t.count(MetricTxSpeedUp)
+	t.update(entry, func(e *TrackedTx) { e.SpeedUps++ })
+	g.logger.Info("pending transaction sped up", map[string]interface{}{
+		"original":    entry.Hash.Hex(),
+		"replacement": signedTx.Hash().Hex(),
+		"nonce":       entry.Nonce,
+	})
+}
+
+// update applies fn to the tracked entry for entry's sender and nonce.
+func (t *TxTracker) update(entry TrackedTx, fn func(*TrackedTx)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, ok := t.txs[trackerKey(entry.From, entry.Nonce)]; ok {
+		fn(e)
+	}
+}
+
+// forget stops tracking entry.
+func (t *TxTracker) forget(entry TrackedTx, reason string, fields map[string]interface{}) {
+	t.mu.Lock()
+	delete(t.txs, trackerKey(entry.From, entry.Nonce))
+	t.mu.Unlock()
+
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+	fields["tx_hash"] = entry.Hash.Hex()
+	fields["reason"] = reason
+	t.gateway.logger.Debug("transaction no longer tracked", fields)
+}
+
+// count increments a tracker metric.
+func (t *TxTracker) count(name string) {
+	c := t.gateway.client
+	if !c.metricsOn {
+		return
+	}
+	c.metrics.Counter(name, 1, map[string]string{"chain": c.chain})
+}
+
+// Start checks the tracked transactions every Interval until Stop.
+// Calling Start on a running tracker has no effect.
+func (t *TxTracker) Start() {
+	t.runMu.Lock()
+	defer t.runMu.Unlock()
+	if t.stop != nil {
+		return
+	}
+	t.stop, t.done = make(chan struct{}), make(chan struct{})
+	go t.run(t.stop, t.done)
+}
+
+// run is the Start loop.
+func (t *TxTracker) run(stop, done chan struct{}) {
+	defer close(done)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.gateway.client.clock.After(t.cfg.Interval):
+		}
+		if err := t.Check(ctx); err != nil && ctx.Err() == nil {
+			t.gateway.logger.Warn("transaction tracker check failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// Stop ends the Start loop and waits for a running check to finish.
+// It is safe to call on a tracker that was never started.
+func (t *TxTracker) Stop() {
+	t.runMu.Lock()
+	defer t.runMu.Unlock()
+	if t.stop == nil {
+		return
+	}
+	close(t.stop)
+	<-t.done
+	t.stop, t.done = nil, nil
+}
+
+// load reads the saved state, if any; t.mu need not be held.
+func (t *TxTracker) load() error {
+	if t.cfg.StatePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(t.cfg.StatePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read state: %w", err)
+	}
+	var state trackerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("parse state %s: %w", t.cfg.StatePath, err)
+	}
+	for _, entry := range state.Transactions {
+		t.txs[trackerKey(entry.From, entry.Nonce)] = entry
+	}
+	return nil
+}
+
+// save writes the state atomically; t.mu must be held.
+func (t *TxTracker) save() error {
+	if t.cfg.StatePath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(trackerState{Transactions: t.pendingPointers()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(t.cfg.StatePath), filepath.Base(t.cfg.StatePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), t.cfg.StatePath); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+	return nil
+}
+
+// pendingPointers returns the tracked entries in Pending order; t.mu must
+// be held.
+func (t *TxTracker) pendingPointers() []*TrackedTx {
+	sorted := t.pendingLocked()
+	out := make([]*TrackedTx, len(sorted))
+	for i := range sorted {
+		out[i] = &sorted[i]
+	}
+	return out
+}
+
+// transactionByHashOnce looks a transaction up once, bounded by the
+// per‑attempt timeout. ethereum.NotFound means the node does not know it.
+func (c *Client) transactionByHashOnce(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	if err := c.connect(ctx); err != nil {
+		return nil, false, err
+	}
+	if err := c.limiter.wait(ctx, c.clock, 1); err != nil {
+		return nil, false, err
+	}
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	return c.eth().TransactionByHash(attemptCtx, hash)
+}
+
+// nonceAtOnce returns the account's nonce at the latest block once,
+// bounded by the per‑attempt timeout.
+func (c *Client) nonceAtOnce(ctx context.Context, account common.Address) (uint64, error) {
+	if err := c.connect(ctx); err != nil {
+		return 0, err
+	}
+	if err := c.limiter.wait(ctx, c.clock, 1); err != nil {
+		return 0, err
+	}
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	return c.eth().NonceAt(attemptCtx, account, nil)
+}
+
+// EOF: internal/blockchain/evm/tracker.go
@@ -0,0 +1,186 @@
+// Package evm_test tests in‑flight transaction tracking.
+//
+// File: internal/blockchain/evm/tracker_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// newTrackedGateway returns a gateway on a simulated backend with a tracker
+// attached, its fake clock and metrics.
+func newTrackedGateway(t *testing.T, cfg evm.TrackerConfig) (*evm.EVMGateway, *simulated.Backend, *fakeClock, *fakeMetrics) {
+	t.Helper()
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	t.Cleanup(func() { sim.Close() })
+
+	metrics := newFakeMetrics()
+	ec := reflect.ValueOf(sim.Client()).Field(0).Interface().(*ethclient.Client)
+	client := evm.NewClientFromEthClient(ec, &observe.NoopLogger{}, nil,
+		evm.WithChainName("testnet"),
+		evm.WithMetrics(metrics),
+	)
+	clock := &fakeClock{now: time.Unix(1700000000, 0).UTC()}
+	client.SetClock(clock)
+	gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, wallet)
+
+	tracker, err := evm.NewTxTracker(gateway, cfg)
+	require.NoError(t, err)
+	gateway.SetTracker(tracker)
+	return gateway, sim, clock, metrics
+}
+
+// sendTransfer sends a small transfer; gasPrice 1 keeps it stuck in the pool.
+func sendTransfer(t *testing.T, gateway *evm.EVMGateway, gasPrice int64) string {
+	t.Helper()
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	hash, err := gateway.SendTransaction(context.Background(), &blockchain.Transaction{
+		To:       &to,
+		Value:    big.NewInt(1000),
+		Gas:      21000,
+		GasPrice: big.NewInt(gasPrice),
+	})
+	require.NoError(t, err)
+	return hash
+}
+
+func TestTxTracker_ForgetsMinedTransactions(t *testing.T) {
+	gateway, sim, _, _ := newTrackedGateway(t, evm.TrackerConfig{})
+	ctx := context.Background()
+	tracker := gateway.Tracker()
+
+	hash := sendTransfer(t, gateway, 1e10)
+	pending := tracker.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, common.HexToHash(hash), pending[0].Hash)
+	assert.Equal(t, uint64(0), pending[0].Nonce)
+	assert.NotEmpty(t, pending[0].Raw)
+
+	require.NoError(t, tracker.Check(ctx))
+	assert.Len(t, tracker.Pending(), 1, "still in the mempool")
+
+	sim.Commit()
+	require.NoError(t, tracker.Check(ctx))
+	assert.Empty(t, tracker.Pending())
+}
+
+func TestTxTracker_RebroadcastsDroppedTransaction(t *testing.T) {
+	gateway, sim, _, metrics := newTrackedGateway(t, evm.TrackerConfig{})
+	ctx := context.Background()
+	tracker := gateway.Tracker()
+
+	sim.Commit() // finish transaction indexing so lookups report not found
+	hash := sendTransfer(t, gateway, 1e10)
+	sim.Rollback() // empties the mempool
+
+	_, _, err := sim.Client().TransactionByHash(ctx, common.HexToHash(hash))
+	require.Error(t, err, "transaction should have been dropped")
+
+	require.NoError(t, tracker.Check(ctx))
+	pending := tracker.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, 1, pending[0].Rebroadcasts)
+	assert.Equal(t, float64(1), metrics.counters[evm.MetricTxDropped])
+	assert.Equal(t, float64(1), metrics.counters[evm.MetricTxRebroadcast])
+
+	sim.Commit()
+	receipt, err := sim.Client().TransactionReceipt(ctx, common.HexToHash(hash))
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+func TestTxTracker_SpeedsUpOverdueTransaction(t *testing.T) {
+	gateway, sim, clock, metrics := newTrackedGateway(t, evm.TrackerConfig{SpeedUpAfter: time.Minute})
+	ctx := context.Background()
+	tracker := gateway.Tracker()
+
+	stuck := sendTransfer(t, gateway, 1)
+	require.NoError(t, tracker.Check(ctx))
+	assert.Equal(t, 0, tracker.Pending()[0].SpeedUps, "not overdue yet")
+
+	clock.Advance(2 * time.Minute)
+	require.NoError(t, tracker.Check(ctx))
+	pending := tracker.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, 1, pending[0].SpeedUps)
+	assert.Equal(t, []common.Hash{common.HexToHash(stuck)}, pending[0].Replaced)
+	assert.NotEqual(t, common.HexToHash(stuck), pending[0].Hash)
+	assert.Equal(t, float64(1), metrics.counters[evm.MetricTxSpeedUp])
+
+	sim.Commit()
+	receipt, err := sim.Client().TransactionReceipt(ctx, pending[0].Hash)
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	require.NoError(t, tracker.Check(ctx))
+	assert.Empty(t, tracker.Pending())
+}
+
+func TestTxTracker_PersistsState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tracker.json")
+	gateway, _, _, _ := newTrackedGateway(t, evm.TrackerConfig{StatePath: path})
+
+	hash := sendTransfer(t, gateway, 1)
+
+	restored, err := evm.NewTxTracker(gateway, evm.TrackerConfig{StatePath: path})
+	require.NoError(t, err)
+	pending := restored.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, common.HexToHash(hash), pending[0].Hash)
+	assert.Equal(t, gateway.Tracker().Pending(), pending)
+}
+
+func TestEVMGateway_SpeedUpTransaction(t *testing.T) {
+	gateway, sim, _, _ := newTrackedGateway(t, evm.TrackerConfig{})
+	ctx := context.Background()
+
+	stuck := sendTransfer(t, gateway, 1)
+	fastHash, err := gateway.SpeedUpTransaction(ctx, stuck)
+	require.NoError(t, err)
+	sim.Commit()
+
+	fast, _, err := sim.Client().TransactionByHash(ctx, common.HexToHash(fastHash))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), fast.Nonce())
+	assert.Equal(t, big.NewInt(1000), fast.Value())
+	assert.Equal(t, uint64(21000), fast.Gas())
+	assert.Greater(t, fast.GasPrice().Int64(), int64(1))
+
+	_, err = gateway.SpeedUpTransaction(ctx, fastHash)
+	assert.ErrorContains(t, err, "no longer pending")
+}
+
+func TestEVMGateway_CloseStopsTrackerOnce(t *testing.T) {
+	gateway, _, _, _ := newTrackedGateway(t, evm.TrackerConfig{})
+	gateway.Tracker().Start()
+
+	require.NoError(t, gateway.Close())
+	require.NoError(t, gateway.Close(), "second Close is a no‑op")
+	gateway.Tracker().Stop() // already stopped; must not block
+
+	_, err := gateway.BlockNumber(context.Background())
+	assert.Error(t, err, "closed gateway")
+}
+
+// EOF: internal/blockchain/evm/tracker_test.go
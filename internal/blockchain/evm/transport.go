@@ -0,0 +1,53 @@
+// Package evm detects whether an RPC transport supports subscriptions.
+//
+// File: internal/blockchain/evm/transport.go
+
+package evm
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// subscriptionTransport reports whether rpcURL selects a transport that
+// supports eth_subscribe: WebSocket, IPC and stdio do, HTTP does not. It
+// mirrors the scheme handling of rpc.DialContext.
+func subscriptionTransport(rpcURL string) bool {
+	u, err := url.Parse(rpcURL)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "ws", "wss", "stdio", "":
+		return true // an empty scheme is an IPC path
+	default:
+		return false
+	}
+}
+
+// SupportsSubscriptions reports whether the client's transport supports
+// subscriptions (WebSocket or IPC endpoints). Features that can either
+// subscribe or poll, such as WaitForReceipt, use it to pick a strategy.
+func (c *Client) SupportsSubscriptions() bool {
+	return c.subscriptions
+}
+
+// subscribeNewHeads subscribes to new chain heads. The subscription outlives
+// ctx, which only bounds the subscribe call.
+func (c *Client) subscribeNewHeads(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	if err := c.connect(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.limiter.wait(ctx, c.clock, 1); err != nil {
+		return nil, err
+	}
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	return c.eth().SubscribeNewHead(attemptCtx, ch)
+}
+
+// EOF: internal/blockchain/evm/transport.go
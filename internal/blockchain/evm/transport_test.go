@@ -0,0 +1,131 @@
+// Package evm_test tests transport detection and subscription-based receipt waiting.
+//
+// File: internal/blockchain/evm/transport_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// stalledClock never fires, so a client that polls would wait forever.
+type stalledClock struct{}
+
+func (stalledClock) Now() time.Time                       { return time.Unix(1700000000, 0) }
+func (stalledClock) After(time.Duration) <-chan time.Time { return nil }
+
+// miningClock is a fakeClock that mines a block on the reorg node before
+// each wait, so polling makes progress without real time passing.
+type miningClock struct {
+	fakeClock
+	node *reorgNode
+}
+
+func (c *miningClock) After(d time.Duration) <-chan time.Time {
+	c.node.mu.Lock()
+	c.node.head++
+	c.node.mu.Unlock()
+	return c.fakeClock.After(d)
+}
+
+func TestClient_SupportsSubscriptions(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"http://127.0.0.1:1", false},
+		{"https://127.0.0.1:1", false},
+		{"ws://127.0.0.1:1", true},
+		{"WSS://127.0.0.1:1", true},
+		{filepath.Join(t.TempDir(), "geth.ipc"), true},
+	}
+	for _, tt := range tests {
+		client, err := evm.NewClient(context.Background(), tt.url, &observe.NoopLogger{}, nil, time.Second,
+			evm.WithLazyConnect())
+		require.NoError(t, err, tt.url)
+		assert.Equal(t, tt.want, client.SupportsSubscriptions(), tt.url)
+		client.Close()
+	}
+}
+
+func TestClient_WaitForReceipt_Subscription(t *testing.T) {
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	ec := reflect.ValueOf(sim.Client()).Field(0).Interface().(*ethclient.Client)
+	client := evm.NewClientFromEthClient(ec, &observe.NoopLogger{}, nil)
+	gateway := evm.NewEVMGatewayFromClient(client, &observe.NoopLogger{}, wallet)
+	require.True(t, client.SupportsSubscriptions(), "in-process transport")
+	client.SetClock(stalledClock{}) // only new heads can wake the waiter
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	hash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Value: big.NewInt(1)})
+	require.NoError(t, err)
+
+	type waitResult struct {
+		result *evm.ReceiptResult
+		err    error
+	}
+	done := make(chan waitResult, 1)
+	go func() {
+		result, err := client.WaitForReceipt(ctx, common.HexToHash(hash), 2)
+		done <- waitResult{result, err}
+	}()
+
+	for {
+		select {
+		case got := <-done:
+			require.NoError(t, got.err)
+			assert.Equal(t, uint64(2), got.result.Confirmations)
+			assert.Equal(t, types.ReceiptStatusSuccessful, got.result.Receipt.Status)
+			return
+		case <-ctx.Done():
+			t.Fatal("WaitForReceipt did not react to new heads")
+		case <-time.After(20 * time.Millisecond):
+			sim.Commit()
+		}
+	}
+}
+
+func TestClient_WaitForReceipt_PollsOverHTTP(t *testing.T) {
+	txHash := common.HexToHash("0xabc")
+	node := &reorgNode{txHash: txHash, head: 10, checksA: 100}
+	client := newReorgClient(t, node)
+	require.False(t, client.SupportsSubscriptions())
+	clock := &miningClock{node: node}
+	client.SetClock(clock)
+
+	result, err := client.WaitForReceipt(context.Background(), txHash, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), result.Confirmations)
+
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	require.NotEmpty(t, clock.waits)
+	for _, d := range clock.waits {
+		assert.Equal(t, time.Second, d)
+	}
+}
+
+// EOF: internal/blockchain/evm/transport_test.go
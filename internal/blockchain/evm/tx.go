@@ -6,7 +6,9 @@ package evm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum"
@@ -32,13 +34,18 @@ func NewTxBuilder(ctx context.Context, client *Client, wallet blockchain.Wallet)
 	if err != nil {
 		return nil, fmt.Errorf("txbuilder: get chain ID: %w", err)
 	}
+	return newTxBuilder(client, wallet, chainID), nil
+}
+
+// newTxBuilder creates a builder for an already known chain ID.
+func newTxBuilder(client *Client, wallet blockchain.Wallet, chainID *big.Int) *TxBuilder {
 	address := common.HexToAddress(wallet.Address())
 	return &TxBuilder{
 		client:  client,
 		wallet:  wallet,
 		chainID: chainID,
 		address: address,
-	}, nil
+	}
 }
 
 // BuildTransfer constructs and signs a native currency transfer transaction.
@@ -46,10 +53,10 @@ func NewTxBuilder(ctx context.Context, client *Client, wallet blockchain.Wallet)
 // If gasLimit is 0, it is estimated.
 // If nonce is nil, the next pending nonce is fetched.
 func (b *TxBuilder) BuildTransfer(ctx context.Context, to string, value *big.Int, opts *TxOpts) (*types.Transaction, error) {
-	if !common.IsHexAddress(to) {
-		return nil, fmt.Errorf("txbuilder: invalid to address: %s", to)
+	toAddr, err := parseAddress(to)
+	if err != nil {
+		return nil, fmt.Errorf("txbuilder: to address: %w", err)
 	}
-	toAddr := common.HexToAddress(to)
 
 	nonce, err := b.resolveNonce(ctx, opts)
 	if err != nil {
@@ -65,10 +72,10 @@ func (b *TxBuilder) BuildTransfer(ctx context.Context, to string, value *big.Int
 
 // BuildContractCall constructs and signs a contract call transaction.
 func (b *TxBuilder) BuildContractCall(ctx context.Context, to string, data []byte, value *big.Int, opts *TxOpts) (*types.Transaction, error) {
-	if !common.IsHexAddress(to) {
-		return nil, fmt.Errorf("txbuilder: invalid contract address: %s", to)
+	toAddr, err := parseAddress(to)
+	if err != nil {
+		return nil, fmt.Errorf("txbuilder: contract address: %w", err)
 	}
-	toAddr := common.HexToAddress(to)
 
 	nonce, err := b.resolveNonce(ctx, opts)
 	if err != nil {
@@ -109,6 +116,58 @@ type TxOpts struct {
 	Nonce *uint64
 	// DynamicFee forces EIP‑1559 transaction (if supported).
 	DynamicFee bool
+	// AccessList pre‑declares touched accounts and slots (EIP‑2930).
+	// Legacy‑priced transactions with a list are sent as AccessListTx.
+	AccessList types.AccessList
+	// BlobFeeCap for blob transactions (nil = twice the blob base fee).
+	BlobFeeCap *big.Int
+	// FeeSpeed selects the fee estimate when GasFeeCap and GasTipCap are
+	// both nil ("" = standard; see FeeEstimator).
+	FeeSpeed FeeSpeed
+	// GasLimitMultiplier pads an estimated gas limit (0 = client default,
+	// see WithGasLimitMultiplier). An explicit GasLimit is never padded.
+	GasLimitMultiplier float64
+	// MaxGasLimit caps padding of an estimated gas limit (0 = client default).
+	MaxGasLimit uint64
+	// Simulate runs the transaction as an eth_call before signing and fails
+	// with a *RevertError (ErrWouldRevert) if it would revert. WithSimulation
+	// enables it for every transaction.
+	Simulate bool
+}
+
+// DefaultGasLimitMultiplier pads estimated gas limits so that small state
+// changes between estimation and inclusion do not run out of gas.
+const DefaultGasLimitMultiplier = 1.2
+
+// padGasLimit applies the gas limit multiplier to an estimate. The padded
+// limit is capped at MaxGasLimit, but never below the estimate itself.
+func (b *TxBuilder) padGasLimit(estimate uint64, opts *TxOpts) (uint64, error) {
+	multiplier, maxGas := b.client.gasMultiplier, b.client.maxGasLimit
+	if opts != nil {
+		if opts.GasLimitMultiplier != 0 {
+			multiplier = opts.GasLimitMultiplier
+		}
+		if opts.MaxGasLimit != 0 {
+			maxGas = opts.MaxGasLimit
+		}
+	}
+	if multiplier < 1 || math.IsNaN(multiplier) || math.IsInf(multiplier, 0) {
+		return 0, fmt.Errorf("txbuilder: invalid gas limit multiplier %v (must be at least 1)", multiplier)
+	}
+
+	padded := uint64(math.MaxUint64)
+	if f := math.Round(float64(estimate) * multiplier); f < math.MaxUint64 {
+		padded = uint64(f)
+	}
+	if maxGas != 0 && padded > maxGas {
+		padded = max(maxGas, estimate)
+	}
+	b.client.logger.Debug("gas limit padded", map[string]interface{}{
+		"estimate":   estimate,
+		"padded":     padded,
+		"multiplier": multiplier,
+	})
+	return padded, nil
 }
 
 // resolveNonce gets the nonce from opts or fetches the pending nonce.
@@ -127,26 +186,32 @@ func (b *TxBuilder) resolveNonce(ctx context.Context, opts *TxOpts) (uint64, err
 func (b *TxBuilder) buildAndSignLegacy(ctx context.Context, to *common.Address, value *big.Int, data []byte, opts *TxOpts, nonce uint64) (*types.Transaction, error) {
 	var gasPrice *big.Int
 	var gasLimit uint64
+	var accessList types.AccessList
 
 	if opts != nil {
 		gasPrice = opts.GasPrice
 		gasLimit = opts.GasLimit
+		accessList = opts.AccessList
 	}
 
 	// Estimate gas if not provided.
 	if gasLimit == 0 {
 		callMsg := ethereum.CallMsg{
-			From:     b.address,
-			To:       to,
-			Value:    value,
-			Data:     data,
-			GasPrice: gasPrice,
+			From:       b.address,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			GasPrice:   gasPrice,
+			AccessList: accessList,
 		}
 		est, err := b.client.EstimateGas(ctx, callMsg)
 		if err != nil {
-			return nil, fmt.Errorf("txbuilder: estimate gas: %w", err)
+			return nil, b.estimateError(err, opts)
+		}
+		gasLimit, err = b.padGasLimit(est, opts)
+		if err != nil {
+			return nil, err
 		}
-		gasLimit = est
 	}
 
 	// Suggest gas price if not provided.
@@ -158,56 +223,95 @@ func (b *TxBuilder) buildAndSignLegacy(ctx context.Context, to *common.Address,
 		gasPrice = price
 	}
 
-	// Build unsigned transaction.
-	unsignedTx := types.NewTx(&types.LegacyTx{
-		Nonce:    nonce,
-		To:       to,
-		Value:    value,
-		Gas:      gasLimit,
-		GasPrice: gasPrice,
-		Data:     data,
-	})
+	// Build unsigned transaction; an access list requires the typed envelope.
+	var unsignedTx *types.Transaction
+	if len(accessList) > 0 {
+		unsignedTx = types.NewTx(&types.AccessListTx{
+			ChainID:    b.chainID,
+			Nonce:      nonce,
+			To:         to,
+			Value:      value,
+			Gas:        gasLimit,
+			GasPrice:   gasPrice,
+			Data:       data,
+			AccessList: accessList,
+		})
+	} else {
+		unsignedTx = types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			To:       to,
+			Value:    value,
+			Gas:      gasLimit,
+			GasPrice: gasPrice,
+			Data:     data,
+		})
+	}
 
 	// Sign.
-	return b.signTransaction(unsignedTx)
+	return b.simulateAndSign(ctx, unsignedTx, opts)
 }
 
 // buildAndSignDynamicFee constructs and signs an EIP‑1559 transaction.
 func (b *TxBuilder) buildAndSignDynamicFee(ctx context.Context, to *common.Address, value *big.Int, data []byte, opts *TxOpts, nonce uint64) (*types.Transaction, error) {
 	var gasFeeCap, gasTipCap *big.Int
 	var gasLimit uint64
+	var accessList types.AccessList
+	var speed FeeSpeed
 
 	if opts != nil {
 		gasFeeCap = opts.GasFeeCap
 		gasTipCap = opts.GasTipCap
 		gasLimit = opts.GasLimit
+		accessList = opts.AccessList
+		speed = opts.FeeSpeed
 	}
 
-	// Get header for base fee.
-	header, err := b.client.ec.HeaderByNumber(ctx, nil)
-	if err != nil {
-		return nil, fmt.Errorf("txbuilder: get header for base fee: %w", err)
+	// Get header for base fee. An explicit fee cap needs no base fee, which
+	// lets fully specified transactions be built without a node.
+	var header *types.Header
+	if gasFeeCap == nil {
+		var err error
+		header, err = b.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("txbuilder: get header for base fee: %w", err)
+		}
+		if header.BaseFee == nil {
+			// Chain does not support EIP‑1559; fall back to legacy.
+			return b.buildAndSignLegacy(ctx, to, value, data, opts, nonce)
+		}
 	}
-	if header.BaseFee == nil {
-		// Chain does not support EIP‑1559; fall back to legacy.
-		return b.buildAndSignLegacy(ctx, to, value, data, opts, nonce)
+
+	// Estimate both fees from fee history when neither is given. Nodes
+	// without eth_feeHistory fall back to the suggestions below.
+	if gasFeeCap == nil && gasTipCap == nil {
+		tip, feeCap, err := b.client.EstimateFees(ctx, speed)
+		switch {
+		case err == nil:
+			gasTipCap, gasFeeCap = tip, feeCap
+		case !errors.Is(err, ErrNotSupported):
+			return nil, fmt.Errorf("txbuilder: %w", err)
+		}
 	}
 
 	// Estimate gas if not provided.
 	if gasLimit == 0 {
 		callMsg := ethereum.CallMsg{
-			From:      b.address,
-			To:        to,
-			Value:     value,
-			Data:      data,
-			GasFeeCap: gasFeeCap,
-			GasTipCap: gasTipCap,
+			From:       b.address,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			GasFeeCap:  gasFeeCap,
+			GasTipCap:  gasTipCap,
+			AccessList: accessList,
 		}
 		est, err := b.client.EstimateGas(ctx, callMsg)
 		if err != nil {
-			return nil, fmt.Errorf("txbuilder: estimate gas: %w", err)
+			return nil, b.estimateError(err, opts)
+		}
+		gasLimit, err = b.padGasLimit(est, opts)
+		if err != nil {
+			return nil, err
 		}
-		gasLimit = est
 	}
 
 	// Suggest tip if not provided.
@@ -228,32 +332,64 @@ func (b *TxBuilder) buildAndSignDynamicFee(ctx context.Context, to *common.Addre
 
 	// Build unsigned transaction.
 	unsignedTx := types.NewTx(&types.DynamicFeeTx{
-		Nonce:     nonce,
-		To:        to,
-		Value:     value,
-		Gas:       gasLimit,
-		GasFeeCap: gasFeeCap,
-		GasTipCap: gasTipCap,
-		Data:      data,
+		Nonce:      nonce,
+		To:         to,
+		Value:      value,
+		Gas:        gasLimit,
+		GasFeeCap:  gasFeeCap,
+		GasTipCap:  gasTipCap,
+		Data:       data,
+		AccessList: accessList,
 	})
 
 	// Sign.
-	return b.signTransaction(unsignedTx)
+	return b.simulateAndSign(ctx, unsignedTx, opts)
 }
 
-// signTransaction signs an unsigned transaction using the wallet.
-func (b *TxBuilder) signTransaction(unsignedTx *types.Transaction) (*types.Transaction, error) {
+// replacementBumpPercent is the minimum fee increase (in percent) most
+// node mempools require before accepting a transaction that reuses a nonce.
+const replacementBumpPercent = 10
+
+// bumpFee returns the fee raised by replacementBumpPercent (plus one wei to
+// clear integer rounding), or suggested if that is higher.
+func bumpFee(original, suggested *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(original, big.NewInt(100+replacementBumpPercent))
+	bumped.Div(bumped, big.NewInt(100))
+	bumped.Add(bumped, big.NewInt(1))
+	if suggested != nil && suggested.Cmp(bumped) > 0 {
+		return new(big.Int).Set(suggested)
+	}
+	return bumped
+}
+
+// TxSigner is implemented by wallets that sign whole transactions instead
+// of digests, such as hardware wallets, MPC providers and
+// RemoteSignerWallet. TxBuilder prefers SignTx to Sign when the wallet
+// offers it, and checks that the result is the requested transaction
+// signed by the wallet's address. Keystore implements both.
+type TxSigner interface {
+	blockchain.Wallet
+
+	// SignTx signs tx for chainID and returns the signed transaction.
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// signTransaction signs an unsigned transaction using the wallet: through
+// SignTx if the wallet is a TxSigner, otherwise by signing its hash.
+func (b *TxBuilder) signTransaction(ctx context.Context, unsignedTx *types.Transaction) (*types.Transaction, error) {
 	signer := types.LatestSignerForChainID(b.chainID)
-	hash := signer.Hash(unsignedTx)
+	if txSigner, ok := b.wallet.(TxSigner); ok {
+		return b.signWithTxSigner(ctx, txSigner, signer, unsignedTx)
+	}
 
+	hash := signer.Hash(unsignedTx)
 	signature, err := b.wallet.Sign(hash.Bytes())
 	if err != nil {
 		return nil, fmt.Errorf("txbuilder: sign: %w", err)
 	}
 
-	// Adjust V for chain ID (EIP‑155). The signature from crypto.Sign is [R || S || V] with V = 27/28.
-	// signer.SignatureValues expects V = 0/1.
-	// We need to normalize: if signature[64] >= 27, subtract 27.
+	// Wallets return V as 0/1 or 27/28 (see blockchain.Wallet); the signer
+	// expects 0/1 and derives the EIP‑155 V from the chain ID itself.
 	if len(signature) != 65 {
 		return nil, fmt.Errorf("txbuilder: invalid signature length: %d", len(signature))
 	}
@@ -270,4 +406,24 @@ func (b *TxBuilder) signTransaction(unsignedTx *types.Transaction) (*types.Trans
 	return signedTx, nil
 }
 
+// signWithTxSigner signs unsignedTx with a TxSigner and checks that the
+// result is the same transaction, signed by the builder's account.
+func (b *TxBuilder) signWithTxSigner(ctx context.Context, txSigner TxSigner, signer types.Signer, unsignedTx *types.Transaction) (*types.Transaction, error) {
+	signedTx, err := txSigner.SignTx(ctx, unsignedTx, b.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("txbuilder: sign: %w", err)
+	}
+	if signer.Hash(signedTx) != signer.Hash(unsignedTx) {
+		return nil, errors.New("txbuilder: signer returned a different transaction")
+	}
+	sender, err := types.Sender(signer, signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("txbuilder: signed transaction: %w", err)
+	}
+	if sender != b.address {
+		return nil, fmt.Errorf("txbuilder: signer signed as %s, want %s", sender.Hex(), b.address.Hex())
+	}
+	return signedTx, nil
+}
+
 // EOF: internal/blockchain/evm/tx.go
\ No newline at end of file
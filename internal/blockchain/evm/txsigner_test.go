@@ -0,0 +1,102 @@
+// Package evm_test tests that transactions signed through SignTx and
+// through the digest path are the same.
+//
+// File: internal/blockchain/evm/txsigner_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// digestWallet hides every method but Sign and Address of a wallet, so the
+// digest path is used. With v27 it returns V as 27/28.
+type digestWallet struct {
+	wallet blockchain.Wallet
+	v27    bool
+}
+
+func (w digestWallet) Sign(digest []byte) ([]byte, error) {
+	sig, err := w.wallet.Sign(digest)
+	if err == nil && w.v27 {
+		sig[64] += 27
+	}
+	return sig, err
+}
+
+func (w digestWallet) Address() string { return w.wallet.Address() }
+
+func TestTxBuilder_SignPathsAgree(t *testing.T) {
+	ks, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	var _ evm.TxSigner = ks
+
+	sim := simulated.NewBackend(types.GenesisAlloc{common.HexToAddress(ks.Address()): {Balance: big.NewInt(1e18)}})
+	defer sim.Close()
+	ctx := context.Background()
+	chainID, err := sim.Client().ChainID(ctx)
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(chainID)
+
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	nonce := uint64(3)
+	txs := map[string]*blockchain.Transaction{
+		"legacy": {To: &to, Value: big.NewInt(1000), Gas: 21000, GasPrice: big.NewInt(2e9), Nonce: &nonce},
+		"dynamic fee": {To: &to, Value: big.NewInt(1000), Gas: 21000, GasFeeCap: big.NewInt(3e9),
+			GasTipCap: big.NewInt(1e9), Nonce: &nonce},
+	}
+	wallets := map[string]blockchain.Wallet{
+		"SignTx":         ks,
+		"digest V 0/1":   digestWallet{wallet: ks},
+		"digest V 27/28": digestWallet{wallet: ks, v27: true},
+	}
+	for txName, tx := range txs {
+		raws := map[string]string{}
+		for walletName, wallet := range wallets {
+			raw, _, err := newSimulatedGateway(t, sim, wallet).SignTransaction(ctx, tx)
+			require.NoError(t, err, "%s, %s", txName, walletName)
+
+			decoded := new(types.Transaction)
+			require.NoError(t, decoded.UnmarshalBinary(hexutil.MustDecode(raw)))
+			sender, err := types.Sender(signer, decoded)
+			require.NoError(t, err)
+			assert.Equal(t, ks.Address(), sender.Hex(), "%s, %s", txName, walletName)
+			raws[walletName] = raw
+		}
+		// ECDSA signatures are deterministic (RFC 6979), so the paths must
+		// produce byte‑identical transactions.
+		assert.Equal(t, raws["SignTx"], raws["digest V 0/1"], txName)
+		assert.Equal(t, raws["SignTx"], raws["digest V 27/28"], txName)
+	}
+}
+
+func TestKeystore_SignTx(t *testing.T) {
+	ks, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	to := common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
+	chainID := big.NewInt(10)
+
+	signed, err := ks.SignTx(context.Background(), types.NewTx(&types.DynamicFeeTx{
+		Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1),
+	}), chainID)
+	require.NoError(t, err)
+	assert.Equal(t, chainID, signed.ChainId())
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, ks.Address(), sender.Hex())
+}
+
+// EOF: internal/blockchain/evm/txsigner_test.go
@@ -0,0 +1,376 @@
+// Package evm provides a wallet implementing blockchain.Wallet whose key is
+// kept in HashiCorp Vault.
+//
+// File: internal/blockchain/evm/vault.go
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+)
+
+// VaultMode selects how a VaultWallet uses Vault.
+type VaultMode string
+
+const (
+	// VaultModeKV reads a hex private key from a KV secret once and keeps
+	// it in memory only.
+	VaultModeKV VaultMode = "kv"
+	// VaultModePlugin signs every digest through a Vault plugin endpoint;
+	// the key never leaves Vault.
+	VaultModePlugin VaultMode = "plugin"
+)
+
+// vaultKeyField is the field of a KV secret holding the private key.
+const vaultKeyField = "private_key"
+
+// DefaultVaultTimeout bounds each Vault request of a VaultWallet.
+const DefaultVaultTimeout = 10 * time.Second
+
+// vaultRenewRetry is the pause between failed token renewals while the
+// token is still valid.
+const vaultRenewRetry = 5 * time.Second
+
+// VaultWallet implements blockchain.Wallet with a secp256k1 key kept in
+// HashiCorp Vault, either read from a KV secret (VaultModeKV) or used
+// through a signing plugin (VaultModePlugin). The Vault token is renewed in
+// the background; if it expires before a renewal succeeds, the wallet
+// locks: the key is wiped and Sign returns blockchain.ErrWalletLocked.
+// A VaultWallet is safe for concurrent use and must be closed.
+type VaultWallet struct {
+	httpClient *http.Client
+	addr       string
+	path       string
+	token      string
+	mode       VaultMode
+	timeout    time.Duration
+	address    common.Address
+
+	mu      sync.RWMutex
+	key     *ecdsa.PrivateKey // VaultModeKV only; nil once locked
+	lockErr error             // why the wallet locked; nil while unlocked
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// VaultOption configures a VaultWallet.
+type VaultOption func(*VaultWallet)
+
+// WithVaultHTTPClient sets the client for Vault requests (default
+// http.DefaultClient).
+func WithVaultHTTPClient(client *http.Client) VaultOption {
+	return func(w *VaultWallet) {
+		w.httpClient = client
+	}
+}
+
+// WithVaultTimeout bounds each Vault request (0 = DefaultVaultTimeout).
+func WithVaultTimeout(timeout time.Duration) VaultOption {
+	return func(w *VaultWallet) {
+		if timeout > 0 {
+			w.timeout = timeout
+		}
+	}
+}
+
+// NewVaultWallet creates a wallet for the Vault server addr using token.
+// path is the API path below /v1/: the KV secret holding the key in field
+// "private_key" (for KV version 2 including "data/", e.g.
+// "secret/data/agent"), or the plugin account whose GET returns its
+// "address" and whose "<path>/sign" endpoint signs a "hash". Token renewal
+// starts if the token is renewable.
+func NewVaultWallet(ctx context.Context, addr, path, token string, mode VaultMode, opts ...VaultOption) (*VaultWallet, error) {
+	if addr == "" || path == "" {
+		return nil, errors.New("vault wallet: address and path are required")
+	}
+	if token == "" {
+		return nil, errors.New("vault wallet: empty token")
+	}
+	if mode == "" {
+		mode = VaultModeKV
+	}
+	w := &VaultWallet{
+		httpClient: http.DefaultClient,
+		addr:       strings.TrimRight(addr, "/"),
+		path:       strings.Trim(path, "/"),
+		token:      token,
+		mode:       mode,
+		timeout:    DefaultVaultTimeout,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	switch mode {
+	case VaultModeKV:
+		if err := w.loadKey(ctx); err != nil {
+			return nil, err
+		}
+	case VaultModePlugin:
+		if err := w.loadAddress(ctx); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("vault wallet: unknown mode %q (want %q or %q)", mode, VaultModeKV, VaultModePlugin)
+	}
+
+	var lookup struct {
+		Data struct {
+			TTL       int64 `json:"ttl"`
+			Renewable bool  `json:"renewable"`
+		} `json:"data"`
+	}
+	if err := w.call(ctx, http.MethodGet, "auth/token/lookup-self", nil, &lookup); err != nil {
+		w.wipe()
+		return nil, fmt.Errorf("vault wallet: look up token: %w", err)
+	}
+	if lookup.Data.Renewable && lookup.Data.TTL > 0 {
+		go w.renew(time.Duration(lookup.Data.TTL) * time.Second)
+	} else {
+		close(w.done)
+	}
+	return w, nil
+}
+
+// loadKey reads the private key from the KV secret at w.path.
+func (w *VaultWallet) loadKey(ctx context.Context) error {
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := w.call(ctx, http.MethodGet, w.path, nil, &secret); err != nil {
+		return fmt.Errorf("vault wallet: read %s: %w", w.path, err)
+	}
+	fields := secret.Data
+	if nested, ok := fields["data"].(map[string]interface{}); ok { // KV version 2
+		fields = nested
+	}
+	hexKey, _ := fields[vaultKeyField].(string)
+	if hexKey == "" {
+		return fmt.Errorf("vault wallet: secret %s has no %q field", w.path, vaultKeyField)
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		// The error may quote the key, so it is not wrapped.
+		return fmt.Errorf("vault wallet: secret %s: invalid private key", w.path)
+	}
+	w.key = key
+	w.address = crypto.PubkeyToAddress(key.PublicKey)
+	return nil
+}
+
+// loadAddress reads the account address of the plugin endpoint at w.path.
+func (w *VaultWallet) loadAddress(ctx context.Context) error {
+	var account struct {
+		Data struct {
+			Address string `json:"address"`
+		} `json:"data"`
+	}
+	if err := w.call(ctx, http.MethodGet, w.path, nil, &account); err != nil {
+		return fmt.Errorf("vault wallet: read %s: %w", w.path, err)
+	}
+	if !common.IsHexAddress(account.Data.Address) {
+		return fmt.Errorf("vault wallet: %s returned invalid address %q", w.path, account.Data.Address)
+	}
+	w.address = common.HexToAddress(account.Data.Address)
+	return nil
+}
+
+// Sign implements blockchain.Wallet.
+// It returns the signature as [R || S || V] with V in {0,1}, or an error
+// wrapping blockchain.ErrWalletLocked once the wallet has locked.
+func (w *VaultWallet) Sign(digest []byte) ([]byte, error) {
+	if len(digest) != 32 {
+		return nil, fmt.Errorf("vault wallet: digest is %d bytes, want 32", len(digest))
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.lockErr != nil {
+		return nil, fmt.Errorf("vault wallet: %w: %v", blockchain.ErrWalletLocked, w.lockErr)
+	}
+	if w.mode == VaultModeKV {
+		return crypto.Sign(digest, w.key)
+	}
+
+	var out struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	in := map[string]string{"hash": hexutil.Encode(digest)}
+	if err := w.call(context.Background(), http.MethodPost, w.path+"/sign", in, &out); err != nil {
+		return nil, fmt.Errorf("vault wallet: sign: %w", err)
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(out.Data.Signature, "0x"))
+	if err != nil || len(sig) != 65 {
+		return nil, errors.New("vault wallet: sign: invalid signature")
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest, sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != w.address {
+		return nil, errors.New("vault wallet: sign: signature does not recover to the account address")
+	}
+	return sig, nil
+}
+
+// Address implements blockchain.Wallet.
+func (w *VaultWallet) Address() string {
+	return w.address.Hex()
+}
+
+// Locked reports why the wallet locked, or nil while it can sign.
+func (w *VaultWallet) Locked() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.lockErr
+}
+
+// Close stops token renewal and wipes the key; the wallet is locked
+// afterwards. Calls after the first do nothing.
+func (w *VaultWallet) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		<-w.done
+		w.lock(errors.New("wallet closed"))
+	})
+	return nil
+}
+
+// renew keeps the token alive, renewing it halfway through each lease.
+// A failed renewal is retried until the token expires; then the wallet
+// locks.
+func (w *VaultWallet) renew(ttl time.Duration) {
+	defer close(w.done)
+	expiry := time.Now().Add(ttl)
+	next := ttl / 2
+	for {
+		timer := time.NewTimer(next)
+		select {
+		case <-w.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		var out struct {
+			Auth struct {
+				LeaseDuration int64 `json:"lease_duration"`
+				Renewable     bool  `json:"renewable"`
+			} `json:"auth"`
+		}
+		err := w.call(context.Background(), http.MethodPost, "auth/token/renew-self", map[string]string{}, &out)
+		switch {
+		case err == nil && out.Auth.LeaseDuration > 0:
+			ttl = time.Duration(out.Auth.LeaseDuration) * time.Second
+			expiry = time.Now().Add(ttl)
+			if !out.Auth.Renewable {
+				return // valid until expiry, but cannot be renewed again
+			}
+			next = ttl / 2
+		case time.Until(expiry) <= 0:
+			if err == nil {
+				err = errors.New("token not renewed")
+			}
+			w.lock(fmt.Errorf("token renewal failed: %w", err))
+			return
+		default:
+			next = min(vaultRenewRetry, time.Until(expiry))
+		}
+	}
+}
+
+// lock wipes the key and makes Sign fail with cause.
+func (w *VaultWallet) lock(cause error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.lockErr == nil {
+		w.lockErr = cause
+	}
+	w.wipeLocked()
+}
+
+// wipe clears the key of a wallet that failed to construct.
+func (w *VaultWallet) wipe() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.wipeLocked()
+}
+
+// wipeLocked clears the key; w.mu must be held.
+func (w *VaultWallet) wipeLocked() {
+	if w.key != nil {
+		w.key.D.SetInt64(0)
+		w.key = nil
+	}
+}
+
+// call sends a request with the Vault token to /v1/path and decodes the
+// JSON response into out.
+func (w *VaultWallet) call(ctx context.Context, method, path string, in, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, w.addr+"/v1/"+path, body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", w.token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Errors []string `json:"errors"`
+		}
+		msg := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &apiErr) == nil && len(apiErr.Errors) > 0 {
+			msg = strings.Join(apiErr.Errors, "; ")
+		}
+		return fmt.Errorf("http %d: %s", resp.StatusCode, msg)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// EOF: internal/blockchain/evm/vault.go
@@ -0,0 +1,83 @@
+//go:build vault
+
+// Package evm_test tests the Vault wallet against a dev-mode Vault server:
+//
+//	docker run --rm -p 8200:8200 -e VAULT_DEV_ROOT_TOKEN_ID=root hashicorp/vault
+//	VAULT_ADDR=http://127.0.0.1:8200 VAULT_TOKEN=root go test -tags vault -run Vault ./internal/blockchain/evm
+//
+// File: internal/blockchain/evm/vault_integration_test.go
+
+package evm_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// vaultRequest sends a request with the root token and decodes the reply
+// into out, if given.
+func vaultRequest(t *testing.T, method, path string, in, out interface{}) {
+	t.Helper()
+	body, err := json.Marshal(in)
+	require.NoError(t, err)
+	req, err := http.NewRequest(method, os.Getenv("VAULT_ADDR")+"/v1/"+path, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Vault-Token", os.Getenv("VAULT_TOKEN"))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Less(t, resp.StatusCode, 300, "%s %s", method, path)
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+}
+
+func TestVaultWallet_DevServer(t *testing.T) {
+	if os.Getenv("VAULT_ADDR") == "" || os.Getenv("VAULT_TOKEN") == "" {
+		t.Skip("VAULT_ADDR and VAULT_TOKEN must point at a dev-mode Vault")
+	}
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	vaultRequest(t, http.MethodPost, "secret/data/lola-test", map[string]interface{}{
+		"data": map[string]string{"private_key": hexutil.Encode(crypto.FromECDSA(key))},
+	}, nil)
+
+	// A renewable token limited to reading the secret, as in production.
+	vaultRequest(t, http.MethodPut, "sys/policies/acl/lola-test", map[string]string{
+		"policy": `path "secret/data/lola-test" { capabilities = ["read"] }`,
+	}, nil)
+	var created struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	vaultRequest(t, http.MethodPost, "auth/token/create", map[string]interface{}{
+		"policies": []string{"lola-test"}, "ttl": "2s", "renewable": true,
+	}, &created)
+
+	wallet, err := evm.NewVaultWallet(context.Background(), os.Getenv("VAULT_ADDR"), "secret/data/lola-test",
+		created.Auth.ClientToken, evm.VaultModeKV)
+	require.NoError(t, err)
+	t.Cleanup(func() { wallet.Close() })
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey).Hex(), wallet.Address())
+
+	digest := crypto.Keccak256([]byte("hello"))
+	want, err := crypto.Sign(digest, key)
+	require.NoError(t, err)
+	sig, err := wallet.Sign(digest)
+	require.NoError(t, err)
+	assert.Equal(t, want, sig)
+}
+
+// EOF: internal/blockchain/evm/vault_integration_test.go
@@ -0,0 +1,184 @@
+// Package evm_test tests the Vault wallet against a fake Vault server.
+//
+// File: internal/blockchain/evm/vault_test.go
+
+package evm_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+const testVaultToken = "s.test"
+
+// fakeVault serves a KV version 2 secret at secret/data/agent, a signing
+// plugin account at ethereum/accounts/agent and the token endpoints.
+type fakeVault struct {
+	key *ecdsa.PrivateKey
+
+	mu        sync.Mutex
+	ttl       int64 // token TTL in seconds; 0 = not renewable
+	renewFail bool
+	renewals  int
+}
+
+func newFakeVault(t *testing.T, ttl int64) (*fakeVault, *httptest.Server) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	f := &fakeVault{key: key, ttl: ttl}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != testVaultToken {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	reply := func(v interface{}) { _ = json.NewEncoder(w).Encode(v) }
+	switch r.URL.Path {
+	case "/v1/secret/data/agent":
+		reply(map[string]interface{}{"data": map[string]interface{}{
+			"data": map[string]string{"private_key": hexutil.Encode(crypto.FromECDSA(f.key))},
+		}})
+	case "/v1/ethereum/accounts/agent":
+		reply(map[string]interface{}{"data": map[string]string{
+			"address": crypto.PubkeyToAddress(f.key.PublicKey).Hex(),
+		}})
+	case "/v1/ethereum/accounts/agent/sign":
+		var in struct {
+			Hash string `json:"hash"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		sig, err := crypto.Sign(hexutil.MustDecode(in.Hash), f.key)
+		if err != nil {
+			http.Error(w, `{"errors":["bad hash"]}`, http.StatusBadRequest)
+			return
+		}
+		sig[64] += 27
+		reply(map[string]interface{}{"data": map[string]string{"signature": hexutil.Encode(sig)}})
+	case "/v1/auth/token/lookup-self":
+		reply(map[string]interface{}{"data": map[string]interface{}{"ttl": f.ttl, "renewable": f.ttl > 0}})
+	case "/v1/auth/token/renew-self":
+		f.renewals++
+		if f.renewFail {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		reply(map[string]interface{}{"auth": map[string]interface{}{"lease_duration": f.ttl, "renewable": true}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestVaultWallet_KV(t *testing.T) {
+	fake, srv := newFakeVault(t, 0)
+	wallet, err := evm.NewVaultWallet(context.Background(), srv.URL, "secret/data/agent", testVaultToken, evm.VaultModeKV)
+	require.NoError(t, err)
+	t.Cleanup(func() { wallet.Close() })
+	assert.Equal(t, crypto.PubkeyToAddress(fake.key.PublicKey).Hex(), wallet.Address())
+
+	digest := crypto.Keccak256([]byte("hello"))
+	want, err := crypto.Sign(digest, fake.key)
+	require.NoError(t, err)
+	sig, err := wallet.Sign(digest)
+	require.NoError(t, err)
+	assert.Equal(t, want, sig)
+	assert.NoError(t, wallet.Locked())
+}
+
+func TestVaultWallet_Plugin(t *testing.T) {
+	fake, srv := newFakeVault(t, 0)
+	wallet, err := evm.NewVaultWallet(context.Background(), srv.URL, "ethereum/accounts/agent", testVaultToken, evm.VaultModePlugin)
+	require.NoError(t, err)
+	t.Cleanup(func() { wallet.Close() })
+	assert.Equal(t, crypto.PubkeyToAddress(fake.key.PublicKey).Hex(), wallet.Address())
+
+	digest := crypto.Keccak256([]byte("hello"))
+	want, err := crypto.Sign(digest, fake.key)
+	require.NoError(t, err)
+	sig, err := wallet.Sign(digest)
+	require.NoError(t, err)
+	assert.Equal(t, want, sig, "V is normalized to 0/1")
+}
+
+func TestVaultWallet_Errors(t *testing.T) {
+	ctx := context.Background()
+	_, srv := newFakeVault(t, 0)
+
+	_, err := evm.NewVaultWallet(ctx, srv.URL, "secret/data/agent", "wrong", evm.VaultModeKV)
+	assert.ErrorContains(t, err, "permission denied")
+
+	_, err = evm.NewVaultWallet(ctx, srv.URL, "secret/data/missing", testVaultToken, evm.VaultModeKV)
+	assert.ErrorContains(t, err, "404")
+
+	_, err = evm.NewVaultWallet(ctx, srv.URL, "secret/data/agent", testVaultToken, "transit")
+	assert.ErrorContains(t, err, "unknown mode")
+
+	_, err = evm.NewVaultWallet(ctx, srv.URL, "secret/data/agent", "", evm.VaultModeKV)
+	assert.ErrorContains(t, err, "empty token")
+}
+
+func TestVaultWallet_RenewsToken(t *testing.T) {
+	fake, srv := newFakeVault(t, 1)
+	wallet, err := evm.NewVaultWallet(context.Background(), srv.URL, "secret/data/agent", testVaultToken, evm.VaultModeKV)
+	require.NoError(t, err)
+	t.Cleanup(func() { wallet.Close() })
+
+	assert.Eventually(t, func() bool {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return fake.renewals >= 2
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.NoError(t, wallet.Locked())
+	_, err = wallet.Sign(crypto.Keccak256([]byte("hello")))
+	assert.NoError(t, err)
+}
+
+func TestVaultWallet_LocksWhenRenewalFails(t *testing.T) {
+	fake, srv := newFakeVault(t, 1)
+	fake.renewFail = true
+	wallet, err := evm.NewVaultWallet(context.Background(), srv.URL, "secret/data/agent", testVaultToken, evm.VaultModeKV)
+	require.NoError(t, err)
+	t.Cleanup(func() { wallet.Close() })
+
+	digest := crypto.Keccak256([]byte("hello"))
+	_, err = wallet.Sign(digest)
+	require.NoError(t, err, "signs while the token is valid")
+
+	assert.Eventually(t, func() bool { return wallet.Locked() != nil }, 5*time.Second, 50*time.Millisecond)
+	_, err = wallet.Sign(digest)
+	assert.ErrorIs(t, err, blockchain.ErrWalletLocked)
+	assert.ErrorContains(t, wallet.Locked(), "permission denied")
+}
+
+func TestVaultWallet_Close(t *testing.T) {
+	_, srv := newFakeVault(t, 60)
+	wallet, err := evm.NewVaultWallet(context.Background(), srv.URL, "secret/data/agent", testVaultToken, evm.VaultModeKV)
+	require.NoError(t, err)
+
+	require.NoError(t, wallet.Close())
+	require.NoError(t, wallet.Close())
+	_, err = wallet.Sign(crypto.Keccak256([]byte("hello")))
+	assert.ErrorIs(t, err, blockchain.ErrWalletLocked)
+}
+
+// EOF: internal/blockchain/evm/vault_test.go
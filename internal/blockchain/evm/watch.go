@@ -0,0 +1,268 @@
+// Package evm streams decoded contract events as they are mined.
+//
+// File: internal/blockchain/evm/watch.go
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultWatchBuffer is the event channel capacity of WatchEvents unless
+// set with WithWatchBuffer.
+const DefaultWatchBuffer = 64
+
+// watchPollInterval is the WatchEvents polling interval when the block
+// time is unknown.
+const watchPollInterval = receiptPollInterval
+
+// SubscribeLogs subscribes to logs matching query. It needs a transport
+// that supports subscriptions (see SupportsSubscriptions) and fails with
+// ErrNotSupported otherwise. The subscription outlives ctx, which only
+// bounds the subscribe call.
+func (c *Client) SubscribeLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	if !c.subscriptions {
+		return nil, fmt.Errorf("SubscribeLogs: %s: %w", c.rpcURL, ErrNotSupported)
+	}
+	if err := c.connect(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.limiter.wait(ctx, c.clock, 1); err != nil {
+		return nil, err
+	}
+	attemptCtx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	return c.eth().SubscribeFilterLogs(attemptCtx, query, ch)
+}
+
+// WatchEvents streams the eventName events the contract emits from now
+// on, decoded. Filters work as in FilterEvents. On WebSocket and IPC
+// transports it subscribes to logs and, when the subscription drops,
+// resubscribes and backfills from the last delivered block, so no event
+// is lost or repeated; on HTTP it polls every block time (WithBlockTime,
+// else every second). Logs removed by a reorg are not delivered.
+//
+// Both channels are closed once ctx is cancelled. Sends never block the
+// watcher: when the event channel (see WithWatchBuffer) is full the event
+// is dropped and counted in MetricEventsDropped, and errors are dropped
+// while one is pending. Errors are reported without ending the watch.
+func (c *BoundContract) WatchEvents(ctx context.Context, eventName string, filters ...interface{}) (<-chan DecodedEvent, <-chan error, error) {
+	event, err := lookupEvent(c.abi, eventName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if event.Anonymous {
+		return nil, nil, fmt.Errorf("event %q is anonymous and cannot be watched by name", eventName)
+	}
+	topics, err := eventTopics(event, filters)
+	if err != nil {
+		return nil, nil, err
+	}
+	client := c.gateway.client
+	buffer := client.watchBuffer
+	if buffer <= 0 {
+		buffer = DefaultWatchBuffer
+	}
+	w := &eventWatcher{
+		contract: c,
+		client:   client,
+		event:    eventName,
+		query:    ethereum.FilterQuery{Addresses: []common.Address{c.address}, Topics: topics},
+		events:   make(chan DecodedEvent, buffer),
+		errs:     make(chan error, 1),
+	}
+	go w.run(ctx)
+	return w.events, w.errs, nil
+}
+
+// eventWatcher delivers the logs of one WatchEvents call in order.
+type eventWatcher struct {
+	contract *BoundContract
+	client   *Client
+	event    string
+	query    ethereum.FilterQuery
+	events   chan DecodedEvent
+	errs     chan error
+
+	// The position of the last delivered log; started is false until the
+	// first one. next is the first block not yet searched while polling.
+	started   bool
+	lastBlock uint64
+	lastIndex uint
+	next      uint64
+}
+
+func (w *eventWatcher) run(ctx context.Context) {
+	defer close(w.errs)
+	defer close(w.events)
+	if w.client.SupportsSubscriptions() {
+		w.subscribe(ctx)
+	} else {
+		w.poll(ctx)
+	}
+}
+
+// subscribe streams logs from a subscription, resubscribing and
+// backfilling after failures.
+func (w *eventWatcher) subscribe(ctx context.Context) {
+	head, ok := w.head(ctx)
+	if !ok {
+		return
+	}
+	w.next = head + 1
+	for {
+		logs := make(chan types.Log, cap(w.events))
+		sub, err := w.client.SubscribeLogs(ctx, w.query, logs)
+		if err != nil {
+			w.fail(fmt.Errorf("watch %s: subscribe: %w", w.event, err))
+			if !w.sleep(ctx) {
+				return
+			}
+			continue
+		}
+		// Catch up on anything mined while unsubscribed. Logs the new
+		// subscription repeats are skipped by deliver.
+		if !w.backfill(ctx) {
+			sub.Unsubscribe()
+			return
+		}
+	stream:
+		for {
+			select {
+			case <-ctx.Done():
+				sub.Unsubscribe()
+				return
+			case log := <-logs:
+				w.deliver(log)
+			case err := <-sub.Err():
+				w.fail(fmt.Errorf("watch %s: subscription: %w", w.event, err))
+				break stream
+			}
+		}
+		sub.Unsubscribe()
+		if !w.sleep(ctx) {
+			return
+		}
+	}
+}
+
+// poll queries new blocks every interval.
+func (w *eventWatcher) poll(ctx context.Context) {
+	head, ok := w.head(ctx)
+	if !ok {
+		return
+	}
+	w.next = head + 1
+	for w.sleep(ctx) {
+		w.backfill(ctx)
+	}
+}
+
+// backfill delivers the logs from w.next, or the last delivered block if
+// later, to the current head. It reports false once ctx ends; RPC failures are
+// reported and retried at the next call.
+func (w *eventWatcher) backfill(ctx context.Context) bool {
+	head, err := w.client.BlockNumber(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		w.fail(fmt.Errorf("watch %s: block number: %w", w.event, err))
+		return true
+	}
+	from := w.next
+	if w.started && w.lastBlock > from {
+		from = w.lastBlock // the rest of its logs may be missing
+	}
+	if head < from {
+		return true
+	}
+	query := w.query
+	query.FromBlock = new(big.Int).SetUint64(from)
+	query.ToBlock = new(big.Int).SetUint64(head)
+	logs, err := w.client.FilterLogs(ctx, query)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		w.fail(fmt.Errorf("watch %s: get logs: %w", w.event, err))
+		return true
+	}
+	for _, log := range logs {
+		w.deliver(log)
+	}
+	w.next = head + 1
+	return true
+}
+
+// deliver decodes log and sends it unless it was removed by a reorg or is
+// not after the last delivered log.
+func (w *eventWatcher) deliver(log types.Log) {
+	if log.Removed {
+		return
+	}
+	if w.started && (log.BlockNumber < w.lastBlock || log.BlockNumber == w.lastBlock && log.Index <= w.lastIndex) {
+		return
+	}
+	w.started, w.lastBlock, w.lastIndex = true, log.BlockNumber, log.Index
+	events, err := decodeLogs(w.contract.abi, []*types.Log{&log}, &w.contract.address)
+	if err != nil {
+		w.fail(fmt.Errorf("watch %s: %w", w.event, err))
+		return
+	}
+	for _, ev := range events {
+		select {
+		case w.events <- ev:
+		default:
+			w.client.countDroppedEvent(w.event)
+		}
+	}
+}
+
+// head returns the current block number, retrying until it succeeds or ctx
+// ends.
+func (w *eventWatcher) head(ctx context.Context) (uint64, bool) {
+	for {
+		head, err := w.client.BlockNumber(ctx)
+		if err == nil {
+			return head, true
+		}
+		if ctx.Err() != nil {
+			return 0, false
+		}
+		w.fail(fmt.Errorf("watch %s: block number: %w", w.event, err))
+		if !w.sleep(ctx) {
+			return 0, false
+		}
+	}
+}
+
+// fail reports err unless an error is already pending.
+func (w *eventWatcher) fail(err error) {
+	select {
+	case w.errs <- err:
+	default:
+	}
+}
+
+// sleep waits one polling interval; it reports false if ctx ended first.
+func (w *eventWatcher) sleep(ctx context.Context) bool {
+	interval := w.client.blockTime
+	if interval <= 0 {
+		interval = watchPollInterval
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-w.client.clock.After(interval):
+		return true
+	}
+}
+
+// EOF: internal/blockchain/evm/watch.go
@@ -0,0 +1,193 @@
+// Package evm_test tests live event watching on bound contracts.
+//
+// File: internal/blockchain/evm/watch_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// tickClock is a fakeClock whose waits last a millisecond of real time, so
+// pollers make progress without spinning.
+type tickClock struct{ fakeClock }
+
+func (c *tickClock) After(d time.Duration) <-chan time.Time {
+	c.fakeClock.After(d)
+	return time.After(time.Millisecond)
+}
+
+// watchSetup deploys the Stored contract and returns a sender for it.
+func watchSetup(t *testing.T, opts ...evm.ClientOption) (*simulated.Backend, *evm.BoundContract, func(id, value int64)) {
+	t.Helper()
+	wallet, err := evm.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	t.Cleanup(func() { sim.Close() })
+	gateway := newSimulatedGateway(t, sim, wallet, opts...)
+	ctx := context.Background()
+
+	_, addr, err := gateway.DeployContract(ctx, storedInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+	bound, err := evm.NewBoundContract(addr.Hex(), storedABI, gateway)
+	require.NoError(t, err)
+
+	to := addr.Hex()
+	store := func(id, value int64) {
+		data := append(common.LeftPadBytes(big.NewInt(id).Bytes(), 32), common.LeftPadBytes(big.NewInt(value).Bytes(), 32)...)
+		_, err := gateway.SendTransaction(ctx, &blockchain.Transaction{To: &to, Data: data})
+		require.NoError(t, err)
+	}
+	return sim, bound.(*evm.BoundContract), store
+}
+
+func TestBoundContract_WatchEvents_Subscription(t *testing.T) {
+	sim, contract, store := watchSetup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store(1, 10) // mined before the watch starts: not delivered
+	sim.Commit()
+
+	events, errs, err := contract.WatchEvents(ctx, "Stored", nil, []interface{}{big.NewInt(2), big.NewInt(3)})
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond) // let the watcher subscribe
+
+	store(2, 20)
+	store(4, 40) // filtered out by id
+	sim.Commit()
+	store(3, 30)
+	sim.Commit()
+
+	var got []int64
+	timeout := time.After(5 * time.Second)
+	for len(got) < 2 {
+		select {
+		case ev := <-events:
+			assert.Equal(t, "Stored", ev.Name)
+			got = append(got, ev.Args["id"].(*big.Int).Int64())
+		case err := <-errs:
+			t.Fatalf("watch error: %v", err)
+		case <-timeout:
+			t.Fatalf("got %v before timeout", got)
+		}
+	}
+	assert.Equal(t, []int64{2, 3}, got)
+
+	cancel()
+	for range events {
+	}
+	_, open := <-errs
+	assert.False(t, open, "both channels are closed after cancel")
+
+	_, _, err = contract.WatchEvents(context.Background(), "Stored", nil, nil, big.NewInt(1))
+	assert.ErrorContains(t, err, "not indexed")
+}
+
+func TestBoundContract_WatchEvents_DropsWhenFull(t *testing.T) {
+	metrics := newFakeMetrics()
+	sim, contract, store := watchSetup(t, evm.WithWatchBuffer(1), evm.WithMetrics(metrics), evm.WithChainName("sim"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, _, err := contract.WatchEvents(ctx, "Stored")
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+
+	for id := int64(1); id <= 3; id++ {
+		store(id, id)
+	}
+	sim.Commit()
+
+	require.Eventually(t, func() bool {
+		metrics.mu.Lock()
+		defer metrics.mu.Unlock()
+		return metrics.counters[evm.MetricEventsDropped] == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	ev := <-events
+	assert.Equal(t, big.NewInt(1), ev.Args["id"], "the first event is kept")
+}
+
+func TestBoundContract_WatchEvents_PollsOverHTTP(t *testing.T) {
+	addr := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	log := types.Log{
+		Address:     addr,
+		Topics:      []common.Hash{storedTopic, common.HexToHash("0x01"), common.BigToHash(big.NewInt(5))},
+		Data:        common.LeftPadBytes(big.NewInt(50).Bytes(), 32),
+		BlockNumber: 11,
+		TxHash:      common.HexToHash("0xfeed"),
+		Index:       0,
+	}
+	var mu sync.Mutex
+	var heads, queries int
+	client := newFeeClient(t, map[string]interface{}{
+		"eth_blockNumber": func([]json.RawMessage) interface{} {
+			mu.Lock()
+			defer mu.Unlock()
+			heads++
+			if heads == 1 {
+				return "0xa" // block 10 when the watch starts
+			}
+			return "0xb"
+		},
+		"eth_getLogs": func([]json.RawMessage) interface{} {
+			mu.Lock()
+			defer mu.Unlock()
+			queries++
+			return []types.Log{log}
+		},
+	}, evm.WithBlockTime(2*time.Second))
+	clock := &tickClock{}
+	client.SetClock(clock)
+	gateway := evm.NewEVMGatewayFromClient(client, nil, nil)
+	bound, err := evm.NewBoundContract(addr.Hex(), storedABI, gateway)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, _, err := bound.(*evm.BoundContract).WatchEvents(ctx, "Stored")
+	require.NoError(t, err)
+
+	select {
+	case ev := <-events:
+		assert.Equal(t, big.NewInt(5), ev.Args["id"])
+		assert.Equal(t, uint64(11), ev.BlockNumber)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event polled")
+	}
+	// Later polls see no new block and query nothing.
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, 1, queries)
+	mu.Unlock()
+	select {
+	case ev := <-events:
+		t.Fatalf("event delivered twice: %+v", ev)
+	default:
+	}
+
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	require.NotEmpty(t, clock.waits)
+	assert.Equal(t, 2*time.Second, clock.waits[0])
+}
+
+// EOF: internal/blockchain/evm/watch_test.go
@@ -5,7 +5,12 @@
 // Key types:
 //   - Chain       : read/write operations common to all blockchains.
 //   - Wallet      : signing and address derivation.
+//   - MultiWallet : optional wallet holding several accounts.
 //   - Contract    : high‑level interaction with smart contracts.
+//   - NameResolver: optional resolution of names (e.g. ENS) to addresses.
+//   - ContractDetector: optional check whether an address holds code.
+//   - CostEstimator: optional fee estimates, including rollup data fees.
+//   - Simulator   : optional simulation of transactions before signing.
 //
 // All implementations of these interfaces must be safe for concurrent use.
 //
@@ -15,9 +20,35 @@ package blockchain
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
 )
 
+// ErrUnknownAccount is returned when a transaction names a sender the
+// wallet holds no key for.
+var ErrUnknownAccount = errors.New("unknown account")
+
+// ErrWalletLocked is returned by a wallet that can no longer sign, for
+// example because its credentials to a secret store expired or a
+// LockableWallet was locked.
+var ErrWalletLocked = errors.New("wallet locked")
+
+// SignerRejectedError is returned when a remote signer refuses to sign,
+// typically because of a policy on the signer's side. It is distinct from
+// transport failures so that it can be logged as a policy decision.
+type SignerRejectedError struct {
+	Signer  string // signer URL
+	Account string // account that was to sign
+	Code    int    // signer's error code (JSON‑RPC code or HTTP status)
+	Reason  string // signer's message
+}
+
+// Error returns the signer, account and the signer's reason.
+func (e *SignerRejectedError) Error() string {
+	return fmt.Sprintf("signer %s rejected signing as %s: %s (code %d)", e.Signer, e.Account, e.Reason, e.Code)
+}
+
 // BlockNumber represents a block identifier.
 // It can be a decimal/hex string, a *big.Int, or one of the predefined
 // constants: "latest", "pending", "earliest".
@@ -33,23 +64,34 @@ const (
 // All fields are optional; nil values indicate the field should be omitted
 // or automatically estimated by the node.
 type Transaction struct {
-	To        *string  `json:"to"`         // nil for contract creation
-	Value     *big.Int `json:"value"`      // amount of native currency
-	Gas       uint64   `json:"gas"`        // gas limit
-	GasPrice  *big.Int `json:"gasPrice"`   // legacy gas price
-	GasFeeCap *big.Int `json:"maxFeePerGas"`   // EIP‑1559 fee cap
-	GasTipCap *big.Int `json:"maxPriorityFeePerGas"` // EIP‑1559 tip
-	Data      []byte   `json:"data"`       // input data
-	Nonce     *uint64  `json:"nonce"`      // account nonce
+	To         *string       `json:"to"`                   // nil for contract creation
+	Value      *big.Int      `json:"value"`                // amount of native currency
+	Gas        uint64        `json:"gas"`                  // gas limit
+	GasPrice   *big.Int      `json:"gasPrice"`             // legacy gas price
+	GasFeeCap  *big.Int      `json:"maxFeePerGas"`         // EIP‑1559 fee cap
+	GasTipCap  *big.Int      `json:"maxPriorityFeePerGas"` // EIP‑1559 tip
+	Data       []byte        `json:"data"`                 // input data
+	Nonce      *uint64       `json:"nonce"`                // account nonce
+	AccessList []AccessTuple `json:"accessList,omitempty"` // EIP‑2930 access list
+	Simulate   bool          `json:"simulate,omitempty"`   // eth_call before signing; fail on revert
+	From       string        `json:"from,omitempty"`       // signing account ("" = the wallet's primary account)
+}
+
+// AccessTuple names an account and the storage slots a transaction will
+// touch (EIP‑2930). Addresses and keys are hex strings.
+type AccessTuple struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
 }
 
 // ContractCall represents a message call that does not create a transaction.
 // It is used for eth_call and similar read‑only operations.
 type ContractCall struct {
-	To    string   `json:"to"`    // target contract address
-	Data  []byte   `json:"data"`  // encoded call data
-	Value *big.Int `json:"value"` // native currency sent with the call
-	Gas   uint64   `json:"gas"`   // gas limit (optional)
+	To    string      `json:"to"`              // target contract address
+	Data  []byte      `json:"data"`            // encoded call data
+	Value *big.Int    `json:"value"`           // native currency sent with the call
+	Gas   uint64      `json:"gas"`             // gas limit (optional)
+	Block BlockNumber `json:"block,omitempty"` // block to read state at (empty = latest)
 }
 
 // Chain defines the set of operations a blockchain must support.
@@ -73,18 +115,138 @@ type Chain interface {
 
 	// EstimateGas tries to estimate the gas needed for a transaction or call.
 	EstimateGas(ctx context.Context, call *ContractCall) (uint64, error)
+
+	// Close releases the connection to the chain. It is safe to call more
+	// than once; the chain must not be used afterwards.
+	Close() error
+}
+
+// NameResolver is implemented by chains whose address inputs also accept
+// human‑readable names, such as ENS names on EVM chains.
+type NameResolver interface {
+	// ResolveName returns the address a name points to.
+	ResolveName(ctx context.Context, name string) (string, error)
+}
+
+// ContractDetector is implemented by chains that can tell contract accounts
+// from externally owned ones, e.g. to warn before sending value to a token
+// contract.
+type ContractDetector interface {
+	// IsContract reports whether address has code deployed at the latest block.
+	IsContract(ctx context.Context, address string) (bool, error)
+}
+
+// TxCost is the estimated fee of a transaction in wei, excluding the value
+// it sends.
+type TxCost struct {
+	Gas          uint64   `json:"gas"`          // estimated gas limit
+	GasPrice     *big.Int `json:"gasPrice"`     // price per gas the fees are estimated at
+	ExecutionFee *big.Int `json:"executionFee"` // execution gas × gas price
+	DataFee      *big.Int `json:"dataFee"`      // L1 data fee on rollups, else 0
+	Total        *big.Int `json:"total"`        // ExecutionFee + DataFee
+}
+
+// CostEstimator is implemented by chains that can estimate a transaction's
+// total fee before it is signed.
+type CostEstimator interface {
+	// EstimateTotalCost estimates the fees tx will pay.
+	EstimateTotalCost(ctx context.Context, tx *Transaction) (*TxCost, error)
+}
+
+// Simulation is the outcome of running a transaction against the latest
+// state without sending it.
+type Simulation struct {
+	Reverted     bool   `json:"reverted"`
+	RevertReason string `json:"revertReason,omitempty"`
+	// Traced reports whether the run was traced. Only then are Transfers
+	// and Contracts known.
+	Traced bool `json:"traced"`
+	// Transfers are the movements of native currency and ERC‑20 tokens
+	// made by calls that did not revert.
+	Transfers []AssetTransfer `json:"transfers,omitempty"`
+	// Contracts are the addresses called with data, by the transaction or
+	// internally, in order of first call; precompiles are left out.
+	Contracts []string `json:"contracts,omitempty"`
+}
+
+// AssetTransfer is a movement of native currency or of an ERC‑20 token.
+// Addresses are checksummed hex strings.
+type AssetTransfer struct {
+	Token  string   `json:"token,omitempty"` // "" for native currency
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Amount *big.Int `json:"amount"`
+}
+
+// Simulator is implemented by chains that can simulate a transaction
+// before it is signed.
+type Simulator interface {
+	// SimulateTransaction runs tx against the latest state, tracing it if
+	// the node can. A revert is reported in the result, not as an error.
+	SimulateTransaction(ctx context.Context, tx *Transaction) (*Simulation, error)
+}
+
+// Canceller is implemented by chains that can cancel a pending transaction
+// by replacing it with a transfer of 0 to its sender.
+type Canceller interface {
+	// CancellationTransaction returns the replacement that cancelling the
+	// pending transaction txHash would send, unsigned: From and To are the
+	// original's sender, with its nonce and bumped fees.
+	CancellationTransaction(ctx context.Context, txHash string) (*Transaction, error)
+}
+
+// RawSender is implemented by chains that can recover the sender of a
+// transaction signed elsewhere, so that policies charge the account that
+// signed it.
+type RawSender interface {
+	// RawTransactionSender returns the address that signed raw, a signed
+	// transaction as bytes or 0x‑hex text.
+	RawTransactionSender(raw []byte) (string, error)
 }
 
 // Wallet is responsible for cryptographic signing and address management.
 type Wallet interface {
-	// Sign signs the provided digest (usually a transaction hash).
-	// Returns the raw signature bytes.
+	// Sign signs the provided 32‑byte digest (usually a transaction hash)
+	// without hashing it again. It returns the 65‑byte signature
+	// [R || S || V] with S in the lower half of the curve order and the
+	// recovery id V as 0/1 (as crypto.Sign returns it) or 27/28; callers
+	// normalize V and add any chain ID themselves. Wallets that must see
+	// the whole transaction implement evm.TxSigner in addition.
 	Sign(digest []byte) ([]byte, error)
 
 	// Address returns the wallet's public address as a hex‑encoded string.
 	Address() string
 }
 
+// MultiWallet is implemented by wallets that hold several accounts, such
+// as a keystore directory. Address returns the primary account, which
+// signs transactions that do not name a sender.
+type MultiWallet interface {
+	Wallet
+
+	// Accounts returns the addresses of all accounts, primary first.
+	Accounts() []string
+
+	// WalletFor returns a wallet signing as address, or an error wrapping
+	// ErrUnknownAccount if the wallet holds no key for it.
+	WalletFor(address string) (Wallet, error)
+}
+
+// LockableWallet is a Wallet that can wipe its key from memory, after
+// which it fails with ErrWalletLocked until unlocked with its passphrase.
+type LockableWallet interface {
+	Wallet
+
+	// Lock wipes the key from memory. Locking a locked wallet is a no‑op.
+	Lock()
+
+	// Unlock decrypts the key again with passphrase.
+	Unlock(passphrase string) error
+
+	// Locked reports whether the wallet is locked.
+	Locked() bool
+}
+
 // Contract provides a convenient, type‑safe interface for interacting with
 // a deployed smart contract. It requires an ABI definition to encode/decode calls.
 type Contract interface {
@@ -93,9 +255,20 @@ type Contract interface {
 	// Returns the decoded return values as a slice of interface{}.
 	Call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error)
 
+	// CallInto executes a read‑only contract method and unpacks the return
+	// values into out: a pointer to a struct for multiple values, or to a
+	// single value.
+	CallInto(ctx context.Context, out interface{}, method string, args ...interface{}) error
+
 	// Transact creates and sends a transaction that invokes a contract method.
 	// Returns the transaction hash.
 	Transact(ctx context.Context, method string, args ...interface{}) (string, error)
+
+	// EstimateGas estimates the gas a transaction invoking method would use.
+	EstimateGas(ctx context.Context, method string, args ...interface{}) (uint64, error)
+
+	// EstimateCost estimates the fee in wei of a transaction invoking method.
+	EstimateCost(ctx context.Context, method string, args ...interface{}) (*big.Int, error)
 }
 
 // EOF: internal/blockchain/interface.go
\ No newline at end of file
@@ -51,6 +51,10 @@ func (m *MockChain) EstimateGas(ctx context.Context, call *blockchain.ContractCa
 	return args.Get(0).(uint64), args.Error(1)
 }
 
+func (m *MockChain) Close() error {
+	return nil
+}
+
 // MockWallet implements blockchain.Wallet for testing.
 type MockWallet struct {
 	mock.Mock
@@ -76,11 +80,25 @@ func (m *MockContract) Call(ctx context.Context, method string, args ...interfac
 	return callArgs.Get(0).([]interface{}), callArgs.Error(1)
 }
 
+func (m *MockContract) CallInto(ctx context.Context, out interface{}, method string, args ...interface{}) error {
+	return m.Called(ctx, out, method, args).Error(0)
+}
+
 func (m *MockContract) Transact(ctx context.Context, method string, args ...interface{}) (string, error) {
 	callArgs := m.Called(ctx, method, args)
 	return callArgs.String(0), callArgs.Error(1)
 }
 
+func (m *MockContract) EstimateGas(ctx context.Context, method string, args ...interface{}) (uint64, error) {
+	callArgs := m.Called(ctx, method, args)
+	return callArgs.Get(0).(uint64), callArgs.Error(1)
+}
+
+func (m *MockContract) EstimateCost(ctx context.Context, method string, args ...interface{}) (*big.Int, error) {
+	callArgs := m.Called(ctx, method, args)
+	return callArgs.Get(0).(*big.Int), callArgs.Error(1)
+}
+
 func TestChainInterface(t *testing.T) {
 	ctx := context.Background()
 	mockChain := new(MockChain)
@@ -7,16 +7,39 @@ package config
 import (
 	"fmt"
 	"math/big"
-	"strconv"
 	"strings"
+
+	"github.com/0xSemantic/lola-os/sdk/types/units"
 )
 
 // Amount represents a token amount with unit.
 type Amount struct {
 	Wei *big.Int
+	// USD is set instead of Wei for an amount in US dollars ("500 usd"),
+	// which policies convert from native currency with a price oracle.
+	USD *big.Rat
+}
+
+// IsUSD reports whether the amount is in US dollars.
+func (a *Amount) IsUSD() bool {
+	return a != nil && a.USD != nil
+}
+
+// usdDecimals is the precision of amounts in US dollars.
+const usdDecimals = 18
+
+// amountDecimals maps the accepted units to their decimals.
+var amountDecimals = map[string]int{
+	"wei":   units.WeiDecimals,
+	"gwei":  units.GweiDecimals,
+	"eth":   units.EtherDecimals,
+	"ether": units.EtherDecimals,
 }
 
-// ParseAmount parses a string like "1.5 eth", "100 gwei", "5000 wei".
+// ParseAmount parses a string like "1.5 eth", "100 gwei", "5000 wei", or
+// "500 usd" for an amount in US dollars.
+// The conversion is exact; amounts finer than one wei and negative amounts
+// are rejected.
 func ParseAmount(s string) (*Amount, error) {
 	s = strings.TrimSpace(s)
 	parts := strings.Fields(s)
@@ -25,23 +48,22 @@ func ParseAmount(s string) (*Amount, error) {
 	}
 	valueStr, unit := parts[0], strings.ToLower(parts[1])
 
-	valueFloat, err := strconv.ParseFloat(valueStr, 64)
+	decimals, ok := amountDecimals[unit]
+	if unit == "usd" {
+		decimals, ok = usdDecimals, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown unit: %s", unit)
+	}
+	wei, err := units.ParseUnits(valueStr, decimals)
 	if err != nil {
 		return nil, fmt.Errorf("parse number: %w", err)
 	}
-
-	var wei *big.Int
-	switch unit {
-	case "wei":
-		wei = big.NewInt(int64(valueFloat))
-	case "gwei":
-		// 1 gwei = 1e9 wei
-		wei = new(big.Int).Mul(big.NewInt(int64(valueFloat*1e9)), big.NewInt(1))
-	case "eth":
-		// 1 eth = 1e18 wei
-		wei = new(big.Int).Mul(big.NewInt(int64(valueFloat*1e18)), big.NewInt(1))
-	default:
-		return nil, fmt.Errorf("unknown unit: %s", unit)
+	if wei.Sign() < 0 {
+		return nil, fmt.Errorf("negative amount: %q", s)
+	}
+	if unit == "usd" {
+		return &Amount{USD: new(big.Rat).SetFrac(wei, new(big.Int).Exp(big.NewInt(10), big.NewInt(usdDecimals), nil))}, nil
 	}
 	return &Amount{Wei: wei}, nil
 }
@@ -55,4 +77,49 @@ func MustParseAmount(s string) *Amount {
 	return a
 }
 
+// TokenAmount is an amount of an ERC‑20 token in whole tokens, such as
+// "1000 usdc". Its value in base units depends on the token's decimals.
+type TokenAmount struct {
+	Value  string // decimal number, e.g. "1000" or "0.5"
+	Symbol string // optional, informational only
+}
+
+// ParseTokenAmount parses a string like "1000 usdc" or "2.5".
+func ParseTokenAmount(s string) (*TokenAmount, error) {
+	parts := strings.Fields(s)
+	if len(parts) == 0 || len(parts) > 2 {
+		return nil, fmt.Errorf("invalid token amount format: %q", s)
+	}
+	a := &TokenAmount{Value: parts[0]}
+	if len(parts) == 2 {
+		a.Symbol = parts[1]
+	}
+	// The token's decimals are not known yet; this only checks the number.
+	v, err := units.ParseUnits(a.Value, 255)
+	if err != nil {
+		return nil, fmt.Errorf("parse number: %w", err)
+	}
+	if v.Sign() < 0 {
+		return nil, fmt.Errorf("negative amount: %q", s)
+	}
+	return a, nil
+}
+
+// Units returns the amount in base units of a token with decimals.
+func (a *TokenAmount) Units(decimals int) (*big.Int, error) {
+	v, err := units.ParseUnits(a.Value, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", a, err)
+	}
+	return v, nil
+}
+
+// String returns the amount as configured.
+func (a *TokenAmount) String() string {
+	if a.Symbol == "" {
+		return a.Value
+	}
+	return a.Value + " " + a.Symbol
+}
+
 // EOF: internal/config/amount.go
\ No newline at end of file
@@ -32,6 +32,9 @@ type Config struct {
 
 	// Advanced tuning parameters.
 	Advanced *AdvancedConfig `mapstructure:"advanced"`
+
+	// ABI lookup for binding contracts by address (optional).
+	ABI *evm.ABIConfig `mapstructure:"abi"`
 }
 
 // ChainConfig defines settings for a single blockchain.
@@ -65,16 +68,127 @@ type ChainConfig struct {
 
 	// Retry configuration (optional).
 	RetryConfig *evm.RetryConfig `mapstructure:"retry"`
+	// Circuit breaker configuration (optional; disabled when nil).
+	Circuit *evm.CircuitConfig `mapstructure:"circuit"`
+	// Client‑side rate limit for the RPC endpoint (0 = unlimited).
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	// Maximum burst above the rate limit.
+	Burst int `mapstructure:"burst"`
+	// Skip verifying the node's chain ID against ChainID (local devnets).
+	SkipChainIDCheck bool `mapstructure:"skip_chain_id_check"`
+	// Factor by which estimated gas limits are padded (0 = 1.2).
+	GasLimitMultiplier float64 `mapstructure:"gas_limit_multiplier"`
+	// Upper bound for padded gas limits (0 = no cap).
+	MaxGasLimit uint64 `mapstructure:"max_gas_limit"`
+	// ENS registry address (default: the mainnet registry on Ethereum,
+	// Sepolia and Holesky; names do not resolve on other chains).
+	ENSRegistry string `mapstructure:"ens_registry"`
+	// In‑flight transaction tracking (optional; disabled when nil).
+	TxTracker *evm.TrackerConfig `mapstructure:"tx_tracker"`
+	// Background RPC health checks with automatic reconnect (optional;
+	// disabled when nil).
+	Health *evm.HealthConfig `mapstructure:"health"`
+	// Rollup stack ("op-stack" or "arbitrum") whose L1 data fee is included
+	// in cost estimates (empty for L1s).
+	L2 evm.L2Stack `mapstructure:"l2"`
+	// Start even if the RPC endpoint is unreachable and connect on first
+	// use instead of dropping the chain.
+	LazyConnect bool `mapstructure:"lazy_connect"`
+	// Etherscan‑compatible explorer API used to fetch verified ABIs
+	// (optional; Sourcify is used without it).
+	Explorer *evm.ExplorerConfig `mapstructure:"explorer"`
+	// Base URL of the Safe Transaction Service, to which the safe_propose
+	// tool submits proposals (e.g. https://safe-transaction-mainnet.safe.global).
+	SafeTxService string `mapstructure:"safe_tx_service"`
+	// ERC‑4337 bundler and smart account used by the aa_send tool
+	// (optional).
+	Bundler *evm.BundlerConfig `mapstructure:"bundler"`
 }
 
 // WalletConfig defines wallet/keystore settings.
 type WalletConfig struct {
+	// Provider of the signing key: "keystore" (default; a keystore or
+	// mnemonic as configured below), "kms" (an AWS KMS key), "gcpkms" (a
+	// Google Cloud KMS key), "vault" (a key in HashiCorp Vault) or
+	// "remote" (a remote signer such as Web3Signer).
+	Provider string `mapstructure:"provider"`
+
+	// KMS key id, ARN or alias (provider "kms"). The key must have spec
+	// ECC_SECG_P256K1 and usage SIGN_VERIFY.
+	KeyID string `mapstructure:"key_id"`
+
+	// AWS region of the KMS key ("" = from the AWS configuration).
+	Region string `mapstructure:"region"`
+
+	// Cloud KMS key version (provider "gcpkms"), as
+	// projects/…/locations/…/keyRings/…/cryptoKeys/…/cryptoKeyVersions/….
+	// The key must have algorithm EC_SIGN_SECP256K1_SHA256.
+	KeyResourceName string `mapstructure:"key_resource_name"`
+
+	// Vault server address (provider "vault"; "" = $VAULT_ADDR).
+	Address string `mapstructure:"address"`
+
+	// Vault API path of the KV secret holding "private_key" (e.g.
+	// secret/data/agent) or of the signing plugin account.
+	Path string `mapstructure:"path"`
+
+	// Environment variable holding the Vault token (default VAULT_TOKEN).
+	TokenEnv string `mapstructure:"token_env"`
+
+	// How the Vault key is used: "kv" (default; read once, kept in memory)
+	// or "plugin" (every digest signed by the plugin at Path).
+	Mode string `mapstructure:"mode"`
+
+	// JSON‑RPC URL of the remote signer (provider "remote").
+	SignerURL string `mapstructure:"signer_url"`
+
+	// Account the remote signer signs as.
+	Account string `mapstructure:"account"`
+
+	// PEM client certificate and key presented to the remote signer, and
+	// the CA it is verified against ("" = system roots).
+	TLSCert string `mapstructure:"tls_cert"`
+	TLSKey  string `mapstructure:"tls_key"`
+	TLSCA   string `mapstructure:"tls_ca"`
+
 	// Path to encrypted keystore file.
 	KeystorePath string `mapstructure:"keystore_path"`
 
+	// Directory holding several encrypted keys, one file each, like geth's
+	// keystore directory. Takes precedence over KeystorePath; transactions
+	// may name any of its accounts as sender.
+	KeystoreDir string `mapstructure:"keystore_dir"`
+
+	// Format a new keystore is written in: "v3" (default, geth‑compatible)
+	// or "legacy". Existing keystores are read in either format.
+	KeystoreFormat string `mapstructure:"keystore_format"`
+
 	// Environment variable name that holds the passphrase.
 	PassphraseEnv string `mapstructure:"passphrase_env"`
 
+	// Idle time after which the keystore wipes its keys from memory until
+	// unlocked again with the passphrase (0 = never).
+	AutoLock time.Duration `mapstructure:"auto_lock"`
+
+	// Scrypt cost (N, a power of two) and parallelization (p) of key files
+	// the keystore writes (0 = 32768 and 1). Lower N only for tests.
+	ScryptN int `mapstructure:"scrypt_n"`
+	ScryptP int `mapstructure:"scrypt_p"`
+
+	// Environment variable holding a BIP‑39 mnemonic. If set, the wallet
+	// is derived from it instead of loaded from the keystore.
+	MnemonicEnv string `mapstructure:"mnemonic_env"`
+
+	// Environment variable holding the optional BIP‑39 passphrase.
+	MnemonicPassphraseEnv string `mapstructure:"mnemonic_passphrase_env"`
+
+	// Derivation path with an {index} placeholder
+	// (default m/44'/60'/0'/0/{index}).
+	DerivationPath string `mapstructure:"derivation_path"`
+
+	// Index of the account to derive from the mnemonic.
+	AccountIndex uint32 `mapstructure:"account_index"`
+
 	// Timeout for wallet operations.
 	Timeout time.Duration `mapstructure:"timeout"`
 
@@ -87,28 +201,504 @@ type SecurityConfig struct {
 	// Global read‑only flag (rejects all writes).
 	ReadOnly bool `mapstructure:"read_only"`
 
-	// Per‑transaction value limit (native currency).
+	// Per‑transaction value limit (native currency, or "500 usd").
 	MaxTransactionValue *Amount `mapstructure:"max_transaction_value"`
 
-	// Daily spend limit (rolling 24h).
+	// Daily spend limit (rolling 24h; native currency, or "5000 usd").
 	DailyLimit *Amount `mapstructure:"daily_limit"`
 
-	// Allowed destination addresses (if non‑empty, only these are permitted).
+	// Prices of native currencies, for limits in usd.
+	PriceOracle *PriceOracleConfig `mapstructure:"price_oracle"`
+
+	// File in which the daily spend is kept across restarts (empty =
+	// lola.limits.json next to the audit log).
+	DailyLimitState string `mapstructure:"daily_limit_state"`
+
+	// What an unreadable daily_limit_state file does: "open" (default;
+	// log an error and count from zero) or "closed" (refuse to start).
+	DailyLimitStateMode string `mapstructure:"daily_limit_state_mode"`
+
+	// Maximum gas price a transaction may pay, e.g. "100 gwei".
+	MaxGasPrice *Amount `mapstructure:"max_gas_price"`
+
+	// Maximum gas limit of a transaction (0 = no limit).
+	MaxGasPerTx uint64 `mapstructure:"max_gas_per_tx"`
+
+	// Fees the wallet may spend on gas per rolling 24h.
+	DailyGasBudget *Amount `mapstructure:"daily_gas_budget"`
+
+	// File in which the daily fees are kept across restarts (empty =
+	// lola.gas.json next to the audit log). An unreadable file is handled
+	// as daily_limit_state_mode says.
+	DailyGasBudgetState string `mapstructure:"daily_gas_budget_state"`
+
+	// Tools the agent may execute (if non‑empty, only these), by name or
+	// glob pattern such as "erc20_*".
+	AllowedTools []string `mapstructure:"allowed_tools"`
+
+	// Tools the agent may not execute, in the same forms; they win over
+	// allowed ones.
+	BlockedTools []string `mapstructure:"blocked_tools"`
+
+	// Cap on write operations per signing address and time window.
+	RateLimit *RateLimitConfig `mapstructure:"rate_limit"`
+
+	// Caps on the total sent to any one address in a time window.
+	RecipientLimits *RecipientLimitConfig `mapstructure:"recipient_limits"`
+
+	// Holds back new transactions while earlier ones of the signing
+	// account are stuck in the mempool (nil = never).
+	PendingTx *PendingTxConfig `mapstructure:"pending_tx"`
+
+	// Allowed destination addresses (if non‑empty, only these are
+	// permitted): addresses, ENS names, or "contract:<address>" for any
+	// call to a contract.
 	AllowedAddresses []string `mapstructure:"allowed_addresses"`
 
-	// Blocked destination addresses.
+	// Blocked destination addresses, in the same forms; they win over
+	// allowed ones.
 	BlockedAddresses []string `mapstructure:"blocked_addresses"`
 
+	// Address lists kept up to date from files and URLs, such as
+	// sanctions lists (nil = none).
+	Denylist *DenylistConfig `mapstructure:"denylist"`
+
+	// Contracts and methods transactions may call (nil = any).
+	ContractAllowlist *ContractAllowlistConfig `mapstructure:"contract_allowlist"`
+
+	// Contracts that may be deployed, by creation bytecode (nil = any).
+	Deploy *DeployConfig `mapstructure:"deploy"`
+
+	// Limits on ERC‑20 transfers and approvals, by token address.
+	TokenLimits map[string]*TokenLimitConfig `mapstructure:"token_limits"`
+
+	// What transfers and approvals of tokens not in TokenLimits do:
+	// "allow" (default), "deny" or "approve" (ask a human).
+	UnknownTokens string `mapstructure:"unknown_tokens"`
+
+	// Caps and spenders for ERC‑20 approvals, and what unlimited
+	// approvals do (nil = approvals are only counted by TokenLimits).
+	TokenApprovals *TokenApprovalConfig `mapstructure:"token_approvals"`
+
 	// Human‑in‑the‑loop configuration.
 	HITL *HITLConfig `mapstructure:"human_in_the_loop"`
+
+	// Ways to pause every write at runtime, besides Runtime.PauseWrites
+	// (nil = none).
+	KillSwitch *KillSwitchConfig `mapstructure:"kill_switch"`
+
+	// Simulate every transaction with eth_call before signing.
+	SimulateTransactions bool `mapstructure:"simulate_transactions"`
+
+	// Simulate transactions while policies are evaluated, and deny those
+	// that would revert or move more than allowed (nil = not simulated).
+	Simulation *SimulationConfig `mapstructure:"simulation"`
+
+	// Chains each of the tools, read_only, limits, gas, whitelist and
+	// token_limits policies applies to, by chain name (absent = every
+	// chain). Policy blocks carry their own chains list.
+	Scope map[string][]string `mapstructure:"scope"`
+
+	// Policies among tools, read_only, limits, gas, whitelist and
+	// token_limits that only warn: their denials are logged and audited as
+	// "would_deny" and the operation goes ahead. Policy blocks carry their
+	// own advisory flag.
+	Advisory []string `mapstructure:"advisory"`
+
+	// Run every policy but read_only and human_in_the_loop as advisory,
+	// to see what a configuration would block before enforcing it.
+	DryRun bool `mapstructure:"dry_run"`
+
+	// Stop evaluating policies at the first denial. By default every
+	// policy runs, so that a denial lists everything wrong.
+	ShortCircuit bool `mapstructure:"short_circuit"`
+
+	// Order in which policies are evaluated, by name (see Policies);
+	// those not listed follow in the default order. Cheap checks first
+	// keep a human from being asked about an operation another policy
+	// denies.
+	PolicyOrder []string `mapstructure:"policy_order"`
+
+	// Policies whose denial stops evaluation, by name (see Policies).
+	Terminal []string `mapstructure:"terminal"`
+}
+
+// ScopedPolicies are the keys of SecurityConfig.Scope, and the names
+// SecurityConfig.Advisory takes.
+var ScopedPolicies = []string{"tools", "read_only", "limits", "gas", "whitelist", "token_limits"}
+
+// Policies are the names of the built‑in policies, and "custom" for those
+// added with the SDK, in the order they are evaluated by default:
+// stateless checks first, then those that count spending, then those
+// that may ask a human.
+var Policies = []string{
+	"tools", "read_only", "whitelist", "denylist", "contract_allowlist", "deploy", "token_approvals",
+	"limits", "recipient_limits", "session_budget", "gas", "rate_limit", "pending_tx", "token_limits", "custom", "simulation", "human_in_the_loop",
+}
+
+// PriceOracleConfig configures how native currency amounts are converted
+// to US dollars for limits given in usd.
+type PriceOracleConfig struct {
+	// Chain name -> address of the Chainlink feed pricing the chain's
+	// native currency in USD (e.g. ETH / USD on ethereum).
+	Chainlink map[string]string `mapstructure:"chainlink"`
+
+	// Native currency symbol -> fixed price, e.g. "3000 usd". Used alone
+	// without Chainlink feeds, or when they fail with on_failure: static.
+	StaticPrices map[string]*Amount `mapstructure:"static_prices"`
+
+	// What a failed Chainlink lookup does: "deny" (default; the
+	// transaction is denied) or "static" (use static_prices).
+	OnFailure string `mapstructure:"on_failure"`
+
+	// Oldest Chainlink answer accepted (0 = 1h).
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// PendingTxConfig holds back new transactions while too many of the
+// signing account's are pending, or one has been pending too long: they
+// would only queue up behind the stuck one, and all go through at once
+// when it is mined.
+type PendingTxConfig struct {
+	// Transactions the account may have pending when it sends another
+	// (0 = no limit).
+	MaxPending int `mapstructure:"max_pending"`
+
+	// How long the oldest pending transaction may have waited, e.g. "10m"
+	// (0 = no limit).
+	MaxAge time.Duration `mapstructure:"max_age"`
+
+	// What an operation over either limit does: "deny" (default) or
+	// "approve" (ask a human).
+	OnExceed string `mapstructure:"on_exceed"`
+
+	// Chains the policy applies to (empty = every chain).
+	Chains []string `mapstructure:"chains"`
+
+	// Only warn of denials instead of blocking (see SecurityConfig.Advisory).
+	Advisory bool `mapstructure:"advisory"`
+}
+
+// RateLimitConfig caps the number of transactions in a sliding window.
+type RateLimitConfig struct {
+	// Write operations allowed per window.
+	MaxTx int `mapstructure:"max_tx"`
+
+	// Length of the sliding window, e.g. "1h".
+	Window time.Duration `mapstructure:"window"`
+
+	// File in which the transaction times are kept across restarts
+	// (empty = lola.rate.json next to the audit log). An unreadable file
+	// is handled as daily_limit_state_mode says.
+	State string `mapstructure:"state"`
+
+	// Chains the limit applies to (empty = every chain).
+	Chains []string `mapstructure:"chains"`
+
+	// Only warn of denials instead of blocking (see SecurityConfig.Advisory).
+	Advisory bool `mapstructure:"advisory"`
+}
+
+// KillSwitchConfig sets how writes can be paused without code: reads
+// keep working while they are.
+type KillSwitchConfig struct {
+	// File whose existence pauses writes, looked for at every operation;
+	// its content, if any, is the reason (empty = none).
+	File string `mapstructure:"file"`
+
+	// Serve GET /kill-switch, POST /kill-switch/pause and
+	// POST /kill-switch/resume on the metrics server.
+	HTTP bool `mapstructure:"http"`
+
+	// Environment variable holding the endpoints' bearer token (default
+	// LOLA_KILL_SWITCH_TOKEN).
+	TokenEnv string `mapstructure:"token_env"`
+}
+
+// RecipientLimitConfig caps the total sent to any one address, whoever
+// signs, within a window: a drip of transfers each under the
+// per‑transaction limit still adds up.
+type RecipientLimitConfig struct {
+	// Native currency sent to one address per window, e.g. "1 eth"
+	// (nil = not limited). Amounts in usd are not supported.
+	MaxPerRecipient *Amount `mapstructure:"max_per_recipient"`
+
+	// ERC‑20 amounts transferred or approved to one address per window,
+	// by token address.
+	Tokens map[string]*RecipientTokenLimit `mapstructure:"tokens"`
+
+	// Length of the window, e.g. "168h" (0 = seven days).
+	Window time.Duration `mapstructure:"window"`
+
+	// Caps of their own for known addresses, such as a treasury, by
+	// address. A cap an override does not set is the default one.
+	Overrides map[string]*RecipientOverride `mapstructure:"overrides"`
+
+	// File in which the totals are kept across restarts (empty =
+	// lola.recipients.json next to the audit log). An unreadable file is
+	// handled as daily_limit_state_mode says.
+	State string `mapstructure:"state"`
+
+	// Chains the limits apply to (empty = every chain).
+	Chains []string `mapstructure:"chains"`
+
+	// Only warn of denials instead of blocking (see SecurityConfig.Advisory).
+	Advisory bool `mapstructure:"advisory"`
+}
+
+// RecipientTokenLimit caps the amounts of one ERC‑20 token sent to any
+// one address.
+type RecipientTokenLimit struct {
+	// Amount per recipient and window, e.g. "5000 usdc".
+	MaxPerRecipient *TokenAmount `mapstructure:"max_per_recipient"`
+
+	// Token decimals (nil = read from the token).
+	Decimals *uint8 `mapstructure:"decimals"`
+}
+
+// RecipientOverride replaces the caps of recipient_limits for one address.
+type RecipientOverride struct {
+	// Native currency per window, e.g. "100 eth".
+	MaxPerRecipient *Amount `mapstructure:"max_per_recipient"`
+
+	// Token amounts per window, by token address; the token must be
+	// listed under recipient_limits.tokens.
+	Tokens map[string]*TokenAmount `mapstructure:"tokens"`
+}
+
+// DenylistConfig denies operations with a destination on one of several
+// externally maintained address lists, reloaded periodically.
+type DenylistConfig struct {
+	// The lists; an address on any of them is denied.
+	Feeds []DenylistFeed `mapstructure:"feeds"`
+
+	// How often the lists are reloaded (0 = every hour).
+	Refresh time.Duration `mapstructure:"refresh"`
+
+	// How long a list may go without loading before on_stale applies
+	// (0 = 24h).
+	MaxStale time.Duration `mapstructure:"max_stale"`
+
+	// What a stale list does: "warn" (default; log a warning and check
+	// against the addresses last loaded) or "deny" (deny operations with
+	// a destination until it loads).
+	OnStale string `mapstructure:"on_stale"`
+
+	// Chains the lists apply to (empty = every chain).
+	Chains []string `mapstructure:"chains"`
+
+	// Only warn of denials instead of blocking (see SecurityConfig.Advisory).
+	Advisory bool `mapstructure:"advisory"`
+}
+
+// DenylistFeed is one address list, read from a file or fetched from a
+// URL: a JSON array of addresses, a JSON object with an "addresses"
+// array, or one address per line with # comments.
+type DenylistFeed struct {
+	// Name of the list, given in denials, e.g. "ofac".
+	Name string `mapstructure:"name"`
+
+	// Path of a local file.
+	File string `mapstructure:"file"`
+
+	// HTTPS URL, fetched with If‑None‑Match when the server sends ETags.
+	URL string `mapstructure:"url"`
+}
+
+// ContractAllowlistConfig restricts the contracts transactions call and
+// the methods they call on each.
+type ContractAllowlistConfig struct {
+	// Contract address -> allowed methods, each a 4‑byte selector in hex
+	// ("0x095ea7b3") or a signature ("approve(address,uint256)").
+	Contracts map[string][]string `mapstructure:"contracts"`
+
+	// Allow transactions without data, i.e. plain value transfers.
+	AllowTransfers bool `mapstructure:"allow_transfers"`
+
+	// Allow contract creation.
+	AllowDeploy bool `mapstructure:"allow_deploy"`
+
+	// Chains the allowlist applies to (empty = every chain).
+	Chains []string `mapstructure:"chains"`
+
+	// Only warn of denials instead of blocking (see SecurityConfig.Advisory).
+	Advisory bool `mapstructure:"advisory"`
+}
+
+// DeployConfig restricts the contracts that may be deployed to known
+// creation bytecode.
+type DeployConfig struct {
+	// Allow contract creation; false denies every deployment.
+	AllowDeploy bool `mapstructure:"allow_deploy"`
+
+	// Keccak‑256 hashes of the creation data that may be deployed, as
+	// 0x‑hex: the bytecode followed by any constructor arguments. A denial
+	// names the hash of the data it denied.
+	AllowedHashes []string `mapstructure:"allowed_hashes"`
+
+	// Creation bytecode that may be deployed, each as 0x‑hex or the path
+	// of a Foundry or Hardhat artifact. Without allowed_hashes or
+	// allowed_bytecode, any bytecode may be deployed.
+	AllowedBytecode []string `mapstructure:"allowed_bytecode"`
+
+	// Match allowed_bytecode as a prefix of the creation data, so that it
+	// may be deployed with any constructor arguments.
+	IgnoreConstructorArgs bool `mapstructure:"ignore_constructor_args"`
+
+	// Chains the policy applies to (empty = every chain).
+	Chains []string `mapstructure:"chains"`
+
+	// Only warn of denials instead of blocking (see SecurityConfig.Advisory).
+	Advisory bool `mapstructure:"advisory"`
+}
+
+// SimulationConfig configures the policy that simulates each transaction
+// before it is approved. A transaction that would revert is denied; the
+// bounds below need a node that traces calls (debug_traceCall).
+type SimulationConfig struct {
+	// Most native currency the signing wallet may send, as the
+	// transaction's value and from internal calls (nil = any).
+	MaxNativeOutflow *Amount `mapstructure:"max_native_outflow"`
+
+	// Token address -> most the signing wallet may send of the token,
+	// e.g. "1000 usdc"; decimals are read from the token.
+	MaxTokenOutflow map[string]*TokenAmount `mapstructure:"max_token_outflow"`
+
+	// Contracts the transaction may call, directly or internally (empty =
+	// any).
+	AllowedContracts []string `mapstructure:"allowed_contracts"`
+
+	// What happens when the chain cannot simulate the transaction, or
+	// cannot trace it to check the bounds: "skip" (default; check what can
+	// be checked) or "deny".
+	OnUnsupported string `mapstructure:"on_unsupported"`
+
+	// Chains the policy applies to (empty = every chain).
+	Chains []string `mapstructure:"chains"`
+
+	// Only warn of denials instead of blocking (see SecurityConfig.Advisory).
+	Advisory bool `mapstructure:"advisory"`
+}
+
+// TokenLimitConfig limits the amounts of one ERC‑20 token that
+// transactions transfer or approve.
+type TokenLimitConfig struct {
+	// Per‑transaction limit, e.g. "1000 usdc".
+	MaxTransactionValue *TokenAmount `mapstructure:"max_transaction_value"`
+
+	// Limit per rolling 24h and signing address.
+	DailyLimit *TokenAmount `mapstructure:"daily_limit"`
+
+	// Token decimals (nil = read from the token).
+	Decimals *uint8 `mapstructure:"decimals"`
+}
+
+// TokenApprovalConfig restricts the allowances that ERC‑20 approve and
+// increaseAllowance calls grant.
+type TokenApprovalConfig struct {
+	// Caps on the allowance granted in one call, by token address.
+	Tokens map[string]*TokenApprovalLimit `mapstructure:"tokens"`
+
+	// Spenders that may be granted an allowance, by address (empty = any).
+	// Revoking an allowance is always allowed.
+	AllowedSpenders []string `mapstructure:"allowed_spenders"`
+
+	// What an unlimited approval (2²⁵⁵ or more, such as MaxUint256) does:
+	// "deny" (default) or "approve" (ask a human).
+	OnUnlimited string `mapstructure:"on_unlimited"`
+
+	// Chains the policy applies to (empty = every chain).
+	Chains []string `mapstructure:"chains"`
+
+	// Only warn of denials instead of blocking (see SecurityConfig.Advisory).
+	Advisory bool `mapstructure:"advisory"`
+}
+
+// TokenApprovalLimit caps the allowances of one ERC‑20 token.
+type TokenApprovalLimit struct {
+	// Largest allowance one call may grant, e.g. "1000 usdc".
+	MaxAllowance *TokenAmount `mapstructure:"max_allowance"`
+
+	// Token decimals (nil = read from the token).
+	Decimals *uint8 `mapstructure:"decimals"`
 }
 
 // HITLConfig defines human‑in‑the‑loop parameters.
 type HITLConfig struct {
-	Enabled   bool          `mapstructure:"enabled"`
-	Threshold *Amount       `mapstructure:"threshold"`
-	Timeout   time.Duration `mapstructure:"timeout"`
-	Mode      string        `mapstructure:"mode"` // "console" (others future)
+	Enabled   bool               `mapstructure:"enabled"`
+	Threshold *Amount            `mapstructure:"threshold"` // native currency, or "500 usd"
+	Timeout   time.Duration      `mapstructure:"timeout"`
+	Mode      string             `mapstructure:"mode"`     // "console", "telegram" or "api"
+	Telegram  *TelegramConfig    `mapstructure:"telegram"` // for mode "telegram"
+	API       *ApprovalAPIConfig `mapstructure:"api"`      // for mode "api"
+	Chains    []string           `mapstructure:"chains"`   // empty = every chain
+
+	// Operations over the threshold matching one of Rules are approved
+	// without asking; those matching one of AlwaysRequire are always asked
+	// about, whatever the threshold.
+	Rules         []HITLRule `mapstructure:"rules"`
+	AlwaysRequire []HITLRule `mapstructure:"always_require"`
+
+	// Operations over a tier's threshold need the approvals of several
+	// humans; the tier requiring the most approvals applies.
+	Tiers []HITLTier `mapstructure:"tiers"`
+}
+
+// HITLTier requires several distinct approvers for operations above a
+// threshold.
+type HITLTier struct {
+	Threshold *Amount `mapstructure:"threshold"` // native currency, or "5000 usd"
+
+	// Distinct approvals needed, e.g. 2 of the approvers.
+	ApprovalsRequired int `mapstructure:"approvals_required"`
+
+	// Who may decide: Telegram user ids in mode telegram, or in mode api
+	// the names of environment variables each holding one approver's
+	// bearer token.
+	Approvers []string `mapstructure:"approvers"`
+
+	// What a denial does: "veto" (the default) denies the operation;
+	// "count" denies it only once too few approvers are left to approve.
+	OnDeny string `mapstructure:"on_deny"`
+}
+
+// HITLRule matches operations meeting every condition it sets; at least
+// one must be set.
+type HITLRule struct {
+	Name   string   `mapstructure:"name"`   // for logs and the audit log
+	To     []string `mapstructure:"to"`     // destination addresses
+	Below  *Amount  `mapstructure:"below"`  // value strictly below, native currency or usd
+	Tools  []string `mapstructure:"tools"`  // tool names
+	Chains []string `mapstructure:"chains"` // chain names
+}
+
+// ApprovalAPIConfig defines the built‑in HTTP approval server.
+type ApprovalAPIConfig struct {
+	// Address to listen on (default 127.0.0.1:8089).
+	Listen string `mapstructure:"listen"`
+
+	// Environment variable holding the bearer token (default
+	// LOLA_APPROVAL_TOKEN).
+	TokenEnv string `mapstructure:"token_env"`
+
+	// File saving the pending requests, so that requests abandoned by a
+	// crash are recorded (empty = in memory only).
+	State string `mapstructure:"state"`
+}
+
+// TelegramConfig defines approval through a Telegram bot.
+type TelegramConfig struct {
+	// Environment variable holding the bot token (default TELEGRAM_BOT_TOKEN).
+	BotTokenEnv string `mapstructure:"bot_token_env"`
+
+	// Chat the approval requests are sent to.
+	ChatID int64 `mapstructure:"chat_id"`
+
+	// IDs of the users who may approve or deny.
+	AllowedUsers []int64 `mapstructure:"allowed_users"`
+
+	// Bot API base URL (default https://api.telegram.org).
+	APIURL string `mapstructure:"api_url"`
 }
 
 // ObservabilityConfig defines logging, metrics, tracing, audit.
@@ -6,10 +6,18 @@ package config
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/mitchellh/mapstructure"
 	"reflect"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
 )
 
 // Loader defines the interface for configuration sources.
@@ -41,6 +49,7 @@ func LoadConfig(ctx context.Context, loaders ...Loader) (*Config, error) {
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
 			mapstructure.StringToTimeDurationHookFunc(),
 			stringToAmountHookFunc(),
+			stringToTokenAmountHookFunc(),
 		),
 	})
 	if err != nil {
@@ -114,6 +123,22 @@ func stringToAmountHookFunc() mapstructure.DecodeHookFunc {
 	}
 }
 
+// stringToTokenAmountHookFunc converts string token amounts to *TokenAmount.
+func stringToTokenAmountHookFunc() mapstructure.DecodeHookFunc {
+	return func(f, t reflect.Type, data interface{}) (interface{}, error) {
+		if t != reflect.TypeOf(&TokenAmount{}) {
+			return data, nil
+		}
+		switch v := data.(type) {
+		case string:
+			return ParseTokenAmount(v)
+		case int, int64, uint64, float64:
+			return ParseTokenAmount(fmt.Sprint(v))
+		}
+		return data, nil
+	}
+}
+
 // validateConfig performs semantic validation.
 func validateConfig(cfg *Config) error {
 	// Ensure at least one chain is configured.
@@ -127,8 +152,593 @@ func validateConfig(cfg *Config) error {
 			// For now, just warn; we can allow empty RPC if profile has default? We'll require RPC.
 			return fmt.Errorf("chain %q: missing RPC URL", name)
 		}
+		if chain.GasLimitMultiplier != 0 && chain.GasLimitMultiplier < 1 {
+			return fmt.Errorf("chain %q: gas_limit_multiplier must be at least 1, got %v", name, chain.GasLimitMultiplier)
+		}
+		if chain.ENSRegistry != "" {
+			if _, err := evm.NormalizeAddress(chain.ENSRegistry); err != nil {
+				return fmt.Errorf("chain %q: ens_registry: %w", name, err)
+			}
+		}
+		if b := chain.Bundler; b != nil {
+			if b.URL == "" {
+				return fmt.Errorf("chain %q: bundler: missing url", name)
+			}
+			if _, err := evm.NormalizeAddress(b.Account); err != nil {
+				return fmt.Errorf("chain %q: bundler: account: %w", name, err)
+			}
+			if b.EntryPoint != "" {
+				if _, err := evm.NormalizeAddress(b.EntryPoint); err != nil {
+					return fmt.Errorf("chain %q: bundler: entry_point: %w", name, err)
+				}
+			}
+		}
+		if t := chain.TxTracker; t != nil && (t.Interval < 0 || t.SpeedUpAfter < 0) {
+			return fmt.Errorf("chain %q: tx_tracker durations must not be negative", name)
+		}
+		if h := chain.Health; h != nil && (h.Interval < 0 || h.FailureThreshold < 0) {
+			return fmt.Errorf("chain %q: health interval and failure_threshold must not be negative", name)
+		}
+		switch chain.L2 {
+		case evm.L2None, evm.L2OPStack, evm.L2Arbitrum:
+		default:
+			return fmt.Errorf("chain %q: unknown l2 stack %q (want %q or %q)", name, chain.L2, evm.L2OPStack, evm.L2Arbitrum)
+		}
+	}
+	if w := cfg.Wallet; w != nil {
+		switch w.Provider {
+		case "", "keystore":
+		case "kms":
+			if w.KeyID == "" {
+				return fmt.Errorf("wallet: provider kms requires key_id")
+			}
+		case "gcpkms":
+			if w.KeyResourceName == "" {
+				return fmt.Errorf("wallet: provider gcpkms requires key_resource_name")
+			}
+		case "vault":
+			if w.Path == "" {
+				return fmt.Errorf("wallet: provider vault requires path")
+			}
+			switch evm.VaultMode(w.Mode) {
+			case "", evm.VaultModeKV, evm.VaultModePlugin:
+			default:
+				return fmt.Errorf("wallet: unknown vault mode %q (want %q or %q)", w.Mode, evm.VaultModeKV, evm.VaultModePlugin)
+			}
+		case "remote":
+			if w.SignerURL == "" {
+				return fmt.Errorf("wallet: provider remote requires signer_url")
+			}
+			if _, err := evm.NormalizeAddress(w.Account); err != nil {
+				return fmt.Errorf("wallet: provider remote account: %w", err)
+			}
+			if (w.TLSCert == "") != (w.TLSKey == "") {
+				return fmt.Errorf("wallet: tls_cert and tls_key must be set together")
+			}
+		default:
+			return fmt.Errorf("wallet: unknown provider %q (want %q, %q, %q, %q or %q)", w.Provider, "keystore", "kms", "gcpkms", "vault", "remote")
+		}
+		if w.AutoLock < 0 {
+			return fmt.Errorf("wallet: auto_lock must not be negative")
+		}
+		if n := w.ScryptN; n < 0 || n == 1 || n&(n-1) != 0 {
+			return fmt.Errorf("wallet: scrypt_n must be a power of two")
+		}
+		if w.ScryptP < 0 {
+			return fmt.Errorf("wallet: scrypt_p must not be negative")
+		}
+	}
+	switch cfg.Security.DailyLimitStateMode {
+	case "", "open", "closed":
+	default:
+		return fmt.Errorf("security: unknown daily_limit_state_mode %q (want %q or %q)", cfg.Security.DailyLimitStateMode, "open", "closed")
+	}
+	if hitl := cfg.Security.HITL; hitl != nil {
+		switch hitl.Mode {
+		case "", "console":
+		case "telegram":
+			if hitl.Telegram == nil || hitl.Telegram.ChatID == 0 {
+				return fmt.Errorf("security: human_in_the_loop: mode telegram needs telegram.chat_id")
+			}
+			if len(hitl.Telegram.AllowedUsers) == 0 {
+				return fmt.Errorf("security: human_in_the_loop: mode telegram needs telegram.allowed_users")
+			}
+		case "api":
+		default:
+			return fmt.Errorf("security: human_in_the_loop: unknown mode %q (want %q, %q or %q)", hitl.Mode, "console", "telegram", "api")
+		}
+	}
+	if err := validateScopes(cfg); err != nil {
+		return err
+	}
+	if err := validateHITLRules(cfg); err != nil {
+		return err
+	}
+	if err := validateHITLTiers(cfg.Security.HITL); err != nil {
+		return err
+	}
+	if err := validatePolicyNames(cfg); err != nil {
+		return err
+	}
+	if err := validatePriceOracle(cfg); err != nil {
+		return err
+	}
+	if rl := cfg.Security.RateLimit; rl != nil {
+		if rl.MaxTx <= 0 {
+			return fmt.Errorf("security: rate_limit: max_tx must be positive")
+		}
+		if rl.Window <= 0 {
+			return fmt.Errorf("security: rate_limit: window must be positive")
+		}
+	}
+	if pt := cfg.Security.PendingTx; pt != nil {
+		if pt.MaxPending < 0 || pt.MaxAge < 0 {
+			return fmt.Errorf("security: pending_tx: max_pending and max_age must not be negative")
+		}
+		if pt.MaxPending == 0 && pt.MaxAge == 0 {
+			return fmt.Errorf("security: pending_tx: needs max_pending or max_age")
+		}
+		switch pt.OnExceed {
+		case "", "deny", "approve":
+		default:
+			return fmt.Errorf("security: pending_tx: invalid on_exceed %q (want %q or %q)", pt.OnExceed, "deny", "approve")
+		}
+	}
+	if err := validateDenylist(cfg.Security.Denylist); err != nil {
+		return err
+	}
+	if err := validateRecipientLimits(cfg.Security.RecipientLimits); err != nil {
+		return err
+	}
+	if ks := cfg.Security.KillSwitch; ks != nil && ks.HTTP {
+		if o := cfg.Observability; o == nil || o.Metrics == nil || !o.Metrics.Enabled || o.Metrics.Addr == "" {
+			return fmt.Errorf("security: kill_switch: http needs the metrics server (observability.metrics.enabled and addr)")
+		}
+	}
+	if cal := cfg.Security.ContractAllowlist; cal != nil {
+		for addr, methods := range cal.Contracts {
+			if _, err := evm.NormalizeAddress(addr); err != nil {
+				return fmt.Errorf("security: contract_allowlist: %w", err)
+			}
+			for _, method := range methods {
+				if _, _, err := evm.ParseSelector(method); err != nil {
+					return fmt.Errorf("security: contract_allowlist: %s: %w", addr, err)
+				}
+			}
+		}
+	}
+	if d := cfg.Security.Deploy; d != nil {
+		for _, h := range d.AllowedHashes {
+			if len(h) != 66 || !strings.HasPrefix(h, "0x") {
+				return fmt.Errorf("security: deploy: allowed_hashes: invalid hash %q (want 0x and 64 hex digits)", h)
+			}
+			if _, err := hex.DecodeString(h[2:]); err != nil {
+				return fmt.Errorf("security: deploy: allowed_hashes: invalid hash %q: %w", h, err)
+			}
+		}
+		for i, b := range d.AllowedBytecode {
+			if !strings.HasPrefix(b, "0x") {
+				continue // an artifact, read when the runtime starts
+			}
+			if _, err := hex.DecodeString(b[2:]); err != nil || len(b) == 2 {
+				return fmt.Errorf("security: deploy: allowed_bytecode[%d]: not valid hex bytecode", i)
+			}
+		}
+	}
+	if sim := cfg.Security.Simulation; sim != nil {
+		for addr := range sim.MaxTokenOutflow {
+			if _, err := evm.NormalizeAddress(addr); err != nil {
+				return fmt.Errorf("security: simulation: max_token_outflow: %w", err)
+			}
+		}
+		for _, addr := range sim.AllowedContracts {
+			if _, err := evm.NormalizeAddress(addr); err != nil {
+				return fmt.Errorf("security: simulation: allowed_contracts: %w", err)
+			}
+		}
+		switch sim.OnUnsupported {
+		case "", "skip", "deny":
+		default:
+			return fmt.Errorf("security: simulation: invalid on_unsupported %q (want %q or %q)", sim.OnUnsupported, "skip", "deny")
+		}
+	}
+	for addr := range cfg.Security.TokenLimits {
+		if _, err := evm.NormalizeAddress(addr); err != nil {
+			return fmt.Errorf("security: token_limits: %w", err)
+		}
+	}
+	switch cfg.Security.UnknownTokens {
+	case "", "allow", "deny", "approve":
+	default:
+		return fmt.Errorf("security: invalid unknown_tokens %q (want %q, %q or %q)", cfg.Security.UnknownTokens, "allow", "deny", "approve")
+	}
+	if ta := cfg.Security.TokenApprovals; ta != nil {
+		for addr := range ta.Tokens {
+			if _, err := evm.NormalizeAddress(addr); err != nil {
+				return fmt.Errorf("security: token_approvals: tokens: %w", err)
+			}
+		}
+		for _, addr := range ta.AllowedSpenders {
+			if _, err := evm.NormalizeAddress(addr); err != nil {
+				return fmt.Errorf("security: token_approvals: allowed_spenders: %w", err)
+			}
+		}
+		switch ta.OnUnlimited {
+		case "", "deny", "approve":
+		default:
+			return fmt.Errorf("security: token_approvals: invalid on_unlimited %q (want %q or %q)", ta.OnUnlimited, "deny", "approve")
+		}
+	}
+	for key, list := range map[string][]string{"allowed_tools": cfg.Security.AllowedTools, "blocked_tools": cfg.Security.BlockedTools} {
+		for _, pattern := range list {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return fmt.Errorf("security: %s: invalid tool pattern %q", key, pattern)
+			}
+		}
+	}
+	for _, list := range [][]string{cfg.Security.AllowedAddresses, cfg.Security.BlockedAddresses} {
+		for _, addr := range list {
+			// A contract entry names the contract by address.
+			if len(addr) > len("contract:") && strings.EqualFold(addr[:len("contract:")], "contract:") {
+				addr = strings.TrimSpace(addr[len("contract:"):])
+			} else if evm.IsENSName(addr) {
+				continue
+			}
+			if _, err := evm.NormalizeAddress(addr); err != nil {
+				return fmt.Errorf("security: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRecipientLimits checks the recipient_limits block, if any.
+func validateRecipientLimits(rl *RecipientLimitConfig) error {
+	if rl == nil {
+		return nil
+	}
+	if rl.MaxPerRecipient == nil && len(rl.Tokens) == 0 {
+		return fmt.Errorf("security: recipient_limits: no max_per_recipient or tokens")
+	}
+	if rl.MaxPerRecipient.IsUSD() {
+		return fmt.Errorf("security: recipient_limits: max_per_recipient must be in the native currency")
+	}
+	if rl.Window < 0 {
+		return fmt.Errorf("security: recipient_limits: window must not be negative")
+	}
+	tokens := make(map[string]bool, len(rl.Tokens))
+	for addr, limit := range rl.Tokens {
+		token, err := evm.NormalizeAddress(addr)
+		if err != nil {
+			return fmt.Errorf("security: recipient_limits: tokens: %w", err)
+		}
+		if limit == nil || limit.MaxPerRecipient == nil {
+			return fmt.Errorf("security: recipient_limits: tokens: %s: missing max_per_recipient", addr)
+		}
+		tokens[token] = true
+	}
+	for addr, override := range rl.Overrides {
+		if _, err := evm.NormalizeAddress(addr); err != nil {
+			return fmt.Errorf("security: recipient_limits: overrides: %w", err)
+		}
+		if override == nil {
+			continue
+		}
+		if override.MaxPerRecipient.IsUSD() {
+			return fmt.Errorf("security: recipient_limits: overrides: %s: max_per_recipient must be in the native currency", addr)
+		}
+		for tokenAddr := range override.Tokens {
+			token, err := evm.NormalizeAddress(tokenAddr)
+			if err != nil {
+				return fmt.Errorf("security: recipient_limits: overrides: %s: %w", addr, err)
+			}
+			if !tokens[token] {
+				return fmt.Errorf("security: recipient_limits: overrides: %s: token %s is not under tokens", addr, tokenAddr)
+			}
+		}
 	}
 	return nil
 }
 
+// validateDenylist checks that every denylist feed has a unique name and
+// one source.
+func validateDenylist(dl *DenylistConfig) error {
+	if dl == nil {
+		return nil
+	}
+	if len(dl.Feeds) == 0 {
+		return fmt.Errorf("security: denylist: no feeds")
+	}
+	names := make(map[string]bool, len(dl.Feeds))
+	for i, feed := range dl.Feeds {
+		if feed.Name == "" {
+			return fmt.Errorf("security: denylist: feeds[%d]: missing name", i)
+		}
+		if names[feed.Name] {
+			return fmt.Errorf("security: denylist: feed %q listed twice", feed.Name)
+		}
+		names[feed.Name] = true
+		if (feed.File == "") == (feed.URL == "") {
+			return fmt.Errorf("security: denylist: feed %q: set one of file and url", feed.Name)
+		}
+		if feed.URL != "" {
+			u, err := url.Parse(feed.URL)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return fmt.Errorf("security: denylist: feed %q: invalid url %q", feed.Name, feed.URL)
+			}
+		}
+	}
+	if dl.Refresh < 0 || dl.MaxStale < 0 {
+		return fmt.Errorf("security: denylist: refresh and max_stale must not be negative")
+	}
+	switch dl.OnStale {
+	case "", "warn", "deny":
+	default:
+		return fmt.Errorf("security: denylist: invalid on_stale %q (want %q or %q)", dl.OnStale, "warn", "deny")
+	}
+	return nil
+}
+
+// validatePolicyNames checks that policy_order and terminal name built‑in
+// policies, each once.
+func validatePolicyNames(cfg *Config) error {
+	lists := []struct {
+		key   string
+		names []string
+	}{
+		{"policy_order", cfg.Security.PolicyOrder},
+		{"terminal", cfg.Security.Terminal},
+	}
+	for _, list := range lists {
+		key, names := list.key, list.names
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			if !slices.Contains(Policies, name) {
+				return fmt.Errorf("security: %s: unknown policy %q (want one of %s)", key, name, strings.Join(Policies, ", "))
+			}
+			if seen[name] {
+				return fmt.Errorf("security: %s: policy %q listed twice", key, name)
+			}
+			seen[name] = true
+		}
+	}
+	return nil
+}
+
+// validateScopes checks that the chains security policies are scoped to
+// are configured, and the policies named advisory exist.
+func validateScopes(cfg *Config) error {
+	check := func(policy string, chains []string) error {
+		for _, name := range chains {
+			if !hasChain(cfg, name) {
+				return fmt.Errorf("security: %s: unknown chain %q", policy, name)
+			}
+		}
+		return nil
+	}
+	for policy, chains := range cfg.Security.Scope {
+		if !slices.Contains(ScopedPolicies, policy) {
+			return fmt.Errorf("security: scope: unknown policy %q (want one of %s)", policy, strings.Join(ScopedPolicies, ", "))
+		}
+		if err := check("scope: "+policy, chains); err != nil {
+			return err
+		}
+	}
+	for _, policy := range cfg.Security.Advisory {
+		if !slices.Contains(ScopedPolicies, policy) {
+			return fmt.Errorf("security: advisory: unknown policy %q (want one of %s)", policy, strings.Join(ScopedPolicies, ", "))
+		}
+	}
+	if hitl := cfg.Security.HITL; hitl != nil {
+		if err := check("human_in_the_loop", hitl.Chains); err != nil {
+			return err
+		}
+	}
+	if rl := cfg.Security.RateLimit; rl != nil {
+		if err := check("rate_limit", rl.Chains); err != nil {
+			return err
+		}
+	}
+	if pt := cfg.Security.PendingTx; pt != nil {
+		if err := check("pending_tx", pt.Chains); err != nil {
+			return err
+		}
+	}
+	if dl := cfg.Security.Denylist; dl != nil {
+		if err := check("denylist", dl.Chains); err != nil {
+			return err
+		}
+	}
+	if cal := cfg.Security.ContractAllowlist; cal != nil {
+		if err := check("contract_allowlist", cal.Chains); err != nil {
+			return err
+		}
+	}
+	if d := cfg.Security.Deploy; d != nil {
+		if err := check("deploy", d.Chains); err != nil {
+			return err
+		}
+	}
+	if rl := cfg.Security.RecipientLimits; rl != nil {
+		if err := check("recipient_limits", rl.Chains); err != nil {
+			return err
+		}
+	}
+	if ta := cfg.Security.TokenApprovals; ta != nil {
+		if err := check("token_approvals", ta.Chains); err != nil {
+			return err
+		}
+	}
+	if sim := cfg.Security.Simulation; sim != nil {
+		if err := check("simulation", sim.Chains); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateHITLRules checks that every human_in_the_loop rule can match:
+// a rule with a typo in an address or chain would otherwise never apply.
+func validateHITLRules(cfg *Config) error {
+	hitl := cfg.Security.HITL
+	if hitl == nil {
+		return nil
+	}
+	for _, list := range []struct {
+		name  string
+		rules []HITLRule
+	}{{"rules", hitl.Rules}, {"always_require", hitl.AlwaysRequire}} {
+		for i, r := range list.rules {
+			name := fmt.Sprintf("security: human_in_the_loop: %s[%d]", list.name, i)
+			if r.Name != "" {
+				name += fmt.Sprintf(" (%s)", r.Name)
+			}
+			if len(r.To) == 0 && r.Below == nil && len(r.Tools) == 0 && len(r.Chains) == 0 {
+				return fmt.Errorf("%s: no conditions", name)
+			}
+			for _, addr := range r.To {
+				if _, err := evm.NormalizeAddress(addr); err != nil {
+					return fmt.Errorf("%s: to: %w", name, err)
+				}
+			}
+			if r.Below != nil && ((r.Below.IsUSD() && r.Below.USD.Sign() <= 0) || (!r.Below.IsUSD() && r.Below.Wei.Sign() <= 0)) {
+				return fmt.Errorf("%s: below must be positive", name)
+			}
+			for _, tool := range r.Tools {
+				if tool == "" {
+					return fmt.Errorf("%s: empty tool name", name)
+				}
+			}
+			for _, chain := range r.Chains {
+				if !hasChain(cfg, chain) {
+					return fmt.Errorf("%s: unknown chain %q", name, chain)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateHITLTiers checks that every human_in_the_loop tier names
+// enough approvers, in the form its mode identifies them by.
+func validateHITLTiers(hitl *HITLConfig) error {
+	if hitl == nil {
+		return nil
+	}
+	for i, tier := range hitl.Tiers {
+		name := fmt.Sprintf("security: human_in_the_loop: tiers[%d]", i)
+		if tier.Threshold == nil {
+			return fmt.Errorf("%s: no threshold", name)
+		}
+		if tier.ApprovalsRequired < 1 {
+			return fmt.Errorf("%s: approvals_required must be at least 1", name)
+		}
+		if len(tier.Approvers) < tier.ApprovalsRequired {
+			return fmt.Errorf("%s: %d approvers cannot give %d approvals", name, len(tier.Approvers), tier.ApprovalsRequired)
+		}
+		switch tier.OnDeny {
+		case "", "veto", "count":
+		default:
+			return fmt.Errorf("%s: unknown on_deny %q (want %q or %q)", name, tier.OnDeny, "veto", "count")
+		}
+		seen := make(map[string]bool, len(tier.Approvers))
+		for _, approver := range tier.Approvers {
+			if seen[approver] {
+				return fmt.Errorf("%s: approver %q listed twice", name, approver)
+			}
+			seen[approver] = true
+			switch hitl.Mode {
+			case "telegram":
+				id, err := strconv.ParseInt(approver, 10, 64)
+				if err != nil || !slices.Contains(hitl.Telegram.AllowedUsers, id) {
+					return fmt.Errorf("%s: approver %q is not a telegram user id in telegram.allowed_users", name, approver)
+				}
+			case "api":
+				if approver == "" {
+					return fmt.Errorf("%s: empty approver", name)
+				}
+			default:
+				return fmt.Errorf("%s: tiers need mode telegram or api, which tell approvers apart", name)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePriceOracle checks that amounts in usd are used only for the
+// limits that convert them, and that those have prices to convert with.
+func validatePriceOracle(cfg *Config) error {
+	sec := cfg.Security
+	amounts := map[string]*Amount{"max_gas_price": sec.MaxGasPrice, "daily_gas_budget": sec.DailyGasBudget}
+	if sec.Simulation != nil {
+		amounts["simulation: max_native_outflow"] = sec.Simulation.MaxNativeOutflow
+	}
+	for name, a := range amounts {
+		if a.IsUSD() {
+			return fmt.Errorf("security: %s: amounts in usd are not supported", name)
+		}
+	}
+	for name, chain := range cfg.Chains {
+		if chain.GasPriceLimit.IsUSD() {
+			return fmt.Errorf("chain %q: gas_price_limit: amounts in usd are not supported", name)
+		}
+	}
+	usd := sec.MaxTransactionValue.IsUSD() || sec.DailyLimit.IsUSD() || (sec.HITL != nil && sec.HITL.Threshold.IsUSD())
+	if hitl := sec.HITL; hitl != nil {
+		for _, r := range append(slices.Clone(hitl.Rules), hitl.AlwaysRequire...) {
+			usd = usd || r.Below.IsUSD()
+		}
+		for _, tier := range hitl.Tiers {
+			usd = usd || tier.Threshold.IsUSD()
+		}
+	}
+	po := sec.PriceOracle
+	if po == nil {
+		if usd {
+			return fmt.Errorf("security: limits in usd require price_oracle")
+		}
+		return nil
+	}
+	if len(po.Chainlink) == 0 && len(po.StaticPrices) == 0 {
+		return fmt.Errorf("security: price_oracle: no chainlink feeds or static_prices")
+	}
+	for name, feed := range po.Chainlink {
+		if !hasChain(cfg, name) {
+			return fmt.Errorf("security: price_oracle: unknown chain %q", name)
+		}
+		if _, err := evm.NormalizeAddress(feed); err != nil {
+			return fmt.Errorf("security: price_oracle: chain %q: %w", name, err)
+		}
+	}
+	for symbol, price := range po.StaticPrices {
+		if !price.IsUSD() || price.USD.Sign() == 0 {
+			return fmt.Errorf("security: price_oracle: static price of %s must be a positive amount in usd", symbol)
+		}
+	}
+	switch po.OnFailure {
+	case "", "deny":
+	case "static":
+		if len(po.StaticPrices) == 0 {
+			return fmt.Errorf("security: price_oracle: on_failure static requires static_prices")
+		}
+	default:
+		return fmt.Errorf("security: price_oracle: unknown on_failure %q (want %q or %q)", po.OnFailure, "deny", "static")
+	}
+	if po.MaxAge < 0 {
+		return fmt.Errorf("security: price_oracle: max_age must not be negative")
+	}
+	return nil
+}
+
+// hasChain reports whether a chain is configured under name, matched
+// case‑insensitively like the enforcer matches it.
+func hasChain(cfg *Config, name string) bool {
+	for chain := range cfg.Chains {
+		if strings.EqualFold(chain, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // EOF: internal/config/loader.go
\ No newline at end of file
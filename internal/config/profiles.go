@@ -45,6 +45,7 @@ func DefaultChainProfiles() map[string]interface{} {
 			"chain_id":        42161,
 			"native_currency": "ETH",
 			"block_time":      "0.25s",
+			"l2":              "arbitrum",
 			"gas_price_limit": "1 gwei",
 			"confirmations":   2,
 			"timeout":         "30s",
@@ -60,6 +61,7 @@ func DefaultChainProfiles() map[string]interface{} {
 			"chain_id":        10,
 			"native_currency": "ETH",
 			"block_time":      "2s",
+			"l2":              "op-stack",
 			"gas_price_limit": "1 gwei",
 			"confirmations":   2,
 			"timeout":         "30s",
@@ -75,6 +77,7 @@ func DefaultChainProfiles() map[string]interface{} {
 			"chain_id":        8453,
 			"native_currency": "ETH",
 			"block_time":      "2s",
+			"l2":              "op-stack",
 			"gas_price_limit": "1 gwei",
 			"confirmations":   2,
 			"timeout":         "30s",
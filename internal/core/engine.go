@@ -15,7 +15,10 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"sync"
 
 	"github.com/0xSemantic/lola-os/internal/blockchain"
@@ -49,11 +52,12 @@ func NewEngine(
 	}
 }
 
-// CreateSession initializes a new agent session and stores it in the engine.
-// The session is automatically logged with its ID.
-// If chain is nil, the session will have no blockchain capabilities.
-func (e *Engine) CreateSession(defaultChainID string, chain blockchain.Chain) *Session {
-	sess := NewSession(e.logger, defaultChainID, chain)
+// CreateSession initializes a new agent session on chains, by name, and
+// stores it in the engine; the chain named defaultChainID is the default.
+// The session is automatically logged with its ID. If chains is empty,
+// the session will have no blockchain capabilities.
+func (e *Engine) CreateSession(defaultChainID string, chains map[string]blockchain.Chain) *Session {
+	sess := NewSessionWithChains(e.logger, defaultChainID, chains)
 
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -61,7 +65,7 @@ func (e *Engine) CreateSession(defaultChainID string, chain blockchain.Chain) *S
 
 	sess.Logger.Info("session created", map[string]interface{}{
 		"default_chain": defaultChainID,
-		"has_chain":     chain != nil,
+		"chains":        sess.ChainNames(),
 	})
 	return sess
 }
@@ -105,26 +109,85 @@ func (e *Engine) Execute(ctx context.Context, toolName string, args map[string]i
 		defer e.CloseSession(sess.ID)
 	}
 
+	// An operation runs on the chain its "chain" argument names, if any,
+	// and is checked by the policies of that chain.
+	chainName := sess.DefaultChainID
+	if raw, ok := args["chain"]; ok {
+		name, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("execute: 'chain' must be a string")
+		}
+		if name != "" {
+			if chainName, _, err = sess.resolveChain(name); err != nil {
+				return nil, fmt.Errorf("execute: %w", err)
+			}
+		}
+	}
+
 	evalCtx := &security.EvaluationContext{
-		Tool:    toolName,
-		Args:    args,
-		Session: sess,
+		Tool:      toolName,
+		Args:      args,
+		Session:   sess,
+		ChainName: chainName,
+	}
+	evalCtx.From = evalCtx.Signer()
+	switch toolName {
+	case "cancel":
+		if args, err = cancelArgs(ctx, evalCtx, args); err != nil {
+			return nil, fmt.Errorf("execute: %w", err)
+		}
+		evalCtx.Args = args
+	case "send_raw":
+		if args, err = sendRawArgs(evalCtx, args); err != nil {
+			return nil, fmt.Errorf("execute: %w", err)
+		}
+		evalCtx.Args = args
 	}
 
 	// 3. Run security policies.
-	if err := e.security.Evaluate(ctx, evalCtx); err != nil {
-		sess.Logger.Warn("security policy blocked execution",
-			map[string]interface{}{"tool": toolName, "reason": err.Error()})
+	// The signing account is recorded with every decision; the enforcer
+	// writes denials, with every policy's decision, to the audit log.
+	signer := evalCtx.From
+	verdict, err := e.security.EvaluateAll(ctx, evalCtx)
+	if err != nil {
+		sess.Logger.Warn("security policy blocked execution", map[string]interface{}{
+			"tool":      toolName,
+			"signer":    signer,
+			"reason":    err.Error(),
+			"decisions": verdict.Decisions,
+		})
 		return nil, fmt.Errorf("execute: security policy denied: %w", err)
 	}
+	if len(verdict.Decisions) > 0 {
+		sess.Logger.Debug("security policies allowed execution", map[string]interface{}{
+			"tool":      toolName,
+			"signer":    signer,
+			"decisions": verdict.Decisions,
+		})
+	}
 
 	// 4. Execute the tool.
 	sess.Logger.Info("executing tool", map[string]interface{}{
-		"tool": toolName,
-		"args": args,
+		"tool":   toolName,
+		"signer": signer,
+		"args":   args,
 	})
 	result, err := tool(ctx, args)
+	// Policies that reserved something for the operation, such as daily
+	// spend, commit it on success and release it on failure.
+	e.security.Settle(ctx, evalCtx, err)
 	if err != nil {
+		var rejected *blockchain.SignerRejectedError
+		if errors.As(err, &rejected) {
+			sess.Logger.Warn("remote signer rejected transaction", map[string]interface{}{
+				"tool":   toolName,
+				"signer": rejected.Account,
+				"url":    rejected.Signer,
+				"code":   rejected.Code,
+				"reason": rejected.Reason,
+			})
+			return nil, fmt.Errorf("execute: tool %q failed: %w", toolName, err)
+		}
 		sess.Logger.Error("tool execution failed",
 			map[string]interface{}{"tool": toolName, "error": err.Error()})
 		return nil, fmt.Errorf("execute: tool %q failed: %w", toolName, err)
@@ -136,4 +199,83 @@ func (e *Engine) Execute(ctx context.Context, toolName string, args map[string]i
 	return result, nil
 }
 
+// cancelArgs returns the arguments of the cancel tool with the transfer
+// it sends filled in, so that policies see it as what it is: a transfer
+// of 0 from the pending transaction's sender to itself, with the same
+// nonce and bumped fees. The sender may be any account of the wallet, not
+// only the signing account. On a chain that can build the replacement
+// (see blockchain.Canceller), it becomes evalCtx.Tx and its sender
+// evalCtx.From, so that fees are estimated and counted for the account
+// that pays them; elsewhere the signing account stands in. Any to, from,
+// amount or nonce given is replaced.
+func cancelArgs(ctx context.Context, evalCtx *security.EvaluationContext, args map[string]interface{}) (map[string]interface{}, error) {
+	filled := make(map[string]interface{}, len(args)+4)
+	for k, v := range args {
+		filled[k] = v
+	}
+	delete(filled, "to")
+	delete(filled, "from")
+	delete(filled, "nonce")
+	filled["amount"] = big.NewInt(0)
+
+	canceller, ok := evalCtx.Chain().(blockchain.Canceller)
+	txHash, isString := args["tx_hash"].(string)
+	if !ok || !isString {
+		if evalCtx.From != "" {
+			filled["to"] = evalCtx.From
+		}
+		return filled, nil
+	}
+	tx, err := canceller.CancellationTransaction(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("cancel: %w", err)
+	}
+	evalCtx.Tx = tx
+	evalCtx.From = tx.From
+	filled["from"] = tx.From
+	filled["to"] = tx.From
+	if tx.Nonce != nil {
+		filled["nonce"] = *tx.Nonce
+	}
+	return filled, nil
+}
+
+// sendRawArgs returns the arguments of the send_raw tool with "from" set
+// to the sender recovered from the raw transaction, which also becomes
+// evalCtx.From, so that policies charge the account that signed it rather
+// than the wallet. A "from" argument naming another account is an error.
+// On a chain that cannot recover senders (see blockchain.RawSender), args
+// are returned unchanged.
+func sendRawArgs(evalCtx *security.EvaluationContext, args map[string]interface{}) (map[string]interface{}, error) {
+	recoverer, ok := evalCtx.Chain().(blockchain.RawSender)
+	if !ok {
+		return args, nil
+	}
+	var raw []byte
+	switch v := args["raw"].(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return args, nil // the tool rejects it
+	}
+	sender, err := recoverer.RawTransactionSender(raw)
+	if err != nil {
+		return nil, fmt.Errorf("send_raw: %w", err)
+	}
+	if from, ok := args["from"]; ok {
+		if s, isString := from.(string); !isString || !strings.EqualFold(s, sender) {
+			return nil, errors.New("send_raw: 'from' argument does not match the raw transaction")
+		}
+	}
+	filled := make(map[string]interface{}, len(args)+1)
+	for k, v := range args {
+		filled[k] = v
+	}
+	filled["from"] = sender
+	evalCtx.From = sender
+	return filled, nil
+}
+
 // EOF: internal/core/engine.go
\ No newline at end of file
@@ -6,12 +6,18 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/0xSemantic/lola-os/internal/blockchain"
 	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
 )
 
 // Session holds per‑invocation context for an agent run.
@@ -27,19 +33,48 @@ type Session struct {
 	// Logger is a child logger pre‑populated with the session ID.
 	Logger observe.Logger
 
-	// DefaultChainID (optional) indicates which blockchain the agent prefers.
+	// DefaultChainID (optional) is the name of the chain operations run on
+	// unless they name another.
 	DefaultChainID string
 
-	// Chain is the blockchain interface used by tools during this session.
-	// May be nil if no blockchain is available (read‑only mode still possible?).
+	// Chain is the default chain, used by tools during this session unless
+	// an operation names another. May be nil if no blockchain is available.
 	Chain blockchain.Chain
+
+	// chains are every chain of the session, by configured name, the
+	// default one included.
+	chains map[string]blockchain.Chain
+
+	// budget caps what the session may spend (nil = no cap). It is kept
+	// nowhere else, so it ends with the session.
+	budget *security.Budget
 }
 
+// ErrNoChain is returned by ChainByName for a session without the chain
+// asked for by default.
+var ErrNoChain = errors.New("no blockchain chain available in session")
+
 // NewSession creates a new session with a fresh UUID and a logger that includes
-// the session ID as a structured field.
+// the session ID as a structured field. chain, if not nil, is its only
+// chain, named defaultChainID.
 func NewSession(logger observe.Logger, defaultChainID string, chain blockchain.Chain) *Session {
+	var chains map[string]blockchain.Chain
+	if chain != nil {
+		chains = map[string]blockchain.Chain{defaultChainID: chain}
+	}
+	return NewSessionWithChains(logger, defaultChainID, chains)
+}
+
+// NewSessionWithChains is NewSession for a session on several chains, by
+// name; the one named defaultChainID is the default.
+func NewSessionWithChains(logger observe.Logger, defaultChainID string, chains map[string]blockchain.Chain) *Session {
 	sessionID := uuid.New().String()
 
+	copied := make(map[string]blockchain.Chain, len(chains))
+	for name, chain := range chains {
+		copied[name] = chain
+	}
+
 	sessionLogger := logger.With(map[string]interface{}{
 		"session_id": sessionID,
 	})
@@ -49,13 +84,84 @@ func NewSession(logger observe.Logger, defaultChainID string, chain blockchain.C
 		CreatedAt:      time.Now().UTC(),
 		Logger:         sessionLogger,
 		DefaultChainID: defaultChainID,
-		Chain:          chain,
+		Chain:          chains[defaultChainID],
+		chains:         copied,
 	}
 }
 
-// SetChain updates the blockchain interface for this session.
+// SetChain updates the default chain of this session.
 func (s *Session) SetChain(chain blockchain.Chain) {
 	s.Chain = chain
+	if s.chains == nil {
+		s.chains = make(map[string]blockchain.Chain)
+	}
+	s.chains[s.DefaultChainID] = chain
+}
+
+// ChainByName returns the session's chain configured under name,
+// case‑insensitively, or the default chain if name is empty.
+func (s *Session) ChainByName(name string) (blockchain.Chain, error) {
+	_, chain, err := s.resolveChain(name)
+	return chain, err
+}
+
+// ChainNames returns the names of the session's chains, sorted.
+func (s *Session) ChainNames() []string {
+	names := make([]string, 0, len(s.chains))
+	for name, chain := range s.chains {
+		if chain != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveChain returns the configured name of the chain name refers to,
+// and the chain.
+func (s *Session) resolveChain(name string) (string, blockchain.Chain, error) {
+	if name == "" {
+		if s.Chain == nil {
+			return "", nil, ErrNoChain
+		}
+		return s.DefaultChainID, s.Chain, nil
+	}
+	if chain := s.chains[name]; chain != nil {
+		return name, chain, nil
+	}
+	for configured, chain := range s.chains {
+		if chain != nil && strings.EqualFold(configured, name) {
+			return configured, chain, nil
+		}
+	}
+	return "", nil, fmt.Errorf("unknown chain %q (session has %s)", name, strings.Join(s.ChainNames(), ", "))
+}
+
+// SetBudget caps what operations in this session may spend on each of its
+// chains, in wei of the chain's native currency, fees included, whatever
+// the daily limits allow; the session_budget policy enforces it. Call it
+// before the session's first operation.
+func (s *Session) SetBudget(limit *big.Int) {
+	s.budget = security.NewBudget(limit)
+}
+
+// Budget implements security.BudgetSession: it returns the session's
+// budget, or nil if it has none.
+func (s *Session) Budget() *security.Budget { return s.budget }
+
+// RemainingBudget returns what the session may still spend on the chain
+// named chain, or its default chain if chain is empty, in wei, and false
+// if it has no budget or no chain of that name. Spends of operations still
+// running are counted, so an agent can check before acting.
+func (s *Session) RemainingBudget(chain string) (*big.Int, bool) {
+	if s.budget == nil {
+		return nil, false
+	}
+	name, _, err := s.resolveChain(chain)
+	if err != nil && chain != "" {
+		return nil, false
+	}
+	return s.budget.Remaining(name), true
 }
 
 // SessionFromContext extracts the Session from the context.
@@ -77,4 +183,7 @@ type sessionContextKey struct{}
 
 func (s *Session) GetID() string { return s.ID }
 
+// GetChain returns the session's default chain, which may be nil.
+func (s *Session) GetChain() blockchain.Chain { return s.Chain }
+
 // EOF: internal/core/session.go
\ No newline at end of file
@@ -13,19 +13,39 @@ import (
 	"time"
 )
 
+// Audit entry kinds.
+const (
+	AuditKindTx             = "tx"              // an onchain write
+	AuditKindPolicyDecision = "policy_decision" // a policy denial, or a figure a policy decided on
+	AuditKindApproval       = "approval"        // a human approval was requested or resolved
+	AuditKindAutoApproval   = "auto_approval"   // a rule approved an operation without asking
+	AuditKindWouldDeny      = "would_deny"      // an advisory policy denied an operation it let through
+	AuditKindPause          = "pause"           // writes were paused or resumed
+)
+
 // AuditEntry represents a single audit record.
 type AuditEntry struct {
-	Timestamp   time.Time              `json:"timestamp"`
-	SessionID   string                 `json:"session_id"`
-	AgentName   string                 `json:"agent_name,omitempty"`
-	Chain       string                 `json:"chain"`
-	TxHash      string                 `json:"tx_hash"`
-	From        string                 `json:"from"`
-	To          string                 `json:"to"`
-	Value       string                 `json:"value,omitempty"` // wei as string
-	Data        string                 `json:"data,omitempty"`  // hex
-	PolicyResults []string             `json:"policy_results,omitempty"`
-	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Timestamp     time.Time              `json:"timestamp"`
+	Kind          string                 `json:"kind,omitempty"` // an AuditKind constant
+	SessionID     string                 `json:"session_id"`
+	AgentName     string                 `json:"agent_name,omitempty"`
+	Chain         string                 `json:"chain"`
+	TxHash        string                 `json:"tx_hash"`
+	From          string                 `json:"from"`
+	To            string                 `json:"to"`
+	Value         string                 `json:"value,omitempty"` // wei as string
+	Data          string                 `json:"data,omitempty"`  // hex
+	PolicyResults []PolicyResult         `json:"policy_results,omitempty"`
+	Extra         map[string]interface{} `json:"extra,omitempty"`
+}
+
+// PolicyResult is the outcome of one policy for an audited operation.
+type PolicyResult struct {
+	Policy   string      `json:"policy"`
+	Allowed  bool        `json:"allowed"`
+	Reason   string      `json:"reason,omitempty"`   // why it denied
+	Advisory bool        `json:"advisory,omitempty"` // its denial did not block
+	Details  interface{} `json:"details,omitempty"`  // what the policy based its decision on
 }
 
 // AuditLogger is an append‑only audit log for onchain write operations.
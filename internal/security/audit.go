@@ -0,0 +1,93 @@
+// Package security provides the audit records of policy decisions.
+//
+// File: internal/security/audit.go
+
+package security
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// AuditEntry returns an audit entry of kind for the operation, with its
+// session, chain, signer, recipient and value filled in.
+func (e *EvaluationContext) AuditEntry(kind string) *observe.AuditEntry {
+	entry := &observe.AuditEntry{Kind: kind, Chain: e.ChainName, From: e.Signer()}
+	if s, ok := e.Session.(interface{ GetID() string }); ok {
+		entry.SessionID = s.GetID()
+	}
+	if to, ok := e.Args["to"].(string); ok {
+		entry.To = to
+	}
+	if amount, ok := e.Args["amount"].(*big.Int); ok {
+		entry.Value = amount.String()
+	}
+	return entry
+}
+
+// PolicyName names policy in decisions and audit entries: its Name method
+// if it has one, as the built‑in policies do, else its type, e.g.
+// "CustomPolicy" for a *CustomPolicy.
+func PolicyName(policy Policy) string {
+	if n, ok := policy.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return typeName(policy)
+}
+
+// maxArgLen bounds each argument in an args summary.
+const maxArgLen = 80
+
+// sensitiveArgs are argument name parts whose values are never recorded.
+var sensitiveArgs = []string{"key", "passphrase", "password", "secret", "mnemonic", "seed"}
+
+// SummarizeArgs renders args for an audit entry: each value as text, cut
+// to a readable length, with byte slices as hex and secrets redacted.
+func SummarizeArgs(args map[string]interface{}) map[string]string {
+	if len(args) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	summary := make(map[string]string, len(args))
+	for _, name := range names {
+		summary[name] = summarizeArg(name, args[name])
+	}
+	return summary
+}
+
+func summarizeArg(name string, v interface{}) string {
+	lower := strings.ToLower(name)
+	for _, s := range sensitiveArgs {
+		if strings.Contains(lower, s) {
+			return "<redacted>"
+		}
+	}
+	var s string
+	switch v := v.(type) {
+	case []byte:
+		s = "0x" + hex.EncodeToString(v)
+		if len(s) > maxArgLen {
+			return fmt.Sprintf("%s… (%d bytes)", s[:maxArgLen], len(v))
+		}
+		return s
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		s = fmt.Sprint(v)
+	}
+	if len(s) > maxArgLen {
+		s = s[:maxArgLen] + "…"
+	}
+	return s
+}
+
+// EOF: internal/security/audit.go
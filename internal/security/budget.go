@@ -0,0 +1,113 @@
+// Package security provides the spending budget of an agent session.
+//
+// File: internal/security/budget.go
+
+package security
+
+import (
+	"math/big"
+	"sync"
+)
+
+// Budget caps the wei an agent session may spend on each chain, whatever
+// the daily limits allow. Each chain has its own count against the same
+// cap, so amounts in different native currencies are never added up. A
+// policy reserves a spend when it allows an operation and commits or
+// releases it once the operation ends, so a send that fails does not use
+// up the budget. It is safe for concurrent use.
+type Budget struct {
+	mu       sync.Mutex
+	limit    *big.Int
+	spent    map[string]*big.Int // chain name -> committed
+	reserved map[string]*big.Int // chain name -> allowed, not settled yet
+}
+
+// NewBudget creates a budget of limit wei per chain, none of it spent.
+func NewBudget(limit *big.Int) *Budget {
+	return &Budget{
+		limit:    new(big.Int).Set(limit),
+		spent:    make(map[string]*big.Int),
+		reserved: make(map[string]*big.Int),
+	}
+}
+
+// BudgetSession is implemented by sessions that may carry a budget, such
+// as core.Session.
+type BudgetSession interface {
+	// Budget returns the session's budget, or nil if it has none.
+	Budget() *Budget
+}
+
+// Budget returns the budget of the evaluated session, or nil if it has
+// none.
+func (e *EvaluationContext) Budget() *Budget {
+	if bs, ok := e.Session.(BudgetSession); ok {
+		return bs.Budget()
+	}
+	return nil
+}
+
+// Limit returns the budget's cap on each chain.
+func (b *Budget) Limit() *big.Int {
+	return new(big.Int).Set(b.limit)
+}
+
+// Spent returns what operations that succeeded have spent on chain.
+func (b *Budget) Spent(chain string) *big.Int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.total(b.spent, chain)
+}
+
+// Remaining returns what is left to spend on chain: the cap less what is
+// spent and reserved there, and never below zero.
+func (b *Budget) Remaining(chain string) *big.Int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	left := new(big.Int).Sub(b.limit, b.total(b.spent, chain))
+	left.Sub(left, b.total(b.reserved, chain))
+	if left.Sign() < 0 {
+		left.SetInt64(0)
+	}
+	return left
+}
+
+// Reserve reserves amount on chain unless it is more than what remains
+// there. It returns what was spent and reserved on chain before, and
+// whether amount was reserved.
+func (b *Budget) Reserve(chain string, amount *big.Int) (*big.Int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	used := new(big.Int).Add(b.total(b.spent, chain), b.total(b.reserved, chain))
+	if new(big.Int).Add(used, amount).Cmp(b.limit) > 0 {
+		return used, false
+	}
+	b.reserved[chain] = new(big.Int).Add(b.total(b.reserved, chain), amount)
+	return used, true
+}
+
+// Commit turns amount, reserved on chain by Reserve, into spend.
+func (b *Budget) Commit(chain string, amount *big.Int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reserved[chain] = new(big.Int).Sub(b.total(b.reserved, chain), amount)
+	b.spent[chain] = new(big.Int).Add(b.total(b.spent, chain), amount)
+}
+
+// Release gives amount, reserved on chain by Reserve, back to the budget.
+func (b *Budget) Release(chain string, amount *big.Int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reserved[chain] = new(big.Int).Sub(b.total(b.reserved, chain), amount)
+}
+
+// total returns a copy of the amount of chain in m, zero if none; b.mu
+// must be held.
+func (b *Budget) total(m map[string]*big.Int, chain string) *big.Int {
+	if v, ok := m[chain]; ok {
+		return new(big.Int).Set(v)
+	}
+	return new(big.Int)
+}
+
+// EOF: internal/security/budget.go
@@ -0,0 +1,124 @@
+// Package security provides the adapter that runs policies written
+// against the SDK, which see an operation as an Evaluation, with the
+// built‑in ones.
+//
+// File: internal/security/custom.go
+
+package security
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Evaluation is the view of an operation given to a CustomPolicy: what
+// EvaluationContext knows about it, without the internal types.
+type Evaluation struct {
+	// Tool is the tool the operation runs: "transfer", "send" (which
+	// SendTransaction and contract bindings run), "sign", "send_raw",
+	// "deploy", and so on.
+	Tool string
+	// Args are the tool's arguments. The map is a copy; changing it does
+	// not change the operation.
+	Args map[string]interface{}
+	// Chain is the name of the configured chain the operation runs on, ""
+	// if unknown.
+	Chain string
+	// From is the address the operation signs as, "" if unknown.
+	From string
+	// To is the recipient of the transaction, checksummed if it is an
+	// address, or the ENS name it was given as; "" for a deployment or an
+	// operation that sends no transaction.
+	To string
+	// Value is the wei the transaction sends, zero if none.
+	Value *big.Int
+	// Data is the transaction's calldata, or the creation code of a
+	// deployment.
+	Data []byte
+	// SessionID is the id of the agent session, "" if unknown.
+	SessionID string
+}
+
+// Evaluation returns a new Evaluation of the operation.
+func (e *EvaluationContext) Evaluation() *Evaluation {
+	tx := e.Transaction()
+	eval := &Evaluation{
+		Tool:  e.Tool,
+		Args:  make(map[string]interface{}, len(e.Args)),
+		Chain: e.ChainName,
+		From:  e.Signer(),
+		Value: new(big.Int),
+		Data:  append([]byte(nil), tx.Data...),
+	}
+	for k, v := range e.Args {
+		eval.Args[k] = v
+	}
+	if eval.From != "" && common.IsHexAddress(eval.From) {
+		eval.From = common.HexToAddress(eval.From).Hex()
+	}
+	if tx.To != nil {
+		eval.To = *tx.To
+		if common.IsHexAddress(eval.To) {
+			eval.To = common.HexToAddress(eval.To).Hex()
+		}
+	}
+	if tx.Value != nil {
+		eval.Value.Set(tx.Value)
+	}
+	if s, ok := e.Session.(interface{ GetID() string }); ok {
+		eval.SessionID = s.GetID()
+	}
+	return eval
+}
+
+// CustomPolicy is a security rule written against the SDK. It returns nil
+// if the operation is allowed, otherwise an error describing the denial.
+type CustomPolicy interface {
+	Check(ctx context.Context, eval *Evaluation) error
+}
+
+// CustomPolicyFunc adapts a function to a CustomPolicy.
+type CustomPolicyFunc func(ctx context.Context, eval *Evaluation) error
+
+// Check calls f.
+func (f CustomPolicyFunc) Check(ctx context.Context, eval *Evaluation) error { return f(ctx, eval) }
+
+// AdaptCustomPolicy returns a Policy that runs p. Its decisions are named
+// after p's Name method if it has one, else p's type (see PolicyName).
+func AdaptCustomPolicy(p CustomPolicy) Policy {
+	return &customPolicy{policy: p}
+}
+
+// customPolicy runs a CustomPolicy as a Policy.
+type customPolicy struct {
+	policy CustomPolicy
+}
+
+// Name names the policy after the custom one.
+func (p *customPolicy) Name() string {
+	if n, ok := p.policy.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return typeName(p.policy)
+}
+
+// Check implements Policy.
+func (p *customPolicy) Check(ctx context.Context, evalCtx *EvaluationContext) error {
+	return p.policy.Check(ctx, evalCtx.Evaluation())
+}
+
+// typeName returns the name of v's type without its package, e.g.
+// "CustomPolicy" for a *CustomPolicy.
+func typeName(v interface{}) string {
+	name := fmt.Sprintf("%T", v)
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// EOF: internal/security/custom.go
@@ -0,0 +1,112 @@
+// Package security_test tests the adapter of SDK policies.
+//
+// File: internal/security/custom_test.go
+
+package security_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// idSession is a session that only has an id.
+type idSession string
+
+func (s idSession) GetID() string { return string(s) }
+
+// fridayPolicy denies transfers above a limit, recording what it saw.
+type fridayPolicy struct {
+	limit *big.Int
+	seen  []*security.Evaluation
+}
+
+func (p *fridayPolicy) Name() string { return "no_big_fridays" }
+
+func (p *fridayPolicy) Check(ctx context.Context, eval *security.Evaluation) error {
+	p.seen = append(p.seen, eval)
+	if eval.Value.Cmp(p.limit) > 0 {
+		return errors.New("too much for a friday")
+	}
+	return nil
+}
+
+func TestEvaluationContext_Evaluation(t *testing.T) {
+	amount := big.NewInt(5)
+	for _, tc := range []struct {
+		name string
+		ctx  *security.EvaluationContext
+		want security.Evaluation
+	}{
+		{
+			name: "transfer",
+			ctx: &security.EvaluationContext{Tool: "transfer", ChainName: "base", Session: idSession("s1"),
+				Args: map[string]interface{}{"to": "0x742d35cc6634c0532925a3b844bc9e90f1a6b1e7", "amount": amount}},
+			want: security.Evaluation{Tool: "transfer", Chain: "base", SessionID: "s1",
+				To: "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", Value: amount, Data: []byte{}},
+		},
+		{
+			name: "send",
+			ctx: &security.EvaluationContext{Tool: "send", From: "0x1306b01bc3e4ad202612d3843387e94737673f53",
+				Args: map[string]interface{}{"to": "vitalik.eth", "data": []byte{0xa9, 0x05, 0x9c, 0xbb}}},
+			want: security.Evaluation{Tool: "send", From: "0x1306b01bC3e4AD202612D3843387e94737673F53",
+				To: "vitalik.eth", Value: new(big.Int), Data: []byte{0xa9, 0x05, 0x9c, 0xbb}},
+		},
+		{
+			name: "deploy",
+			ctx:  &security.EvaluationContext{Tool: "deploy", Args: map[string]interface{}{"bytecode": "0x6080"}},
+			want: security.Evaluation{Tool: "deploy", Value: new(big.Int), Data: []byte{0x60, 0x80}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			eval := tc.ctx.Evaluation()
+			assert.Equal(t, tc.ctx.Args, eval.Args)
+			tc.want.Args = eval.Args
+			if len(tc.want.Data) == 0 {
+				tc.want.Data = eval.Data
+				assert.Empty(t, eval.Data)
+			}
+			assert.Equal(t, tc.want, *eval)
+		})
+	}
+
+	// Policies cannot change the operation.
+	evalCtx := &security.EvaluationContext{Tool: "transfer", Args: map[string]interface{}{"amount": amount}}
+	eval := evalCtx.Evaluation()
+	eval.Args["amount"] = big.NewInt(1000)
+	eval.Value.SetInt64(1000)
+	assert.Equal(t, big.NewInt(5), evalCtx.Args["amount"])
+}
+
+func TestAdaptCustomPolicy(t *testing.T) {
+	ctx := context.Background()
+	friday := &fridayPolicy{limit: big.NewInt(10)}
+	e := security.NewEnforcer()
+	e.AddPolicy(security.AdaptCustomPolicy(friday))
+	e.AddPolicy(security.AdaptCustomPolicy(security.CustomPolicyFunc(func(ctx context.Context, eval *security.Evaluation) error {
+		return nil
+	})))
+	send := func(wei int64) *security.EvaluationContext {
+		return &security.EvaluationContext{Tool: "transfer", Args: map[string]interface{}{"to": "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", "amount": big.NewInt(wei)}}
+	}
+
+	result, err := e.EvaluateAll(ctx, send(10))
+	require.NoError(t, err)
+	require.Len(t, result.Decisions, 2)
+	assert.Equal(t, "no_big_fridays", result.Decisions[0].PolicyName)
+	assert.Equal(t, "CustomPolicyFunc", result.Decisions[1].PolicyName)
+
+	_, err = e.EvaluateAll(ctx, send(11))
+	var denied *security.ErrPolicyDenied
+	require.ErrorAs(t, err, &denied)
+	assert.Equal(t, "no_big_fridays", denied.Decision.PolicyName)
+	assert.Equal(t, "too much for a friday", denied.Decision.Reason)
+	require.Len(t, friday.seen, 2)
+	assert.Equal(t, big.NewInt(11), friday.seen[1].Value)
+}
@@ -1,5 +1,7 @@
 // Package security provides a pluggable policy enforcer.
-// It aggregates multiple policies and evaluates them; all must allow.
+// It aggregates multiple policies and evaluates them; all must allow,
+// except advisory policies, whose denials are only reported. Every policy
+// is run, so that a denial explains everything wrong with an operation.
 //
 // File: internal/security/enforcer.go
 
@@ -7,46 +9,287 @@ package security
 
 import (
 	"context"
-	"fmt"
+	"sort"
+	"strings"
 	"sync"
+
+	"github.com/0xSemantic/lola-os/internal/observe"
 )
 
 // Enforcer aggregates and evaluates security policies.
 // It is safe for concurrent use.
 type Enforcer struct {
 	mu       sync.RWMutex
-	policies []Policy
+	policies []scopedPolicy
+	audit    *observe.AuditLogger // nil = denials not recorded
+	logger   observe.Logger       // nil = advisory denials not logged
+	metrics  observe.Metrics      // nil = advisory denials not counted
+	short    bool                 // stop at the first denial
+	kill     pauseState           // why writes are paused, if they are
+}
+
+// scopedPolicy is a policy, the chains it applies to and how it runs.
+type scopedPolicy struct {
+	policy   Policy
+	chains   []string // nil = every chain
+	advisory bool
+	terminal bool
+	priority int
+}
+
+// PolicyOptions sets how the enforcer runs a policy.
+type PolicyOptions struct {
+	// Chains are the chains the policy applies to, case‑insensitively
+	// (empty = every chain). An operation whose chain is not known is
+	// checked by every policy.
+	Chains []string
+	// Advisory turns the policy's denials into warnings: the denial is
+	// logged, recorded in the audit log and counted in
+	// policy_would_deny_total, and does not block the operation.
+	Advisory bool
+	// Terminal stops evaluation when the policy denies an operation.
+	Terminal bool
+	// Priority orders the policy among the others: lower runs first, and
+	// policies of equal priority run in the order they were added.
+	Priority int
 }
 
 // NewEnforcer creates an empty enforcer.
 func NewEnforcer() *Enforcer {
 	return &Enforcer{
-		policies: make([]Policy, 0),
+		policies: make([]scopedPolicy, 0),
 	}
 }
 
-// AddPolicy appends a policy to the enforcer.
+// AddPolicy appends a policy to the enforcer that applies on every chain.
 func (e *Enforcer) AddPolicy(policy Policy) {
+	e.AddPolicyForChains(policy, nil)
+}
+
+// AddPolicyForChains appends a policy to the enforcer that applies only to
+// operations on the named chains (case‑insensitively), or on every chain
+// if chains is empty. An operation whose chain is not known is checked by
+// every policy.
+func (e *Enforcer) AddPolicyForChains(policy Policy, chains []string) {
+	e.AddPolicyWithOptions(policy, PolicyOptions{Chains: chains})
+}
+
+// AddPolicyWithOptions appends a policy to the enforcer, run as opts says.
+func (e *Enforcer) AddPolicyWithOptions(policy Policy, opts PolicyOptions) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	chains := opts.Chains
+	if len(chains) == 0 {
+		chains = nil
+	}
+	e.policies = append(e.policies, scopedPolicy{
+		policy:   policy,
+		chains:   chains,
+		advisory: opts.Advisory,
+		terminal: opts.Terminal,
+		priority: opts.Priority,
+	})
+	sort.SliceStable(e.policies, func(i, j int) bool { return e.policies[i].priority < e.policies[j].priority })
+}
+
+// SetAudit sets the log that records every denial, with the outcome of
+// each policy evaluated and a summary of the operation's arguments, and
+// every pause and resumption of writes.
+func (e *Enforcer) SetAudit(audit *observe.AuditLogger) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.audit = audit
+}
+
+// SetLogger sets the logger that warns of advisory denials and of writes
+// being paused.
+func (e *Enforcer) SetLogger(logger observe.Logger) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.logger = logger
+}
+
+// SetMetrics sets the metrics that count advisory denials per policy.
+func (e *Enforcer) SetMetrics(metrics observe.Metrics) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metrics = metrics
+}
+
+// SetShortCircuit sets whether evaluation stops at the first denial
+// instead of running the remaining policies, as if every policy were
+// terminal.
+func (e *Enforcer) SetShortCircuit(short bool) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-	e.policies = append(e.policies, policy)
+	e.short = short
 }
 
-// Evaluate runs all policies against the given context.
-// If any policy returns an error, evaluation stops immediately and that error is returned.
-// Returns nil if all policies allow the operation.
+// Evaluate is EvaluateAll without the decisions: it returns nil if the
+// operation may proceed, else the error of the first denial.
 func (e *Enforcer) Evaluate(ctx context.Context, evalCtx *EvaluationContext) error {
+	_, err := e.EvaluateAll(ctx, evalCtx)
+	return err
+}
+
+// EvaluateAll runs the policies that apply to the operation's chain against
+// the given context, by priority, and returns the decision of each, in
+// order.
+//
+// Every policy runs, unless a terminal policy denies the operation, or
+// short‑circuiting stops at the first denial. Policies that ask a human
+// (see Prompter) do not run once the operation is denied. Denials by
+// advisory policies are reported and do not block. If the operation is
+// denied, the error is an *ErrPolicyDenied for the first denial, and the
+// policies that allowed it are settled with it.
+//
+// While writes are paused (see Pause), a write operation is denied by
+// "kill_switch", wrapping an *ErrPaused, before any policy runs.
+func (e *Enforcer) EvaluateAll(ctx context.Context, evalCtx *EvaluationContext) (*EvaluationResult, error) {
+	kill := killSwitch{e}
+	if err := kill.Check(ctx, evalCtx); err != nil {
+		decision := PolicyDecision{PolicyName: kill.Name(), Allowed: false, Reason: err.Error()}
+		result := &EvaluationResult{Decisions: []PolicyDecision{decision}, Allowed: false}
+		e.record(evalCtx, result, false)
+		return result, &ErrPolicyDenied{Policy: kill, Decision: decision, Err: err}
+	}
 	e.mu.RLock()
-	policies := make([]Policy, len(e.policies))
-	copy(policies, e.policies)
+	short := e.short
 	e.mu.RUnlock()
+	policies := e.snapshot(evalCtx)
+	result := &EvaluationResult{Decisions: make([]PolicyDecision, 0, len(policies)), Allowed: true}
+	var allowed []scopedPolicy
+	var denial error
+	advised := false
+	for _, sp := range policies {
+		if denial != nil {
+			if p, ok := sp.policy.(Prompter); ok && p.Prompts() {
+				continue
+			}
+		}
+		decision := PolicyDecision{PolicyName: PolicyName(sp.policy), Allowed: true, Advisory: sp.advisory}
+		err := sp.policy.Check(ctx, evalCtx)
+		if err != nil {
+			decision.Allowed, decision.Reason = false, err.Error()
+		}
+		if r, ok := sp.policy.(Reporter); ok {
+			decision.Details = r.Report(evalCtx)
+		}
+		result.Decisions = append(result.Decisions, decision)
+		switch {
+		case err == nil:
+			allowed = append(allowed, sp)
+		case sp.advisory:
+			advised = true
+			e.warnWouldDeny(evalCtx, decision)
+		case denial == nil:
+			result.Allowed = false
+			denial = &ErrPolicyDenied{Policy: sp.policy, Decision: decision, Err: err}
+		}
+		if err != nil && !sp.advisory && (short || sp.terminal) {
+			break
+		}
+	}
+	if denial != nil {
+		e.record(evalCtx, result, false)
+		settle(ctx, allowed, evalCtx, denial)
+		return result, denial
+	}
+	if advised {
+		e.record(evalCtx, result, true)
+	}
+	return result, nil
+}
+
+// Settle reports the outcome of an operation that Evaluate allowed to the
+// policies implementing Settler; err is nil if the tool succeeded.
+func (e *Enforcer) Settle(ctx context.Context, evalCtx *EvaluationContext, err error) {
+	settle(ctx, e.snapshot(evalCtx), evalCtx, err)
+}
+
+// warnWouldDeny logs and counts the advisory denial d.
+func (e *Enforcer) warnWouldDeny(evalCtx *EvaluationContext, d PolicyDecision) {
+	e.mu.RLock()
+	logger, metrics := e.logger, e.metrics
+	e.mu.RUnlock()
+	if logger != nil {
+		logger.Warn("policy would deny operation (advisory)", map[string]interface{}{
+			"policy": d.PolicyName,
+			"tool":   evalCtx.Tool,
+			"chain":  evalCtx.ChainName,
+			"reason": d.Reason,
+		})
+	}
+	if metrics != nil {
+		metrics.Counter("policy_would_deny_total", 1, map[string]string{"policy": d.PolicyName})
+	}
+}
+
+// record writes the audit entry for result: a denial, or if advisory, an
+// operation that went ahead although advisory policies denied it. The
+// entry names the policies that denied. Audit failures do not change the
+// outcome.
+func (e *Enforcer) record(evalCtx *EvaluationContext, result *EvaluationResult, advisory bool) {
+	e.mu.RLock()
+	audit := e.audit
+	e.mu.RUnlock()
+	if audit == nil {
+		return
+	}
+	kind, decision := observe.AuditKindPolicyDecision, "denied"
+	if advisory {
+		kind, decision = observe.AuditKindWouldDeny, "would_deny"
+	}
+	var denied []string
+	for _, d := range result.Decisions {
+		if !d.Allowed && d.Advisory == advisory {
+			denied = append(denied, d.PolicyName)
+		}
+	}
+	entry := evalCtx.AuditEntry(kind)
+	entry.PolicyResults = result.AuditResults()
+	entry.Extra = map[string]interface{}{
+		"tool":      evalCtx.Tool,
+		"decision":  decision,
+		"policy":    denied[0],
+		"denied_by": denied,
+		"args":      SummarizeArgs(evalCtx.Args),
+	}
+	_ = audit.Log(entry)
+}
+
+// snapshot returns a copy of the policies that apply to evalCtx's chain.
+func (e *Enforcer) snapshot(evalCtx *EvaluationContext) []scopedPolicy {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	policies := make([]scopedPolicy, 0, len(e.policies))
+	for _, sp := range e.policies {
+		if sp.appliesTo(evalCtx.ChainName) {
+			policies = append(policies, sp)
+		}
+	}
+	return policies
+}
+
+// appliesTo reports whether the policy applies on chain.
+func (sp scopedPolicy) appliesTo(chain string) bool {
+	if sp.chains == nil || chain == "" {
+		return true
+	}
+	for _, c := range sp.chains {
+		if strings.EqualFold(c, chain) {
+			return true
+		}
+	}
+	return false
+}
 
-	for _, p := range policies {
-		if err := p.Check(ctx, evalCtx); err != nil {
-			return fmt.Errorf("policy %T: %w", p, err)
+func settle(ctx context.Context, policies []scopedPolicy, evalCtx *EvaluationContext, err error) {
+	for _, sp := range policies {
+		if s, ok := sp.policy.(Settler); ok {
+			s.Settle(ctx, evalCtx, err)
 		}
 	}
-	return nil
 }
 
 // EOF: internal/security/enforcer.go
\ No newline at end of file
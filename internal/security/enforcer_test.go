@@ -6,12 +6,20 @@ package security_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/0xSemantic/lola-os/internal/observe"
 	"github.com/0xSemantic/lola-os/internal/security"
 )
 
@@ -48,8 +56,66 @@ func TestEnforcer_AllAllow(t *testing.T) {
 	p2.AssertExpectations(t)
 }
 
-func TestEnforcer_FirstDenies(t *testing.T) {
+// settlingPolicy allows every operation and records how each ended.
+type settlingPolicy struct {
+	settled []error
+}
+
+func (p *settlingPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	return nil
+}
+
+func (p *settlingPolicy) Settle(ctx context.Context, evalCtx *security.EvaluationContext, err error) {
+	p.settled = append(p.settled, err)
+}
+
+// promptingPolicy stands for a policy that asks a human.
+type promptingPolicy struct {
+	MockPolicy
+}
+
+func (p *promptingPolicy) Prompts() bool { return true }
+
+func TestEnforcer_AllDecisions(t *testing.T) {
+	e := security.NewEnforcer()
+	p1 := new(MockPolicy)
+	p2 := new(MockPolicy)
+	later := &settlingPolicy{}
+	human := new(promptingPolicy)
+
+	denyErr := errors.New("denied")
+	p1.On("Check", mock.Anything, mock.Anything).Return(denyErr)
+	p2.On("Check", mock.Anything, mock.Anything).Return(errors.New("also denied"))
+	// human should not be asked about a denied operation.
+
+	e.AddPolicy(p1)
+	e.AddPolicy(p2)
+	e.AddPolicy(later)
+	e.AddPolicy(human)
+
+	result, err := e.EvaluateAll(context.Background(), &security.EvaluationContext{})
+	assert.ErrorIs(t, err, denyErr, "the first denial")
+	var policyErr *security.ErrPolicyDenied
+	require.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, p1, policyErr.Policy)
+	assert.Equal(t, security.PolicyDecision{PolicyName: "MockPolicy", Reason: "denied"}, policyErr.Decision)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, []security.PolicyDecision{
+		{PolicyName: "MockPolicy", Reason: "denied"},
+		{PolicyName: "MockPolicy", Reason: "also denied"},
+		{PolicyName: "settlingPolicy", Allowed: true},
+	}, result.Decisions)
+	assert.Len(t, result.Denials(), 2)
+
+	// A policy that allowed the operation after the denial learns of it.
+	require.Len(t, later.settled, 1)
+	assert.ErrorAs(t, later.settled[0], &policyErr)
+	human.AssertNotCalled(t, "Check")
+}
+
+func TestEnforcer_ShortCircuit(t *testing.T) {
 	e := security.NewEnforcer()
+	e.SetShortCircuit(true)
 	p1 := new(MockPolicy)
 	p2 := new(MockPolicy)
 
@@ -60,11 +126,249 @@ func TestEnforcer_FirstDenies(t *testing.T) {
 	e.AddPolicy(p1)
 	e.AddPolicy(p2)
 
-	err := e.Evaluate(context.Background(), &security.EvaluationContext{})
+	result, err := e.EvaluateAll(context.Background(), &security.EvaluationContext{})
 	assert.ErrorIs(t, err, denyErr)
+	assert.Len(t, result.Decisions, 1)
 
 	p1.AssertExpectations(t)
 	p2.AssertNotCalled(t, "Check")
 }
 
+// namedPolicy records the order policies run in.
+type namedPolicy struct {
+	name string
+	deny bool
+	ran  *[]string
+}
+
+func (p namedPolicy) Name() string { return p.name }
+
+func (p namedPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	*p.ran = append(*p.ran, p.name)
+	if p.deny {
+		return errors.New(p.name + " denies")
+	}
+	return nil
+}
+
+func TestEnforcer_PriorityAndTerminal(t *testing.T) {
+	var ran []string
+	e := security.NewEnforcer()
+	e.AddPolicyWithOptions(namedPolicy{name: "hitl", ran: &ran}, security.PolicyOptions{Priority: 3})
+	e.AddPolicyWithOptions(namedPolicy{name: "limits", ran: &ran}, security.PolicyOptions{Priority: 2})
+	e.AddPolicyWithOptions(namedPolicy{name: "gas", ran: &ran}, security.PolicyOptions{Priority: 2})
+	e.AddPolicyWithOptions(namedPolicy{name: "whitelist", ran: &ran}, security.PolicyOptions{Priority: 1})
+
+	require.NoError(t, e.Evaluate(context.Background(), &security.EvaluationContext{}))
+	assert.Equal(t, []string{"whitelist", "limits", "gas", "hitl"}, ran, "by priority, then as added")
+
+	// A terminal denial ends evaluation; others let it go on.
+	ran = nil
+	e = security.NewEnforcer()
+	e.AddPolicyWithOptions(namedPolicy{name: "gas", deny: true, ran: &ran}, security.PolicyOptions{})
+	e.AddPolicyWithOptions(namedPolicy{name: "whitelist", deny: true, ran: &ran}, security.PolicyOptions{Terminal: true})
+	e.AddPolicyWithOptions(namedPolicy{name: "limits", ran: &ran}, security.PolicyOptions{})
+	result, err := e.EvaluateAll(context.Background(), &security.EvaluationContext{})
+	assert.ErrorContains(t, err, "gas denies")
+	assert.Equal(t, []string{"gas", "whitelist"}, ran)
+	assert.Len(t, result.Denials(), 2)
+}
+
+func TestEnforcer_ChainScope(t *testing.T) {
+	e := security.NewEnforcer()
+	mainnet := new(MockPolicy)
+	everywhere := new(MockPolicy)
+
+	mainnet.On("Check", mock.Anything, mock.Anything).Return(errors.New("over mainnet cap"))
+	everywhere.On("Check", mock.Anything, mock.Anything).Return(nil)
+
+	e.AddPolicyForChains(mainnet, []string{"Ethereum"})
+	e.AddPolicy(everywhere)
+
+	err := e.Evaluate(context.Background(), &security.EvaluationContext{ChainName: "sepolia"})
+	assert.NoError(t, err)
+	mainnet.AssertNotCalled(t, "Check")
+	everywhere.AssertNumberOfCalls(t, "Check", 1)
+
+	err = e.Evaluate(context.Background(), &security.EvaluationContext{ChainName: "ethereum"})
+	assert.ErrorContains(t, err, "over mainnet cap")
+
+	// An operation on an unknown chain is checked by every policy.
+	err = e.Evaluate(context.Background(), &security.EvaluationContext{})
+	assert.ErrorContains(t, err, "over mainnet cap")
+}
+
+func TestEnforcer_AuditDenial(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+
+	e := security.NewEnforcer()
+	e.SetAudit(audit)
+	allow := new(MockPolicy)
+	deny := new(MockPolicy)
+	allow.On("Check", mock.Anything, mock.Anything).Return(nil)
+	deny.On("Check", mock.Anything, mock.Anything).Return(errors.New("over the cap")).Once()
+	deny.On("Check", mock.Anything, mock.Anything).Return(nil)
+	e.AddPolicy(allow)
+	e.AddPolicy(deny)
+
+	evalCtx := &security.EvaluationContext{
+		Tool:      "transfer",
+		ChainName: "ethereum",
+		From:      "0xagent",
+		Args: map[string]interface{}{
+			"to":         "0xrecipient",
+			"amount":     big.NewInt(10),
+			"data":       make([]byte, 100),
+			"passphrase": "hunter2",
+		},
+	}
+	require.Error(t, e.Evaluate(context.Background(), evalCtx))
+	require.NoError(t, e.Evaluate(context.Background(), evalCtx), "allowed operations are not recorded here")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+	var entry observe.AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, observe.AuditKindPolicyDecision, entry.Kind)
+	assert.Equal(t, "ethereum", entry.Chain)
+	assert.Equal(t, "0xagent", entry.From)
+	assert.Equal(t, "0xrecipient", entry.To)
+	assert.Equal(t, "10", entry.Value)
+	assert.Equal(t, []observe.PolicyResult{
+		{Policy: "MockPolicy", Allowed: true},
+		{Policy: "MockPolicy", Reason: "over the cap"},
+	}, entry.PolicyResults)
+	assert.Equal(t, "transfer", entry.Extra["tool"])
+	assert.Equal(t, "denied", entry.Extra["decision"])
+	args := entry.Extra["args"].(map[string]interface{})
+	assert.Equal(t, "<redacted>", args["passphrase"])
+	assert.Contains(t, args["data"], "(100 bytes)")
+	assert.NotContains(t, string(data), "hunter2")
+}
+
+// reportingPolicy denies and reports the tool it checked.
+type reportingPolicy struct{ namedPolicy }
+
+func (p reportingPolicy) Report(evalCtx *security.EvaluationContext) interface{} {
+	return map[string]interface{}{"tool": evalCtx.Tool}
+}
+
+func TestEnforcer_ReportDetails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+
+	var ran []string
+	e := security.NewEnforcer()
+	e.SetAudit(audit)
+	e.AddPolicy(namedPolicy{name: "plain", ran: &ran})
+	e.AddPolicy(reportingPolicy{namedPolicy{name: "simulation", deny: true, ran: &ran}})
+
+	result, err := e.EvaluateAll(context.Background(), &security.EvaluationContext{Tool: "send"})
+	require.Error(t, err)
+	require.Len(t, result.Decisions, 2)
+	assert.Nil(t, result.Decisions[0].Details)
+	assert.Equal(t, map[string]interface{}{"tool": "send"}, result.Decisions[1].Details)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entry observe.AuditEntry
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.Equal(t, []observe.PolicyResult{
+		{Policy: "plain", Allowed: true},
+		{Policy: "simulation", Reason: "simulation denies", Details: map[string]interface{}{"tool": "send"}},
+	}, entry.PolicyResults)
+}
+
+// warnCounter records warnings and counters.
+type warnCounter struct {
+	observe.NoopLogger
+	observe.NoopMetrics
+	mu       sync.Mutex
+	warnings []map[string]interface{}
+	counts   map[string]float64 // name{policy} -> value
+}
+
+func (w *warnCounter) Warn(msg string, fields ...map[string]interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.warnings = append(w.warnings, fields[0])
+}
+
+func (w *warnCounter) Counter(name string, value float64, labels ...map[string]string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.counts == nil {
+		w.counts = make(map[string]float64)
+	}
+	w.counts[name+"{"+labels[0]["policy"]+"}"] += value
+}
+
+func TestEnforcer_Advisory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+	obs := &warnCounter{}
+
+	e := security.NewEnforcer()
+	e.SetAudit(audit)
+	e.SetLogger(obs)
+	e.SetMetrics(obs)
+	advisory := new(MockPolicy)
+	enforced := new(MockPolicy)
+	advisory.On("Check", mock.Anything, mock.Anything).Return(errors.New("over the cap"))
+	enforced.On("Check", mock.Anything, mock.Anything).Return(nil).Once()
+	enforced.On("Check", mock.Anything, mock.Anything).Return(errors.New("not allowed"))
+	e.AddPolicyWithOptions(advisory, security.PolicyOptions{Advisory: true})
+	e.AddPolicyWithOptions(enforced, security.PolicyOptions{Chains: []string{"ethereum"}})
+
+	// The advisory denial is reported; the operation goes ahead.
+	evalCtx := &security.EvaluationContext{Tool: "transfer", ChainName: "ethereum"}
+	result, err := e.EvaluateAll(context.Background(), evalCtx)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, []security.PolicyDecision{
+		{PolicyName: "MockPolicy", Reason: "over the cap", Advisory: true},
+		{PolicyName: "MockPolicy", Allowed: true},
+	}, result.Decisions)
+	assert.Empty(t, result.Denials())
+
+	// An enforced denial still blocks, and its entry has both denials.
+	result, err = e.EvaluateAll(context.Background(), evalCtx)
+	assert.ErrorContains(t, err, "not allowed")
+	require.Len(t, result.Decisions, 2)
+	assert.False(t, result.Decisions[1].Allowed)
+	assert.Equal(t, "not allowed", result.Decisions[1].Reason)
+
+	require.Len(t, obs.warnings, 2)
+	assert.Equal(t, "over the cap", obs.warnings[0]["reason"])
+	assert.Equal(t, "transfer", obs.warnings[0]["tool"])
+	assert.Equal(t, map[string]float64{"policy_would_deny_total{MockPolicy}": 2}, obs.counts)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entries []observe.AuditEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry observe.AuditEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 2)
+	assert.Equal(t, observe.AuditKindWouldDeny, entries[0].Kind)
+	assert.Equal(t, "would_deny", entries[0].Extra["decision"])
+	assert.Equal(t, observe.AuditKindPolicyDecision, entries[1].Kind)
+	assert.Equal(t, []interface{}{"MockPolicy"}, entries[1].Extra["denied_by"], "advisory denials are listed apart")
+	assert.Equal(t, []observe.PolicyResult{
+		{Policy: "MockPolicy", Reason: "over the cap", Advisory: true},
+		{Policy: "MockPolicy", Reason: "not allowed"},
+	}, entries[1].PolicyResults)
+}
+
 // EOF: internal/security/enforcer_test.go
\ No newline at end of file
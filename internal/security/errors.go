@@ -0,0 +1,120 @@
+// Package security defines the errors policies deny operations with, so
+// that agent code can tell why an operation was denied with errors.As
+// instead of matching messages.
+//
+// Key types:
+//   - ErrPolicyDenied   : the denial of an operation, as Evaluate returns it.
+//   - ErrNeedsApproval  : an operation a human did not approve.
+//   - ErrLimitExceeded  : an operation over a spending or rate limit.
+//   - ErrAddressBlocked : an operation to a blocked address.
+//   - ErrPaused         : a write while writes are paused.
+//
+// File: internal/security/errors.go
+
+package security
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ErrPolicyDenied is the error Evaluate returns when a policy denies an
+// operation, wrapping the policy's error, so that a Settler can tell a
+// denial, after which nothing ran, from the tool's own error. Engine.Execute
+// returns it wrapped.
+type ErrPolicyDenied struct {
+	Policy Policy
+	// Decision is the policy's decision, with its name and reason.
+	Decision PolicyDecision
+	Err      error
+}
+
+func (e *ErrPolicyDenied) Error() string { return fmt.Sprintf("policy %T: %v", e.Policy, e.Err) }
+
+func (e *ErrPolicyDenied) Unwrap() error { return e.Err }
+
+// ErrNeedsApproval is the denial of an operation that needed a human's
+// approval and did not get it: the human rejected it, did not answer in
+// time, or could not be asked. Err says which; errors.Is finds
+// policies.ErrApprovalTimeout through it.
+type ErrNeedsApproval struct {
+	// Details are the lines shown to the human, such as the threshold the
+	// operation is over or the rule that requires approval.
+	Details []string
+	Err     error
+}
+
+func (e *ErrNeedsApproval) Error() string { return e.Err.Error() }
+
+func (e *ErrNeedsApproval) Unwrap() error { return e.Err }
+
+// ErrLimitExceeded is the denial of an operation that would exceed a limit:
+// a per‑transaction limit, a daily budget or a rate limit. The amounts are
+// in Unit.
+type ErrLimitExceeded struct {
+	// Name is the limit's configuration key: "max_transaction_value",
+	// "daily_limit", "max_gas_price", "max_gas_per_tx", "daily_gas_budget",
+	// "rate_limit", "session_budget", "max_allowance" or
+	// "max_per_recipient".
+	Name string
+	// Unit is "wei", "usd" (in 10⁻¹⁸ USD), "gas", "transactions", or
+	// "token" for base units of Token.
+	Unit  string
+	Token string // the token limited, for token_limits, token_approvals and recipient_limits
+	// Account is the account whose budget is exhausted, "" for
+	// per‑transaction limits or an unknown signer.
+	Account string
+	// Recipient is the address whose cumulative limit is reached, for
+	// recipient_limits.
+	Recipient string
+	Limit     *big.Int
+	Attempted *big.Int
+	Spent     *big.Int // already spent in the window; nil for per‑transaction limits
+	Reason    string   // the message, with the amounts formatted
+}
+
+func (e *ErrLimitExceeded) Error() string {
+	if e.Reason != "" {
+		return e.Reason
+	}
+	if e.Spent == nil {
+		return fmt.Sprintf("%s exceeded: limit %s %s, attempted %s", e.Name, e.Limit, e.Unit, e.Attempted)
+	}
+	return fmt.Sprintf("%s exceeded: limit %s %s, already spent %s, attempted +%s", e.Name, e.Limit, e.Unit, e.Spent, e.Attempted)
+}
+
+// ErrAddressBlocked is the denial of an operation to an address, its target
+// or the recipient or spender of an ERC‑20 call, by an address list.
+type ErrAddressBlocked struct {
+	Address string // checksummed
+	Name    string // the ENS name the address was given as, if any
+	// List is the list that denied it: "blocked_addresses",
+	// "allowed_addresses" or "allowed_spenders" for an address not in it,
+	// or the name of a denylist feed.
+	List   string
+	Reason string // the message
+}
+
+func (e *ErrAddressBlocked) Error() string {
+	if e.Reason != "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("address %s is blocked by %s", e.Address, e.List)
+}
+
+// ErrPaused is the denial of a write operation while writes are paused,
+// by Enforcer.Pause or the kill‑switch file (see pause.go).
+type ErrPaused struct {
+	Reason string
+	Since  time.Time
+}
+
+func (e *ErrPaused) Error() string {
+	if e.Reason == "" {
+		return "writes are paused"
+	}
+	return "writes are paused: " + e.Reason
+}
+
+// EOF: internal/security/errors.go
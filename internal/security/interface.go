@@ -2,15 +2,42 @@
 // on agent operations. Policies are evaluated before any onchain write.
 //
 // Key types:
-//   - EvaluationContext : carries information about the operation.
+//   - EvaluationContext : carries information about the operation and gives
+//     policies read access to the session's chain.
 //   - Policy            : a single rule that can allow or deny.
+//   - Settler           : a policy that learns how an allowed operation ended.
+//   - Reporter          : a policy that records details with its decisions.
+//   - EvaluationResult  : the decisions of the policies on an operation.
 //   - Enforcer          : aggregates policies and evaluates them.
 //
 // File: internal/security/interface.go
 
 package security
 
-import "context"
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// ErrCostEstimationUnsupported is returned by
+// EvaluationContext.EstimateTotalCost when the session has no chain or its
+// chain cannot estimate fees.
+var ErrCostEstimationUnsupported = errors.New("cost estimation not supported")
+
+// ErrContractDetectionUnsupported is returned by EvaluationContext.IsContract
+// when the session has no chain or its chain cannot inspect account code.
+var ErrContractDetectionUnsupported = errors.New("contract detection not supported")
+
+// ErrSimulationUnsupported is returned by EvaluationContext.Simulate when
+// the session has no chain or its chain cannot simulate transactions.
+var ErrSimulationUnsupported = errors.New("simulation not supported")
 
 // EvaluationContext holds all data needed for policy decisions.
 // Session will later contain agent identity, chain, etc.
@@ -18,6 +45,252 @@ type EvaluationContext struct {
 	Tool    string                 `json:"tool"`
 	Args    map[string]interface{} `json:"args"`
 	Session interface{}            `json:"session"` // placeholder
+	// ChainName is the name of the configured chain the operation runs on,
+	// set by the engine from the operation's "chain" argument or the
+	// session's default chain ("" if unknown). The enforcer runs only the
+	// policies scoped to it.
+	ChainName string `json:"chain,omitempty"`
+	// From is the address of the account the operation signs as, set by
+	// the engine from the session chain's wallet ("" if unknown).
+	From string `json:"from,omitempty"`
+	// Tx is the transaction the operation sends, including its to and
+	// data, filled in by Transaction on first use.
+	Tx *blockchain.Transaction `json:"tx,omitempty"`
+	// Cost is the estimated fee of the operation's transaction, filled in
+	// by EstimatedCost on first use so that policies share one estimate.
+	Cost *blockchain.TxCost `json:"cost,omitempty"`
+	// Simulation is the outcome of simulating the operation's transaction,
+	// filled in by Simulate on first use.
+	Simulation *blockchain.Simulation `json:"simulation,omitempty"`
+}
+
+// SessionChain is implemented by sessions that carry a chain, such as
+// core.Session.
+type SessionChain interface {
+	GetChain() blockchain.Chain
+}
+
+// SessionChains is implemented by sessions that carry several chains, by
+// name, such as core.Session.
+type SessionChains interface {
+	ChainByName(name string) (blockchain.Chain, error)
+}
+
+// Chain returns the chain the operation runs on: the session's chain
+// named ChainName, else its default chain, or nil if the session has none.
+func (e *EvaluationContext) Chain() blockchain.Chain {
+	if sc, ok := e.Session.(SessionChains); ok && e.ChainName != "" {
+		if chain, err := sc.ChainByName(e.ChainName); err == nil {
+			return chain
+		}
+	}
+	if sc, ok := e.Session.(SessionChain); ok {
+		return sc.GetChain()
+	}
+	return nil
+}
+
+// walletChain is implemented by chains that sign with a wallet, such as
+// the EVM gateway.
+type walletChain interface {
+	Wallet() blockchain.Wallet
+}
+
+// Wallet returns the wallet of the session chain, or nil if there is none.
+func (e *EvaluationContext) Wallet() blockchain.Wallet {
+	if wc, ok := e.Chain().(walletChain); ok {
+		return wc.Wallet()
+	}
+	return nil
+}
+
+// Signer returns the account the evaluated operation signs as: From if
+// set, else the "from" argument if given, else the primary account of the
+// session chain's wallet, or "" if there is none. Policies keyed by
+// account use it.
+func (e *EvaluationContext) Signer() string {
+	if e.From != "" {
+		return e.From
+	}
+	if from, ok := e.Args["from"].(string); ok && from != "" {
+		return from
+	}
+	if wallet := e.Wallet(); wallet != nil {
+		return wallet.Address()
+	}
+	return ""
+}
+
+// IsContract reports whether address holds code on the session's chain, so
+// policies can treat sends to contracts differently without their own RPC
+// plumbing.
+func (e *EvaluationContext) IsContract(ctx context.Context, address string) (bool, error) {
+	detector, ok := e.Chain().(blockchain.ContractDetector)
+	if !ok {
+		return false, ErrContractDetectionUnsupported
+	}
+	isContract, err := detector.IsContract(ctx, address)
+	if err != nil {
+		return false, fmt.Errorf("address %s: %w", address, err)
+	}
+	return isContract, nil
+}
+
+// EstimateTotalCost estimates the fees tx will pay on the session's chain,
+// including the L1 data fee on rollups.
+func (e *EvaluationContext) EstimateTotalCost(ctx context.Context, tx *blockchain.Transaction) (*blockchain.TxCost, error) {
+	estimator, ok := e.Chain().(blockchain.CostEstimator)
+	if !ok {
+		return nil, ErrCostEstimationUnsupported
+	}
+	return estimator.EstimateTotalCost(ctx, tx)
+}
+
+// Transaction returns Tx, which unless set is the transaction the tool
+// arguments describe: to, amount, data (or the bytecode of a deployment),
+// gas, gasPrice, gasFeeCap, gasTipCap and from, as taken by the transfer,
+// send, sign, send_raw and deploy tools. A nil To is a contract creation;
+// a deployment with a salt calls the CREATE2 factory (see Deployment).
+func (e *EvaluationContext) Transaction() *blockchain.Transaction {
+	if e.Tx != nil {
+		return e.Tx
+	}
+	tx := &blockchain.Transaction{Value: new(big.Int)}
+	if to, ok := e.Args["to"].(string); ok && to != "" {
+		tx.To = &to
+	}
+	if amount, ok := e.Args["amount"].(*big.Int); ok {
+		tx.Value = amount
+	}
+	if data, ok := e.Args["data"].([]byte); ok {
+		tx.Data = data
+	}
+	switch bytecode := e.Args["bytecode"].(type) {
+	case []byte:
+		tx.Data = bytecode
+	case string:
+		if data, err := hex.DecodeString(strings.TrimPrefix(bytecode, "0x")); err == nil {
+			tx.Data = data
+		}
+	}
+	if gas, ok := e.Args["gas"].(uint64); ok {
+		tx.Gas = gas
+	}
+	if gasPrice, ok := e.Args["gasPrice"].(*big.Int); ok {
+		tx.GasPrice = gasPrice
+	}
+	if gasFeeCap, ok := e.Args["gasFeeCap"].(*big.Int); ok {
+		tx.GasFeeCap = gasFeeCap
+	}
+	if gasTipCap, ok := e.Args["gasTipCap"].(*big.Int); ok {
+		tx.GasTipCap = gasTipCap
+	}
+	if from, ok := e.Args["from"].(string); ok {
+		tx.From = from
+	}
+	// A deployment with a salt is a call to the chain's CREATE2 factory
+	// with the salt followed by the creation data.
+	if salt, ok := create2Salt(e.Args["salt"]); ok && e.Tool == "deploy" {
+		if factory := e.create2Factory(); factory != "" {
+			tx.To = &factory
+			tx.Data = append(salt, tx.Data...)
+		}
+	}
+	e.Tx = tx
+	return tx
+}
+
+// create2Chain is implemented by chains that deploy contracts through a
+// CREATE2 factory, such as the EVM gateway.
+type create2Chain interface {
+	Create2Factory() string
+}
+
+// create2Factory returns the address of the CREATE2 factory of the
+// session chain, or "" if it has none.
+func (e *EvaluationContext) create2Factory() string {
+	if cc, ok := e.Chain().(create2Chain); ok {
+		return cc.Create2Factory()
+	}
+	return ""
+}
+
+// create2Salt returns the 32‑byte salt of a salt argument, given as
+// [32]byte, []byte or 0x‑hex.
+func create2Salt(raw interface{}) ([]byte, bool) {
+	var salt []byte
+	switch v := raw.(type) {
+	case [32]byte:
+		salt = v[:]
+	case []byte:
+		salt = v
+	case string:
+		decoded, err := hex.DecodeString(strings.TrimPrefix(v, "0x"))
+		if err != nil {
+			return nil, false
+		}
+		salt = decoded
+	}
+	if len(salt) != 32 {
+		return nil, false
+	}
+	return append([]byte(nil), salt...), true
+}
+
+// Deployment returns the creation data of the contract the operation
+// deploys, its bytecode followed by any constructor arguments, and whether
+// it deploys one: always for the deploy tool, which takes no to, and for
+// other tools if the transaction has no to or calls the session chain's
+// CREATE2 factory, whose data is a salt followed by the creation data.
+// The data is empty if the bytecode argument is missing or not valid hex.
+func (e *EvaluationContext) Deployment() ([]byte, bool) {
+	tx := e.Transaction()
+	if tx.To != nil && len(tx.Data) >= 32 && e.isCreate2Factory(*tx.To) {
+		return tx.Data[32:], true
+	}
+	if e.Tool != "deploy" && tx.To != nil {
+		return nil, false
+	}
+	return tx.Data, true
+}
+
+// isCreate2Factory reports whether address is the session chain's CREATE2
+// factory.
+func (e *EvaluationContext) isCreate2Factory(address string) bool {
+	factory := e.create2Factory()
+	return factory != "" && strings.EqualFold(factory, address)
+}
+
+// EstimatedCost estimates the fees of the operation's transaction (see
+// Transaction) on the session's chain once, and records them in Cost.
+func (e *EvaluationContext) EstimatedCost(ctx context.Context) (*blockchain.TxCost, error) {
+	if e.Cost != nil {
+		return e.Cost, nil
+	}
+	cost, err := e.EstimateTotalCost(ctx, e.Transaction())
+	if err != nil {
+		return nil, err
+	}
+	e.Cost = cost
+	return cost, nil
+}
+
+// Simulate simulates the operation's transaction (see Transaction) on the
+// session's chain once, and records the outcome in Simulation.
+func (e *EvaluationContext) Simulate(ctx context.Context) (*blockchain.Simulation, error) {
+	if e.Simulation != nil {
+		return e.Simulation, nil
+	}
+	simulator, ok := e.Chain().(blockchain.Simulator)
+	if !ok {
+		return nil, ErrSimulationUnsupported
+	}
+	sim, err := simulator.SimulateTransaction(ctx, e.Transaction())
+	if err != nil {
+		return nil, err
+	}
+	e.Simulation = sim
+	return sim, nil
 }
 
 // Policy is a single security rule.
@@ -26,15 +299,84 @@ type Policy interface {
 	Check(ctx context.Context, evalCtx *EvaluationContext) error
 }
 
+// Settler is implemented by policies that reserve something when they allow
+// an operation, such as spending budget, and must learn how it ended.
+// Settle is called once for every evalCtx the policy allowed: with nil
+// after the tool succeeded, or with the tool's error, or with the denial of
+// another policy (an *ErrPolicyDenied), in which case nothing was sent.
+type Settler interface {
+	Settle(ctx context.Context, evalCtx *EvaluationContext, err error)
+}
+
+// Prompter is implemented by policies that may ask a human to decide.
+// They are not run for an operation already denied, so nobody is asked
+// about an operation that cannot proceed.
+type Prompter interface {
+	// Prompts reports whether the policy, as configured, may ask.
+	Prompts() bool
+}
+
+// Reporter is implemented by policies that back their decisions with
+// details, such as the outcome of a simulation, recorded with the decision.
+type Reporter interface {
+	// Report returns the details of the policy's decision on evalCtx, just
+	// checked, or nil if there are none.
+	Report(evalCtx *EvaluationContext) interface{}
+}
+
+// PolicyDecision is the outcome of one policy for an operation.
+type PolicyDecision struct {
+	PolicyName string      `json:"policy"`
+	Allowed    bool        `json:"allowed"`
+	Reason     string      `json:"reason,omitempty"`   // why it denied
+	Advisory   bool        `json:"advisory,omitempty"` // its denial does not block
+	Details    interface{} `json:"details,omitempty"`  // from a Reporter
+}
+
+// EvaluationResult is the outcome of evaluating an operation: the decision
+// of each policy run, in order, and whether the operation may proceed.
+type EvaluationResult struct {
+	Decisions []PolicyDecision `json:"decisions"`
+	Allowed   bool             `json:"allowed"`
+}
+
+// Denials returns the decisions that deny the operation, advisory ones
+// excluded.
+func (r *EvaluationResult) Denials() []PolicyDecision {
+	var denials []PolicyDecision
+	for _, d := range r.Decisions {
+		if !d.Allowed && !d.Advisory {
+			denials = append(denials, d)
+		}
+	}
+	return denials
+}
+
+// AuditResults returns the decisions as recorded in audit entries.
+func (r *EvaluationResult) AuditResults() []observe.PolicyResult {
+	results := make([]observe.PolicyResult, len(r.Decisions))
+	for i, d := range r.Decisions {
+		results[i] = observe.PolicyResult{Policy: d.PolicyName, Allowed: d.Allowed, Reason: d.Reason, Advisory: d.Advisory, Details: d.Details}
+	}
+	return results
+}
+
 // Enforcer manages a set of policies and evaluates them collectively.
 // All policies must allow the operation for it to proceed.
 type Enforcer interface {
 	// AddPolicy appends a policy to the enforcer.
 	AddPolicy(policy Policy)
 
-	// Evaluate runs all policies against the given context.
-	// If any policy returns an error, evaluation stops and that error is returned.
+	// EvaluateAll runs the policies against the given context and returns
+	// the decision of each, with the error of the first denial.
+	EvaluateAll(ctx context.Context, evalCtx *EvaluationContext) (*EvaluationResult, error)
+
+	// Evaluate is EvaluateAll without the decisions.
 	Evaluate(ctx context.Context, evalCtx *EvaluationContext) error
+
+	// Settle reports the outcome of an allowed operation to the policies
+	// that implement Settler; err is nil if the tool succeeded.
+	Settle(ctx context.Context, evalCtx *EvaluationContext, err error)
 }
 
 // EOF: internal/security/interface.go
\ No newline at end of file
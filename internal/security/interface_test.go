@@ -11,7 +11,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/0xSemantic/lola-os/internal/blockchain"
 	"github.com/0xSemantic/lola-os/internal/security"
 )
 
@@ -34,11 +36,18 @@ func (m *MockEnforcer) AddPolicy(policy security.Policy) {
 	m.Called(policy)
 }
 
+func (m *MockEnforcer) EvaluateAll(ctx context.Context, evalCtx *security.EvaluationContext) (*security.EvaluationResult, error) {
+	err := m.Evaluate(ctx, evalCtx)
+	return &security.EvaluationResult{Allowed: err == nil}, err
+}
+
 func (m *MockEnforcer) Evaluate(ctx context.Context, evalCtx *security.EvaluationContext) error {
 	args := m.Called(ctx, evalCtx)
 	return args.Error(0)
 }
 
+func (m *MockEnforcer) Settle(ctx context.Context, evalCtx *security.EvaluationContext, err error) {}
+
 func TestPolicyInterface(t *testing.T) {
 	ctx := context.Background()
 	evalCtx := &security.EvaluationContext{
@@ -70,4 +79,72 @@ func TestEnforcerInterface(t *testing.T) {
 	mockEnforcer.AssertExpectations(t)
 }
 
+// codeChain is a chain that knows which addresses are contracts.
+type codeChain struct {
+	blockchain.Chain
+	contracts map[string]bool
+}
+
+func (c *codeChain) IsContract(ctx context.Context, address string) (bool, error) {
+	if address == "bad" {
+		return false, errors.New("rpc down")
+	}
+	return c.contracts[address], nil
+}
+
+// chainSession carries a chain like core.Session.
+type chainSession struct{ chain blockchain.Chain }
+
+func (s chainSession) GetChain() blockchain.Chain { return s.chain }
+
+func TestEvaluationContext_IsContract(t *testing.T) {
+	ctx := context.Background()
+	chain := &codeChain{contracts: map[string]bool{"0xtoken": true}}
+	evalCtx := &security.EvaluationContext{Tool: "send", Session: chainSession{chain}}
+
+	assert.Equal(t, chain, evalCtx.Chain())
+	isContract, err := evalCtx.IsContract(ctx, "0xtoken")
+	require.NoError(t, err)
+	assert.True(t, isContract)
+	isContract, err = evalCtx.IsContract(ctx, "0xwallet")
+	require.NoError(t, err)
+	assert.False(t, isContract)
+	_, err = evalCtx.IsContract(ctx, "bad")
+	assert.ErrorContains(t, err, "rpc down")
+
+	for _, session := range []interface{}{nil, "not a session", chainSession{}} {
+		evalCtx := &security.EvaluationContext{Session: session}
+		_, err := evalCtx.IsContract(ctx, "0xtoken")
+		assert.ErrorIs(t, err, security.ErrContractDetectionUnsupported)
+	}
+}
+
+// walletChain is a chain that signs with a wallet, like the EVM gateway.
+type walletChain struct {
+	blockchain.Chain
+	wallet blockchain.Wallet
+}
+
+func (c *walletChain) Wallet() blockchain.Wallet { return c.wallet }
+
+// addressWallet is a wallet with a fixed address.
+type addressWallet string
+
+func (w addressWallet) Sign([]byte) ([]byte, error) { return nil, errors.New("not implemented") }
+func (w addressWallet) Address() string             { return string(w) }
+
+func TestEvaluationContext_Signer(t *testing.T) {
+	session := chainSession{&walletChain{wallet: addressWallet("0xprimary")}}
+	evalCtx := &security.EvaluationContext{Tool: "send", Args: map[string]interface{}{}, Session: session}
+	assert.Equal(t, "0xprimary", evalCtx.Signer())
+
+	evalCtx.Args["from"] = "0xsecond"
+	assert.Equal(t, "0xsecond", evalCtx.Signer())
+
+	for _, session := range []interface{}{nil, chainSession{&walletChain{}}} {
+		evalCtx := &security.EvaluationContext{Tool: "send", Session: session}
+		assert.Empty(t, evalCtx.Signer())
+	}
+}
+
 // EOF: internal/security/interface_test.go
\ No newline at end of file
@@ -0,0 +1,281 @@
+// Package security provides the kill switch: while writes are paused,
+// the enforcer denies every write operation before any policy runs, and
+// reads go on, so that an agent misbehaving in production can be stopped
+// without killing the process that is needed to diagnose it.
+//
+// File: internal/security/pause.go
+
+package security
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// writeTools are the tools that perform writes.
+var writeTools = map[string]bool{
+	"transfer":     true,
+	"send":         true,
+	"swap":         true,
+	"deploy":       true,
+	"approve":      true,
+	"cancel":       true,
+	"sign":         true,
+	"send_raw":     true,
+	"sign_message": true,
+	"safe_propose": true,
+	"aa_send":      true,
+}
+
+// IsWriteTool reports whether tool performs writes: it sends a
+// transaction, or signs something that could be sent.
+func IsWriteTool(tool string) bool { return writeTools[tool] }
+
+// pauseState is why writes are paused, if they are.
+type pauseState struct {
+	manual   *ErrPaused // set by Pause; nil = not paused by it
+	file     *ErrPaused // set while the kill‑switch file exists
+	filePath string     // "" = no kill‑switch file
+}
+
+// paused returns the pause in force, nil if writes are not paused.
+func (s *pauseState) paused() *ErrPaused {
+	if s.manual != nil {
+		return s.manual
+	}
+	return s.file
+}
+
+// killSwitch is the check the enforcer runs before any policy.
+type killSwitch struct{ e *Enforcer }
+
+// Name returns "kill_switch", its name in decisions and audit entries.
+func (k killSwitch) Name() string { return "kill_switch" }
+
+// Check implements Policy: it denies write operations while writes are
+// paused, looking for the kill‑switch file first.
+func (k killSwitch) Check(ctx context.Context, evalCtx *EvaluationContext) error {
+	k.e.checkKillSwitchFile()
+	if !IsWriteTool(evalCtx.Tool) {
+		return nil
+	}
+	if p := k.e.Paused(); p != nil {
+		return p
+	}
+	return nil
+}
+
+// Pause denies every write operation, with reason, until Resume. Reads
+// still run. Pausing again only changes the reason.
+func (e *Enforcer) Pause(reason string) {
+	e.pause(reason, "sdk")
+}
+
+// Resume ends a pause by Pause. Writes stay paused while the kill‑switch
+// file exists.
+func (e *Enforcer) Resume() {
+	e.resume("sdk")
+}
+
+// Paused returns the pause in force, nil if writes are not paused.
+func (e *Enforcer) Paused() *ErrPaused {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if p := e.kill.paused(); p != nil {
+		copied := *p
+		return &copied
+	}
+	return nil
+}
+
+// SetKillSwitchFile sets a file whose existence pauses writes: it is
+// looked for at every evaluation, and its content, if any, is the reason.
+// Removing it resumes writes, unless Pause paused them too. If the file
+// cannot be looked for, say for lack of permission on its directory,
+// writes are paused as if it existed. An empty path disables the file.
+func (e *Enforcer) SetKillSwitchFile(path string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.kill.filePath = path
+}
+
+func (e *Enforcer) pause(reason, source string) {
+	e.mu.Lock()
+	if e.kill.manual != nil && e.kill.manual.Reason == reason {
+		e.mu.Unlock()
+		return
+	}
+	since := time.Now().UTC()
+	if e.kill.manual != nil {
+		since = e.kill.manual.Since
+	}
+	e.kill.manual = &ErrPaused{Reason: reason, Since: since}
+	e.mu.Unlock()
+	e.recordPause(true, reason, source)
+}
+
+func (e *Enforcer) resume(source string) {
+	e.mu.Lock()
+	if e.kill.manual == nil {
+		e.mu.Unlock()
+		return
+	}
+	reason := e.kill.manual.Reason
+	e.kill.manual = nil
+	e.mu.Unlock()
+	e.recordPause(false, reason, source)
+}
+
+// checkKillSwitchFile pauses or resumes writes if the kill‑switch file
+// has appeared or gone since the last evaluation.
+func (e *Enforcer) checkKillSwitchFile() {
+	e.mu.RLock()
+	path, was := e.kill.filePath, e.kill.file != nil
+	e.mu.RUnlock()
+	if path == "" {
+		if was {
+			e.setFilePause(nil, path)
+		}
+		return
+	}
+	_, err := os.Stat(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		// The file may be there: keep writes paused rather than fail open.
+		e.mu.RLock()
+		logger := e.logger
+		e.mu.RUnlock()
+		if logger != nil {
+			logger.Error("kill switch file unreadable; writes stay paused", map[string]interface{}{"path": path, "error": err.Error()})
+		}
+		if !was {
+			e.setFilePause(&ErrPaused{Reason: "kill switch file " + path + " unreadable: " + err.Error(), Since: time.Now().UTC()}, path)
+		}
+		return
+	}
+	exists := err == nil
+	if exists == was {
+		return
+	}
+	if !exists {
+		e.setFilePause(nil, path)
+		return
+	}
+	reason := "kill switch file " + path
+	if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) != "" {
+		reason = strings.TrimSpace(string(data))
+	}
+	e.setFilePause(&ErrPaused{Reason: reason, Since: time.Now().UTC()}, path)
+}
+
+// setFilePause records a change of the kill‑switch file, unless another
+// evaluation recorded it first.
+func (e *Enforcer) setFilePause(p *ErrPaused, path string) {
+	e.mu.Lock()
+	if (e.kill.file != nil) == (p != nil) {
+		e.mu.Unlock()
+		return
+	}
+	old := e.kill.file
+	e.kill.file = p
+	e.mu.Unlock()
+	if p != nil {
+		e.recordPause(true, p.Reason, "file "+path)
+	} else {
+		e.recordPause(false, old.Reason, "file "+path)
+	}
+}
+
+// recordPause logs and audits writes being paused or resumed. Audit
+// failures are logged.
+func (e *Enforcer) recordPause(paused bool, reason, source string) {
+	e.mu.RLock()
+	logger, audit := e.logger, e.audit
+	e.mu.RUnlock()
+	decision := "resumed"
+	if paused {
+		decision = "paused"
+	}
+	fields := map[string]interface{}{"reason": reason, "source": source}
+	if logger != nil {
+		if paused {
+			logger.Warn("writes paused", fields)
+		} else {
+			logger.Info("writes resumed", fields)
+		}
+	}
+	if audit == nil {
+		return
+	}
+	entry := &observe.AuditEntry{
+		Kind:  observe.AuditKindPause,
+		Extra: map[string]interface{}{"decision": decision, "reason": reason, "source": source},
+	}
+	if err := audit.Log(entry); err != nil && logger != nil {
+		logger.Error("audit of write pause failed", map[string]interface{}{"error": err})
+	}
+}
+
+// NewPauseHandler serves the kill switch of e over HTTP, to anyone
+// bearing token:
+//
+//	GET  /kill-switch          the pause in force: {"paused": false} or
+//	                           {"paused": true, "reason": ..., "since": ...}
+//	POST /kill-switch/pause    pause writes; body {"reason": "..."}, optional
+//	POST /kill-switch/resume   end a pause made by Pause or this endpoint
+func NewPauseHandler(e *Enforcer, token string) http.Handler {
+	status := func(w http.ResponseWriter) {
+		body := map[string]interface{}{"paused": false}
+		if p := e.Paused(); p != nil {
+			body = map[string]interface{}{"paused": true, "reason": p.Reason, "since": p.Since}
+		}
+		writePauseJSON(w, http.StatusOK, body)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /kill-switch", func(w http.ResponseWriter, r *http.Request) {
+		status(w)
+	})
+	mux.HandleFunc("POST /kill-switch/pause", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&body); err != nil {
+				writePauseJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid body: " + err.Error()})
+				return
+			}
+		}
+		e.pause(strings.TrimSpace(body.Reason), "http")
+		status(w)
+	})
+	mux.HandleFunc("POST /kill-switch/resume", func(w http.ResponseWriter, r *http.Request) {
+		e.resume("http")
+		status(w)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="lola kill switch"`)
+			writePauseJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func writePauseJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// EOF: internal/security/pause.go
@@ -0,0 +1,205 @@
+// Package security_test tests the kill switch.
+//
+// File: internal/security/pause_test.go
+
+package security_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// pauseEntries returns the pause entries of the audit log at path.
+func pauseEntries(t *testing.T, path string) []observe.AuditEntry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entries []observe.AuditEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry observe.AuditEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		if entry.Kind == observe.AuditKindPause {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func TestEnforcer_Pause(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+
+	e := security.NewEnforcer()
+	e.SetAudit(audit)
+	p := new(MockPolicy)
+	p.On("Check", mock.Anything, mock.Anything).Return(nil)
+	e.AddPolicy(p)
+	ctx := context.Background()
+	transfer := &security.EvaluationContext{Tool: "transfer"}
+	balance := &security.EvaluationContext{Tool: "balance"}
+
+	assert.Nil(t, e.Paused())
+	e.Pause("incident 42")
+	require.NotNil(t, e.Paused())
+	assert.Equal(t, "incident 42", e.Paused().Reason)
+
+	result, err := e.EvaluateAll(ctx, transfer)
+	var denied *security.ErrPolicyDenied
+	require.ErrorAs(t, err, &denied)
+	assert.Equal(t, "kill_switch", denied.Decision.PolicyName)
+	var paused *security.ErrPaused
+	require.ErrorAs(t, err, &paused)
+	assert.Equal(t, "incident 42", paused.Reason)
+	assert.False(t, paused.Since.IsZero())
+	assert.Len(t, result.Decisions, 1)
+	p.AssertNotCalled(t, "Check", mock.Anything, transfer)
+
+	// Reads keep working, so that the agent can still be diagnosed.
+	assert.NoError(t, e.Evaluate(ctx, balance))
+
+	e.Resume()
+	assert.Nil(t, e.Paused())
+	assert.NoError(t, e.Evaluate(ctx, transfer))
+	e.Resume() // not paused: nothing to record
+
+	entries := pauseEntries(t, path)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "paused", entries[0].Extra["decision"])
+	assert.Equal(t, "incident 42", entries[0].Extra["reason"])
+	assert.Equal(t, "sdk", entries[0].Extra["source"])
+	assert.Equal(t, "resumed", entries[1].Extra["decision"])
+}
+
+func TestEnforcer_KillSwitchFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+
+	kill := filepath.Join(dir, "STOP")
+	e := security.NewEnforcer()
+	e.SetAudit(audit)
+	e.SetKillSwitchFile(kill)
+	ctx := context.Background()
+	transfer := &security.EvaluationContext{Tool: "transfer"}
+
+	require.NoError(t, e.Evaluate(ctx, transfer))
+
+	require.NoError(t, os.WriteFile(kill, []byte("draining wallet\n"), 0o600))
+	err = e.Evaluate(ctx, transfer)
+	var paused *security.ErrPaused
+	require.ErrorAs(t, err, &paused)
+	assert.Equal(t, "draining wallet", paused.Reason)
+	assert.NoError(t, e.Evaluate(ctx, &security.EvaluationContext{Tool: "balance"}))
+
+	// Resume ends only a pause by Pause; the file still pauses writes.
+	e.Resume()
+	assert.Error(t, e.Evaluate(ctx, transfer))
+
+	require.NoError(t, os.Remove(kill))
+	assert.NoError(t, e.Evaluate(ctx, transfer))
+
+	entries := pauseEntries(t, path)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "paused", entries[0].Extra["decision"])
+	assert.Equal(t, "file "+kill, entries[0].Extra["source"])
+	assert.Equal(t, "resumed", entries[1].Extra["decision"])
+}
+
+// errorRecorder records errors logged.
+type errorRecorder struct {
+	observe.NoopLogger
+	errors []string
+}
+
+func (r *errorRecorder) Error(msg string, fields ...map[string]interface{}) {
+	r.errors = append(r.errors, msg)
+}
+
+func TestEnforcer_KillSwitchFileUnreadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root ignores directory permissions")
+	}
+	dir := filepath.Join(t.TempDir(), "run")
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	logger := &errorRecorder{}
+	e := security.NewEnforcer()
+	e.SetLogger(logger)
+	e.SetKillSwitchFile(filepath.Join(dir, "STOP"))
+	ctx := context.Background()
+	transfer := &security.EvaluationContext{Tool: "transfer"}
+	require.NoError(t, e.Evaluate(ctx, transfer))
+
+	// A file that cannot be looked for may be there: writes are paused,
+	// not resumed.
+	require.NoError(t, os.Chmod(dir, 0))
+	t.Cleanup(func() { os.Chmod(dir, 0o700) })
+	var paused *security.ErrPaused
+	require.ErrorAs(t, e.Evaluate(ctx, transfer), &paused)
+	assert.Contains(t, paused.Reason, "unreadable")
+	assert.NotEmpty(t, logger.errors)
+
+	require.NoError(t, os.Chmod(dir, 0o700))
+	assert.NoError(t, e.Evaluate(ctx, transfer))
+}
+
+func TestPauseHandler(t *testing.T) {
+	e := security.NewEnforcer()
+	srv := httptest.NewServer(security.NewPauseHandler(e, "s3cret"))
+	defer srv.Close()
+
+	call := func(method, path, token, body string) (int, map[string]interface{}) {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var out map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		return resp.StatusCode, out
+	}
+
+	status, _ := call("POST", "/kill-switch/pause", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = call("POST", "/kill-switch/pause", "wrong", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Nil(t, e.Paused())
+
+	status, out := call("POST", "/kill-switch/pause", "s3cret", `{"reason": "oncall"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, out["paused"])
+	assert.Equal(t, "oncall", out["reason"])
+	err := e.Evaluate(context.Background(), &security.EvaluationContext{Tool: "send"})
+	assert.True(t, errors.As(err, new(*security.ErrPaused)))
+
+	status, out = call("GET", "/kill-switch", "s3cret", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, out["paused"])
+
+	status, out = call("POST", "/kill-switch/resume", "s3cret", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, false, out["paused"])
+	assert.Nil(t, e.Paused())
+}
+
+// EOF: internal/security/pause_test.go
@@ -0,0 +1,405 @@
+// Package policies provides a queue of operations awaiting human approval,
+// and an HTTP API to review and resolve them.
+//
+// File: internal/security/policies/approvals.go
+
+package policies
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// ErrApprovalNotFound is returned by ApprovalQueue.Resolve for a request
+// that is not pending: unknown, already resolved or expired.
+var ErrApprovalNotFound = errors.New("approval not found")
+
+// PendingApproval is an operation awaiting approval.
+type PendingApproval struct {
+	ID      string    `json:"id"`
+	Tool    string    `json:"tool"`
+	Chain   string    `json:"chain,omitempty"`
+	From    string    `json:"from,omitempty"`
+	To      string    `json:"to,omitempty"`
+	Value   string    `json:"value,omitempty"` // amount in base units
+	Details []string  `json:"details,omitempty"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+	Age     string    `json:"age"` // at the time of listing
+
+	// For requests needing several approvals: how many, who may give
+	// them, and the decisions so far.
+	Required  int      `json:"approvals_required,omitempty"`
+	Approvers []string `json:"approvers,omitempty"`
+	Approvals []string `json:"approvals,omitempty"`
+	Denials   []string `json:"denials,omitempty"`
+}
+
+// ApprovalQueue holds operations awaiting human approval. Approve blocks
+// the calling tool until the request is resolved, expires or the queue is
+// closed; frontends list the requests and resolve them. The built‑in HTTP
+// API is one such frontend.
+//
+// With a state file, the pending requests are saved as they change. Their
+// callers do not survive a restart, so requests found on start are
+// recorded as abandoned and dropped.
+type ApprovalQueue struct {
+	mu      sync.Mutex
+	pending map[string]*queuedApproval
+	state   *LimitState // nil = in memory only
+	audit   *observe.AuditLogger
+	closed  chan struct{}
+	once    sync.Once
+}
+
+type queuedApproval struct {
+	PendingApproval
+	evalCtx  *security.EvaluationContext
+	votes    *quorumVotes
+	decision chan approvalDecision // buffered; receives the resolution
+}
+
+type approvalDecision struct {
+	approved bool
+	by       string                 // who decided last
+	votes    map[string]interface{} // for requests needing several approvals
+}
+
+// approvalState is the on‑disk form of the queue.
+type approvalState struct {
+	Pending []PendingApproval `json:"pending"`
+}
+
+// NewApprovalQueue creates an empty queue. Decisions are recorded in audit
+// (nil = not recorded).
+func NewApprovalQueue(audit *observe.AuditLogger) *ApprovalQueue {
+	return &ApprovalQueue{
+		pending: make(map[string]*queuedApproval),
+		audit:   audit,
+		closed:  make(chan struct{}),
+	}
+}
+
+// NewApprovalQueueWithState is NewApprovalQueue saving the pending requests
+// in state.Path, so that requests left by a crash are recorded.
+func NewApprovalQueueWithState(audit *observe.AuditLogger, state LimitState) (*ApprovalQueue, error) {
+	q := NewApprovalQueue(audit)
+	q.state = &state
+	var saved approvalState
+	found, err := readStateFile(state.Path, &saved)
+	if err != nil {
+		if state.FailClosed {
+			return nil, fmt.Errorf("approval queue: %w", err)
+		}
+		q.state.log("approval queue state unreadable; earlier requests are not recorded", err)
+	}
+	if found && len(saved.Pending) > 0 {
+		for _, a := range saved.Pending {
+			evalCtx := &security.EvaluationContext{Tool: a.Tool, ChainName: a.Chain, From: a.From, Args: map[string]interface{}{}}
+			if a.To != "" {
+				evalCtx.Args["to"] = a.To
+			}
+			if amount, ok := new(big.Int).SetString(a.Value, 10); ok {
+				evalCtx.Args["amount"] = amount
+			}
+			recordDecision(audit, evalCtx, "api", "abandoned", a.Created,
+				map[string]interface{}{"approval_id": a.ID, "reason": "restart"})
+		}
+		q.state.warn(fmt.Sprintf("%d pending approvals abandoned by a restart", len(saved.Pending)))
+		if err := writeStateFile(state.Path, approvalState{}); err != nil {
+			q.state.log("approval queue state not saved", err)
+		}
+	}
+	return q, nil
+}
+
+// Approve queues an approval request for evalCtx, with details, and waits
+// up to timeout for it to be resolved.
+func (q *ApprovalQueue) Approve(ctx context.Context, evalCtx *security.EvaluationContext, timeout time.Duration, details []string) error {
+	return q.ApproveQuorum(ctx, evalCtx, timeout, details, Quorum{})
+}
+
+// ApproveQuorum is Approve for a request that quorum must approve: it
+// waits for enough distinct approvals, or a denial that settles it.
+func (q *ApprovalQueue) ApproveQuorum(ctx context.Context, evalCtx *security.EvaluationContext, timeout time.Duration, details []string, quorum Quorum) error {
+	id, err := newRequestID()
+	if err != nil {
+		return fmt.Errorf("approval queue: %w", err)
+	}
+	now := time.Now().UTC()
+	a := &queuedApproval{
+		PendingApproval: PendingApproval{
+			ID:      id,
+			Tool:    evalCtx.Tool,
+			Chain:   evalCtx.ChainName,
+			From:    evalCtx.Signer(),
+			Details: details,
+			Created: now,
+			Expires: now.Add(timeout),
+		},
+		evalCtx:  evalCtx,
+		votes:    newQuorumVotes(quorum),
+		decision: make(chan approvalDecision, 1),
+	}
+	if a.votes.multi() {
+		a.Required, a.Approvers = a.votes.required, quorum.Approvers
+	}
+	if to, ok := evalCtx.Args["to"].(string); ok {
+		a.To = to
+	}
+	if amount, ok := evalCtx.Args["amount"].(*big.Int); ok {
+		a.Value = amount.String()
+	}
+
+	q.mu.Lock()
+	select {
+	case <-q.closed:
+		q.mu.Unlock()
+		return errors.New("approval queue: closed")
+	default:
+	}
+	q.pending[id] = a
+	q.save()
+	q.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var reason error
+	decision := "cancelled"
+	select {
+	case d := <-a.decision:
+		return q.decided(evalCtx, a, d)
+	case <-timer.C:
+		reason = fmt.Errorf("%w after %v", ErrApprovalTimeout, timeout)
+		decision = "timeout"
+	case <-ctx.Done():
+		reason = fmt.Errorf("human approval: %w", ctx.Err())
+	case <-q.closed:
+		reason = errors.New("human approval: approval queue closed")
+	}
+
+	q.mu.Lock()
+	_, waiting := q.pending[id]
+	extra := map[string]interface{}{"approval_id": id}
+	if waiting {
+		delete(q.pending, id)
+		q.save()
+		if a.votes.multi() {
+			// Who had approved when time ran out.
+			for k, v := range a.votes.record() {
+				extra[k] = v
+			}
+		}
+	}
+	q.mu.Unlock()
+	if !waiting {
+		// Resolved as the wait ended.
+		return q.decided(evalCtx, a, <-a.decision)
+	}
+	recordDecision(q.audit, evalCtx, "api", decision, a.Created, extra)
+	return reason
+}
+
+// decided records d and returns the outcome for the caller.
+func (q *ApprovalQueue) decided(evalCtx *security.EvaluationContext, a *queuedApproval, d approvalDecision) error {
+	extra := map[string]interface{}{"approval_id": a.ID, "approved_by": d.by}
+	for k, v := range d.votes {
+		extra[k] = v
+	}
+	if !d.approved {
+		recordDecision(q.audit, evalCtx, "api", "denied", a.Created, extra)
+		return fmt.Errorf("human rejected transaction (by %s)", d.by)
+	}
+	recordDecision(q.audit, evalCtx, "api", "approved", a.Created, extra)
+	return nil
+}
+
+// List returns the pending requests, oldest first.
+func (q *ApprovalQueue) List() []PendingApproval {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now()
+	list := make([]PendingApproval, 0, len(q.pending))
+	for _, a := range q.pending {
+		p := a.PendingApproval
+		p.Age = now.Sub(p.Created).Round(time.Second).String()
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Created.Before(list[j].Created) })
+	return list
+}
+
+// Resolve approves or denies request id on behalf of by. The first
+// resolution wins, or for a request needing several approvals the one
+// that settles it; later ones get ErrApprovalNotFound. A decision by
+// someone not among the request's approvers gets ErrNotApprover, and a
+// second one by the same approver ErrDuplicateApproval. Decisions that do
+// not settle the request are recorded as partial.
+func (q *ApprovalQueue) Resolve(id string, approve bool, by string) error {
+	q.mu.Lock()
+	a, ok := q.pending[id]
+	if !ok {
+		q.mu.Unlock()
+		return ErrApprovalNotFound
+	}
+	done, approved, err := a.votes.vote(by, approve)
+	if err != nil {
+		q.mu.Unlock()
+		return err
+	}
+	var votes map[string]interface{}
+	if a.votes.multi() {
+		votes = a.votes.record()
+		a.Approvals = append([]string(nil), a.votes.approvals...)
+		a.Denials = append([]string(nil), a.votes.denials...)
+	}
+	if done {
+		delete(q.pending, id)
+	}
+	q.save()
+	q.mu.Unlock()
+
+	if done {
+		a.decision <- approvalDecision{approved: approved, by: by, votes: votes}
+		return nil
+	}
+	decision := "partial_approval"
+	if !approve {
+		decision = "partial_denial"
+	}
+	votes["approval_id"], votes["approved_by"] = id, by
+	recordDecision(q.audit, a.evalCtx, "api", decision, a.Created, votes)
+	return nil
+}
+
+// Close denies every pending request and every later one, unblocking
+// their callers.
+func (q *ApprovalQueue) Close() {
+	q.once.Do(func() {
+		q.mu.Lock()
+		close(q.closed)
+		q.mu.Unlock()
+	})
+}
+
+// save writes the pending requests; q.mu must be held. Failures are
+// logged: the queue itself works without its file.
+func (q *ApprovalQueue) save() {
+	if q.state == nil {
+		return
+	}
+	state := approvalState{Pending: make([]PendingApproval, 0, len(q.pending))}
+	for _, a := range q.pending {
+		state.Pending = append(state.Pending, a.PendingApproval)
+	}
+	if err := writeStateFile(q.state.Path, state); err != nil {
+		q.state.log("approval queue state not saved", err)
+	}
+}
+
+// NewApprovalHandler serves q over HTTP, for requests bearing token:
+//
+//	GET  /approvals               the pending requests
+//	POST /approvals/{id}/approve  approves one
+//	POST /approvals/{id}/deny     denies one
+//
+// A resolution may name who made it in a JSON body, {"by": "alice"};
+// it is recorded in the audit log.
+func NewApprovalHandler(q *ApprovalQueue, token string) http.Handler {
+	return NewApprovalHandlerWithApprovers(q, token, nil)
+}
+
+// NewApprovalHandlerWithApprovers is NewApprovalHandler also accepting the
+// tokens of approvers, by name. A resolution bearing an approver's token
+// is made by that approver, whatever the body says; one bearing token
+// cannot name an approver, so that only the approvers themselves count
+// towards a Quorum.
+func NewApprovalHandlerWithApprovers(q *ApprovalQueue, token string, approvers map[string]string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /approvals", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, q.List())
+	})
+	resolve := func(approve bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				By string `json:"by"`
+			}
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&body); err != nil {
+					writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid body: " + err.Error()})
+					return
+				}
+			}
+			by := strings.TrimSpace(body.By)
+			if by == "" {
+				by = "api"
+			}
+			if approver, ok := r.Context().Value(approverKey{}).(string); ok {
+				by = approver
+			} else if _, ok := approvers[by]; ok {
+				writeJSON(w, http.StatusForbidden, map[string]string{"error": "approver " + by + " must use their own token"})
+				return
+			}
+			id := r.PathValue("id")
+			if err := q.Resolve(id, approve, by); err != nil {
+				status := http.StatusNotFound
+				switch {
+				case errors.Is(err, ErrNotApprover):
+					status = http.StatusForbidden
+				case errors.Is(err, ErrDuplicateApproval):
+					status = http.StatusConflict
+				}
+				writeJSON(w, status, map[string]string{"error": err.Error()})
+				return
+			}
+			decision := "approved"
+			if !approve {
+				decision = "denied"
+			}
+			writeJSON(w, http.StatusOK, map[string]string{"id": id, "decision": decision})
+		}
+	}
+	mux.HandleFunc("POST /approvals/{id}/approve", resolve(true))
+	mux.HandleFunc("POST /approvals/{id}/deny", resolve(false))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		authorized := ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+		for name, approverToken := range approvers {
+			if ok && approverToken != "" && subtle.ConstantTimeCompare([]byte(got), []byte(approverToken)) == 1 {
+				authorized = true
+				r = r.WithContext(context.WithValue(r.Context(), approverKey{}, name))
+			}
+		}
+		if !authorized {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="lola approvals"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// approverKey keys the name of the approver a request authenticated as in
+// its context.
+type approverKey struct{}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// EOF: internal/security/policies/approvals.go
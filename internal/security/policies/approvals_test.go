@@ -0,0 +1,291 @@
+package policies_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+const approvalToken = "s3cret"
+
+func queueHITL(queue *policies.ApprovalQueue, timeout time.Duration) *policies.HITLPolicy {
+	hitl := policies.NewHITLPolicy(config.MustParseAmount("1 eth"), timeout, "api")
+	hitl.SetQueue(queue)
+	return hitl
+}
+
+// approvalAPI calls the approval API of srv.
+func approvalAPI(t *testing.T, srv *httptest.Server, method, path, token, body string) (int, string) {
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var out json.RawMessage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return resp.StatusCode, string(out)
+}
+
+// waitPending waits for n requests to be pending and returns them.
+func waitPending(t *testing.T, queue *policies.ApprovalQueue, n int) []policies.PendingApproval {
+	var list []policies.PendingApproval
+	require.Eventually(t, func() bool {
+		list = queue.List()
+		return len(list) == n
+	}, 2*time.Second, 5*time.Millisecond)
+	return list
+}
+
+func TestApprovalQueue_API(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+	queue := policies.NewApprovalQueue(audit)
+	srv := httptest.NewServer(policies.NewApprovalHandler(queue, approvalToken))
+	defer srv.Close()
+	hitl := queueHITL(queue, 5*time.Second)
+
+	status, _ := approvalAPI(t, srv, "GET", "/approvals", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = approvalAPI(t, srv, "GET", "/approvals", "wrong", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	done := make(chan error, 1)
+	evalCtx := usdEvalCtx(eth(2000))
+	evalCtx.Args["to"] = owner
+	go func() { done <- hitl.Check(context.Background(), evalCtx) }()
+	pending := waitPending(t, queue, 1)
+
+	status, body := approvalAPI(t, srv, "GET", "/approvals", approvalToken, "")
+	require.Equal(t, http.StatusOK, status)
+	var listed []policies.PendingApproval
+	require.NoError(t, json.Unmarshal([]byte(body), &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, pending[0].ID, listed[0].ID)
+	assert.Equal(t, "transfer", listed[0].Tool)
+	assert.Equal(t, "ethereum", listed[0].Chain)
+	assert.Equal(t, owner, listed[0].To)
+	assert.Equal(t, "2000000000000000000", listed[0].Value)
+	assert.NotEmpty(t, listed[0].Age)
+
+	status, body = approvalAPI(t, srv, "POST", "/approvals/"+listed[0].ID+"/approve", approvalToken, `{"by":"alice"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"id":"`+listed[0].ID+`","decision":"approved"}`, body)
+	require.NoError(t, <-done)
+
+	status, body = approvalAPI(t, srv, "POST", "/approvals/"+listed[0].ID+"/deny", approvalToken, "")
+	assert.Equal(t, http.StatusNotFound, status, "already resolved")
+	assert.Contains(t, body, "approval not found")
+
+	entries := readAudit(t, path)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "hitl_decision", entries[0].Extra["action"])
+	assert.Equal(t, "api", entries[0].Extra["mode"])
+	assert.Equal(t, "approved", entries[0].Extra["decision"])
+	assert.Equal(t, "alice", entries[0].Extra["approved_by"])
+	assert.Equal(t, owner, entries[0].To)
+}
+
+func TestApprovalQueue_Concurrent(t *testing.T) {
+	queue := policies.NewApprovalQueue(nil)
+	srv := httptest.NewServer(policies.NewApprovalHandler(queue, approvalToken))
+	defer srv.Close()
+	hitl := queueHITL(queue, 5*time.Second)
+
+	const n = 8
+	results := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() { results <- hitl.Check(context.Background(), usdEvalCtx(eth(2000))) }()
+	}
+	pending := waitPending(t, queue, n)
+
+	// Two reviewers race on every request; each is resolved once.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	resolved := make(map[string]int)
+	for i, p := range pending {
+		action := "approve"
+		if i%2 == 1 {
+			action = "deny"
+		}
+		for reviewer := 0; reviewer < 2; reviewer++ {
+			wg.Add(1)
+			go func(id string, reviewer int) {
+				defer wg.Done()
+				status, _ := approvalAPI(t, srv, "POST", "/approvals/"+id+"/"+action, approvalToken, fmt.Sprintf(`{"by":"r%d"}`, reviewer))
+				if status == http.StatusOK {
+					mu.Lock()
+					resolved[id]++
+					mu.Unlock()
+				}
+			}(p.ID, reviewer)
+		}
+	}
+	wg.Wait()
+	for _, p := range pending {
+		assert.Equal(t, 1, resolved[p.ID], "first resolution wins")
+	}
+	var approved, denied int
+	for i := 0; i < n; i++ {
+		if err := <-results; err == nil {
+			approved++
+		} else {
+			assert.ErrorContains(t, err, "human rejected transaction (by r")
+			denied++
+		}
+	}
+	assert.Equal(t, []int{n / 2, n / 2}, []int{approved, denied})
+	assert.Empty(t, queue.List())
+}
+
+func TestApprovalQueue_Expiry(t *testing.T) {
+	queue := policies.NewApprovalQueue(nil)
+	hitl := queueHITL(queue, 100*time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() { done <- hitl.Check(context.Background(), usdEvalCtx(eth(2000))) }()
+	id := waitPending(t, queue, 1)[0].ID
+	assert.ErrorContains(t, <-done, "human approval timed out after 100ms")
+	assert.Empty(t, queue.List())
+	assert.ErrorIs(t, queue.Resolve(id, true, "late"), policies.ErrApprovalNotFound)
+
+	// Closing denies what is waiting, and anything after.
+	hitl = queueHITL(queue, time.Hour)
+	go func() { done <- hitl.Check(context.Background(), usdEvalCtx(eth(2000))) }()
+	waitPending(t, queue, 1)
+	queue.Close()
+	assert.ErrorContains(t, <-done, "approval queue closed")
+	assert.ErrorContains(t, hitl.Check(context.Background(), usdEvalCtx(eth(2000))), "closed")
+}
+
+func TestApprovalQueue_Quorum(t *testing.T) {
+	approvers := map[string]string{"ALICE": "alice-token", "BOB": "bob-token", "CAROL": "carol-token"}
+	for _, onDeny := range []string{"veto", "count"} {
+		t.Run(onDeny, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.log")
+			audit, err := observe.NewAuditLogger(path, true)
+			require.NoError(t, err)
+			defer audit.Close()
+			queue := policies.NewApprovalQueue(audit)
+			srv := httptest.NewServer(policies.NewApprovalHandlerWithApprovers(queue, approvalToken, approvers))
+			defer srv.Close()
+			hitl := queueHITL(queue, 5*time.Second)
+			require.NoError(t, hitl.SetTiers([]config.HITLTier{{
+				Threshold:         config.MustParseAmount("5 eth"),
+				ApprovalsRequired: 2,
+				Approvers:         []string{"ALICE", "BOB", "CAROL"},
+				OnDeny:            onDeny,
+			}}))
+
+			done := make(chan error, 1)
+			go func() { done <- hitl.Check(context.Background(), usdEvalCtx(eth(10000))) }()
+			pending := waitPending(t, queue, 1)[0]
+			assert.Equal(t, 2, pending.Required)
+			assert.Contains(t, pending.Details, "Approvals required: 2 of 3 (over 5000000000000000000 wei)")
+			resolve := func(action, token, body string) int {
+				status, _ := approvalAPI(t, srv, "POST", "/approvals/"+pending.ID+"/"+action, token, body)
+				return status
+			}
+
+			// Only the approvers, with their own tokens, count.
+			assert.Equal(t, http.StatusForbidden, resolve("approve", approvalToken, `{"by":"ALICE"}`))
+			assert.Equal(t, http.StatusForbidden, resolve("approve", approvalToken, `{"by":"dave"}`))
+			assert.Equal(t, http.StatusOK, resolve("deny", "bob-token", `{"by":"ALICE"}`))
+
+			if onDeny == "veto" {
+				assert.ErrorContains(t, <-done, "human rejected transaction (by BOB)")
+				entries := readAudit(t, path)
+				require.Len(t, entries, 1)
+				assert.Equal(t, "denied", entries[0].Extra["decision"])
+				assert.Equal(t, []interface{}{"BOB"}, entries[0].Extra["denials"])
+				return
+			}
+
+			// A denial counts against the quorum; two approvals are still
+			// possible.
+			assert.Equal(t, http.StatusConflict, resolve("approve", "bob-token", ""), "one decision per approver")
+			assert.Equal(t, http.StatusOK, resolve("approve", "alice-token", ""))
+			assert.Equal(t, http.StatusConflict, resolve("approve", "alice-token", ""))
+			listed := waitPending(t, queue, 1)[0]
+			assert.Equal(t, []string{"ALICE"}, listed.Approvals)
+			assert.Equal(t, []string{"BOB"}, listed.Denials)
+			assert.Equal(t, http.StatusOK, resolve("approve", "carol-token", ""))
+			require.NoError(t, <-done)
+
+			entries := readAudit(t, path)
+			require.Len(t, entries, 3)
+			assert.Equal(t, "partial_denial", entries[0].Extra["decision"])
+			assert.Equal(t, "partial_approval", entries[1].Extra["decision"])
+			assert.Equal(t, "ALICE", entries[1].Extra["approved_by"])
+			assert.Equal(t, "approved", entries[2].Extra["decision"])
+			assert.Equal(t, []interface{}{"ALICE", "CAROL"}, entries[2].Extra["approvals"])
+			assert.Equal(t, []interface{}{"BOB"}, entries[2].Extra["denials"])
+			assert.EqualValues(t, 2, entries[2].Extra["approvals_required"])
+
+			// A timeout records who had approved.
+			hitl = queueHITL(queue, 300*time.Millisecond)
+			require.NoError(t, hitl.SetTiers([]config.HITLTier{{
+				Threshold: config.MustParseAmount("5 eth"), ApprovalsRequired: 2,
+				Approvers: []string{"ALICE", "BOB", "CAROL"}, OnDeny: onDeny,
+			}}))
+			go func() { done <- hitl.Check(context.Background(), usdEvalCtx(eth(10000))) }()
+			pending = waitPending(t, queue, 1)[0]
+			assert.Equal(t, http.StatusOK, resolve("approve", "carol-token", ""))
+			assert.ErrorIs(t, <-done, policies.ErrApprovalTimeout)
+			entries = readAudit(t, path)
+			last := entries[len(entries)-1]
+			assert.Equal(t, "timeout", last.Extra["decision"])
+			assert.Equal(t, []interface{}{"CAROL"}, last.Extra["approvals"])
+		})
+	}
+}
+
+func TestApprovalQueue_State(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "approvals.json")
+	auditPath := filepath.Join(dir, "audit.log")
+	queue, err := policies.NewApprovalQueueWithState(nil, policies.LimitState{Path: statePath})
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() { done <- queueHITL(queue, time.Hour).Check(context.Background(), usdEvalCtx(eth(2000))) }()
+	id := waitPending(t, queue, 1)[0].ID
+	data, err := os.ReadFile(statePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), id)
+
+	// After a crash, the abandoned request is recorded as expired.
+	audit, err := observe.NewAuditLogger(auditPath, true)
+	require.NoError(t, err)
+	defer audit.Close()
+	logger := &errorLogger{}
+	restarted, err := policies.NewApprovalQueueWithState(audit, policies.LimitState{Path: statePath, Logger: logger})
+	require.NoError(t, err)
+	assert.Empty(t, restarted.List())
+	assert.Equal(t, []string{"1 pending approvals abandoned by a restart"}, logger.warnings)
+	entries := readAudit(t, auditPath)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "abandoned", entries[0].Extra["decision"])
+	assert.Equal(t, id, entries[0].Extra["approval_id"])
+	queue.Close()
+	<-done
+}
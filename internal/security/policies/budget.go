@@ -0,0 +1,115 @@
+// Package policies provides the per‑session spending budget policy.
+//
+// File: internal/security/policies/budget.go
+
+package policies
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// budgetTools are the tools whose amount counts against a session budget,
+// as against the daily limit. A cancel sends nothing but pays the fee of
+// its replacement.
+var budgetTools = map[string]bool{
+	"transfer":     true,
+	"send":         true,
+	"swap":         true,
+	"sign":         true,
+	"send_raw":     true,
+	"safe_propose": true,
+	"aa_send":      true,
+	"cancel":       true,
+}
+
+// SessionBudgetPolicy caps what one agent session, such as a single Run,
+// may spend in total on each chain, value and fees, whatever the daily
+// limit allows. The cap is the session's security.Budget, counted per
+// chain like the daily limit; sessions without one are not limited.
+// Check reserves an allowed spend in the budget, and Settle commits it if
+// the tool succeeded and releases it otherwise, so a send that fails does
+// not use up the budget. The budget lives in the session, so it is gone
+// when the session is.
+type SessionBudgetPolicy struct {
+	mu       sync.Mutex
+	reserved map[*security.EvaluationContext]budgetReservation // awaiting Settle
+}
+
+// budgetReservation is a spend reserved by Check and not settled yet.
+type budgetReservation struct {
+	budget *security.Budget
+	chain  string
+	amount *big.Int
+}
+
+// NewSessionBudgetPolicy creates the policy.
+func NewSessionBudgetPolicy() *SessionBudgetPolicy {
+	return &SessionBudgetPolicy{reserved: make(map[*security.EvaluationContext]budgetReservation)}
+}
+
+// Name returns "session_budget", the policy's name in decisions and audit entries.
+func (p *SessionBudgetPolicy) Name() string { return "session_budget" }
+
+// Check implements security.Policy.
+func (p *SessionBudgetPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	if !budgetTools[evalCtx.Tool] {
+		return nil
+	}
+	budget := evalCtx.Budget()
+	if budget == nil {
+		return nil
+	}
+	amount, ok := evalCtx.Args["amount"].(*big.Int)
+	if !ok {
+		return nil
+	}
+	fees, err := estimateFees(ctx, evalCtx)
+	if err != nil {
+		return err
+	}
+	spend := new(big.Int).Add(amount, fees)
+	used, ok := budget.Reserve(evalCtx.ChainName, spend)
+	if !ok {
+		return &security.ErrLimitExceeded{
+			Name: "session_budget", Unit: "wei", Limit: budget.Limit(), Attempted: spend, Spent: used,
+			Reason: fmt.Sprintf("session budget exceeded%s: budget %s, already spent %s, attempted +%s%s",
+				chainSuffix(evalCtx.ChainName), budget.Limit(), used, amount, feeSuffix(fees)),
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reserved[evalCtx] = budgetReservation{budget: budget, chain: evalCtx.ChainName, amount: spend}
+	return nil
+}
+
+// Settle implements security.Settler. It commits the spend reserved for
+// evalCtx if err is nil and releases it otherwise.
+func (p *SessionBudgetPolicy) Settle(ctx context.Context, evalCtx *security.EvaluationContext, err error) {
+	p.mu.Lock()
+	r, ok := p.reserved[evalCtx]
+	delete(p.reserved, evalCtx)
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+	if err == nil {
+		r.budget.Commit(r.chain, r.amount)
+	} else {
+		r.budget.Release(r.chain, r.amount)
+	}
+}
+
+// chainSuffix names chain in a denial, if known.
+func chainSuffix(chain string) string {
+	if chain == "" {
+		return ""
+	}
+	return " on " + chain
+}
+
+// EOF: internal/security/policies/budget.go
@@ -0,0 +1,90 @@
+package policies_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/core"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+func TestSessionBudgetPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy := policies.NewSessionBudgetPolicy()
+	sess := core.NewSession(&observe.NoopLogger{}, "", nil)
+	sess.SetBudget(eth(50)) // 0.05 eth
+	send := func(session interface{}, tool string, milli int64) *security.EvaluationContext {
+		return &security.EvaluationContext{Tool: tool, Args: map[string]interface{}{"amount": eth(milli)}, Session: session}
+	}
+
+	first := send(sess, "transfer", 30)
+	require.NoError(t, policy.Check(ctx, first))
+	remaining, ok := sess.RemainingBudget("")
+	require.True(t, ok)
+	assert.Equal(t, eth(20), remaining, "reserved spends count")
+
+	err := policy.Check(ctx, send(sess, "send", 30))
+	assert.EqualError(t, err, "session budget exceeded: budget 50000000000000000, already spent 30000000000000000, attempted +30000000000000000")
+	var exceeded *security.ErrLimitExceeded
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, "session_budget", exceeded.Name)
+
+	// A failed send gives its spend back; a successful one keeps it.
+	policy.Settle(ctx, first, errors.New("nonce too low"))
+	remaining, _ = sess.RemainingBudget("")
+	assert.Equal(t, eth(50), remaining)
+	second := send(sess, "send", 40)
+	require.NoError(t, policy.Check(ctx, second))
+	policy.Settle(ctx, second, nil)
+	assert.Equal(t, eth(40), sess.Budget().Spent(""))
+	assert.Error(t, policy.Check(ctx, send(sess, "transfer", 11)))
+	assert.NoError(t, policy.Check(ctx, send(sess, "balance", 11)), "reads are not counted")
+
+	// Other sessions have budgets of their own, or none.
+	other := core.NewSession(&observe.NoopLogger{}, "", nil)
+	assert.NoError(t, policy.Check(ctx, send(other, "transfer", 1000)))
+	_, ok = other.RemainingBudget("")
+	assert.False(t, ok)
+	other.SetBudget(big.NewInt(0))
+	assert.Error(t, policy.Check(ctx, send(other, "transfer", 1)))
+}
+
+func TestSessionBudgetPolicy_PerChain(t *testing.T) {
+	ctx := context.Background()
+	policy := policies.NewSessionBudgetPolicy()
+	sess := core.NewSessionWithChains(&observe.NoopLogger{}, "ethereum", map[string]blockchain.Chain{
+		"ethereum": &feeChain{}, "polygon": &feeChain{},
+	})
+	sess.SetBudget(eth(50))
+	spend := func(chain string, milli int64) *security.EvaluationContext {
+		return &security.EvaluationContext{Tool: "transfer", Args: map[string]interface{}{"amount": eth(milli)}, Session: sess, ChainName: chain}
+	}
+
+	// 0.03 ETH and 0.03 MATIC are not added up.
+	onEthereum := spend("ethereum", 30)
+	require.NoError(t, policy.Check(ctx, onEthereum))
+	policy.Settle(ctx, onEthereum, nil)
+	require.NoError(t, policy.Check(ctx, spend("polygon", 30)))
+	remaining, ok := sess.RemainingBudget("")
+	require.True(t, ok)
+	assert.Equal(t, eth(20), remaining, "the default chain")
+	remaining, _ = sess.RemainingBudget("Polygon")
+	assert.Equal(t, eth(20), remaining)
+	_, ok = sess.RemainingBudget("polgon")
+	assert.False(t, ok, "an unknown chain is not an untouched budget")
+	assert.Equal(t, eth(30), sess.Budget().Spent("ethereum"))
+	assert.Equal(t, new(big.Int), sess.Budget().Spent("polygon"), "not settled yet")
+
+	err := policy.Check(ctx, spend("polygon", 30))
+	assert.ErrorContains(t, err, "session budget exceeded on polygon: budget 50000000000000000, already spent 30000000000000000")
+	assert.Error(t, policy.Check(ctx, spend("ethereum", 21)))
+	assert.NoError(t, policy.Check(ctx, spend("ethereum", 20)))
+}
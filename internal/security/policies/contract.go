@@ -0,0 +1,149 @@
+// Package policies provides the contract method allowlist policy.
+//
+// File: internal/security/policies/contract.go
+
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// contractTools are the tools that send a transaction, or make an account
+// send one, to the to and data of their arguments.
+var contractTools = map[string]bool{
+	"transfer":     true,
+	"send":         true,
+	"sign":         true,
+	"send_raw":     true,
+	"deploy":       true,
+	"safe_propose": true,
+	"aa_send":      true,
+}
+
+// ContractPolicy restricts which contracts transactions call and which
+// methods they call on each, by the 4‑byte selector that starts the
+// transaction's data. Transactions without data (plain value transfers)
+// and contract creations are allowed only if enabled. A denied call is
+// named by its method signature if the contract's ABI is known.
+type ContractPolicy struct {
+	contracts      map[string]map[[4]byte]bool // address -> allowed selectors
+	signatures     map[[4]byte]string          // configured signatures by selector
+	allowTransfers bool
+	allowDeploy    bool
+}
+
+// NewContractPolicy creates a policy allowing the methods of each contract
+// address in contracts, given as selectors in hex or as signatures (see
+// evm.ParseSelector).
+func NewContractPolicy(contracts map[string][]string, allowTransfers, allowDeploy bool) (*ContractPolicy, error) {
+	p := &ContractPolicy{
+		contracts:      make(map[string]map[[4]byte]bool, len(contracts)),
+		signatures:     make(map[[4]byte]string),
+		allowTransfers: allowTransfers,
+		allowDeploy:    allowDeploy,
+	}
+	for addr, methods := range contracts {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("contract allowlist: invalid address %q", addr)
+		}
+		selectors := make(map[[4]byte]bool, len(methods))
+		for _, method := range methods {
+			selector, sig, err := evm.ParseSelector(method)
+			if err != nil {
+				return nil, fmt.Errorf("contract allowlist: %s: %w", addr, err)
+			}
+			selectors[selector] = true
+			if sig != "" {
+				p.signatures[selector] = sig
+			}
+		}
+		p.contracts[common.HexToAddress(addr).Hex()] = selectors
+	}
+	return p, nil
+}
+
+// Name returns "contract_allowlist", the policy's name in decisions and audit entries.
+func (p *ContractPolicy) Name() string { return "contract_allowlist" }
+
+// Check implements security.Policy.
+func (p *ContractPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	if !contractTools[evalCtx.Tool] {
+		return nil
+	}
+	tx := evalCtx.Transaction()
+	if _, ok := evalCtx.Deployment(); ok {
+		if p.allowDeploy {
+			return nil
+		}
+		return errors.New("contract allowlist: contract creation is not allowed")
+	}
+	to := *tx.To
+	if !common.IsHexAddress(to) {
+		resolved, err := resolveName(ctx, evalCtx, to)
+		if err != nil {
+			return err
+		}
+		to = resolved
+	}
+	to = common.HexToAddress(to).Hex()
+
+	if len(tx.Data) == 0 {
+		if p.allowTransfers {
+			return nil
+		}
+		return fmt.Errorf("contract allowlist: plain transfer to %s is not allowed", to)
+	}
+	if len(tx.Data) < 4 {
+		return fmt.Errorf("contract allowlist: data to %s is %d bytes, shorter than a selector", to, len(tx.Data))
+	}
+	var selector [4]byte
+	copy(selector[:], tx.Data)
+	selectors, known := p.contracts[to]
+	if selectors[selector] {
+		return nil
+	}
+	method := p.methodName(ctx, evalCtx, to, tx.Data)
+	if !known {
+		return fmt.Errorf("contract allowlist: contract %s is not allowed (calling %s)", to, method)
+	}
+	return fmt.Errorf("contract allowlist: method %s is not allowed on %s", method, to)
+}
+
+// abiChain is implemented by chains that look up contract ABIs, such as
+// the EVM gateway.
+type abiChain interface {
+	ABIResolver() evm.ABIResolver
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// methodName names the method data calls on address: by its signature in
+// the contract's ABI if the session's chain can look it up, else by a
+// configured signature, else by its selector.
+func (p *ContractPolicy) methodName(ctx context.Context, evalCtx *security.EvaluationContext, address string, data []byte) string {
+	if chain, ok := evalCtx.Chain().(abiChain); ok && chain.ABIResolver() != nil {
+		if chainID, err := chain.ChainID(ctx); err == nil {
+			if abiJSON, err := chain.ABIResolver().ResolveABI(ctx, chainID.Uint64(), address); err == nil {
+				if sig, err := evm.MethodSignature(abiJSON, data); err == nil {
+					return sig
+				}
+			}
+		}
+	}
+	var selector [4]byte
+	copy(selector[:], data)
+	if sig, ok := p.signatures[selector]; ok {
+		return sig
+	}
+	return hexutil.Encode(data[:4])
+}
+
+// EOF: internal/security/policies/contract.go
@@ -0,0 +1,95 @@
+package policies_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+const (
+	token = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	vault = "0x5aFE3855358E112B5647B952709E6165e1c1eEEe"
+)
+
+const erc20ABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"transferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"increaseAllowance","inputs":[{"name":"spender","type":"address"},{"name":"addedValue","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"}
+]`
+
+// abiChain knows the ABI of every contract: the ERC‑20 ABI.
+type abiChain struct {
+	blockchain.Chain
+}
+
+func (abiChain) ChainID(context.Context) (*big.Int, error) { return big.NewInt(1), nil }
+
+func (abiChain) ABIResolver() evm.ABIResolver { return erc20Resolver{} }
+
+type erc20Resolver struct{}
+
+func (erc20Resolver) ResolveABI(context.Context, uint64, string) (string, error) {
+	return erc20ABI, nil
+}
+
+func call(t *testing.T, method string, args ...interface{}) []byte {
+	t.Helper()
+	data, err := evm.EncodeCall(erc20ABI, method, args...)
+	require.NoError(t, err)
+	return data
+}
+
+func TestContractPolicy(t *testing.T) {
+	policy, err := policies.NewContractPolicy(map[string][]string{
+		// Keys are lower‑cased when read from YAML.
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": {"approve(address, uint256)", "0xa9059cbb"},
+	}, false, false)
+	require.NoError(t, err)
+	ctx := context.Background()
+	check := func(chain blockchain.Chain, tool string, args map[string]interface{}) error {
+		return policy.Check(ctx, &security.EvaluationContext{
+			Tool:    tool,
+			Args:    args,
+			Session: &chainSession{mockSession{id: "s1"}, chain},
+		})
+	}
+	amount := big.NewInt(1000)
+	spender := common.HexToAddress(vault)
+
+	assert.NoError(t, check(nil, "send", map[string]interface{}{"to": token, "data": call(t, "approve", spender, amount)}))
+	assert.NoError(t, check(nil, "sign", map[string]interface{}{"to": token, "data": call(t, "transfer", spender, amount)}))
+	assert.NoError(t, check(nil, "balance", map[string]interface{}{"address": vault}), "reads are not restricted")
+
+	err = check(abiChain{}, "send", map[string]interface{}{"to": token, "data": call(t, "transferFrom", spender, spender, amount)})
+	assert.ErrorContains(t, err, "method transferFrom(address,address,uint256) is not allowed on "+token, "decoded with the ABI")
+	err = check(nil, "send", map[string]interface{}{"to": token, "data": call(t, "transferFrom", spender, spender, amount)})
+	assert.ErrorContains(t, err, "method 0x23b872dd is not allowed", "selector without an ABI")
+
+	err = check(nil, "aa_send", map[string]interface{}{"to": vault, "data": call(t, "approve", spender, amount)})
+	assert.ErrorContains(t, err, "contract "+vault+" is not allowed (calling approve(address,uint256))", "configured signature")
+	err = check(nil, "send", map[string]interface{}{"to": token, "data": []byte{0x09, 0x5e}})
+	assert.ErrorContains(t, err, "shorter than a selector")
+
+	err = check(nil, "transfer", map[string]interface{}{"to": vault, "amount": amount})
+	assert.ErrorContains(t, err, "plain transfer to "+vault+" is not allowed")
+	err = check(nil, "deploy", map[string]interface{}{"bytecode": "6000"})
+	assert.ErrorContains(t, err, "contract creation is not allowed")
+
+	permissive, err := policies.NewContractPolicy(nil, true, true)
+	require.NoError(t, err)
+	assert.NoError(t, permissive.Check(ctx, &security.EvaluationContext{Tool: "transfer", Args: map[string]interface{}{"to": vault, "amount": amount}}))
+	assert.NoError(t, permissive.Check(ctx, &security.EvaluationContext{Tool: "deploy", Args: map[string]interface{}{"bytecode": []byte{0x60}}}))
+
+	_, err = policies.NewContractPolicy(map[string][]string{token: {"approve"}}, false, false)
+	assert.Error(t, err)
+}
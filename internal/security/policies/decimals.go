@@ -0,0 +1,102 @@
+// Package policies provides the decimals of tokens with configured amounts.
+//
+// File: internal/security/policies/decimals.go
+
+package policies
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/sdk/types/units"
+)
+
+// decimalsSelector is the selector of decimals().
+var decimalsSelector = []byte{0x31, 0x3c, 0xe5, 0x67}
+
+// tokenDecimals is the decimals of a token whose limits are configured in
+// whole tokens. Once the decimals are known, from the configuration or the
+// chain, convert turns the configured amounts into base units; format
+// renders base unit amounts like the configuration.
+type tokenDecimals struct {
+	decimals int    // -1 until known
+	symbol   string // of the configured amounts; "" = none
+	convert  func(decimals int) error
+}
+
+// newTokenDecimals returns unknown decimals of a token whose configured
+// amounts are in symbol and converted by convert. If configured is not
+// nil, the decimals are set to it.
+func newTokenDecimals(configured *uint8, symbol string, convert func(decimals int) error) (tokenDecimals, error) {
+	d := tokenDecimals{decimals: -1, symbol: symbol, convert: convert}
+	if configured != nil {
+		if err := d.setDecimals(int(*configured)); err != nil {
+			return tokenDecimals{}, err
+		}
+	}
+	return d, nil
+}
+
+// setDecimals converts the configured amounts to base units.
+func (d *tokenDecimals) setDecimals(decimals int) error {
+	if err := d.convert(decimals); err != nil {
+		return err
+	}
+	d.decimals = decimals
+	return nil
+}
+
+// resolveDecimals reads the token's decimals from the chain unless known.
+// mu guards d and the amounts convert sets.
+func (d *tokenDecimals) resolveDecimals(ctx context.Context, evalCtx *security.EvaluationContext, token string, mu *sync.Mutex) error {
+	mu.Lock()
+	known := d.decimals >= 0
+	mu.Unlock()
+	if known {
+		return nil
+	}
+	if evalCtx.Chain() == nil {
+		return fmt.Errorf("token %s: decimals not configured and no chain to read them from", token)
+	}
+	decimals, err := readDecimals(ctx, evalCtx, token)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if err := d.setDecimals(decimals); err != nil {
+		return fmt.Errorf("token %s: %w", token, err)
+	}
+	return nil
+}
+
+// format renders a base unit amount of the token like its configuration.
+func (d *tokenDecimals) format(v *big.Int) string {
+	s := units.FormatUnits(v, d.decimals, -1)
+	if d.symbol != "" {
+		return s + " " + d.symbol
+	}
+	return s
+}
+
+// readDecimals calls decimals() on token with the session's chain.
+func readDecimals(ctx context.Context, evalCtx *security.EvaluationContext, token string) (int, error) {
+	chain := evalCtx.Chain()
+	if chain == nil {
+		return 0, fmt.Errorf("token %s: no chain to read decimals from", token)
+	}
+	out, err := chain.CallContract(ctx, &blockchain.ContractCall{To: token, Data: decimalsSelector})
+	if err != nil {
+		return 0, fmt.Errorf("token %s: read decimals: %w", token, err)
+	}
+	if len(out) != 32 || new(big.Int).SetBytes(out).Cmp(big.NewInt(255)) > 0 {
+		return 0, fmt.Errorf("token %s: read decimals: invalid result %#x", token, out)
+	}
+	return int(out[31]), nil
+}
+
+// EOF: internal/security/policies/decimals.go
@@ -0,0 +1,345 @@
+// Package policies provides the address denylist feed policy.
+//
+// File: internal/security/policies/denylist.go
+
+package policies
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+const (
+	// defaultDenylistRefresh is how often feeds are reloaded by default.
+	defaultDenylistRefresh = time.Hour
+	// defaultDenylistMaxStale is how long a feed may fail to load by
+	// default before on_stale applies.
+	defaultDenylistMaxStale = 24 * time.Hour
+	// denylistFetchTimeout bounds one fetch of a feed.
+	denylistFetchTimeout = 30 * time.Second
+	// maxDenylistSize bounds the body of a feed.
+	maxDenylistSize = 64 << 20
+)
+
+// DenylistFeedPolicy denies operations whose destinations are on
+// externally maintained address lists, such as sanctions or known‑scam
+// lists. Destinations are found as by WhitelistPolicy: the target and the
+// inner recipient or spender of ERC‑20 calls.
+//
+// Feeds are loaded by Refresh, and every refresh interval once Start is
+// called. A feed that fails to load keeps its last addresses; an update
+// that does not parse is a failure, so a broken feed is never emptied.
+// Once a feed has not loaded for max_stale, the policy logs a warning or,
+// with on_stale "deny", denies every operation it checks.
+type DenylistFeedPolicy struct {
+	feeds     []*denylistFeed
+	interval  time.Duration
+	maxStale  time.Duration
+	denyStale bool
+	client    *http.Client
+
+	mu     sync.RWMutex // guards the feeds' state and logger
+	logger observe.Logger
+
+	refreshMu sync.Mutex // serializes refreshes
+	startOnce sync.Once
+	cancel    context.CancelFunc // stops the reloads; nil unless started
+	done      chan struct{}      // closed when the reloads have stopped
+}
+
+// denylistFeed is one list and its state, guarded by
+// DenylistFeedPolicy.mu.
+type denylistFeed struct {
+	name, file, url string
+
+	addrs  map[common.Address]bool
+	etag   string
+	loaded time.Time // of the last successful load or, before, the policy's creation
+	warned bool      // whether staleness was logged since
+}
+
+// NewDenylistFeedPolicy creates a policy from cfg. No feed is loaded
+// until Refresh is called.
+func NewDenylistFeedPolicy(cfg *config.DenylistConfig) (*DenylistFeedPolicy, error) {
+	p := &DenylistFeedPolicy{
+		interval: cfg.Refresh,
+		maxStale: cfg.MaxStale,
+		client:   &http.Client{Timeout: denylistFetchTimeout},
+		done:     make(chan struct{}),
+	}
+	if p.interval <= 0 {
+		p.interval = defaultDenylistRefresh
+	}
+	if p.maxStale <= 0 {
+		p.maxStale = defaultDenylistMaxStale
+	}
+	switch cfg.OnStale {
+	case "", "warn":
+	case "deny":
+		p.denyStale = true
+	default:
+		return nil, fmt.Errorf("denylist: invalid on_stale %q", cfg.OnStale)
+	}
+	if len(cfg.Feeds) == 0 {
+		return nil, errors.New("denylist: no feeds")
+	}
+	now := time.Now()
+	for _, f := range cfg.Feeds {
+		if f.Name == "" || (f.File == "") == (f.URL == "") {
+			return nil, fmt.Errorf("denylist: feed %q needs a name and one of file and url", f.Name)
+		}
+		p.feeds = append(p.feeds, &denylistFeed{name: f.Name, file: f.File, url: f.URL, loaded: now})
+	}
+	return p, nil
+}
+
+// SetLogger sets the logger that records loads, failures and stale feeds
+// (nil = not logged).
+func (p *DenylistFeedPolicy) SetLogger(logger observe.Logger) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.logger = logger
+}
+
+// Name returns "denylist", the policy's name in decisions and audit entries.
+func (p *DenylistFeedPolicy) Name() string { return "denylist" }
+
+// Check implements security.Policy.
+func (p *DenylistFeedPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	to, ok := evalCtx.Args["to"].(string)
+	if !ok || to == "" {
+		return nil // not a transfer/contract call
+	}
+	if err := p.checkStale(); err != nil {
+		return err
+	}
+	target, err := targetDestination(ctx, evalCtx, to)
+	if err != nil {
+		return fmt.Errorf("denylist: %w", err)
+	}
+	dests := []destination{target}
+	if inner, ok := innerRecipient(evalCtx); ok {
+		dests = append(dests, inner)
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, d := range dests {
+		for _, f := range p.feeds {
+			if f.addrs[d.addr] {
+				return d.blockedBy(f.name, fmt.Sprintf("denylist: address %s is on the %s list", d.display, f.name))
+			}
+		}
+	}
+	return nil
+}
+
+// checkStale denies if a feed is stale and on_stale is "deny", and logs
+// a stale feed once otherwise.
+func (p *DenylistFeedPolicy) checkStale() error {
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, f := range p.feeds {
+		age := now.Sub(f.loaded)
+		if age <= p.maxStale {
+			continue
+		}
+		if p.denyStale {
+			return fmt.Errorf("denylist: feed %s has not loaded for %s", f.name, age.Round(time.Second))
+		}
+		if !f.warned && p.logger != nil {
+			p.logger.Warn("denylist feed is stale, checking against the addresses last loaded", map[string]interface{}{
+				"feed":      f.name,
+				"addresses": len(f.addrs),
+				"stale_for": age.Round(time.Second).String(),
+				"max_stale": p.maxStale.String(),
+			})
+		}
+		f.warned = true
+	}
+	return nil
+}
+
+// Refresh loads every feed once, and returns the failures joined. A feed
+// that fails keeps its last addresses.
+func (p *DenylistFeedPolicy) Refresh(ctx context.Context) error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+	var errs []error
+	for _, f := range p.feeds {
+		if err := p.load(ctx, f); err != nil {
+			errs = append(errs, fmt.Errorf("denylist: feed %s: %w", f.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// load loads f and logs the outcome.
+func (p *DenylistFeedPolicy) load(ctx context.Context, f *denylistFeed) error {
+	p.mu.RLock()
+	etag, logger := f.etag, p.logger
+	p.mu.RUnlock()
+
+	var data []byte
+	var err error
+	if f.file != "" {
+		data, err = os.ReadFile(f.file)
+	} else {
+		data, etag, err = p.fetch(ctx, f.url, etag)
+	}
+	var addrs map[common.Address]bool
+	if err == nil && data != nil {
+		addrs, err = parseDenylist(data)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		if logger != nil {
+			logger.Warn("denylist feed failed to load", map[string]interface{}{
+				"feed":      f.name,
+				"error":     err.Error(),
+				"stale_for": time.Since(f.loaded).Round(time.Second).String(),
+			})
+		}
+		return err
+	}
+	f.loaded, f.warned = time.Now(), false
+	if addrs == nil {
+		return nil // not modified
+	}
+	f.addrs, f.etag = addrs, etag
+	if logger != nil {
+		logger.Info("denylist feed loaded", map[string]interface{}{"feed": f.name, "addresses": len(addrs)})
+	}
+	return nil
+}
+
+// fetch fetches url, conditionally on etag if set. It returns nil data if
+// the list has not changed, and the list's ETag.
+func (p *DenylistFeedPolicy) fetch(ctx context.Context, url, etag string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, etag, nil
+	default:
+		return nil, "", fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDenylistSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("GET %s: %w", url, err)
+	}
+	if len(data) > maxDenylistSize {
+		return nil, "", fmt.Errorf("GET %s: list larger than %d bytes", url, maxDenylistSize)
+	}
+	return data, resp.Header.Get("ETag"), nil
+}
+
+// parseDenylist parses a list: a JSON array of addresses, a JSON object
+// with an "addresses" array, or one address per line with # comments.
+// Every address must be valid, checksum included.
+func parseDenylist(data []byte) (map[common.Address]bool, error) {
+	var entries []string
+	addrs := make(map[common.Address]bool)
+	switch trimmed := bytes.TrimSpace(data); {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("parse list: %w", err)
+		}
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var list struct {
+			Addresses []string `json:"addresses"`
+		}
+		if err := json.Unmarshal(trimmed, &list); err != nil {
+			return nil, fmt.Errorf("parse list: %w", err)
+		}
+		entries = list.Addresses
+	default:
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for line := 1; scanner.Scan(); line++ {
+			entry, _, _ := strings.Cut(scanner.Text(), "#")
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			addr, err := evm.NormalizeAddress(entry)
+			if err != nil {
+				return nil, fmt.Errorf("parse list: line %d: %w", line, err)
+			}
+			addrs[common.HexToAddress(addr)] = true
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("parse list: %w", err)
+		}
+	}
+	for _, entry := range entries {
+		addr, err := evm.NormalizeAddress(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, fmt.Errorf("parse list: %w", err)
+		}
+		addrs[common.HexToAddress(addr)] = true
+	}
+	return addrs, nil
+}
+
+// Start reloads the feeds every refresh interval in the background, until
+// Close. Failures are logged.
+func (p *DenylistFeedPolicy) Start() {
+	p.startOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		p.cancel = cancel
+		go func() {
+			defer close(p.done)
+			ticker := time.NewTicker(p.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					_ = p.Refresh(ctx)
+				}
+			}
+		}()
+	})
+}
+
+// Close stops the background reloads, cancelling one in progress, and
+// waits for them to end. The policy cannot be started afterwards.
+func (p *DenylistFeedPolicy) Close() {
+	p.startOnce.Do(func() { close(p.done) })
+	if p.cancel != nil {
+		p.cancel()
+	}
+	<-p.done
+}
+
+// EOF: internal/security/policies/denylist.go
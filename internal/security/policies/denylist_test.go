@@ -0,0 +1,183 @@
+package policies_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+// listServer serves a list with an ETag per version, answers
+// If-None-Match with 304, and fails with 500 while broken.
+type listServer struct {
+	mu          sync.Mutex
+	body        string
+	version     int
+	broken      bool
+	notModified int
+}
+
+func (s *listServer) set(body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body, s.version = body, s.version+1
+}
+
+func (s *listServer) setBroken(broken bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.broken = broken
+}
+
+func (s *listServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.broken {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+		return
+	}
+	etag := fmt.Sprintf(`"v%d"`, s.version)
+	if r.Header.Get("If-None-Match") == etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	fmt.Fprint(w, s.body)
+}
+
+func newDenylist(t *testing.T, cfg config.DenylistConfig) *policies.DenylistFeedPolicy {
+	t.Helper()
+	p, err := policies.NewDenylistFeedPolicy(&cfg)
+	require.NoError(t, err)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func sendTo(to string) *security.EvaluationContext {
+	return &security.EvaluationContext{Tool: "transfer", Args: map[string]interface{}{"to": to}}
+}
+
+func TestDenylistFeedPolicy_URL(t *testing.T) {
+	const stranger = "0x000000000000000000000000000000000000dEaD"
+	ctx := context.Background()
+	srv := &listServer{}
+	srv.set(`["` + recipient + `"]`)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	p := newDenylist(t, config.DenylistConfig{Feeds: []config.DenylistFeed{{Name: "ofac", URL: ts.URL}}})
+	assert.NoError(t, p.Check(ctx, sendTo(recipient)), "not loaded yet")
+	require.NoError(t, p.Refresh(ctx))
+	err := p.Check(ctx, sendTo(recipient))
+	assert.EqualError(t, err, "denylist: address "+recipient+" is on the ofac list")
+	var blocked *security.ErrAddressBlocked
+	require.ErrorAs(t, err, &blocked)
+	assert.Equal(t, "ofac", blocked.List)
+	assert.NoError(t, p.Check(ctx, sendTo(stranger)))
+	assert.NoError(t, p.Check(ctx, &security.EvaluationContext{Tool: "balance", Args: map[string]interface{}{}}))
+
+	// The recipient of a token call is checked too.
+	data := call(t, "transfer", common.HexToAddress(recipient), big.NewInt(5))
+	assert.ErrorContains(t, p.Check(ctx, tokenEvalCtx(nil, token, data)), "is on the ofac list")
+
+	require.NoError(t, p.Refresh(ctx))
+	assert.Equal(t, 1, srv.notModified, "unchanged lists are not downloaded again")
+
+	srv.set("# updated\n" + stranger + "\n")
+	require.NoError(t, p.Refresh(ctx))
+	assert.NoError(t, p.Check(ctx, sendTo(recipient)))
+	assert.ErrorContains(t, p.Check(ctx, sendTo(stranger)), "is on the ofac list")
+
+	srv.set(`{"addresses": ["0x1234"]}`)
+	assert.ErrorContains(t, p.Refresh(ctx), "denylist: feed ofac: parse list")
+	assert.ErrorContains(t, p.Check(ctx, sendTo(stranger)), "is on the ofac list", "a bad update keeps the list")
+}
+
+func TestDenylistFeedPolicy_File(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "scams.txt")
+	require.NoError(t, os.WriteFile(path, []byte(`{"addresses": ["`+dai+`"]}`), 0o600))
+
+	p := newDenylist(t, config.DenylistConfig{Feeds: []config.DenylistFeed{{Name: "scams", File: path}}})
+	require.NoError(t, p.Refresh(ctx))
+	assert.EqualError(t, p.Check(ctx, sendTo(dai)), "denylist: address "+dai+" is on the scams list")
+
+	require.NoError(t, os.Remove(path))
+	assert.Error(t, p.Refresh(ctx))
+	assert.Error(t, p.Check(ctx, sendTo(dai)), "a missing file keeps the list")
+
+	for _, cfg := range []config.DenylistConfig{
+		{},
+		{Feeds: []config.DenylistFeed{{Name: "scams"}}},
+		{Feeds: []config.DenylistFeed{{Name: "scams", File: path}}, OnStale: "ignore"},
+	} {
+		_, err := policies.NewDenylistFeedPolicy(&cfg)
+		assert.Error(t, err)
+	}
+}
+
+func TestDenylistFeedPolicy_Stale(t *testing.T) {
+	ctx := context.Background()
+	srv := &listServer{}
+	srv.set(recipient)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	feeds := []config.DenylistFeed{{Name: "ofac", URL: ts.URL}}
+
+	warn := newDenylist(t, config.DenylistConfig{Feeds: feeds, MaxStale: 50 * time.Millisecond})
+	deny := newDenylist(t, config.DenylistConfig{Feeds: feeds, MaxStale: 50 * time.Millisecond, OnStale: "deny"})
+	logger := &errorLogger{}
+	warn.SetLogger(logger)
+	require.NoError(t, warn.Refresh(ctx))
+	require.NoError(t, deny.Refresh(ctx))
+
+	srv.setBroken(true)
+	assert.Error(t, warn.Refresh(ctx))
+	assert.Error(t, deny.Refresh(ctx))
+	time.Sleep(100 * time.Millisecond)
+
+	assert.ErrorContains(t, deny.Check(ctx, sendTo(owner)), "denylist: feed ofac has not loaded for")
+	assert.NoError(t, warn.Check(ctx, sendTo(owner)))
+	assert.ErrorContains(t, warn.Check(ctx, sendTo(recipient)), "is on the ofac list")
+	assert.Equal(t, []string{
+		"denylist feed failed to load",
+		"denylist feed is stale, checking against the addresses last loaded",
+	}, logger.warnings, "stale feeds are logged once")
+
+	srv.setBroken(false)
+	require.NoError(t, deny.Refresh(ctx))
+	assert.NoError(t, deny.Check(ctx, sendTo(owner)))
+}
+
+func TestDenylistFeedPolicy_Start(t *testing.T) {
+	ctx := context.Background()
+	srv := &listServer{}
+	srv.set("")
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	p := newDenylist(t, config.DenylistConfig{
+		Feeds:   []config.DenylistFeed{{Name: "ofac", URL: ts.URL}},
+		Refresh: 10 * time.Millisecond,
+	})
+	require.NoError(t, p.Refresh(ctx))
+	p.Start()
+	srv.set(recipient)
+	assert.Eventually(t, func() bool { return p.Check(ctx, sendTo(recipient)) != nil }, 2*time.Second, 10*time.Millisecond)
+	p.Close()
+}
@@ -0,0 +1,114 @@
+// Package policies provides the deployment bytecode allowlist policy.
+//
+// File: internal/security/policies/deploy.go
+
+package policies
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// DeployPolicy restricts contract creation to known creation bytecode, so
+// that an agent cannot deploy bytecode it made up. A deployment is allowed
+// if the keccak‑256 hash of its creation data is allowed, or if the data
+// is allowed bytecode, or starts with it when constructor arguments are
+// ignored. A denial names the hash of the creation data, ready to be added
+// to allowed_hashes.
+type DeployPolicy struct {
+	allowDeploy bool
+	hashes      map[common.Hash]bool
+	bytecode    [][]byte // matched as prefixes
+	ignoreArgs  bool
+}
+
+// NewDeployPolicy creates a policy from cfg, reading the bytecode of the
+// artifacts it names.
+func NewDeployPolicy(cfg *config.DeployConfig) (*DeployPolicy, error) {
+	p := &DeployPolicy{
+		allowDeploy: cfg.AllowDeploy,
+		hashes:      make(map[common.Hash]bool, len(cfg.AllowedHashes)+len(cfg.AllowedBytecode)),
+		ignoreArgs:  cfg.IgnoreConstructorArgs,
+	}
+	for _, h := range cfg.AllowedHashes {
+		hash, err := hexutil.Decode(h)
+		if err != nil || len(hash) != common.HashLength {
+			return nil, fmt.Errorf("deploy: invalid bytecode hash %q", h)
+		}
+		p.hashes[common.BytesToHash(hash)] = true
+	}
+	for _, entry := range cfg.AllowedBytecode {
+		code, err := deployBytecode(entry)
+		if err != nil {
+			return nil, fmt.Errorf("deploy: %w", err)
+		}
+		p.hashes[crypto.Keccak256Hash(code)] = true
+		if p.ignoreArgs {
+			p.bytecode = append(p.bytecode, code)
+		}
+	}
+	return p, nil
+}
+
+// deployBytecode returns the bytecode of an allowed_bytecode entry: 0x‑hex,
+// or the creation bytecode of the artifact at the path.
+func deployBytecode(entry string) ([]byte, error) {
+	if strings.HasPrefix(entry, "0x") {
+		code, err := hexutil.Decode(entry)
+		if err != nil || len(code) == 0 {
+			return nil, errors.New("invalid hex bytecode")
+		}
+		return code, nil
+	}
+	artifact, err := evm.LoadArtifact(entry)
+	if err != nil {
+		return nil, err
+	}
+	if len(artifact.Bytecode) == 0 {
+		return nil, fmt.Errorf("artifact %s has no creation bytecode", entry)
+	}
+	return artifact.Bytecode, nil
+}
+
+// Name returns "deploy", the policy's name in decisions and audit entries.
+func (p *DeployPolicy) Name() string { return "deploy" }
+
+// Check implements security.Policy.
+func (p *DeployPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	if !contractTools[evalCtx.Tool] {
+		return nil
+	}
+	data, ok := evalCtx.Deployment()
+	if !ok {
+		return nil
+	}
+	if len(data) == 0 {
+		return errors.New("deploy: contract creation without bytecode")
+	}
+	hash := crypto.Keccak256Hash(data)
+	if !p.allowDeploy {
+		return fmt.Errorf("deploy: contract creation is not allowed (bytecode hash %s)", hash.Hex())
+	}
+	if len(p.hashes) == 0 || p.hashes[hash] {
+		return nil
+	}
+	for _, code := range p.bytecode {
+		if bytes.HasPrefix(data, code) {
+			return nil
+		}
+	}
+	return fmt.Errorf("deploy: bytecode hash %s is not allowed", hash.Hex())
+}
+
+// EOF: internal/security/policies/deploy.go
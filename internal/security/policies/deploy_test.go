@@ -0,0 +1,128 @@
+package policies_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+func TestDeployPolicy(t *testing.T) {
+	audited := common.FromHex("0x6080604052348015600e575f5ffd5b50")
+	other := common.FromHex("0x6080604052348015600e575f5ffd5b51")
+	ctorArgs := common.LeftPadBytes([]byte{42}, 32)
+	withArgs := append(append([]byte(nil), audited...), ctorArgs...)
+
+	ctx := context.Background()
+	deploy := func(p *policies.DeployPolicy, bytecode interface{}) error {
+		return p.Check(ctx, &security.EvaluationContext{
+			Tool: "deploy",
+			Args: map[string]interface{}{"bytecode": bytecode},
+		})
+	}
+	newPolicy := func(cfg config.DeployConfig) *policies.DeployPolicy {
+		p, err := policies.NewDeployPolicy(&cfg)
+		require.NoError(t, err)
+		return p
+	}
+
+	t.Run("matching", func(t *testing.T) {
+		byHash := newPolicy(config.DeployConfig{AllowDeploy: true, AllowedHashes: []string{crypto.Keccak256Hash(audited).Hex()}})
+		assert.NoError(t, deploy(byHash, audited))
+		assert.NoError(t, deploy(byHash, hexutil.Encode(audited)), "0x‑hex string")
+		assert.NoError(t, deploy(byHash, hexutil.Encode(audited)[2:]), "hex string")
+
+		byCode := newPolicy(config.DeployConfig{AllowDeploy: true, AllowedBytecode: []string{hexutil.Encode(audited)}})
+		assert.NoError(t, deploy(byCode, audited))
+		assert.ErrorContains(t, deploy(byCode, withArgs), "not allowed", "arguments count unless ignored")
+	})
+
+	t.Run("prefix matching", func(t *testing.T) {
+		p := newPolicy(config.DeployConfig{AllowDeploy: true, AllowedBytecode: []string{hexutil.Encode(audited)}, IgnoreConstructorArgs: true})
+		assert.NoError(t, deploy(p, audited))
+		assert.NoError(t, deploy(p, withArgs))
+		assert.ErrorContains(t, deploy(p, append(append([]byte(nil), other...), ctorArgs...)), "not allowed")
+		assert.ErrorContains(t, deploy(p, audited[:len(audited)-1]), "not allowed", "truncated bytecode")
+	})
+
+	t.Run("mismatching", func(t *testing.T) {
+		p := newPolicy(config.DeployConfig{AllowDeploy: true, AllowedHashes: []string{crypto.Keccak256Hash(audited).Hex()}})
+		err := deploy(p, other)
+		require.Error(t, err)
+		assert.Equal(t, "deploy: bytecode hash "+crypto.Keccak256Hash(other).Hex()+" is not allowed", err.Error(),
+			"the denial names the hash to allow")
+		assert.ErrorContains(t, deploy(p, "not hex"), "without bytecode")
+	})
+
+	t.Run("switch", func(t *testing.T) {
+		off := newPolicy(config.DeployConfig{AllowedHashes: []string{crypto.Keccak256Hash(audited).Hex()}})
+		assert.ErrorContains(t, deploy(off, audited), "contract creation is not allowed (bytecode hash "+crypto.Keccak256Hash(audited).Hex()+")")
+		anything := newPolicy(config.DeployConfig{AllowDeploy: true})
+		assert.NoError(t, deploy(anything, other))
+	})
+
+	t.Run("other tools", func(t *testing.T) {
+		p := newPolicy(config.DeployConfig{AllowDeploy: true, AllowedBytecode: []string{hexutil.Encode(audited)}})
+		check := func(tool string, args map[string]interface{}) error {
+			return p.Check(ctx, &security.EvaluationContext{Tool: tool, Args: args})
+		}
+		assert.NoError(t, check("send", map[string]interface{}{"to": token, "data": other}), "a call is not a deployment")
+		assert.NoError(t, check("sign", map[string]interface{}{"data": audited}))
+		assert.ErrorContains(t, check("send_raw", map[string]interface{}{"data": other}), "not allowed", "creation without to")
+		assert.ErrorContains(t, check("deploy", map[string]interface{}{"to": token, "bytecode": other}), "not allowed", "deploy ignores to")
+		assert.NoError(t, check("sign_message", map[string]interface{}{"message": other}))
+	})
+
+	t.Run("create2", func(t *testing.T) {
+		p := newPolicy(config.DeployConfig{AllowDeploy: true, AllowedBytecode: []string{hexutil.Encode(audited)}})
+		sess := &chainSession{chain: factoryChain{}}
+		check := func(tool string, args map[string]interface{}) error {
+			return p.Check(ctx, &security.EvaluationContext{Tool: tool, Args: args, Session: sess})
+		}
+		var salt [32]byte
+		salt[31] = 7
+		assert.NoError(t, check("deploy", map[string]interface{}{"bytecode": audited, "salt": salt}))
+		assert.ErrorContains(t, check("deploy", map[string]interface{}{"bytecode": other, "salt": salt}), "not allowed")
+
+		// A call to the factory deploys the data after the salt.
+		viaFactory := func(code []byte) map[string]interface{} {
+			return map[string]interface{}{"to": factoryChain{}.Create2Factory(), "data": append(salt[:], code...)}
+		}
+		assert.NoError(t, check("send", viaFactory(audited)))
+		assert.ErrorContains(t, check("send", viaFactory(other)), "bytecode hash "+crypto.Keccak256Hash(other).Hex())
+	})
+
+	t.Run("artifact", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "Vault.json")
+		artifact := `{"abi":[],"bytecode":{"object":"` + hexutil.Encode(audited) + `"}}`
+		require.NoError(t, os.WriteFile(path, []byte(artifact), 0o600))
+		p := newPolicy(config.DeployConfig{AllowDeploy: true, AllowedBytecode: []string{path}, IgnoreConstructorArgs: true})
+		assert.NoError(t, deploy(p, withArgs))
+		assert.ErrorContains(t, deploy(p, other), "not allowed")
+
+		_, err := policies.NewDeployPolicy(&config.DeployConfig{AllowedBytecode: []string{filepath.Join(t.TempDir(), "missing.json")}})
+		assert.Error(t, err)
+		_, err = policies.NewDeployPolicy(&config.DeployConfig{AllowedHashes: []string{"0x1234"}})
+		assert.Error(t, err)
+	})
+}
+
+// factoryChain is a chain with the canonical CREATE2 factory.
+type factoryChain struct {
+	blockchain.Chain
+}
+
+func (factoryChain) Create2Factory() string {
+	return "0x4e59b44847b379578588920cA78FbF26c0B4956C"
+}
@@ -0,0 +1,194 @@
+// Package policies provides the gas spending policy.
+//
+// File: internal/security/policies/gas.go
+
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// gasTools are the tools whose transactions the agent's wallet pays gas
+// for. A cancel's fee is that of the replacement the engine has the chain
+// build before the policies run.
+var gasTools = map[string]bool{
+	"transfer": true,
+	"send":     true,
+	"deploy":   true,
+	"sign":     true,
+	"send_raw": true,
+	"cancel":   true,
+}
+
+// GasPolicy limits what transactions spend on gas, independently of the
+// value they send: a loop deploying contracts spends real money with a
+// value of zero. It checks the fees the session's chain estimates for the
+// transaction against a maximum gas price, a maximum gas limit per
+// transaction and a daily fee budget per chain and signing address, which
+// NewGasPolicyWithState keeps across restarts.
+//
+// Like LimitPolicy, Check reserves the estimated fee against the daily
+// budget and Settle releases it if the tool fails. The estimate assumes
+// the whole gas limit is used, so the budget errs on the side of caution.
+type GasPolicy struct {
+	mu          sync.Mutex
+	maxGasPrice *big.Int // wei per gas (nil = no limit)
+	maxGas      uint64   // gas limit per transaction (0 = no limit)
+	dailyBudget *big.Int // fees in wei per rolling window (nil = no limit)
+	dailySpent  map[string]*big.Int
+	dailyReset  map[string]time.Time
+	window      time.Duration
+	reserved    map[*security.EvaluationContext]reservation
+	state       *LimitState // nil = fees are kept in memory only
+}
+
+// NewGasPolicy creates a policy from configuration; a nil amount or zero
+// gas is not limited.
+func NewGasPolicy(maxGasPrice *config.Amount, maxGasPerTx uint64, dailyBudget *config.Amount) *GasPolicy {
+	p := &GasPolicy{
+		maxGas:     maxGasPerTx,
+		dailySpent: make(map[string]*big.Int),
+		dailyReset: make(map[string]time.Time),
+		window:     24 * time.Hour,
+		reserved:   make(map[*security.EvaluationContext]reservation),
+	}
+	if maxGasPrice != nil {
+		p.maxGasPrice = new(big.Int).Set(maxGasPrice.Wei)
+	}
+	if dailyBudget != nil {
+		p.dailyBudget = new(big.Int).Set(dailyBudget.Wei)
+	}
+	return p
+}
+
+// NewGasPolicyWithState is NewGasPolicy keeping the daily fees in
+// state.Path, so that a restart does not reset the daily budget. A missing
+// file starts from zero. An unreadable file is an error if
+// state.FailClosed is set.
+func NewGasPolicyWithState(maxGasPrice *config.Amount, maxGasPerTx uint64, dailyBudget *config.Amount, state LimitState) (*GasPolicy, error) {
+	p := NewGasPolicy(maxGasPrice, maxGasPerTx, dailyBudget)
+	p.state = &state
+	if err := loadSpend(p.state, "", "no daily gas budget state yet; daily fees start from zero", p.dailySpent, p.dailyReset); err != nil {
+		if state.FailClosed {
+			return nil, fmt.Errorf("daily gas budget: %w", err)
+		}
+		p.state.log("daily gas budget state unreadable; daily fees start from zero", err)
+	}
+	return p, nil
+}
+
+// saveState writes the fees atomically and durably; p.mu must be held.
+func (p *GasPolicy) saveState() error {
+	if p.state == nil {
+		return nil
+	}
+	return saveSpend(p.state.Path, "", p.dailySpent, p.dailyReset)
+}
+
+// Name returns "gas", the policy's name in decisions and audit entries.
+func (p *GasPolicy) Name() string { return "gas" }
+
+// Check implements security.Policy.
+func (p *GasPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	if !gasTools[evalCtx.Tool] {
+		return nil
+	}
+	cost, err := evalCtx.EstimatedCost(ctx)
+	if errors.Is(err, security.ErrCostEstimationUnsupported) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("estimate transaction fees: %w", err)
+	}
+
+	if p.maxGasPrice != nil && cost.GasPrice != nil && cost.GasPrice.Cmp(p.maxGasPrice) > 0 {
+		return &security.ErrLimitExceeded{
+			Name: "max_gas_price", Unit: "wei", Limit: p.maxGasPrice, Attempted: cost.GasPrice,
+			Reason: fmt.Sprintf("gas price %s wei exceeds max gas price %s wei", cost.GasPrice, p.maxGasPrice),
+		}
+	}
+	if p.maxGas != 0 && cost.Gas > p.maxGas {
+		return &security.ErrLimitExceeded{
+			Name: "max_gas_per_tx", Unit: "gas",
+			Limit: new(big.Int).SetUint64(p.maxGas), Attempted: new(big.Int).SetUint64(cost.Gas),
+			Reason: fmt.Sprintf("gas limit %d (fee %s wei) exceeds per‑tx gas limit %d", cost.Gas, cost.Total, p.maxGas),
+		}
+	}
+
+	if p.dailyBudget != nil {
+		// Fees are tracked per chain and signing address, like
+		// LimitPolicy's daily spend.
+		account := ""
+		if signer := evalCtx.Signer(); signer != "" {
+			account = common.HexToAddress(signer).Hex()
+		}
+		key := spendKey(evalCtx.ChainName, account)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		now := time.Now().UTC()
+		resetTime, exists := p.dailyReset[key]
+		if !exists || now.Sub(resetTime) > p.window {
+			p.dailySpent[key] = new(big.Int)
+			p.dailyReset[key] = now
+		}
+
+		spent := p.dailySpent[key]
+		newSpent := new(big.Int).Add(spent, cost.Total)
+		if newSpent.Cmp(p.dailyBudget) > 0 {
+			return &security.ErrLimitExceeded{
+				Name: "daily_gas_budget", Unit: "wei", Account: account,
+				Limit: p.dailyBudget, Attempted: cost.Total, Spent: new(big.Int).Set(spent),
+				Reason: fmt.Sprintf("daily gas budget exceeded for %s: budget %s, already spent %s, attempted +%s",
+					accountLabel(account), p.dailyBudget, spent, cost.Total),
+			}
+		}
+		p.dailySpent[key] = newSpent
+		if err := p.saveState(); err != nil {
+			if p.state.FailClosed {
+				p.dailySpent[key] = spent
+				return fmt.Errorf("daily gas budget: %w", err)
+			}
+			p.state.log("daily gas budget state not saved; a restart would forget this fee", err)
+		}
+		p.reserved[evalCtx] = reservation{key: key, amount: new(big.Int).Set(cost.Total), window: p.dailyReset[key]}
+	}
+
+	return nil
+}
+
+// Settle implements security.Settler. It keeps the fee reserved for
+// evalCtx if err is nil and gives it back to the daily budget otherwise.
+func (p *GasPolicy) Settle(ctx context.Context, evalCtx *security.EvaluationContext, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r, ok := p.reserved[evalCtx]
+	if !ok {
+		return
+	}
+	delete(p.reserved, evalCtx)
+	if err == nil || !p.dailyReset[r.key].Equal(r.window) {
+		return
+	}
+	spent := new(big.Int).Sub(p.dailySpent[r.key], r.amount)
+	if spent.Sign() < 0 {
+		spent.SetInt64(0)
+	}
+	p.dailySpent[r.key] = spent
+	if err := p.saveState(); err != nil {
+		p.state.log("daily gas budget state not saved; a restart would count a failed fee", err)
+	}
+}
+
+// EOF: internal/security/policies/gas.go
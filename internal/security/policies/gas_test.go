@@ -0,0 +1,136 @@
+package policies_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+// gasChain prices every transaction at its gas limit (21000 if not given)
+// and gas price (1 gwei if not given), and counts the estimates.
+type gasChain struct {
+	blockchain.Chain
+	estimates int
+}
+
+func (c *gasChain) EstimateTotalCost(ctx context.Context, tx *blockchain.Transaction) (*blockchain.TxCost, error) {
+	c.estimates++
+	gas, price := tx.Gas, tx.GasPrice
+	if gas == 0 {
+		gas = 21000
+	}
+	if price == nil {
+		price = big.NewInt(1e9)
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), price)
+	return &blockchain.TxCost{Gas: gas, GasPrice: price, ExecutionFee: fee, DataFee: new(big.Int), Total: fee}, nil
+}
+
+func gasEvalCtx(chain blockchain.Chain, tool string, args map[string]interface{}) *security.EvaluationContext {
+	return &security.EvaluationContext{
+		Tool:    tool,
+		Args:    args,
+		Session: &chainSession{mockSession{id: "s1"}, chain},
+		From:    "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7",
+	}
+}
+
+func TestGasPolicy_PerTx(t *testing.T) {
+	policy := policies.NewGasPolicy(config.MustParseAmount("100 gwei"), 1000000, nil)
+	ctx := context.Background()
+	chain := &gasChain{}
+
+	assert.NoError(t, policy.Check(ctx, gasEvalCtx(chain, "deploy", map[string]interface{}{"bytecode": "6000"})))
+	err := policy.Check(ctx, gasEvalCtx(chain, "send", map[string]interface{}{"gasPrice": big.NewInt(150e9)}))
+	assert.ErrorContains(t, err, "gas price 150000000000 wei exceeds max gas price 100000000000 wei")
+	err = policy.Check(ctx, gasEvalCtx(chain, "deploy", map[string]interface{}{"gas": uint64(2000000)}))
+	assert.ErrorContains(t, err, "gas limit 2000000 (fee 2000000000000000 wei) exceeds per‑tx gas limit 1000000")
+
+	// Tools the wallet pays no gas for are not estimated.
+	assert.NoError(t, policy.Check(ctx, gasEvalCtx(chain, "balance", nil)))
+	assert.NoError(t, policy.Check(ctx, gasEvalCtx(chain, "safe_propose", nil)))
+	assert.Equal(t, 3, chain.estimates)
+}
+
+func TestGasPolicy_DailyBudget(t *testing.T) {
+	policy := policies.NewGasPolicy(nil, 0, config.MustParseAmount("0.001 eth"))
+	ctx := context.Background()
+	chain := &gasChain{}
+	deploy := func() *security.EvaluationContext {
+		return gasEvalCtx(chain, "deploy", map[string]interface{}{"gas": uint64(400000)}) // 0.0004 eth
+	}
+
+	// A zero‑value loop is stopped by its fees.
+	require.NoError(t, policy.Check(ctx, deploy()))
+	failed := deploy()
+	require.NoError(t, policy.Check(ctx, failed))
+	err := policy.Check(ctx, deploy())
+	assert.ErrorContains(t, err, "daily gas budget exceeded for 0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7: "+
+		"budget 1000000000000000, already spent 800000000000000, attempted +400000000000000")
+
+	// A failed transaction gives its fee back.
+	policy.Settle(ctx, failed, errors.New("rpc down"))
+	assert.NoError(t, policy.Check(ctx, deploy()))
+
+	// Each wallet has its own budget.
+	other := deploy()
+	other.From = "0x5aFE3855358E112B5647B952709E6165e1c1eEEe"
+	assert.NoError(t, policy.Check(ctx, other))
+
+	// And each chain: fees in different native currencies are not added up.
+	polygon := deploy()
+	polygon.ChainName = "polygon"
+	assert.NoError(t, policy.Check(ctx, polygon))
+}
+
+func TestGasPolicy_StateSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lola.gas.json")
+	budget := config.MustParseAmount("0.001 eth")
+	ctx := context.Background()
+	chain := &gasChain{}
+	deploy := func() *security.EvaluationContext {
+		return gasEvalCtx(chain, "deploy", map[string]interface{}{"gas": uint64(400000)}) // 0.0004 eth
+	}
+
+	policy, err := policies.NewGasPolicyWithState(nil, 0, budget, policies.LimitState{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, policy.Check(ctx, deploy()))
+	require.NoError(t, policy.Check(ctx, deploy()))
+
+	// A new policy, as after a restart, continues from the saved fees.
+	restarted, err := policies.NewGasPolicyWithState(nil, 0, budget, policies.LimitState{Path: path})
+	require.NoError(t, err)
+	assert.ErrorContains(t, restarted.Check(ctx, deploy()), "already spent 800000000000000")
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err = policies.NewGasPolicyWithState(nil, 0, budget, policies.LimitState{Path: path, FailClosed: true})
+	assert.ErrorContains(t, err, "daily gas budget")
+	_, err = policies.NewGasPolicyWithState(nil, 0, budget, policies.LimitState{Path: path})
+	assert.NoError(t, err, "fails open by default")
+}
+
+func TestGasPolicy_SharesEstimate(t *testing.T) {
+	chain := &gasChain{}
+	enforcer := security.NewEnforcer()
+	enforcer.AddPolicy(policies.NewLimitPolicy(config.MustParseAmount("1 eth"), nil))
+	enforcer.AddPolicy(policies.NewGasPolicy(config.MustParseAmount("10 gwei"), 0, nil))
+
+	evalCtx := gasEvalCtx(chain, "transfer", map[string]interface{}{
+		"to":     "0x5aFE3855358E112B5647B952709E6165e1c1eEEe",
+		"amount": big.NewInt(1),
+	})
+	require.NoError(t, enforcer.Evaluate(context.Background(), evalCtx))
+	assert.Equal(t, 1, chain.estimates, "one estimate for both policies")
+	assert.Equal(t, big.NewInt(21000e9), evalCtx.Cost.Total)
+}
@@ -1,4 +1,5 @@
-// Package policies provides human‑in‑the‑loop policy with console approval.
+// Package policies provides human‑in‑the‑loop policy with console,
+// Telegram and HTTP API approval.
 //
 // File: internal/security/policies/hitl.go
 
@@ -7,28 +8,61 @@ package policies
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
+	"os/user"
 	"strings"
 	"time"
 
+	"golang.org/x/term"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
 	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
 	"github.com/0xSemantic/lola-os/internal/security"
 )
 
-// HITLPolicy pauses execution and requests human approval for transactions above threshold.
+// ErrApprovalTimeout is returned when no human decided within the
+// approval timeout.
+var ErrApprovalTimeout = errors.New("human approval timed out")
+
+// HITLPolicy pauses execution and requests human approval for transactions
+// above threshold. In console mode it also asks for the passphrase when a
+// transaction meets a locked wallet, and unlocks it. A threshold given in
+// usd is compared with the amount's value at the current price. Rules can
+// approve operations over the threshold without asking, or require
+// approval for operations under it. Tiers require several approvers for
+// operations over higher thresholds.
 type HITLPolicy struct {
+	threshold     *big.Int
+	thresholdUSD  bool // threshold is in 10⁻¹⁸ USD, not wei
+	pricer        *USDPricer
+	timeout       time.Duration
+	mode          string         // "console", "telegram" or "api"
+	telegram      *TelegramBot   // for mode "telegram"
+	queue         *ApprovalQueue // for mode "api"
+	audit         *observe.AuditLogger
+	logger        observe.Logger
+	rules         []hitlRule // approve without asking
+	alwaysRequire []hitlRule // ask whatever the threshold
+	tiers         []hitlTier // need several approvers
+}
+
+// hitlTier is a config.HITLTier ready to apply.
+type hitlTier struct {
 	threshold *big.Int
-	timeout   time.Duration
-	mode      string // "console"
+	usd       bool // threshold is in 10⁻¹⁸ USD, not wei
+	quorum    Quorum
 }
 
 // NewHITLPolicy creates a human‑in‑the‑loop policy from config.
 func NewHITLPolicy(threshold *config.Amount, timeout time.Duration, mode string) *HITLPolicy {
 	var thresh *big.Int
+	var usd bool
 	if threshold != nil {
-		thresh = new(big.Int).Set(threshold.Wei)
+		thresh, usd = limitValue(threshold)
 	}
 	if mode == "" {
 		mode = "console"
@@ -37,19 +71,118 @@ func NewHITLPolicy(threshold *config.Amount, timeout time.Duration, mode string)
 		timeout = 5 * time.Minute
 	}
 	return &HITLPolicy{
-		threshold: thresh,
-		timeout:   timeout,
-		mode:      mode,
+		threshold:    thresh,
+		thresholdUSD: usd,
+		timeout:      timeout,
+		mode:         mode,
+	}
+}
+
+// SetPricer sets the converter for a threshold in usd. Without one, every
+// operation with an amount is denied.
+func (p *HITLPolicy) SetPricer(pricer *USDPricer) {
+	p.pricer = pricer
+}
+
+// SetAudit sets the log that records every approval request and, in
+// console mode, its outcome. The Telegram bot and the approval queue
+// record their outcomes themselves.
+func (p *HITLPolicy) SetAudit(audit *observe.AuditLogger) {
+	p.audit = audit
+}
+
+// SetTelegram sets the bot that asks for approvals in mode "telegram".
+// Without one, operations needing approval are denied.
+func (p *HITLPolicy) SetTelegram(bot *TelegramBot) {
+	p.telegram = bot
+}
+
+// SetQueue sets the queue that holds approvals in mode "api". Without one,
+// operations needing approval are denied.
+func (p *HITLPolicy) SetQueue(queue *ApprovalQueue) {
+	p.queue = queue
+}
+
+// SetTiers sets the tiers that require several approvers for operations
+// over their thresholds, whatever the policy's own threshold. Console mode
+// cannot tell approvers apart, so it only takes tiers of one approval.
+func (p *HITLPolicy) SetTiers(tiers []config.HITLTier) error {
+	out := make([]hitlTier, 0, len(tiers))
+	for i, t := range tiers {
+		if t.Threshold == nil {
+			return fmt.Errorf("human_in_the_loop: tiers[%d]: no threshold", i)
+		}
+		tier := hitlTier{quorum: Quorum{Required: t.ApprovalsRequired, Approvers: t.Approvers, Count: t.OnDeny == "count"}}
+		if p.mode == "console" && newQuorumVotes(tier.quorum).multi() {
+			return fmt.Errorf("human_in_the_loop: tiers[%d]: mode console cannot collect several approvals", i)
+		}
+		tier.threshold, tier.usd = limitValue(t.Threshold)
+		out = append(out, tier)
 	}
+	p.tiers = out
+	return nil
 }
 
+// tier returns the tier requiring the most approvals whose threshold the
+// operation's amount exceeds, or nil.
+func (p *HITLPolicy) tier(ctx context.Context, evalCtx *security.EvaluationContext) (*hitlTier, error) {
+	amount, ok := evalCtx.Args["amount"].(*big.Int)
+	if !ok || len(p.tiers) == 0 {
+		return nil, nil
+	}
+	var best *hitlTier
+	var usd *big.Int
+	for i := range p.tiers {
+		t := &p.tiers[i]
+		value := amount
+		if t.usd {
+			if usd == nil {
+				conv, err := p.pricer.ToUSD(ctx, evalCtx, amount, "human_in_the_loop")
+				if err != nil {
+					return nil, err
+				}
+				usd = conv.USD
+			}
+			value = usd
+		}
+		if value.Cmp(t.threshold) > 0 && (best == nil || t.quorum.Required > best.quorum.Required) {
+			best = t
+		}
+	}
+	return best, nil
+}
+
+// Name returns "human_in_the_loop", the policy's name in decisions and audit entries.
+func (p *HITLPolicy) Name() string { return "human_in_the_loop" }
+
+// Prompts implements security.Prompter: the policy asks a human.
+func (p *HITLPolicy) Prompts() bool { return true }
+
 // Check implements security.Policy.
 func (p *HITLPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	// Some operations always need a human.
+	if rule := p.match(ctx, evalCtx, p.alwaysRequire); rule != nil {
+		p.log("hitl: approval required by rule", evalCtx, rule.name)
+		tier, err := p.tier(ctx, evalCtx)
+		if err != nil {
+			return err
+		}
+		return p.approve(ctx, evalCtx, tier, "Reason: rule "+rule.name+" requires approval")
+	}
+
 	// Only apply to tools that send value.
-	if evalCtx.Tool != "transfer" && evalCtx.Tool != "send" && evalCtx.Tool != "swap" {
+	if evalCtx.Tool != "transfer" && evalCtx.Tool != "send" && evalCtx.Tool != "swap" && evalCtx.Tool != "sign" && evalCtx.Tool != "send_raw" &&
+		evalCtx.Tool != "safe_propose" && evalCtx.Tool != "aa_send" {
 		return nil
 	}
 
+	// A locked wallet cannot sign; let the operator unlock it.
+	if lockable, ok := evalCtx.Wallet().(blockchain.LockableWallet); ok && lockable.Locked() && p.mode == "console" {
+		if err := p.consoleUnlock(lockable); err != nil {
+			return err
+		}
+	}
+
 	// Extract amount.
 	amountRaw, ok := evalCtx.Args["amount"]
 	if !ok {
@@ -60,26 +193,159 @@ func (p *HITLPolicy) Check(ctx context.Context, evalCtx *security.EvaluationCont
 		return nil
 	}
 
-	// Check threshold.
-	if p.threshold == nil || amount.Cmp(p.threshold) <= 0 {
+	// Check the threshold, and those of the tiers.
+	tier, err := p.tier(ctx, evalCtx)
+	if err != nil {
+		return err
+	}
+	var details []string
+	if p.threshold != nil && p.thresholdUSD {
+		conv, err := p.pricer.ToUSD(ctx, evalCtx, amount, "human_in_the_loop")
+		if err != nil {
+			return err
+		}
+		if conv.USD.Cmp(p.threshold) > 0 {
+			details = []string{"Threshold: " + formatUSD(p.threshold),
+				fmt.Sprintf("Amount: %s wei (%s at %s usd/%s)", amount.String(), formatUSD(conv.USD), conv.Price.FloatString(2), conv.Symbol)}
+		}
+	} else if p.threshold != nil && amount.Cmp(p.threshold) > 0 {
+		details = []string{fmt.Sprintf("Threshold: %s wei", p.threshold.String()), fmt.Sprintf("Amount: %s wei", amount.String())}
+	}
+	if details == nil && tier == nil || p.autoApprove(ctx, evalCtx) {
 		return nil
 	}
+	if details == nil {
+		details = []string{fmt.Sprintf("Amount: %s wei", amount.String())}
+	}
 
 	// Request approval.
+	return p.approve(ctx, evalCtx, tier, details...)
+}
+
+// Approve asks a human to approve the operation for reason, whatever its
+// value, or the approvers of the tier its amount falls in. Other policies
+// use it for operations that need a human decision.
+func (p *HITLPolicy) Approve(ctx context.Context, evalCtx *security.EvaluationContext, reason string) error {
+	tier, err := p.tier(ctx, evalCtx)
+	if err != nil {
+		return err
+	}
+	return p.approve(ctx, evalCtx, tier, "Reason: "+reason)
+}
+
+// approve asks a human, or the approvers of tier if not nil, to approve
+// the operation, showing details, and returns a
+// *security.ErrNeedsApproval unless they do.
+func (p *HITLPolicy) approve(ctx context.Context, evalCtx *security.EvaluationContext, tier *hitlTier, details ...string) error {
+	var quorum Quorum
+	if tier != nil {
+		quorum = tier.quorum
+		threshold := tier.threshold.String() + " wei"
+		if tier.usd {
+			threshold = formatUSD(tier.threshold)
+		}
+		details = append(details, fmt.Sprintf("Approvals required: %d of %d (over %s)", max(quorum.Required, 1), len(quorum.Approvers), threshold))
+	}
+	if err := p.ask(ctx, evalCtx, details, quorum); err != nil {
+		return &security.ErrNeedsApproval{Details: details, Err: err}
+	}
+	return nil
+}
+
+func (p *HITLPolicy) ask(ctx context.Context, evalCtx *security.EvaluationContext, details []string, quorum Quorum) error {
+	started := time.Now()
+	requested := map[string]interface{}{
+		"details": details,
+		"args":    security.SummarizeArgs(evalCtx.Args),
+	}
+	multi := newQuorumVotes(quorum).multi()
+	if multi {
+		requested["approvals_required"] = max(quorum.Required, 1)
+		requested["approvers"] = quorum.Approvers
+	}
+	recordDecision(p.audit, evalCtx, p.mode, "requested", started, requested)
 	switch p.mode {
 	case "console":
-		return p.consoleApprove(evalCtx)
+		if multi {
+			return fmt.Errorf("HITL mode console: cannot collect several approvals")
+		}
+		err := p.consoleApprove(evalCtx, details)
+		extra := map[string]interface{}{"approved_by": consoleUser()}
+		decision := "approved"
+		if err != nil {
+			decision = "denied"
+			if errors.Is(err, ErrApprovalTimeout) {
+				decision = "timeout"
+				delete(extra, "approved_by")
+			}
+			extra["reason"] = err.Error()
+		}
+		recordDecision(p.audit, evalCtx, p.mode, decision, started, extra)
+		return err
+	case "telegram":
+		if p.telegram == nil {
+			return fmt.Errorf("HITL mode telegram: no bot configured")
+		}
+		return p.telegram.ApproveQuorum(ctx, evalCtx, p.timeout, details, quorum)
+	case "api":
+		if p.queue == nil {
+			return fmt.Errorf("HITL mode api: no approval queue configured")
+		}
+		return p.queue.ApproveQuorum(ctx, evalCtx, p.timeout, details, quorum)
 	default:
 		return fmt.Errorf("unsupported HITL mode: %s", p.mode)
 	}
 }
 
-func (p *HITLPolicy) consoleApprove(evalCtx *security.EvaluationContext) error {
+// consoleUnlock asks for the passphrase of wallet on the console, without
+// echoing it if stdin is a terminal, and unlocks the wallet with it.
+func (p *HITLPolicy) consoleUnlock(wallet blockchain.LockableWallet) error {
+	fmt.Printf("\n=== WALLET LOCKED ===\n")
+	fmt.Printf("Wallet: %s\n", wallet.Address())
+	fmt.Printf("Passphrase: ")
+
+	ch := make(chan string, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+			passphrase, err := term.ReadPassword(fd)
+			fmt.Println()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			ch <- string(passphrase)
+			return
+		}
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			errCh <- err
+			return
+		}
+		ch <- strings.TrimRight(line, "\r\n")
+	}()
+
+	select {
+	case <-time.After(p.timeout):
+		return fmt.Errorf("wallet unlock timed out after %v", p.timeout)
+	case err := <-errCh:
+		return fmt.Errorf("error reading passphrase: %w", err)
+	case passphrase := <-ch:
+		if err := wallet.Unlock(passphrase); err != nil {
+			return fmt.Errorf("unlock wallet: %w", err)
+		}
+	}
+	fmt.Println("Wallet unlocked.")
+	return nil
+}
+
+func (p *HITLPolicy) consoleApprove(evalCtx *security.EvaluationContext, details []string) error {
 	fmt.Printf("\n=== HUMAN APPROVAL REQUIRED ===\n")
 	fmt.Printf("Tool: %s\n", evalCtx.Tool)
 	fmt.Printf("Arguments: %v\n", evalCtx.Args)
-	fmt.Printf("Threshold: %s wei\n", p.threshold.String())
-	fmt.Printf("Amount: %s wei\n", evalCtx.Args["amount"].(*big.Int).String())
+	for _, detail := range details {
+		fmt.Println(detail)
+	}
 	fmt.Printf("Approve? (y/N): ")
 
 	// Use buffered reader with timeout.
@@ -98,7 +364,7 @@ func (p *HITLPolicy) consoleApprove(evalCtx *security.EvaluationContext) error {
 
 	select {
 	case <-time.After(p.timeout):
-		return fmt.Errorf("human approval timed out after %v", p.timeout)
+		return fmt.Errorf("%w after %v", ErrApprovalTimeout, p.timeout)
 	case err := <-errCh:
 		return fmt.Errorf("error reading input: %w", err)
 	case response := <-ch:
@@ -111,4 +377,35 @@ func (p *HITLPolicy) consoleApprove(evalCtx *security.EvaluationContext) error {
 	return nil
 }
 
+// consoleUser names the operator answering console prompts.
+func consoleUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return "console:" + u.Username
+	}
+	return "console"
+}
+
+// recordDecision writes an approval request or its outcome to audit, with
+// the time since the request started. Audit failures do not change the
+// decision.
+func recordDecision(audit *observe.AuditLogger, evalCtx *security.EvaluationContext, mode, decision string, started time.Time, extra map[string]interface{}) {
+	if audit == nil {
+		return
+	}
+	entry := evalCtx.AuditEntry(observe.AuditKindApproval)
+	entry.Extra = map[string]interface{}{
+		"action":   "hitl_decision",
+		"mode":     mode,
+		"tool":     evalCtx.Tool,
+		"decision": decision,
+	}
+	if decision != "requested" {
+		entry.Extra["elapsed_ms"] = time.Since(started).Milliseconds()
+	}
+	for k, v := range extra {
+		entry.Extra[k] = v
+	}
+	_ = audit.Log(entry)
+}
+
 // EOF: internal/security/policies/hitl.go
\ No newline at end of file
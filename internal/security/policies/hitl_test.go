@@ -0,0 +1,219 @@
+package policies_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+// ruleLogger records the rules named in info messages.
+type ruleLogger struct {
+	observe.NoopLogger
+	mu    sync.Mutex
+	rules []string
+}
+
+func (l *ruleLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rule, _ := fields[0]["rule"].(string)
+	l.rules = append(l.rules, msg+": "+rule)
+}
+
+// withStdin runs fn with input on os.Stdin.
+func withStdin(t *testing.T, input string, fn func()) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	_, err = w.WriteString(input)
+	require.NoError(t, err)
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() {
+		os.Stdin = stdin
+		w.Close()
+		r.Close()
+	}()
+	fn()
+}
+
+func TestHITLPolicy_AuditConsole(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+	hitl := policies.NewHITLPolicy(config.MustParseAmount("1 eth"), 200*time.Millisecond, "console")
+	hitl.SetAudit(audit)
+	ctx := context.Background()
+
+	withStdin(t, "y\n", func() { require.NoError(t, hitl.Check(ctx, usdEvalCtx(eth(10000)))) })
+	withStdin(t, "n\n", func() {
+		err := hitl.Check(ctx, usdEvalCtx(eth(2000)))
+		assert.ErrorContains(t, err, "human rejected")
+		var needs *security.ErrNeedsApproval
+		require.ErrorAs(t, err, &needs)
+		assert.Equal(t, []string{"Threshold: 1000000000000000000 wei", "Amount: 2000000000000000000 wei"}, needs.Details)
+	})
+	withStdin(t, "", func() { assert.ErrorIs(t, hitl.Check(ctx, usdEvalCtx(eth(2000))), policies.ErrApprovalTimeout) })
+
+	entries := readAudit(t, path)
+	require.Len(t, entries, 6)
+	var decisions []string
+	for _, e := range entries {
+		assert.Equal(t, observe.AuditKindApproval, e.Kind)
+		assert.Equal(t, "console", e.Extra["mode"])
+		decisions = append(decisions, e.Extra["decision"].(string))
+	}
+	assert.Equal(t, []string{"requested", "approved", "requested", "denied", "requested", "timeout"}, decisions)
+
+	requested, approved := entries[0], entries[1]
+	assert.Equal(t, "s1", requested.SessionID)
+	assert.Equal(t, "10000000000000000000", requested.Value)
+	assert.Contains(t, requested.Extra["details"], "Amount: 10000000000000000000 wei")
+	assert.NotContains(t, requested.Extra, "elapsed_ms")
+	assert.True(t, strings.HasPrefix(approved.Extra["approved_by"].(string), "console"))
+	assert.Contains(t, approved.Extra, "elapsed_ms")
+	assert.NotContains(t, entries[5].Extra, "approved_by", "nobody decided")
+	assert.GreaterOrEqual(t, entries[5].Extra["elapsed_ms"], float64(200))
+}
+
+func TestHITLPolicy_AuditQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+	queue := policies.NewApprovalQueue(audit)
+	hitl := queueHITL(queue, 5*time.Second)
+	hitl.SetAudit(audit)
+
+	done := make(chan error, 1)
+	go func() { done <- hitl.Check(context.Background(), usdEvalCtx(eth(2000))) }()
+	id := waitPending(t, queue, 1)[0].ID
+	require.NoError(t, queue.Resolve(id, false, "bob"))
+	assert.ErrorContains(t, <-done, "human rejected transaction (by bob)")
+
+	entries := readAudit(t, path)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "requested", entries[0].Extra["decision"])
+	assert.Equal(t, "denied", entries[1].Extra["decision"])
+	assert.Equal(t, "bob", entries[1].Extra["approved_by"])
+	assert.Equal(t, id, entries[1].Extra["approval_id"])
+	assert.Equal(t, observe.AuditKindApproval, entries[1].Kind)
+}
+
+func TestHITLPolicy_Rules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+	logger := &ruleLogger{}
+	queue := policies.NewApprovalQueue(nil)
+	hitl := policies.NewHITLPolicy(config.MustParseAmount("0.001 eth"), 50*time.Millisecond, "api")
+	hitl.SetQueue(queue)
+	hitl.SetAudit(audit)
+	hitl.SetLogger(logger)
+	require.NoError(t, hitl.SetRules(
+		[]config.HITLRule{
+			{Name: "treasury", To: []string{strings.ToLower(owner)}},
+			{Below: config.MustParseAmount("0.01 eth"), Tools: []string{"transfer"}, Chains: []string{"Ethereum"}},
+		},
+		[]config.HITLRule{
+			{Name: "deploys", Tools: []string{"deploy"}},
+			{Name: "mainnet sends", Tools: []string{"send"}, Chains: []string{"ethereum"}},
+		},
+	))
+	ctx := context.Background()
+	op := func(tool string, milli int64, to, chain string) *security.EvaluationContext {
+		evalCtx := usdEvalCtx(eth(milli))
+		evalCtx.Tool, evalCtx.ChainName = tool, chain
+		if to != "" {
+			evalCtx.Args["to"] = to
+		}
+		return evalCtx
+	}
+
+	// Auto-approved: a trusted destination, a small transfer on mainnet.
+	require.NoError(t, hitl.Check(ctx, op("transfer", 5000, owner, "ethereum")))
+	require.NoError(t, hitl.Check(ctx, op("transfer", 5, agent, "ethereum")))
+	// Asked: too much, another chain, another tool.
+	assert.ErrorIs(t, hitl.Check(ctx, op("transfer", 10, agent, "ethereum")), policies.ErrApprovalTimeout)
+	assert.ErrorIs(t, hitl.Check(ctx, op("transfer", 5, agent, "gnosis")), policies.ErrApprovalTimeout)
+	assert.ErrorIs(t, hitl.Check(ctx, op("swap", 5, agent, "ethereum")), policies.ErrApprovalTimeout)
+	// A destination that is not known meets no auto-approval condition.
+	assert.ErrorIs(t, hitl.Check(ctx, op("swap", 5000, "", "ethereum")), policies.ErrApprovalTimeout)
+
+	// Always asked, under the threshold or for tools it does not cover,
+	// and on an unknown chain too.
+	assert.ErrorIs(t, hitl.Check(ctx, op("deploy", 0, "", "gnosis")), policies.ErrApprovalTimeout)
+	assert.ErrorIs(t, hitl.Check(ctx, op("send", 0, owner, "")), policies.ErrApprovalTimeout)
+	require.NoError(t, hitl.Check(ctx, op("send", 0, owner, "gnosis")))
+
+	assert.Equal(t, []string{
+		"hitl: auto-approved by rule: treasury",
+		"hitl: auto-approved by rule: rules[1]",
+		"hitl: no auto-approval rule matched: ",
+		"hitl: no auto-approval rule matched: ",
+		"hitl: no auto-approval rule matched: ",
+		"hitl: no auto-approval rule matched: ",
+		"hitl: approval required by rule: deploys",
+		"hitl: approval required by rule: mainnet sends",
+	}, logger.rules)
+
+	var auto []observe.AuditEntry
+	for _, e := range readAudit(t, path) {
+		if e.Kind == observe.AuditKindAutoApproval {
+			auto = append(auto, e)
+		}
+	}
+	require.Len(t, auto, 2)
+	assert.Equal(t, "treasury", auto[0].Extra["rule"])
+	assert.Equal(t, owner, auto[0].To)
+	assert.Equal(t, "5000000000000000000", auto[0].Value)
+	assert.Equal(t, "rules[1]", auto[1].Extra["rule"])
+
+	// Rules that could never match are refused.
+	err = hitl.SetRules([]config.HITLRule{{To: []string{"0x1234"}}}, nil)
+	assert.ErrorContains(t, err, `rules[0]: invalid address "0x1234"`)
+	err = hitl.SetRules(nil, []config.HITLRule{{Name: "empty"}})
+	assert.ErrorContains(t, err, "empty: no conditions")
+}
+
+func TestHITLPolicy_NotAskedAfterDenial(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+	hitl := policies.NewHITLPolicy(config.MustParseAmount("1 eth"), time.Second, "console")
+	hitl.SetAudit(audit)
+
+	// HITL is added first but ordered after the whitelist, which denies.
+	whitelist, err := policies.NewWhitelistPolicy(nil, []string{agent})
+	require.NoError(t, err)
+	e := security.NewEnforcer()
+	e.AddPolicyWithOptions(hitl, security.PolicyOptions{Priority: 1})
+	e.AddPolicyWithOptions(whitelist, security.PolicyOptions{})
+	evalCtx := usdEvalCtx(eth(2000))
+	evalCtx.Args["to"] = agent
+
+	withStdin(t, "y\n", func() {
+		result, err := e.EvaluateAll(context.Background(), evalCtx)
+		assert.ErrorContains(t, err, "blocked")
+		require.Len(t, result.Decisions, 1)
+		assert.Equal(t, "whitelist", result.Decisions[0].PolicyName)
+		unread, _ := io.ReadAll(io.LimitReader(os.Stdin, 2))
+		assert.Equal(t, "y\n", string(unread), "the console prompt was not reached")
+	})
+	assert.Empty(t, readAudit(t, path), "no approval was requested")
+}
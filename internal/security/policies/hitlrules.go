@@ -0,0 +1,192 @@
+// Package policies provides the rules that let human‑in‑the‑loop approve
+// operations automatically, or always ask.
+//
+// File: internal/security/policies/hitlrules.go
+
+package policies
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// hitlRule is a config.HITLRule ready to match operations. An operation
+// matches when it meets every condition the rule sets.
+type hitlRule struct {
+	name     string
+	to       map[common.Address]bool // nil = any destination
+	below    *big.Int                // nil = any value
+	belowUSD bool                    // below is in 10⁻¹⁸ USD, not wei
+	tools    map[string]bool         // nil = any tool
+	chains   []string                // nil = any chain
+	unknown  bool                    // whether an unknown fact meets a condition on it
+}
+
+// newHITLRules converts rules from config; rules without a name are named
+// after their position in list, e.g. "rules[0]". unknown is whether an
+// operation's unknown chain, destination or value meets a condition on
+// it: true for rules that make a human look, false for those that don't.
+func newHITLRules(list string, rules []config.HITLRule, unknown bool) ([]hitlRule, error) {
+	out := make([]hitlRule, 0, len(rules))
+	for i, r := range rules {
+		rule := hitlRule{name: r.Name, chains: r.Chains, unknown: unknown}
+		if rule.name == "" {
+			rule.name = fmt.Sprintf("%s[%d]", list, i)
+		}
+		if len(r.To) == 0 && r.Below == nil && len(r.Tools) == 0 && len(r.Chains) == 0 {
+			return nil, fmt.Errorf("%s: no conditions", rule.name)
+		}
+		if len(r.To) > 0 {
+			rule.to = make(map[common.Address]bool, len(r.To))
+			for _, addr := range r.To {
+				if !common.IsHexAddress(addr) {
+					return nil, fmt.Errorf("%s: invalid address %q", rule.name, addr)
+				}
+				rule.to[common.HexToAddress(addr)] = true
+			}
+		}
+		if r.Below != nil {
+			rule.below, rule.belowUSD = limitValue(r.Below)
+		}
+		if len(r.Tools) > 0 {
+			rule.tools = make(map[string]bool, len(r.Tools))
+			for _, tool := range r.Tools {
+				rule.tools[tool] = true
+			}
+		}
+		out = append(out, rule)
+	}
+	return out, nil
+}
+
+// matches reports whether evalCtx meets every condition of the rule. An
+// operation whose chain, destination or value is not known, or cannot be
+// priced, meets a condition on it if r.unknown.
+func (r *hitlRule) matches(ctx context.Context, evalCtx *security.EvaluationContext, pricer *USDPricer) bool {
+	if r.tools != nil && !r.tools[evalCtx.Tool] {
+		return false
+	}
+	if r.chains != nil {
+		if evalCtx.ChainName == "" {
+			if !r.unknown {
+				return false
+			}
+		} else if !containsFold(r.chains, evalCtx.ChainName) {
+			return false
+		}
+	}
+	if r.to != nil {
+		to, _ := evalCtx.Args["to"].(string)
+		if !common.IsHexAddress(to) {
+			if !r.unknown {
+				return false
+			}
+		} else if !r.to[common.HexToAddress(to)] {
+			return false
+		}
+	}
+	if r.below != nil {
+		amount, ok := evalCtx.Args["amount"].(*big.Int)
+		if !ok {
+			return r.unknown
+		}
+		value := amount
+		if r.belowUSD {
+			conv, err := pricer.ToUSD(ctx, evalCtx, amount, "human_in_the_loop")
+			if err != nil {
+				return r.unknown
+			}
+			value = conv.USD
+		}
+		if value.Cmp(r.below) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRules sets the rules that approve operations over the threshold
+// without asking, and those that always ask, whatever the threshold and
+// the tool. The first matching rule of each list applies.
+func (p *HITLPolicy) SetRules(rules, alwaysRequire []config.HITLRule) error {
+	auto, err := newHITLRules("rules", rules, false)
+	if err != nil {
+		return fmt.Errorf("human_in_the_loop: %w", err)
+	}
+	always, err := newHITLRules("always_require", alwaysRequire, true)
+	if err != nil {
+		return fmt.Errorf("human_in_the_loop: %w", err)
+	}
+	p.rules, p.alwaysRequire = auto, always
+	return nil
+}
+
+// SetLogger sets the logger that records which rule matched (nil = not
+// logged).
+func (p *HITLPolicy) SetLogger(logger observe.Logger) {
+	p.logger = logger
+}
+
+// match returns the first rule of rules that evalCtx matches, or nil.
+func (p *HITLPolicy) match(ctx context.Context, evalCtx *security.EvaluationContext, rules []hitlRule) *hitlRule {
+	for i := range rules {
+		if rules[i].matches(ctx, evalCtx, p.pricer) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// autoApprove reports whether a rule approves evalCtx, and records the
+// approval.
+func (p *HITLPolicy) autoApprove(ctx context.Context, evalCtx *security.EvaluationContext) bool {
+	rule := p.match(ctx, evalCtx, p.rules)
+	if rule == nil {
+		if len(p.rules) > 0 {
+			p.log("hitl: no auto-approval rule matched", evalCtx, "")
+		}
+		return false
+	}
+	p.log("hitl: auto-approved by rule", evalCtx, rule.name)
+	if p.audit != nil {
+		entry := evalCtx.AuditEntry(observe.AuditKindAutoApproval)
+		entry.Extra = map[string]interface{}{
+			"action":   "hitl_decision",
+			"tool":     evalCtx.Tool,
+			"decision": "auto_approved",
+			"rule":     rule.name,
+		}
+		_ = p.audit.Log(entry)
+	}
+	return true
+}
+
+func (p *HITLPolicy) log(msg string, evalCtx *security.EvaluationContext, rule string) {
+	if p.logger == nil {
+		return
+	}
+	fields := map[string]interface{}{"tool": evalCtx.Tool, "chain": evalCtx.ChainName}
+	if rule != "" {
+		fields["rule"] = rule
+	}
+	p.logger.Info(msg, fields)
+}
+
+// EOF: internal/security/policies/hitlrules.go
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/0xSemantic/lola-os/internal/blockchain"
 	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
 	"github.com/0xSemantic/lola-os/internal/core"
 	"github.com/0xSemantic/lola-os/internal/observe"
@@ -43,7 +44,7 @@ func TestPolicy_DailyLimit_Integration(t *testing.T) {
 	tmpDir := t.TempDir()
 	keyFile := tmpDir + "/wallet.key"
 	wallet, _ := evm.NewKeystore(keyFile, "test")
-	gw, _ := evm.NewEVMGateway(context.Background(), "sim", logger, nil, wallet)
+	gw, _ := evm.NewEVMGateway(context.Background(), "sim", logger, nil, 0, wallet)
 	gw.SetClient(client) // we need a method to set client; we'll add for testing.
 
 	// Setup enforcer with daily limit.
@@ -57,12 +58,12 @@ func TestPolicy_DailyLimit_Integration(t *testing.T) {
 	engine := core.NewEngine(reg, enforcer, logger)
 
 	// Create session with chain.
-	sess := engine.CreateSession("", gw)
+	sess := engine.CreateSession("ethereum", map[string]blockchain.Chain{"ethereum": gw})
 	ctx := core.ContextWithSession(context.Background(), sess)
 
 	// Send transaction of 0.3 ETH (should pass).
 	args := map[string]interface{}{
-		"to":     "0x742d35Cc6634C0532925a3b844Bc9e90F1A6B1E7",
+		"to":     "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7",
 		"amount": big.NewInt(300000000000000000),
 	}
 	_, err := engine.Execute(ctx, "transfer", args)
@@ -71,7 +72,10 @@ func TestPolicy_DailyLimit_Integration(t *testing.T) {
 
 	// Send another 0.3 ETH (should exceed daily limit).
 	_, err = engine.Execute(ctx, "transfer", args)
-	assert.ErrorContains(t, err, "daily limit exceeded")
+	var exceeded *security.ErrLimitExceeded
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, "daily_limit", exceeded.Name)
+	assert.True(t, exceeded.Spent.Cmp(big.NewInt(300000000000000000)) >= 0, "the first transfer and its fees")
 }
 
 // EOF: internal/security/policies/integration_test.go
\ No newline at end of file
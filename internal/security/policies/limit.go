@@ -6,33 +6,45 @@ package policies
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
 	"time"
 
-	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/ethereum/go-ethereum/common"
+
 	"github.com/0xSemantic/lola-os/internal/config"
 	"github.com/0xSemantic/lola-os/internal/security"
 )
 
 // LimitPolicy enforces per‑transaction and daily spending limits on native currency.
+// Limits given in usd are compared with the spend's value at the current
+// price, as converted by the policy's USDPricer.
+//
+// Check reserves an allowed spend against the daily limit, so concurrent
+// operations cannot overspend it together. Settle commits the reservation
+// if the tool succeeded and releases it if the tool failed, so a
+// transaction that was never sent does not use up the budget.
 type LimitPolicy struct {
-	mu               sync.RWMutex
-	maxTxValue       *big.Int      // per‑transaction maximum (nil = no limit)
-	dailyLimit       *big.Int      // daily total maximum (nil = no limit)
-	dailySpent       map[string]*big.Int // address -> total spent in current rolling window
-	dailyReset       map[string]time.Time // address -> last reset time
-	window           time.Duration // 24h
+	mu         sync.RWMutex
+	maxTxValue *big.Int                                    // per‑transaction maximum (nil = no limit)
+	dailyLimit *big.Int                                    // daily total maximum (nil = no limit)
+	maxTxUSD   bool                                        // maxTxValue is in 10⁻¹⁸ USD, not wei
+	dailyUSD   bool                                        // dailyLimit and dailySpent are in 10⁻¹⁸ USD
+	pricer     *USDPricer                                  // converts spends for limits in usd
+	dailySpent map[string]*big.Int                         // spendKey -> total spent in current rolling window
+	dailyReset map[string]time.Time                        // spendKey -> last reset time
+	window     time.Duration                               // 24h
+	state      *LimitState                                 // nil = spend is kept in memory only
+	reserved   map[*security.EvaluationContext]reservation // awaiting Settle
 }
 
-type sessionIDer interface {
-	GetID() string
-}
-if sid, ok := evalCtx.Session.(sessionIDer); ok {
-    agentID = sid.GetID()
-} else {
-    agentID = "unknown"
+// reservation is a spend counted by Check and not settled yet.
+type reservation struct {
+	key    string // the budget's key in dailySpent and dailyReset
+	amount *big.Int
+	window time.Time // start of the window the spend was counted in
 }
 
 // NewLimitPolicy creates a policy from configuration.
@@ -41,21 +53,40 @@ func NewLimitPolicy(maxTx, daily *config.Amount) *LimitPolicy {
 		dailySpent: make(map[string]*big.Int),
 		dailyReset: make(map[string]time.Time),
 		window:     24 * time.Hour,
+		reserved:   make(map[*security.EvaluationContext]reservation),
 	}
 	if maxTx != nil {
-		p.maxTxValue = new(big.Int).Set(maxTx.Wei)
+		p.maxTxValue, p.maxTxUSD = limitValue(maxTx)
 	}
 	if daily != nil {
-		p.dailyLimit = new(big.Int).Set(daily.Wei)
+		p.dailyLimit, p.dailyUSD = limitValue(daily)
 	}
 	return p
 }
 
+// limitValue returns a limit in wei, or in 10⁻¹⁸ USD and true.
+func limitValue(a *config.Amount) (*big.Int, bool) {
+	if a.IsUSD() {
+		return usdUnits(a.USD), true
+	}
+	return new(big.Int).Set(a.Wei), false
+}
+
+// SetPricer sets the converter for limits in usd. Without one, every
+// operation a limit in usd applies to is denied.
+func (p *LimitPolicy) SetPricer(pricer *USDPricer) {
+	p.pricer = pricer
+}
+
+// Name returns "limits", the policy's name in decisions and audit entries.
+func (p *LimitPolicy) Name() string { return "limits" }
+
 // Check implements security.Policy.
 func (p *LimitPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
 	// Only apply to transaction tools (send, transfer, etc.).
 	// For simplicity, we check if the tool is one that sends value.
-	if evalCtx.Tool != "transfer" && evalCtx.Tool != "send" && evalCtx.Tool != "swap" {
+	if evalCtx.Tool != "transfer" && evalCtx.Tool != "send" && evalCtx.Tool != "swap" && evalCtx.Tool != "sign" && evalCtx.Tool != "send_raw" &&
+		evalCtx.Tool != "safe_propose" && evalCtx.Tool != "aa_send" && evalCtx.Tool != "cancel" {
 		return nil
 	}
 
@@ -69,42 +100,172 @@ func (p *LimitPolicy) Check(ctx context.Context, evalCtx *security.EvaluationCon
 		return nil // ignore if not *big.Int
 	}
 
+	// Fees count against the limits too; on rollups the L1 data fee can
+	// exceed the value sent.
+	fees, err := estimateFees(ctx, evalCtx)
+	if err != nil {
+		return err
+	}
+	spend := new(big.Int).Add(amount, fees)
+
+	// Limits in usd count the spend's value at the current price.
+	var usd *big.Int
+	if p.maxTxUSD || p.dailyUSD {
+		conv, err := p.pricer.ToUSD(ctx, evalCtx, spend, "limit")
+		if err != nil {
+			return err
+		}
+		usd = conv.USD
+	}
+
 	// Per‑transaction limit.
-	if p.maxTxValue != nil && amount.Cmp(p.maxTxValue) > 0 {
-		return fmt.Errorf("transaction value %s exceeds per‑tx limit %s",
-			amount.String(), p.maxTxValue.String())
+	if p.maxTxUSD && usd.Cmp(p.maxTxValue) > 0 {
+		return &security.ErrLimitExceeded{
+			Name: "max_transaction_value", Unit: "usd", Limit: p.maxTxValue, Attempted: usd,
+			Reason: fmt.Sprintf("transaction value %s%s (%s) exceeds per‑tx limit %s",
+				amount.String(), feeSuffix(fees), formatUSD(usd), formatUSD(p.maxTxValue)),
+		}
+	}
+	if !p.maxTxUSD && p.maxTxValue != nil && spend.Cmp(p.maxTxValue) > 0 {
+		return &security.ErrLimitExceeded{
+			Name: "max_transaction_value", Unit: "wei", Limit: p.maxTxValue, Attempted: spend,
+			Reason: fmt.Sprintf("transaction value %s%s exceeds per‑tx limit %s",
+				amount.String(), feeSuffix(fees), p.maxTxValue.String()),
+		}
 	}
 
 	// Daily limit.
 	if p.dailyLimit != nil {
-		// Identify the agent/address. For now, use session ID as key.
-		session, ok := evalCtx.Session.(interface{ GetID() string }) // we need session to have ID
-		// Since Session is an interface{} in EvaluationContext, we need to cast.
-		// We'll assume it has an ID field. We'll adjust later.
-		// For now, use a placeholder "agent".
-		agentID := "agent" // placeholder
+		// Spending is tracked per chain and signing address, so sessions
+		// sharing a wallet share its budget on each chain, and amounts in
+		// different native currencies are never added up. Operations
+		// without a known signer share one budget per chain.
+		account := ""
+		if signer := evalCtx.Signer(); signer != "" {
+			account = common.HexToAddress(signer).Hex()
+		}
+		key := spendKey(evalCtx.ChainName, account)
 
 		p.mu.Lock()
 		defer p.mu.Unlock()
 
 		now := time.Now().UTC()
-		resetTime, exists := p.dailyReset[agentID]
+		resetTime, exists := p.dailyReset[key]
 		if !exists || now.Sub(resetTime) > p.window {
 			// Reset window.
-			p.dailySpent[agentID] = new(big.Int)
-			p.dailyReset[agentID] = now
+			p.dailySpent[key] = new(big.Int)
+			p.dailyReset[key] = now
 		}
 
-		spent := p.dailySpent[agentID]
-		newSpent := new(big.Int).Add(spent, amount)
+		counted := spend
+		if p.dailyUSD {
+			counted = usd
+		}
+		spent := p.dailySpent[key]
+		newSpent := new(big.Int).Add(spent, counted)
 		if newSpent.Cmp(p.dailyLimit) > 0 {
-			return fmt.Errorf("daily limit %s exceeded, already spent %s, attempted +%s",
-				p.dailyLimit.String(), spent.String(), amount.String())
+			return &security.ErrLimitExceeded{
+				Name: "daily_limit", Unit: p.dailyUnit(), Account: account,
+				Limit: p.dailyLimit, Attempted: counted, Spent: new(big.Int).Set(spent),
+				Reason: fmt.Sprintf("daily limit exceeded for %s: limit %s, already spent %s, attempted +%s",
+					accountLabel(account), p.formatDaily(p.dailyLimit), p.formatDaily(spent), p.formatDaily(counted)),
+			}
+		}
+		p.dailySpent[key] = newSpent
+		if err := p.saveState(); err != nil {
+			if p.state.FailClosed {
+				p.dailySpent[key] = spent
+				return fmt.Errorf("daily limit: %w", err)
+			}
+			p.logState("daily limit state not saved; a restart would forget this spend", err)
 		}
-		p.dailySpent[agentID] = newSpent
+		p.reserved[evalCtx] = reservation{key: key, amount: counted, window: p.dailyReset[key]}
 	}
 
 	return nil
 }
 
-// EOF: internal/security/policies/limit.go
\ No newline at end of file
+// Settle implements security.Settler. It keeps the spend reserved for
+// evalCtx if err is nil and gives it back to the daily budget otherwise,
+// unless the window it was counted in has been reset since.
+func (p *LimitPolicy) Settle(ctx context.Context, evalCtx *security.EvaluationContext, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r, ok := p.reserved[evalCtx]
+	if !ok {
+		return
+	}
+	delete(p.reserved, evalCtx)
+	if err == nil || !p.dailyReset[r.key].Equal(r.window) {
+		return
+	}
+	spent := new(big.Int).Sub(p.dailySpent[r.key], r.amount)
+	if spent.Sign() < 0 {
+		spent.SetInt64(0)
+	}
+	p.dailySpent[r.key] = spent
+	if err := p.saveState(); err != nil {
+		p.logState("daily limit state not saved; a restart would count a failed spend", err)
+	}
+}
+
+// formatDaily renders an amount counted against the daily limit.
+func (p *LimitPolicy) formatDaily(v *big.Int) string {
+	if p.dailyUSD {
+		return formatUSD(v)
+	}
+	return v.String()
+}
+
+// dailyUnit is the unit of the daily limit in errors.
+func (p *LimitPolicy) dailyUnit() string {
+	if p.dailyUSD {
+		return "usd"
+	}
+	return "wei"
+}
+
+// spendKey is the key of the daily budget of account on the chain named
+// chain.
+func spendKey(chain, account string) string {
+	return chain + "/" + account
+}
+
+// accountLabel names a daily budget in errors.
+func accountLabel(account string) string {
+	if account == "" {
+		return "unknown signer"
+	}
+	return account
+}
+
+// estimateFees returns the fees a transfer or send of amount, or the
+// replacement a cancel sends, will pay, as estimated by the session's
+// chain, or zero if the tool builds no transaction from its arguments or
+// the chain cannot estimate fees.
+func estimateFees(ctx context.Context, evalCtx *security.EvaluationContext) (*big.Int, error) {
+	if evalCtx.Tool != "transfer" && evalCtx.Tool != "send" && evalCtx.Tool != "cancel" {
+		return new(big.Int), nil
+	}
+	if _, ok := evalCtx.Args["to"].(string); !ok {
+		return new(big.Int), nil
+	}
+	cost, err := evalCtx.EstimatedCost(ctx)
+	if errors.Is(err, security.ErrCostEstimationUnsupported) {
+		return new(big.Int), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("estimate transaction fees: %w", err)
+	}
+	return cost.Total, nil
+}
+
+// feeSuffix describes fees for limit errors.
+func feeSuffix(fees *big.Int) string {
+	if fees.Sign() == 0 {
+		return ""
+	}
+	return " plus fees " + fees.String()
+}
+
+// EOF: internal/security/policies/limit.go
@@ -2,16 +2,28 @@ package policies_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
 	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/core"
+	"github.com/0xSemantic/lola-os/internal/observe"
 	"github.com/0xSemantic/lola-os/internal/security"
 	"github.com/0xSemantic/lola-os/internal/security/policies"
+	"github.com/0xSemantic/lola-os/internal/tools"
+	"github.com/0xSemantic/lola-os/internal/tools/builtin"
 )
 
 type mockSession struct {
@@ -34,6 +46,70 @@ func TestLimitPolicy_PerTx(t *testing.T) {
 	assert.ErrorContains(t, err, "exceeds per‑tx limit")
 }
 
+func TestLimitPolicy_AppliesToOfflineSigning(t *testing.T) {
+	maxTx := config.MustParseAmount("1 eth")
+	policy := policies.NewLimitPolicy(maxTx, nil)
+
+	evalCtx := &security.EvaluationContext{
+		Tool:    "sign",
+		Args:    map[string]interface{}{"amount": big.NewInt(2e18)}, // 2 eth
+		Session: &mockSession{id: "s1"},
+	}
+	err := policy.Check(context.Background(), evalCtx)
+	assert.ErrorContains(t, err, "exceeds per‑tx limit")
+}
+
+func TestLimitPolicy_AppliesToPayableContractCalls(t *testing.T) {
+	maxTx := config.MustParseAmount("1 eth")
+	policy := policies.NewLimitPolicy(maxTx, nil)
+
+	evalCtx := &security.EvaluationContext{
+		Tool: "send",
+		Args: map[string]interface{}{
+			"to":     "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7",
+			"amount": big.NewInt(2e18), // 2 eth
+			"data":   []byte{0xd0, 0xe3, 0x0d, 0xb0},
+		},
+		Session: &mockSession{id: "s1"},
+	}
+	err := policy.Check(context.Background(), evalCtx)
+	assert.ErrorContains(t, err, "exceeds per‑tx limit")
+}
+
+func TestLimitPolicy_AppliesToSafeProposals(t *testing.T) {
+	maxTx := config.MustParseAmount("1 eth")
+	policy := policies.NewLimitPolicy(maxTx, nil)
+
+	// The limit applies to the value the Safe sends, not to the proposal.
+	evalCtx := &security.EvaluationContext{
+		Tool: "safe_propose",
+		Args: map[string]interface{}{
+			"safe":   "0x5aFE3855358E112B5647B952709E6165e1c1eEEe",
+			"to":     "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7",
+			"amount": big.NewInt(2e18), // 2 eth
+		},
+		Session: &mockSession{id: "s1"},
+	}
+	err := policy.Check(context.Background(), evalCtx)
+	assert.ErrorContains(t, err, "exceeds per‑tx limit")
+}
+
+func TestLimitPolicy_AppliesToUserOperations(t *testing.T) {
+	policy := policies.NewLimitPolicy(config.MustParseAmount("1 eth"), nil)
+
+	// The limit applies to the call the smart account makes.
+	evalCtx := &security.EvaluationContext{
+		Tool: "aa_send",
+		Args: map[string]interface{}{
+			"to":     "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7",
+			"amount": big.NewInt(2e18), // 2 eth
+		},
+		Session: &mockSession{id: "s1"},
+	}
+	err := policy.Check(context.Background(), evalCtx)
+	assert.ErrorContains(t, err, "exceeds per‑tx limit")
+}
+
 func TestLimitPolicy_DailyLimit(t *testing.T) {
 	daily := config.MustParseAmount("1 eth")
 	policy := policies.NewLimitPolicy(nil, daily)
@@ -57,4 +133,224 @@ func TestLimitPolicy_DailyLimit(t *testing.T) {
 	evalCtx.Args["amount"] = big.NewInt(5e17)
 	err = policy.Check(ctx, evalCtx)
 	assert.ErrorContains(t, err, "daily limit exceeded")
-}
\ No newline at end of file
+}
+
+// walletChain is a chain signing with a wallet of the given address.
+type walletChain struct {
+	blockchain.Chain
+	address string
+}
+
+type addressWallet struct {
+	blockchain.Wallet
+	address string
+}
+
+func (w *addressWallet) Address() string { return w.address }
+
+func (c *walletChain) Wallet() blockchain.Wallet { return &addressWallet{address: c.address} }
+
+func TestLimitPolicy_DailyLimitSharedByWallet(t *testing.T) {
+	policy := policies.NewLimitPolicy(nil, config.MustParseAmount("1 eth"))
+	ctx := context.Background()
+	chain := &walletChain{address: "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"}
+	check := func(sessionID string) error {
+		return policy.Check(ctx, &security.EvaluationContext{
+			Tool:    "transfer",
+			Args:    map[string]interface{}{"amount": big.NewInt(6e17)}, // 0.6 eth
+			Session: &chainSession{mockSession{id: sessionID}, chain},
+		})
+	}
+
+	require.NoError(t, check("s1"))
+	err := check("s2")
+	assert.ErrorContains(t, err, "daily limit exceeded for 0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7",
+		"two sessions with one wallet share its budget")
+	var exceeded *security.ErrLimitExceeded
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, &security.ErrLimitExceeded{
+		Name: "daily_limit", Unit: "wei", Account: "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7",
+		Limit: big.NewInt(1e18), Attempted: big.NewInt(6e17), Spent: big.NewInt(6e17), Reason: err.Error(),
+	}, exceeded)
+}
+
+func TestLimitPolicy_DailyLimitPerWallet(t *testing.T) {
+	policy := policies.NewLimitPolicy(nil, config.MustParseAmount("1 eth"))
+	ctx := context.Background()
+	check := func(from string) error {
+		return policy.Check(ctx, &security.EvaluationContext{
+			Tool:    "transfer",
+			Args:    map[string]interface{}{"amount": big.NewInt(6e17)}, // 0.6 eth
+			Session: &mockSession{id: "s1"},
+			From:    from,
+		})
+	}
+
+	first := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	second := "0x5aFE3855358E112B5647B952709E6165e1c1eEEe"
+	require.NoError(t, check(first))
+	require.NoError(t, check(second), "each wallet has its own budget")
+	assert.ErrorContains(t, check(first), "daily limit exceeded")
+	assert.ErrorContains(t, check(strings.ToLower(second)), "daily limit exceeded", "addresses are compared case‑insensitively")
+}
+
+func TestLimitPolicy_DailyLimitPerChain(t *testing.T) {
+	policy := policies.NewLimitPolicy(nil, config.MustParseAmount("1 eth"))
+	ctx := context.Background()
+	wallet := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	check := func(chain string) error {
+		return policy.Check(ctx, &security.EvaluationContext{
+			Tool:      "transfer",
+			Args:      map[string]interface{}{"amount": big.NewInt(6e17)}, // 0.6 of the native currency
+			Session:   &mockSession{id: "s1"},
+			ChainName: chain,
+			From:      wallet,
+		})
+	}
+
+	require.NoError(t, check("ethereum"))
+	require.NoError(t, check("polygon"), "ETH and MATIC are not added up")
+	assert.ErrorContains(t, check("ethereum"), "daily limit exceeded for "+wallet)
+	assert.ErrorContains(t, check("polygon"), "daily limit exceeded for "+wallet)
+}
+
+// feeChain estimates a fixed fee for every transaction.
+type feeChain struct {
+	blockchain.Chain
+	fee *big.Int
+	err error
+}
+
+func (c *feeChain) EstimateTotalCost(ctx context.Context, tx *blockchain.Transaction) (*blockchain.TxCost, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &blockchain.TxCost{DataFee: c.fee, Total: c.fee}, nil
+}
+
+func TestLimitPolicy_IncludesFees(t *testing.T) {
+	policy := policies.NewLimitPolicy(config.MustParseAmount("1 eth"), nil)
+	ctx := context.Background()
+	chain := &feeChain{fee: big.NewInt(2e17)} // 0.2 eth, e.g. an L1 data fee
+	check := func(tool string, amount int64) error {
+		return policy.Check(ctx, &security.EvaluationContext{
+			Tool:    tool,
+			Args:    map[string]interface{}{"to": "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", "amount": big.NewInt(amount)},
+			Session: &chainSession{mockSession{id: "s1"}, chain},
+		})
+	}
+
+	assert.NoError(t, check("transfer", 8e17))
+	err := check("transfer", 9e17)
+	assert.ErrorContains(t, err, "plus fees 200000000000000000 exceeds per‑tx limit")
+	assert.NoError(t, check("send_raw", 9e17), "raw transactions carry their own fees")
+
+	chain.err = errors.New("rpc down")
+	assert.ErrorContains(t, check("transfer", 1), "rpc down")
+}
+
+// newRejectingGateway returns a gateway on a fake legacy chain whose node
+// rejects every raw transaction.
+func newRejectingGateway(t *testing.T) *evm.EVMGateway {
+	t.Helper()
+	results := map[string]interface{}{
+		"eth_chainId":             "0x1",
+		"eth_getTransactionCount": "0x0",
+		"eth_gasPrice":            "0x3b9aca00",
+		"eth_estimateGas":         "0x5208",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		reply := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if result, ok := results[req.Method]; ok {
+			reply["result"] = result
+		} else {
+			reply["error"] = map[string]interface{}{"code": -32000, "message": "insufficient funds for gas * price + value"}
+		}
+		_ = json.NewEncoder(w).Encode(reply)
+	}))
+	t.Cleanup(srv.Close)
+	wallet, err := evm.NewKeystoreWithOptions(filepath.Join(t.TempDir(), "wallet.key"), "test", evm.LightKDF)
+	require.NoError(t, err)
+	gw, err := evm.NewEVMGateway(context.Background(), srv.URL, &observe.NoopLogger{},
+		&evm.RetryConfig{MaxAttempts: 1}, time.Second, wallet)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = gw.Close() })
+	return gw
+}
+
+func TestLimitPolicy_FailedTransferReleasesSpend(t *testing.T) {
+	policy := policies.NewLimitPolicy(nil, config.MustParseAmount("1 eth"))
+	enforcer := security.NewEnforcer()
+	enforcer.AddPolicy(policy)
+	reg := tools.New()
+	require.NoError(t, reg.Register("transfer", builtin.Transfer))
+	engine := core.NewEngine(reg, enforcer, &observe.NoopLogger{})
+
+	gw := newRejectingGateway(t)
+	sess := engine.CreateSession("ethereum", map[string]blockchain.Chain{"ethereum": gw})
+	ctx := core.ContextWithSession(context.Background(), sess)
+	args := map[string]interface{}{
+		"to":     "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7",
+		"amount": big.NewInt(9e17), // 0.9 eth
+	}
+	for i := 0; i < 3; i++ {
+		_, err := engine.Execute(ctx, "transfer", args)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "daily limit exceeded", "attempt %d", i+1)
+	}
+
+	// The whole budget is still available to the wallet.
+	evalCtx := &security.EvaluationContext{
+		Tool: "send_raw",
+		Args: map[string]interface{}{"amount": big.NewInt(1e18)},
+		From: gw.Wallet().Address(),
+	}
+	assert.NoError(t, policy.Check(ctx, evalCtx))
+}
+
+// denyPolicy denies every operation.
+type denyPolicy struct{}
+
+func (denyPolicy) Check(context.Context, *security.EvaluationContext) error {
+	return errors.New("denied")
+}
+
+func TestLimitPolicy_Settle(t *testing.T) {
+	policy := policies.NewLimitPolicy(nil, config.MustParseAmount("1 eth"))
+	ctx := context.Background()
+	from := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	newEvalCtx := func(amount int64) *security.EvaluationContext {
+		return &security.EvaluationContext{
+			Tool: "transfer",
+			Args: map[string]interface{}{"amount": big.NewInt(amount)},
+			From: from,
+		}
+	}
+
+	// A reserved spend counts until it is settled.
+	pending := newEvalCtx(6e17)
+	require.NoError(t, policy.Check(ctx, pending))
+	assert.ErrorContains(t, policy.Check(ctx, newEvalCtx(6e17)), "already spent 600000000000000000")
+
+	// Released on failure, and only once.
+	policy.Settle(ctx, pending, errors.New("rpc down"))
+	policy.Settle(ctx, pending, errors.New("rpc down"))
+	committed := newEvalCtx(6e17)
+	require.NoError(t, policy.Check(ctx, committed))
+
+	// Kept on success.
+	policy.Settle(ctx, committed, nil)
+	assert.ErrorContains(t, policy.Check(ctx, newEvalCtx(6e17)), "daily limit exceeded")
+
+	// Released when a later policy denies the operation.
+	enforcer := security.NewEnforcer()
+	enforcer.AddPolicy(policy)
+	enforcer.AddPolicy(denyPolicy{})
+	assert.ErrorContains(t, enforcer.Evaluate(ctx, newEvalCtx(4e17)), "denied")
+	assert.NoError(t, policy.Check(ctx, newEvalCtx(4e17)))
+}
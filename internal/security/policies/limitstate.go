@@ -0,0 +1,202 @@
+// Package policies provides persistence of LimitPolicy's daily spend and
+// RatePolicy's transaction times, so a restart does not reset the limits.
+//
+// File: internal/security/policies/limitstate.go
+
+package policies
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+)
+
+// LimitState configures where a LimitPolicy keeps its daily spend, a
+// GasPolicy its daily fees, or a RatePolicy its transaction times.
+type LimitState struct {
+	// Path of the JSON state file. It is read when the policy is created
+	// and rewritten, with fsync, after every transaction counted against
+	// the limit.
+	Path string
+	// FailClosed refuses to start from a state file that cannot be read,
+	// and denies transactions whose spend cannot be saved. Otherwise
+	// (the default) both are logged as errors and the policy carries on,
+	// counting from zero for an unreadable file.
+	FailClosed bool
+	// Logger receives state errors (nil = not logged).
+	Logger observe.Logger
+}
+
+// limitState is the on‑disk form of the daily spend or fees: per chain and signing
+// address, keyed "<chain>/<address>" (an empty address for unknown
+// signers), the wei spent and the start of the window.
+type limitState struct {
+	Unit     string                `json:"unit,omitempty"` // "usd" for 10⁻¹⁸ USD, else wei
+	Accounts map[string]limitEntry `json:"accounts"`
+}
+
+type limitEntry struct {
+	Spent       string    `json:"spent"`
+	WindowStart time.Time `json:"window_start"`
+}
+
+// NewLimitPolicyWithState is NewLimitPolicy keeping the daily spend in
+// state.Path, so that it survives restarts. A missing file starts from
+// zero, as on the first run. An unreadable file is an error if
+// state.FailClosed is set.
+func NewLimitPolicyWithState(maxTx, daily *config.Amount, state LimitState) (*LimitPolicy, error) {
+	p := NewLimitPolicy(maxTx, daily)
+	p.state = &state
+	if err := p.loadState(); err != nil {
+		if state.FailClosed {
+			return nil, fmt.Errorf("daily limit: %w", err)
+		}
+		p.logState("daily limit state unreadable; daily spend starts from zero", err)
+	}
+	return p, nil
+}
+
+// loadState reads the saved spend, if any.
+func (p *LimitPolicy) loadState() error {
+	return loadSpend(p.state, p.stateUnit(), "no daily limit state yet; daily spend starts from zero", p.dailySpent, p.dailyReset)
+}
+
+// saveState writes the spend atomically and durably; p.mu must be held.
+func (p *LimitPolicy) saveState() error {
+	if p.state == nil {
+		return nil
+	}
+	return saveSpend(p.state.Path, p.stateUnit(), p.dailySpent, p.dailyReset)
+}
+
+// loadSpend reads the spend saved in state.Path, in unit, into spent and
+// windows, logging missing if there is none yet.
+func loadSpend(state *LimitState, unit, missing string, spent map[string]*big.Int, windows map[string]time.Time) error {
+	var saved limitState
+	found, err := readStateFile(state.Path, &saved)
+	if err != nil {
+		return err
+	}
+	if !found {
+		state.warn(missing)
+		return nil
+	}
+	if saved.Unit != unit {
+		return fmt.Errorf("parse state %s: spend is in %s, the limit in %s", state.Path, unitName(saved.Unit), unitName(unit))
+	}
+	parsed := make(map[string]*big.Int, len(saved.Accounts))
+	for account, entry := range saved.Accounts {
+		v, ok := new(big.Int).SetString(entry.Spent, 10)
+		if !ok || v.Sign() < 0 {
+			return fmt.Errorf("parse state %s: account %q: invalid spend %q", state.Path, account, entry.Spent)
+		}
+		parsed[account] = v
+	}
+	for account, entry := range saved.Accounts {
+		spent[account] = parsed[account]
+		windows[account] = entry.WindowStart
+	}
+	return nil
+}
+
+// saveSpend writes spent and windows, in unit, to path atomically and
+// durably.
+func saveSpend(path, unit string, spent map[string]*big.Int, windows map[string]time.Time) error {
+	state := limitState{Unit: unit, Accounts: make(map[string]limitEntry, len(spent))}
+	for account, v := range spent {
+		state.Accounts[account] = limitEntry{Spent: v.String(), WindowStart: windows[account]}
+	}
+	return writeStateFile(path, state)
+}
+
+// stateUnit is the unit of the saved spend.
+func (p *LimitPolicy) stateUnit() string {
+	if p.dailyUSD {
+		return "usd"
+	}
+	return ""
+}
+
+func unitName(unit string) string {
+	if unit == "" {
+		return "wei"
+	}
+	return unit
+}
+
+// logState logs a state error loudly.
+func (p *LimitPolicy) logState(msg string, err error) {
+	p.state.log(msg, err)
+}
+
+// readStateFile decodes the JSON state file at path into v. It reports
+// false, and leaves v alone, if the file does not exist.
+func readStateFile(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read state: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("parse state %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// writeStateFile replaces the state file at path with v as JSON,
+// atomically and durably.
+func writeStateFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save state: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+	return nil
+}
+
+// warn logs a state warning.
+func (s *LimitState) warn(msg string) {
+	if s.Logger != nil {
+		s.Logger.Warn(msg, map[string]interface{}{"path": s.Path})
+	}
+}
+
+// log logs a state error loudly.
+func (s *LimitState) log(msg string, err error) {
+	if s.Logger != nil {
+		s.Logger.Error(msg, map[string]interface{}{"path": s.Path, "error": err.Error()})
+	}
+}
+
+// EOF: internal/security/policies/limitstate.go
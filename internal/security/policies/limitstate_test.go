@@ -0,0 +1,91 @@
+package policies_test
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+// errorLogger records the messages of Warn and Error.
+type errorLogger struct {
+	observe.NoopLogger
+	warnings, errors []string
+}
+
+func (l *errorLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.warnings = append(l.warnings, msg)
+}
+
+func (l *errorLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.errors = append(l.errors, msg)
+}
+
+// spend checks a transfer of amount wei signed by from.
+func spend(policy *policies.LimitPolicy, from string, amount int64) error {
+	return policy.Check(context.Background(), &security.EvaluationContext{
+		Tool:    "transfer",
+		Args:    map[string]interface{}{"amount": big.NewInt(amount)},
+		Session: &mockSession{id: "s1"},
+		From:    from,
+	})
+}
+
+func TestLimitPolicy_StateSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "limits.json")
+	daily := config.MustParseAmount("1 eth")
+	first := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	second := "0x5aFE3855358E112B5647B952709E6165e1c1eEEe"
+
+	logger := &errorLogger{}
+	policy, err := policies.NewLimitPolicyWithState(nil, daily, policies.LimitState{Path: path, Logger: logger})
+	require.NoError(t, err)
+	assert.Len(t, logger.warnings, 1, "a missing file is logged")
+	require.NoError(t, spend(policy, first, 6e17))
+	require.NoError(t, spend(policy, second, 3e17))
+	assert.FileExists(t, path)
+
+	// A new policy, as after a restart, continues from the saved spend.
+	restarted, err := policies.NewLimitPolicyWithState(nil, daily, policies.LimitState{Path: path})
+	require.NoError(t, err)
+	assert.ErrorContains(t, spend(restarted, first, 6e17), "already spent 600000000000000000")
+	require.NoError(t, spend(restarted, second, 7e17))
+	assert.ErrorContains(t, spend(restarted, second, 1), "daily limit exceeded")
+
+	// A rejected spend is not saved.
+	again, err := policies.NewLimitPolicyWithState(nil, daily, policies.LimitState{Path: path})
+	require.NoError(t, err)
+	assert.NoError(t, spend(again, first, 4e17))
+}
+
+func TestLimitPolicy_CorruptState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"accounts":`), 0600))
+	daily := config.MustParseAmount("1 eth")
+
+	_, err := policies.NewLimitPolicyWithState(nil, daily, policies.LimitState{Path: path, FailClosed: true})
+	assert.ErrorContains(t, err, "parse state")
+
+	logger := &errorLogger{}
+	policy, err := policies.NewLimitPolicyWithState(nil, daily, policies.LimitState{Path: path, Logger: logger})
+	require.NoError(t, err, "fails open by default")
+	assert.Len(t, logger.errors, 1, "and says so loudly")
+	require.NoError(t, spend(policy, "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", 6e17))
+
+	// The next save repairs the file.
+	_, err = policies.NewLimitPolicyWithState(nil, daily, policies.LimitState{Path: path, FailClosed: true})
+	assert.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"accounts":{"":{"spent":"-1"}}}`), 0600))
+	_, err = policies.NewLimitPolicyWithState(nil, daily, policies.LimitState{Path: path, FailClosed: true})
+	assert.ErrorContains(t, err, "invalid spend")
+}
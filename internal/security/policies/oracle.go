@@ -0,0 +1,254 @@
+// Package policies provides price oracles for spending limits given in US
+// dollars.
+//
+// File: internal/security/policies/oracle.go
+
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/sdk/types/units"
+)
+
+// PriceOracle prices assets in US dollars.
+type PriceOracle interface {
+	// Price returns the price in USD of one whole unit of symbol, such
+	// as 1 ETH. Symbols match case‑insensitively.
+	Price(ctx context.Context, symbol string) (*big.Rat, error)
+}
+
+// StaticOracle prices assets at fixed rates.
+type StaticOracle struct {
+	prices map[string]*big.Rat // upper‑case symbol -> price
+}
+
+// NewStaticOracle creates an oracle from prices by symbol, each an amount
+// in usd.
+func NewStaticOracle(prices map[string]*config.Amount) (*StaticOracle, error) {
+	o := &StaticOracle{prices: make(map[string]*big.Rat, len(prices))}
+	for symbol, price := range prices {
+		if !price.IsUSD() || price.USD.Sign() <= 0 {
+			return nil, fmt.Errorf("static price of %s: must be a positive amount in usd", symbol)
+		}
+		o.prices[strings.ToUpper(symbol)] = price.USD
+	}
+	return o, nil
+}
+
+// Price implements PriceOracle.
+func (o *StaticOracle) Price(ctx context.Context, symbol string) (*big.Rat, error) {
+	price, ok := o.prices[strings.ToUpper(symbol)]
+	if !ok {
+		return nil, fmt.Errorf("static price: no price for %s", symbol)
+	}
+	return price, nil
+}
+
+// Chainlink AggregatorV3Interface selectors.
+var (
+	latestRoundDataSelector = []byte{0xfe, 0xaf, 0x96, 0x8c} // latestRoundData()
+	feedDecimalsSelector    = []byte{0x31, 0x3c, 0xe5, 0x67} // decimals()
+)
+
+// ChainlinkOracle prices assets with Chainlink price feeds, read from the
+// chains they are deployed on. Answers older than the maximum age are
+// rejected as stale.
+type ChainlinkOracle struct {
+	mu     sync.Mutex
+	feeds  map[string][]*chainlinkFeed // upper‑case symbol -> feeds, tried in order
+	maxAge time.Duration
+}
+
+type chainlinkFeed struct {
+	chain    blockchain.Chain
+	address  string
+	decimals int // -1 until read
+}
+
+// NewChainlinkOracle creates an oracle without feeds; maxAge 0 is an hour.
+func NewChainlinkOracle(maxAge time.Duration) *ChainlinkOracle {
+	if maxAge == 0 {
+		maxAge = time.Hour
+	}
+	return &ChainlinkOracle{
+		feeds:  make(map[string][]*chainlinkFeed),
+		maxAge: maxAge,
+	}
+}
+
+// AddFeed adds the feed at address on chain, which prices symbol in USD.
+// Feeds added for the same symbol are tried in order.
+func (o *ChainlinkOracle) AddFeed(symbol string, chain blockchain.Chain, address string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	symbol = strings.ToUpper(symbol)
+	o.feeds[symbol] = append(o.feeds[symbol], &chainlinkFeed{chain: chain, address: address, decimals: -1})
+}
+
+// Price implements PriceOracle.
+func (o *ChainlinkOracle) Price(ctx context.Context, symbol string) (*big.Rat, error) {
+	o.mu.Lock()
+	feeds := o.feeds[strings.ToUpper(symbol)]
+	o.mu.Unlock()
+	if len(feeds) == 0 {
+		return nil, fmt.Errorf("chainlink: no feed for %s", symbol)
+	}
+	var errs []error
+	for _, feed := range feeds {
+		price, err := o.read(ctx, feed)
+		if err == nil {
+			return price, nil
+		}
+		errs = append(errs, fmt.Errorf("feed %s: %w", feed.address, err))
+	}
+	return nil, fmt.Errorf("chainlink: %s: %w", symbol, errors.Join(errs...))
+}
+
+// read reads the latest answer of feed.
+func (o *ChainlinkOracle) read(ctx context.Context, feed *chainlinkFeed) (*big.Rat, error) {
+	o.mu.Lock()
+	decimals := feed.decimals
+	o.mu.Unlock()
+	if decimals < 0 {
+		out, err := feed.chain.CallContract(ctx, &blockchain.ContractCall{To: feed.address, Data: feedDecimalsSelector})
+		if err != nil {
+			return nil, fmt.Errorf("read decimals: %w", err)
+		}
+		if len(out) != 32 || new(big.Int).SetBytes(out).Cmp(big.NewInt(36)) > 0 {
+			return nil, fmt.Errorf("read decimals: invalid result %#x", out)
+		}
+		decimals = int(out[31])
+		o.mu.Lock()
+		feed.decimals = decimals
+		o.mu.Unlock()
+	}
+
+	// latestRoundData returns (roundId, answer, startedAt, updatedAt,
+	// answeredInRound).
+	out, err := feed.chain.CallContract(ctx, &blockchain.ContractCall{To: feed.address, Data: latestRoundDataSelector})
+	if err != nil {
+		return nil, fmt.Errorf("read latest round: %w", err)
+	}
+	if len(out) != 5*32 {
+		return nil, fmt.Errorf("read latest round: invalid result of %d bytes", len(out))
+	}
+	answer := new(big.Int).SetBytes(out[32:64])
+	if out[32]&0x80 != 0 || answer.Sign() == 0 {
+		return nil, errors.New("invalid answer: not positive")
+	}
+	updatedAt := time.Unix(new(big.Int).SetBytes(out[96:128]).Int64(), 0)
+	if age := time.Since(updatedAt); age > o.maxAge {
+		return nil, fmt.Errorf("stale answer: updated %s ago, max age %s", age.Round(time.Second), o.maxAge)
+	}
+	return new(big.Rat).SetFrac(answer, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)), nil
+}
+
+// USDPricer converts native currency amounts to US dollars for limits
+// given in usd, and records every conversion in the audit log so that
+// decisions can be reviewed later.
+type USDPricer struct {
+	// Oracle prices the native currency.
+	Oracle PriceOracle
+	// Fallback prices it when Oracle fails (nil = the operation is denied).
+	Fallback PriceOracle
+	// Symbols maps chain names to their native currency symbols.
+	Symbols map[string]string
+	// Audit receives the conversions (nil = not recorded).
+	Audit *observe.AuditLogger
+}
+
+// Conversion is a native currency amount converted to US dollars.
+type Conversion struct {
+	Symbol string
+	Price  *big.Rat // USD per whole unit
+	Source string   // "oracle" or "fallback"
+	Wei    *big.Int
+	USD    *big.Int // in units of 10⁻¹⁸ USD, rounded up
+}
+
+// ToUSD converts wei of evalCtx's chain's native currency at the current
+// price and records the conversion for policy. An operation whose value
+// cannot be priced is denied rather than allowed.
+func (p *USDPricer) ToUSD(ctx context.Context, evalCtx *security.EvaluationContext, wei *big.Int, policy string) (*Conversion, error) {
+	if p == nil || p.Oracle == nil {
+		return nil, errors.New("usd limit: no price oracle configured")
+	}
+	symbol := p.Symbols[strings.ToLower(evalCtx.ChainName)]
+	if symbol == "" {
+		return nil, fmt.Errorf("usd limit: native currency of chain %q unknown", evalCtx.ChainName)
+	}
+	conv := &Conversion{Symbol: symbol, Source: "oracle", Wei: wei}
+	price, err := p.Oracle.Price(ctx, symbol)
+	var oracleErr error
+	if err != nil {
+		if p.Fallback == nil {
+			return nil, fmt.Errorf("usd limit: price %s: %w", symbol, err)
+		}
+		oracleErr = err
+		if price, err = p.Fallback.Price(ctx, symbol); err != nil {
+			return nil, fmt.Errorf("usd limit: price %s: %w", symbol, errors.Join(oracleErr, err))
+		}
+		conv.Source = "fallback"
+	}
+	conv.Price = price
+	conv.USD = usdUnits(new(big.Rat).Mul(new(big.Rat).SetFrac(wei, weiPerEther), price))
+	p.record(evalCtx, policy, conv, oracleErr)
+	return conv, nil
+}
+
+// record writes conv to the audit log. Audit failures do not fail the
+// operation.
+func (p *USDPricer) record(evalCtx *security.EvaluationContext, policy string, conv *Conversion, oracleErr error) {
+	if p.Audit == nil {
+		return
+	}
+	extra := map[string]interface{}{
+		"action": "usd_conversion",
+		"policy": policy,
+		"tool":   evalCtx.Tool,
+		"symbol": conv.Symbol,
+		"price":  conv.Price.FloatString(8),
+		"usd":    formatUSD(conv.USD),
+		"source": conv.Source,
+	}
+	if oracleErr != nil {
+		extra["oracle_error"] = oracleErr.Error()
+	}
+	entry := evalCtx.AuditEntry(observe.AuditKindPolicyDecision)
+	entry.Value = conv.Wei.String()
+	entry.Extra = extra
+	_ = p.Audit.Log(entry)
+}
+
+// weiPerEther is the number of base units in a whole unit of native
+// currency, the same on every EVM chain.
+var weiPerEther = big.NewInt(1e18)
+
+// usdUnits returns usd in units of 10⁻¹⁸ USD, rounded up so that limits
+// err on the side of denying.
+func usdUnits(usd *big.Rat) *big.Int {
+	n := new(big.Int).Mul(usd.Num(), weiPerEther)
+	q, r := new(big.Int).QuoRem(n, usd.Denom(), new(big.Int))
+	if r.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// formatUSD renders an amount in units of 10⁻¹⁸ USD in cents.
+func formatUSD(v *big.Int) string {
+	return units.FormatUnits(v, 18, 2) + " usd"
+}
+
+// EOF: internal/security/policies/oracle.go
@@ -0,0 +1,168 @@
+package policies_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+// feedChain serves a Chainlink feed with 8 decimals.
+type feedChain struct {
+	blockchain.Chain
+	answer    int64
+	updatedAt time.Time
+	err       error
+}
+
+func (c *feedChain) CallContract(ctx context.Context, call *blockchain.ContractCall) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	word := func(v int64) []byte {
+		n := big.NewInt(v)
+		if v < 0 { // two's complement
+			n.Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return common.LeftPadBytes(n.Bytes(), 32)
+	}
+	if common.Bytes2Hex(call.Data) == "313ce567" {
+		return word(8), nil
+	}
+	var out []byte
+	for _, v := range []int64{1, c.answer, c.updatedAt.Unix(), c.updatedAt.Unix(), 1} {
+		out = append(out, word(v)...)
+	}
+	return out, nil
+}
+
+func usdEvalCtx(amount *big.Int) *security.EvaluationContext {
+	return &security.EvaluationContext{
+		Tool:      "transfer",
+		Args:      map[string]interface{}{"amount": amount},
+		Session:   &mockSession{id: "s1"},
+		ChainName: "ethereum",
+		From:      agent,
+	}
+}
+
+// eth is an amount of ETH in wei, in thousandths.
+func eth(milli int64) *big.Int { return new(big.Int).Mul(big.NewInt(milli), big.NewInt(1e15)) }
+
+func staticPrices(t *testing.T, prices map[string]string) *policies.StaticOracle {
+	t.Helper()
+	amounts := make(map[string]*config.Amount, len(prices))
+	for symbol, price := range prices {
+		amounts[symbol] = config.MustParseAmount(price)
+	}
+	oracle, err := policies.NewStaticOracle(amounts)
+	require.NoError(t, err)
+	return oracle
+}
+
+func TestChainlinkOracle(t *testing.T) {
+	ctx := context.Background()
+	oracle := policies.NewChainlinkOracle(time.Hour)
+	_, err := oracle.Price(ctx, "ETH")
+	assert.ErrorContains(t, err, "no feed for ETH")
+
+	down := &feedChain{err: errors.New("connection refused")}
+	live := &feedChain{answer: 250012345678, updatedAt: time.Now()}
+	oracle.AddFeed("ETH", down, "0x1")
+	oracle.AddFeed("eth", live, "0x2")
+	price, err := oracle.Price(ctx, "Eth")
+	require.NoError(t, err, "the second feed answers")
+	assert.Equal(t, "2500.12345678", price.FloatString(8))
+
+	live.updatedAt = time.Now().Add(-2 * time.Hour)
+	_, err = oracle.Price(ctx, "ETH")
+	assert.ErrorContains(t, err, "stale answer")
+	assert.ErrorContains(t, err, "connection refused")
+
+	live.updatedAt, live.answer = time.Now(), -1
+	_, err = oracle.Price(ctx, "ETH")
+	assert.ErrorContains(t, err, "not positive")
+
+	_, err = policies.NewStaticOracle(map[string]*config.Amount{"eth": config.MustParseAmount("1 eth")})
+	assert.Error(t, err, "static prices are in usd")
+}
+
+func TestLimitPolicy_USD(t *testing.T) {
+	ctx := context.Background()
+	policy := policies.NewLimitPolicy(config.MustParseAmount("500 usd"), config.MustParseAmount("1000 usd"))
+	assert.ErrorContains(t, policy.Check(ctx, usdEvalCtx(eth(1))), "no price oracle configured", "fails closed")
+
+	policy.SetPricer(&policies.USDPricer{
+		Oracle:  staticPrices(t, map[string]string{"eth": "2500 usd"}),
+		Symbols: map[string]string{"ethereum": "ETH"},
+	})
+	require.NoError(t, policy.Check(ctx, usdEvalCtx(eth(200))))
+	err := policy.Check(ctx, usdEvalCtx(eth(201)))
+	assert.ErrorContains(t, err, "transaction value 201000000000000000 (502.5 usd) exceeds per‑tx limit 500 usd")
+	require.NoError(t, policy.Check(ctx, usdEvalCtx(eth(180))))
+	err = policy.Check(ctx, usdEvalCtx(eth(50)))
+	assert.ErrorContains(t, err, "daily limit exceeded for "+agent+": limit 1000 usd, already spent 950 usd, attempted +125 usd")
+
+	other := usdEvalCtx(eth(1))
+	other.ChainName = "gnosis"
+	assert.ErrorContains(t, policy.Check(ctx, other), `native currency of chain "gnosis" unknown`)
+}
+
+func TestUSDPricer_Fallback(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+
+	chainlink := policies.NewChainlinkOracle(0)
+	chainlink.AddFeed("ETH", &feedChain{err: errors.New("rpc down")}, "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419")
+	pricer := &policies.USDPricer{Oracle: chainlink, Symbols: map[string]string{"ethereum": "ETH"}, Audit: audit}
+
+	hitl := policies.NewHITLPolicy(config.MustParseAmount("100 usd"), 0, "")
+	hitl.SetPricer(pricer)
+	err = hitl.Check(ctx, usdEvalCtx(eth(10)))
+	assert.ErrorContains(t, err, "usd limit: price ETH: chainlink: ETH: feed 0x5f4e")
+	assert.ErrorContains(t, err, "rpc down", "never silently allowed")
+
+	pricer.Fallback = staticPrices(t, map[string]string{"ETH": "3000 usd"})
+	require.NoError(t, hitl.Check(ctx, usdEvalCtx(eth(10))), "30 usd is under the threshold")
+
+	// The conversion is recorded for review.
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var entries []observe.AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry observe.AuditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "s1", entry.SessionID)
+	assert.Equal(t, "ethereum", entry.Chain)
+	assert.Equal(t, "10000000000000000", entry.Value)
+	assert.Equal(t, "usd_conversion", entry.Extra["action"])
+	assert.Equal(t, "human_in_the_loop", entry.Extra["policy"])
+	assert.Equal(t, "3000.00000000", entry.Extra["price"])
+	assert.Equal(t, "30 usd", entry.Extra["usd"])
+	assert.Equal(t, "fallback", entry.Extra["source"])
+	assert.Contains(t, entry.Extra["oracle_error"], "rpc down")
+}
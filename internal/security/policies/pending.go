@@ -0,0 +1,213 @@
+// Package policies provides a policy that holds back new transactions
+// while earlier ones of the signing account are stuck.
+//
+// File: internal/security/policies/pending.go
+
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// nonceTools are the write tools that send a transaction with the signing
+// account's next nonce. cancel is not one of them: replacing the stuck
+// transaction is the way out.
+var nonceTools = map[string]bool{
+	"transfer": true,
+	"send":     true,
+	"swap":     true,
+	"deploy":   true,
+	"approve":  true,
+}
+
+// nonceChain is implemented by chains that report an account's nonce in
+// the latest block and in the pending state, such as the EVM gateway.
+type nonceChain interface {
+	GetTransactionCount(ctx context.Context, address string, block blockchain.BlockNumber) (uint64, error)
+}
+
+// trackerChain is implemented by chains that may track the transactions
+// they broadcast, such as the EVM gateway.
+type trackerChain interface {
+	Tracker() *evm.TxTracker
+}
+
+// PendingTxReport is what PendingTxPolicy decided on, recorded with its
+// decisions.
+type PendingTxReport struct {
+	Account string `json:"account"`
+	// Nonce is the nonce of the oldest pending transaction: the account's
+	// nonce in the latest block.
+	Nonce   uint64 `json:"nonce"`
+	Pending uint64 `json:"pending"` // transactions sent and not yet mined
+	// OldestAgeSeconds is how long the oldest pending transaction has
+	// waited, as far as known.
+	OldestAgeSeconds int64 `json:"oldest_age_seconds,omitempty"`
+}
+
+// PendingTxPolicy holds back a new transaction while more than max_pending
+// transactions of the signing account are pending, or the oldest has been
+// pending longer than max_age: new transactions would only queue up behind
+// it, and all go through at once when it is mined. The operation is denied,
+// or put to the Approver, pointing at speeding up or cancelling the stuck
+// transaction instead.
+//
+// The pending count is the difference between the account's nonce in the
+// pending state and in the latest block. The age of the oldest is when
+// the chain's transaction tracker saw it sent, if it did, else when the
+// policy first saw the latest nonce stuck.
+type PendingTxPolicy struct {
+	mu         sync.Mutex
+	maxPending uint64
+	maxAge     time.Duration
+	approver   Approver
+	stuck      map[string]stuckNonce                            // chain and account -> latest nonce
+	reports    map[*security.EvaluationContext]*PendingTxReport // until Report
+}
+
+// stuckNonce is when the policy first saw an account's latest nonce with
+// transactions pending.
+type stuckNonce struct {
+	nonce uint64
+	since time.Time
+}
+
+// NewPendingTxPolicy creates the policy from cfg. If cfg.OnExceed is
+// "approve", operations over a limit are put to approver, which must not
+// be nil.
+func NewPendingTxPolicy(cfg *config.PendingTxConfig, approver Approver) (*PendingTxPolicy, error) {
+	if cfg.MaxPending < 0 || cfg.MaxAge < 0 {
+		return nil, errors.New("pending tx: max_pending and max_age must not be negative")
+	}
+	p := &PendingTxPolicy{
+		maxPending: uint64(cfg.MaxPending),
+		maxAge:     cfg.MaxAge,
+		stuck:      make(map[string]stuckNonce),
+		reports:    make(map[*security.EvaluationContext]*PendingTxReport),
+	}
+	switch cfg.OnExceed {
+	case "", "deny":
+	case "approve":
+		if approver == nil {
+			return nil, errors.New("pending tx: on_exceed approve needs an approver")
+		}
+		p.approver = approver
+	default:
+		return nil, fmt.Errorf("pending tx: invalid on_exceed %q", cfg.OnExceed)
+	}
+	return p, nil
+}
+
+// Name returns "pending_tx", the policy's name in decisions and audit entries.
+func (p *PendingTxPolicy) Name() string { return "pending_tx" }
+
+// Prompts implements security.Prompter: the policy asks the approver
+// about operations over a limit if on_exceed is "approve".
+func (p *PendingTxPolicy) Prompts() bool { return p.approver != nil }
+
+// Check implements security.Policy. Operations whose chain cannot report
+// nonces, or whose signer is not known, are allowed; a failure to read the
+// nonces denies.
+func (p *PendingTxPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	if !nonceTools[evalCtx.Tool] {
+		return nil
+	}
+	account := evalCtx.Signer()
+	chain, ok := evalCtx.Chain().(nonceChain)
+	if account == "" || !ok {
+		return nil
+	}
+	latest, err := chain.GetTransactionCount(ctx, account, blockchain.BlockNumberLatest)
+	if err != nil {
+		return fmt.Errorf("pending tx: nonce of %s: %w", account, err)
+	}
+	pending, err := chain.GetTransactionCount(ctx, account, blockchain.BlockNumberPending)
+	if err != nil {
+		return fmt.Errorf("pending tx: pending nonce of %s: %w", account, err)
+	}
+	report := &PendingTxReport{Account: account, Nonce: latest}
+	if pending > latest {
+		report.Pending = pending - latest
+	}
+	age := p.oldestAge(evalCtx, account, latest, report.Pending)
+	report.OldestAgeSeconds = int64(age / time.Second)
+
+	p.mu.Lock()
+	p.reports[evalCtx] = report
+	p.mu.Unlock()
+
+	var reason string
+	switch {
+	case p.maxPending > 0 && report.Pending > p.maxPending:
+		reason = fmt.Sprintf("%d transactions of %s are pending, more than max_pending %d", report.Pending, account, p.maxPending)
+	case p.maxAge > 0 && report.Pending > 0 && age > p.maxAge:
+		reason = fmt.Sprintf("transaction %d of %s has been pending for %s, longer than max_age %s", latest, account, age.Round(time.Second), p.maxAge)
+	default:
+		return nil
+	}
+	reason += "; speed it up or cancel it before sending more"
+	if p.approver != nil {
+		return p.approver.Approve(ctx, evalCtx, reason)
+	}
+	return errors.New("pending tx: " + reason)
+}
+
+// oldestAge returns how long the transaction with nonce latest of account
+// has been pending, 0 if none is.
+func (p *PendingTxPolicy) oldestAge(evalCtx *security.EvaluationContext, account string, latest, pending uint64) time.Duration {
+	key := evalCtx.ChainName + "/" + strings.ToLower(account)
+	now := time.Now()
+
+	p.mu.Lock()
+	if pending == 0 {
+		delete(p.stuck, key)
+		p.mu.Unlock()
+		return 0
+	}
+	seen, ok := p.stuck[key]
+	if !ok || seen.nonce != latest {
+		seen = stuckNonce{nonce: latest, since: now}
+		p.stuck[key] = seen
+	}
+	p.mu.Unlock()
+
+	since := seen.since
+	if tc, ok := evalCtx.Chain().(trackerChain); ok {
+		if tracker := tc.Tracker(); tracker != nil {
+			from := common.HexToAddress(account)
+			for _, tx := range tracker.Pending() {
+				if tx.From == from && tx.Nonce >= latest && tx.SentAt.Before(since) {
+					since = tx.SentAt
+				}
+			}
+		}
+	}
+	return now.Sub(since)
+}
+
+// Report implements security.Reporter: it returns the pending count and
+// age Check decided on for evalCtx, or nil if it did not look.
+func (p *PendingTxPolicy) Report(evalCtx *security.EvaluationContext) interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	report, ok := p.reports[evalCtx]
+	if !ok {
+		return nil
+	}
+	delete(p.reports, evalCtx)
+	return report
+}
+
+// EOF: internal/security/policies/pending.go
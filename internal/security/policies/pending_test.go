@@ -0,0 +1,99 @@
+package policies_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+// nonceChain reports fixed latest and pending nonces.
+type nonceChain struct {
+	blockchain.Chain
+	latest, pending uint64
+	err             error
+}
+
+func (c *nonceChain) GetTransactionCount(ctx context.Context, address string, block blockchain.BlockNumber) (uint64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	if block == blockchain.BlockNumberPending {
+		return c.pending, nil
+	}
+	return c.latest, nil
+}
+
+func pendingEvalCtx(chain blockchain.Chain, tool string) *security.EvaluationContext {
+	return &security.EvaluationContext{
+		Tool:      tool,
+		ChainName: "ethereum",
+		From:      agent,
+		Session:   &chainSession{mockSession: mockSession{id: "s1"}, chain: chain},
+	}
+}
+
+func TestPendingTxPolicy_MaxPending(t *testing.T) {
+	policy, err := policies.NewPendingTxPolicy(&config.PendingTxConfig{MaxPending: 2}, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+	chain := &nonceChain{latest: 7, pending: 9}
+
+	evalCtx := pendingEvalCtx(chain, "transfer")
+	require.NoError(t, policy.Check(ctx, evalCtx))
+	report := policy.Report(evalCtx).(*policies.PendingTxReport)
+	assert.Equal(t, uint64(2), report.Pending)
+	assert.Equal(t, uint64(7), report.Nonce)
+
+	chain.pending = 10
+	evalCtx = pendingEvalCtx(chain, "transfer")
+	err = policy.Check(ctx, evalCtx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "3 transactions of "+agent+" are pending, more than max_pending 2")
+	assert.Contains(t, err.Error(), "speed it up or cancel it")
+	assert.Equal(t, uint64(3), policy.Report(evalCtx).(*policies.PendingTxReport).Pending)
+
+	// Reads and cancellations of the stuck transaction are not held back.
+	assert.NoError(t, policy.Check(ctx, pendingEvalCtx(chain, "balance")))
+	assert.NoError(t, policy.Check(ctx, pendingEvalCtx(chain, "cancel")))
+
+	chain.err = errors.New("rpc down")
+	assert.ErrorContains(t, policy.Check(ctx, pendingEvalCtx(chain, "send")), "rpc down")
+}
+
+func TestPendingTxPolicy_MaxAge(t *testing.T) {
+	approver := &recordingApprover{}
+	policy, err := policies.NewPendingTxPolicy(&config.PendingTxConfig{MaxAge: 50 * time.Millisecond, OnExceed: "approve"}, approver)
+	require.NoError(t, err)
+	assert.True(t, policy.Prompts())
+	ctx := context.Background()
+	chain := &nonceChain{latest: 7, pending: 8}
+
+	require.NoError(t, policy.Check(ctx, pendingEvalCtx(chain, "transfer")))
+	time.Sleep(80 * time.Millisecond)
+
+	// The same nonce still pending is now too old: a human is asked.
+	approver.err = errors.New("rejected")
+	evalCtx := pendingEvalCtx(chain, "transfer")
+	assert.ErrorContains(t, policy.Check(ctx, evalCtx), "rejected")
+	require.Len(t, approver.reasons, 1)
+	assert.Contains(t, approver.reasons[0], "transaction 7 of "+agent+" has been pending for")
+	assert.Contains(t, approver.reasons[0], "longer than max_age 50ms")
+	policy.Report(evalCtx)
+
+	// Once it is mined, the next one starts its own clock.
+	chain.latest, chain.pending = 8, 9
+	assert.NoError(t, policy.Check(ctx, pendingEvalCtx(chain, "transfer")))
+	assert.Len(t, approver.reasons, 1)
+
+	_, err = policies.NewPendingTxPolicy(&config.PendingTxConfig{MaxPending: 1, OnExceed: "approve"}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+// Package policies provides the counting of approvals for operations that
+// need several humans.
+//
+// File: internal/security/policies/quorum.go
+
+package policies
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrNotApprover is returned by ApprovalQueue.Resolve for a decision by
+// someone who is not an approver of the request.
+var ErrNotApprover = errors.New("not an approver of this request")
+
+// ErrDuplicateApproval is returned by ApprovalQueue.Resolve for a second
+// decision by the same approver.
+var ErrDuplicateApproval = errors.New("approver already decided")
+
+// Quorum is who must approve an operation. The zero Quorum is one
+// decision by anyone allowed, the first one deciding.
+type Quorum struct {
+	// Required is the number of distinct approvals needed (0 = 1).
+	Required int
+	// Approvers are the identities whose decisions count: Telegram user
+	// ids, or names of API approvers (empty = anyone allowed).
+	Approvers []string
+	// Count makes a denial count against the quorum instead of denying:
+	// the operation is denied once too few approvers are left to approve.
+	// Without Approvers, a denial always denies.
+	Count bool
+}
+
+// quorumVotes counts the decisions on one request.
+type quorumVotes struct {
+	required  int
+	approvers map[string]bool // nil = anyone allowed
+	count     bool
+	approvals []string // approvers who approved, in order
+	denials   []string // approvers who denied, in order
+}
+
+func newQuorumVotes(q Quorum) *quorumVotes {
+	v := &quorumVotes{required: q.Required, count: q.Count && len(q.Approvers) > 0}
+	if v.required < 1 {
+		v.required = 1
+	}
+	if len(q.Approvers) > 0 {
+		v.approvers = make(map[string]bool, len(q.Approvers))
+		for _, a := range q.Approvers {
+			v.approvers[a] = true
+		}
+	}
+	return v
+}
+
+// vote records the decision of by and reports whether it settles the
+// request, and how.
+func (v *quorumVotes) vote(by string, approve bool) (done, approved bool, err error) {
+	if v.approvers != nil && !v.approvers[by] {
+		return false, false, ErrNotApprover
+	}
+	if slices.Contains(v.approvals, by) || slices.Contains(v.denials, by) {
+		return false, false, ErrDuplicateApproval
+	}
+	if approve {
+		v.approvals = append(v.approvals, by)
+		return len(v.approvals) >= v.required, true, nil
+	}
+	v.denials = append(v.denials, by)
+	if !v.count {
+		return true, false, nil
+	}
+	left := len(v.approvers) - len(v.approvals) - len(v.denials)
+	return len(v.approvals)+left < v.required, false, nil
+}
+
+// multi reports whether the request may take more than one decision.
+func (v *quorumVotes) multi() bool { return v.required > 1 || v.count }
+
+// tally describes the approvals so far, e.g. "1/2".
+func (v *quorumVotes) tally() string {
+	return fmt.Sprintf("%d/%d", len(v.approvals), v.required)
+}
+
+// record returns the votes for an audit entry.
+func (v *quorumVotes) record() map[string]interface{} {
+	return map[string]interface{}{
+		"approvals_required": v.required,
+		"approvals":          append([]string{}, v.approvals...),
+		"denials":            append([]string{}, v.denials...),
+	}
+}
+
+// EOF: internal/security/policies/quorum.go
@@ -0,0 +1,216 @@
+// Package policies provides a policy capping the number of transactions
+// per time window.
+//
+// File: internal/security/policies/rate.go
+
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// RatePolicy allows at most maxTx write operations per signing address in
+// any sliding window of the configured length, so a runaway agent loop is
+// stopped after a handful of transactions. Read‑only tools are never
+// counted.
+//
+// An operation counts from the moment Check allows it, whether the tool
+// then succeeds or fails. Only an operation denied by a later policy,
+// which never ran, gives its slot back.
+type RatePolicy struct {
+	mu       sync.Mutex
+	maxTx    int
+	window   time.Duration
+	sent     map[string][]time.Time // address -> times in window, oldest first
+	state    *LimitState            // nil = times are kept in memory only
+	reserved map[*security.EvaluationContext]rateReservation
+}
+
+// rateReservation is a slot taken by Check and not settled yet.
+type rateReservation struct {
+	account string
+	at      time.Time
+}
+
+// rateState is the on‑disk form of the transaction times: per signing
+// address ("" for unknown signers), the times in the window.
+type rateState struct {
+	Accounts map[string][]time.Time `json:"accounts"`
+}
+
+// NewRatePolicy creates a policy allowing maxTx write operations per
+// window.
+func NewRatePolicy(maxTx int, window time.Duration) *RatePolicy {
+	return &RatePolicy{
+		maxTx:    maxTx,
+		window:   window,
+		sent:     make(map[string][]time.Time),
+		reserved: make(map[*security.EvaluationContext]rateReservation),
+	}
+}
+
+// NewRatePolicyWithState is NewRatePolicy keeping the transaction times in
+// state.Path, like NewLimitPolicyWithState, so that they survive restarts.
+func NewRatePolicyWithState(maxTx int, window time.Duration, state LimitState) (*RatePolicy, error) {
+	p := NewRatePolicy(maxTx, window)
+	p.state = &state
+	if err := p.loadState(); err != nil {
+		if state.FailClosed {
+			return nil, fmt.Errorf("rate limit: %w", err)
+		}
+		p.state.log("rate limit state unreadable; transaction count starts from zero", err)
+	}
+	return p, nil
+}
+
+// Name returns "rate_limit", the policy's name in decisions and audit entries.
+func (p *RatePolicy) Name() string { return "rate_limit" }
+
+// Check implements security.Policy.
+func (p *RatePolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	if !security.IsWriteTool(evalCtx.Tool) {
+		return nil
+	}
+	account := rateAccount(evalCtx.Signer())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now().UTC()
+	times := p.prune(account, now)
+	if len(times) >= p.maxTx {
+		wait := times[len(times)-p.maxTx].Add(p.window).Sub(now)
+		return &security.ErrLimitExceeded{
+			Name: "rate_limit", Unit: "transactions", Account: account,
+			Limit: big.NewInt(int64(p.maxTx)), Attempted: big.NewInt(1), Spent: big.NewInt(int64(len(times))),
+			Reason: fmt.Sprintf("rate limit exceeded for %s: %d transactions in the last %s, next allowed in %s",
+				accountLabel(account), len(times), p.window, roundWait(wait)),
+		}
+	}
+	p.sent[account] = append(times, now)
+	if err := p.saveState(); err != nil {
+		if p.state.FailClosed {
+			p.sent[account] = times
+			return fmt.Errorf("rate limit: %w", err)
+		}
+		p.state.log("rate limit state not saved; a restart would forget this transaction", err)
+	}
+	p.reserved[evalCtx] = rateReservation{account: account, at: now}
+	return nil
+}
+
+// Settle implements security.Settler. It gives the slot taken for evalCtx
+// back if a later policy denied the operation.
+func (p *RatePolicy) Settle(ctx context.Context, evalCtx *security.EvaluationContext, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r, ok := p.reserved[evalCtx]
+	if !ok {
+		return
+	}
+	delete(p.reserved, evalCtx)
+	var denied *security.ErrPolicyDenied
+	if !errors.As(err, &denied) {
+		return
+	}
+	times := p.sent[r.account]
+	for i, t := range times {
+		if t.Equal(r.at) {
+			p.sent[r.account] = append(times[:i:i], times[i+1:]...)
+			break
+		}
+	}
+	if err := p.saveState(); err != nil {
+		p.state.log("rate limit state not saved; a restart would count a denied transaction", err)
+	}
+}
+
+// Usage returns how many write operations address has made in the current
+// window and how many it may make, e.g. for a status page showing
+// "7/10 used". An empty address is the budget of unknown signers.
+func (p *RatePolicy) Usage(address string) (used, limit int) {
+	account := rateAccount(address)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cutoff := time.Now().UTC().Add(-p.window)
+	for _, t := range p.sent[account] {
+		if t.After(cutoff) {
+			used++
+		}
+	}
+	return used, p.maxTx
+}
+
+// prune drops the times of account that have left the window and returns
+// the rest; p.mu must be held.
+func (p *RatePolicy) prune(account string, now time.Time) []time.Time {
+	times := p.sent[account]
+	cutoff := now.Add(-p.window)
+	i := sort.Search(len(times), func(i int) bool { return times[i].After(cutoff) })
+	times = times[i:]
+	if len(times) == 0 {
+		delete(p.sent, account)
+		return nil
+	}
+	p.sent[account] = times
+	return times
+}
+
+// loadState reads the saved transaction times, if any.
+func (p *RatePolicy) loadState() error {
+	var state rateState
+	found, err := readStateFile(p.state.Path, &state)
+	if err != nil {
+		return err
+	}
+	if !found {
+		p.state.warn("no rate limit state yet; transaction count starts from zero")
+		return nil
+	}
+	for account, times := range state.Accounts {
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		p.sent[account] = times
+	}
+	return nil
+}
+
+// saveState writes the transaction times atomically and durably; p.mu
+// must be held.
+func (p *RatePolicy) saveState() error {
+	if p.state == nil {
+		return nil
+	}
+	state := rateState{Accounts: make(map[string][]time.Time, len(p.sent))}
+	for account, times := range p.sent {
+		state.Accounts[account] = times
+	}
+	return writeStateFile(p.state.Path, state)
+}
+
+// rateAccount keys an address like LimitPolicy's daily budgets.
+func rateAccount(address string) string {
+	if address == "" {
+		return ""
+	}
+	return common.HexToAddress(address).Hex()
+}
+
+// roundWait rounds a wait for errors: to the second, or to the millisecond
+// below a second.
+func roundWait(wait time.Duration) time.Duration {
+	if wait >= time.Second {
+		return wait.Round(time.Second)
+	}
+	return wait.Round(time.Millisecond)
+}
+
+// EOF: internal/security/policies/rate.go
@@ -0,0 +1,120 @@
+package policies_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+func rateEvalCtx(tool, from string) *security.EvaluationContext {
+	return &security.EvaluationContext{Tool: tool, Session: &mockSession{id: "s1"}, From: from}
+}
+
+func TestRatePolicy_Window(t *testing.T) {
+	policy := policies.NewRatePolicy(3, 300*time.Millisecond)
+	ctx := context.Background()
+
+	for _, tool := range []string{"transfer", "deploy", "sign_message"} {
+		require.NoError(t, policy.Check(ctx, rateEvalCtx(tool, agent)))
+	}
+	err := policy.Check(ctx, rateEvalCtx("send", agent))
+	assert.ErrorContains(t, err, "rate limit exceeded for "+agent+": 3 transactions in the last 300ms, next allowed in")
+	var exceeded *security.ErrLimitExceeded
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, "rate_limit", exceeded.Name)
+	assert.Equal(t, []int64{3, 3, 1}, []int64{exceeded.Limit.Int64(), exceeded.Spent.Int64(), exceeded.Attempted.Int64()})
+	used, limit := policy.Usage(agent)
+	assert.Equal(t, []int{3, 3}, []int{used, limit})
+
+	// Reads are never counted, and each wallet has its own window.
+	assert.NoError(t, policy.Check(ctx, rateEvalCtx("balance", agent)))
+	assert.NoError(t, policy.Check(ctx, rateEvalCtx("transfer", owner)))
+
+	time.Sleep(350 * time.Millisecond)
+	used, _ = policy.Usage(agent)
+	assert.Zero(t, used)
+	assert.NoError(t, policy.Check(ctx, rateEvalCtx("transfer", agent)))
+}
+
+func TestRatePolicy_Concurrent(t *testing.T) {
+	policy := policies.NewRatePolicy(10, time.Hour)
+	ctx := context.Background()
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				if g%2 == 1 {
+					// Reads and status queries race with the writes.
+					assert.NoError(t, policy.Check(ctx, rateEvalCtx("balance", agent)))
+					policy.Usage(agent)
+					continue
+				}
+				evalCtx := rateEvalCtx("send", agent)
+				if policy.Check(ctx, evalCtx) == nil {
+					allowed.Add(1)
+					policy.Settle(ctx, evalCtx, nil)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.EqualValues(t, 10, allowed.Load())
+	used, _ := policy.Usage(agent)
+	assert.Equal(t, 10, used)
+}
+
+func TestRatePolicy_Settle(t *testing.T) {
+	policy := policies.NewRatePolicy(1, time.Hour)
+	ctx := context.Background()
+
+	// An operation denied by a later policy never ran and is not counted.
+	enforcer := security.NewEnforcer()
+	enforcer.AddPolicy(policy)
+	enforcer.AddPolicy(denyPolicy{})
+	assert.ErrorContains(t, enforcer.Evaluate(ctx, rateEvalCtx("send", agent)), "denied")
+	used, _ := policy.Usage(agent)
+	assert.Zero(t, used)
+
+	// A failed one ran, and is.
+	evalCtx := rateEvalCtx("send", agent)
+	require.NoError(t, policy.Check(ctx, evalCtx))
+	policy.Settle(ctx, evalCtx, errors.New("rpc down"))
+	assert.ErrorContains(t, policy.Check(ctx, rateEvalCtx("send", agent)), "rate limit exceeded")
+}
+
+func TestRatePolicy_StateSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rate.json")
+	ctx := context.Background()
+
+	logger := &errorLogger{}
+	policy, err := policies.NewRatePolicyWithState(2, time.Hour, policies.LimitState{Path: path, Logger: logger})
+	require.NoError(t, err)
+	assert.Len(t, logger.warnings, 1, "a missing file is logged")
+	require.NoError(t, policy.Check(ctx, rateEvalCtx("transfer", agent)))
+	require.NoError(t, policy.Check(ctx, rateEvalCtx("transfer", owner)))
+
+	restarted, err := policies.NewRatePolicyWithState(2, time.Hour, policies.LimitState{Path: path})
+	require.NoError(t, err)
+	used, _ := restarted.Usage(agent)
+	assert.Equal(t, 1, used)
+	require.NoError(t, restarted.Check(ctx, rateEvalCtx("transfer", agent)))
+	assert.ErrorContains(t, restarted.Check(ctx, rateEvalCtx("transfer", agent)), "2 transactions in the last 1h0m0s")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"accounts":{"":["yesterday"]}}`), 0600))
+	_, err = policies.NewRatePolicyWithState(2, time.Hour, policies.LimitState{Path: path, FailClosed: true})
+	assert.ErrorContains(t, err, "parse state")
+}
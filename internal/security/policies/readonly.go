@@ -19,17 +19,12 @@ func NewReadOnlyPolicy() *ReadOnlyPolicy {
 	return &ReadOnlyPolicy{}
 }
 
+// Name returns "read_only", the policy's name in decisions and audit entries.
+func (p *ReadOnlyPolicy) Name() string { return "read_only" }
+
 // Check implements security.Policy.
 func (p *ReadOnlyPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
-	// List of tools that perform writes.
-	writeTools := map[string]bool{
-		"transfer": true,
-		"send":     true,
-		"swap":     true,
-		"deploy":   true,
-		"approve":  true,
-	}
-	if writeTools[evalCtx.Tool] {
+	if security.IsWriteTool(evalCtx.Tool) {
 		return errors.New("read‑only mode: write operations are disabled")
 	}
 	return nil
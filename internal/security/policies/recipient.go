@@ -0,0 +1,416 @@
+// Package policies provides cumulative spending limits per recipient.
+//
+// File: internal/security/policies/recipient.go
+
+package policies
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// defaultRecipientWindow is the window of recipient limits unless
+// configured.
+const defaultRecipientWindow = 7 * 24 * time.Hour
+
+// RecipientLimitPolicy caps the total sent to any one address within a
+// window, such as 1 ETH in seven days, whichever account signs: an agent
+// draining the wallet in transfers each under the per‑transaction limit
+// is stopped once the total to its address reaches the cap.
+//
+// The native currency sent counts against the transaction's recipient,
+// and the amounts of limited ERC‑20 tokens against the recipient of a
+// transfer or transferFrom, or the spender of an approve or
+// increaseAllowance, decoded as by TokenLimitPolicy. Fees are not counted,
+// since the recipient does not get them. The totals are kept per chain.
+// Like LimitPolicy's, they are reserved by Check and given back by Settle
+// if the tool fails.
+type RecipientLimitPolicy struct {
+	mu        sync.Mutex
+	max       *big.Int                   // wei per recipient (nil = not limited)
+	overrides map[string]*big.Int        // checksummed recipient -> wei cap
+	tokens    map[string]*recipientToken // checksummed token address -> caps
+	window    time.Duration
+	spent     map[recipientKey]*big.Int  // total in the current window
+	reset     map[recipientKey]time.Time // window start
+	state     *LimitState                // nil = totals are kept in memory only
+	reserved  map[*security.EvaluationContext][]recipientReservation
+}
+
+// recipientKey is a recipient's total of one asset on one chain:
+// nativeAsset, or a checksummed token address. Amounts in the native
+// currencies of different chains are never added up.
+type recipientKey struct {
+	chain     string
+	asset     string
+	recipient string // checksummed
+}
+
+// recipientToken is the configured caps of one token.
+type recipientToken struct {
+	tokenDecimals
+	cfg       config.RecipientTokenLimit
+	overrides map[string]*config.TokenAmount // checksummed recipient -> cap
+	max       *big.Int                       // base units, once decimals are known
+	maxFor    map[string]*big.Int            // overrides in base units, likewise
+}
+
+// recipientSpend is an amount an operation sends to a recipient, with the
+// recipient's cap.
+type recipientSpend struct {
+	key    recipientKey
+	amount *big.Int
+	limit  *big.Int
+	token  *recipientToken // nil for the native currency
+}
+
+// recipientReservation is a spend counted by Check and not settled yet.
+type recipientReservation struct {
+	key    recipientKey
+	amount *big.Int
+	window time.Time // start of the window the spend was counted in
+}
+
+// NewRecipientLimitPolicy creates a policy from configuration, keeping the
+// totals in memory.
+func NewRecipientLimitPolicy(cfg *config.RecipientLimitConfig) (*RecipientLimitPolicy, error) {
+	p := &RecipientLimitPolicy{
+		overrides: make(map[string]*big.Int),
+		tokens:    make(map[string]*recipientToken, len(cfg.Tokens)),
+		window:    cfg.Window,
+		spent:     make(map[recipientKey]*big.Int),
+		reset:     make(map[recipientKey]time.Time),
+		reserved:  make(map[*security.EvaluationContext][]recipientReservation),
+	}
+	if p.window <= 0 {
+		p.window = defaultRecipientWindow
+	}
+	if cfg.MaxPerRecipient.IsUSD() {
+		return nil, fmt.Errorf("recipient limits: max_per_recipient must be in the native currency")
+	}
+	if cfg.MaxPerRecipient != nil {
+		p.max = new(big.Int).Set(cfg.MaxPerRecipient.Wei)
+	}
+	for addr, limitCfg := range cfg.Tokens {
+		token, err := evm.NormalizeAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("recipient limits: %w", err)
+		}
+		if limitCfg == nil || limitCfg.MaxPerRecipient == nil {
+			return nil, fmt.Errorf("recipient limits: token %s: missing max_per_recipient", token)
+		}
+		p.tokens[token] = &recipientToken{cfg: *limitCfg, overrides: make(map[string]*config.TokenAmount)}
+	}
+	for addr, override := range cfg.Overrides {
+		recipient, err := evm.NormalizeAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("recipient limits: override: %w", err)
+		}
+		if override == nil {
+			continue
+		}
+		if override.MaxPerRecipient.IsUSD() {
+			return nil, fmt.Errorf("recipient limits: override %s: max_per_recipient must be in the native currency", recipient)
+		}
+		if override.MaxPerRecipient != nil {
+			p.overrides[recipient] = new(big.Int).Set(override.MaxPerRecipient.Wei)
+		}
+		for tokenAddr, amount := range override.Tokens {
+			token, err := evm.NormalizeAddress(tokenAddr)
+			if err != nil {
+				return nil, fmt.Errorf("recipient limits: override %s: %w", recipient, err)
+			}
+			t, ok := p.tokens[token]
+			if !ok {
+				return nil, fmt.Errorf("recipient limits: override %s: token %s has no limit", recipient, token)
+			}
+			t.overrides[recipient] = amount
+		}
+	}
+	for token, t := range p.tokens {
+		var err error
+		if t.tokenDecimals, err = newTokenDecimals(t.cfg.Decimals, t.cfg.MaxPerRecipient.Symbol, t.convert); err != nil {
+			return nil, fmt.Errorf("recipient limits: token %s: %w", token, err)
+		}
+	}
+	return p, nil
+}
+
+// NewRecipientLimitPolicyWithState is NewRecipientLimitPolicy keeping the
+// totals in state.Path, like NewLimitPolicyWithState, so that a restart
+// does not reset the windows.
+func NewRecipientLimitPolicyWithState(cfg *config.RecipientLimitConfig, state LimitState) (*RecipientLimitPolicy, error) {
+	p, err := NewRecipientLimitPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.state = &state
+	if err := p.loadState(); err != nil {
+		if state.FailClosed {
+			return nil, fmt.Errorf("recipient limits: %w", err)
+		}
+		p.state.log("recipient limit state unreadable; totals start from zero", err)
+	}
+	return p, nil
+}
+
+// convert converts the token's caps to base units.
+func (t *recipientToken) convert(decimals int) error {
+	max, err := t.cfg.MaxPerRecipient.Units(decimals)
+	if err != nil {
+		return err
+	}
+	maxFor := make(map[string]*big.Int, len(t.overrides))
+	for recipient, amount := range t.overrides {
+		if maxFor[recipient], err = amount.Units(decimals); err != nil {
+			return fmt.Errorf("override %s: %w", recipient, err)
+		}
+	}
+	t.max, t.maxFor = max, maxFor
+	return nil
+}
+
+// Name returns "recipient_limits", the policy's name in decisions and audit entries.
+func (p *RecipientLimitPolicy) Name() string { return "recipient_limits" }
+
+// Check implements security.Policy.
+func (p *RecipientLimitPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	if !budgetTools[evalCtx.Tool] {
+		return nil
+	}
+	spends, err := p.spends(ctx, evalCtx)
+	if err != nil || len(spends) == 0 {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now().UTC()
+	reserved := make([]recipientReservation, 0, len(spends))
+	for _, s := range spends {
+		start, ok := p.reset[s.key]
+		if !ok || now.Sub(start) > p.window {
+			p.spent[s.key] = new(big.Int)
+			p.reset[s.key] = now
+		}
+		spent := p.spent[s.key]
+		newSpent := new(big.Int).Add(spent, s.amount)
+		if newSpent.Cmp(s.limit) > 0 {
+			p.release(reserved)
+			return p.exceeded(s, spent)
+		}
+		p.spent[s.key] = newSpent
+		reserved = append(reserved, recipientReservation{key: s.key, amount: s.amount, window: p.reset[s.key]})
+	}
+	if err := p.saveState(); err != nil {
+		if p.state.FailClosed {
+			p.release(reserved)
+			return fmt.Errorf("recipient limits: %w", err)
+		}
+		p.state.log("recipient limit state not saved; a restart would forget this spend", err)
+	}
+	p.reserved[evalCtx] = reserved
+	return nil
+}
+
+// spends returns what the operation sends to recipients with a cap.
+func (p *RecipientLimitPolicy) spends(ctx context.Context, evalCtx *security.EvaluationContext) ([]recipientSpend, error) {
+	var spends []recipientSpend
+	amount, _ := evalCtx.Args["amount"].(*big.Int)
+	to, _ := evalCtx.Args["to"].(string)
+	if amount != nil && amount.Sign() > 0 && to != "" && (p.max != nil || len(p.overrides) > 0) {
+		dest, err := targetDestination(ctx, evalCtx, to)
+		if err != nil {
+			return nil, err
+		}
+		recipient := dest.addr.Hex()
+		limit, ok := p.overrides[recipient]
+		if !ok {
+			limit = p.max
+		}
+		if limit != nil {
+			spends = append(spends, recipientSpend{key: recipientKey{evalCtx.ChainName, nativeAsset, recipient}, amount: amount, limit: limit})
+		}
+	}
+	if len(p.tokens) == 0 {
+		return spends, nil
+	}
+
+	call, err := decodeTokenCall(ctx, evalCtx)
+	if err != nil || call == nil {
+		return spends, err
+	}
+	t, ok := p.tokens[call.token]
+	if !ok {
+		return spends, nil
+	}
+	var recipientAddr common.Address
+	switch call.method {
+	case "transfer", "transferFrom":
+		recipientAddr, _ = call.args["to"].(common.Address)
+	case "approve", "increaseAllowance":
+		recipientAddr, _ = call.args["spender"].(common.Address)
+	}
+	recipient := recipientAddr.Hex()
+	if signer := evalCtx.Signer(); call.method == "transferFrom" && signer != "" && common.HexToAddress(signer).Hex() == recipient {
+		return spends, nil // tokens come to the signer
+	}
+	tokenAmount, _ := call.args["amount"].(*big.Int)
+	if tokenAmount == nil || tokenAmount.Sign() == 0 {
+		return spends, nil
+	}
+	if err := t.resolveDecimals(ctx, evalCtx, call.token, &p.mu); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	limit, ok := t.maxFor[recipient]
+	if !ok {
+		limit = t.max
+	}
+	p.mu.Unlock()
+	return append(spends, recipientSpend{key: recipientKey{evalCtx.ChainName, call.token, recipient}, amount: tokenAmount, limit: limit, token: t}), nil
+}
+
+// exceeded returns the denial of s, over its cap with spent already sent;
+// p.mu must be held.
+func (p *RecipientLimitPolicy) exceeded(s recipientSpend, spent *big.Int) error {
+	err := &security.ErrLimitExceeded{
+		Name: "max_per_recipient", Unit: "wei", Recipient: s.key.recipient,
+		Limit: s.limit, Attempted: s.amount, Spent: new(big.Int).Set(spent),
+	}
+	if s.token == nil {
+		err.Reason = fmt.Sprintf("recipient limit exceeded for %s: limit %s per %s, already sent %s, attempted +%s",
+			s.key.recipient, s.limit, p.window, spent, s.amount)
+		return err
+	}
+	err.Unit, err.Token = "token", s.key.asset
+	err.Reason = fmt.Sprintf("token %s: recipient limit exceeded for %s: limit %s per %s, already sent %s, attempted +%s",
+		s.key.asset, s.key.recipient, s.token.format(s.limit), p.window, s.token.format(spent), s.token.format(s.amount))
+	return err
+}
+
+// release gives reserved back; p.mu must be held.
+func (p *RecipientLimitPolicy) release(reserved []recipientReservation) {
+	for _, r := range reserved {
+		if !p.reset[r.key].Equal(r.window) {
+			continue // counted in a window since reset
+		}
+		spent := new(big.Int).Sub(p.spent[r.key], r.amount)
+		if spent.Sign() < 0 {
+			spent.SetInt64(0)
+		}
+		p.spent[r.key] = spent
+	}
+}
+
+// Settle implements security.Settler. It keeps the totals reserved for
+// evalCtx if err is nil and gives them back otherwise.
+func (p *RecipientLimitPolicy) Settle(ctx context.Context, evalCtx *security.EvaluationContext, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	reserved, ok := p.reserved[evalCtx]
+	if !ok {
+		return
+	}
+	delete(p.reserved, evalCtx)
+	if err == nil {
+		return
+	}
+	p.release(reserved)
+	if err := p.saveState(); err != nil {
+		p.state.log("recipient limit state not saved; a restart would count a failed spend", err)
+	}
+}
+
+// Total returns what was sent to recipient on the named chain in the
+// current window, in wei, or in base units of token if given.
+func (p *RecipientLimitPolicy) Total(chain, recipient, token string) *big.Int {
+	key := recipientKey{chain: chain, asset: nativeAsset, recipient: common.HexToAddress(recipient).Hex()}
+	if token != "" {
+		key.asset = common.HexToAddress(token).Hex()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	spent, ok := p.spent[key]
+	if !ok || time.Since(p.reset[key]) > p.window {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(spent)
+}
+
+// recipientState is the on‑disk form of the totals: per chain and asset
+// (the spendKey of the chain and nativeAsset or a token address) and
+// recipient, the amount sent and the start of the window. A key without a
+// chain, as saved before totals were kept per chain, is the default
+// chain's.
+type recipientState struct {
+	Assets map[string]map[string]limitEntry `json:"assets"`
+}
+
+// loadState reads the saved totals, if any.
+func (p *RecipientLimitPolicy) loadState() error {
+	var state recipientState
+	found, err := readStateFile(p.state.Path, &state)
+	if err != nil {
+		return err
+	}
+	if !found {
+		p.state.warn("no recipient limit state yet; totals start from zero")
+		return nil
+	}
+	spent := make(map[recipientKey]*big.Int)
+	reset := make(map[recipientKey]time.Time)
+	for chainAsset, recipients := range state.Assets {
+		chain, asset, ok := strings.Cut(chainAsset, "/")
+		if !ok {
+			chain, asset = "", chainAsset
+		}
+		for recipient, entry := range recipients {
+			v, ok := new(big.Int).SetString(entry.Spent, 10)
+			if !ok || v.Sign() < 0 {
+				return fmt.Errorf("parse state %s: %s to %s: invalid total %q", p.state.Path, chainAsset, recipient, entry.Spent)
+			}
+			key := recipientKey{chain, asset, recipient}
+			spent[key], reset[key] = v, entry.WindowStart
+		}
+	}
+	for key, v := range spent {
+		p.spent[key] = v
+		p.reset[key] = reset[key]
+	}
+	return nil
+}
+
+// saveState writes the totals of windows not yet over atomically and
+// durably; p.mu must be held.
+func (p *RecipientLimitPolicy) saveState() error {
+	if p.state == nil {
+		return nil
+	}
+	now := time.Now().UTC()
+	state := recipientState{Assets: make(map[string]map[string]limitEntry)}
+	for key, spent := range p.spent {
+		start := p.reset[key]
+		if now.Sub(start) > p.window {
+			continue
+		}
+		chainAsset := spendKey(key.chain, key.asset)
+		if state.Assets[chainAsset] == nil {
+			state.Assets[chainAsset] = make(map[string]limitEntry)
+		}
+		state.Assets[chainAsset][key.recipient] = limitEntry{Spent: spent.String(), WindowStart: start}
+	}
+	return writeStateFile(p.state.Path, state)
+}
+
+// EOF: internal/security/policies/recipient.go
@@ -0,0 +1,146 @@
+package policies_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+// payTo returns a transfer of wei to to, signed by from.
+func payTo(from, to string, wei *big.Int) *security.EvaluationContext {
+	return &security.EvaluationContext{
+		Tool:    "transfer",
+		Args:    map[string]interface{}{"to": to, "amount": wei},
+		Session: &mockSession{id: "s1"},
+		From:    from,
+	}
+}
+
+func TestRecipientLimitPolicy_Native(t *testing.T) {
+	ctx := context.Background()
+	policy, err := policies.NewRecipientLimitPolicy(&config.RecipientLimitConfig{
+		MaxPerRecipient: config.MustParseAmount("1 eth"),
+		Overrides: map[string]*config.RecipientOverride{
+			strings.ToLower(owner): {MaxPerRecipient: config.MustParseAmount("2 eth")},
+		},
+	})
+	require.NoError(t, err)
+
+	// Spends to one recipient add up, whoever signs and however the
+	// address is written.
+	require.NoError(t, policy.Check(ctx, payTo(agent, recipient, eth(600))))
+	err = policy.Check(ctx, payTo(owner, strings.ToLower(recipient), eth(500)))
+	var limitErr *security.ErrLimitExceeded
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, "max_per_recipient", limitErr.Name)
+	assert.Equal(t, recipient, limitErr.Recipient)
+	assert.Equal(t, eth(600), limitErr.Spent)
+	assert.Equal(t, eth(600), policy.Total("", recipient, ""))
+	require.NoError(t, policy.Check(ctx, payTo(owner, recipient, eth(400))))
+
+	// Other recipients have their own totals, and overrides their own caps.
+	require.NoError(t, policy.Check(ctx, payTo(owner, agent, eth(1000))))
+	require.NoError(t, policy.Check(ctx, payTo(agent, owner, eth(1500))))
+	assert.Error(t, policy.Check(ctx, payTo(agent, owner, eth(600))))
+
+	// A failed spend is given back.
+	failed := payTo(agent, owner, eth(500))
+	require.NoError(t, policy.Check(ctx, failed))
+	policy.Settle(ctx, failed, errors.New("reverted"))
+	assert.Equal(t, eth(1500), policy.Total("", owner, ""))
+}
+
+func TestRecipientLimitPolicy_Tokens(t *testing.T) {
+	ctx := context.Background()
+	decimals := uint8(6)
+	policy, err := policies.NewRecipientLimitPolicy(&config.RecipientLimitConfig{
+		Tokens: map[string]*config.RecipientTokenLimit{
+			strings.ToLower(token): {MaxPerRecipient: tokens(t, "100 usdc"), Decimals: &decimals},
+		},
+		Overrides: map[string]*config.RecipientOverride{
+			owner: {Tokens: map[string]*config.TokenAmount{token: tokens(t, "1000 usdc")}},
+		},
+	})
+	require.NoError(t, err)
+	send := func(method string, args ...interface{}) error {
+		return policy.Check(ctx, tokenEvalCtx(nil, token, call(t, method, args...)))
+	}
+
+	// Transfers and approvals count against the recipient or spender.
+	require.NoError(t, send("transfer", common.HexToAddress(vault), usdc(60)))
+	require.NoError(t, send("approve", common.HexToAddress(vault), usdc(30)))
+	err = send("increaseAllowance", common.HexToAddress(vault), usdc(20))
+	var limitErr *security.ErrLimitExceeded
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, "token", limitErr.Unit)
+	assert.Equal(t, token, limitErr.Token)
+	assert.Equal(t, vault, limitErr.Recipient)
+	assert.ErrorContains(t, err, "already sent 90 usdc")
+	assert.Equal(t, usdc(90), policy.Total("", vault, token))
+
+	// transferFrom counts against its recipient, not the owner; tokens
+	// pulled to the signer are not counted.
+	require.NoError(t, send("transferFrom", common.HexToAddress(vault), common.HexToAddress(owner), usdc(500)))
+	require.NoError(t, send("transferFrom", common.HexToAddress(vault), common.HexToAddress(agent), usdc(5000)))
+	assert.Equal(t, usdc(500), policy.Total("", owner, token))
+	assert.Equal(t, usdc(0), policy.Total("", agent, token))
+
+	// Tokens without a limit are not counted.
+	require.NoError(t, policy.Check(ctx, tokenEvalCtx(nil, dai, call(t, "transfer", common.HexToAddress(vault), usdc(5000)))))
+}
+
+func TestRecipientLimitPolicy_StateSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "recipients.json")
+	cfg := &config.RecipientLimitConfig{MaxPerRecipient: config.MustParseAmount("1 eth")}
+
+	logger := &errorLogger{}
+	policy, err := policies.NewRecipientLimitPolicyWithState(cfg, policies.LimitState{Path: path, Logger: logger})
+	require.NoError(t, err)
+	assert.Len(t, logger.warnings, 1, "a missing file is logged")
+	require.NoError(t, policy.Check(ctx, payTo(agent, recipient, eth(700))))
+
+	restarted, err := policies.NewRecipientLimitPolicyWithState(cfg, policies.LimitState{Path: path})
+	require.NoError(t, err)
+	assert.Equal(t, eth(700), restarted.Total("", recipient, ""))
+	assert.ErrorContains(t, restarted.Check(ctx, payTo(agent, recipient, eth(400))), "already sent 700000000000000000")
+}
+
+func TestRecipientLimitPolicy_PerChain(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "recipients.json")
+	cfg := &config.RecipientLimitConfig{MaxPerRecipient: config.MustParseAmount("1 eth")}
+	payOn := func(chain string, wei *big.Int) *security.EvaluationContext {
+		evalCtx := payTo(agent, recipient, wei)
+		evalCtx.ChainName = chain
+		return evalCtx
+	}
+
+	// The same recipient on another chain has its own total: ETH and
+	// MATIC are not added up.
+	policy, err := policies.NewRecipientLimitPolicyWithState(cfg, policies.LimitState{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, policy.Check(ctx, payOn("ethereum", eth(700))))
+	require.NoError(t, policy.Check(ctx, payOn("polygon", eth(700))))
+	assert.ErrorContains(t, policy.Check(ctx, payOn("ethereum", eth(400))), "already sent 700000000000000000")
+	assert.Equal(t, eth(700), policy.Total("polygon", recipient, ""))
+
+	// So does the saved state.
+	restarted, err := policies.NewRecipientLimitPolicyWithState(cfg, policies.LimitState{Path: path})
+	require.NoError(t, err)
+	assert.Equal(t, eth(700), restarted.Total("ethereum", recipient, ""))
+	assert.Equal(t, eth(700), restarted.Total("polygon", recipient, ""))
+	assert.Equal(t, eth(0), restarted.Total("", recipient, ""))
+	require.NoError(t, restarted.Check(ctx, payOn("gnosis", eth(1000))))
+}
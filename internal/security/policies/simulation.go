@@ -0,0 +1,303 @@
+// Package policies provides the transaction simulation policy.
+//
+// File: internal/security/policies/simulation.go
+
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/sdk/types/units"
+)
+
+// simulatedTools are the tools whose transaction the signing wallet sends
+// itself, so that simulating it from the wallet shows what it would do.
+var simulatedTools = map[string]bool{
+	"transfer": true,
+	"send":     true,
+	"sign":     true,
+	"send_raw": true,
+	"deploy":   true,
+}
+
+// SimulationPolicy simulates each transaction against the latest state
+// before it is approved and denies it if it would revert. When the chain
+// traces the simulation, it also denies transactions that would make the
+// signing wallet send more native currency or tokens than allowed, counting
+// every transfer out of the wallet, or that would call a contract not
+// allowed, directly or internally.
+//
+// The outcome is shared through the evaluation context, reported with the
+// policy's decision (see SimulationReport) and recorded in the audit log
+// when the policy allows.
+type SimulationPolicy struct {
+	maxNative       *big.Int
+	maxTokens       map[common.Address]*config.TokenAmount
+	contracts       map[common.Address]bool // empty = any
+	denyUnsupported bool
+
+	mu       sync.Mutex
+	decimals map[common.Address]int // of tokens in maxTokens, once read
+	audit    *observe.AuditLogger
+}
+
+// SimulationReport summarizes a simulation in the policy's decision.
+type SimulationReport struct {
+	Reverted     bool   `json:"reverted"`
+	RevertReason string `json:"revert_reason,omitempty"`
+	Traced       bool   `json:"traced"`
+	// Deltas are the changes of the signing wallet's balances in base
+	// units, by asset: "native" or a token address. Fees are not included.
+	Deltas    map[string]string `json:"deltas,omitempty"`
+	Contracts []string          `json:"contracts,omitempty"`
+}
+
+// nativeAsset keys native currency in SimulationReport.Deltas and the
+// recipient limit state.
+const nativeAsset = "native"
+
+// NewSimulationPolicy creates a policy from cfg.
+func NewSimulationPolicy(cfg *config.SimulationConfig) (*SimulationPolicy, error) {
+	p := &SimulationPolicy{
+		maxTokens: make(map[common.Address]*config.TokenAmount, len(cfg.MaxTokenOutflow)),
+		contracts: make(map[common.Address]bool, len(cfg.AllowedContracts)),
+		decimals:  make(map[common.Address]int),
+	}
+	if a := cfg.MaxNativeOutflow; a != nil {
+		if a.IsUSD() {
+			return nil, errors.New("simulation: max_native_outflow: amounts in usd are not supported")
+		}
+		p.maxNative = a.Wei
+	}
+	for addr, amount := range cfg.MaxTokenOutflow {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("simulation: max_token_outflow: invalid address %q", addr)
+		}
+		p.maxTokens[common.HexToAddress(addr)] = amount
+	}
+	for _, addr := range cfg.AllowedContracts {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("simulation: allowed_contracts: invalid address %q", addr)
+		}
+		p.contracts[common.HexToAddress(addr)] = true
+	}
+	switch cfg.OnUnsupported {
+	case "", "skip":
+	case "deny":
+		p.denyUnsupported = true
+	default:
+		return nil, fmt.Errorf("simulation: invalid on_unsupported %q", cfg.OnUnsupported)
+	}
+	return p, nil
+}
+
+// SetAudit sets the log that records the simulations of allowed
+// operations.
+func (p *SimulationPolicy) SetAudit(audit *observe.AuditLogger) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.audit = audit
+}
+
+// Name returns "simulation", the policy's name in decisions and audit entries.
+func (p *SimulationPolicy) Name() string { return "simulation" }
+
+// Check implements security.Policy.
+func (p *SimulationPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	if !simulatedTools[evalCtx.Tool] {
+		return nil
+	}
+	err := p.check(ctx, evalCtx)
+	if err == nil && evalCtx.Simulation != nil {
+		p.record(evalCtx)
+	}
+	return err
+}
+
+func (p *SimulationPolicy) check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	sim, err := evalCtx.Simulate(ctx)
+	if errors.Is(err, security.ErrSimulationUnsupported) {
+		if p.denyUnsupported {
+			return errors.New("simulation: the chain cannot simulate transactions")
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("simulation: %w", err)
+	}
+	if sim.Reverted {
+		if sim.RevertReason == "" {
+			return errors.New("simulation: transaction would revert")
+		}
+		return fmt.Errorf("simulation: transaction would revert: %s", sim.RevertReason)
+	}
+	if !sim.Traced {
+		if p.denyUnsupported && p.bounded() {
+			return errors.New("simulation: the node cannot trace the transaction to check what it moves and calls")
+		}
+		return nil
+	}
+
+	if len(p.contracts) > 0 {
+		for _, c := range sim.Contracts {
+			if !p.contracts[common.HexToAddress(c)] {
+				return fmt.Errorf("simulation: transaction would call %s, which is not in allowed_contracts", c)
+			}
+		}
+	}
+	out := outflows(sim, evalCtx.Signer())
+	if p.maxNative != nil {
+		if sent := out[common.Address{}]; sent != nil && sent.Cmp(p.maxNative) > 0 {
+			return fmt.Errorf("simulation: wallet would send %s wei, more than max_native_outflow %s",
+				sent, p.maxNative)
+		}
+	}
+	for _, t := range sim.Transfers {
+		if t.Token == "" {
+			continue
+		}
+		token := common.HexToAddress(t.Token)
+		bound, ok := p.maxTokens[token]
+		if !ok || out[token] == nil {
+			continue
+		}
+		decimals, err := p.tokenDecimals(ctx, evalCtx, token)
+		if err != nil {
+			return fmt.Errorf("simulation: %w", err)
+		}
+		max, err := bound.Units(decimals)
+		if err != nil {
+			return fmt.Errorf("simulation: token %s: %w", token.Hex(), err)
+		}
+		if sent := out[token]; sent.Cmp(max) > 0 {
+			return fmt.Errorf("simulation: wallet would send %s of token %s, more than max_token_outflow %s",
+				units.FormatUnits(sent, decimals, -1), token.Hex(), bound)
+		}
+		delete(out, token) // checked
+	}
+	return nil
+}
+
+// bounded reports whether the policy checks anything only a trace shows.
+func (p *SimulationPolicy) bounded() bool {
+	return p.maxNative != nil || len(p.maxTokens) > 0 || len(p.contracts) > 0
+}
+
+// tokenDecimals returns the decimals of token, read once.
+func (p *SimulationPolicy) tokenDecimals(ctx context.Context, evalCtx *security.EvaluationContext, token common.Address) (int, error) {
+	p.mu.Lock()
+	decimals, ok := p.decimals[token]
+	p.mu.Unlock()
+	if ok {
+		return decimals, nil
+	}
+	decimals, err := readDecimals(ctx, evalCtx, token.Hex())
+	if err != nil {
+		return 0, err
+	}
+	p.mu.Lock()
+	p.decimals[token] = decimals
+	p.mu.Unlock()
+	return decimals, nil
+}
+
+// outflows sums what sim's transfers send from signer, by token (the zero
+// address for native currency).
+func outflows(sim *blockchain.Simulation, signer string) map[common.Address]*big.Int {
+	out := make(map[common.Address]*big.Int)
+	if signer == "" {
+		return out
+	}
+	from := common.HexToAddress(signer)
+	for _, t := range sim.Transfers {
+		if common.HexToAddress(t.From) != from {
+			continue
+		}
+		var token common.Address
+		if t.Token != "" {
+			token = common.HexToAddress(t.Token)
+		}
+		if out[token] == nil {
+			out[token] = new(big.Int)
+		}
+		out[token].Add(out[token], t.Amount)
+	}
+	return out
+}
+
+// Report implements security.Reporter: it summarizes the simulation of
+// evalCtx, or returns nil if it was not simulated.
+func (p *SimulationPolicy) Report(evalCtx *security.EvaluationContext) interface{} {
+	sim := evalCtx.Simulation
+	if sim == nil {
+		return nil
+	}
+	report := &SimulationReport{
+		Reverted:     sim.Reverted,
+		RevertReason: sim.RevertReason,
+		Traced:       sim.Traced,
+		Contracts:    sim.Contracts,
+	}
+	signer := evalCtx.Signer()
+	if signer == "" {
+		return report
+	}
+	wallet := common.HexToAddress(signer)
+	deltas := make(map[string]*big.Int)
+	for _, t := range sim.Transfers {
+		from, to := common.HexToAddress(t.From), common.HexToAddress(t.To)
+		if from == to || (from != wallet && to != wallet) {
+			continue
+		}
+		asset := nativeAsset
+		if t.Token != "" {
+			asset = common.HexToAddress(t.Token).Hex()
+		}
+		if deltas[asset] == nil {
+			deltas[asset] = new(big.Int)
+		}
+		if from == wallet {
+			deltas[asset].Sub(deltas[asset], t.Amount)
+		} else {
+			deltas[asset].Add(deltas[asset], t.Amount)
+		}
+	}
+	if len(deltas) > 0 {
+		report.Deltas = make(map[string]string, len(deltas))
+		for asset, d := range deltas {
+			report.Deltas[asset] = d.String()
+		}
+	}
+	return report
+}
+
+// record writes the simulation of an operation the policy allowed to the
+// audit log.
+func (p *SimulationPolicy) record(evalCtx *security.EvaluationContext) {
+	p.mu.Lock()
+	audit := p.audit
+	p.mu.Unlock()
+	if audit == nil {
+		return
+	}
+	entry := evalCtx.AuditEntry(observe.AuditKindPolicyDecision)
+	entry.Extra = map[string]interface{}{
+		"tool":       evalCtx.Tool,
+		"policy":     p.Name(),
+		"decision":   "allowed",
+		"simulation": p.Report(evalCtx),
+	}
+	_ = audit.Log(entry)
+}
+
+// EOF: internal/security/policies/simulation.go
@@ -0,0 +1,176 @@
+package policies_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+// simChain returns sim for every simulation, or err, and counts them.
+type simChain struct {
+	decimalsChain
+	sim         *blockchain.Simulation
+	err         error
+	simulations int
+}
+
+func (c *simChain) SimulateTransaction(ctx context.Context, tx *blockchain.Transaction) (*blockchain.Simulation, error) {
+	c.simulations++
+	return c.sim, c.err
+}
+
+func simEvalCtx(chain blockchain.Chain, tool string) *security.EvaluationContext {
+	return &security.EvaluationContext{
+		Tool:    tool,
+		Args:    map[string]interface{}{"to": vault, "data": []byte{1}},
+		Session: &chainSession{mockSession{id: "s1"}, chain},
+		From:    agent,
+	}
+}
+
+// ether is a whole number of ether in wei.
+func ether(n int64) *big.Int { return eth(n * 1000) }
+
+func newSimulationPolicy(t *testing.T, cfg config.SimulationConfig) *policies.SimulationPolicy {
+	t.Helper()
+	p, err := policies.NewSimulationPolicy(&cfg)
+	require.NoError(t, err)
+	return p
+}
+
+func TestSimulationPolicy_Reverts(t *testing.T) {
+	ctx := context.Background()
+	p := newSimulationPolicy(t, config.SimulationConfig{})
+
+	chain := &simChain{sim: &blockchain.Simulation{Reverted: true, RevertReason: "insufficient allowance"}}
+	err := p.Check(ctx, simEvalCtx(chain, "send"))
+	assert.EqualError(t, err, "simulation: transaction would revert: insufficient allowance")
+	chain.sim = &blockchain.Simulation{Reverted: true}
+	assert.EqualError(t, p.Check(ctx, simEvalCtx(chain, "transfer")), "simulation: transaction would revert")
+
+	chain.sim = &blockchain.Simulation{}
+	assert.NoError(t, p.Check(ctx, simEvalCtx(chain, "deploy")))
+	assert.NoError(t, p.Check(ctx, simEvalCtx(chain, "balance")))
+	assert.NoError(t, p.Check(ctx, simEvalCtx(chain, "safe_propose")), "the wallet does not send proposals")
+	assert.Equal(t, 3, chain.simulations)
+
+	chain.err = errors.New("connection refused")
+	assert.ErrorContains(t, p.Check(ctx, simEvalCtx(chain, "send")), "connection refused")
+}
+
+func TestSimulationPolicy_Outflows(t *testing.T) {
+	ctx := context.Background()
+	p := newSimulationPolicy(t, config.SimulationConfig{
+		MaxNativeOutflow: config.MustParseAmount("1 eth"),
+		MaxTokenOutflow:  map[string]*config.TokenAmount{dai: tokens(t, "100 dai")},
+	})
+	chain := &simChain{sim: &blockchain.Simulation{Traced: true}}
+	check := func(transfers ...blockchain.AssetTransfer) error {
+		chain.sim.Transfers = transfers
+		return p.Check(ctx, simEvalCtx(chain, "send"))
+	}
+
+	assert.NoError(t, check(
+		blockchain.AssetTransfer{From: agent, To: vault, Amount: ether(1)},
+		blockchain.AssetTransfer{Token: dai, From: agent, To: vault, Amount: ether(60)},
+		blockchain.AssetTransfer{Token: dai, From: agent, To: recipient, Amount: ether(40)},
+		blockchain.AssetTransfer{From: vault, To: recipient, Amount: ether(5)}, // not from the wallet
+	))
+	assert.EqualError(t, check(
+		blockchain.AssetTransfer{From: agent, To: vault, Amount: ether(1)},
+		blockchain.AssetTransfer{From: agent, To: recipient, Amount: big.NewInt(1)},
+	), "simulation: wallet would send 1000000000000000001 wei, more than max_native_outflow 1000000000000000000")
+	assert.EqualError(t, check(
+		blockchain.AssetTransfer{Token: dai, From: agent, To: vault, Amount: ether(60)},
+		blockchain.AssetTransfer{Token: dai, From: vault, To: agent, Amount: ether(60)}, // refunds do not offset
+		blockchain.AssetTransfer{Token: dai, From: agent, To: recipient, Amount: ether(41)},
+	), "simulation: wallet would send 101 of token "+dai+", more than max_token_outflow 100 dai")
+	assert.NoError(t, check(blockchain.AssetTransfer{Token: token, From: agent, To: vault, Amount: ether(1000)}),
+		"tokens without bound")
+	assert.Equal(t, 1, chain.calls, "decimals are read once")
+}
+
+func TestSimulationPolicy_Contracts(t *testing.T) {
+	ctx := context.Background()
+	p := newSimulationPolicy(t, config.SimulationConfig{AllowedContracts: []string{vault, token}})
+	chain := &simChain{sim: &blockchain.Simulation{Traced: true, Contracts: []string{vault, token}}}
+	assert.NoError(t, p.Check(ctx, simEvalCtx(chain, "send")))
+
+	chain.sim.Contracts = []string{vault, dai}
+	assert.EqualError(t, p.Check(ctx, simEvalCtx(chain, "send")),
+		"simulation: transaction would call "+dai+", which is not in allowed_contracts")
+}
+
+func TestSimulationPolicy_Unsupported(t *testing.T) {
+	ctx := context.Background()
+	bounded := config.SimulationConfig{AllowedContracts: []string{vault}}
+	noSimulator := &decimalsChain{}
+	untraced := &simChain{sim: &blockchain.Simulation{Contracts: []string{dai}}}
+
+	skip := newSimulationPolicy(t, bounded)
+	assert.NoError(t, skip.Check(ctx, simEvalCtx(noSimulator, "send")))
+	assert.NoError(t, skip.Check(ctx, simEvalCtx(untraced, "send")))
+
+	bounded.OnUnsupported = "deny"
+	deny := newSimulationPolicy(t, bounded)
+	assert.ErrorContains(t, deny.Check(ctx, simEvalCtx(noSimulator, "send")), "cannot simulate")
+	assert.ErrorContains(t, deny.Check(ctx, simEvalCtx(untraced, "send")), "cannot trace")
+	revertsOnly := newSimulationPolicy(t, config.SimulationConfig{OnUnsupported: "deny"})
+	assert.NoError(t, revertsOnly.Check(ctx, simEvalCtx(untraced, "send")), "nothing to trace for")
+
+	_, err := policies.NewSimulationPolicy(&config.SimulationConfig{OnUnsupported: "ask"})
+	assert.Error(t, err)
+	_, err = policies.NewSimulationPolicy(&config.SimulationConfig{AllowedContracts: []string{"vault.eth"}})
+	assert.Error(t, err)
+}
+
+func TestSimulationPolicy_Report(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+	p := newSimulationPolicy(t, config.SimulationConfig{})
+	p.SetAudit(audit)
+	ctx := context.Background()
+
+	chain := &simChain{sim: &blockchain.Simulation{
+		Traced:    true,
+		Contracts: []string{vault},
+		Transfers: []blockchain.AssetTransfer{
+			{From: agent, To: vault, Amount: ether(2)},
+			{Token: dai, From: vault, To: agent, Amount: ether(300)},
+			{Token: dai, From: agent, To: recipient, Amount: ether(100)},
+			{From: vault, To: recipient, Amount: ether(1)},
+		},
+	}}
+	evalCtx := simEvalCtx(chain, "send")
+	require.NoError(t, p.Check(ctx, evalCtx))
+	assert.Equal(t, &policies.SimulationReport{
+		Traced:    true,
+		Contracts: []string{vault},
+		Deltas:    map[string]string{"native": "-2000000000000000000", dai: "200000000000000000000"},
+	}, p.Report(evalCtx))
+	assert.Nil(t, p.Report(simEvalCtx(chain, "send")), "not simulated")
+
+	entries := readAudit(t, path)
+	require.Len(t, entries, 1)
+	assert.Equal(t, observe.AuditKindPolicyDecision, entries[0].Kind)
+	assert.Equal(t, "allowed", entries[0].Extra["decision"])
+	sim := entries[0].Extra["simulation"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"native": "-2000000000000000000", dai: "200000000000000000000"}, sim["deltas"])
+
+	chain.sim = &blockchain.Simulation{Reverted: true, RevertReason: "paused"}
+	require.Error(t, p.Check(ctx, simEvalCtx(chain, "send")))
+	assert.Len(t, readAudit(t, path), 1, "denials are recorded by the enforcer")
+}
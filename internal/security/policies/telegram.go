@@ -0,0 +1,494 @@
+// Package policies provides human‑in‑the‑loop approval through a Telegram
+// bot.
+//
+// File: internal/security/policies/telegram.go
+
+package policies
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// TelegramBotConfig configures a TelegramBot.
+type TelegramBotConfig struct {
+	// Token of the bot, from @BotFather. It is never logged.
+	Token string
+	// ChatID is the chat approval requests are sent to.
+	ChatID int64
+	// AllowedUsers are the IDs of the users who may approve or deny.
+	AllowedUsers []int64
+	// APIURL is the Bot API base URL (default https://api.telegram.org).
+	APIURL string
+	// PollTimeout is how long one long poll for answers waits (default 30s).
+	PollTimeout time.Duration
+	// Audit receives the decisions (nil = not recorded).
+	Audit *observe.AuditLogger
+	// Logger receives polling errors and unauthorized answers (nil = not
+	// logged).
+	Logger observe.Logger
+}
+
+// TelegramBot asks for approvals in a Telegram chat, with Approve and Deny
+// buttons, and receives the answers by long polling, so no public endpoint
+// is needed. It polls only while approvals are pending.
+//
+// Only allowed users can decide, and the first decision wins; later
+// answers are told the request was already decided. A request needing a
+// Quorum keeps its buttons until enough approvers have decided, each once,
+// and shows their decisions. A request that times out is marked expired
+// in the chat.
+type TelegramBot struct {
+	cfg     TelegramBotConfig
+	allowed map[int64]bool
+	client  *http.Client
+
+	mu      sync.Mutex
+	pending map[string]*telegramApproval // request ID -> awaiting an answer
+	closed  map[string]string            // request ID -> outcome, for late answers
+	polling bool
+	offset  int64
+}
+
+// telegramApproval is a request awaiting an answer.
+type telegramApproval struct {
+	messageID int64
+	text      string
+	evalCtx   *security.EvaluationContext
+	started   time.Time
+	votes     *quorumVotes
+	decisions []string              // one line per decision so far
+	decision  chan telegramDecision // buffered; receives the settling decision
+}
+
+type telegramDecision struct {
+	approved bool
+	user     tgUser
+	votes    map[string]interface{} // for requests needing several approvals
+}
+
+// NewTelegramBot creates a bot from cfg.
+func NewTelegramBot(cfg TelegramBotConfig) (*TelegramBot, error) {
+	if cfg.Token == "" {
+		return nil, errors.New("telegram: no bot token")
+	}
+	if cfg.ChatID == 0 {
+		return nil, errors.New("telegram: no chat_id")
+	}
+	if len(cfg.AllowedUsers) == 0 {
+		return nil, errors.New("telegram: no allowed_users")
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = "https://api.telegram.org"
+	}
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+	if cfg.PollTimeout <= 0 {
+		cfg.PollTimeout = 30 * time.Second
+	}
+	b := &TelegramBot{
+		cfg:     cfg,
+		allowed: make(map[int64]bool, len(cfg.AllowedUsers)),
+		client:  &http.Client{Timeout: cfg.PollTimeout + 10*time.Second},
+		pending: make(map[string]*telegramApproval),
+		closed:  make(map[string]string),
+	}
+	for _, id := range cfg.AllowedUsers {
+		b.allowed[id] = true
+	}
+	return b, nil
+}
+
+// Approve sends an approval request for evalCtx, with details, and waits
+// up to timeout for an allowed user to approve or deny it.
+func (b *TelegramBot) Approve(ctx context.Context, evalCtx *security.EvaluationContext, timeout time.Duration, details []string) error {
+	return b.ApproveQuorum(ctx, evalCtx, timeout, details, Quorum{})
+}
+
+// ApproveQuorum is Approve for a request that quorum must approve, its
+// approvers named by Telegram user id: it waits for enough distinct
+// approvals, or a denial that settles it.
+func (b *TelegramBot) ApproveQuorum(ctx context.Context, evalCtx *security.EvaluationContext, timeout time.Duration, details []string, quorum Quorum) error {
+	id, err := newRequestID()
+	if err != nil {
+		return fmt.Errorf("telegram: %w", err)
+	}
+	lines := []string{"Approval required", "Tool: " + evalCtx.Tool}
+	if signer := evalCtx.Signer(); signer != "" {
+		lines = append(lines, "Signer: "+signer)
+	}
+	if evalCtx.ChainName != "" {
+		lines = append(lines, "Chain: "+evalCtx.ChainName)
+	}
+	lines = append(lines, fmt.Sprintf("Arguments: %v", evalCtx.Args))
+	approval := &telegramApproval{
+		evalCtx:  evalCtx,
+		started:  time.Now(),
+		votes:    newQuorumVotes(quorum),
+		decision: make(chan telegramDecision, 1),
+	}
+	approval.text = strings.Join(append(lines, details...), "\n")
+
+	// Register first, so that even an immediate answer finds the request.
+	b.mu.Lock()
+	b.pending[id] = approval
+	b.mu.Unlock()
+	var msg tgMessage
+	err = b.call(ctx, "sendMessage", map[string]interface{}{
+		"chat_id":      b.cfg.ChatID,
+		"text":         approval.text,
+		"reply_markup": buttons(id),
+	}, &msg)
+	b.mu.Lock()
+	if err != nil {
+		delete(b.pending, id)
+		b.mu.Unlock()
+		return fmt.Errorf("telegram: send approval request: %w", err)
+	}
+	approval.messageID = msg.MessageID
+	b.mu.Unlock()
+	b.startPolling()
+
+	started := approval.started
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var d telegramDecision
+	select {
+	case d = <-approval.decision:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	if d.user.ID == 0 {
+		b.mu.Lock()
+		_, waiting := b.pending[id]
+		var votes map[string]interface{}
+		text := approval.status()
+		if waiting {
+			delete(b.pending, id)
+			b.closed[id] = "expired"
+			if approval.votes.multi() {
+				// Who had approved when time ran out.
+				votes = approval.votes.record()
+			}
+		}
+		b.mu.Unlock()
+		if !waiting {
+			// Decided as the wait ended.
+			d = <-approval.decision
+		} else {
+			b.edit(approval.messageID, text+"\n\n⌛ Expired")
+			if ctx.Err() != nil {
+				b.record(evalCtx, "cancelled", started, tgUser{}, votes)
+				return fmt.Errorf("human approval: %w", ctx.Err())
+			}
+			b.record(evalCtx, "timeout", started, tgUser{}, votes)
+			return fmt.Errorf("%w after %v", ErrApprovalTimeout, timeout)
+		}
+	}
+	if !d.approved {
+		b.record(evalCtx, "denied", started, d.user, d.votes)
+		return fmt.Errorf("human rejected transaction (telegram user %d)", d.user.ID)
+	}
+	b.record(evalCtx, "approved", started, d.user, d.votes)
+	return nil
+}
+
+// buttons returns the Approve and Deny buttons of request id.
+func buttons(id string) map[string]interface{} {
+	return map[string]interface{}{"inline_keyboard": [][]map[string]string{{
+		{"text": "Approve", "callback_data": "approve:" + id},
+		{"text": "Deny", "callback_data": "deny:" + id},
+	}}}
+}
+
+// status returns the request's text with the decisions so far; b.mu must
+// be held.
+func (a *telegramApproval) status() string {
+	if len(a.decisions) == 0 {
+		return a.text
+	}
+	return a.text + "\n\n" + strings.Join(a.decisions, "\n")
+}
+
+// startPolling starts the poller unless it is running.
+func (b *TelegramBot) startPolling() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.polling {
+		return
+	}
+	b.polling = true
+	go b.poll()
+}
+
+// poll receives answers until no approval is pending.
+func (b *TelegramBot) poll() {
+	for {
+		b.mu.Lock()
+		if len(b.pending) == 0 {
+			b.polling = false
+			b.mu.Unlock()
+			return
+		}
+		offset := b.offset
+		b.mu.Unlock()
+
+		var updates []tgUpdate
+		err := b.call(context.Background(), "getUpdates", map[string]interface{}{
+			"offset":          offset,
+			"timeout":         int(b.cfg.PollTimeout / time.Second),
+			"allowed_updates": []string{"callback_query"},
+		}, &updates)
+		if err != nil {
+			b.log("telegram: poll for approvals failed", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		for _, u := range updates {
+			b.mu.Lock()
+			if u.UpdateID >= b.offset {
+				b.offset = u.UpdateID + 1
+			}
+			b.mu.Unlock()
+			if u.CallbackQuery != nil {
+				b.answer(u.CallbackQuery)
+			}
+		}
+	}
+}
+
+// answer resolves the request a button press answers: the first press of
+// an allowed user decides it, or for a request needing a Quorum, the press
+// of an approver that settles it.
+func (b *TelegramBot) answer(q *tgCallbackQuery) {
+	if q.Message == nil || q.Message.Chat.ID != b.cfg.ChatID {
+		return
+	}
+	action, id, _ := strings.Cut(q.Data, ":")
+	if action != "approve" && action != "deny" {
+		return
+	}
+	if !b.allowed[q.From.ID] {
+		if b.cfg.Logger != nil {
+			b.cfg.Logger.Warn("telegram: answer from a user who is not allowed",
+				map[string]interface{}{"telegram_user_id": q.From.ID})
+		}
+		b.reply(q.ID, "You are not allowed to decide.")
+		return
+	}
+
+	outcome := "Approved"
+	if action == "deny" {
+		outcome = "Denied"
+	}
+	b.mu.Lock()
+	approval, waiting := b.pending[id]
+	previous, known := b.closed[id]
+	var done, approved bool
+	var err error
+	var text, tally string
+	var votes map[string]interface{}
+	if waiting {
+		done, approved, err = approval.votes.vote(strconv.FormatInt(q.From.ID, 10), action == "approve")
+	}
+	if waiting && err == nil {
+		line := outcome + " by " + q.From.label()
+		if approval.votes.multi() {
+			tally = approval.votes.tally()
+			line += " (" + tally + ")"
+			votes = approval.votes.record()
+		}
+		approval.decisions = append(approval.decisions, line)
+		text = approval.status()
+		if done {
+			delete(b.pending, id)
+			b.closed[id] = outcome
+		}
+	}
+	b.mu.Unlock()
+
+	switch {
+	case errors.Is(err, ErrNotApprover):
+		b.reply(q.ID, "You are not an approver of this request.")
+	case errors.Is(err, ErrDuplicateApproval):
+		b.reply(q.ID, "You already decided.")
+	case waiting && done:
+		approval.decision <- telegramDecision{approved: approved, user: q.From, votes: votes}
+		b.reply(q.ID, outcome+".")
+		b.edit(approval.messageID, text)
+	case waiting:
+		// More approvals are needed; the buttons stay.
+		b.reply(q.ID, outcome+"; "+tally+" approvals.")
+		b.editPending(approval.messageID, text, id)
+		decision := "partial_approval"
+		if action == "deny" {
+			decision = "partial_denial"
+		}
+		b.record(approval.evalCtx, decision, approval.started, q.From, votes)
+	case known && previous != "expired":
+		b.reply(q.ID, "Already "+strings.ToLower(previous)+".")
+	default:
+		// Timed out, or sent before a restart.
+		b.reply(q.ID, "This request has expired.")
+		b.edit(q.Message.MessageID, q.Message.Text+"\n\n⌛ Expired")
+	}
+}
+
+// reply answers a button press with a notification.
+func (b *TelegramBot) reply(queryID, text string) {
+	err := b.call(context.Background(), "answerCallbackQuery", map[string]interface{}{
+		"callback_query_id": queryID,
+		"text":              text,
+	}, nil)
+	if err != nil {
+		b.log("telegram: answer callback failed", err)
+	}
+}
+
+// edit replaces the text of a request message, removing its buttons.
+func (b *TelegramBot) edit(messageID int64, text string) {
+	b.editMessage(map[string]interface{}{
+		"chat_id":    b.cfg.ChatID,
+		"message_id": messageID,
+		"text":       text,
+	})
+}
+
+// editPending replaces the text of the message of request id, keeping its
+// buttons.
+func (b *TelegramBot) editPending(messageID int64, text, id string) {
+	b.editMessage(map[string]interface{}{
+		"chat_id":      b.cfg.ChatID,
+		"message_id":   messageID,
+		"text":         text,
+		"reply_markup": buttons(id),
+	})
+}
+
+func (b *TelegramBot) editMessage(params map[string]interface{}) {
+	if err := b.call(context.Background(), "editMessageText", params, nil); err != nil {
+		b.log("telegram: edit approval message failed", err)
+	}
+}
+
+// record writes a decision to the audit log, with the votes of a request
+// needing several approvals.
+func (b *TelegramBot) record(evalCtx *security.EvaluationContext, decision string, started time.Time, user tgUser, votes map[string]interface{}) {
+	extra := map[string]interface{}{}
+	for k, v := range votes {
+		extra[k] = v
+	}
+	if user.ID != 0 {
+		extra["approved_by"] = fmt.Sprintf("telegram:%d", user.ID)
+		extra["telegram_user_id"] = user.ID
+		if user.Username != "" {
+			extra["telegram_username"] = user.Username
+		}
+	}
+	recordDecision(b.cfg.Audit, evalCtx, "telegram", decision, started, extra)
+}
+
+// call invokes a Bot API method and decodes its result into result. The
+// token is part of the URL, so errors are redacted before they are
+// returned.
+func (b *TelegramBot) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("telegram %s: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.APIURL+"/bot"+b.cfg.Token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("telegram %s: %s", method, b.redact(err.Error()))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("telegram %s: %s", method, b.redact(err.Error()))
+	}
+	defer resp.Body.Close()
+	var out struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("telegram %s: decode response (HTTP %d): %w", method, resp.StatusCode, err)
+	}
+	if !out.OK {
+		return fmt.Errorf("telegram %s: %s", method, b.redact(out.Description))
+	}
+	if result != nil {
+		if err := json.Unmarshal(out.Result, result); err != nil {
+			return fmt.Errorf("telegram %s: decode result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// redact removes the bot token from s.
+func (b *TelegramBot) redact(s string) string {
+	return strings.ReplaceAll(s, b.cfg.Token, "<redacted>")
+}
+
+func (b *TelegramBot) log(msg string, err error) {
+	if b.cfg.Logger != nil {
+		b.cfg.Logger.Warn(msg, map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// newRequestID returns a random ID short enough for callback data.
+func newRequestID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("request id: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Bot API types, as far as they are used.
+type tgUpdate struct {
+	UpdateID      int64            `json:"update_id"`
+	CallbackQuery *tgCallbackQuery `json:"callback_query,omitempty"`
+}
+
+type tgCallbackQuery struct {
+	ID      string     `json:"id"`
+	From    tgUser     `json:"from"`
+	Message *tgMessage `json:"message,omitempty"`
+	Data    string     `json:"data"`
+}
+
+type tgUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+// label names the user in messages.
+func (u tgUser) label() string {
+	if u.Username != "" {
+		return fmt.Sprintf("@%s (%d)", u.Username, u.ID)
+	}
+	return fmt.Sprint(u.ID)
+}
+
+type tgMessage struct {
+	MessageID int64 `json:"message_id"`
+	Chat      struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text string `json:"text"`
+}
+
+// EOF: internal/security/policies/telegram.go
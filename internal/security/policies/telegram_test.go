@@ -0,0 +1,295 @@
+package policies_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+const (
+	botToken = "123456:SECRET-bot-token"
+	chatID   = int64(-1001)
+	alice    = int64(11)
+	bob      = int64(22)
+	mallory  = int64(66)
+)
+
+// fakeTelegram is a Bot API serving button presses queued with press.
+type fakeTelegram struct {
+	*httptest.Server
+	mu       sync.Mutex
+	sent     []string          // request message texts
+	ids      map[int64]string  // message ID -> request ID in its buttons
+	edits    map[int64]string  // message ID -> last edited text
+	answers  map[string]string // callback query ID -> answer
+	updates  []map[string]interface{}
+	nextID   int64
+	notify   chan struct{}
+	answered chan struct{}
+}
+
+func newFakeTelegram(t *testing.T) *fakeTelegram {
+	f := &fakeTelegram{
+		ids:      make(map[int64]string),
+		edits:    make(map[int64]string),
+		answers:  make(map[string]string),
+		notify:   make(chan struct{}, 1),
+		answered: make(chan struct{}, 16),
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/bot"+botToken+"/") {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "description": "Not Found"})
+			return
+		}
+		var params map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+		var result interface{} = true
+		switch strings.TrimPrefix(r.URL.Path, "/bot"+botToken+"/") {
+		case "sendMessage":
+			f.mu.Lock()
+			f.nextID++
+			f.sent = append(f.sent, params["text"].(string))
+			button := params["reply_markup"].(map[string]interface{})["inline_keyboard"].([]interface{})[0].([]interface{})[0]
+			f.ids[f.nextID] = strings.TrimPrefix(button.(map[string]interface{})["callback_data"].(string), "approve:")
+			result = map[string]interface{}{"message_id": f.nextID, "chat": map[string]interface{}{"id": chatID}}
+			f.mu.Unlock()
+		case "editMessageText":
+			f.mu.Lock()
+			f.edits[int64(params["message_id"].(float64))] = params["text"].(string)
+			f.mu.Unlock()
+		case "answerCallbackQuery":
+			f.mu.Lock()
+			f.answers[params["callback_query_id"].(string)] = params["text"].(string)
+			f.mu.Unlock()
+			f.answered <- struct{}{}
+		case "getUpdates":
+			offset := int64(params["offset"].(float64))
+			var out []map[string]interface{}
+			for try := 0; try < 2 && len(out) == 0; try++ {
+				f.mu.Lock()
+				for _, u := range f.updates {
+					if u["update_id"].(int64) >= offset {
+						out = append(out, u)
+					}
+				}
+				f.mu.Unlock()
+				if len(out) == 0 {
+					select {
+					case <-f.notify:
+					case <-time.After(200 * time.Millisecond):
+					}
+				}
+			}
+			result = out
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "result": result})
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// press queues a press of a button of the last request message.
+func (f *fakeTelegram) press(queryID string, user int64, action string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data := action + ":" + f.ids[f.nextID]
+	f.updates = append(f.updates, map[string]interface{}{
+		"update_id": int64(len(f.updates) + 1),
+		"callback_query": map[string]interface{}{
+			"id":      queryID,
+			"from":    map[string]interface{}{"id": user, "username": "u" + queryID},
+			"data":    data,
+			"message": map[string]interface{}{"message_id": f.nextID, "chat": map[string]interface{}{"id": chatID}, "text": f.sent[len(f.sent)-1]},
+		},
+	})
+	select {
+	case f.notify <- struct{}{}:
+	default:
+	}
+}
+
+// waitSent waits for the n‑th request message.
+func (f *fakeTelegram) waitSent(t *testing.T, n int) {
+	require.Eventually(t, func() bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return len(f.sent) >= n
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func (f *fakeTelegram) answer(queryID string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.answers[queryID]
+}
+
+// waitEdit waits for message ID messageID to be edited to contain text.
+func (f *fakeTelegram) waitEdit(t *testing.T, messageID int64, text string) {
+	require.Eventually(t, func() bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return strings.Contains(f.edits[messageID], text)
+	}, 2*time.Second, 5*time.Millisecond, "message %d edited to contain %q", messageID, text)
+}
+
+func newTelegramBot(t *testing.T, f *fakeTelegram, audit *observe.AuditLogger) *policies.TelegramBot {
+	bot, err := policies.NewTelegramBot(policies.TelegramBotConfig{
+		Token:        botToken,
+		ChatID:       chatID,
+		AllowedUsers: []int64{alice, bob},
+		APIURL:       f.URL,
+		PollTimeout:  time.Second,
+		Audit:        audit,
+	})
+	require.NoError(t, err)
+	return bot
+}
+
+func telegramHITL(bot *policies.TelegramBot, timeout time.Duration) *policies.HITLPolicy {
+	hitl := policies.NewHITLPolicy(config.MustParseAmount("1 eth"), timeout, "telegram")
+	hitl.SetTelegram(bot)
+	return hitl
+}
+
+func TestTelegramBot_Approve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	audit, err := observe.NewAuditLogger(path, true)
+	require.NoError(t, err)
+	defer audit.Close()
+	f := newFakeTelegram(t)
+	hitl := telegramHITL(newTelegramBot(t, f, audit), 5*time.Second)
+
+	done := make(chan error, 1)
+	go func() { done <- hitl.Check(context.Background(), usdEvalCtx(eth(2000))) }()
+	f.waitSent(t, 1)
+	f.press("q1", mallory, "approve")
+	f.press("q2", alice, "approve")
+	require.NoError(t, <-done)
+	<-f.answered
+	<-f.answered
+
+	assert.Contains(t, f.sent[0], "Tool: transfer")
+	assert.Contains(t, f.sent[0], "Amount: 2000000000000000000 wei")
+	assert.Equal(t, "You are not allowed to decide.", f.answer("q1"))
+	assert.Equal(t, "Approved.", f.answer("q2"))
+	f.waitEdit(t, 1, "Approved by @uq2 (11)")
+
+	entries := readAudit(t, path)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "s1", entries[0].SessionID)
+	assert.Equal(t, "hitl_decision", entries[0].Extra["action"])
+	assert.Equal(t, "approved", entries[0].Extra["decision"])
+	assert.EqualValues(t, alice, entries[0].Extra["telegram_user_id"])
+
+	// Below the threshold nothing is asked.
+	require.NoError(t, hitl.Check(context.Background(), usdEvalCtx(eth(500))))
+	assert.Len(t, f.sent, 1)
+}
+
+func TestTelegramBot_FirstDecisionWins(t *testing.T) {
+	f := newFakeTelegram(t)
+	hitl := telegramHITL(newTelegramBot(t, f, nil), 5*time.Second)
+
+	done := make(chan error, 1)
+	go func() { done <- hitl.Check(context.Background(), usdEvalCtx(eth(2000))) }()
+	f.waitSent(t, 1)
+	f.press("q1", bob, "deny")
+	f.press("q2", alice, "approve")
+	err := <-done
+	assert.ErrorContains(t, err, "human rejected transaction (telegram user 22)")
+	<-f.answered
+	<-f.answered
+	assert.Equal(t, "Denied.", f.answer("q1"))
+	assert.Equal(t, "Already denied.", f.answer("q2"))
+	f.waitEdit(t, 1, "Denied by @uq1 (22)")
+}
+
+func TestTelegramBot_Quorum(t *testing.T) {
+	f := newFakeTelegram(t)
+	hitl := telegramHITL(newTelegramBot(t, f, nil), 5*time.Second)
+	require.NoError(t, hitl.SetTiers([]config.HITLTier{{
+		Threshold:         config.MustParseAmount("1.5 eth"),
+		ApprovalsRequired: 2,
+		Approvers:         []string{"11", "22"},
+	}}))
+
+	done := make(chan error, 1)
+	go func() { done <- hitl.Check(context.Background(), usdEvalCtx(eth(2000))) }()
+	f.waitSent(t, 1)
+	f.press("q1", alice, "approve")
+	<-f.answered
+	f.press("q2", alice, "approve")
+	<-f.answered
+	f.waitEdit(t, 1, "Approved by @uq1 (11) (1/2)")
+	f.press("q3", bob, "approve")
+	require.NoError(t, <-done)
+	<-f.answered
+
+	assert.Contains(t, f.sent[0], "Approvals required: 2 of 2")
+	assert.Equal(t, "Approved; 1/2 approvals.", f.answer("q1"))
+	assert.Equal(t, "You already decided.", f.answer("q2"))
+	assert.Equal(t, "Approved.", f.answer("q3"))
+	f.waitEdit(t, 1, "Approved by @uq3 (22) (2/2)")
+}
+
+func TestTelegramBot_Expired(t *testing.T) {
+	f := newFakeTelegram(t)
+	hitl := telegramHITL(newTelegramBot(t, f, nil), 100*time.Millisecond)
+
+	err := hitl.Check(context.Background(), usdEvalCtx(eth(2000)))
+	assert.ErrorContains(t, err, "human approval timed out after 100ms")
+	f.waitEdit(t, 1, "Expired")
+
+	// A late answer, received while the next request waits, is refused.
+	f.press("late", alice, "approve")
+	done := make(chan error, 1)
+	go func() { done <- hitl.Check(context.Background(), usdEvalCtx(eth(3000))) }()
+	<-f.answered
+	assert.Equal(t, "This request has expired.", f.answer("late"))
+	assert.ErrorContains(t, <-done, "timed out")
+}
+
+func TestTelegramBot_TokenNeverShown(t *testing.T) {
+	f := newFakeTelegram(t)
+	f.Close()
+	hitl := telegramHITL(newTelegramBot(t, f, nil), time.Second)
+	err := hitl.Check(context.Background(), usdEvalCtx(eth(2000)))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "telegram: send approval request")
+	assert.NotContains(t, err.Error(), botToken)
+	assert.NotContains(t, err.Error(), "SECRET")
+
+	_, err = policies.NewTelegramBot(policies.TelegramBotConfig{ChatID: chatID, AllowedUsers: []int64{alice}})
+	assert.ErrorContains(t, err, "no bot token")
+	assert.ErrorContains(t, policies.NewHITLPolicy(nil, 0, "telegram").Approve(context.Background(), usdEvalCtx(nil), "unknown token"), "no bot configured")
+}
+
+func readAudit(t *testing.T, path string) []observe.AuditEntry {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var entries []observe.AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry observe.AuditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
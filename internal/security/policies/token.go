@@ -0,0 +1,327 @@
+// Package policies provides per‑token limits on ERC‑20 transfers.
+//
+// File: internal/security/policies/token.go
+
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// tokenABI holds the ERC‑20 methods that move or approve tokens. Every
+// amount is named "amount", increaseAllowance's addedValue included.
+const tokenABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"transferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"increaseAllowance","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+// tokenSelectors are the selectors of the methods in tokenABI.
+var tokenSelectors = map[[4]byte]bool{
+	{0xa9, 0x05, 0x9c, 0xbb}: true, // transfer(address,uint256)
+	{0x23, 0xb8, 0x72, 0xdd}: true, // transferFrom(address,address,uint256)
+	{0x09, 0x5e, 0xa7, 0xb3}: true, // approve(address,uint256)
+	{0x39, 0x50, 0x93, 0x51}: true, // increaseAllowance(address,uint256)
+}
+
+// tokenTools are the tools that send, or make an account send, the data
+// of their arguments.
+var tokenTools = map[string]bool{
+	"send":         true,
+	"sign":         true,
+	"send_raw":     true,
+	"safe_propose": true,
+	"aa_send":      true,
+}
+
+// tokenCall is an ERC‑20 call in the transaction of an operation.
+type tokenCall struct {
+	token  string                 // checksummed address of the token
+	method string                 // a method of tokenABI
+	args   map[string]interface{} // by the input names of tokenABI
+}
+
+// tokenCallData decodes the ERC‑20 call in the data of the operation's
+// transaction. It returns a nil args if the operation makes no such call.
+func tokenCallData(evalCtx *security.EvaluationContext) (string, map[string]interface{}, error) {
+	if !tokenTools[evalCtx.Tool] {
+		return "", nil, nil
+	}
+	tx := evalCtx.Transaction()
+	if tx.To == nil || len(tx.Data) < 4 || !tokenSelectors[[4]byte(tx.Data[:4])] {
+		return "", nil, nil
+	}
+	return evm.DecodeCall(tokenABI, tx.Data)
+}
+
+// decodeTokenCall returns the ERC‑20 call the operation makes, with the
+// token resolved if given as a name, or nil if it makes none.
+func decodeTokenCall(ctx context.Context, evalCtx *security.EvaluationContext) (*tokenCall, error) {
+	method, args, err := tokenCallData(evalCtx)
+	if err == nil && args == nil {
+		return nil, nil
+	}
+	token := *evalCtx.Transaction().To
+	if !common.IsHexAddress(token) {
+		resolved, err := resolveName(ctx, evalCtx, token)
+		if err != nil {
+			return nil, err
+		}
+		token = resolved
+	}
+	token = common.HexToAddress(token).Hex()
+	if err != nil {
+		return nil, fmt.Errorf("token %s: %w", token, err)
+	}
+	return &tokenCall{token: token, method: method, args: args}, nil
+}
+
+// Approver asks a human to approve an operation, as HITLPolicy does.
+type Approver interface {
+	Approve(ctx context.Context, evalCtx *security.EvaluationContext, reason string) error
+}
+
+// TokenLimitPolicy enforces per‑transaction and daily limits on the
+// amounts of ERC‑20 tokens that transactions transfer or approve, which
+// the native currency limits do not see. It decodes transfer,
+// transferFrom, approve and increaseAllowance calls from the
+// transaction's data.
+//
+// An approval, or an increase of an allowance, counts like a transfer,
+// since the spender can move the approved amount. A transferFrom counts
+// unless the tokens go to the signing account itself. Calls on tokens
+// without limits are allowed, denied or put to the Approver, as
+// configured.
+type TokenLimitPolicy struct {
+	mu       sync.Mutex
+	tokens   map[string]*tokenLimit // checksummed token address -> limit
+	unknown  string                 // "allow", "deny" or "approve"
+	approver Approver
+	window   time.Duration
+	reserved map[*security.EvaluationContext]tokenReservation
+}
+
+// tokenLimit is the configured limit of one token and its daily spend.
+// A token deployed at the same address on several chains has limits,
+// decimals and a daily spend on each.
+type tokenLimit struct {
+	cfg        config.TokenLimitConfig
+	units      map[string]*tokenUnits // chain -> limits in base units
+	dailySpent map[string]*big.Int    // spendKey -> spent in current window
+	dailyReset map[string]time.Time   // spendKey -> window start
+}
+
+// tokenUnits is the limit of a token in base units on one chain.
+type tokenUnits struct {
+	tokenDecimals
+	cfg        *config.TokenLimitConfig
+	maxTxValue *big.Int
+	dailyLimit *big.Int
+}
+
+type tokenReservation struct {
+	token string
+	reservation
+}
+
+// NewTokenLimitPolicy creates a policy with limits by token address.
+// unknown is "allow" (or ""), "deny" or "approve"; approve requires an
+// approver.
+func NewTokenLimitPolicy(limits map[string]*config.TokenLimitConfig, unknown string, approver Approver) (*TokenLimitPolicy, error) {
+	switch unknown {
+	case "":
+		unknown = "allow"
+	case "allow", "deny":
+	case "approve":
+		if approver == nil {
+			return nil, errors.New("token limits: unknown tokens need an approver")
+		}
+	default:
+		return nil, fmt.Errorf("token limits: invalid unknown token mode %q", unknown)
+	}
+	p := &TokenLimitPolicy{
+		tokens:   make(map[string]*tokenLimit, len(limits)),
+		unknown:  unknown,
+		approver: approver,
+		window:   24 * time.Hour,
+		reserved: make(map[*security.EvaluationContext]tokenReservation),
+	}
+	for addr, cfg := range limits {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("token limits: invalid address %q", addr)
+		}
+		limit := &tokenLimit{
+			units:      make(map[string]*tokenUnits),
+			dailySpent: make(map[string]*big.Int),
+			dailyReset: make(map[string]time.Time),
+		}
+		if cfg != nil {
+			limit.cfg = *cfg
+		}
+		if _, err := limit.newUnits(); err != nil {
+			return nil, fmt.Errorf("token limits: %s: %w", addr, err)
+		}
+		p.tokens[common.HexToAddress(addr).Hex()] = limit
+	}
+	return p, nil
+}
+
+// newUnits returns the limit of the token on a chain, in base units once
+// its decimals are known.
+func (l *tokenLimit) newUnits() (*tokenUnits, error) {
+	u := &tokenUnits{cfg: &l.cfg}
+	var err error
+	if u.tokenDecimals, err = newTokenDecimals(l.cfg.Decimals, l.symbol(), u.convert); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// unitsOn returns the limit of the token on chain. The caller holds the
+// policy's lock.
+func (l *tokenLimit) unitsOn(chain string) *tokenUnits {
+	u, ok := l.units[chain]
+	if !ok {
+		u, _ = l.newUnits() // validated by NewTokenLimitPolicy
+		l.units[chain] = u
+	}
+	return u
+}
+
+// convert converts the configured limits to base units.
+func (u *tokenUnits) convert(decimals int) error {
+	var err error
+	if u.cfg.MaxTransactionValue != nil {
+		if u.maxTxValue, err = u.cfg.MaxTransactionValue.Units(decimals); err != nil {
+			return err
+		}
+	}
+	if u.cfg.DailyLimit != nil {
+		if u.dailyLimit, err = u.cfg.DailyLimit.Units(decimals); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// symbol returns the symbol the configured limits are given in, if any.
+func (l *tokenLimit) symbol() string {
+	for _, a := range []*config.TokenAmount{l.cfg.MaxTransactionValue, l.cfg.DailyLimit} {
+		if a != nil && a.Symbol != "" {
+			return a.Symbol
+		}
+	}
+	return ""
+}
+
+// Name returns "token_limits", the policy's name in decisions and audit entries.
+func (p *TokenLimitPolicy) Name() string { return "token_limits" }
+
+// Prompts implements security.Prompter: the policy asks the approver
+// about tokens without limits if so configured.
+func (p *TokenLimitPolicy) Prompts() bool { return p.unknown == "approve" }
+
+// Check implements security.Policy.
+func (p *TokenLimitPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	call, err := decodeTokenCall(ctx, evalCtx)
+	if err != nil || call == nil {
+		return err
+	}
+	token, method, args := call.token, call.method, call.args
+	amount, _ := args["amount"].(*big.Int)
+	account := ""
+	if signer := evalCtx.Signer(); signer != "" {
+		account = common.HexToAddress(signer).Hex()
+	}
+	if method == "transferFrom" {
+		if to, _ := args["to"].(common.Address); account != "" && to.Hex() == account {
+			return nil // tokens come to the signer
+		}
+	}
+
+	limit, ok := p.tokens[token]
+	if !ok {
+		switch p.unknown {
+		case "deny":
+			return fmt.Errorf("token %s: %s of a token without limits is not allowed", token, method)
+		case "approve":
+			return p.approver.Approve(ctx, evalCtx, fmt.Sprintf("%s of %s base units of token %s, which has no limits", method, amount, token))
+		}
+		return nil
+	}
+	p.mu.Lock()
+	units := limit.unitsOn(evalCtx.ChainName)
+	p.mu.Unlock()
+	if err := units.resolveDecimals(ctx, evalCtx, token, &p.mu); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if units.maxTxValue != nil && amount.Cmp(units.maxTxValue) > 0 {
+		return &security.ErrLimitExceeded{
+			Name: "max_transaction_value", Unit: "token", Token: token, Limit: units.maxTxValue, Attempted: amount,
+			Reason: fmt.Sprintf("token %s: %s of %s exceeds per‑tx limit %s",
+				token, method, units.format(amount), units.format(units.maxTxValue)),
+		}
+	}
+	if units.dailyLimit == nil {
+		return nil
+	}
+	now := time.Now().UTC()
+	key := spendKey(evalCtx.ChainName, account)
+	resetTime, exists := limit.dailyReset[key]
+	if !exists || now.Sub(resetTime) > p.window {
+		limit.dailySpent[key] = new(big.Int)
+		limit.dailyReset[key] = now
+	}
+	spent := limit.dailySpent[key]
+	newSpent := new(big.Int).Add(spent, amount)
+	if newSpent.Cmp(units.dailyLimit) > 0 {
+		return &security.ErrLimitExceeded{
+			Name: "daily_limit", Unit: "token", Token: token, Account: account,
+			Limit: units.dailyLimit, Attempted: amount, Spent: new(big.Int).Set(spent),
+			Reason: fmt.Sprintf("token %s: daily limit exceeded for %s: limit %s, already spent %s, attempted +%s",
+				token, accountLabel(account), units.format(units.dailyLimit), units.format(spent), units.format(amount)),
+		}
+	}
+	limit.dailySpent[key] = newSpent
+	p.reserved[evalCtx] = tokenReservation{token: token,
+		reservation: reservation{key: key, amount: amount, window: limit.dailyReset[key]}}
+	return nil
+}
+
+// Settle implements security.Settler. It keeps the amount reserved for
+// evalCtx if err is nil and gives it back to the daily limit otherwise.
+func (p *TokenLimitPolicy) Settle(ctx context.Context, evalCtx *security.EvaluationContext, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r, ok := p.reserved[evalCtx]
+	if !ok {
+		return
+	}
+	delete(p.reserved, evalCtx)
+	limit := p.tokens[r.token]
+	if err == nil || !limit.dailyReset[r.key].Equal(r.window) {
+		return
+	}
+	spent := new(big.Int).Sub(limit.dailySpent[r.key], r.amount)
+	if spent.Sign() < 0 {
+		spent.SetInt64(0)
+	}
+	limit.dailySpent[r.key] = spent
+}
+
+// EOF: internal/security/policies/token.go
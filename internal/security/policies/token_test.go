@@ -0,0 +1,209 @@
+package policies_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+const (
+	agent     = "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	owner     = "0x1306b01bC3e4AD202612D3843387e94737673F53"
+	recipient = "0x5aFE3855358E112B5647B952709E6165e1c1eEEe"
+	dai       = "0x6B175474E89094C44Da98b954EedeAC495271d0F"
+)
+
+func tokens(t *testing.T, s string) *config.TokenAmount {
+	t.Helper()
+	a, err := config.ParseTokenAmount(s)
+	require.NoError(t, err)
+	return a
+}
+
+// usdc is a whole number of USDC in base units.
+func usdc(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e6)) }
+
+// decimalsChain answers decimals() calls with 18.
+type decimalsChain struct {
+	blockchain.Chain
+	calls int
+}
+
+func (c *decimalsChain) CallContract(ctx context.Context, call *blockchain.ContractCall) ([]byte, error) {
+	c.calls++
+	return common.LeftPadBytes([]byte{18}, 32), nil
+}
+
+// recordingApprover approves or rejects every operation and records why
+// it was asked.
+type recordingApprover struct {
+	reasons []string
+	err     error
+}
+
+func (a *recordingApprover) Approve(ctx context.Context, evalCtx *security.EvaluationContext, reason string) error {
+	a.reasons = append(a.reasons, reason)
+	return a.err
+}
+
+func tokenEvalCtx(chain blockchain.Chain, to string, data []byte) *security.EvaluationContext {
+	return &security.EvaluationContext{
+		Tool:    "send",
+		Args:    map[string]interface{}{"to": to, "data": data},
+		Session: &chainSession{mockSession{id: "s1"}, chain},
+		From:    agent,
+	}
+}
+
+func newUSDCPolicy(t *testing.T) *policies.TokenLimitPolicy {
+	t.Helper()
+	decimals := uint8(6)
+	policy, err := policies.NewTokenLimitPolicy(map[string]*config.TokenLimitConfig{
+		token: {MaxTransactionValue: tokens(t, "1000 usdc"), DailyLimit: tokens(t, "1500 usdc"), Decimals: &decimals},
+	}, "", nil)
+	require.NoError(t, err)
+	return policy
+}
+
+func TestTokenLimitPolicy_Transfer(t *testing.T) {
+	policy := newUSDCPolicy(t)
+	ctx := context.Background()
+	check := func(data []byte) error { return policy.Check(ctx, tokenEvalCtx(nil, token, data)) }
+	to := common.HexToAddress(recipient)
+
+	require.NoError(t, check(call(t, "transfer", to, usdc(800))))
+	err := check(call(t, "transfer", to, usdc(1200)))
+	assert.ErrorContains(t, err, "token "+token+": transfer of 1200 usdc exceeds per‑tx limit 1000 usdc")
+	err = check(call(t, "transfer", to, usdc(800)))
+	assert.ErrorContains(t, err, "daily limit exceeded for "+agent+": limit 1500 usdc, already spent 800 usdc, attempted +800 usdc")
+
+	// Approvals count: the spender can move the approved amount.
+	err = check(call(t, "approve", to, new(big.Int).Lsh(big.NewInt(1), 255)))
+	assert.ErrorContains(t, err, "approve of")
+	require.NoError(t, check(call(t, "approve", to, usdc(700))))
+	assert.ErrorContains(t, check(call(t, "approve", to, usdc(1))), "daily limit exceeded")
+
+	// Calls that cannot be decoded are denied; other methods and other
+	// tools are not limited.
+	assert.Error(t, check(call(t, "transferFrom", to, to, big.NewInt(0))[:4]))
+	require.NoError(t, check(append([]byte{0x70, 0xa0, 0x82, 0x31}, common.LeftPadBytes(to.Bytes(), 32)...))) // balanceOf
+	require.NoError(t, policy.Check(ctx, &security.EvaluationContext{Tool: "balance", Args: map[string]interface{}{"to": token}}))
+}
+
+func TestTokenLimitPolicy_TransferFrom(t *testing.T) {
+	policy := newUSDCPolicy(t)
+	ctx := context.Background()
+	check := func(data []byte) error { return policy.Check(ctx, tokenEvalCtx(nil, token, data)) }
+	from := common.HexToAddress(owner)
+
+	// The agent spends an allowance, sending the tokens to someone else.
+	require.NoError(t, check(call(t, "transferFrom", from, common.HexToAddress(recipient), usdc(1000))))
+	err := check(call(t, "transferFrom", from, common.HexToAddress(recipient), usdc(600)))
+	assert.ErrorContains(t, err, "daily limit exceeded for "+agent+": limit 1500 usdc, already spent 1000 usdc")
+
+	// Pulling tokens to the agent itself spends nothing.
+	require.NoError(t, check(call(t, "transferFrom", from, common.HexToAddress(agent), usdc(5000))))
+}
+
+func TestTokenLimitPolicy_DecimalsFromChain(t *testing.T) {
+	policy, err := policies.NewTokenLimitPolicy(map[string]*config.TokenLimitConfig{
+		dai: {MaxTransactionValue: tokens(t, "100")},
+	}, "", nil)
+	require.NoError(t, err)
+	chain := &decimalsChain{}
+	ctx := context.Background()
+	to := common.HexToAddress(recipient)
+
+	require.NoError(t, policy.Check(ctx, tokenEvalCtx(chain, dai, call(t, "transfer", to, new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))))))
+	err = policy.Check(ctx, tokenEvalCtx(chain, dai, call(t, "transfer", to, new(big.Int).Mul(big.NewInt(101), big.NewInt(1e18)))))
+	assert.ErrorContains(t, err, "transfer of 101 exceeds per‑tx limit 100")
+	assert.Equal(t, 1, chain.calls, "decimals are read once")
+
+	fresh, err := policies.NewTokenLimitPolicy(map[string]*config.TokenLimitConfig{dai: {MaxTransactionValue: tokens(t, "100")}}, "", nil)
+	require.NoError(t, err)
+	err = fresh.Check(ctx, tokenEvalCtx(nil, dai, call(t, "transfer", to, big.NewInt(1))))
+	assert.ErrorContains(t, err, "decimals not configured")
+}
+
+// sixDecimalsChain answers decimals() calls with 6.
+type sixDecimalsChain struct{ decimalsChain }
+
+func (c *sixDecimalsChain) CallContract(ctx context.Context, call *blockchain.ContractCall) ([]byte, error) {
+	c.calls++
+	return common.LeftPadBytes([]byte{6}, 32), nil
+}
+
+func TestTokenLimitPolicy_PerChain(t *testing.T) {
+	policy, err := policies.NewTokenLimitPolicy(map[string]*config.TokenLimitConfig{
+		dai: {MaxTransactionValue: tokens(t, "100"), DailyLimit: tokens(t, "150")},
+	}, "", nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+	to := common.HexToAddress(recipient)
+	ethereum, polygon := &decimalsChain{}, &sixDecimalsChain{}
+	check := func(chain blockchain.Chain, name string, amount *big.Int) error {
+		evalCtx := tokenEvalCtx(chain, dai, call(t, "transfer", to, amount))
+		evalCtx.ChainName = name
+		return policy.Check(ctx, evalCtx)
+	}
+	wei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18)) }
+
+	// The same address on another chain has its own decimals and its own
+	// daily budget.
+	require.NoError(t, check(ethereum, "ethereum", wei(100)))
+	require.NoError(t, check(polygon, "polygon", usdc(100)))
+	assert.ErrorContains(t, check(polygon, "polygon", wei(1)), "exceeds per‑tx limit 100")
+	assert.ErrorContains(t, check(polygon, "polygon", usdc(51)), "already spent 100, attempted +51")
+	assert.ErrorContains(t, check(ethereum, "ethereum", wei(51)), "already spent 100, attempted +51")
+	assert.Equal(t, 1, ethereum.calls)
+	assert.Equal(t, 1, polygon.calls)
+}
+
+func TestTokenLimitPolicy_UnknownTokens(t *testing.T) {
+	ctx := context.Background()
+	data := call(t, "transfer", common.HexToAddress(recipient), big.NewInt(5))
+
+	allow, err := policies.NewTokenLimitPolicy(nil, "allow", nil)
+	require.NoError(t, err)
+	assert.NoError(t, allow.Check(ctx, tokenEvalCtx(nil, dai, data)))
+
+	deny, err := policies.NewTokenLimitPolicy(nil, "deny", nil)
+	require.NoError(t, err)
+	assert.ErrorContains(t, deny.Check(ctx, tokenEvalCtx(nil, dai, data)), "transfer of a token without limits is not allowed")
+
+	approver := &recordingApprover{}
+	ask, err := policies.NewTokenLimitPolicy(nil, "approve", approver)
+	require.NoError(t, err)
+	assert.NoError(t, ask.Check(ctx, tokenEvalCtx(nil, dai, data)))
+	approver.err = errors.New("human rejected transaction")
+	assert.ErrorContains(t, ask.Check(ctx, tokenEvalCtx(nil, dai, data)), "human rejected")
+	require.Len(t, approver.reasons, 2)
+	assert.Equal(t, "transfer of 5 base units of token "+dai+", which has no limits", approver.reasons[0])
+
+	_, err = policies.NewTokenLimitPolicy(nil, "approve", nil)
+	assert.Error(t, err)
+	_, err = policies.NewTokenLimitPolicy(nil, "maybe", nil)
+	assert.Error(t, err)
+}
+
+func TestTokenLimitPolicy_Settle(t *testing.T) {
+	policy := newUSDCPolicy(t)
+	ctx := context.Background()
+	data := call(t, "transfer", common.HexToAddress(recipient), usdc(1000))
+
+	failed := tokenEvalCtx(nil, token, data)
+	require.NoError(t, policy.Check(ctx, failed))
+	assert.ErrorContains(t, policy.Check(ctx, tokenEvalCtx(nil, token, data)), "daily limit exceeded")
+	policy.Settle(ctx, failed, errors.New("reverted"))
+	assert.NoError(t, policy.Check(ctx, tokenEvalCtx(nil, token, data)))
+}
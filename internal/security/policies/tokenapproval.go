@@ -0,0 +1,187 @@
+// Package policies provides caps on ERC‑20 approvals.
+//
+// File: internal/security/policies/tokenapproval.go
+
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/sdk/types/units"
+)
+
+// unlimitedAllowance is the smallest allowance taken as unlimited. Tokens
+// and wallets grant MaxUint256, which many tokens never decrease.
+var unlimitedAllowance = new(big.Int).Lsh(big.NewInt(1), 255)
+
+// TokenApprovalPolicy restricts the allowances that ERC‑20 approve and
+// increaseAllowance calls grant, which let the spender move the tokens
+// later without another transaction from the agent. It denies an
+// allowance over the token's cap or to a spender not allowed, and an
+// unlimited allowance unless a human approves it, if so configured.
+// Revoking an allowance, by approving zero, is always allowed.
+type TokenApprovalPolicy struct {
+	mu       sync.Mutex
+	tokens   map[string]*approvalLimit // checksummed token address -> cap
+	spenders map[string]bool           // checksummed; empty = any
+	approver Approver                  // asks about unlimited approvals; nil = deny them
+	decimals map[string]int            // checksummed token address -> decimals read
+}
+
+// approvalLimit is the configured cap of one token.
+type approvalLimit struct {
+	tokenDecimals
+	cfg config.TokenApprovalLimit
+	max *big.Int // in base units once decimals are known
+}
+
+// NewTokenApprovalPolicy creates the policy from cfg. If cfg.OnUnlimited is
+// "approve", unlimited approvals are put to approver, which must not be nil.
+func NewTokenApprovalPolicy(cfg *config.TokenApprovalConfig, approver Approver) (*TokenApprovalPolicy, error) {
+	p := &TokenApprovalPolicy{
+		tokens:   make(map[string]*approvalLimit, len(cfg.Tokens)),
+		spenders: make(map[string]bool, len(cfg.AllowedSpenders)),
+		decimals: make(map[string]int),
+	}
+	switch cfg.OnUnlimited {
+	case "", "deny":
+	case "approve":
+		if approver == nil {
+			return nil, errors.New("token approvals: unlimited approvals need an approver")
+		}
+		p.approver = approver
+	default:
+		return nil, fmt.Errorf("token approvals: invalid on_unlimited %q", cfg.OnUnlimited)
+	}
+	for addr, limitCfg := range cfg.Tokens {
+		token, err := evm.NormalizeAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("token approvals: %w", err)
+		}
+		limit := &approvalLimit{}
+		if limitCfg != nil {
+			limit.cfg = *limitCfg
+		}
+		symbol := ""
+		if limit.cfg.MaxAllowance != nil {
+			symbol = limit.cfg.MaxAllowance.Symbol
+		}
+		if limit.tokenDecimals, err = newTokenDecimals(limit.cfg.Decimals, symbol, limit.convert); err != nil {
+			return nil, fmt.Errorf("token approvals: %s: %w", token, err)
+		}
+		p.tokens[token] = limit
+	}
+	for _, addr := range cfg.AllowedSpenders {
+		spender, err := evm.NormalizeAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("token approvals: allowed spender: %w", err)
+		}
+		p.spenders[spender] = true
+	}
+	return p, nil
+}
+
+// convert converts the configured cap to base units.
+func (l *approvalLimit) convert(decimals int) error {
+	if l.cfg.MaxAllowance != nil {
+		max, err := l.cfg.MaxAllowance.Units(decimals)
+		if err != nil {
+			return err
+		}
+		l.max = max
+	}
+	return nil
+}
+
+// Name returns "token_approvals", the policy's name in decisions and audit entries.
+func (p *TokenApprovalPolicy) Name() string { return "token_approvals" }
+
+// Prompts implements security.Prompter: the policy asks the approver
+// about unlimited approvals if so configured.
+func (p *TokenApprovalPolicy) Prompts() bool { return p.approver != nil }
+
+// Check implements security.Policy.
+func (p *TokenApprovalPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	call, err := decodeTokenCall(ctx, evalCtx)
+	if err != nil || call == nil {
+		return err
+	}
+	if call.method != "approve" && call.method != "increaseAllowance" {
+		return nil
+	}
+	token := call.token
+	spenderAddr, _ := call.args["spender"].(common.Address)
+	spender := spenderAddr.Hex()
+	amount, _ := call.args["amount"].(*big.Int)
+	if amount == nil || amount.Sign() == 0 {
+		return nil // a revocation, or no change
+	}
+
+	if len(p.spenders) > 0 && !p.spenders[spender] {
+		return &security.ErrAddressBlocked{
+			Address: spender, List: "allowed_spenders",
+			Reason: fmt.Sprintf("token %s: %s of %s to spender %s: spender is not in allowed_spenders",
+				token, call.method, p.format(ctx, evalCtx, token, amount), spender),
+		}
+	}
+	if amount.Cmp(unlimitedAllowance) >= 0 {
+		if p.approver != nil {
+			return p.approver.Approve(ctx, evalCtx, fmt.Sprintf("unlimited %s of token %s to spender %s", call.method, token, spender))
+		}
+		return fmt.Errorf("token %s: unlimited %s to spender %s is not allowed", token, call.method, spender)
+	}
+
+	limit, ok := p.tokens[token]
+	if !ok || limit.cfg.MaxAllowance == nil {
+		return nil
+	}
+	if err := limit.resolveDecimals(ctx, evalCtx, token, &p.mu); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if amount.Cmp(limit.max) > 0 {
+		return &security.ErrLimitExceeded{
+			Name: "max_allowance", Unit: "token", Token: token, Limit: limit.max, Attempted: amount,
+			Reason: fmt.Sprintf("token %s: %s of %s to spender %s exceeds max_allowance %s",
+				token, call.method, limit.format(amount), spender, limit.format(limit.max)),
+		}
+	}
+	return nil
+}
+
+// format renders a base unit amount of a token in whole tokens if its
+// decimals are configured or can be read, and in base units otherwise.
+func (p *TokenApprovalPolicy) format(ctx context.Context, evalCtx *security.EvaluationContext, token string, v *big.Int) string {
+	if limit, ok := p.tokens[token]; ok && limit.resolveDecimals(ctx, evalCtx, token, &p.mu) == nil {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return limit.format(v)
+	}
+	p.mu.Lock()
+	decimals, ok := p.decimals[token]
+	p.mu.Unlock()
+	if !ok && evalCtx.Chain() != nil {
+		if d, err := readDecimals(ctx, evalCtx, token); err == nil {
+			decimals, ok = d, true
+			p.mu.Lock()
+			p.decimals[token] = d
+			p.mu.Unlock()
+		}
+	}
+	if !ok {
+		return v.String() + " base units"
+	}
+	return units.FormatUnits(v, decimals, -1)
+}
+
+// EOF: internal/security/policies/tokenapproval.go
@@ -0,0 +1,103 @@
+package policies_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+func newApprovalPolicy(t *testing.T, onUnlimited string, approver policies.Approver) *policies.TokenApprovalPolicy {
+	t.Helper()
+	decimals := uint8(6)
+	policy, err := policies.NewTokenApprovalPolicy(&config.TokenApprovalConfig{
+		Tokens:          map[string]*config.TokenApprovalLimit{token: {MaxAllowance: tokens(t, "1000 usdc"), Decimals: &decimals}},
+		AllowedSpenders: []string{vault},
+		OnUnlimited:     onUnlimited,
+	}, approver)
+	require.NoError(t, err)
+	return policy
+}
+
+func TestTokenApprovalPolicy_Caps(t *testing.T) {
+	policy := newApprovalPolicy(t, "", nil)
+	ctx := context.Background()
+	check := func(method string, spender string, amount *big.Int) error {
+		return policy.Check(ctx, tokenEvalCtx(nil, token, call(t, method, common.HexToAddress(spender), amount)))
+	}
+
+	require.NoError(t, check("approve", vault, usdc(1000)))
+	err := check("increaseAllowance", vault, usdc(5000))
+	assert.EqualError(t, err, "token "+token+": increaseAllowance of 5000 usdc to spender "+vault+" exceeds max_allowance 1000 usdc")
+	var exceeded *security.ErrLimitExceeded
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, "max_allowance", exceeded.Name)
+	assert.Equal(t, usdc(1000), exceeded.Limit)
+
+	err = check("approve", owner, usdc(10))
+	assert.EqualError(t, err, "token "+token+": approve of 10 usdc to spender "+owner+": spender is not in allowed_spenders")
+	var blocked *security.ErrAddressBlocked
+	require.ErrorAs(t, err, &blocked)
+	assert.Equal(t, "allowed_spenders", blocked.List)
+
+	assert.ErrorContains(t, check("approve", vault, math.MaxBig256), "unlimited approve to spender "+vault+" is not allowed")
+	assert.NoError(t, check("approve", owner, big.NewInt(0)), "revoking is always allowed")
+	assert.NoError(t, check("transfer", owner, usdc(5000)), "transfers are for token_limits")
+}
+
+func TestTokenApprovalPolicy_Unlimited(t *testing.T) {
+	approver := &recordingApprover{}
+	policy := newApprovalPolicy(t, "approve", approver)
+	assert.True(t, policy.Prompts())
+	ctx := context.Background()
+	data := call(t, "approve", common.HexToAddress(vault), math.MaxBig256)
+
+	require.NoError(t, policy.Check(ctx, tokenEvalCtx(nil, token, data)))
+	approver.err = errors.New("human rejected transaction")
+	assert.ErrorContains(t, policy.Check(ctx, tokenEvalCtx(nil, token, data)), "human rejected")
+	require.Len(t, approver.reasons, 2)
+	assert.Equal(t, "unlimited approve of token "+token+" to spender "+vault, approver.reasons[0])
+
+	_, err := policies.NewTokenApprovalPolicy(&config.TokenApprovalConfig{OnUnlimited: "approve"}, nil)
+	assert.Error(t, err)
+	_, err = policies.NewTokenApprovalPolicy(&config.TokenApprovalConfig{OnUnlimited: "maybe"}, approver)
+	assert.Error(t, err)
+}
+
+func TestTokenApprovalPolicy_DecimalsFromChain(t *testing.T) {
+	policy, err := policies.NewTokenApprovalPolicy(&config.TokenApprovalConfig{
+		Tokens: map[string]*config.TokenApprovalLimit{dai: {MaxAllowance: tokens(t, "100 dai")}},
+	}, nil)
+	require.NoError(t, err)
+	chain := &decimalsChain{}
+	ctx := context.Background()
+	spender := common.HexToAddress(vault)
+
+	require.NoError(t, policy.Check(ctx, tokenEvalCtx(chain, dai, call(t, "approve", spender, ether(100)))))
+	err = policy.Check(ctx, tokenEvalCtx(chain, dai, call(t, "approve", spender, ether(150))))
+	assert.ErrorContains(t, err, "approve of 150 dai to spender "+vault+" exceeds max_allowance 100 dai")
+	assert.Equal(t, 1, chain.calls, "decimals are read once")
+
+	// Without decimals, amounts to spenders not allowed are in base units.
+	strict, err := policies.NewTokenApprovalPolicy(&config.TokenApprovalConfig{AllowedSpenders: []string{owner}}, nil)
+	require.NoError(t, err)
+	err = strict.Check(ctx, tokenEvalCtx(nil, dai, call(t, "approve", spender, big.NewInt(5))))
+	assert.ErrorContains(t, err, "approve of 5 base units to spender "+vault)
+}
+
+func TestTokenLimitPolicy_IncreaseAllowance(t *testing.T) {
+	policy := newUSDCPolicy(t)
+	ctx := context.Background()
+
+	err := policy.Check(ctx, tokenEvalCtx(nil, token, call(t, "increaseAllowance", common.HexToAddress(recipient), usdc(1001))))
+	assert.ErrorContains(t, err, "increaseAllowance of 1001 usdc exceeds per‑tx limit 1000 usdc")
+}
@@ -0,0 +1,156 @@
+// Package policies provides the tool allowlist/denylist policy.
+//
+// File: internal/security/policies/tool.go
+
+package policies
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// ToolBlockedError is the denial of a tool by ToolPolicy. It reaches the
+// caller of Engine.Execute wrapped, so errors.As finds it.
+type ToolBlockedError struct {
+	Tool string
+	// Pattern is the blocked_tools entry the tool matched, or "" if the
+	// tool is not in allowed_tools.
+	Pattern string
+}
+
+// Error names the tool and why it is blocked.
+func (e *ToolBlockedError) Error() string {
+	if e.Pattern == "" {
+		return fmt.Sprintf("tools: tool %q is not in allowed_tools", e.Tool)
+	}
+	return fmt.Sprintf("tools: tool %q is blocked by %q", e.Tool, e.Pattern)
+}
+
+// toolPatterns matches tool names against exact names and glob patterns
+// (see path.Match).
+type toolPatterns struct {
+	names map[string]bool
+	globs []string
+}
+
+func newToolPatterns(key string, patterns []string) (toolPatterns, error) {
+	m := toolPatterns{names: make(map[string]bool, len(patterns))}
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil || p == "" {
+			return m, fmt.Errorf("tools: %s: invalid tool pattern %q", key, p)
+		}
+		if strings.ContainsAny(p, `*?[\`) {
+			m.globs = append(m.globs, p)
+		} else {
+			m.names[p] = true
+		}
+	}
+	return m, nil
+}
+
+// match returns the pattern tool matches, if any.
+func (m toolPatterns) match(tool string) (string, bool) {
+	if m.names[tool] {
+		return tool, true
+	}
+	for _, g := range m.globs {
+		if ok, _ := path.Match(g, tool); ok {
+			return g, true
+		}
+	}
+	return "", false
+}
+
+func (m toolPatterns) empty() bool { return len(m.names) == 0 && len(m.globs) == 0 }
+
+// ToolPolicy restricts the tools an agent may execute, so that an agent
+// deployed for one role cannot run the tools of another, whatever its
+// prompt says. A tool is denied if it matches a blocked pattern, or if
+// allowed patterns are given and it matches none. Denials are
+// *ToolBlockedError and are counted in tool_blocked_total per tool.
+type ToolPolicy struct {
+	allowed, blocked toolPatterns
+
+	mu      sync.Mutex
+	metrics observe.Metrics // nil = not counted
+}
+
+// NewToolPolicy creates a policy from the allowed_tools and blocked_tools
+// entries: tool names, or glob patterns such as "erc20_*".
+func NewToolPolicy(allowed, blocked []string) (*ToolPolicy, error) {
+	a, err := newToolPatterns("allowed_tools", allowed)
+	if err != nil {
+		return nil, err
+	}
+	b, err := newToolPatterns("blocked_tools", blocked)
+	if err != nil {
+		return nil, err
+	}
+	return &ToolPolicy{allowed: a, blocked: b}, nil
+}
+
+// SetMetrics sets the metrics that count blocked tools.
+func (p *ToolPolicy) SetMetrics(metrics observe.Metrics) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.metrics = metrics
+}
+
+// Name returns "tools", the policy's name in decisions and audit entries.
+func (p *ToolPolicy) Name() string { return "tools" }
+
+// Check implements security.Policy.
+func (p *ToolPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	var err *ToolBlockedError
+	if pattern, ok := p.blocked.match(evalCtx.Tool); ok {
+		err = &ToolBlockedError{Tool: evalCtx.Tool, Pattern: pattern}
+	} else if _, ok := p.allowed.match(evalCtx.Tool); !ok && !p.allowed.empty() {
+		err = &ToolBlockedError{Tool: evalCtx.Tool}
+	} else {
+		return nil
+	}
+	p.mu.Lock()
+	metrics := p.metrics
+	p.mu.Unlock()
+	if metrics != nil {
+		metrics.Counter("tool_blocked_total", 1, map[string]string{"tool": evalCtx.Tool})
+	}
+	return err
+}
+
+// Unknown returns the allowed_tools and blocked_tools entries that match
+// none of the registered tools, most likely typos, sorted.
+func (p *ToolPolicy) Unknown(registered []string) []string {
+	var unknown []string
+	for _, m := range []toolPatterns{p.allowed, p.blocked} {
+		for name := range m.names {
+			if !slices.Contains(registered, name) {
+				unknown = append(unknown, name)
+			}
+		}
+		for _, g := range m.globs {
+			matched := false
+			for _, tool := range registered {
+				if ok, _ := path.Match(g, tool); ok {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				unknown = append(unknown, g)
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// EOF: internal/security/policies/tool.go
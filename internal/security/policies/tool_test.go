@@ -0,0 +1,80 @@
+package policies_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+// toolCounter records the tools counted in tool_blocked_total.
+type toolCounter struct {
+	observe.NoopMetrics
+	blocked []string
+}
+
+func (c *toolCounter) Counter(name string, value float64, labels ...map[string]string) {
+	if name == "tool_blocked_total" {
+		c.blocked = append(c.blocked, labels[0]["tool"])
+	}
+}
+
+func TestToolPolicy(t *testing.T) {
+	ctx := context.Background()
+	check := func(p *policies.ToolPolicy, tool string) error {
+		return p.Check(ctx, &security.EvaluationContext{Tool: tool})
+	}
+
+	t.Run("allowlist", func(t *testing.T) {
+		p, err := policies.NewToolPolicy([]string{"balance", "erc20_*"}, nil)
+		require.NoError(t, err)
+		assert.NoError(t, check(p, "balance"))
+		assert.NoError(t, check(p, "erc20_balance"))
+		err = check(p, "transfer")
+		var blocked *policies.ToolBlockedError
+		require.True(t, errors.As(err, &blocked))
+		assert.Equal(t, &policies.ToolBlockedError{Tool: "transfer"}, blocked)
+		assert.EqualError(t, err, `tools: tool "transfer" is not in allowed_tools`)
+	})
+
+	t.Run("denylist", func(t *testing.T) {
+		p, err := policies.NewToolPolicy([]string{"erc20_*"}, []string{"deploy", "erc20_approve*"})
+		require.NoError(t, err)
+		assert.NoError(t, check(p, "erc20_transfer"))
+		assert.EqualError(t, check(p, "erc20_approve_max"), `tools: tool "erc20_approve_max" is blocked by "erc20_approve*"`,
+			"blocked wins over allowed")
+		assert.EqualError(t, check(p, "deploy"), `tools: tool "deploy" is blocked by "deploy"`)
+
+		open, err := policies.NewToolPolicy(nil, []string{"transfer"})
+		require.NoError(t, err)
+		assert.NoError(t, check(open, "send"), "any tool without allowed_tools")
+		assert.Error(t, check(open, "transfer"))
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		p, err := policies.NewToolPolicy([]string{"balance"}, []string{"deploy"})
+		require.NoError(t, err)
+		metrics := &toolCounter{}
+		p.SetMetrics(metrics)
+		_ = check(p, "balance")
+		_ = check(p, "deploy")
+		_ = check(p, "transfer")
+		assert.Equal(t, []string{"deploy", "transfer"}, metrics.blocked)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		p, err := policies.NewToolPolicy([]string{"balance", "erc20_*", "ballance"}, []string{"deploy", "swap_*"})
+		require.NoError(t, err)
+		registered := []string{"balance", "transfer", "deploy", "erc20_transfer"}
+		assert.Equal(t, []string{"ballance", "swap_*"}, p.Unknown(registered))
+
+		_, err = policies.NewToolPolicy([]string{"erc20_["}, nil)
+		assert.Error(t, err)
+	})
+}
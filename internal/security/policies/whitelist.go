@@ -6,35 +6,161 @@ package policies
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
 	"github.com/0xSemantic/lola-os/internal/security"
 )
 
+// contractPrefix marks an entry that matches every call to a contract,
+// whatever the call's inner recipient.
+const contractPrefix = "contract:"
+
+// whitelistNameTTL is how long the addresses of ENS entries are cached.
+const whitelistNameTTL = evm.DefaultENSCacheTTL
+
 // WhitelistPolicy restricts destination addresses for write operations.
+//
+// An operation's destinations are its target and, for ERC‑20 transfer,
+// transferFrom and approve calls, the inner recipient or spender. Each
+// list holds addresses, ENS names and "contract:<address>" entries:
+//
+//   - An address matches the destination it names, whatever its case; a
+//     mixed‑case destination with an invalid EIP‑55 checksum is denied.
+//   - A name matches a destination given as that name, or the address it
+//     resolves to through the session's chain, cached for a few minutes.
+//   - "contract:<address>" matches any call whose target is the contract,
+//     regardless of its inner recipient.
+//
+// An operation is denied if any destination is blocked, and the blocklist
+// wins over the allowlist. With a non‑empty allowlist, an operation is
+// allowed only if a contract entry matches its target or every destination
+// is allowed.
 type WhitelistPolicy struct {
-	allowed map[string]bool
-	blocked map[string]bool
+	allowed addressList
+	blocked addressList
+
+	mu    sync.Mutex
+	names map[nameKey]resolvedName // ENS entries resolved, by chain
+}
+
+// addressList is the entries of one list.
+type addressList struct {
+	addrs     map[common.Address]bool
+	names     map[string]bool // lowercase ENS names
+	contracts map[common.Address]bool
+}
+
+type nameKey struct {
+	chain string
+	name  string
+}
+
+type resolvedName struct {
+	addr    common.Address
+	expires time.Time
+}
+
+// destination is an address an operation sends to or calls.
+type destination struct {
+	display string
+	name    string // lowercase, if given as a name
+	addr    common.Address
 }
 
-// NewWhitelistPolicy creates a policy with allowed and blocked address sets.
-// If allowed is non‑empty, only those addresses are permitted.
-// Blocked addresses are always denied, even if also in allowed (allowed takes precedence).
-func NewWhitelistPolicy(allowed, blocked []string) *WhitelistPolicy {
-	allowedSet := make(map[string]bool)
-	for _, addr := range allowed {
-		allowedSet[addr] = true
+// blockedBy returns the denial of d by list.
+func (d destination) blockedBy(list, reason string) *security.ErrAddressBlocked {
+	return &security.ErrAddressBlocked{Address: d.addr.Hex(), Name: d.name, List: list, Reason: reason}
+}
+
+// NewWhitelistPolicy creates a policy with allowed and blocked entries.
+// If allowed is non‑empty, only those destinations are permitted. Blocked
+// entries are always denied, even if also allowed. An entry that is not a
+// valid address, ENS name or contract entry is an error.
+func NewWhitelistPolicy(allowed, blocked []string) (*WhitelistPolicy, error) {
+	allowedList, err := newAddressList(allowed)
+	if err != nil {
+		return nil, fmt.Errorf("allowed_addresses: %w", err)
 	}
-	blockedSet := make(map[string]bool)
-	for _, addr := range blocked {
-		blockedSet[addr] = true
+	blockedList, err := newAddressList(blocked)
+	if err != nil {
+		return nil, fmt.Errorf("blocked_addresses: %w", err)
 	}
 	return &WhitelistPolicy{
-		allowed: allowedSet,
-		blocked: blockedSet,
+		allowed: allowedList,
+		blocked: blockedList,
+		names:   make(map[nameKey]resolvedName),
+	}, nil
+}
+
+// parseWhitelistEntry checks an allowed_addresses or blocked_addresses
+// entry: it returns the address of an address or contract entry in
+// checksummed form, with contract true for the latter, or the lowercase
+// name of an ENS entry.
+func parseWhitelistEntry(entry string) (value string, contract bool, err error) {
+	if rest, ok := cutPrefixFold(entry, contractPrefix); ok {
+		addr, err := evm.NormalizeAddress(strings.TrimSpace(rest))
+		if err != nil {
+			return "", false, fmt.Errorf("entry %q: %w", entry, err)
+		}
+		return addr, true, nil
 	}
+	if common.IsHexAddress(entry) {
+		addr, err := evm.NormalizeAddress(entry)
+		if err != nil {
+			return "", false, fmt.Errorf("entry %q: %w", entry, err)
+		}
+		return addr, false, nil
+	}
+	if evm.IsENSName(entry) {
+		return strings.ToLower(entry), false, nil
+	}
+	return "", false, fmt.Errorf("entry %q: not an address, ENS name or contract:<address>", entry)
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
 }
 
+func newAddressList(entries []string) (addressList, error) {
+	l := addressList{
+		addrs:     make(map[common.Address]bool),
+		names:     make(map[string]bool),
+		contracts: make(map[common.Address]bool),
+	}
+	for _, entry := range entries {
+		value, contract, err := parseWhitelistEntry(entry)
+		switch {
+		case err != nil:
+			return addressList{}, err
+		case contract:
+			l.contracts[common.HexToAddress(value)] = true
+		case common.IsHexAddress(value):
+			l.addrs[common.HexToAddress(value)] = true
+		default:
+			l.names[value] = true
+		}
+	}
+	return l, nil
+}
+
+func (l addressList) empty() bool {
+	return len(l.addrs) == 0 && len(l.names) == 0 && len(l.contracts) == 0
+}
+
+// Name returns "whitelist", the policy's name in decisions and audit entries.
+func (p *WhitelistPolicy) Name() string { return "whitelist" }
+
 // Check implements security.Policy.
 func (p *WhitelistPolicy) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
 	// Extract 'to' address.
@@ -46,18 +172,155 @@ func (p *WhitelistPolicy) Check(ctx context.Context, evalCtx *security.Evaluatio
 	if !ok {
 		return nil // not a string
 	}
+	if p.allowed.empty() && p.blocked.empty() {
+		return nil
+	}
+	// A cancellation is a transfer of 0 from the pending transaction's
+	// sender, the signing account, to itself: it sends nothing to anyone.
+	if evalCtx.Tool == "cancel" && common.IsHexAddress(to) &&
+		common.HexToAddress(to) == common.HexToAddress(evalCtx.Signer()) {
+		return nil
+	}
 
-	// Check whitelist.
-	if len(p.allowed) > 0 {
-		if !p.allowed[to] {
-			return fmt.Errorf("address %s not in whitelist", to)
-		}
+	target, err := targetDestination(ctx, evalCtx, to)
+	if err != nil {
+		return err
+	}
+	dests := []destination{target}
+	if inner, ok := innerRecipient(evalCtx); ok {
+		dests = append(dests, inner)
 	}
+
 	// Check blacklist.
-	if p.blocked[to] {
-		return fmt.Errorf("address %s is blocked", to)
+	if p.blocked.contracts[target.addr] {
+		return target.blockedBy("blocked_addresses", fmt.Sprintf("contract %s is blocked", target.display))
+	}
+	for _, d := range dests {
+		blocked, err := p.matches(ctx, evalCtx, p.blocked, d)
+		if err != nil {
+			return fmt.Errorf("address %s: cannot check blocked names: %w", d.display, err)
+		}
+		if blocked {
+			return d.blockedBy("blocked_addresses", fmt.Sprintf("address %s is blocked", d.display))
+		}
+	}
+	// Check whitelist.
+	if p.allowed.empty() || p.allowed.contracts[target.addr] {
+		return nil
+	}
+	for _, d := range dests {
+		allowed, err := p.matches(ctx, evalCtx, p.allowed, d)
+		if err != nil {
+			return d.blockedBy("allowed_addresses", fmt.Sprintf("address %s not in whitelist (%v)", d.display, err))
+		}
+		if !allowed {
+			return d.blockedBy("allowed_addresses", fmt.Sprintf("address %s not in whitelist", d.display))
+		}
 	}
 	return nil
 }
 
-// EOF: internal/security/policies/whitelist.go
\ No newline at end of file
+// targetDestination is the operation's target, to, resolved if a name.
+func targetDestination(ctx context.Context, evalCtx *security.EvaluationContext, to string) (destination, error) {
+	if common.IsHexAddress(to) {
+		addr, err := evm.NormalizeAddress(to)
+		if err != nil {
+			return destination{}, err
+		}
+		return destination{display: to, addr: common.HexToAddress(addr)}, nil
+	}
+	// A name is checked both as given and as the address it resolves to.
+	resolved, err := resolveName(ctx, evalCtx, to)
+	if err != nil {
+		return destination{}, err
+	}
+	return destination{
+		display: fmt.Sprintf("%s (%s)", to, resolved),
+		name:    strings.ToLower(to),
+		addr:    common.HexToAddress(resolved),
+	}, nil
+}
+
+// innerRecipient returns the recipient of an ERC‑20 transfer or
+// transferFrom call, or the spender of an approve or increaseAllowance
+// call.
+func innerRecipient(evalCtx *security.EvaluationContext) (destination, bool) {
+	_, args, err := tokenCallData(evalCtx)
+	if err != nil || args == nil {
+		return destination{}, false
+	}
+	for _, name := range []string{"to", "spender"} {
+		if addr, ok := args[name].(common.Address); ok {
+			return destination{display: addr.Hex(), addr: addr}, true
+		}
+	}
+	return destination{}, false
+}
+
+// matches reports whether d is in list. Names in list are resolved on the
+// session's chain; on a chain that cannot resolve names, they match only
+// destinations given by name.
+func (p *WhitelistPolicy) matches(ctx context.Context, evalCtx *security.EvaluationContext, list addressList, d destination) (bool, error) {
+	if list.addrs[d.addr] || (d.name != "" && list.names[d.name]) {
+		return true, nil
+	}
+	if len(list.names) == 0 {
+		return false, nil
+	}
+	resolver, ok := evalCtx.Chain().(blockchain.NameResolver)
+	if !ok {
+		return false, nil
+	}
+	var errs []error
+	for name := range list.names {
+		addr, err := p.resolveEntry(ctx, evalCtx.ChainName, resolver, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if addr == d.addr {
+			return true, nil
+		}
+	}
+	return false, errors.Join(errs...)
+}
+
+// resolveEntry returns the address of the ENS entry name on chain, from
+// the cache if resolved recently.
+func (p *WhitelistPolicy) resolveEntry(ctx context.Context, chain string, resolver blockchain.NameResolver, name string) (common.Address, error) {
+	key := nameKey{chain: strings.ToLower(chain), name: name}
+	now := time.Now()
+	p.mu.Lock()
+	cached, ok := p.names[key]
+	p.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.addr, nil
+	}
+	resolved, err := resolver.ResolveName(ctx, name)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("name %s: %w", name, err)
+	}
+	if !common.IsHexAddress(resolved) {
+		return common.Address{}, fmt.Errorf("name %s: resolved to invalid address %q", name, resolved)
+	}
+	addr := common.HexToAddress(resolved)
+	p.mu.Lock()
+	p.names[key] = resolvedName{addr: addr, expires: now.Add(whitelistNameTTL)}
+	p.mu.Unlock()
+	return addr, nil
+}
+
+// resolveName resolves name with the session's chain.
+func resolveName(ctx context.Context, evalCtx *security.EvaluationContext, name string) (string, error) {
+	resolver, ok := evalCtx.Chain().(blockchain.NameResolver)
+	if !ok {
+		return "", fmt.Errorf("could not resolve name %q: chain does not support name resolution", name)
+	}
+	addr, err := resolver.ResolveName(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("address %s: %w", name, err)
+	}
+	return addr, nil
+}
+
+// EOF: internal/security/policies/whitelist.go
@@ -0,0 +1,153 @@
+package policies_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+)
+
+// resolvingChain is a chain that resolves names from a fixed table.
+type resolvingChain struct {
+	blockchain.Chain
+	names    map[string]string
+	resolved atomic.Int32 // number of lookups
+}
+
+func (c *resolvingChain) ResolveName(ctx context.Context, name string) (string, error) {
+	c.resolved.Add(1)
+	if addr, ok := c.names[name]; ok {
+		return addr, nil
+	}
+	return "", errors.New("could not resolve name")
+}
+
+// chainSession is a session carrying a chain.
+type chainSession struct {
+	mockSession
+	chain blockchain.Chain
+}
+
+func (s *chainSession) GetChain() blockchain.Chain { return s.chain }
+
+func newWhitelist(t *testing.T, allowed, blocked []string) *policies.WhitelistPolicy {
+	p, err := policies.NewWhitelistPolicy(allowed, blocked)
+	require.NoError(t, err)
+	return p
+}
+
+func TestWhitelistPolicy_ENSNames(t *testing.T) {
+	const (
+		vitalik = "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"
+		blocked = "0x000000000000000000000000000000000000dEaD"
+	)
+	sess := &chainSession{chain: &resolvingChain{names: map[string]string{
+		"vitalik.eth": vitalik,
+		"burn.eth":    blocked,
+	}}}
+	check := func(p *policies.WhitelistPolicy, to string, session interface{}) error {
+		return p.Check(context.Background(), &security.EvaluationContext{
+			Tool:    "transfer",
+			Args:    map[string]interface{}{"to": to},
+			Session: session,
+		})
+	}
+
+	allowAddr := newWhitelist(t, []string{vitalik}, nil)
+	assert.NoError(t, check(allowAddr, "vitalik.eth", sess), "resolved address is allowed")
+	assert.NoError(t, check(allowAddr, "0xd8da6bf26964af9d7eed9e03e53415d37aa96045", sess), "case‑insensitive")
+	assert.ErrorContains(t, check(allowAddr, "burn.eth", sess), "not in whitelist")
+	assert.ErrorContains(t, check(allowAddr, "0xD8dA6BF26964aF9D7eEd9e03E53415D37aA96045", sess), "invalid address checksum")
+
+	allowName := newWhitelist(t, []string{"Vitalik.eth"}, nil)
+	assert.NoError(t, check(allowName, "vitalik.eth", sess))
+
+	block := newWhitelist(t, nil, []string{blocked})
+	assert.ErrorContains(t, check(block, "burn.eth", sess), "burn.eth ("+blocked+") is blocked")
+	assert.NoError(t, check(block, "vitalik.eth", sess))
+
+	assert.ErrorContains(t, check(block, "nobody.eth", sess), "could not resolve name")
+	assert.ErrorContains(t, check(block, "vitalik.eth", &mockSession{}), "could not resolve name")
+
+	// Name entries match the address they resolve to, looked up once.
+	chain := sess.chain.(*resolvingChain)
+	byName := newWhitelist(t, []string{"vitalik.eth"}, []string{"burn.eth"})
+	before := chain.resolved.Load()
+	assert.NoError(t, check(byName, vitalik, sess))
+	assert.NoError(t, check(byName, vitalik, sess))
+	assert.ErrorContains(t, check(byName, blocked, sess), "is blocked")
+	assert.Equal(t, int32(2), chain.resolved.Load()-before, "each name resolved once")
+
+	// A blocked name that cannot be resolved denies.
+	unresolvable := newWhitelist(t, nil, []string{"gone.eth"})
+	assert.ErrorContains(t, check(unresolvable, vitalik, sess), "cannot check blocked names")
+}
+
+func TestWhitelistPolicy_Entries(t *testing.T) {
+	const (
+		friend   = "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"
+		stranger = "0x000000000000000000000000000000000000dEaD"
+	)
+	check := func(p *policies.WhitelistPolicy, to string) error {
+		return p.Check(context.Background(), &security.EvaluationContext{
+			Tool: "transfer",
+			Args: map[string]interface{}{"to": to},
+		})
+	}
+
+	// Entries and destinations in any case match.
+	lower := newWhitelist(t, []string{"0xd8da6bf26964af9d7eed9e03e53415d37aa96045"}, nil)
+	assert.NoError(t, check(lower, friend))
+	upper := newWhitelist(t, []string{"0xD8DA6BF26964AF9D7EED9E03E53415D37AA96045"}, nil)
+	assert.NoError(t, check(upper, "0xd8da6bf26964af9d7eed9e03e53415d37aa96045"))
+	assert.ErrorContains(t, check(upper, stranger), "not in whitelist")
+
+	// The blacklist wins.
+	both := newWhitelist(t, []string{friend}, []string{"0xD8DA6BF26964AF9D7EED9E03E53415D37AA96045"})
+	assert.ErrorContains(t, check(both, friend), "is blocked")
+
+	// Invalid entries are refused.
+	for _, entry := range []string{"0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96046x", "0xD8dA6BF26964aF9D7eEd9e03E53415D37aA96045", "friend", "contract:vitalik.eth"} {
+		_, err := policies.NewWhitelistPolicy(nil, []string{entry})
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestWhitelistPolicy_TokenCalls(t *testing.T) {
+	const stranger = "0x000000000000000000000000000000000000dEaD"
+	ctx := context.Background()
+	send := func(p *policies.WhitelistPolicy, method, to string) error {
+		return p.Check(ctx, tokenEvalCtx(nil, token, call(t, method, common.HexToAddress(to), big.NewInt(5))))
+	}
+
+	// The inner recipient or spender must be allowed too, and is checked
+	// for blocks.
+	plain := newWhitelist(t, []string{token, recipient}, nil)
+	assert.NoError(t, send(plain, "transfer", recipient))
+	assert.ErrorContains(t, send(plain, "transfer", stranger), "address "+stranger+" not in whitelist")
+	assert.ErrorContains(t, send(plain, "approve", stranger), "not in whitelist")
+	blocked := newWhitelist(t, nil, []string{stranger})
+	err := send(blocked, "transfer", stranger)
+	assert.ErrorContains(t, err, "is blocked")
+	var addrErr *security.ErrAddressBlocked
+	require.ErrorAs(t, err, &addrErr)
+	assert.Equal(t, &security.ErrAddressBlocked{Address: stranger, List: "blocked_addresses", Reason: err.Error()}, addrErr)
+	assert.NoError(t, send(blocked, "transfer", recipient))
+
+	// A contract entry allows any call to the contract.
+	contract := newWhitelist(t, []string{"contract:" + token}, nil)
+	assert.NoError(t, send(contract, "transfer", stranger))
+	assert.NoError(t, send(contract, "approve", recipient))
+	assert.ErrorContains(t, send(newWhitelist(t, []string{"contract:" + dai}, nil), "transfer", recipient), "not in whitelist")
+	blockedContract := newWhitelist(t, []string{"contract:" + token}, []string{"Contract:" + token})
+	assert.ErrorContains(t, send(blockedContract, "transfer", recipient), "contract "+token+" is blocked")
+}
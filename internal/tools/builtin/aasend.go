@@ -0,0 +1,91 @@
+// Package builtin provides the ERC‑4337 smart account tool.
+//
+// File: internal/tools/builtin/aasend.go
+
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm/aa"
+)
+
+// AASend makes the chain's smart account (the chain's bundler setting)
+// call to, in a user operation signed by the wallet as the account's
+// owner and submitted to the bundler, and waits for it to be included.
+// Arguments:
+//   - to:     address the account calls (string)
+//   - amount: optional value in wei the account sends (*big.Int)
+//   - data:   optional call data ([]byte)
+//
+// Policies see to, amount and data of the account's call, so value
+// limits, whitelists and human approval apply as to a normal transaction.
+// Returns map[string]string with "user_op_hash", "hash" of the bundle
+// transaction, and "status" ("success" or "reverted").
+func AASend(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	to, ok := args["to"].(string)
+	if !ok {
+		return nil, errors.New("aa_send: missing 'to' argument")
+	}
+	value := new(big.Int)
+	if amountRaw, ok := args["amount"]; ok {
+		amount, ok := amountRaw.(*big.Int)
+		if !ok {
+			return nil, errors.New("aa_send: 'amount' must be *big.Int")
+		}
+		value = amount
+	}
+	var data []byte
+	if dataRaw, ok := args["data"]; ok {
+		if data, ok = dataRaw.([]byte); !ok {
+			return nil, errors.New("aa_send: 'data' must be []byte")
+		}
+	}
+
+	target, err := sessionChain(ctx, "aa_send", args)
+	if err != nil {
+		return nil, err
+	}
+	chain, ok := target.(aa.Chain)
+	if !ok {
+		return nil, errors.New("aa_send: chain does not support user operations")
+	}
+	var cfg *evm.BundlerConfig
+	if bc, ok := target.(interface{ Bundler() *evm.BundlerConfig }); ok {
+		cfg = bc.Bundler()
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("aa_send: %w", aa.ErrNoBundler)
+	}
+	var opts []aa.Option
+	if cfg.EntryPoint != "" {
+		opts = append(opts, aa.WithEntryPoint(common.HexToAddress(cfg.EntryPoint)))
+	}
+	client, err := aa.NewAccountClient(chain, cfg.Account, cfg.URL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("aa_send: %w", err)
+	}
+
+	receipt, err := client.Send(ctx, &blockchain.Transaction{To: &to, Value: value, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("aa_send: %w", err)
+	}
+	out := map[string]string{
+		"user_op_hash": receipt.UserOpHash.Hex(),
+		"hash":         receipt.TxHash.Hex(),
+		"status":       "success",
+	}
+	if !receipt.Success {
+		out["status"] = "reverted"
+	}
+	return out, nil
+}
+
+// EOF: internal/tools/builtin/aasend.go
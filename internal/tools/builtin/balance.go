@@ -12,11 +12,11 @@ import (
 	"math/big"
 
 	"github.com/0xSemantic/lola-os/internal/blockchain"
-	"github.com/0xSemantic/lola-os/internal/core"
 )
 
 // Balance is a tool that returns the native currency balance of an address.
-// It expects an "address" argument (string) and an optional "block" argument (string).
+// It expects an "address" argument (string), an optional "block" argument (string)
+// and an optional "chain" argument naming one of the session's chains.
 // Returns *big.Int.
 func Balance(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	// 1. Extract address.
@@ -39,17 +39,14 @@ func Balance(ctx context.Context, args map[string]interface{}) (interface{}, err
 		}
 	}
 
-	// 3. Get session and chain.
-	sess := core.SessionFromContext(ctx)
-	if sess == nil {
-		return nil, errors.New("balance: no session in context")
-	}
-	if sess.Chain == nil {
-		return nil, errors.New("balance: no blockchain chain available in session")
+	// 3. Get the chain: the one named by "chain", or the session's default.
+	chain, err := sessionChain(ctx, "balance", args)
+	if err != nil {
+		return nil, err
 	}
 
 	// 4. Call GetBalance.
-	bal, err := sess.Chain.GetBalance(ctx, address, block)
+	bal, err := chain.GetBalance(ctx, address, block)
 	if err != nil {
 		return nil, fmt.Errorf("balance: %w", err)
 	}
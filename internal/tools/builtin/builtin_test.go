@@ -9,6 +9,9 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -47,6 +50,9 @@ func (m *mockChain) EstimateGas(ctx context.Context, call *blockchain.ContractCa
 	args := m.Called(ctx, call)
 	return args.Get(0).(uint64), args.Error(1)
 }
+func (m *mockChain) Close() error {
+	return nil
+}
 
 type noopLogger struct{}
 
@@ -67,11 +73,11 @@ func TestBalance(t *testing.T) {
 		ctx = core.ContextWithSession(ctx, sess)
 
 		expectedBalance := big.NewInt(12345)
-		chain.On("GetBalance", ctx, "0x742d35Cc6634C0532925a3b844Bc9e90F1A6B1E7", blockchain.BlockNumberLatest).
+		chain.On("GetBalance", ctx, "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", blockchain.BlockNumberLatest).
 			Return(expectedBalance, nil)
 
 		args := map[string]interface{}{
-			"address": "0x742d35Cc6634C0532925a3b844Bc9e90F1A6B1E7",
+			"address": "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7",
 		}
 		result, err := builtin.Balance(ctx, args)
 		require.NoError(t, err)
@@ -120,7 +126,7 @@ func TestTransfer(t *testing.T) {
 		logger := &noopLogger{}
 
 		// Setup mock chain to expect SendTransaction.
-		to := "0x742d35Cc6634C0532925a3b844Bc9e90F1A6B1E7"
+		to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
 		amount := big.NewInt(1000)
 		expectedTxHash := "0xabc123"
 
@@ -148,5 +154,58 @@ func TestTransfer(t *testing.T) {
 	// ... error cases
 }
 
+func TestSendRaw(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		To:        &to,
+		Value:     big.NewInt(1000),
+		Gas:       21000,
+		GasFeeCap: big.NewInt(1e9),
+	})
+	require.NoError(t, err)
+	raw, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	args, err := builtin.SendRawArgs(raw)
+	require.NoError(t, err)
+	assert.Equal(t, to.Hex(), args["to"])
+	assert.Equal(t, big.NewInt(1000), args["amount"])
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey).Hex(), args["from"])
+	assert.Equal(t, uint64(21000), args["gas"])
+	assert.Equal(t, big.NewInt(1e9), args["gasFeeCap"])
+	assert.NotContains(t, args, "gasPrice")
+
+	t.Run("misleading arguments", func(t *testing.T) {
+		for name, value := range map[string]interface{}{
+			"to":        "0x0000000000000000000000000000000000000001",
+			"amount":    big.NewInt(1),
+			"data":      []byte{1},
+			"gas":       uint64(1),
+			"gasFeeCap": big.NewInt(1),
+			"gasPrice":  big.NewInt(1),
+			"from":      "0x0000000000000000000000000000000000000002",
+		} {
+			forged := map[string]interface{}{}
+			for k, v := range args {
+				forged[k] = v
+			}
+			forged[name] = value
+			_, err := builtin.SendRaw(context.Background(), forged)
+			assert.ErrorContains(t, err, "does not match the raw transaction", name)
+		}
+
+		// The sender must be given, so policies know whom to charge.
+		anonymous := map[string]interface{}{}
+		for k, v := range args {
+			anonymous[k] = v
+		}
+		delete(anonymous, "from")
+		_, err := builtin.SendRaw(context.Background(), anonymous)
+		assert.ErrorContains(t, err, "'from' argument does not match the raw transaction")
+	})
+}
 
 // EOF: internal/tools/builtin/builtin_test.go
\ No newline at end of file
@@ -0,0 +1,53 @@
+// Package builtin provides the pending transaction cancellation tool.
+//
+// File: internal/tools/builtin/cancel.go
+
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// Cancel replaces a pending transaction with a zero‑value self‑transfer.
+// Arguments:
+//   - tx_hash: hash of the pending transaction to cancel (string)
+//
+// The cancellation is a transfer of 0 from the pending transaction's
+// sender to itself, and the engine fills in "from", "to", "amount" and
+// "nonce" so that policies see it as one: address whitelists do not block
+// it and limits count only its fee, against the sender; read‑only mode and
+// the kill switch still apply. Any "from", "to", "amount" or "nonce" is
+// ignored.
+// Returns the cancellation transaction hash (string).
+func Cancel(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	hashRaw, ok := args["tx_hash"]
+	if !ok {
+		return nil, errors.New("cancel: missing 'tx_hash' argument")
+	}
+	txHash, ok := hashRaw.(string)
+	if !ok {
+		return nil, errors.New("cancel: 'tx_hash' must be string")
+	}
+
+	// Get session and chain.
+	chain, err := sessionChain(ctx, "cancel", args)
+	if err != nil {
+		return nil, err
+	}
+	evmChain, ok := chain.(*evm.EVMGateway)
+	if !ok {
+		return nil, errors.New("cancel: chain is not an EVM gateway")
+	}
+
+	cancelHash, err := evmChain.CancelTransaction(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("cancel: %w", err)
+	}
+	return cancelHash, nil
+}
+
+// EOF: internal/tools/builtin/cancel.go
@@ -0,0 +1,36 @@
+// Package builtin resolves the chain a tool runs on.
+//
+// File: internal/tools/builtin/chain.go
+
+package builtin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/core"
+)
+
+// sessionChain returns the chain the tool named tool runs on: the
+// session's chain named by the optional "chain" argument, or its default
+// chain.
+func sessionChain(ctx context.Context, tool string, args map[string]interface{}) (blockchain.Chain, error) {
+	sess := core.SessionFromContext(ctx)
+	if sess == nil {
+		return nil, fmt.Errorf("%s: no session in context", tool)
+	}
+	var name string
+	if raw, ok := args["chain"]; ok {
+		if name, ok = raw.(string); !ok {
+			return nil, fmt.Errorf("%s: 'chain' must be a string", tool)
+		}
+	}
+	chain, err := sess.ChainByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", tool, err)
+	}
+	return chain, nil
+}
+
+// EOF: internal/tools/builtin/chain.go
@@ -8,16 +8,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
-	"github.com/0xSemantic/lola-os/internal/core"
 	"encoding/hex"
 )
 
 // Deploy deploys a smart contract.
 // Arguments:
-//   - bytecode: contract creation bytecode (hex string or []byte)
+//   - bytecode: contract creation bytecode, with any constructor
+//     arguments (hex string with or without 0x, or []byte)
 //   - gas:      optional gas limit (uint64)
+//   - salt:     optional 32‑byte salt ([32]byte, []byte or hex string);
+//     with one, the contract is deployed through the CREATE2 factory to
+//     an address that depends only on the salt and bytecode, and the tool
+//     waits until it is mined
 // Returns: map[string]interface{} with "tx_hash" and "contract_address".
 func Deploy(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	// Extract bytecode.
@@ -25,7 +32,9 @@ func Deploy(ctx context.Context, args map[string]interface{}) (interface{}, erro
 	switch v := args["bytecode"].(type) {
 	case string:
 		var err error
-		bytecode, err = hex.DecodeString(v)
+		// Decoded as the security policies decode it, so that the
+		// bytecode they check is the bytecode deployed.
+		bytecode, err = hex.DecodeString(strings.TrimPrefix(v, "0x"))
 		if err != nil {
 			return nil, fmt.Errorf("deploy: decode hex bytecode: %w", err)
 		}
@@ -43,18 +52,35 @@ func Deploy(ctx context.Context, args map[string]interface{}) (interface{}, erro
 		}
 	}
 
+	// Optional CREATE2 salt.
+	var salt *[32]byte
+	if saltRaw, ok := args["salt"]; ok {
+		parsed, err := deploySalt(saltRaw)
+		if err != nil {
+			return nil, err
+		}
+		salt = &parsed
+	}
+
 	// Get session and chain.
-	sess := core.SessionFromContext(ctx)
-	if sess == nil {
-		return nil, errors.New("deploy: no session in context")
+	chain, err := sessionChain(ctx, "deploy", args)
+	if err != nil {
+		return nil, err
 	}
-	evmChain, ok := sess.Chain.(*evm.EVMGateway)
+	evmChain, ok := chain.(*evm.EVMGateway)
 	if !ok {
 		return nil, errors.New("deploy: chain is not an EVM gateway")
 	}
 
 	// Deploy.
-	txHash, contractAddr, err := evmChain.DeployContract(ctx, bytecode, &evm.TxOpts{GasLimit: gas})
+	opts := &evm.TxOpts{GasLimit: gas}
+	var txHash string
+	var contractAddr common.Address
+	if salt != nil {
+		txHash, contractAddr, err = evmChain.DeployContractCreate2(ctx, common.Address{}, *salt, bytecode, opts)
+	} else {
+		txHash, contractAddr, err = evmChain.DeployContract(ctx, bytecode, opts)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("deploy: %w", err)
 	}
@@ -65,4 +91,28 @@ func Deploy(ctx context.Context, args map[string]interface{}) (interface{}, erro
 	}, nil
 }
 
+// deploySalt returns the CREATE2 salt of the salt argument.
+func deploySalt(raw interface{}) ([32]byte, error) {
+	var salt [32]byte
+	var b []byte
+	switch v := raw.(type) {
+	case [32]byte:
+		return v, nil
+	case []byte:
+		b = v
+	case string:
+		var err error
+		if b, err = hex.DecodeString(strings.TrimPrefix(v, "0x")); err != nil {
+			return salt, fmt.Errorf("deploy: decode hex salt: %w", err)
+		}
+	default:
+		return salt, errors.New("deploy: 'salt' must be [32]byte, []byte or string")
+	}
+	if len(b) != len(salt) {
+		return salt, fmt.Errorf("deploy: 'salt' must be 32 bytes, not %d", len(b))
+	}
+	copy(salt[:], b)
+	return salt, nil
+}
+
 // EOF: internal/tools/builtin/deploy.go
\ No newline at end of file
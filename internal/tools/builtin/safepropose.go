@@ -0,0 +1,118 @@
+// Package builtin provides the Safe multisig proposal tool.
+//
+// File: internal/tools/builtin/safepropose.go
+
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm/safe"
+)
+
+// SafePropose proposes a transaction of a Safe multisig, signed by the
+// wallet as one of its owners, to the chain's Safe Transaction Service
+// (the chain's safe_tx_service setting). If signatures by other owners
+// bring it to the Safe's threshold, it executes the transaction instead.
+// Arguments:
+//   - safe:       address of the Safe (string)
+//   - to:         address the Safe calls (string)
+//   - amount:     optional value in wei the Safe sends (*big.Int)
+//   - data:       optional call data ([]byte)
+//   - operation:  optional "call" (default) or "delegatecall" (string)
+//   - nonce:      optional Safe nonce, e.g. to replace a queued
+//     transaction (uint64, default the Safe's current nonce)
+//   - signatures: optional 0x‑hex signatures by other owners ([]string)
+//
+// Policies see to, amount and data of the Safe's transaction, so value
+// limits, whitelists and human approval apply to what the Safe does.
+// Returns map[string]string with "safe_tx_hash", "status" ("proposed" or
+// "executed") and, when executed, "hash" of the execution transaction.
+func SafePropose(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	safeAddress, ok := args["safe"].(string)
+	if !ok {
+		return nil, errors.New("safe_propose: missing 'safe' argument")
+	}
+	to, ok := args["to"].(string)
+	if !ok {
+		return nil, errors.New("safe_propose: missing 'to' argument")
+	}
+	value := new(big.Int)
+	if amountRaw, ok := args["amount"]; ok {
+		amount, ok := amountRaw.(*big.Int)
+		if !ok {
+			return nil, errors.New("safe_propose: 'amount' must be *big.Int")
+		}
+		value = amount
+	}
+	var data []byte
+	if dataRaw, ok := args["data"]; ok {
+		if data, ok = dataRaw.([]byte); !ok {
+			return nil, errors.New("safe_propose: 'data' must be []byte")
+		}
+	}
+	op := safe.Call
+	switch args["operation"] {
+	case nil, "call":
+	case "delegatecall":
+		op = safe.DelegateCall
+	default:
+		return nil, fmt.Errorf("safe_propose: unknown operation %v (want \"call\" or \"delegatecall\")", args["operation"])
+	}
+	var signatures [][]byte
+	if sigsRaw, ok := args["signatures"]; ok {
+		sigs, ok := sigsRaw.([]string)
+		if !ok {
+			return nil, errors.New("safe_propose: 'signatures' must be []string")
+		}
+		for i, s := range sigs {
+			sig, err := hexutil.Decode(s)
+			if err != nil {
+				return nil, fmt.Errorf("safe_propose: signature %d: %w", i, err)
+			}
+			signatures = append(signatures, sig)
+		}
+	}
+
+	target, err := sessionChain(ctx, "safe_propose", args)
+	if err != nil {
+		return nil, err
+	}
+	chain, ok := target.(safe.Chain)
+	if !ok {
+		return nil, errors.New("safe_propose: chain does not support Safe transactions")
+	}
+	var opts []safe.Option
+	if sc, ok := target.(interface{ SafeTxService() string }); ok && sc.SafeTxService() != "" {
+		opts = append(opts, safe.WithTxService(sc.SafeTxService()))
+	}
+	client, err := safe.NewSafeClient(chain, safeAddress, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("safe_propose: %w", err)
+	}
+	tx, err := client.BuildTx(ctx, to, value, data, op)
+	if err != nil {
+		return nil, fmt.Errorf("safe_propose: %w", err)
+	}
+	if nonce, ok := args["nonce"].(uint64); ok {
+		tx.Nonce = new(big.Int).SetUint64(nonce)
+	}
+
+	result, err := client.Submit(ctx, tx, signatures)
+	if err != nil {
+		return nil, fmt.Errorf("safe_propose: %w", err)
+	}
+	out := map[string]string{"safe_tx_hash": result.SafeTxHash.Hex(), "status": "proposed"}
+	if result.Executed() {
+		out["status"] = "executed"
+		out["hash"] = result.TxHash
+	}
+	return out, nil
+}
+
+// EOF: internal/tools/builtin/safepropose.go
@@ -0,0 +1,55 @@
+// Package builtin provides the general transaction sending tool.
+//
+// File: internal/tools/builtin/send.go
+
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// Send signs and broadcasts a transaction, such as a contract call that
+// carries value. It takes the arguments of Sign, plus:
+//   - simulate: optional; run the transaction with eth_call first and
+//     fail without sending if it would revert (bool)
+//   - token_id: optional; the NFT the transaction transfers or approves,
+//     ignored here but recorded with the tool call (string)
+//   - token_ids, token_amounts: optional; likewise, the ERC‑1155 token
+//     ids and amounts the transaction moves ([]string)
+//
+// Policies treat send like transfer, so value limits, whitelists and
+// human approval apply to the value attached to contract calls.
+// Returns the transaction hash (string).
+func Send(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	tx, err := transactionArgs("send", args)
+	if err != nil {
+		return nil, err
+	}
+	if tx.To == nil {
+		return nil, errors.New("send: missing 'to' argument; use deploy for contract creation")
+	}
+	if simulate, ok := args["simulate"].(bool); ok {
+		tx.Simulate = simulate
+	}
+
+	chain, err := sessionChain(ctx, "send", args)
+	if err != nil {
+		return nil, err
+	}
+	evmChain, ok := chain.(*evm.EVMGateway)
+	if !ok {
+		return nil, errors.New("send: chain is not an EVM gateway")
+	}
+
+	txHash, err := evmChain.SendTransaction(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("send: %w", err)
+	}
+	return txHash, nil
+}
+
+// EOF: internal/tools/builtin/send.go
@@ -0,0 +1,124 @@
+// Package builtin provides the pre‑signed transaction broadcast tool.
+//
+// File: internal/tools/builtin/sendraw.go
+
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// SendRawArgs decodes raw and returns the arguments for the send_raw tool:
+// the transaction itself plus the to, amount, from, data, gas and fee
+// fields it encodes, so policies evaluate the broadcast like a transfer.
+func SendRawArgs(raw []byte) (map[string]interface{}, error) {
+	decoded, err := evm.DecodeRawTransaction(raw)
+	if err != nil {
+		return nil, fmt.Errorf("send_raw: %w", err)
+	}
+	args := map[string]interface{}{
+		"raw":    raw,
+		"amount": decoded.Value,
+		"from":   decoded.From.Hex(),
+		"gas":    decoded.Tx.Gas(),
+		"data":   decoded.Tx.Data(),
+	}
+	if decoded.To != nil {
+		args["to"] = decoded.To.Hex()
+	}
+	if decoded.Tx.Type() == types.DynamicFeeTxType || decoded.Tx.Type() == types.BlobTxType {
+		args["gasFeeCap"] = decoded.Tx.GasFeeCap()
+		args["gasTipCap"] = decoded.Tx.GasTipCap()
+	} else {
+		args["gasPrice"] = decoded.Tx.GasPrice()
+	}
+	return args, nil
+}
+
+// SendRaw broadcasts a transaction signed outside the runtime.
+// Arguments:
+//   - raw:    signed transaction as bytes or 0x‑hex ([]byte or string)
+//   - to:     recipient encoded in raw (string, omit for deployment)
+//   - amount: value encoded in raw in wei (*big.Int)
+//   - from:   sender recovered from raw (string)
+//   - data:   optional input data encoded in raw ([]byte)
+//   - gas, gasPrice, gasFeeCap, gasTipCap: optional gas limit and fees
+//     encoded in raw (uint64, *big.Int)
+//
+// to, amount and from must describe the raw transaction (see SendRawArgs),
+// and so must the optional arguments if given; a mismatch is rejected so
+// policies cannot be evaded with misleading arguments, nor charged to
+// another account than the signer's; the engine fills in from. Returns
+// transaction hash (string).
+func SendRaw(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var raw []byte
+	switch v := args["raw"].(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	case nil:
+		return nil, errors.New("send_raw: missing 'raw' argument")
+	default:
+		return nil, errors.New("send_raw: 'raw' must be []byte or string")
+	}
+
+	want, err := SendRawArgs(raw)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"to", "from"} {
+		if args[name] != want[name] {
+			return nil, fmt.Errorf("send_raw: '%s' argument does not match the raw transaction", name)
+		}
+	}
+	amount, ok := args["amount"].(*big.Int)
+	if !ok || amount.Cmp(want["amount"].(*big.Int)) != 0 {
+		return nil, errors.New("send_raw: 'amount' argument does not match the raw transaction")
+	}
+	if data, ok := args["data"]; ok {
+		if b, ok := data.([]byte); !ok || !bytes.Equal(b, want["data"].([]byte)) {
+			return nil, errors.New("send_raw: 'data' argument does not match the raw transaction")
+		}
+	}
+	if gas, ok := args["gas"]; ok && gas != want["gas"] {
+		return nil, errors.New("send_raw: 'gas' argument does not match the raw transaction")
+	}
+	for _, name := range []string{"gasPrice", "gasFeeCap", "gasTipCap"} {
+		got, ok := args[name]
+		if !ok {
+			continue
+		}
+		fee, ok := got.(*big.Int)
+		encoded, _ := want[name].(*big.Int)
+		if !ok || encoded == nil || fee.Cmp(encoded) != 0 {
+			return nil, fmt.Errorf("send_raw: '%s' argument does not match the raw transaction", name)
+		}
+	}
+
+	// Get session and chain.
+	chain, err := sessionChain(ctx, "send_raw", args)
+	if err != nil {
+		return nil, err
+	}
+	evmChain, ok := chain.(*evm.EVMGateway)
+	if !ok {
+		return nil, errors.New("send_raw: chain is not an EVM gateway")
+	}
+
+	txHash, err := evmChain.SendRawTransaction(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("send_raw: %w", err)
+	}
+	return txHash, nil
+}
+
+// EOF: internal/tools/builtin/sendraw.go
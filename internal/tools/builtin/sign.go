@@ -0,0 +1,122 @@
+// Package builtin provides the offline transaction signing tool.
+//
+// File: internal/tools/builtin/sign.go
+
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// Sign builds and signs a transaction without broadcasting it.
+// Arguments:
+//   - to:        recipient or contract address (string, omit for deployment)
+//   - amount:    optional value in wei (*big.Int)
+//   - data:      optional input data ([]byte)
+//   - gas:       optional gas limit (uint64)
+//   - gasPrice:  optional gas price (*big.Int) – legacy
+//   - gasFeeCap: optional EIP‑1559 fee cap (*big.Int)
+//   - gasTipCap: optional EIP‑1559 tip (*big.Int)
+//   - nonce:     optional nonce (uint64)
+//   - accessList: optional EIP‑2930 access list ([]blockchain.AccessTuple)
+//   - from:      optional signing account of a multi‑account wallet
+//     (string, default the primary account)
+//
+// With nonce, gas and fees all given no RPC call is made. Policies treat
+// sign like transfer, so value limits and whitelists apply.
+// Returns map[string]string with "raw" (0x‑hex RLP) and "hash".
+func Sign(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	tx, err := transactionArgs("sign", args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get session and chain.
+	chain, err := sessionChain(ctx, "sign", args)
+	if err != nil {
+		return nil, err
+	}
+	evmChain, ok := chain.(*evm.EVMGateway)
+	if !ok {
+		return nil, errors.New("sign: chain is not an EVM gateway")
+	}
+
+	raw, hash, err := evmChain.SignTransaction(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	return map[string]string{"raw": raw, "hash": hash}, nil
+}
+
+// transactionArgs builds a transaction from the arguments shared by the
+// sign and send tools; tool prefixes error messages.
+func transactionArgs(tool string, args map[string]interface{}) (*blockchain.Transaction, error) {
+	tx := &blockchain.Transaction{}
+	if toRaw, ok := args["to"]; ok {
+		to, ok := toRaw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'to' must be string", tool)
+		}
+		tx.To = &to
+	}
+	if amountRaw, ok := args["amount"]; ok {
+		amount, ok := amountRaw.(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'amount' must be *big.Int", tool)
+		}
+		tx.Value = amount
+	}
+	if dataRaw, ok := args["data"]; ok {
+		data, ok := dataRaw.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'data' must be []byte", tool)
+		}
+		tx.Data = data
+	}
+	if gasRaw, ok := args["gas"]; ok {
+		if g, ok := gasRaw.(uint64); ok {
+			tx.Gas = g
+		}
+	}
+	if nonceRaw, ok := args["nonce"]; ok {
+		if n, ok := nonceRaw.(uint64); ok {
+			tx.Nonce = &n
+		}
+	}
+	if listRaw, ok := args["accessList"]; ok {
+		list, ok := listRaw.([]blockchain.AccessTuple)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'accessList' must be []blockchain.AccessTuple", tool)
+		}
+		tx.AccessList = list
+	}
+	if fromRaw, ok := args["from"]; ok {
+		from, ok := fromRaw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: 'from' must be string", tool)
+		}
+		tx.From = from
+	}
+	tx.GasPrice = optionalBigInt(args, "gasPrice")
+	tx.GasFeeCap = optionalBigInt(args, "gasFeeCap")
+	tx.GasTipCap = optionalBigInt(args, "gasTipCap")
+	return tx, nil
+}
+
+// optionalBigInt returns args[name] if it is a *big.Int, else nil.
+func optionalBigInt(args map[string]interface{}, name string) *big.Int {
+	if raw, ok := args[name]; ok {
+		if v, ok := raw.(*big.Int); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// EOF: internal/tools/builtin/sign.go
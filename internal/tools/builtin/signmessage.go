@@ -0,0 +1,49 @@
+// Package builtin provides the EIP‑191 message signing tool.
+//
+// File: internal/tools/builtin/signmessage.go
+
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// SignMessage signs a message with the personal_sign (EIP‑191) prefix.
+// Arguments:
+//   - message: the message to sign ([]byte or string)
+//
+// It is a distinct operation from sign so that policies and HITL can gate
+// off‑chain signatures separately from transactions.
+// Returns the 65‑byte signature as 0x‑hex with V of 27 or 28.
+func SignMessage(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	var msg []byte
+	switch m := args["message"].(type) {
+	case []byte:
+		msg = m
+	case string:
+		msg = []byte(m)
+	default:
+		return nil, errors.New("sign_message: 'message' must be []byte or string")
+	}
+
+	chain, err := sessionChain(ctx, "sign_message", args)
+	if err != nil {
+		return nil, err
+	}
+	evmChain, ok := chain.(*evm.EVMGateway)
+	if !ok {
+		return nil, errors.New("sign_message: chain is not an EVM gateway")
+	}
+
+	sig, err := evmChain.SignMessage(msg)
+	if err != nil {
+		return nil, fmt.Errorf("sign_message: %w", err)
+	}
+	return sig, nil
+}
+
+// EOF: internal/tools/builtin/signmessage.go
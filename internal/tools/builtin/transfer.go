@@ -11,15 +11,16 @@ import (
 	"math/big"
 
 	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
-	"github.com/0xSemantic/lola-os/internal/core"
 )
 
 // Transfer sends native currency to an address.
 // Arguments:
-//   - to:      recipient address (string)
+//   - to:      recipient address or ENS name (string); a mixed‑case
+//     address must carry a valid EIP‑55 checksum
 //   - amount:  amount in wei (*big.Int)
 //   - gas:     optional gas limit (uint64)
 //   - gasPrice: optional gas price (*big.Int) – legacy
+//   - from:    optional signing account of a multi‑account wallet (string)
 // Returns transaction hash (string).
 func Transfer(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	// Extract arguments.
@@ -31,6 +32,14 @@ func Transfer(ctx context.Context, args map[string]interface{}) (interface{}, er
 	if !ok {
 		return nil, errors.New("transfer: 'to' must be string")
 	}
+	// Reject mistyped addresses early; ENS names are resolved by the gateway.
+	if !evm.IsENSName(to) {
+		normalized, err := evm.NormalizeAddress(to)
+		if err != nil {
+			return nil, fmt.Errorf("transfer: 'to': %w", err)
+		}
+		to = normalized
+	}
 
 	amountRaw, ok := args["amount"]
 	if !ok {
@@ -57,12 +66,20 @@ func Transfer(ctx context.Context, args map[string]interface{}) (interface{}, er
 		}
 	}
 
+	// Optional sender.
+	var from string
+	if fromRaw, ok := args["from"]; ok {
+		if from, ok = fromRaw.(string); !ok {
+			return nil, errors.New("transfer: 'from' must be string")
+		}
+	}
+
 	// Get session and chain.
-	sess := core.SessionFromContext(ctx)
-	if sess == nil {
-		return nil, errors.New("transfer: no session in context")
+	chain, err := sessionChain(ctx, "transfer", args)
+	if err != nil {
+		return nil, err
 	}
-	evmChain, ok := sess.Chain.(*evm.EVMGateway)
+	evmChain, ok := chain.(*evm.EVMGateway)
 	if !ok {
 		return nil, errors.New("transfer: chain is not an EVM gateway")
 	}
@@ -73,6 +90,7 @@ func Transfer(ctx context.Context, args map[string]interface{}) (interface{}, er
 		Value:    amount,
 		Gas:      gas,
 		GasPrice: gasPrice,
+		From:     from,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("transfer: %w", err)
@@ -0,0 +1,68 @@
+// Package sdk provides the errors security policies deny operations with.
+//
+// File: sdk/errors.go
+
+package sdk
+
+import (
+	"errors"
+
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// PolicyDecision is the outcome of one security policy for an operation.
+type PolicyDecision = security.PolicyDecision
+
+// ErrPolicyDenied is the error Runtime.Execute returns, wrapped, when
+// a security policy denies an operation. Decision names the policy and
+// its reason; Err is the policy's error, one of the types below if the
+// policy has one for the denial.
+type ErrPolicyDenied = security.ErrPolicyDenied
+
+// ErrNeedsApproval is the denial of an operation that needed a human's
+// approval and did not get it.
+type ErrNeedsApproval = security.ErrNeedsApproval
+
+// ErrLimitExceeded is the denial of an operation over a per‑transaction
+// limit, a daily budget or a rate limit, with the amounts involved.
+type ErrLimitExceeded = security.ErrLimitExceeded
+
+// ErrAddressBlocked is the denial of an operation to an address by an
+// address list or denylist feed.
+type ErrAddressBlocked = security.ErrAddressBlocked
+
+// ErrPaused is the denial of a write operation while writes are paused
+// by Runtime.PauseWrites or the kill switch.
+type ErrPaused = security.ErrPaused
+
+// IsPolicyDenied reports whether err is, or wraps, a denial by a security
+// policy, as opposed to a failure of the tool or the chain.
+func IsPolicyDenied(err error) bool {
+	var denied *ErrPolicyDenied
+	return errors.As(err, &denied)
+}
+
+// AsLimitExceeded returns the limit err exceeds, if err is, or wraps, a
+// denial by a limit. An agent can retry a smaller amount, or wait.
+func AsLimitExceeded(err error) (*ErrLimitExceeded, bool) {
+	var exceeded *ErrLimitExceeded
+	ok := errors.As(err, &exceeded)
+	return exceeded, ok
+}
+
+// AsAddressBlocked returns the blocked address and the list that blocks
+// it, if err is, or wraps, such a denial. Retrying will not help.
+func AsAddressBlocked(err error) (*ErrAddressBlocked, bool) {
+	var blocked *ErrAddressBlocked
+	ok := errors.As(err, &blocked)
+	return blocked, ok
+}
+
+// NeedsApproval reports whether err is, or wraps, the denial of an
+// operation a human did not approve, which an agent may escalate.
+func NeedsApproval(err error) bool {
+	var needs *ErrNeedsApproval
+	return errors.As(err, &needs)
+}
+
+// EOF: sdk/errors.go
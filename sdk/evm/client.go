@@ -9,18 +9,27 @@ import (
 	"context"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
 	"github.com/0xSemantic/lola-os/internal/blockchain"
 	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
 	"github.com/0xSemantic/lola-os/internal/core"
+	"github.com/0xSemantic/lola-os/internal/tools/builtin"
 	"github.com/0xSemantic/lola-os/sdk/types"
+	"github.com/0xSemantic/lola-os/sdk/types/units"
 )
 
 // Client is a high‑level EVM client attached to a runtime session.
 type Client struct {
 	chain blockchain.Chain
 	sess  *core.Session
+	exec  Executor // runs tools through the security policies; may be nil
 }
 
+// Executor runs a named tool through the runtime's security enforcer.
+type Executor func(ctx context.Context, tool string, args map[string]interface{}) (interface{}, error)
+
 // NewClient creates a new EVM client from a session.
 // It is not intended for direct use; use Runtime.EVM() instead.
 func NewClient(sess *core.Session) *Client {
@@ -30,6 +39,36 @@ func NewClient(sess *core.Session) *Client {
 	}
 }
 
+// NewClientWithExecutor creates a client whose policy‑checked operations
+// (such as SignTransaction) run through exec.
+func NewClientWithExecutor(sess *core.Session, exec Executor) *Client {
+	c := NewClient(sess)
+	c.exec = exec
+	return c
+}
+
+// NewClientForChain creates a client for the session's chain named name,
+// or its default chain if name is empty, whose policy‑checked operations
+// run through exec on that chain. Runtime.EVM uses it.
+func NewClientForChain(sess *core.Session, name string, exec Executor) (*Client, error) {
+	chain, err := sess.ChainByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("evm client: %w", err)
+	}
+	c := &Client{chain: chain, sess: sess, exec: exec}
+	if exec != nil && name != "" {
+		c.exec = func(ctx context.Context, tool string, args map[string]interface{}) (interface{}, error) {
+			onChain := make(map[string]interface{}, len(args)+1)
+			for k, v := range args {
+				onChain[k] = v
+			}
+			onChain["chain"] = name
+			return exec(ctx, tool, onChain)
+		}
+	}
+	return c, nil
+}
+
 // GetBalance returns the wei balance of the given address.
 func (c *Client) GetBalance(ctx context.Context, address string, block *types.BlockNumber) (*big.Int, error) {
 	if c.chain == nil {
@@ -42,6 +81,116 @@ func (c *Client) GetBalance(ctx context.Context, address string, block *types.Bl
 	return c.chain.GetBalance(ctx, address, b)
 }
 
+// GetBalanceEther returns the balance of the given address as an exact
+// decimal ether amount, such as "1.5".
+func (c *Client) GetBalanceEther(ctx context.Context, address string, block *types.BlockNumber) (string, error) {
+	wei, err := c.GetBalance(ctx, address, block)
+	if err != nil {
+		return "", err
+	}
+	return units.FormatEther(wei), nil
+}
+
+// IsContract reports whether address, a hex address or ENS name, has code
+// deployed. Check it before sending value to an address that should be a
+// plain account: ether sent to most token contracts is lost.
+func (c *Client) IsContract(ctx context.Context, address string) (bool, error) {
+	if c.chain == nil {
+		return false, fmt.Errorf("evm client: no chain available in session")
+	}
+	detector, ok := c.chain.(blockchain.ContractDetector)
+	if !ok {
+		return false, fmt.Errorf("evm client: chain does not support contract detection")
+	}
+	return detector.IsContract(ctx, address)
+}
+
+// DetectProxy reports the implementation behind a proxy contract at
+// address, or nil if it is not a recognised proxy (see types.ProxyKind).
+func (c *Client) DetectProxy(ctx context.Context, address string) (*types.ProxyInfo, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return nil, err
+	}
+	return evm.DetectProxy(ctx, gw, address)
+}
+
+// GetStorageAt returns the raw 32‑byte value of a contract storage slot,
+// given as 0x‑prefixed hex or decimal, at the specified block (nil = latest).
+func (c *Client) GetStorageAt(ctx context.Context, address, slot string, block *types.BlockNumber) ([]byte, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return nil, err
+	}
+	var b blockchain.BlockNumber
+	if block != nil {
+		b = blockchain.BlockNumber(*block)
+	}
+	return gw.GetStorageAt(ctx, address, slot, b)
+}
+
+// PendingNonce returns the nonce of address including transactions still
+// waiting in the mempool, i.e. the nonce its next transaction would use.
+func (c *Client) PendingNonce(ctx context.Context, address string) (uint64, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return 0, err
+	}
+	return gw.GetTransactionCount(ctx, address, blockchain.BlockNumberPending)
+}
+
+// ConfirmedNonce returns the nonce of address at the latest block, counting
+// only mined transactions. If it stays below PendingNonce, transactions of
+// the account are stuck.
+func (c *Client) ConfirmedNonce(ctx context.Context, address string) (uint64, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return 0, err
+	}
+	return gw.GetTransactionCount(ctx, address, blockchain.BlockNumberLatest)
+}
+
+// BaseFee returns the base fee of the latest block, or nil on chains
+// without EIP‑1559.
+func (c *Client) BaseFee(ctx context.Context) (*big.Int, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return nil, err
+	}
+	return gw.BaseFee(ctx)
+}
+
+// GasInfo returns the latest base fee, suggested priority fee and suggested
+// legacy gas price in one call.
+func (c *Client) GasInfo(ctx context.Context) (*types.GasInfo, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return nil, err
+	}
+	info, err := gw.GasInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &types.GasInfo{
+		BlockNumber: info.BlockNumber,
+		BaseFee:     info.BaseFee,
+		GasTipCap:   info.GasTipCap,
+		GasPrice:    info.GasPrice,
+	}, nil
+}
+
+// gateway returns the session chain as an EVM gateway.
+func (c *Client) gateway() (*evm.EVMGateway, error) {
+	if c.chain == nil {
+		return nil, fmt.Errorf("evm client: no chain available in session")
+	}
+	gw, ok := c.chain.(*evm.EVMGateway)
+	if !ok {
+		return nil, fmt.Errorf("evm client: chain is not EVM gateway")
+	}
+	return gw, nil
+}
+
 // CallContract executes a read‑only contract call.
 func (c *Client) CallContract(ctx context.Context, call *types.ContractCall) ([]byte, error) {
 	if c.chain == nil {
@@ -57,11 +206,27 @@ func (c *Client) CallContract(ctx context.Context, call *types.ContractCall) ([]
 }
 
 // SendTransaction signs and broadcasts a transaction.
-// Requires a wallet configured in the runtime.
+// Requires a wallet configured in the runtime. From a runtime client it
+// runs as the "send" tool, so security policies apply to it as to a
+// contract call.
 func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) (string, error) {
 	if c.chain == nil {
 		return "", fmt.Errorf("evm client: no chain available in session")
 	}
+	if c.exec != nil {
+		if tx.To == nil {
+			return "", fmt.Errorf("evm client: SendTransaction requires a recipient; use DeployContract for contract creation")
+		}
+		result, err := c.exec(ctx, "send", txArgs(tx))
+		if err != nil {
+			return "", err
+		}
+		txHash, ok := result.(string)
+		if !ok {
+			return "", fmt.Errorf("evm client: unexpected send result %T", result)
+		}
+		return txHash, nil
+	}
 	internalTx := &blockchain.Transaction{
 		To:        tx.To,
 		Value:     tx.Value,
@@ -71,24 +236,352 @@ func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) (st
 		GasTipCap: tx.GasTipCap,
 		Data:      tx.Data,
 		Nonce:     tx.Nonce,
+		From:      tx.From,
+	}
+	for _, t := range tx.AccessList {
+		internalTx.AccessList = append(internalTx.AccessList, blockchain.AccessTuple{
+			Address:     t.Address,
+			StorageKeys: t.StorageKeys,
+		})
 	}
 	return c.chain.SendTransaction(ctx, internalTx)
 }
 
-// DeployContract deploys a smart contract.
-func (c *Client) DeployContract(ctx context.Context, bytecode []byte) (string, string, error) {
-	if c.chain == nil {
-		return "", "", fmt.Errorf("evm client: no chain available in session")
+// SendAndWaitDecoded sends tx, waits for it to be mined and returns the
+// receipt with its logs decoded against the events in abiJSON. Logs from
+// other contracts or of unknown events are skipped. A transaction that
+// reverts still returns its receipt, with Status 0.
+func (c *Client) SendAndWaitDecoded(ctx context.Context, tx *types.Transaction, abiJSON string) (*types.Receipt, []types.DecodedEvent, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return nil, nil, err
 	}
-	// We need to type‑assert to evm.EVMGateway to access DeployContract.
-	gw, ok := c.chain.(*evm.EVMGateway)
+	txHash, err := c.SendTransaction(ctx, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	receipt, err := gw.WaitForTransaction(ctx, txHash, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	decoded, err := gw.DecodeReceiptLogs(receipt, abiJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+	events := make([]types.DecodedEvent, 0, len(decoded))
+	for _, e := range decoded {
+		events = append(events, types.DecodedEvent{
+			Name:        e.Name,
+			Args:        e.Args,
+			LogIndex:    e.LogIndex,
+			Address:     e.Address,
+			BlockNumber: e.BlockNumber,
+			TxHash:      e.TxHash,
+		})
+	}
+	return toReceipt(receipt), events, nil
+}
+
+// toReceipt converts a go‑ethereum receipt to the public type.
+func toReceipt(r *gethtypes.Receipt) *types.Receipt {
+	out := &types.Receipt{
+		TxHash:            r.TxHash.Hex(),
+		BlockHash:         r.BlockHash.Hex(),
+		Status:            r.Status,
+		GasUsed:           r.GasUsed,
+		EffectiveGasPrice: r.EffectiveGasPrice,
+	}
+	if r.BlockNumber != nil {
+		out.BlockNumber = r.BlockNumber.Uint64()
+	}
+	if r.ContractAddress != (common.Address{}) {
+		out.ContractAddress = r.ContractAddress.Hex()
+	}
+	return out
+}
+
+// SignTransaction builds and signs a transaction without broadcasting it and
+// returns the raw signed transaction (0x‑hex) and its hash. It runs as the
+// "sign" tool, so security policies apply to the signed value as if it
+// were sent. Supplying Nonce, Gas and fees avoids all RPC calls.
+func (c *Client) SignTransaction(ctx context.Context, tx *types.Transaction) (string, string, error) {
+	if c.exec == nil {
+		return "", "", fmt.Errorf("evm client: SignTransaction requires a runtime client (use Runtime.EVM)")
+	}
+	result, err := c.exec(ctx, "sign", txArgs(tx))
+	if err != nil {
+		return "", "", err
+	}
+	signed, ok := result.(map[string]string)
 	if !ok {
-		return "", "", fmt.Errorf("evm client: chain is not EVM gateway")
+		return "", "", fmt.Errorf("evm client: unexpected sign result %T", result)
+	}
+	return signed["raw"], signed["hash"], nil
+}
+
+// txArgs returns the arguments of the sign and send tools for tx.
+func txArgs(tx *types.Transaction) map[string]interface{} {
+	args := map[string]interface{}{}
+	if tx.To != nil {
+		args["to"] = *tx.To
+	}
+	if tx.Value != nil {
+		args["amount"] = tx.Value
+	}
+	if len(tx.Data) > 0 {
+		args["data"] = tx.Data
+	}
+	if tx.Gas != 0 {
+		args["gas"] = tx.Gas
+	}
+	if tx.Nonce != nil {
+		args["nonce"] = *tx.Nonce
+	}
+	if tx.GasPrice != nil {
+		args["gasPrice"] = tx.GasPrice
+	}
+	if tx.GasFeeCap != nil {
+		args["gasFeeCap"] = tx.GasFeeCap
+	}
+	if tx.GasTipCap != nil {
+		args["gasTipCap"] = tx.GasTipCap
+	}
+	if len(tx.AccessList) > 0 {
+		list := make([]blockchain.AccessTuple, 0, len(tx.AccessList))
+		for _, t := range tx.AccessList {
+			list = append(list, blockchain.AccessTuple{Address: t.Address, StorageKeys: t.StorageKeys})
+		}
+		args["accessList"] = list
+	}
+	if tx.From != "" {
+		args["from"] = tx.From
+	}
+	return args
+}
+
+// SendRawTransaction broadcasts a transaction signed elsewhere (for example
+// by a hardware wallet) and returns its hash. raw is the signed transaction
+// as bytes or 0x‑hex text. It runs as the "send_raw" tool with the decoded
+// recipient and value, so whitelists and value limits apply.
+func (c *Client) SendRawTransaction(ctx context.Context, raw []byte) (string, error) {
+	if c.exec == nil {
+		return "", fmt.Errorf("evm client: SendRawTransaction requires a runtime client (use Runtime.EVM)")
+	}
+	args, err := builtin.SendRawArgs(raw)
+	if err != nil {
+		return "", err
+	}
+	result, err := c.exec(ctx, "send_raw", args)
+	if err != nil {
+		return "", err
+	}
+	txHash, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("evm client: unexpected send_raw result %T", result)
+	}
+	return txHash, nil
+}
+
+// SignMessage signs msg with the session wallet using the personal_sign
+// (EIP‑191) prefix and returns the signature as 0x‑hex with V of 27 or 28.
+// It runs as the "sign_message" tool, so read‑only mode and any policy
+// gating that tool apply.
+func (c *Client) SignMessage(ctx context.Context, msg []byte) (string, error) {
+	if c.exec == nil {
+		return "", fmt.Errorf("evm client: SignMessage requires a runtime client (use Runtime.EVM)")
+	}
+	result, err := c.exec(ctx, "sign_message", map[string]interface{}{"message": msg})
+	if err != nil {
+		return "", err
+	}
+	sig, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("evm client: unexpected sign_message result %T", result)
+	}
+	return sig, nil
+}
+
+// VerifyMessage reports whether sig is a personal_sign (EIP‑191) signature
+// of msg by address. It needs no chain access.
+func (c *Client) VerifyMessage(address string, msg []byte, sig string) (bool, error) {
+	return evm.VerifyMessage(address, msg, sig)
+}
+
+// RecoverSigner returns the address that signed the 32‑byte digest. sig is
+// a 65‑byte [R || S || V] signature with V of 0/1 or 27/28.
+func (c *Client) RecoverSigner(digest, sig []byte) (string, error) {
+	return evm.RecoverSigner(digest, sig)
+}
+
+// VerifySignature reports whether sig over the 32‑byte digest is valid for
+// address. Externally owned accounts are checked by recovery; contract
+// wallets are asked through EIP‑1271 isValidSignature.
+func (c *Client) VerifySignature(ctx context.Context, address string, digest, sig []byte) (bool, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return false, err
+	}
+	return gw.VerifySignature(ctx, address, digest, sig)
+}
+
+// Trace replays a mined transaction with the node's callTracer and returns
+// its call tree; print it with String to see where a deep revert came
+// from. Providers without the debug namespace fail with
+// types.ErrNotSupported.
+func (c *Client) Trace(ctx context.Context, txHash string) (*types.CallFrame, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return nil, err
+	}
+	return gw.TraceTransaction(ctx, txHash, nil)
+}
+
+// DeployContract deploys a smart contract. From a runtime client it runs
+// as the "deploy" tool, so security policies, such as the deployment
+// bytecode allowlist, apply to it.
+// Returns the transaction hash and the contract address.
+func (c *Client) DeployContract(ctx context.Context, bytecode []byte) (string, string, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return "", "", err
+	}
+	if c.exec != nil {
+		return c.deploy(ctx, map[string]interface{}{"bytecode": bytecode})
 	}
 	txHash, addr, err := gw.DeployContract(ctx, bytecode, nil)
 	return txHash, addr.Hex(), err
 }
 
+// DeployAndWait deploys a smart contract like DeployContract, then waits
+// until it is mined and checks that code exists at its address. A
+// deployment that reverts or leaves no code fails with a
+// *types.DeploymentFailedError carrying the revert reason when known.
+// From a runtime client the deployment runs as the "deploy" tool, so
+// security policies apply to it.
+// Returns the transaction hash and the contract address.
+func (c *Client) DeployAndWait(ctx context.Context, bytecode []byte) (string, string, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return "", "", err
+	}
+	var txHash, addr string
+	if c.exec != nil {
+		txHash, addr, err = c.deploy(ctx, map[string]interface{}{"bytecode": bytecode})
+	} else {
+		var deployed common.Address
+		txHash, deployed, err = gw.DeployContract(ctx, bytecode, nil)
+		addr = deployed.Hex()
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := gw.WaitForDeployment(ctx, txHash, common.HexToAddress(addr), 0); err != nil {
+		return txHash, addr, err
+	}
+	return txHash, addr, nil
+}
+
+// DeployContractCreate2 deploys a smart contract through the canonical
+// CREATE2 factory, so it gets the same address on every chain for the same
+// salt and bytecode. It waits until the contract is mined. From a runtime
+// client it runs as the "deploy" tool with the salt, so security policies
+// apply to it as to any deployment.
+// Returns the transaction hash and the contract address.
+func (c *Client) DeployContractCreate2(ctx context.Context, salt [32]byte, bytecode []byte) (string, string, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return "", "", err
+	}
+	if c.exec != nil {
+		return c.deploy(ctx, map[string]interface{}{"bytecode": bytecode, "salt": salt})
+	}
+	txHash, addr, err := gw.DeployContractCreate2(ctx, common.Address{}, salt, bytecode, nil)
+	return txHash, addr.Hex(), err
+}
+
+// DeployFromArtifact deploys the contract of a Foundry or Hardhat artifact
+// file, ABI‑encoding args for its constructor. From a runtime client it
+// runs as the "deploy" tool with the creation data, so security policies
+// apply to it.
+// Returns the transaction hash and the contract address.
+func (c *Client) DeployFromArtifact(ctx context.Context, path string, args ...interface{}) (string, string, error) {
+	gw, err := c.gateway()
+	if err != nil {
+		return "", "", err
+	}
+	if c.exec == nil {
+		txHash, addr, err := gw.DeployFromArtifact(ctx, path, nil, args...)
+		return txHash, addr.Hex(), err
+	}
+	artifact, err := evm.LoadArtifact(path)
+	if err != nil {
+		return "", "", fmt.Errorf("evm client: %w", err)
+	}
+	data, err := artifact.CreationData(args...)
+	if err != nil {
+		return "", "", fmt.Errorf("evm client: %s: %w", path, err)
+	}
+	return c.deploy(ctx, map[string]interface{}{"bytecode": data})
+}
+
+// deploy runs the "deploy" tool with args and returns the transaction hash
+// and contract address it reports.
+func (c *Client) deploy(ctx context.Context, args map[string]interface{}) (string, string, error) {
+	result, err := c.exec(ctx, "deploy", args)
+	if err != nil {
+		return "", "", err
+	}
+	deployed, ok := result.(map[string]interface{})
+	if !ok {
+		return "", "", fmt.Errorf("evm client: unexpected deploy result %T", result)
+	}
+	txHash, _ := deployed["tx_hash"].(string)
+	addr, _ := deployed["contract_address"].(string)
+	return txHash, addr, nil
+}
+
+// CancelTransaction replaces a pending transaction with a zero‑value
+// self‑transfer using the same nonce and a higher fee. From a runtime
+// client it runs as the "cancel" tool, which policies see as a transfer of
+// 0 to the wallet's own address.
+// Returns the cancellation transaction hash.
+func (c *Client) CancelTransaction(ctx context.Context, txHash string) (string, error) {
+	if c.exec != nil {
+		result, err := c.exec(ctx, "cancel", map[string]interface{}{"tx_hash": txHash})
+		if err != nil {
+			return "", err
+		}
+		cancelHash, ok := result.(string)
+		if !ok {
+			return "", fmt.Errorf("evm client: unexpected cancel result %T", result)
+		}
+		return cancelHash, nil
+	}
+	gw, err := c.gateway()
+	if err != nil {
+		return "", err
+	}
+	return gw.CancelTransaction(ctx, txHash)
+}
+
+// EncodeCall returns the calldata for invoking method with args, for use
+// as Transaction.Data. method is a bare name or, for overloaded methods, a
+// full signature such as "transfer(address,uint256)".
+func EncodeCall(abiJSON, method string, args ...interface{}) ([]byte, error) {
+	return evm.EncodeCall(abiJSON, method, args...)
+}
+
+// DecodeCall returns the method that calldata invokes and its arguments by
+// name. Overloaded methods are returned by full signature.
+func DecodeCall(abiJSON string, data []byte) (string, map[string]interface{}, error) {
+	return evm.DecodeCall(abiJSON, data)
+}
+
+// DecodeResult decodes the return data of a call to method.
+func DecodeResult(abiJSON, method string, data []byte) ([]interface{}, error) {
+	return evm.DecodeResult(abiJSON, method, data)
+}
+
 // BindContract creates a high‑level contract binding.
 func BindContract(ctx context.Context, client *Client, address, abiJSON string) (types.Contract, error) {
 	if client.chain == nil {
@@ -98,7 +591,143 @@ func BindContract(ctx context.Context, client *Client, address, abiJSON string)
 	if !ok {
 		return nil, fmt.Errorf("evm client: chain is not EVM gateway")
 	}
-	return evm.NewBoundContract(address, abiJSON, gw)
+	return client.wrapContract(evm.NewBoundContract(address, abiJSON, gw))
+}
+
+// BindContractByAddress binds the contract at address, a hex address or
+// ENS name, with its verified ABI fetched from the chain's explorer or
+// Sourcify (cached on disk). For a proxy (EIP‑1967, beacon or EIP‑1167)
+// the implementation's ABI is used. Errors wrap types.ErrABINotVerified when no source has the
+// contract and types.ErrABIUnavailable when a source cannot be reached.
+func BindContractByAddress(ctx context.Context, client *Client, address string) (types.Contract, error) {
+	if client.chain == nil {
+		return nil, fmt.Errorf("evm client: no chain available")
+	}
+	gw, ok := client.chain.(*evm.EVMGateway)
+	if !ok {
+		return nil, fmt.Errorf("evm client: chain is not EVM gateway")
+	}
+	return client.wrapContract(evm.NewBoundContractByAddress(ctx, address, gw))
+}
+
+// BindContractResolvingProxy is BindContractByAddress that also returns
+// the proxy found at address, or nil if it is not one. The binding uses
+// the implementation's ABI but calls the proxy.
+func BindContractResolvingProxy(ctx context.Context, client *Client, address string) (types.Contract, *types.ProxyInfo, error) {
+	gw, err := client.gateway()
+	if err != nil {
+		return nil, nil, err
+	}
+	bound, proxy, err := evm.NewBoundContractResolvingProxy(ctx, address, gw)
+	contract, err := client.wrapContract(bound, err)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contract, proxy, nil
+}
+
+// BindContractFromFile binds the contract at address using the ABI of a
+// Foundry (out/<File>.sol/<Name>.json) or Hardhat artifact file.
+func BindContractFromFile(ctx context.Context, client *Client, path, address string) (types.Contract, error) {
+	if client.chain == nil {
+		return nil, fmt.Errorf("evm client: no chain available")
+	}
+	gw, ok := client.chain.(*evm.EVMGateway)
+	if !ok {
+		return nil, fmt.Errorf("evm client: chain is not EVM gateway")
+	}
+	return client.wrapContract(evm.NewBoundContractFromArtifact(path, address, gw))
+}
+
+// NewCallBatch returns an empty batch of read‑only calls on the session
+// chain. Add calls on contracts from BindContract and run them with
+// Execute; results come back in the order the calls were added, each with
+// its own error, so one revert does not fail the rest.
+func NewCallBatch(client *Client) (*types.CallBatch, error) {
+	gw, err := client.gateway()
+	if err != nil {
+		return nil, err
+	}
+	return evm.NewCallBatch(gw), nil
+}
+
+// contract is the binding returned by BindContract. Transactions run as
+// the "send" tool, so security policies see the value attached to a
+// payable call just as they see a transfer's.
+type contract struct {
+	*evm.BoundContract
+	client *Client
+}
+
+// wrapContract wraps the result of an evm binding constructor.
+func (c *Client) wrapContract(bound blockchain.Contract, err error) (types.Contract, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &contract{BoundContract: bound.(*evm.BoundContract), client: c}, nil
+}
+
+// Transact sends a transaction invoking method, simulated first.
+func (c *contract) Transact(ctx context.Context, method string, args ...interface{}) (string, error) {
+	return c.TransactWithOpts(ctx, nil, method, args...)
+}
+
+// TransactWithOpts sends a transaction invoking method with the value and
+// gas settings of opts (may be nil), simulated first so a revert fails
+// without spending gas.
+func (c *contract) TransactWithOpts(ctx context.Context, opts *types.TransactOpts, method string, args ...interface{}) (string, error) {
+	return c.transact(ctx, opts, nil, method, args...)
+}
+
+// transact is TransactWithOpts with extra arguments for the "send" tool,
+// which are logged with it and visible to policies, such as the id of a
+// transferred NFT.
+func (c *contract) transact(ctx context.Context, opts *types.TransactOpts, extra map[string]interface{}, method string, args ...interface{}) (string, error) {
+	if c.client.exec == nil {
+		return c.BoundContract.TransactWithOpts(ctx, opts, method, args...)
+	}
+	tx, err := c.BuildTransaction(opts, method, args...)
+	if err != nil {
+		return "", err
+	}
+	toolArgs := map[string]interface{}{
+		"to":       *tx.To,
+		"data":     tx.Data,
+		"simulate": true,
+	}
+	for k, v := range extra {
+		toolArgs[k] = v
+	}
+	if tx.Value != nil {
+		toolArgs["amount"] = tx.Value
+	}
+	if tx.Gas != 0 {
+		toolArgs["gas"] = tx.Gas
+	}
+	if tx.Nonce != nil {
+		toolArgs["nonce"] = *tx.Nonce
+	}
+	if tx.GasPrice != nil {
+		toolArgs["gasPrice"] = tx.GasPrice
+	}
+	if tx.GasFeeCap != nil {
+		toolArgs["gasFeeCap"] = tx.GasFeeCap
+	}
+	if tx.GasTipCap != nil {
+		toolArgs["gasTipCap"] = tx.GasTipCap
+	}
+	if tx.From != "" {
+		toolArgs["from"] = tx.From
+	}
+	result, err := c.client.exec(ctx, "send", toolArgs)
+	if err != nil {
+		return "", err
+	}
+	txHash, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("evm client: unexpected send result %T", result)
+	}
+	return txHash, nil
 }
 
 // EOF: sdk/evm/client.go
\ No newline at end of file
@@ -0,0 +1,246 @@
+// Package evm_test tests that client writes run through the security
+// policies.
+//
+// File: sdk/evm/client_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	chain "github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/internal/core"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/security/policies"
+	"github.com/0xSemantic/lola-os/internal/tools"
+	"github.com/0xSemantic/lola-os/internal/tools/builtin"
+	"github.com/0xSemantic/lola-os/sdk/evm"
+	sdktypes "github.com/0xSemantic/lola-os/sdk/types"
+)
+
+// newEngineClient returns a client on a simulated chain whose writes run
+// the builtin tools through an engine enforcing policies, as a runtime
+// client's do.
+func newEngineClient(t *testing.T, policies ...security.Policy) (context.Context, *evm.Client, *security.Enforcer, *simulated.Backend, *chain.EVMGateway) {
+	t.Helper()
+	wallet := newTestWallet(t)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	t.Cleanup(func() { sim.Close() })
+	_, gateway, _ := newSimulatedClient(t, sim, wallet)
+
+	reg := tools.New()
+	require.NoError(t, reg.Register("send", builtin.Send))
+	require.NoError(t, reg.Register("cancel", builtin.Cancel))
+	require.NoError(t, reg.Register("deploy", builtin.Deploy))
+	enforcer := security.NewEnforcer()
+	for _, p := range policies {
+		enforcer.AddPolicy(p)
+	}
+	engine := core.NewEngine(reg, enforcer, &observe.NoopLogger{})
+	sess := engine.CreateSession("sim", map[string]blockchain.Chain{"sim": gateway})
+	t.Cleanup(func() { engine.CloseSession(sess.ID) })
+
+	client, err := evm.NewClientForChain(sess, "", engine.Execute)
+	require.NoError(t, err)
+	return core.ContextWithSession(context.Background(), sess), client, enforcer, sim, gateway
+}
+
+func TestClient_CancelTransaction(t *testing.T) {
+	// The whitelist does not list the wallet itself; the value limit
+	// allows the cancellation's fee, but no more.
+	whitelist, err := policies.NewWhitelistPolicy([]string{"0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"}, nil)
+	require.NoError(t, err)
+	limits := policies.NewLimitPolicy(config.MustParseAmount("0.001 eth"), nil)
+	ctx, client, _, sim, gateway := newEngineClient(t, whitelist, limits)
+
+	// Underpriced transfer: a 1 wei gas price is below the base fee, so
+	// the transaction stays in the pool.
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	stuckHash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{
+		To: &to, Value: big.NewInt(1000), Gas: 21000, GasPrice: big.NewInt(1),
+	})
+	require.NoError(t, err)
+
+	// The policies apply: a transfer of value is over the limit.
+	_, err = client.SendTransaction(ctx, &sdktypes.Transaction{To: &to, Value: big.NewInt(1e15)})
+	var exceeded *security.ErrLimitExceeded
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, "max_transaction_value", exceeded.Name)
+
+	cancelHash, err := client.CancelTransaction(ctx, stuckHash)
+	require.NoError(t, err)
+	sim.Commit()
+	receipt, err := sim.Client().TransactionReceipt(ctx, common.HexToHash(cancelHash))
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+func TestClient_CancelTransactionPaysFees(t *testing.T) {
+	gas := policies.NewGasPolicy(nil, 0, config.MustParseAmount("1 wei"))
+	ctx, client, _, _, gateway := newEngineClient(t, gas)
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	stuckHash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{
+		To: &to, Value: big.NewInt(1000), Gas: 21000, GasPrice: big.NewInt(1),
+	})
+	require.NoError(t, err)
+
+	// The replacement's fee counts against the daily gas budget.
+	_, err = client.CancelTransaction(ctx, stuckHash)
+	var exceeded *security.ErrLimitExceeded
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, "daily_gas_budget", exceeded.Name)
+	assert.Equal(t, gateway.Wallet().Address(), exceeded.Account)
+}
+
+func TestClient_DeployFromArtifact(t *testing.T) {
+	counter := filepath.Join("..", "..", "internal", "blockchain", "evm", "testdata", "artifacts", "hardhat", "Counter.json")
+	artifact, err := chain.LoadArtifact(counter)
+	require.NoError(t, err)
+	allowed, err := artifact.CreationData(big.NewInt(41))
+	require.NoError(t, err)
+	deploy, err := policies.NewDeployPolicy(&config.DeployConfig{
+		AllowDeploy:   true,
+		AllowedHashes: []string{crypto.Keccak256Hash(allowed).Hex()},
+	})
+	require.NoError(t, err)
+	ctx, client, _, sim, _ := newEngineClient(t, deploy)
+
+	// The policy sees the creation data, constructor arguments included.
+	_, addr, err := client.DeployFromArtifact(ctx, counter, big.NewInt(41))
+	require.NoError(t, err)
+	sim.Commit()
+	isContract, err := client.IsContract(ctx, addr)
+	require.NoError(t, err)
+	assert.True(t, isContract)
+
+	_, _, err = client.DeployFromArtifact(ctx, counter, big.NewInt(42))
+	assert.ErrorContains(t, err, "is not allowed")
+}
+
+func TestClient_DeployContract(t *testing.T) {
+	deploy, err := policies.NewDeployPolicy(&config.DeployConfig{
+		AllowDeploy:   true,
+		AllowedHashes: []string{crypto.Keccak256Hash(tokenInitCode).Hex()},
+	})
+	require.NoError(t, err)
+	ctx, client, _, sim, gateway := newEngineClient(t, deploy)
+	me := gateway.Wallet().Address()
+
+	_, addr, err := client.DeployContract(ctx, tokenInitCode)
+	require.NoError(t, err)
+	sim.Commit()
+	isContract, err := client.IsContract(ctx, addr)
+	require.NoError(t, err)
+	assert.True(t, isContract)
+
+	// Bytecode whose hash is not allowed is never sent.
+	nonce, err := client.PendingNonce(ctx, me)
+	require.NoError(t, err)
+	_, _, err = client.DeployContract(ctx, bytes32TokenInitCode)
+	assert.ErrorContains(t, err, "deploy: bytecode hash "+crypto.Keccak256Hash(bytes32TokenInitCode).Hex()+" is not allowed")
+	after, err := client.PendingNonce(ctx, me)
+	require.NoError(t, err)
+	assert.Equal(t, nonce, after)
+}
+
+func TestClient_PausedWrites(t *testing.T) {
+	ctx, client, enforcer, sim, gateway := newEngineClient(t)
+	me := gateway.Wallet().Address()
+	to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+	stuckHash, err := gateway.SendTransaction(ctx, &blockchain.Transaction{
+		To: &to, Value: big.NewInt(1000), Gas: 21000, GasPrice: big.NewInt(1),
+	})
+	require.NoError(t, err)
+	nonce, err := client.PendingNonce(ctx, me)
+	require.NoError(t, err)
+
+	enforcer.Pause("incident")
+	var paused *security.ErrPaused
+	_, _, err = client.DeployContract(ctx, tokenInitCode)
+	require.ErrorAs(t, err, &paused)
+	_, _, err = client.DeployAndWait(ctx, tokenInitCode)
+	require.ErrorAs(t, err, &paused)
+	_, _, err = client.DeployContractCreate2(ctx, [32]byte{1}, tokenInitCode)
+	require.ErrorAs(t, err, &paused)
+	counter := filepath.Join("..", "..", "internal", "blockchain", "evm", "testdata", "artifacts", "hardhat", "Counter.json")
+	_, _, err = client.DeployFromArtifact(ctx, counter, big.NewInt(41))
+	require.ErrorAs(t, err, &paused)
+	_, err = client.CancelTransaction(ctx, stuckHash)
+	require.ErrorAs(t, err, &paused)
+	after, err := client.PendingNonce(ctx, me)
+	require.NoError(t, err)
+	assert.Equal(t, nonce, after, "nothing is broadcast while paused")
+
+	enforcer.Resume()
+	cancelHash, err := client.CancelTransaction(ctx, stuckHash)
+	require.NoError(t, err)
+	sim.Commit()
+	receipt, err := sim.Client().TransactionReceipt(ctx, common.HexToHash(cancelHash))
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+// mineUntilDone commits blocks on sim until fn, which waits for its
+// transactions to be mined, returns.
+func mineUntilDone(sim *simulated.Backend, fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(20 * time.Millisecond):
+			sim.Commit()
+		}
+	}
+}
+
+func TestClient_DeployAndWait(t *testing.T) {
+	recorder := &toolRecorder{}
+	ctx, client, _, sim, _ := newEngineClient(t, recorder)
+
+	var txHash, addr string
+	var err error
+	mineUntilDone(sim, func() { txHash, addr, err = client.DeployAndWait(ctx, tokenInitCode) })
+	require.NoError(t, err)
+	assert.NotEmpty(t, txHash)
+	isContract, err := client.IsContract(ctx, addr)
+	require.NoError(t, err)
+	assert.True(t, isContract)
+	assert.Equal(t, []string{"deploy"}, recorder.tools)
+}
+
+// toolRecorder is a policy that records the tool of every operation.
+type toolRecorder struct {
+	mu    sync.Mutex
+	tools []string
+}
+
+func (p *toolRecorder) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tools = append(p.tools, evalCtx.Tool)
+	return nil
+}
+
+// EOF: sdk/evm/client_test.go
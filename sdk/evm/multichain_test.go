@@ -0,0 +1,105 @@
+// Package evm_test tests one session working on two simulated chains.
+//
+// File: sdk/evm/multichain_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain"
+	chain "github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/core"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/security"
+	"github.com/0xSemantic/lola-os/internal/tools"
+	"github.com/0xSemantic/lola-os/internal/tools/builtin"
+	"github.com/0xSemantic/lola-os/sdk/evm"
+)
+
+// chainRecorder is a policy that records the chain of every operation.
+type chainRecorder struct {
+	mu     sync.Mutex
+	chains []string
+}
+
+func (p *chainRecorder) Check(ctx context.Context, evalCtx *security.EvaluationContext) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.chains = append(p.chains, evalCtx.ChainName)
+	return nil
+}
+
+// newSimulatedGateway returns a gateway for wallet on a simulated chain
+// where the wallet holds balance wei.
+func newSimulatedGateway(t *testing.T, wallet *chain.Keystore, balance int64) *chain.EVMGateway {
+	t.Helper()
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(balance)},
+	})
+	t.Cleanup(func() { sim.Close() })
+	ec := reflect.ValueOf(sim.Client()).Field(0).Interface().(*ethclient.Client)
+	return chain.NewEVMGatewayFromClient(chain.NewClientFromEthClient(ec, &observe.NoopLogger{}, nil), &observe.NoopLogger{}, wallet)
+}
+
+func TestMultiChainSession(t *testing.T) {
+	wallet := newTestWallet(t)
+	one := newSimulatedGateway(t, wallet, 1e18)
+	two := newSimulatedGateway(t, wallet, 2e18)
+
+	reg := tools.New()
+	require.NoError(t, reg.Register("balance", builtin.Balance))
+	require.NoError(t, reg.Register("sign_message", builtin.SignMessage))
+	recorder := &chainRecorder{}
+	enforcer := security.NewEnforcer()
+	enforcer.AddPolicy(recorder)
+	engine := core.NewEngine(reg, enforcer, &observe.NoopLogger{})
+
+	// One session on both chains, as Runtime.Run creates it.
+	sess := engine.CreateSession("one", map[string]blockchain.Chain{"one": one, "two": two})
+	defer engine.CloseSession(sess.ID)
+	ctx := core.ContextWithSession(context.Background(), sess)
+	me := wallet.Address()
+
+	bal, err := engine.Execute(ctx, "balance", map[string]interface{}{"address": me})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1e18), bal)
+	bal, err = engine.Execute(ctx, "balance", map[string]interface{}{"address": me, "chain": "two"})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2e18), bal)
+
+	defaultClient, err := evm.NewClientForChain(sess, "", engine.Execute)
+	require.NoError(t, err)
+	bal, err = defaultClient.GetBalance(ctx, me, nil)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1e18), bal)
+
+	twoClient, err := evm.NewClientForChain(sess, "two", engine.Execute)
+	require.NoError(t, err)
+	bal, err = twoClient.GetBalance(ctx, me, nil)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2e18), bal)
+
+	// Operations of a client run on its chain, checked by its policies.
+	_, err = twoClient.SignMessage(ctx, []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two", "two"}, recorder.chains)
+
+	_, err = evm.NewClientForChain(sess, "three", engine.Execute)
+	assert.ErrorContains(t, err, `unknown chain "three"`)
+	_, err = engine.Execute(ctx, "balance", map[string]interface{}{"address": me, "chain": "three"})
+	assert.ErrorContains(t, err, `unknown chain "three"`)
+}
+
+// EOF: sdk/evm/multichain_test.go
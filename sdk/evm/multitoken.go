@@ -0,0 +1,194 @@
+// Package evm provides a binding for ERC‑1155 multi‑tokens.
+//
+// File: sdk/evm/multitoken.go
+
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ERC1155ABI is the ABI of the ERC‑1155 multi‑token standard, with the
+// metadata URI extension.
+const ERC1155ABI = `[
+	{"type":"function","name":"balanceOf","inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"balanceOfBatch","inputs":[{"name":"accounts","type":"address[]"},{"name":"ids","type":"uint256[]"}],"outputs":[{"name":"","type":"uint256[]"}],"stateMutability":"view"},
+	{"type":"function","name":"isApprovedForAll","inputs":[{"name":"account","type":"address"},{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"view"},
+	{"type":"function","name":"setApprovalForAll","inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"safeBatchTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"ids","type":"uint256[]"},{"name":"values","type":"uint256[]"},{"name":"data","type":"bytes"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"uri","inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"event","name":"TransferSingle","inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"id","type":"uint256","indexed":false},{"name":"value","type":"uint256","indexed":false}],"anonymous":false},
+	{"type":"event","name":"TransferBatch","inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"ids","type":"uint256[]","indexed":false},{"name":"values","type":"uint256[]","indexed":false}],"anonymous":false},
+	{"type":"event","name":"ApprovalForAll","inputs":[{"name":"account","type":"address","indexed":true},{"name":"operator","type":"address","indexed":true},{"name":"approved","type":"bool","indexed":false}],"anonymous":false},
+	{"type":"event","name":"URI","inputs":[{"name":"value","type":"string","indexed":false},{"name":"id","type":"uint256","indexed":true}],"anonymous":false}
+]`
+
+// MultiToken is an ERC‑1155 contract holding many token types, each
+// identified by an id. Transfers and approvals run as the "send" tool, so
+// security policies apply to them and the tool log records the ids and
+// amounts moved.
+type MultiToken struct {
+	// Address is the contract's checksummed address.
+	Address string
+
+	contract *contract
+}
+
+// NewMultiToken binds the ERC‑1155 contract at address, a hex address or
+// ENS name. An address without code is an error.
+func NewMultiToken(ctx context.Context, client *Client, address string) (*MultiToken, error) {
+	gw, err := client.gateway()
+	if err != nil {
+		return nil, err
+	}
+	isContract, err := gw.IsContract(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("multitoken %s: %w", address, err)
+	}
+	if !isContract {
+		return nil, fmt.Errorf("multitoken %s: no contract at address", address)
+	}
+	bound, err := BindContract(ctx, client, address, ERC1155ABI)
+	if err != nil {
+		return nil, fmt.Errorf("multitoken %s: %w", address, err)
+	}
+	c := bound.(*contract)
+	return &MultiToken{Address: c.Address().Hex(), contract: c}, nil
+}
+
+// BalanceOf returns how many tokens of type id owner holds.
+func (m *MultiToken) BalanceOf(ctx context.Context, owner string, id *big.Int) (*big.Int, error) {
+	addr, err := addressArg("multitoken", "owner", owner)
+	if err != nil {
+		return nil, err
+	}
+	var balance *big.Int
+	if err := m.contract.CallInto(ctx, &balance, "balanceOf", addr, id); err != nil {
+		return nil, fmt.Errorf("multitoken %s balanceOf %s: %w", m.Address, id, err)
+	}
+	return balance, nil
+}
+
+// BalanceOfBatch returns the balance of owners[i] in token ids[i] for
+// each i, in one call.
+func (m *MultiToken) BalanceOfBatch(ctx context.Context, owners []string, ids []*big.Int) ([]*big.Int, error) {
+	if len(owners) != len(ids) {
+		return nil, fmt.Errorf("multitoken %s balanceOfBatch: %d owners but %d ids", m.Address, len(owners), len(ids))
+	}
+	addrs := make([]common.Address, len(owners))
+	for i, owner := range owners {
+		addr, err := addressArg("multitoken", "owner", owner)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+	var balances []*big.Int
+	if err := m.contract.CallInto(ctx, &balances, "balanceOfBatch", addrs, ids); err != nil {
+		return nil, fmt.Errorf("multitoken %s balanceOfBatch: %w", m.Address, err)
+	}
+	return balances, nil
+}
+
+// IsApprovedForAll reports whether operator may transfer all of owner's
+// tokens.
+func (m *MultiToken) IsApprovedForAll(ctx context.Context, owner, operator string) (bool, error) {
+	ownerAddr, err := addressArg("multitoken", "owner", owner)
+	if err != nil {
+		return false, err
+	}
+	operatorAddr, err := addressArg("multitoken", "operator", operator)
+	if err != nil {
+		return false, err
+	}
+	var approved bool
+	if err := m.contract.CallInto(ctx, &approved, "isApprovedForAll", ownerAddr, operatorAddr); err != nil {
+		return false, fmt.Errorf("multitoken %s isApprovedForAll: %w", m.Address, err)
+	}
+	return approved, nil
+}
+
+// URI returns the metadata URI of token id, with any "{id}" replaced by
+// the id as 64 lowercase hex digits, as the standard prescribes.
+func (m *MultiToken) URI(ctx context.Context, id *big.Int) (string, error) {
+	var uri string
+	if err := m.contract.CallInto(ctx, &uri, "uri", id); err != nil {
+		return "", fmt.Errorf("multitoken %s uri %s: %w", m.Address, id, err)
+	}
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id)), nil
+}
+
+// SafeTransferFrom transfers amount tokens of type id from from to to. The
+// wallet must be from or an operator approved by it.
+func (m *MultiToken) SafeTransferFrom(ctx context.Context, from, to string, id, amount *big.Int) (string, error) {
+	return m.transfer(ctx, from, to, []*big.Int{id}, []*big.Int{amount})
+}
+
+// SafeBatchTransferFrom transfers amounts[i] tokens of type ids[i] for
+// each i from from to to, in a single transaction.
+func (m *MultiToken) SafeBatchTransferFrom(ctx context.Context, from, to string, ids, amounts []*big.Int) (string, error) {
+	return m.transfer(ctx, from, to, ids, amounts)
+}
+
+// SetApprovalForAll lets operator transfer all of the wallet's tokens, or
+// revokes that right.
+func (m *MultiToken) SetApprovalForAll(ctx context.Context, operator string, approved bool) (string, error) {
+	operatorAddr, err := addressArg("multitoken", "operator", operator)
+	if err != nil {
+		return "", err
+	}
+	txHash, err := m.contract.transact(ctx, nil, nil, "setApprovalForAll", operatorAddr, approved)
+	if err != nil {
+		return "", fmt.Errorf("multitoken %s setApprovalForAll: %w", m.Address, err)
+	}
+	return txHash, nil
+}
+
+// transfer sends safeTransferFrom for a single id and
+// safeBatchTransferFrom otherwise, listing the ids and amounts among the
+// "send" tool's arguments.
+func (m *MultiToken) transfer(ctx context.Context, from, to string, ids, amounts []*big.Int) (string, error) {
+	method := "safeBatchTransferFrom"
+	if len(ids) == 1 {
+		method = "safeTransferFrom"
+	}
+	fromAddr, err := addressArg("multitoken", "from", from)
+	if err != nil {
+		return "", err
+	}
+	toAddr, err := addressArg("multitoken", "to", to)
+	if err != nil {
+		return "", err
+	}
+	if len(ids) == 0 || len(ids) != len(amounts) {
+		return "", fmt.Errorf("multitoken %s %s: %d ids but %d amounts", m.Address, method, len(ids), len(amounts))
+	}
+	idStrings := make([]string, len(ids))
+	amountStrings := make([]string, len(amounts))
+	for i := range ids {
+		if ids[i] == nil || amounts[i] == nil || amounts[i].Sign() < 0 {
+			return "", fmt.Errorf("multitoken %s %s: ids and amounts must be non‑negative integers", m.Address, method)
+		}
+		idStrings[i], amountStrings[i] = ids[i].String(), amounts[i].String()
+	}
+	extra := map[string]interface{}{"token_ids": idStrings, "token_amounts": amountStrings}
+
+	var txHash string
+	if method == "safeTransferFrom" {
+		txHash, err = m.contract.transact(ctx, nil, extra, method, fromAddr, toAddr, ids[0], amounts[0], []byte{})
+	} else {
+		txHash, err = m.contract.transact(ctx, nil, extra, method, fromAddr, toAddr, ids, amounts, []byte{})
+	}
+	if err != nil {
+		return "", fmt.Errorf("multitoken %s %s: %w", m.Address, method, err)
+	}
+	return txHash, nil
+}
+
+// EOF: sdk/evm/multitoken.go
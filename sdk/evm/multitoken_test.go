@@ -0,0 +1,123 @@
+// Package evm_test tests the ERC‑1155 binding against a minimal contract
+// on a simulated chain.
+//
+// File: sdk/evm/multitoken_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	chain "github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/sdk/evm"
+)
+
+// multiTokenInitCode deploys a minimal ERC‑1155 minting 100 of token 1
+// and 5 of token 2 to the deployer. It implements balanceOf,
+// balanceOfBatch, uri (always "ipfs://bafy1155/{id}.json"),
+// setApprovalForAll, isApprovedForAll, safeTransferFrom and
+// safeBatchTransferFrom, which revert unless the caller is the sender or
+// its operator and the balance suffices. It emits no events.
+var multiTokenInitCode = common.FromHex("606433600052600160205260406000205560053360005260026020526040600020556102646100316000396102646000f360003560e01c806300fdd58e146100585780634e1273f4146101f85780630e89341c14610073578063a22cb465146100c7578063e985e9c5146100a7578063f242432a146100e15780632eb2c2d61461013d575b600080fd5b60043560005260243560205260406000205460005260206000f35b602060005260196020527f697066733a2f2f62616679313135352f7b69647d2e6a736f6e0000000000000060405260606000f35b600435600052602435602052600260405260606000205460005260206000f35b602435336000526004356020526002604052606060002055005b600435331460043560005233602052600260405260606000205417156100535760043560005260443560205260406000208054606435808210610053579003905560243560005260443560205260406000208054606435019055005b600435331460043560005233602052600260405260606000205417156100535760443560040135606435600401351415610053575b6044356004013560605110156101f657600435600052606051602002604435016024013560205260406000208054606051602002606435016024013580821061005357900390556024356000526060516020026044350160240135602052604060002080546060516020026064350160240135019055606051600101606052610172565b005b600435600401356024356004013514156100535760005b60043560040135811015610251578060200260043501602401356000528060200260243501602401356020526040600020548160200260c0015260010161020f565b60206080528060a0526020026040016080f3")
+
+func TestMultiToken(t *testing.T) {
+	me, alice := newTestWallet(t), newTestWallet(t)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(me.Address()):    {Balance: big.NewInt(1e18)},
+		common.HexToAddress(alice.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	client, gateway, sends := newSimulatedClient(t, sim, me)
+	aliceClient, _, _ := newSimulatedClient(t, sim, alice)
+	ctx := context.Background()
+	_, addr, err := gateway.DeployContract(ctx, multiTokenInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	mt, err := evm.NewMultiToken(ctx, client, addr.Hex())
+	require.NoError(t, err)
+	aliceMT, err := evm.NewMultiToken(ctx, aliceClient, addr.Hex())
+	require.NoError(t, err)
+	one, two := big.NewInt(1), big.NewInt(2)
+	balances := func() []string {
+		t.Helper()
+		b, err := mt.BalanceOfBatch(ctx,
+			[]string{me.Address(), me.Address(), alice.Address(), alice.Address()},
+			[]*big.Int{one, two, one, two})
+		require.NoError(t, err)
+		out := make([]string, len(b))
+		for i := range b {
+			out[i] = b[i].String()
+		}
+		return out
+	}
+
+	balance, err := mt.BalanceOf(ctx, me.Address(), one)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), balance)
+	assert.Equal(t, []string{"100", "5", "0", "0"}, balances())
+
+	uri, err := mt.URI(ctx, big.NewInt(0x4cce))
+	require.NoError(t, err)
+	assert.Equal(t, "ipfs://bafy1155/0000000000000000000000000000000000000000000000000000000000004cce.json", uri)
+
+	t.Run("single transfer", func(t *testing.T) {
+		_, err := mt.SafeTransferFrom(ctx, me.Address(), alice.Address(), one, big.NewInt(10))
+		require.NoError(t, err)
+		sim.Commit()
+		assert.Equal(t, []string{"90", "5", "10", "0"}, balances())
+	})
+
+	t.Run("batch transfer", func(t *testing.T) {
+		block, err := sim.Client().BlockNumber(ctx)
+		require.NoError(t, err)
+		_, err = mt.SafeBatchTransferFrom(ctx, me.Address(), alice.Address(),
+			[]*big.Int{one, two}, []*big.Int{big.NewInt(40), big.NewInt(3)})
+		require.NoError(t, err)
+		sim.Commit()
+		assert.Equal(t, []string{"50", "2", "50", "3"}, balances())
+
+		// One transaction moved both ids, and the tool call lists them.
+		head, err := sim.Client().BlockByNumber(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, block+1, head.NumberU64())
+		assert.Len(t, head.Transactions(), 1)
+		last := (*sends)[len(*sends)-1]
+		assert.Equal(t, []string{"1", "2"}, last["token_ids"])
+		assert.Equal(t, []string{"40", "3"}, last["token_amounts"])
+
+		_, err = mt.SafeBatchTransferFrom(ctx, me.Address(), alice.Address(),
+			[]*big.Int{one, two}, []*big.Int{big.NewInt(1), big.NewInt(3)})
+		assert.ErrorIs(t, err, chain.ErrWouldRevert, "only 2 of token 2 left")
+		_, err = mt.SafeBatchTransferFrom(ctx, me.Address(), alice.Address(), []*big.Int{one, two}, []*big.Int{one})
+		assert.ErrorContains(t, err, "2 ids but 1 amounts")
+	})
+
+	t.Run("approval for all", func(t *testing.T) {
+		_, err := aliceMT.SafeTransferFrom(ctx, me.Address(), alice.Address(), one, one)
+		assert.ErrorIs(t, err, chain.ErrWouldRevert, "alice is not an operator")
+
+		_, err = mt.SetApprovalForAll(ctx, alice.Address(), true)
+		require.NoError(t, err)
+		sim.Commit()
+		ok, err := mt.IsApprovedForAll(ctx, me.Address(), alice.Address())
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		_, err = aliceMT.SafeBatchTransferFrom(ctx, me.Address(), alice.Address(),
+			[]*big.Int{one, two}, []*big.Int{big.NewInt(50), big.NewInt(2)})
+		require.NoError(t, err)
+		sim.Commit()
+		assert.Equal(t, []string{"0", "0", "100", "5"}, balances())
+	})
+}
+
+// EOF: sdk/evm/multitoken_test.go
@@ -0,0 +1,269 @@
+// Package evm provides a binding for ERC‑721 NFTs.
+//
+// File: sdk/evm/nft.go
+
+package evm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ERC721ABI is the ABI of the ERC‑721 non‑fungible token standard, with
+// the optional metadata extension.
+const ERC721ABI = `[
+	{"type":"function","name":"name","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"symbol","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"tokenURI","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"ownerOf","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}],"stateMutability":"view"},
+	{"type":"function","name":"getApproved","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}],"stateMutability":"view"},
+	{"type":"function","name":"isApprovedForAll","inputs":[{"name":"owner","type":"address"},{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"view"},
+	{"type":"function","name":"approve","inputs":[{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"setApprovalForAll","inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"transferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}],"anonymous":false},
+	{"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"approved","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}],"anonymous":false},
+	{"type":"event","name":"ApprovalForAll","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"operator","type":"address","indexed":true},{"name":"approved","type":"bool","indexed":false}],"anonymous":false}
+]`
+
+// DefaultIPFSGateway resolves ipfs:// URIs when NFT.IPFSGateway is empty.
+const DefaultIPFSGateway = "https://ipfs.io/ipfs/"
+
+// maxMetadataSize bounds the metadata document NFT.Metadata reads.
+const maxMetadataSize = 1 << 20
+
+// NFT is an ERC‑721 collection. Transfers and approvals run as the "send"
+// tool, so security policies apply to them and the tool log records the
+// token id.
+type NFT struct {
+	// Address is the collection contract's checksummed address.
+	Address string
+	// IPFSGateway is the HTTP gateway prefix that ipfs://<cid>/<path> URIs
+	// are resolved against (empty = DefaultIPFSGateway).
+	IPFSGateway string
+	// HTTPClient fetches metadata (nil = http.DefaultClient).
+	HTTPClient *http.Client
+
+	contract *contract
+}
+
+// NewNFT binds the ERC‑721 collection at address, a hex address or ENS
+// name. An address without code is an error.
+func NewNFT(ctx context.Context, client *Client, address string) (*NFT, error) {
+	gw, err := client.gateway()
+	if err != nil {
+		return nil, err
+	}
+	isContract, err := gw.IsContract(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("nft %s: %w", address, err)
+	}
+	if !isContract {
+		return nil, fmt.Errorf("nft %s: no contract at address", address)
+	}
+	bound, err := BindContract(ctx, client, address, ERC721ABI)
+	if err != nil {
+		return nil, fmt.Errorf("nft %s: %w", address, err)
+	}
+	c := bound.(*contract)
+	return &NFT{Address: c.Address().Hex(), contract: c}, nil
+}
+
+// OwnerOf returns the owner of token id.
+func (n *NFT) OwnerOf(ctx context.Context, id *big.Int) (string, error) {
+	var owner common.Address
+	if err := n.contract.CallInto(ctx, &owner, "ownerOf", id); err != nil {
+		return "", fmt.Errorf("nft %s ownerOf %s: %w", n.Address, id, err)
+	}
+	return owner.Hex(), nil
+}
+
+// BalanceOf returns how many tokens of the collection owner holds.
+func (n *NFT) BalanceOf(ctx context.Context, owner string) (*big.Int, error) {
+	addr, err := addressArg("nft", "owner", owner)
+	if err != nil {
+		return nil, err
+	}
+	var balance *big.Int
+	if err := n.contract.CallInto(ctx, &balance, "balanceOf", addr); err != nil {
+		return nil, fmt.Errorf("nft %s balanceOf: %w", n.Address, err)
+	}
+	return balance, nil
+}
+
+// GetApproved returns the address approved to transfer token id, or the
+// zero address if there is none.
+func (n *NFT) GetApproved(ctx context.Context, id *big.Int) (string, error) {
+	var approved common.Address
+	if err := n.contract.CallInto(ctx, &approved, "getApproved", id); err != nil {
+		return "", fmt.Errorf("nft %s getApproved %s: %w", n.Address, id, err)
+	}
+	return approved.Hex(), nil
+}
+
+// IsApprovedForAll reports whether operator may transfer all of owner's
+// tokens.
+func (n *NFT) IsApprovedForAll(ctx context.Context, owner, operator string) (bool, error) {
+	ownerAddr, err := addressArg("nft", "owner", owner)
+	if err != nil {
+		return false, err
+	}
+	operatorAddr, err := addressArg("nft", "operator", operator)
+	if err != nil {
+		return false, err
+	}
+	var approved bool
+	if err := n.contract.CallInto(ctx, &approved, "isApprovedForAll", ownerAddr, operatorAddr); err != nil {
+		return false, fmt.Errorf("nft %s isApprovedForAll: %w", n.Address, err)
+	}
+	return approved, nil
+}
+
+// TokenURI returns the metadata URI of token id as stored on chain.
+func (n *NFT) TokenURI(ctx context.Context, id *big.Int) (string, error) {
+	var uri string
+	if err := n.contract.CallInto(ctx, &uri, "tokenURI", id); err != nil {
+		return "", fmt.Errorf("nft %s tokenURI %s: %w", n.Address, id, err)
+	}
+	return uri, nil
+}
+
+// ResolveURI maps an ipfs:// URI to its URL on the IPFS gateway. Other
+// URIs are returned unchanged.
+func (n *NFT) ResolveURI(uri string) string {
+	if !strings.HasPrefix(uri, "ipfs://") {
+		return uri
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+	gateway := n.IPFSGateway
+	if gateway == "" {
+		gateway = DefaultIPFSGateway
+	}
+	return strings.TrimSuffix(gateway, "/") + "/" + path
+}
+
+// Metadata fetches the metadata JSON of token id from its token URI,
+// resolving ipfs:// URIs through the IPFS gateway. On‑chain data: URIs
+// (plain or base64) are decoded without a request.
+func (n *NFT) Metadata(ctx context.Context, id *big.Int) (json.RawMessage, error) {
+	uri, err := n.TokenURI(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	body, err := n.fetch(ctx, n.ResolveURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("nft %s metadata %s: %w", n.Address, id, err)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("nft %s metadata %s: %s is not JSON", n.Address, id, uri)
+	}
+	return json.RawMessage(body), nil
+}
+
+// fetch reads the document at a data:, http: or https: URI.
+func (n *NFT) fetch(ctx context.Context, uri string) ([]byte, error) {
+	if strings.HasPrefix(uri, "data:") {
+		meta, data, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+		if !ok {
+			return nil, fmt.Errorf("malformed data URI")
+		}
+		if strings.HasSuffix(meta, ";base64") {
+			return base64.StdEncoding.DecodeString(data)
+		}
+		decoded, err := url.PathUnescape(data)
+		if err != nil {
+			return nil, fmt.Errorf("data URI: %w", err)
+		}
+		return []byte(decoded), nil
+	}
+	if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		return nil, fmt.Errorf("unsupported token URI %q", uri)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := n.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", uri, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxMetadataSize {
+		return nil, fmt.Errorf("GET %s: metadata larger than %d bytes", uri, maxMetadataSize)
+	}
+	return body, nil
+}
+
+// SafeTransferFrom transfers token id from from to to, checking that a
+// contract recipient accepts ERC‑721 tokens. The wallet must own the
+// token or be approved for it.
+func (n *NFT) SafeTransferFrom(ctx context.Context, from, to string, id *big.Int) (string, error) {
+	fromAddr, err := addressArg("nft", "from", from)
+	if err != nil {
+		return "", err
+	}
+	toAddr, err := addressArg("nft", "to", to)
+	if err != nil {
+		return "", err
+	}
+	return n.transact(ctx, id, "safeTransferFrom(address,address,uint256)", fromAddr, toAddr, id)
+}
+
+// Approve lets to transfer token id; the zero address clears the
+// approval.
+func (n *NFT) Approve(ctx context.Context, to string, id *big.Int) (string, error) {
+	toAddr, err := addressArg("nft", "to", to)
+	if err != nil {
+		return "", err
+	}
+	return n.transact(ctx, id, "approve", toAddr, id)
+}
+
+// SetApprovalForAll lets operator transfer all of the wallet's tokens in
+// the collection, or revokes that right.
+func (n *NFT) SetApprovalForAll(ctx context.Context, operator string, approved bool) (string, error) {
+	operatorAddr, err := addressArg("nft", "operator", operator)
+	if err != nil {
+		return "", err
+	}
+	return n.transact(ctx, nil, "setApprovalForAll", operatorAddr, approved)
+}
+
+// transact sends method through the "send" tool with the token id, if
+// any, among the tool's arguments.
+func (n *NFT) transact(ctx context.Context, id *big.Int, method string, args ...interface{}) (string, error) {
+	var extra map[string]interface{}
+	if id != nil {
+		extra = map[string]interface{}{"token_id": id.String()}
+	}
+	txHash, err := n.contract.transact(ctx, nil, extra, method, args...)
+	if err != nil {
+		return "", fmt.Errorf("nft %s %s: %w", n.Address, strings.SplitN(method, "(", 2)[0], err)
+	}
+	return txHash, nil
+}
+
+// EOF: sdk/evm/nft.go
@@ -0,0 +1,151 @@
+// Package evm_test tests the ERC‑721 binding against a minimal collection
+// on a simulated chain.
+//
+// File: sdk/evm/nft_test.go
+
+package evm_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	chain "github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/sdk/evm"
+)
+
+// nftInitCode deploys a minimal ERC‑721 minting tokens 1, 2 and 3 to the
+// deployer. It implements balanceOf, ownerOf, tokenURI (always
+// "ipfs://bafynft/1.json"), approve, getApproved, setApprovalForAll,
+// isApprovedForAll, transferFrom and the three‑argument safeTransferFrom,
+// which revert unless the caller owns the token or is approved for it.
+var nftInitCode = common.FromHex("3360016000526000602052604060002055336002600052600060205260406000205533600360005260006020526040600020556003335561020b61004660003961020b6000f360003560e01c806370a082311461006e5780636352211e1461007b578063c87b56dd1461009b578063095ea7b314610123578063081812fc146100cf578063a22cb46514610109578063e985e9c5146100e957806342842e0e1461015157806323b872dd14610151575b600080fd5b6004355460005260206000f35b600435600052600060205260406000205480156100695760005260206000f35b602060005260156020527f697066733a2f2f626166796e66742f312e6a736f6e000000000000000000000060405260606000f35b600435600052600160205260406000205460005260206000f35b600435600052602435602052600260405260606000205460005260206000f35b602435336000526004356020526002604052606060002055005b6024356000526000602052604060002054331415610069576004356024356000526001602052604060002055005b60443560005260006020526040600020548060043514156100695780331460443560005260016020526040600020543314178160005233602052600260405260606000205417156100695750600060443560005260016020526040600020556024356044356000526000602052604060002055600160043554036004355560243554600101602435556044356024356004357fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60006000a400")
+
+func TestNFT(t *testing.T) {
+	me, alice := newTestWallet(t), newTestWallet(t)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(me.Address()):    {Balance: big.NewInt(1e18)},
+		common.HexToAddress(alice.Address()): {Balance: big.NewInt(1e18)},
+	})
+	defer sim.Close()
+	client, gateway, sends := newSimulatedClient(t, sim, me)
+	aliceClient, _, _ := newSimulatedClient(t, sim, alice)
+	ctx := context.Background()
+	_, addr, err := gateway.DeployContract(ctx, nftInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	nft, err := evm.NewNFT(ctx, client, addr.Hex())
+	require.NoError(t, err)
+	aliceNFT, err := evm.NewNFT(ctx, aliceClient, addr.Hex())
+	require.NoError(t, err)
+	owner := func(id int64) string {
+		t.Helper()
+		o, err := nft.OwnerOf(ctx, big.NewInt(id))
+		require.NoError(t, err)
+		return o
+	}
+
+	balance, err := nft.BalanceOf(ctx, me.Address())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(3), balance)
+	assert.Equal(t, me.Address(), owner(1))
+	_, err = nft.OwnerOf(ctx, big.NewInt(4))
+	assert.Error(t, err, "unminted token")
+
+	t.Run("transfer", func(t *testing.T) {
+		_, err := nft.SafeTransferFrom(ctx, me.Address(), alice.Address(), big.NewInt(1))
+		require.NoError(t, err)
+		sim.Commit()
+		assert.Equal(t, alice.Address(), owner(1))
+		balance, err := nft.BalanceOf(ctx, alice.Address())
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1), balance)
+
+		// The transfer ran as the "send" tool, with the token id logged.
+		require.NotEmpty(t, *sends)
+		assert.Equal(t, "1", (*sends)[len(*sends)-1]["token_id"])
+
+		// Alice cannot move a token she neither owns nor is approved for.
+		_, err = aliceNFT.SafeTransferFrom(ctx, me.Address(), alice.Address(), big.NewInt(2))
+		assert.ErrorIs(t, err, chain.ErrWouldRevert)
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		_, err := nft.Approve(ctx, alice.Address(), big.NewInt(2))
+		require.NoError(t, err)
+		sim.Commit()
+		approved, err := nft.GetApproved(ctx, big.NewInt(2))
+		require.NoError(t, err)
+		assert.Equal(t, alice.Address(), approved)
+		assert.Equal(t, "2", (*sends)[len(*sends)-1]["token_id"])
+
+		_, err = aliceNFT.SafeTransferFrom(ctx, me.Address(), alice.Address(), big.NewInt(2))
+		require.NoError(t, err)
+		sim.Commit()
+		assert.Equal(t, alice.Address(), owner(2))
+		approved, err = nft.GetApproved(ctx, big.NewInt(2))
+		require.NoError(t, err)
+		assert.Equal(t, common.Address{}.Hex(), approved, "a transfer clears the approval")
+	})
+
+	t.Run("approval for all", func(t *testing.T) {
+		ok, err := nft.IsApprovedForAll(ctx, me.Address(), alice.Address())
+		require.NoError(t, err)
+		assert.False(t, ok)
+		_, err = nft.SetApprovalForAll(ctx, alice.Address(), true)
+		require.NoError(t, err)
+		sim.Commit()
+		ok, err = nft.IsApprovedForAll(ctx, me.Address(), alice.Address())
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		_, err = aliceNFT.SafeTransferFrom(ctx, me.Address(), alice.Address(), big.NewInt(3))
+		require.NoError(t, err)
+		sim.Commit()
+		assert.Equal(t, alice.Address(), owner(3))
+		balance, err := nft.BalanceOf(ctx, me.Address())
+		require.NoError(t, err)
+		assert.Zero(t, balance.Sign())
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		uri, err := nft.TokenURI(ctx, big.NewInt(1))
+		require.NoError(t, err)
+		assert.Equal(t, "ipfs://bafynft/1.json", uri)
+		assert.Equal(t, "https://ipfs.io/ipfs/bafynft/1.json", nft.ResolveURI(uri))
+
+		ipfs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/ipfs/bafynft/1.json" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(`{"name":"Token #1","image":"ipfs://bafyimg/1.png"}`))
+		}))
+		defer ipfs.Close()
+		nft.IPFSGateway = ipfs.URL + "/ipfs/"
+		meta, err := nft.Metadata(ctx, big.NewInt(1))
+		require.NoError(t, err)
+		var doc struct{ Name string }
+		require.NoError(t, json.Unmarshal(meta, &doc))
+		assert.Equal(t, "Token #1", doc.Name)
+
+		nft.IPFSGateway = ipfs.URL + "/missing/"
+		_, err = nft.Metadata(ctx, big.NewInt(1))
+		assert.ErrorContains(t, err, "404")
+	})
+
+	_, err = evm.NewNFT(ctx, client, "0x00000000000000000000000000000000000A11cE")
+	assert.ErrorContains(t, err, "no contract at address")
+}
+
+// EOF: sdk/evm/nft_test.go
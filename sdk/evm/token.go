@@ -0,0 +1,218 @@
+// Package evm provides a binding for ERC‑20 tokens.
+//
+// File: sdk/evm/token.go
+
+package evm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/sdk/types"
+	"github.com/0xSemantic/lola-os/sdk/types/units"
+)
+
+// ERC20ABI is the ABI of the ERC‑20 token standard.
+const ERC20ABI = `[
+	{"type":"function","name":"name","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"symbol","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"decimals","inputs":[],"outputs":[{"name":"","type":"uint8"}],"stateMutability":"view"},
+	{"type":"function","name":"totalSupply","inputs":[],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"allowance","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"transferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}],"anonymous":false},
+	{"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}],"anonymous":false}
+]`
+
+// DefaultTokenDecimals is assumed for tokens without a decimals() method.
+const DefaultTokenDecimals = 18
+
+// Token is an ERC‑20 token. Its name, symbol and decimals are read once by
+// NewToken. Amounts are in the token's base units; use FormatAmount and
+// ParseAmount to convert to and from whole tokens.
+type Token struct {
+	// Address is the token contract's checksummed address.
+	Address string
+	// Name and Symbol are empty if the token does not provide them.
+	// Symbols returned as bytes32, as by MKR, are decoded.
+	Name   string
+	Symbol string
+	// Decimals is DefaultTokenDecimals if the token does not provide it.
+	Decimals uint8
+
+	contract types.Contract
+}
+
+// NewToken binds the ERC‑20 token at address, a hex address or ENS name,
+// and reads its metadata. Missing or non‑standard name, symbol and
+// decimals methods are tolerated; an address without code is an error.
+// Transfers and approvals run through the client's security policies like
+// any other contract transaction.
+func NewToken(ctx context.Context, client *Client, address string) (*Token, error) {
+	gw, err := client.gateway()
+	if err != nil {
+		return nil, err
+	}
+	isContract, err := gw.IsContract(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("token %s: %w", address, err)
+	}
+	if !isContract {
+		return nil, fmt.Errorf("token %s: no contract at address", address)
+	}
+	bound, err := BindContract(ctx, client, address, ERC20ABI)
+	if err != nil {
+		return nil, fmt.Errorf("token %s: %w", address, err)
+	}
+	t := &Token{
+		Address:  bound.(*contract).Address().Hex(),
+		Decimals: DefaultTokenDecimals,
+		contract: bound,
+	}
+	t.Name = t.metadata(ctx, client, "name")
+	t.Symbol = t.metadata(ctx, client, "symbol")
+	var decimals uint8
+	if err := bound.CallInto(ctx, &decimals, "decimals"); err == nil {
+		t.Decimals = decimals
+	}
+	return t, nil
+}
+
+// metadata reads a string method such as name(), which some older tokens
+// return as bytes32. It returns "" if the call fails or cannot be decoded.
+func (t *Token) metadata(ctx context.Context, client *Client, method string) string {
+	data, err := evm.EncodeCall(ERC20ABI, method)
+	if err != nil {
+		return ""
+	}
+	res, err := client.CallContract(ctx, &types.ContractCall{To: t.Address, Data: data})
+	if err != nil {
+		return ""
+	}
+	if values, err := evm.DecodeResult(ERC20ABI, method, res); err == nil {
+		return values[0].(string)
+	}
+	if len(res) == 32 {
+		return strings.ToValidUTF8(string(bytes.TrimRight(res, "\x00")), "")
+	}
+	return ""
+}
+
+// BalanceOf returns owner's balance in base units.
+func (t *Token) BalanceOf(ctx context.Context, owner string) (*big.Int, error) {
+	addr, err := addressArg("token", "owner", owner)
+	if err != nil {
+		return nil, err
+	}
+	var balance *big.Int
+	if err := t.contract.CallInto(ctx, &balance, "balanceOf", addr); err != nil {
+		return nil, fmt.Errorf("%s balanceOf: %w", t, err)
+	}
+	return balance, nil
+}
+
+// Allowance returns how much spender may still transfer from owner.
+func (t *Token) Allowance(ctx context.Context, owner, spender string) (*big.Int, error) {
+	ownerAddr, err := addressArg("token", "owner", owner)
+	if err != nil {
+		return nil, err
+	}
+	spenderAddr, err := addressArg("token", "spender", spender)
+	if err != nil {
+		return nil, err
+	}
+	var allowance *big.Int
+	if err := t.contract.CallInto(ctx, &allowance, "allowance", ownerAddr, spenderAddr); err != nil {
+		return nil, fmt.Errorf("%s allowance: %w", t, err)
+	}
+	return allowance, nil
+}
+
+// Transfer sends amount base units from the wallet to to and returns the
+// transaction hash. The transaction is simulated first, so a transfer
+// exceeding the balance fails without being sent.
+func (t *Token) Transfer(ctx context.Context, to string, amount *big.Int) (string, error) {
+	toAddr, err := addressArg("token", "to", to)
+	if err != nil {
+		return "", err
+	}
+	return t.transact(ctx, "transfer", toAddr, amount)
+}
+
+// Approve allows spender to transfer up to amount base units from the
+// wallet, replacing any previous allowance.
+func (t *Token) Approve(ctx context.Context, spender string, amount *big.Int) (string, error) {
+	spenderAddr, err := addressArg("token", "spender", spender)
+	if err != nil {
+		return "", err
+	}
+	return t.transact(ctx, "approve", spenderAddr, amount)
+}
+
+// TransferFrom sends amount base units from from to to, spending the
+// allowance from has given the wallet.
+func (t *Token) TransferFrom(ctx context.Context, from, to string, amount *big.Int) (string, error) {
+	fromAddr, err := addressArg("token", "from", from)
+	if err != nil {
+		return "", err
+	}
+	toAddr, err := addressArg("token", "to", to)
+	if err != nil {
+		return "", err
+	}
+	return t.transact(ctx, "transferFrom", fromAddr, toAddr, amount)
+}
+
+// transact checks the amount, the last of args, and sends the transaction
+// through the contract binding, so it runs as the "send" tool.
+func (t *Token) transact(ctx context.Context, method string, args ...interface{}) (string, error) {
+	if amount := args[len(args)-1].(*big.Int); amount == nil || amount.Sign() < 0 {
+		return "", fmt.Errorf("%s %s: amount must be a non‑negative integer", t, method)
+	}
+	txHash, err := t.contract.Transact(ctx, method, args...)
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %w", t, method, err)
+	}
+	return txHash, nil
+}
+
+// FormatAmount renders an amount in base units as whole tokens, e.g.
+// 1500000 as "1.5" for a token with 6 decimals.
+func (t *Token) FormatAmount(amount *big.Int) string {
+	return units.FormatUnits(amount, int(t.Decimals), -1)
+}
+
+// ParseAmount converts a decimal amount of whole tokens, such as "1.5",
+// to base units.
+func (t *Token) ParseAmount(amount string) (*big.Int, error) {
+	return units.ParseUnits(amount, int(t.Decimals))
+}
+
+// String names the token by symbol, or by address if it has none.
+func (t *Token) String() string {
+	if t.Symbol != "" {
+		return t.Symbol
+	}
+	return t.Address
+}
+
+// addressArg validates a hex address argument of a token method; kind
+// prefixes the error.
+func addressArg(kind, arg, address string) (common.Address, error) {
+	normalized, err := types.NormalizeAddress(address)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%s %s: %w", kind, arg, err)
+	}
+	return common.HexToAddress(normalized), nil
+}
+
+// EOF: sdk/evm/token.go
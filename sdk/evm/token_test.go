@@ -0,0 +1,155 @@
+// Package evm_test tests the ERC‑20 token binding against minimal tokens
+// on a simulated chain.
+//
+// File: sdk/evm/token_test.go
+
+package evm_test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	chain "github.com/0xSemantic/lola-os/internal/blockchain/evm"
+	"github.com/0xSemantic/lola-os/internal/core"
+	"github.com/0xSemantic/lola-os/internal/observe"
+	"github.com/0xSemantic/lola-os/internal/tools/builtin"
+	"github.com/0xSemantic/lola-os/sdk/evm"
+)
+
+// tokenInitCode deploys a minimal ERC‑20, "Test Token" (TST) with 6
+// decimals, minting 1,000,000 TST to the deployer. It implements
+// balanceOf, allowance, transfer, approve and transferFrom, reverting on
+// insufficient balance or allowance, and logs Transfer events.
+var tokenInitCode = common.FromHex("7f000000000000000000000000000000000000000000000000000000e8d4a5100033556101ef6100326000396101ef6000f360003560e01c806306fdde031461006357806395d89b4114610097578063313ce567146100cb57806370a08231146100d6578063dd62ed3e146100e3578063a9059cbb1461011c578063095ea7b3146100fe57806323b872dd14610172575b600080fd5b6020600052600a6020527f5465737420546f6b656e0000000000000000000000000000000000000000000060405260606000f35b602060005260036020527f545354000000000000000000000000000000000000000000000000000000000060405260606000f35b600660005260206000f35b6004355460005260206000f35b60043560005260243560205260406000205460005260206000f35b60243533600052600435602052604060002055600160005260206000f35b602435335481811061005e57033355602435600435540160043555602435600052600435337fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a3600160005260206000f35b6004356000523360205260443560406000205481811061005e57036040600020556044356004355481811061005e5703600435556044356024355401602435556044356000526024356004357fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a3600160005260206000f3")
+
+// bytes32TokenInitCode deploys a token in the style of MKR, which returns
+// its symbol as bytes32, and one step further: it has no name() or
+// decimals(), and no balances.
+var bytes32TokenInitCode = common.FromHex("61005661000f6000396100566000f360003560e01c806395d89b411461002157806370a082311461004b575b600080fd5b7f4d4b52000000000000000000000000000000000000000000000000000000000060005260206000f35b600060005260206000f3")
+
+// newTokenClient returns a client on a simulated chain whose transactions
+// run the builtin tools, recording the arguments of each "send".
+func newTokenClient(t *testing.T) (*evm.Client, *simulated.Backend, *chain.EVMGateway, *[]map[string]interface{}) {
+	t.Helper()
+	wallet := newTestWallet(t)
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		common.HexToAddress(wallet.Address()): {Balance: big.NewInt(1e18)},
+	})
+	t.Cleanup(func() { sim.Close() })
+	client, gateway, sends := newSimulatedClient(t, sim, wallet)
+	return client, sim, gateway, sends
+}
+
+// newTestWallet creates a wallet in a temporary keystore.
+func newTestWallet(t *testing.T) *chain.Keystore {
+	t.Helper()
+	wallet, err := chain.NewKeystore(filepath.Join(t.TempDir(), "wallet.key"), "test")
+	require.NoError(t, err)
+	return wallet
+}
+
+// newSimulatedClient returns a client for wallet on sim whose transactions
+// run the "send" tool, recording its arguments in sends.
+func newSimulatedClient(t *testing.T, sim *simulated.Backend, wallet *chain.Keystore) (*evm.Client, *chain.EVMGateway, *[]map[string]interface{}) {
+	t.Helper()
+	ec := reflect.ValueOf(sim.Client()).Field(0).Interface().(*ethclient.Client)
+	gateway := chain.NewEVMGatewayFromClient(chain.NewClientFromEthClient(ec, &observe.NoopLogger{}, nil), &observe.NoopLogger{}, wallet)
+	sess := core.NewSession(&observe.NoopLogger{}, "", gateway)
+
+	var sends []map[string]interface{}
+	exec := func(ctx context.Context, tool string, args map[string]interface{}) (interface{}, error) {
+		require.Equal(t, "send", tool)
+		sends = append(sends, args)
+		return builtin.Send(core.ContextWithSession(ctx, sess), args)
+	}
+	return evm.NewClientWithExecutor(sess, exec), gateway, &sends
+}
+
+func TestToken(t *testing.T) {
+	client, sim, gateway, sends := newTokenClient(t)
+	ctx := context.Background()
+	_, addr, err := gateway.DeployContract(ctx, tokenInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	token, err := evm.NewToken(ctx, client, addr.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, addr.Hex(), token.Address)
+	assert.Equal(t, "Test Token", token.Name)
+	assert.Equal(t, "TST", token.Symbol)
+	assert.Equal(t, uint8(6), token.Decimals)
+
+	me := gateway.Wallet().Address()
+	alice := "0x00000000000000000000000000000000000A11cE"
+	balance, err := token.BalanceOf(ctx, me)
+	require.NoError(t, err)
+	assert.Equal(t, "1000000", token.FormatAmount(balance))
+
+	amount, err := token.ParseAmount("1.5")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1500000), amount)
+	_, err = token.Transfer(ctx, alice, amount)
+	require.NoError(t, err)
+	sim.Commit()
+	balance, err = token.BalanceOf(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, "1.5", token.FormatAmount(balance))
+
+	// Approving ourselves lets transferFrom spend our own tokens.
+	_, err = token.Approve(ctx, me, big.NewInt(1000000))
+	require.NoError(t, err)
+	sim.Commit()
+	allowance, err := token.Allowance(ctx, me, me)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000000), allowance)
+	_, err = token.TransferFrom(ctx, me, alice, big.NewInt(400000))
+	require.NoError(t, err)
+	sim.Commit()
+	allowance, err = token.Allowance(ctx, me, me)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(600000), allowance)
+	balance, err = token.BalanceOf(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, "1.9", token.FormatAmount(balance))
+
+	// Writes ran as the "send" tool, so policies and the audit log saw them.
+	assert.Len(t, *sends, 3)
+
+	// An overdraft is caught by the simulation and never sent.
+	_, err = token.TransferFrom(ctx, me, alice, big.NewInt(700000))
+	assert.ErrorIs(t, err, chain.ErrWouldRevert)
+	_, err = token.Transfer(ctx, "0x1234", amount)
+	assert.ErrorContains(t, err, "token to")
+	_, err = token.Transfer(ctx, alice, big.NewInt(-1))
+	assert.ErrorContains(t, err, "non‑negative")
+}
+
+func TestToken_NonStandard(t *testing.T) {
+	client, sim, gateway, _ := newTokenClient(t)
+	ctx := context.Background()
+	_, addr, err := gateway.DeployContract(ctx, bytes32TokenInitCode, nil)
+	require.NoError(t, err)
+	sim.Commit()
+
+	token, err := evm.NewToken(ctx, client, addr.Hex())
+	require.NoError(t, err)
+	assert.Empty(t, token.Name)
+	assert.Equal(t, "MKR", token.Symbol)
+	assert.Equal(t, uint8(evm.DefaultTokenDecimals), token.Decimals)
+	assert.Equal(t, "MKR", token.String())
+
+	_, err = evm.NewToken(ctx, client, "0x00000000000000000000000000000000000A11cE")
+	assert.ErrorContains(t, err, "no contract at address")
+}
+
+// EOF: sdk/evm/token_test.go
@@ -21,11 +21,11 @@ func main() {
 		if err != nil {
 			return err
 		}
-		balance, err := evm.GetBalance(ctx, "0x742d35Cc6634C0532925a3b844Bc9e90F1A6B1E7", nil)
+		balance, err := evm.GetBalanceEther(ctx, "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7", nil)
 		if err != nil {
 			return err
 		}
-		fmt.Printf("Balance: %s wei\n", balance.String())
+		fmt.Printf("Balance: %s ETH\n", balance)
 		return nil
 	})
 
@@ -8,10 +8,10 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"math/big"
 
 	"github.com/0xSemantic/lola-os/sdk"
 	"github.com/0xSemantic/lola-os/sdk/types"
+	"github.com/0xSemantic/lola-os/sdk/types/units"
 )
 
 func main() {
@@ -22,8 +22,11 @@ func main() {
 		if err != nil {
 			return err
 		}
-		to := "0x742d35Cc6634C0532925a3b844Bc9e90F1A6B1E7"
-		amount := big.NewInt(1000000000000000) // 0.001 ETH
+		to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+		amount, err := units.ParseEther("0.001")
+		if err != nil {
+			return err
+		}
 		txHash, err := evm.SendTransaction(ctx, &sdktypes.Transaction{
 			To:    &to,
 			Value: amount,
@@ -9,16 +9,12 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"math/big"
 	"strings"
 
 	"github.com/0xSemantic/lola-os/sdk"
 	"github.com/0xSemantic/lola-os/sdk/evm"
 )
 
-// ERC‑20 ABI (balanceOf)
-const erc20ABI = `[{"constant":true,"inputs":[{"name":"_owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"balance","type":"uint256"}],"type":"function"}]`
-
 // USDC addresses on different chains.
 var usdcAddresses = map[string]string{
 	"ethereum": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
@@ -32,38 +28,35 @@ func main() {
 	rt := sdk.Init()
 
 	err := rt.Run(context.Background(), func(ctx context.Context, rt *sdk.Runtime) error {
-		// Get list of configured chains from runtime config.
-		chains := rt.Config().Chains // we need to expose Config() on Runtime
-		// We'll add this method.
-		
+		// The session has every configured chain; each client works on one.
+		chains := rt.Config().Chains
+
 		for chainID := range usdcAddresses {
 			// Check if this chain is configured.
 			if _, ok := chains[chainID]; !ok {
 				continue
 			}
 
-			evmClient, err := rt.EVM(ctx)
+			evmClient, err := rt.EVM(ctx, chainID)
 			if err != nil {
 				log.Printf("Skipping %s: %v", chainID, err)
 				continue
 			}
 
-			// Create contract binding.
-			contract, err := evm.BindContract(ctx, evmClient, usdcAddresses[chainID], erc20ABI)
+			// Bind the token; its symbol and decimals are read once.
+			usdc, err := evm.NewToken(ctx, evmClient, usdcAddresses[chainID])
 			if err != nil {
 				log.Printf("Failed to bind USDC on %s: %v", chainID, err)
 				continue
 			}
 
-			// Call balanceOf.
-			result, err := contract.Call(ctx, "balanceOf", "0x742d35Cc6634C0532925a3b844Bc9e90F1A6B1E7")
+			balance, err := usdc.BalanceOf(ctx, "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7")
 			if err != nil {
 				log.Printf("Failed to get balance on %s: %v", chainID, err)
 				continue
 			}
 
-			balance := result[0].(*big.Int)
-			fmt.Printf("%s USDC balance: %s\n", strings.Title(chainID), balance.String())
+			fmt.Printf("%s balance: %s %s\n", strings.Title(chainID), usdc.FormatAmount(balance), usdc.Symbol)
 		}
 		return nil
 	})
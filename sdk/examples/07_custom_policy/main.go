@@ -0,0 +1,76 @@
+// Example 07: Register a custom security policy.
+// The policy denies transfers above 0.1 ETH on Fridays, whatever the
+// configured limits allow.
+//
+// File: sdk/examples/07_custom_policy/main.go
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/0xSemantic/lola-os/sdk"
+	"github.com/0xSemantic/lola-os/sdk/security"
+	"github.com/0xSemantic/lola-os/sdk/types"
+	"github.com/0xSemantic/lola-os/sdk/types/units"
+)
+
+// fridayPolicy denies transfers of more than max wei on Fridays.
+type fridayPolicy struct {
+	max *big.Int
+	now func() time.Time
+}
+
+// Name names the policy in decisions and the audit log.
+func (p *fridayPolicy) Name() string { return "no_big_fridays" }
+
+// Check implements security.Policy.
+func (p *fridayPolicy) Check(ctx context.Context, eval *security.Evaluation) error {
+	if p.now().Weekday() != time.Friday || eval.Value.Cmp(p.max) <= 0 {
+		return nil
+	}
+	return fmt.Errorf("no transfers above %s ETH on Fridays (attempted %s ETH to %s)",
+		units.FormatEther(p.max), units.FormatEther(eval.Value), eval.To)
+}
+
+func main() {
+	limit, err := units.ParseEther("0.1")
+	if err != nil {
+		log.Fatal(err)
+	}
+	rt := sdk.Init(sdk.WithPolicy(&fridayPolicy{max: limit, now: time.Now}))
+
+	err = rt.Run(context.Background(), func(ctx context.Context, rt *sdk.Runtime) error {
+		evm, err := rt.EVM(ctx)
+		if err != nil {
+			return err
+		}
+		to := "0x742d35cC6634c0532925A3b844bc9E90F1a6b1E7"
+		amount, err := units.ParseEther("0.5")
+		if err != nil {
+			return err
+		}
+		txHash, err := evm.SendTransaction(ctx, &types.Transaction{To: &to, Value: amount})
+		var denied *sdk.ErrPolicyDenied
+		if errors.As(err, &denied) {
+			fmt.Printf("Denied by %s: %s\n", denied.Decision.PolicyName, denied.Decision.Reason)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Transaction sent: %s\n", txHash)
+		return nil
+	})
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// EOF: sdk/examples/07_custom_policy/main.go
@@ -0,0 +1,26 @@
+// Package sdk provides keystore onboarding helpers.
+//
+// File: sdk/keystore.go
+
+package sdk
+
+import (
+	"fmt"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// ImportKey encrypts an existing private key (hex, with or without 0x)
+// with passphrase into a geth‑compatible keystore file, for use with
+// WithKeystore or the wallet.keystore_path setting, and returns the
+// account's address. An existing keyFile is only overwritten if force is
+// set.
+func ImportKey(keyFile, passphrase, hexKey string, force bool) (string, error) {
+	ks, err := evm.ImportPrivateKey(keyFile, passphrase, hexKey, force)
+	if err != nil {
+		return "", fmt.Errorf("import key: %w", err)
+	}
+	return ks.Address(), nil
+}
+
+// EOF: sdk/keystore.go
@@ -5,9 +5,12 @@
 package sdk
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/0xSemantic/lola-os/internal/config"
+	"github.com/0xSemantic/lola-os/sdk/security"
+	"github.com/0xSemantic/lola-os/sdk/types"
 )
 
 // Option configures the Runtime.
@@ -22,6 +25,13 @@ type options struct {
 	readOnly        bool
 	rpcRetries      int
 	rpcBackoff      time.Duration
+	simulate        bool
+	policyDryRun    bool
+	lazyConnect     bool
+	abiResolver     types.ABIResolver
+	ephemeral       bool
+	ephemeralKey    string
+	policies        []security.Policy
 }
 
 // WithConfigFile adds a YAML configuration file to load.
@@ -75,4 +85,87 @@ func WithRPCBackoff(backoff time.Duration) Option {
 	}
 }
 
+// WithSimulation simulates every transaction with eth_call before signing,
+// so a transaction that would revert fails without spending gas.
+func WithSimulation() Option {
+	return func(o *options) {
+		o.simulate = true
+	}
+}
+
+// WithPolicyDryRun runs every security policy but read‑only and
+// human‑in‑the‑loop as advisory: denials are logged and audited as
+// "would_deny" instead of blocking (see the security.dry_run setting).
+func WithPolicyDryRun() Option {
+	return func(o *options) {
+		o.policyDryRun = true
+	}
+}
+
+// WithLazyConnect keeps chains whose RPC endpoint is unreachable at start-up.
+// They connect on first use; until then calls fail with types.ErrNotConnected.
+// It applies to every chain (see also the per-chain lazy_connect setting).
+func WithLazyConnect() Option {
+	return func(o *options) {
+		o.lazyConnect = true
+	}
+}
+
+// WithABIResolver replaces the default ABI lookup (explorer, then
+// Sourcify) used by evm.BindContractByAddress on every chain, for example
+// with an internal ABI registry.
+func WithABIResolver(resolver types.ABIResolver) Option {
+	return func(o *options) {
+		o.abiResolver = resolver
+	}
+}
+
+// WithEphemeralWallet signs on every chain with a random key held only in
+// memory, replacing any configured wallet. It is meant for tests and local
+// devnets such as Anvil or Hardhat; nothing funds the account, whose
+// address Runtime.EphemeralAddress returns. It fails in read‑only mode.
+func WithEphemeralWallet() Option {
+	return func(o *options) {
+		o.ephemeral = true
+	}
+}
+
+// WithEphemeralWalletKey is like WithEphemeralWallet, but with the private
+// key hexKey, such as a prefunded devnet account or a key allocated in a
+// test genesis.
+func WithEphemeralWalletKey(hexKey string) Option {
+	return func(o *options) {
+		o.ephemeral = true
+		o.ephemeralKey = hexKey
+	}
+}
+
+// WithPolicy adds a security policy of one's own, run on every chain with
+// the configured policies, in the place "custom" has in the policy order
+// (see the security.policy_order setting). Can be called multiple times.
+func WithPolicy(p security.Policy) Option {
+	return func(o *options) {
+		o.policies = append(o.policies, p)
+	}
+}
+
+// RunOption configures one Runtime.Run.
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	budget *big.Int
+}
+
+// WithRunBudget caps what the run may spend in total on each chain, in
+// wei of the chain's native currency, fees included, whatever the daily
+// limit allows: once it is used up on a chain, further transactions there
+// are denied with an *ErrLimitExceeded named "session_budget". A send that
+// fails does not count. The budget ends with the run; see
+// Runtime.RemainingBudget.
+func WithRunBudget(wei *big.Int) RunOption {
+	return func(o *runOptions) {
+		o.budget = new(big.Int).Set(wei)
+	}
+}
+
 // EOF: sdk/options.go
\ No newline at end of file
@@ -6,8 +6,19 @@ package sdk
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/0xSemantic/lola-os/internal/blockchain"
 	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
@@ -18,21 +29,38 @@ import (
 	"github.com/0xSemantic/lola-os/internal/security/policies"
 	"github.com/0xSemantic/lola-os/internal/tools"
 	"github.com/0xSemantic/lola-os/sdk/evm"
+	sdksecurity "github.com/0xSemantic/lola-os/sdk/security"
+	"github.com/0xSemantic/lola-os/sdk/types"
 )
 
 // Runtime is the primary handle for LOLA OS operations.
 // It holds the engine, configuration, and observability components.
 type Runtime struct {
-	engine   *core.Engine
-	config   *config.Config
-	logger   observe.Logger
-	metrics  observe.Metrics
-	tracer   observe.Tracer
-	audit    *observe.AuditLogger
-	chains   map[string]blockchain.Chain // chain ID -> Chain
-	mu       sync.RWMutex
+	engine    *core.Engine
+	config    *config.Config
+	logger    observe.Logger
+	metrics   observe.Metrics
+	tracer    observe.Tracer
+	audit     *observe.AuditLogger
+	chains    map[string]blockchain.Chain  // chain ID -> Chain
+	server    *http.Server                 // metrics endpoint; nil if not served
+	vault     *evm.VaultWallet             // nil unless the wallet is in Vault
+	ephemeral *evm.MemoryWallet            // nil unless WithEphemeralWallet
+	rate      *policies.RatePolicy         // nil unless rate_limit is set
+	denylist  *policies.DenylistFeedPolicy // nil unless denylist is set
+	approvals *policies.ApprovalQueue      // nil unless HITL mode is api
+	apiServer *http.Server                 // serves approvals; nil unless HITL mode is api
+	addCustom func(security.CustomPolicy)  // adds a policy named "custom"
+	pause     func(reason string)          // pauses writes
+	resume    func()                       // ends a pause by pause
+	paused    func() *security.ErrPaused   // the pause in force, nil if none
+	mu        sync.RWMutex
 }
 
+// serverShutdownTimeout bounds how long Close waits for in‑flight metrics
+// scrapes to finish.
+const serverShutdownTimeout = 5 * time.Second
+
 // newRuntime constructs a fully wired Runtime from configuration.
 func newRuntime(cfg *config.Config, opts *options) (*Runtime, error) {
 	// 1. Initialize logger.
@@ -47,13 +75,18 @@ func newRuntime(cfg *config.Config, opts *options) (*Runtime, error) {
 
 	// 2. Initialize metrics (if enabled).
 	var metrics observe.Metrics = &observe.NoopMetrics{}
+	var server *http.Server
+	var mux *http.ServeMux // the metrics server's; nil if not served
 	if cfg.Observability.Metrics.Enabled {
 		metrics = observe.NewPrometheusMetrics("lola", "agent")
-		// Expose metrics endpoint in a goroutine if addr set.
+		// Expose metrics endpoint in a goroutine if addr set. Each runtime
+		// gets its own mux so runtimes can be created more than once.
 		if cfg.Observability.Metrics.Addr != "" {
+			mux = http.NewServeMux()
+			mux.Handle(cfg.Observability.Metrics.Path, metrics.(*observe.PrometheusMetrics).Handler())
+			server = &http.Server{Addr: cfg.Observability.Metrics.Addr, Handler: mux}
 			go func() {
-				http.Handle(cfg.Observability.Metrics.Path, metrics.(*observe.PrometheusMetrics).Handler())
-				if err := http.ListenAndServe(cfg.Observability.Metrics.Addr, nil); err != nil {
+				if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 					logger.Error("metrics server failed", map[string]interface{}{"error": err})
 				}
 			}()
@@ -86,50 +119,434 @@ func newRuntime(cfg *config.Config, opts *options) (*Runtime, error) {
 	}
 
 	// 5. Initialize tool registry.
-	reg := globalRegistry 
+	reg := globalRegistry
 
 	// 6. Register built‑in tools.
 	reg.Register("balance", builtin.Balance)
 	reg.Register("transfer", builtin.Transfer)
 	reg.Register("deploy", builtin.Deploy)
+	reg.Register("cancel", builtin.Cancel)
+	reg.Register("sign", builtin.Sign)
+	reg.Register("send", builtin.Send)
+	reg.Register("send_raw", builtin.SendRaw)
+	reg.Register("sign_message", builtin.SignMessage)
+	reg.Register("safe_propose", builtin.SafePropose)
+	reg.Register("aa_send", builtin.AASend)
 
 	// 7. Initialize security enforcer and add policies.
+	// Policies apply on the chains they are scoped to, or on every chain.
 	enforcer := security.NewEnforcer()
+	enforcer.SetAudit(audit)
+	enforcer.SetLogger(logger)
+	enforcer.SetMetrics(metrics)
+	enforcer.SetShortCircuit(cfg.Security.ShortCircuit)
+	// The kill switch pauses writes while its file exists, or when told
+	// to over HTTP; the loader made sure the metrics server is served.
+	if ks := cfg.Security.KillSwitch; ks != nil {
+		enforcer.SetKillSwitchFile(ks.File)
+		if ks.HTTP && mux != nil {
+			tokenEnv := ks.TokenEnv
+			if tokenEnv == "" {
+				tokenEnv = "LOLA_KILL_SWITCH_TOKEN"
+			}
+			token := os.Getenv(tokenEnv)
+			if token == "" {
+				return nil, fmt.Errorf("kill_switch: http: no bearer token in $%s", tokenEnv)
+			}
+			handler := security.NewPauseHandler(enforcer, token)
+			mux.Handle("/kill-switch", handler)
+			mux.Handle("/kill-switch/", handler)
+		}
+	}
+	scope := cfg.Security.Scope
+
+	// addPolicy adds the policy named name on chains, with the options
+	// policyOptions returns. It is advisory if its block says so,
+	// security.advisory names it or policies run dry; a dry run does not
+	// lift read_only or human_in_the_loop. Policies run in
+	// security.policy_order, then in the default order.
+	dryRun := cfg.Security.DryRun || opts.policyDryRun
+	policyOptions := func(name string, chains []string, advisory bool) security.PolicyOptions {
+		priority := slices.Index(cfg.Security.PolicyOrder, name)
+		if priority < 0 {
+			priority = len(cfg.Security.PolicyOrder) + slices.Index(config.Policies, name)
+		}
+		return security.PolicyOptions{
+			Chains:   chains,
+			Advisory: advisory || slices.Contains(cfg.Security.Advisory, name) || (dryRun && name != "read_only" && name != "human_in_the_loop"),
+			Terminal: slices.Contains(cfg.Security.Terminal, name),
+			Priority: priority,
+		}
+	}
+	addPolicy := func(policy security.Policy, name string, chains []string, advisory bool) {
+		enforcer.AddPolicyWithOptions(policy, policyOptions(name, chains, advisory))
+	}
+
+	// Tool allowlist and denylist. Entries that match no tool registered
+	// by now are most likely typos.
+	if len(cfg.Security.AllowedTools) > 0 || len(cfg.Security.BlockedTools) > 0 {
+		toolPolicy, err := policies.NewToolPolicy(cfg.Security.AllowedTools, cfg.Security.BlockedTools)
+		if err != nil {
+			return nil, fmt.Errorf("security: %w", err)
+		}
+		toolPolicy.SetMetrics(metrics)
+		for _, pattern := range toolPolicy.Unknown(reg.List()) {
+			logger.Warn("tool policy names no registered tool", map[string]interface{}{"pattern": pattern})
+		}
+		addPolicy(toolPolicy, "tools", scope["tools"], false)
+	}
 
-	// Read‑only policy.
-	if cfg.Security.ReadOnly || opts.readOnly {
-		enforcer.AddPolicy(policies.NewReadOnlyPolicy())
+	// Read‑only policy; the WithReadOnly option applies everywhere, always
+	// blocks and runs first.
+	if opts.readOnly {
+		enforcer.AddPolicyWithOptions(policies.NewReadOnlyPolicy(), security.PolicyOptions{Terminal: true, Priority: -1})
+	} else if cfg.Security.ReadOnly {
+		addPolicy(policies.NewReadOnlyPolicy(), "read_only", scope["read_only"], false)
+	}
+
+	// Prices for limits in usd. Chainlink feeds are added once their
+	// chains are connected.
+	var pricer *policies.USDPricer
+	var chainlink *policies.ChainlinkOracle
+	if po := cfg.Security.PriceOracle; po != nil {
+		static, err := policies.NewStaticOracle(po.StaticPrices)
+		if err != nil {
+			return nil, fmt.Errorf("price oracle: %w", err)
+		}
+		symbols := make(map[string]string, len(cfg.Chains))
+		for name, chainCfg := range cfg.Chains {
+			symbols[strings.ToLower(name)] = chainCfg.NativeCurrency
+		}
+		pricer = &policies.USDPricer{Oracle: static, Symbols: symbols, Audit: audit}
+		if len(po.Chainlink) > 0 {
+			chainlink = policies.NewChainlinkOracle(po.MaxAge)
+			pricer.Oracle = chainlink
+			if po.OnFailure == "static" {
+				pricer.Fallback = static
+			}
+		}
 	}
 
 	// Transaction limits.
 	if cfg.Security.MaxTransactionValue != nil {
-		enforcer.AddPolicy(policies.NewLimitPolicy(cfg.Security.MaxTransactionValue, nil))
+		limit := policies.NewLimitPolicy(cfg.Security.MaxTransactionValue, nil)
+		limit.SetPricer(pricer)
+		addPolicy(limit, "limits", scope["limits"], false)
 	}
 	if cfg.Security.DailyLimit != nil {
-		enforcer.AddPolicy(policies.NewLimitPolicy(nil, cfg.Security.DailyLimit))
+		statePath := cfg.Security.DailyLimitState
+		if statePath == "" {
+			statePath = filepath.Join(filepath.Dir(cfg.Observability.Audit.Path), "lola.limits.json")
+		}
+		limit, err := policies.NewLimitPolicyWithState(nil, cfg.Security.DailyLimit, policies.LimitState{
+			Path:       statePath,
+			FailClosed: cfg.Security.DailyLimitStateMode == "closed",
+			Logger:     logger,
+		})
+		if err != nil {
+			return nil, err
+		}
+		limit.SetPricer(pricer)
+		addPolicy(limit, "limits", scope["limits"], false)
+	}
+
+	// Cumulative amounts per recipient, kept across restarts like the
+	// daily limit.
+	if rl := cfg.Security.RecipientLimits; rl != nil {
+		statePath := rl.State
+		if statePath == "" {
+			statePath = filepath.Join(filepath.Dir(cfg.Observability.Audit.Path), "lola.recipients.json")
+		}
+		recipients, err := policies.NewRecipientLimitPolicyWithState(rl, policies.LimitState{
+			Path:       statePath,
+			FailClosed: cfg.Security.DailyLimitStateMode == "closed",
+			Logger:     logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("security: %w", err)
+		}
+		addPolicy(recipients, "recipient_limits", rl.Chains, rl.Advisory)
+	}
+
+	// Per‑session budgets, set by WithRunBudget; sessions without one are
+	// not limited.
+	addPolicy(policies.NewSessionBudgetPolicy(), "session_budget", nil, false)
+
+	// Transaction rate.
+	var rate *policies.RatePolicy
+	if rl := cfg.Security.RateLimit; rl != nil {
+		statePath := rl.State
+		if statePath == "" {
+			statePath = filepath.Join(filepath.Dir(cfg.Observability.Audit.Path), "lola.rate.json")
+		}
+		rate, err = policies.NewRatePolicyWithState(rl.MaxTx, rl.Window, policies.LimitState{
+			Path:       statePath,
+			FailClosed: cfg.Security.DailyLimitStateMode == "closed",
+			Logger:     logger,
+		})
+		if err != nil {
+			return nil, err
+		}
+		addPolicy(rate, "rate_limit", rl.Chains, rl.Advisory)
+	}
+
+	// Gas spending.
+	if cfg.Security.MaxGasPrice != nil || cfg.Security.MaxGasPerTx != 0 || cfg.Security.DailyGasBudget != nil {
+		gas := policies.NewGasPolicy(cfg.Security.MaxGasPrice, cfg.Security.MaxGasPerTx, cfg.Security.DailyGasBudget)
+		// The daily fees are kept across restarts like the daily limit.
+		if cfg.Security.DailyGasBudget != nil {
+			statePath := cfg.Security.DailyGasBudgetState
+			if statePath == "" {
+				statePath = filepath.Join(filepath.Dir(cfg.Observability.Audit.Path), "lola.gas.json")
+			}
+			gas, err = policies.NewGasPolicyWithState(cfg.Security.MaxGasPrice, cfg.Security.MaxGasPerTx, cfg.Security.DailyGasBudget, policies.LimitState{
+				Path:       statePath,
+				FailClosed: cfg.Security.DailyLimitStateMode == "closed",
+				Logger:     logger,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		addPolicy(gas, "gas", scope["gas"], false)
 	}
 
 	// Whitelist/blacklist.
 	if len(cfg.Security.AllowedAddresses) > 0 || len(cfg.Security.BlockedAddresses) > 0 {
-		enforcer.AddPolicy(policies.NewWhitelistPolicy(
-			cfg.Security.AllowedAddresses,
-			cfg.Security.BlockedAddresses,
-		))
+		whitelist, err := policies.NewWhitelistPolicy(cfg.Security.AllowedAddresses, cfg.Security.BlockedAddresses)
+		if err != nil {
+			return nil, fmt.Errorf("security: %w", err)
+		}
+		addPolicy(whitelist, "whitelist", scope["whitelist"], false)
+	}
+
+	// Denylist feeds, loaded once now and reloaded in the background once
+	// the runtime is built. A feed that fails to load is logged and
+	// retried; on_stale decides when that blocks.
+	var denylist *policies.DenylistFeedPolicy
+	if dl := cfg.Security.Denylist; dl != nil {
+		denylist, err = policies.NewDenylistFeedPolicy(dl)
+		if err != nil {
+			return nil, fmt.Errorf("security: %w", err)
+		}
+		denylist.SetLogger(logger)
+		_ = denylist.Refresh(context.Background())
+		addPolicy(denylist, "denylist", dl.Chains, dl.Advisory)
+	}
+
+	// Contract method allowlist.
+	if cal := cfg.Security.ContractAllowlist; cal != nil {
+		contracts, err := policies.NewContractPolicy(cal.Contracts, cal.AllowTransfers, cal.AllowDeploy)
+		if err != nil {
+			return nil, err
+		}
+		addPolicy(contracts, "contract_allowlist", cal.Chains, cal.Advisory)
 	}
 
-	// HITL.
+	// Deployment bytecode allowlist.
+	if d := cfg.Security.Deploy; d != nil {
+		deploy, err := policies.NewDeployPolicy(d)
+		if err != nil {
+			return nil, fmt.Errorf("security: %w", err)
+		}
+		addPolicy(deploy, "deploy", d.Chains, d.Advisory)
+	}
+
+	// HITL, which also approves transfers of tokens without limits.
+	var telegram *policies.TelegramBot
+	if h := cfg.Security.HITL; h != nil && h.Mode == "telegram" {
+		tokenEnv := h.Telegram.BotTokenEnv
+		if tokenEnv == "" {
+			tokenEnv = "TELEGRAM_BOT_TOKEN"
+		}
+		telegram, err = policies.NewTelegramBot(policies.TelegramBotConfig{
+			Token:        os.Getenv(tokenEnv),
+			ChatID:       h.Telegram.ChatID,
+			AllowedUsers: h.Telegram.AllowedUsers,
+			APIURL:       h.Telegram.APIURL,
+			Audit:        audit,
+			Logger:       logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("human_in_the_loop: %w", err)
+		}
+	}
+	var approvals *policies.ApprovalQueue
+	var approvalToken string
+	var approverTokens map[string]string // approver -> token
+	if h := cfg.Security.HITL; h != nil && h.Mode == "api" {
+		var api config.ApprovalAPIConfig
+		if h.API != nil {
+			api = *h.API
+		}
+		tokenEnv := api.TokenEnv
+		if tokenEnv == "" {
+			tokenEnv = "LOLA_APPROVAL_TOKEN"
+		}
+		if approvalToken = os.Getenv(tokenEnv); approvalToken == "" {
+			return nil, fmt.Errorf("human_in_the_loop: api: no bearer token in $%s", tokenEnv)
+		}
+		// Tier approvers authenticate with tokens of their own, named by
+		// the variables holding them.
+		for _, tier := range h.Tiers {
+			for _, env := range tier.Approvers {
+				if approverTokens == nil {
+					approverTokens = make(map[string]string)
+				}
+				if approverTokens[env] = os.Getenv(env); approverTokens[env] == "" {
+					return nil, fmt.Errorf("human_in_the_loop: api: no bearer token in $%s for a tier approver", env)
+				}
+			}
+		}
+		if api.State == "" {
+			approvals = policies.NewApprovalQueue(audit)
+		} else {
+			approvals, err = policies.NewApprovalQueueWithState(audit, policies.LimitState{Path: api.State, Logger: logger})
+			if err != nil {
+				return nil, fmt.Errorf("human_in_the_loop: %w", err)
+			}
+		}
+	}
+	var hitl *policies.HITLPolicy
 	if cfg.Security.HITL != nil && cfg.Security.HITL.Enabled {
-		enforcer.AddPolicy(policies.NewHITLPolicy(
+		hitl = policies.NewHITLPolicy(
 			cfg.Security.HITL.Threshold,
 			cfg.Security.HITL.Timeout,
 			cfg.Security.HITL.Mode,
-		))
+		)
+		hitl.SetPricer(pricer)
+		hitl.SetAudit(audit)
+		hitl.SetLogger(logger)
+		if err := hitl.SetRules(cfg.Security.HITL.Rules, cfg.Security.HITL.AlwaysRequire); err != nil {
+			return nil, err
+		}
+		if err := hitl.SetTiers(cfg.Security.HITL.Tiers); err != nil {
+			return nil, err
+		}
+		hitl.SetTelegram(telegram)
+		hitl.SetQueue(approvals)
+	}
+
+	// approver returns what asks a human for the token policies if mode is
+	// "approve": human_in_the_loop, or one with its settings if disabled.
+	approver := func(mode string) policies.Approver {
+		if mode != "approve" {
+			return nil
+		}
+		if hitl != nil {
+			return hitl
+		}
+		var timeout time.Duration
+		var hitlMode string
+		if cfg.Security.HITL != nil {
+			timeout, hitlMode = cfg.Security.HITL.Timeout, cfg.Security.HITL.Mode
+		}
+		ask := policies.NewHITLPolicy(nil, timeout, hitlMode)
+		ask.SetAudit(audit)
+		ask.SetTelegram(telegram)
+		ask.SetQueue(approvals)
+		return ask
+	}
+
+	// Token limits.
+	if len(cfg.Security.TokenLimits) > 0 || cfg.Security.UnknownTokens != "" {
+		tokens, err := policies.NewTokenLimitPolicy(cfg.Security.TokenLimits, cfg.Security.UnknownTokens, approver(cfg.Security.UnknownTokens))
+		if err != nil {
+			return nil, err
+		}
+		addPolicy(tokens, "token_limits", scope["token_limits"], false)
+	}
+
+	// Caps on ERC‑20 approvals.
+	if ta := cfg.Security.TokenApprovals; ta != nil {
+		approvalPolicy, err := policies.NewTokenApprovalPolicy(ta, approver(ta.OnUnlimited))
+		if err != nil {
+			return nil, err
+		}
+		addPolicy(approvalPolicy, "token_approvals", ta.Chains, ta.Advisory)
+	}
+
+	// New transactions held back behind stuck ones.
+	if pt := cfg.Security.PendingTx; pt != nil {
+		pending, err := policies.NewPendingTxPolicy(pt, approver(pt.OnExceed))
+		if err != nil {
+			return nil, err
+		}
+		addPolicy(pending, "pending_tx", pt.Chains, pt.Advisory)
+	}
+
+	// Simulation of transactions before they are approved.
+	if s := cfg.Security.Simulation; s != nil {
+		sim, err := policies.NewSimulationPolicy(s)
+		if err != nil {
+			return nil, fmt.Errorf("security: %w", err)
+		}
+		sim.SetAudit(audit)
+		addPolicy(sim, "simulation", s.Chains, s.Advisory)
+	}
+	if hitl != nil {
+		addPolicy(hitl, "human_in_the_loop", cfg.Security.HITL.Chains, false)
+	}
+
+	// Policies added with WithPolicy or AddPolicy, named "custom".
+	custom := policyOptions("custom", nil, false)
+	addCustom := func(p security.CustomPolicy) {
+		enforcer.AddPolicyWithOptions(security.AdaptCustomPolicy(p), custom)
+	}
+	for _, p := range opts.policies {
+		addCustom(p)
 	}
 
 	// 8. Initialize engine.
 	engine := core.NewEngine(reg, enforcer, logger)
 
-	// 9. Initialize blockchain connections.
+	// 9. Initialize blockchain connections. A Vault or remote signer wallet
+	// is shared by all chains, so that one goroutine renews the Vault token
+	// and the signer is health‑checked once. So is an ephemeral wallet.
+	var shared blockchain.Wallet
+	var vault *evm.VaultWallet
+	var ephemeral *evm.MemoryWallet
+	if opts.ephemeral {
+		if cfg.Security.ReadOnly || opts.readOnly {
+			return nil, fmt.Errorf("ephemeral wallet: not allowed in read‑only mode")
+		}
+		var err error
+		if opts.ephemeralKey != "" {
+			ephemeral, err = evm.NewMemoryWalletFromHex(opts.ephemeralKey)
+		} else {
+			ephemeral, err = evm.NewMemoryWallet()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ephemeral wallet: %w", err)
+		}
+		shared = ephemeral
+	} else if cfg.Wallet != nil && cfg.Wallet.Provider == "vault" && !cfg.Security.ReadOnly && !opts.readOnly {
+		addr, tokenEnv := cfg.Wallet.Address, cfg.Wallet.TokenEnv
+		if addr == "" {
+			addr = os.Getenv("VAULT_ADDR")
+		}
+		if tokenEnv == "" {
+			tokenEnv = "VAULT_TOKEN"
+		}
+		w, err := evm.NewVaultWallet(context.Background(), addr, cfg.Wallet.Path, os.Getenv(tokenEnv),
+			evm.VaultMode(cfg.Wallet.Mode), evm.WithVaultTimeout(cfg.Wallet.Timeout))
+		if err != nil {
+			logger.Warn("failed to load Vault wallet, operating in read‑only",
+				map[string]interface{}{"error": err, "path": cfg.Wallet.Path})
+		} else {
+			vault, shared = w, w
+		}
+	} else if cfg.Wallet != nil && cfg.Wallet.Provider == "remote" && !cfg.Security.ReadOnly && !opts.readOnly {
+		w, err := evm.NewRemoteSignerWallet(context.Background(), cfg.Wallet.SignerURL, cfg.Wallet.Account,
+			evm.WithRemoteSignerTLS(cfg.Wallet.TLSCert, cfg.Wallet.TLSKey, cfg.Wallet.TLSCA),
+			evm.WithRemoteSignerTimeout(cfg.Wallet.Timeout))
+		if err != nil {
+			logger.Warn("failed to reach remote signer, operating in read‑only",
+				map[string]interface{}{"error": err, "url": cfg.Wallet.SignerURL})
+		} else {
+			shared = w
+		}
+	}
 	chains := make(map[string]blockchain.Chain)
 	for name, chainCfg := range cfg.Chains {
 		if chainCfg.RPC == "" {
@@ -137,16 +554,61 @@ func newRuntime(cfg *config.Config, opts *options) (*Runtime, error) {
 		}
 		// Create wallet if keystore configured.
 		var wallet blockchain.Wallet
-		if cfg.Wallet != nil && cfg.Wallet.KeystorePath != "" && !cfg.Security.ReadOnly && !opts.readOnly {
+		if shared != nil {
+			wallet = shared
+		} else if cfg.Wallet != nil && cfg.Wallet.Provider == "kms" && !cfg.Security.ReadOnly && !opts.readOnly {
+			w, err := evm.NewKMSWalletFromConfig(context.Background(), cfg.Wallet.KeyID, cfg.Wallet.Region,
+				evm.WithKMSTimeout(cfg.Wallet.Timeout))
+			if err != nil {
+				logger.Warn("failed to load KMS wallet, operating in read‑only",
+					map[string]interface{}{"error": err, "key_id": cfg.Wallet.KeyID})
+			} else {
+				wallet = w
+			}
+		} else if cfg.Wallet != nil && cfg.Wallet.Provider == "gcpkms" && !cfg.Security.ReadOnly && !opts.readOnly {
+			w, err := evm.NewGCPKMSWallet(context.Background(), cfg.Wallet.KeyResourceName,
+				evm.WithGCPKMSTimeout(cfg.Wallet.Timeout))
+			if err != nil {
+				logger.Warn("failed to load Cloud KMS wallet, operating in read‑only",
+					map[string]interface{}{"error": err, "key_resource_name": cfg.Wallet.KeyResourceName})
+			} else {
+				wallet = w
+			}
+		} else if cfg.Wallet != nil && cfg.Wallet.MnemonicEnv != "" && !cfg.Security.ReadOnly && !opts.readOnly {
+			w, err := evm.NewHDWallet(os.Getenv(cfg.Wallet.MnemonicEnv), os.Getenv(cfg.Wallet.MnemonicPassphraseEnv),
+				cfg.Wallet.DerivationPath, cfg.Wallet.AccountIndex)
+			if err != nil {
+				logger.Warn("failed to derive HD wallet, operating in read‑only",
+					map[string]interface{}{"error": err, "env": cfg.Wallet.MnemonicEnv})
+			} else {
+				wallet = w
+			}
+		} else if cfg.Wallet != nil && (cfg.Wallet.KeystorePath != "" || cfg.Wallet.KeystoreDir != "") && !cfg.Security.ReadOnly && !opts.readOnly {
 			passphrase := cfg.Wallet.PassphraseEnv
 			if passphrase == "" {
 				passphrase = opts.keystorePass
 			}
 			if passphrase != "" {
-				w, err := evm.NewKeystore(cfg.Wallet.KeystorePath, passphrase)
+				var ksOpts []evm.KeystoreOption
+				if cfg.Wallet.KeystoreFormat != "" {
+					ksOpts = append(ksOpts, evm.WithKeystoreFormat(evm.KeystoreFormat(cfg.Wallet.KeystoreFormat)))
+				}
+				if cfg.Wallet.AutoLock > 0 {
+					ksOpts = append(ksOpts, evm.WithAutoLock(cfg.Wallet.AutoLock))
+				}
+				ksOpts = append(ksOpts, evm.WithKeystoreOptions(evm.KeystoreOptions{
+					ScryptN: cfg.Wallet.ScryptN,
+					ScryptP: cfg.Wallet.ScryptP,
+					Logger:  logger,
+				}))
+				path, open := cfg.Wallet.KeystorePath, evm.NewKeystore
+				if cfg.Wallet.KeystoreDir != "" {
+					path, open = cfg.Wallet.KeystoreDir, evm.NewKeystoreDir
+				}
+				w, err := open(path, passphrase, ksOpts...)
 				if err != nil {
 					logger.Warn("failed to load keystore, operating in read‑only",
-						map[string]interface{}{"error": err, "path": cfg.Wallet.KeystorePath})
+						map[string]interface{}{"error": err, "path": path})
 				} else {
 					wallet = w
 				}
@@ -167,38 +629,154 @@ func newRuntime(cfg *config.Config, opts *options) (*Runtime, error) {
 			retryCfg.InitialBackoff = opts.rpcBackoff
 		}
 
-		gw, err := evm.NewEVMGateway(context.Background(), chainCfg.RPC, logger, retryCfg, wallet)
+		clientOpts := []evm.ClientOption{
+			evm.WithChainName(name),
+			evm.WithMetrics(metrics),
+			evm.WithBlockTime(chainCfg.BlockTime),
+		}
+		if cfg.Security.SimulateTransactions || opts.simulate {
+			clientOpts = append(clientOpts, evm.WithSimulation())
+		}
+		if chainCfg.GasLimitMultiplier != 0 {
+			clientOpts = append(clientOpts, evm.WithGasLimitMultiplier(chainCfg.GasLimitMultiplier))
+		}
+		if chainCfg.MaxGasLimit != 0 {
+			clientOpts = append(clientOpts, evm.WithMaxGasLimit(chainCfg.MaxGasLimit))
+		}
+		if chainCfg.ENSRegistry != "" {
+			clientOpts = append(clientOpts, evm.WithENSRegistry(common.HexToAddress(chainCfg.ENSRegistry)))
+		}
+		if chainCfg.Circuit != nil {
+			clientOpts = append(clientOpts, evm.WithCircuitBreaker(*chainCfg.Circuit))
+		}
+		if chainCfg.RequestsPerSecond > 0 {
+			clientOpts = append(clientOpts, evm.WithRateLimit(chainCfg.RequestsPerSecond, chainCfg.Burst))
+		}
+		if chainCfg.ChainID != nil {
+			clientOpts = append(clientOpts, evm.WithExpectedChainID(*chainCfg.ChainID))
+		}
+		if chainCfg.SkipChainIDCheck {
+			clientOpts = append(clientOpts, evm.WithSkipChainIDCheck())
+		}
+		if chainCfg.L2 != evm.L2None {
+			clientOpts = append(clientOpts, evm.WithL2Stack(chainCfg.L2))
+		}
+		if chainCfg.LazyConnect || opts.lazyConnect {
+			clientOpts = append(clientOpts, evm.WithLazyConnect())
+		}
+		gw, err := evm.NewEVMGateway(context.Background(), chainCfg.RPC, logger, retryCfg, chainCfg.Timeout, wallet, clientOpts...)
 		if err != nil {
 			logger.Error("failed to connect to chain",
 				map[string]interface{}{"chain": name, "rpc": chainCfg.RPC, "error": err})
 			continue
 		}
+		if opts.abiResolver != nil {
+			gw.SetABIResolver(opts.abiResolver)
+		} else {
+			var abiCfg evm.ABIConfig
+			if cfg.ABI != nil {
+				abiCfg = *cfg.ABI
+			}
+			resolver, err := evm.NewABIResolver(chainCfg.Explorer, abiCfg)
+			if err != nil {
+				logger.Warn("contracts cannot be bound by address",
+					map[string]interface{}{"chain": name, "error": err})
+			} else {
+				gw.SetABIResolver(resolver)
+			}
+		}
+		gw.SetSafeTxService(chainCfg.SafeTxService)
+		gw.SetBundler(chainCfg.Bundler)
+		if chainCfg.TxTracker != nil {
+			tracker, err := evm.NewTxTracker(gw, *chainCfg.TxTracker)
+			if err != nil {
+				logger.Error("failed to start transaction tracker",
+					map[string]interface{}{"chain": name, "error": err})
+				gw.Close()
+				continue
+			}
+			gw.SetTracker(tracker)
+			tracker.Start()
+		}
+		if chainCfg.Health != nil {
+			if err := gw.StartHealthCheck(*chainCfg.Health); err != nil {
+				logger.Error("failed to start health check",
+					map[string]interface{}{"chain": name, "error": err})
+				gw.Close()
+				continue
+			}
+		}
+		if chainlink != nil {
+			if feed := cfg.Security.PriceOracle.Chainlink[name]; feed != "" {
+				chainlink.AddFeed(chainCfg.NativeCurrency, gw, feed)
+			}
+		}
 		chains[name] = gw
 	}
 
 	rt := &Runtime{
-		engine:  engine,
-		config:  cfg,
-		logger:  logger,
-		metrics: metrics,
-		tracer:  tracer,
-		audit:   audit,
-		chains:  chains,
+		engine:    engine,
+		config:    cfg,
+		logger:    logger,
+		metrics:   metrics,
+		tracer:    tracer,
+		audit:     audit,
+		chains:    chains,
+		server:    server,
+		vault:     vault,
+		ephemeral: ephemeral,
+		rate:      rate,
+		approvals: approvals,
+		denylist:  denylist,
+		addCustom: addCustom,
+		pause:     enforcer.Pause,
+		resume:    enforcer.Resume,
+		paused:    enforcer.Paused,
+	}
+	if denylist != nil {
+		denylist.Start()
+	}
+
+	// The approval server starts last, so that a runtime failing to start
+	// never leaves it listening.
+	if approvals != nil {
+		addr := "127.0.0.1:8089"
+		if api := cfg.Security.HITL.API; api != nil && api.Listen != "" {
+			addr = api.Listen
+		}
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			_ = rt.Close()
+			return nil, fmt.Errorf("human_in_the_loop: api: %w", err)
+		}
+		rt.apiServer = &http.Server{
+			Handler:           policies.NewApprovalHandlerWithApprovers(approvals, approvalToken, approverTokens),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := rt.apiServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("approval server failed", map[string]interface{}{"error": err})
+			}
+		}()
+		logger.Info("approval server listening", map[string]interface{}{"addr": ln.Addr().String()})
 	}
 
 	return rt, nil
 }
 
-// Run executes an agent function within a session.
-func (r *Runtime) Run(ctx context.Context, fn func(context.Context, *Runtime) error) error {
-	// Determine default chain ID.
-	defaultChain := r.config.Chains[r.getDefaultChainID()]
-	var chain blockchain.Chain
-	if defaultChain != nil {
-		chain = r.chains[r.getDefaultChainID()]
+// Run executes an agent function within a session, which ends when fn
+// returns. The session has every configured chain: operations run on the
+// default chain unless they name another (see EVM).
+func (r *Runtime) Run(ctx context.Context, fn func(context.Context, *Runtime) error, opts ...RunOption) error {
+	var ro runOptions
+	for _, opt := range opts {
+		opt(&ro)
 	}
 
-	sess := r.engine.CreateSession(r.getDefaultChainID(), chain)
+	sess := r.engine.CreateSession(r.getDefaultChainID(), r.chains)
+	if ro.budget != nil {
+		sess.SetBudget(ro.budget)
+	}
 	ctx = core.ContextWithSession(ctx, sess)
 	defer r.engine.CloseSession(sess.ID)
 
@@ -225,39 +803,234 @@ func (r *Runtime) getDefaultChainID() string {
 	return ""
 }
 
-// Execute runs a tool by name.
+// Execute runs a tool by name. An operation a security policy denies
+// returns an *ErrPolicyDenied, wrapped (see IsPolicyDenied and
+// AsLimitExceeded).
 func (r *Runtime) Execute(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
 	return r.engine.Execute(ctx, name, args)
 }
 
-// Close cleans up resources (audit log, tracer, etc.).
+// AddPolicy adds a security policy of one's own, like WithPolicy, for the
+// operations that start after it returns. It is safe to call while
+// agents run.
+func (r *Runtime) AddPolicy(p sdksecurity.Policy) {
+	r.addCustom(p)
+}
+
+// PauseWrites denies every write operation, with reason, until
+// ResumeWrites: an emergency stop that leaves reads, and so diagnostics,
+// working. A denied write returns an *ErrPaused, wrapped. Pausing and
+// resuming are logged and audited. It is safe to call while agents run.
+func (r *Runtime) PauseWrites(reason string) {
+	r.pause(reason)
+}
+
+// ResumeWrites ends a pause by PauseWrites or the kill‑switch endpoint.
+// Writes stay paused while the kill‑switch file exists.
+func (r *Runtime) ResumeWrites() {
+	r.resume()
+}
+
+// WritesPaused returns the pause in force, with its reason and start, or
+// nil if writes may proceed.
+func (r *Runtime) WritesPaused() *ErrPaused {
+	return r.paused()
+}
+
+// Close cleans up resources: chain connections, the Vault wallet, the
+// denylist reloads, the approval and metrics servers, the audit log and
+// the tracer. Operations waiting for approval are denied. Every resource
+// is closed even if some fail; the failures are returned joined.
 func (r *Runtime) Close() error {
+	var errs []error
+
+	r.mu.Lock()
+	for name, chain := range r.chains {
+		if err := chain.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close chain %s: %w", name, err))
+		}
+	}
+	r.mu.Unlock()
+	if r.vault != nil {
+		// Stops token renewal and wipes the key held in memory.
+		if err := r.vault.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close vault wallet: %w", err))
+		}
+	}
+
+	if r.approvals != nil {
+		r.approvals.Close()
+	}
+	if r.denylist != nil {
+		r.denylist.Close()
+	}
+	if r.apiServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+		err := r.apiServer.Shutdown(ctx)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("close approval server: %w", err))
+		}
+	}
+	if r.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+		err := r.server.Shutdown(ctx)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("close metrics server: %w", err))
+		}
+	}
 	if r.audit != nil {
-		r.audit.Close()
+		if err := r.audit.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close audit log: %w", err))
+		}
 	}
 	if tracer, ok := r.tracer.(*observe.OTelTracer); ok {
-		tracer.Shutdown(context.Background())
+		if err := tracer.Shutdown(context.Background()); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown tracer: %w", err))
+		}
 	}
 	if logger, ok := r.logger.(*observe.ZapLogger); ok {
-		logger.Sync()
+		// Sync fails harmlessly on terminals, so its error is not reported.
+		_ = logger.Sync()
+	}
+	return errors.Join(errs...)
+}
+
+// RateLimitUsage returns how many transactions address has made in the
+// current rate_limit window and how many it may make, e.g. 7 and 10 for
+// "7/10 used". Both are 0 if no rate limit is configured.
+func (r *Runtime) RateLimitUsage(address string) (used, limit int) {
+	if r.rate == nil {
+		return 0, 0
+	}
+	return r.rate.Usage(address)
+}
+
+// ChainStatus returns the RPC health of every connected chain, keyed by
+// chain ID. Chains without health checks configured report "healthy".
+func (r *Runtime) ChainStatus() map[string]types.ChainStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]types.ChainStatus, len(r.chains))
+	for name, chain := range r.chains {
+		gw, ok := chain.(*evm.EVMGateway)
+		if !ok {
+			continue
+		}
+		status := gw.Status()
+		cs := types.ChainStatus{
+			State:               string(status.State),
+			Connected:           gw.Connected(),
+			LastSuccess:         status.LastSuccess,
+			ConsecutiveFailures: status.ConsecutiveFailures,
+			Reconnects:          status.Reconnects,
+		}
+		if status.LastError != nil {
+			cs.LastError = status.LastError.Error()
+		}
+		out[name] = cs
+	}
+	return out
+}
+
+// Ready reports whether every connected chain can serve requests, for use
+// as a readiness check. Degraded chains count as ready; chains that are
+// reconnecting, or lazily connected chains that have not reached their
+// endpoint yet, do not.
+func (r *Runtime) Ready() error {
+	statuses := r.ChainStatus()
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		status := statuses[name]
+		switch {
+		case !status.Connected:
+			errs = append(errs, fmt.Errorf("chain %s: %w", name, evm.ErrNotConnected))
+		case status.State == string(evm.HealthReconnecting):
+			errs = append(errs, fmt.Errorf("chain %s: %s: %s", name, status.State, status.LastError))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UnlockWallet unlocks the wallet of chain, for example a keystore that
+// locked itself after auto_lock, by decrypting its key with passphrase.
+// It fails if the chain has no wallet or the wallet cannot be locked.
+func (r *Runtime) UnlockWallet(ctx context.Context, chain, passphrase string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.RLock()
+	c, ok := r.chains[chain]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unlock wallet: unknown chain %q", chain)
+	}
+	var wallet blockchain.Wallet
+	if wc, ok := c.(interface{ Wallet() blockchain.Wallet }); ok {
+		wallet = wc.Wallet()
+	}
+	if wallet == nil {
+		return fmt.Errorf("unlock wallet: chain %s has no wallet", chain)
+	}
+	lockable, ok := wallet.(blockchain.LockableWallet)
+	if !ok {
+		return fmt.Errorf("unlock wallet: chain %s: %T cannot be locked", chain, wallet)
+	}
+	if err := lockable.Unlock(passphrase); err != nil {
+		return fmt.Errorf("unlock wallet: chain %s: %w", chain, err)
 	}
 	return nil
 }
 
+// EphemeralAddress returns the address of the wallet created by
+// WithEphemeralWallet, for funding it on a devnet, or "" if there is none.
+func (r *Runtime) EphemeralAddress() string {
+	if r.ephemeral == nil {
+		return ""
+	}
+	return r.ephemeral.Address()
+}
+
 // loggerKey is a context key for the logger.
 type loggerKey struct{}
 
-// EVM returns an EVM client for the chain associated with the current session.
-// The context must contain a session (i.e., be from inside Run).
-func (r *Runtime) EVM(ctx context.Context) (*evm.Client, error) {
+// EVM returns an EVM client for the current session's default chain, or
+// for the configured chain named by chain, so that one Run can work on
+// several chains; the client's operations are checked by the policies of
+// its chain. The context must contain a session (i.e., be from inside Run).
+func (r *Runtime) EVM(ctx context.Context, chain ...string) (*evm.Client, error) {
 	sess := core.SessionFromContext(ctx)
 	if sess == nil {
 		return nil, fmt.Errorf("evm client: no session in context (must be called inside Run)")
 	}
-	if sess.Chain == nil {
-		return nil, fmt.Errorf("evm client: no blockchain chain in session")
+	var name string
+	if len(chain) > 0 {
+		name = chain[0]
+	}
+	return evm.NewClientForChain(sess, name, r.engine.Execute)
+}
+
+// RemainingBudget returns what the current session may still spend on its
+// default chain, or on the configured chain named by chain, in wei, and
+// false if it has no budget (see WithRunBudget) or no chain of that name.
+// The context must be from inside Run.
+func (r *Runtime) RemainingBudget(ctx context.Context, chain ...string) (*big.Int, bool) {
+	sess := core.SessionFromContext(ctx)
+	if sess == nil {
+		return nil, false
+	}
+	var name string
+	if len(chain) > 0 {
+		name = chain[0]
 	}
-	return evm.NewClient(sess), nil
+	return sess.RemainingBudget(name)
 }
 
 // Config returns the runtime configuration.
@@ -265,4 +1038,4 @@ func (r *Runtime) Config() *config.Config {
 	return r.config
 }
 
-// EOF: sdk/runtime.go
\ No newline at end of file
+// EOF: sdk/runtime.go
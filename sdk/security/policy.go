@@ -0,0 +1,31 @@
+// Package security provides the API for security policies of one's own,
+// such as organization‑specific rules, which the runtime runs with the
+// configured policies before every onchain write. Register them with
+// sdk.WithPolicy or Runtime.AddPolicy.
+//
+// File: sdk/security/policy.go
+
+package security
+
+import (
+	"github.com/0xSemantic/lola-os/internal/security"
+)
+
+// Evaluation describes the operation a policy decides on: the tool, its
+// arguments, the chain, and the transaction it sends (to, value, data).
+// Write tools fill it in alike, whether run with Runtime.Execute or by
+// the EVM client's SendTransaction, SignTransaction, SendRawTransaction
+// and contract bindings.
+type Evaluation = security.Evaluation
+
+// Policy is a security rule. Check returns nil if the operation is
+// allowed, otherwise an error describing the denial, which the operation
+// returns wrapped in an *sdk.ErrPolicyDenied. A policy with a
+// Name() string method is named by it in decisions and the audit log,
+// else by its type.
+type Policy = security.CustomPolicy
+
+// PolicyFunc adapts a function to a Policy.
+type PolicyFunc = security.CustomPolicyFunc
+
+// EOF: sdk/security/policy.go
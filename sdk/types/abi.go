@@ -0,0 +1,37 @@
+// Package types provides ABI lookup types for SDK users.
+//
+// File: sdk/types/abi.go
+
+package types
+
+import "github.com/0xSemantic/lola-os/internal/blockchain/evm"
+
+// ABIResolver looks up the ABI of a deployed contract by chain ID and
+// checksummed address, returning it as a JSON array. Implement it to
+// serve ABIs from an internal registry (see sdk.WithABIResolver).
+type ABIResolver = evm.ABIResolver
+
+var (
+	// ErrABINotVerified is returned when no ABI source has the contract's
+	// verified source; bind it with an explicit ABI or artifact instead.
+	ErrABINotVerified = evm.ErrABINotVerified
+	// ErrABIUnavailable is returned when an ABI source cannot be reached
+	// or refuses the request; retrying later may succeed.
+	ErrABIUnavailable = evm.ErrABIUnavailable
+)
+
+// ProxyInfo describes a proxy contract: its pattern and the
+// implementation (and, for beacon proxies, the beacon) it delegates to.
+type ProxyInfo = evm.ProxyInfo
+
+// ProxyKind names a proxy pattern.
+type ProxyKind = evm.ProxyKind
+
+// Proxy patterns recognised by DetectProxy.
+const (
+	ProxyEIP1967       = evm.ProxyEIP1967
+	ProxyEIP1967Beacon = evm.ProxyEIP1967Beacon
+	ProxyEIP1167       = evm.ProxyEIP1167
+)
+
+// EOF: sdk/types/abi.go
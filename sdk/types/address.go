@@ -0,0 +1,27 @@
+// Package types provides address validation for SDK users.
+//
+// File: sdk/types/address.go
+
+package types
+
+import "github.com/0xSemantic/lola-os/internal/blockchain/evm"
+
+// Address validation errors returned by NormalizeAddress.
+var (
+	ErrInvalidAddress  = evm.ErrInvalidAddress
+	ErrInvalidChecksum = evm.ErrInvalidChecksum
+)
+
+// ErrNotConnected is returned by calls on a lazily connected chain whose
+// RPC endpoint has not been reached yet (see sdk.WithLazyConnect).
+var ErrNotConnected = evm.ErrNotConnected
+
+// NormalizeAddress validates a hex address and returns its EIP‑55
+// checksummed form. All‑lowercase and all‑uppercase addresses are accepted;
+// a mixed‑case address with a wrong checksum (usually a typo) fails with
+// ErrInvalidChecksum. Use it to validate user input before sending funds.
+func NormalizeAddress(address string) (string, error) {
+	return evm.NormalizeAddress(address)
+}
+
+// EOF: sdk/types/address.go
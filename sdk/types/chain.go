@@ -6,6 +6,7 @@ package types
 
 import (
 	"math/big"
+	"time"
 )
 
 // BlockNumber represents a block identifier.
@@ -27,6 +28,17 @@ type Transaction struct {
 	GasTipCap *big.Int `json:"maxPriorityFeePerGas"`
 	Data      []byte   `json:"data"`
 	Nonce     *uint64  `json:"nonce"`
+	// AccessList pre‑declares touched accounts and slots (EIP‑2930).
+	AccessList []AccessTuple `json:"accessList,omitempty"`
+	// From selects the signing account of a wallet holding several
+	// accounts ("" = its primary account).
+	From string `json:"from,omitempty"`
+}
+
+// AccessTuple names an account and the storage slots a transaction will touch.
+type AccessTuple struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
 }
 
 // ContractCall represents a message call.
@@ -37,4 +49,45 @@ type ContractCall struct {
 	Gas   uint64   `json:"gas"`
 }
 
+// GasInfo is a snapshot of current gas pricing. BaseFee and GasTipCap are
+// nil on chains without EIP‑1559.
+type GasInfo struct {
+	BlockNumber uint64   `json:"blockNumber"`
+	BaseFee     *big.Int `json:"baseFeePerGas"`
+	GasTipCap   *big.Int `json:"maxPriorityFeePerGas"`
+	GasPrice    *big.Int `json:"gasPrice"`
+}
+
+// Receipt summarises a mined transaction.
+type Receipt struct {
+	TxHash            string   `json:"transactionHash"`
+	BlockNumber       uint64   `json:"blockNumber"`
+	BlockHash         string   `json:"blockHash"`
+	Status            uint64   `json:"status"` // 1 = success, 0 = reverted
+	GasUsed           uint64   `json:"gasUsed"`
+	EffectiveGasPrice *big.Int `json:"effectiveGasPrice"`
+	ContractAddress   string   `json:"contractAddress,omitempty"` // set for deployments
+}
+
+// DecodedEvent is a receipt log decoded against an ABI event. Indexed
+// fields of dynamic type hold their keccak256 hash.
+type DecodedEvent struct {
+	Name        string                 `json:"name"`
+	Args        map[string]interface{} `json:"args"`
+	LogIndex    uint                   `json:"logIndex"`
+	Address     string                 `json:"address"`
+	BlockNumber uint64                 `json:"blockNumber"`
+	TxHash      string                 `json:"transactionHash"`
+}
+
+// ChainStatus reports the health of a chain's RPC endpoint.
+type ChainStatus struct {
+	State               string    `json:"state"`     // "healthy", "degraded" or "reconnecting"
+	Connected           bool      `json:"connected"` // false until a lazily connected chain reaches its endpoint
+	LastError           string    `json:"lastError,omitempty"`
+	LastSuccess         time.Time `json:"lastSuccess"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	Reconnects          int       `json:"reconnects"`
+}
+
 // EOF: sdk/types/chain.go
\ No newline at end of file
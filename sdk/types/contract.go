@@ -4,13 +4,57 @@
 
 package types
 
+import (
+	"context"
+	"math/big"
+
+	"github.com/0xSemantic/lola-os/internal/blockchain/evm"
+)
+
+// TransactOpts sets the value and, optionally, gas, fees and nonce of a
+// contract transaction. Zero fields are estimated.
+type TransactOpts = evm.TransactOpts
+
 // Contract is a high‑level binding to a deployed smart contract.
 type Contract interface {
 	// Call executes a read‑only contract method.
 	Call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error)
 
+	// CallInto executes a read‑only contract method and unpacks the return
+	// values into out: a pointer to a struct whose fields match the outputs
+	// by `abi:"name"` tag or camel‑cased name, or to a single value.
+	CallInto(ctx context.Context, out interface{}, method string, args ...interface{}) error
+
 	// Transact creates and sends a transaction that invokes a contract method.
 	Transact(ctx context.Context, method string, args ...interface{}) (string, error)
+
+	// TransactWithOpts is Transact with a value for payable methods and
+	// optional gas, fee and nonce overrides; opts may be nil. Security
+	// policies check the value like a transfer's.
+	TransactWithOpts(ctx context.Context, opts *TransactOpts, method string, args ...interface{}) (string, error)
+
+	// EstimateGas estimates the gas a transaction invoking method would use,
+	// sent from the session's wallet.
+	EstimateGas(ctx context.Context, method string, args ...interface{}) (uint64, error)
+
+	// EstimateCost estimates the fee in wei of a transaction invoking method
+	// at current gas prices (see units.FormatEther to display it).
+	EstimateCost(ctx context.Context, method string, args ...interface{}) (*big.Int, error)
 }
 
+// CallBatch executes read‑only calls on several contracts together,
+// through Multicall3 where it is deployed. Create one with
+// evm.NewCallBatch from the sdk/evm package.
+type CallBatch = evm.CallBatch
+
+// BatchResult is the decoded values, or the error, of one batched call.
+type BatchResult = evm.BatchResult
+
+// DeploymentFailedError describes a deployment that was mined without
+// leaving code at the contract's address, with the revert reason if known.
+type DeploymentFailedError = evm.DeploymentFailedError
+
+// ErrDeploymentFailed matches every *DeploymentFailedError.
+var ErrDeploymentFailed = evm.ErrDeploymentFailed
+
 // EOF: sdk/types/contract.go
\ No newline at end of file
@@ -0,0 +1,17 @@
+// Package types provides call trace types for SDK users.
+//
+// File: sdk/types/trace.go
+
+package types
+
+import "github.com/0xSemantic/lola-os/internal/blockchain/evm"
+
+// CallFrame is one call in a transaction's call trace, with nested calls
+// in Calls. Its String method renders the tree for logs.
+type CallFrame = evm.CallFrame
+
+// ErrNotSupported is returned when the RPC provider does not offer a
+// feature, such as the debug namespace needed for tracing.
+var ErrNotSupported = evm.ErrNotSupported
+
+// EOF: sdk/types/trace.go
@@ -0,0 +1,131 @@
+// Package units converts between wei and decimal amounts such as "1.5"
+// ether or "30" gwei. All conversions are exact: amounts are handled as
+// decimal strings and big integers, never as floating point.
+//
+// File: sdk/types/units/units.go
+
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimals of the common native currency units.
+const (
+	WeiDecimals   = 0
+	GweiDecimals  = 9
+	EtherDecimals = 18
+)
+
+// ErrTooPrecise indicates an amount with more significant decimal places
+// than the unit allows, such as "0.0000000000000000001" ether.
+var ErrTooPrecise = errors.New("too many decimal places")
+
+// ParseUnits converts a decimal amount in a unit with the given number of
+// decimals to its integer base value: ParseUnits("1.5", 18) is 1.5e18.
+// The amount may have a leading sign and a fractional part; exponents and
+// digit separators are not accepted. Fractional digits beyond decimals fail
+// with ErrTooPrecise unless they are zeros.
+func ParseUnits(s string, decimals int) (*big.Int, error) {
+	if decimals < 0 {
+		return nil, fmt.Errorf("parse units: negative decimals %d", decimals)
+	}
+	num := strings.TrimSpace(s)
+	neg := false
+	switch {
+	case strings.HasPrefix(num, "-"):
+		neg, num = true, num[1:]
+	case strings.HasPrefix(num, "+"):
+		num = num[1:]
+	}
+
+	whole, frac, _ := strings.Cut(num, ".")
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return nil, fmt.Errorf("parse units: invalid amount %q", s)
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > decimals {
+		return nil, fmt.Errorf("parse units: %q has more than %d decimal places: %w", s, decimals, ErrTooPrecise)
+	}
+
+	v, ok := new(big.Int).SetString("0"+whole+frac+strings.Repeat("0", decimals-len(frac)), 10)
+	if !ok {
+		return nil, fmt.Errorf("parse units: invalid amount %q", s)
+	}
+	if neg {
+		v.Neg(v)
+	}
+	return v, nil
+}
+
+// isDigits reports whether s consists of ASCII digits only ("" is allowed).
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatUnits renders an integer base value as a decimal amount in a unit
+// with the given number of decimals. precision limits the fractional
+// digits, rounding half away from zero; a negative precision keeps every
+// digit. Trailing fractional zeros are dropped: FormatUnits(1.5e18, 18, -1)
+// is "1.5" and FormatUnits(1e18, 18, 2) is "1".
+func FormatUnits(v *big.Int, decimals, precision int) string {
+	if v == nil {
+		return "0"
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+	abs := new(big.Int).Abs(v)
+	if precision >= 0 && precision < decimals {
+		// Round to precision digits: add half of the dropped unit, truncate.
+		unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-precision)), nil)
+		half := new(big.Int).Rsh(unit, 1)
+		abs.Add(abs, half)
+		abs.Sub(abs, new(big.Int).Mod(abs, unit))
+	}
+
+	digits := abs.String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+
+	out := whole
+	if frac != "" {
+		out += "." + frac
+	}
+	if v.Sign() < 0 && out != "0" {
+		out = "-" + out
+	}
+	return out
+}
+
+// ParseEther converts an ether amount to wei.
+func ParseEther(s string) (*big.Int, error) {
+	return ParseUnits(s, EtherDecimals)
+}
+
+// FormatEther renders wei as an exact ether amount.
+func FormatEther(wei *big.Int) string {
+	return FormatUnits(wei, EtherDecimals, -1)
+}
+
+// ParseGwei converts a gwei amount to wei.
+func ParseGwei(s string) (*big.Int, error) {
+	return ParseUnits(s, GweiDecimals)
+}
+
+// FormatGwei renders wei as an exact gwei amount, as used for gas prices.
+func FormatGwei(wei *big.Int) string {
+	return FormatUnits(wei, GweiDecimals, -1)
+}
+
+// EOF: sdk/types/units/units.go
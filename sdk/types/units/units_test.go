@@ -0,0 +1,134 @@
+// Package units_test tests unit parsing and formatting.
+//
+// File: sdk/types/units/units_test.go
+
+package units_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0xSemantic/lola-os/sdk/types/units"
+)
+
+func bigInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	v, ok := new(big.Int).SetString(s, 10)
+	require.True(t, ok, s)
+	return v
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimals int
+		want     string
+	}{
+		{"1.5", 18, "1500000000000000000"},
+		{"0.000000000000000001", 18, "1"},
+		{"1", 18, "1000000000000000000"},
+		{"1.", 18, "1000000000000000000"},
+		{".5", 18, "500000000000000000"},
+		{"0", 18, "0"},
+		{"-2.25", 18, "-2250000000000000000"},
+		{"+3", 9, "3000000000"},
+		{" 30 ", 9, "30000000000"},
+		{"1.100000000000000000000", 18, "1100000000000000000"},
+		{"123456789012345678901234567890.123456789012345678", 18, "123456789012345678901234567890123456789012345678"},
+		{"5000", 0, "5000"},
+		{"5000.000", 0, "5000"},
+		{"007", 0, "7"},
+	}
+	for _, tt := range tests {
+		got, err := units.ParseUnits(tt.in, tt.decimals)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got.String(), tt.in)
+	}
+}
+
+func TestParseUnits_Errors(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimals int
+		precise  bool
+	}{
+		{"0.0000000000000000001", 18, true},
+		{"1.0000000000000000001", 18, true},
+		{"0.5", 0, true},
+		{"1.0000000001", 9, true},
+		{"", 18, false},
+		{".", 18, false},
+		{"-", 18, false},
+		{"1e18", 18, false},
+		{"1,000", 18, false},
+		{"1.2.3", 18, false},
+		{"--1", 18, false},
+		{"0x10", 18, false},
+		{"NaN", 18, false},
+	}
+	for _, tt := range tests {
+		_, err := units.ParseUnits(tt.in, tt.decimals)
+		require.Error(t, err, tt.in)
+		assert.Equal(t, tt.precise, errors.Is(err, units.ErrTooPrecise), tt.in)
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		wei       string
+		decimals  int
+		precision int
+		want      string
+	}{
+		{"1500000000000000000", 18, -1, "1.5"},
+		{"1", 18, -1, "0.000000000000000001"},
+		{"1000000000000000000", 18, -1, "1"},
+		{"0", 18, -1, "0"},
+		{"-2250000000000000000", 18, -1, "-2.25"},
+		{"123456789012345678901234567890123456789012345678", 18, -1, "123456789012345678901234567890.123456789012345678"},
+		{"1234567890000000000", 18, 4, "1.2346"},
+		{"1234500000000000000", 18, 3, "1.235"},
+		{"-1234500000000000000", 18, 3, "-1.235"},
+		{"999999999999999999", 18, 2, "1"},
+		{"1000000000000000000", 18, 2, "1"},
+		{"1", 18, 4, "0"},
+		{"-1", 18, 4, "0"},
+		{"30000000000", 9, -1, "30"},
+		{"5000", 0, 2, "5000"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, units.FormatUnits(bigInt(t, tt.wei), tt.decimals, tt.precision), tt.wei)
+	}
+	assert.Equal(t, "0", units.FormatUnits(nil, 18, -1))
+}
+
+func TestEtherAndGwei(t *testing.T) {
+	wei, err := units.ParseEther("0.001")
+	require.NoError(t, err)
+	assert.Equal(t, "1000000000000000", wei.String())
+	assert.Equal(t, "0.001", units.FormatEther(wei))
+
+	wei, err = units.ParseGwei("1.5")
+	require.NoError(t, err)
+	assert.Equal(t, "1500000000", wei.String())
+	assert.Equal(t, "1.5", units.FormatGwei(wei))
+
+	_, err = units.ParseGwei("0.0000000001")
+	assert.ErrorIs(t, err, units.ErrTooPrecise)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, s := range []string{"0", "1", "0.000000000000000001", "12345.678901234567890123", "-0.5"} {
+		wei, err := units.ParseEther(s)
+		require.NoError(t, err, s)
+		back, err := units.ParseEther(units.FormatEther(wei))
+		require.NoError(t, err, s)
+		assert.Equal(t, wei, back, s)
+	}
+}
+
+// EOF: sdk/types/units/units_test.go